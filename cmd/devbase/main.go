@@ -1,20 +1,56 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"devbase/db"
+	"devbase/engine"
+	"devbase/logging"
+	"devbase/models"
 	"devbase/ui"
 )
 
 const version = "1.0.0"
 
 func main() {
+	engine.Version = version
+	defer recoverCLICrash()
+
+	// --verbose and --portable apply to every command, so they may appear anywhere. A
+	// devbase.portable file next to the executable works like --portable.
+	verbose := slices.Contains(os.Args[1:], "--verbose")
+	db.SetPortable(slices.Contains(os.Args[1:], "--portable") || db.PortableMarkerExists())
+	os.Args = slices.DeleteFunc(os.Args, func(arg string) bool { return arg == "--verbose" || arg == "--portable" })
+
+	// The daemon also logs to stderr; everything else only to the log file
+	serve := len(os.Args) > 1 && os.Args[1] == "serve"
+	logDir := ""
+	if dataDir, err := db.DataDir(); err == nil {
+		logDir = filepath.Join(dataDir, "logs")
+	}
+	if err := logging.Init(logging.Options{Dir: logDir, Verbose: verbose, Stderr: serve}); err != nil {
+		fmt.Fprintf(os.Stderr, "Logging to file is disabled: %v\n", err)
+	}
+
 	// Check for command line arguments
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
@@ -25,51 +61,212 @@ func main() {
 			printHelp()
 			return
 		case "scan":
-			handleScan()
+			handleScan(os.Args[2:])
+			return
+		case "--inline", "-i":
+			handleInline(ui.InlineOptions{})
+			return
+		case "pick":
+			handlePick(os.Args[2:])
+			return
+		case "import":
+			handleImport(os.Args[2:])
+			return
+		case "doctor":
+			handleDoctor()
+			return
+		case "remote":
+			handleRemote(os.Args[2:])
+			return
+		case "open":
+			handleOpen(os.Args[2:])
+			return
+		case "lock", "unlock":
+			handleLock(os.Args[1], os.Args[2:])
+			return
+		case "export":
+			handleExport(os.Args[2:])
+			return
+		case "wt":
+			handleWT(os.Args[2:])
+			return
+		case "serve":
+			handleServe(os.Args[2:])
+			return
+		case "webhook":
+			handleWebhook(os.Args[2:])
+			return
+		case "script":
+			handleScript(os.Args[2:])
+			return
+		case "trash":
+			handleTrash(os.Args[2:])
+			return
+		case "telemetry":
+			handleTelemetry(os.Args[2:])
+			return
+		case "init":
+			handleInit(os.Args[2:])
+			return
+		case "list":
+			handleList(os.Args[2:])
+			return
+		case "add":
+			handleAdd(os.Args[2:])
+			return
+		case "stale":
+			handleStale(os.Args[2:])
+			return
+		case "reclaim":
+			handleReclaim(os.Args[2:])
+			return
+		case "pathmap":
+			handlePathMap(os.Args[2:])
+			return
+		case "ignore":
+			handleIgnore(os.Args[2:])
+			return
+		case "worktree":
+			handleWorktree(os.Args[2:])
+			return
+		case "settings":
+			handleSettings(os.Args[2:])
 			return
 		}
 	}
 
-	// Initialize the database with proper path
-	homeDir, err := os.UserHomeDir()
+	// The TUI shows up right away and opens the database in the background. Notices
+	// can't be printed over the TUI, so they only reach the log.
+	ctx, cancel := context.WithCancel(context.Background())
+	m := ui.NewLoadingModel(ctx, func() error { return initDB(false) })
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	final, err := p.Run()
+
+	// Bubble Tea restored the terminal after the panic; the config goes into the bundle
+	// before the database is closed
+	crashed := errors.Is(err, tea.ErrProgramPanic)
+	if crashed {
+		reportCrash("panic in a background task", nil)
+	}
+
+	// Bubble Tea also quits on SIGINT and SIGTERM. Scans that are still running stop
+	// before the database is checkpointed and closed, and runs streamed into the output
+	// pane end with the TUI.
+	cancel()
+	engine.StopStreamedRuns()
+	closeDB()
+	if crashed {
+		os.Exit(1)
+	}
+	if errors.Is(err, tea.ErrInterrupted) {
+		os.Exit(130)
+	}
 	if err != nil {
-		log.Fatalf("Failed to get user home directory: %v", err)
+		fatal("Error running program: %v", err)
+	}
+	if err := ui.StartupError(final); err != nil {
+		fatal("%v", err)
 	}
-	dbPath := filepath.Join(homeDir, "devbase.db")
+}
 
-	if err := db.InitDB(dbPath); err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+// recoverCLICrash is deferred in main. A panic in a command writes a diagnostic bundle
+// instead of only a stack trace, and the database is still closed.
+func recoverCLICrash() {
+	if r := recover(); r != nil {
+		stack := debug.Stack()
+		fmt.Fprintf(os.Stderr, "panic: %v\n\n%s", r, stack)
+		reportCrash(r, stack)
+		closeDB()
+		os.Exit(2)
 	}
-	defer db.CloseDB()
+}
 
-	// Check if database is empty
-	projects, err := db.GetProjects()
-	if err != nil {
-		log.Fatalf("Failed to check projects: %v", err)
+// reportCrash writes a diagnostic bundle, unless the TUI already wrote one with the stack
+// trace, and tells the user how to report the crash
+func reportCrash(value any, stack []byte) {
+	path := engine.CrashBundle()
+	if path == "" {
+		path = engine.RecordCrash(value, stack)
+	}
+	fmt.Fprintln(os.Stderr, "\nDevBase crashed, sorry about that.")
+	if path != "" {
+		fmt.Fprintf(os.Stderr, "A diagnostic bundle with the stack trace, recent logs and config (tokens, IDs and URLs left out) was saved to:\n  %s\n", path)
 	}
+	fmt.Fprintf(os.Stderr, "Please report the crash at %s and attach the bundle after checking it for anything private.\n", engine.IssueURL)
+}
 
-	if len(projects) == 0 {
-		fmt.Println("╔═══════════════════════════════════════════════════════════╗")
-		fmt.Println("║              Welcome to DevBase v" + version + "                   ║")
-		fmt.Println("╚═══════════════════════════════════════════════════════════╝")
-		fmt.Println("\nNo projects found in database.")
-		fmt.Println("\nOptions:")
-		fmt.Println("  1. Run 'DevBase scan' to scan your directories for projects")
-		fmt.Println("  2. Add projects manually using the UI (press 'a' in the app)")
-		fmt.Println("\nStarting empty UI...")
-		fmt.Println()
+// openDB initializes the database at the location chosen in setup (devbase.db in the data
+// directory by default), moving a database left in the home directory there first. CLI
+// commands are short, so SIGINT and SIGTERM close the database and exit right away.
+func openDB() error {
+	if err := initDB(true); err != nil {
+		return err
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		slog.Info("Interrupted", "signal", sig.String())
+		closeDB()
+		os.Exit(130)
+	}()
+	return nil
+}
+
+// closeDB checkpoints and closes the database before DevBase exits
+func closeDB() {
+	if err := db.CloseDB(); err != nil {
+		slog.Warn("Failed to close database", "err", err)
+	}
+}
+
+// initDB opens the database for openDB, printing notices to stderr when asked to
+func initDB(notify bool) error {
+	migrated, err := db.MigrateLegacyDB()
+	if err != nil {
+		return fmt.Errorf("failed to move database to the data directory: %w", err)
+	}
+	if migrated != "" {
+		slog.Info("Moved database to the data directory", "path", migrated)
+		if notify {
+			fmt.Fprintf(os.Stderr, "Moved ~/%s to %s\n", db.DefaultDBFileName, migrated)
+		}
 	}
 
-	// Create and run the Bubble Tea UI
-	m, err := ui.NewModel()
+	dbPath, err := db.ResolveDBPath()
 	if err != nil {
-		log.Fatalf("Failed to create UI model: %v", err)
+		return fmt.Errorf("failed to locate database: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		log.Fatalf("Error running program: %v", err)
+	if err := db.InitDB(dbPath); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
 	}
+
+	// config.toml overrides the Config table, including log_level below
+	if err := db.LoadConfigFile(); err != nil {
+		slog.Warn("Ignoring config file", "err", err)
+		if notify {
+			fmt.Fprintf(os.Stderr, "Warning: config file ignored: %v\n", err)
+		}
+	}
+
+	level, _ := db.GetConfig("log_level")
+	if err := logging.SetLevel(level); err != nil {
+		slog.Warn("Ignoring log_level config", "err", err)
+	}
+	return nil
+}
+
+// fatal logs an error and exits. Logs only reach the log file, so it is printed too.
+func fatal(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	slog.Error(msg)
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
 }
 
 func printHelp() {
@@ -79,7 +276,93 @@ USAGE:
     DevBase [command]
 
 COMMANDS:
-    scan            Scan directories for projects and add them to database
+    scan [--report] [--all | <path>]
+                    Scan a root folder (default: the one last scanned, --all: every
+                    one) and store the projects found, e.g. from cron or CI; --report
+                    lists the skipped, unreadable, duplicate and registered paths
+    --inline, -i    Pick a project in a compact inline picker and print its path
+                    (e.g. cd "$(devbase --inline)")
+    pick [query]    Print the path of an active project picked in the inline picker,
+                    or of the only one matching the query; for shell key bindings
+                    (pick --shell bash|zsh|fish|pwsh prints a ctrl+g binding)
+    doctor          Check git, the editor, the terminal and the credentials used for cloning
+    import <tool>   Register projects known to another tool and seed their usage
+                    (tool: zoxide, autojump, jetbrains)
+    import projects [--format json|csv] <file>
+                    Register the projects of a dump from "export projects", skipping
+                    paths already registered ('-' reads stdin)
+    remote          Manage SSH hosts and their projects:
+                      remote add <name> <destination>   (user@host or ~/.ssh/config alias)
+                      remote list | remote rm <name>
+                      remote scan <name> <path>         Find projects in a directory on the host
+                      remote register <name> <path>     Add one remote directory as a project
+    open [--first] <name>
+                    Open a project by name, name prefix, fuzzy match or path in its
+                    preferred editor (--first takes the best of several matches)
+    lock <name>     Refuse archiving, removing and clearing a project until
+                    "unlock <name>" (U in the TUI)
+    init <name>     Create a project in the active root folder with git, a .gitignore
+                    and README (--github creates the GitHub repository as origin,
+                    --private makes it private)
+    add [path]      Register a directory (default: the current one) as a project,
+                    detecting its repository URL and language like a scan; for
+                    scripts and git hooks
+    list [--json] [--status active|archived] [--tag name,...]
+                    Print the projects of the active root folder as a table, or
+                    as JSON for scripts (e.g. devbase list | fzf --header-lines=1)
+    stale [--days N]
+                    List projects not opened and without commits for N days
+                    (default: the stale_days config key, or 90) with their size
+                    and repository status; archive them from the TUI with 'Z'
+    ignore          Directories and repositories scans never register, per root folder:
+                      ignore add <directory | repository URL> [root folder path]
+                      ignore list | ignore rm <entry> [root folder path]
+    reclaim         Dependency and build folders (node_modules, target, .venv, ...):
+                      reclaim [--delete]        Measure them in all projects, or delete them
+                      reclaim exclude <project> | reclaim include <project>
+    export <format> [output]
+                    Write a launcher catalog of the active projects that runs
+                    "devbase open" (format: json for PowerToys Run plugins,
+                    alfred for an Alfred Script Filter, raycast for a directory
+                    of Raycast Script Commands)
+    export projects [--format json|csv] [file]
+                    Write every project with its tags, notes, timestamps and root
+                    folder to a dump for another machine (stdout by default)
+    wt              Windows Terminal profiles for pinned projects:
+                      wt pin <name> [command]   Pin a project, optionally running a command on open
+                      wt unpin <name> | wt list
+                      wt sync                   Regenerate the profile fragment
+    serve           Run as a daemon that rescans root folders and serves
+                    Prometheus metrics on /metrics and a health check on /healthz
+                    (--addr 127.0.0.1:9273, --scan-interval 1h, --sync-interval 0;
+                    defaults can be set under [serve] in config.toml)
+    webhook         Notify URLs of archive, restore, sync_failed and stale events:
+                      webhook add <url> [json|slack|discord] [event,...]
+                      webhook list | webhook rm <id>
+                      webhook test [id]         Send a test notification
+    script          Automation scripts (Starlark files in the scripts directory):
+                      script list
+                      script run <name> [project]   Run an action, optionally for a project
+    trash           Checkouts of archived projects kept until retention purges them:
+                      trash [list]              When each one is purged
+                      trash purge               Purge the due ones now
+    pathmap         Rewrite paths of projects loaded from the cloud on this machine:
+                      pathmap add <from> <to>   e.g. pathmap add 'D:\Projects' ~/code
+                      pathmap list | pathmap rm <from>
+                      pathmap test <path>       Show where a synced path ends up
+    worktree        Git worktrees of a project, listed under it in the TUI:
+                      worktree list <project>
+                      worktree add <project> <branch>   Check out a branch next to the project
+                      worktree rm <worktree>            Delete a worktree and its entry
+    settings        Move preferences and root folders between machines (no tokens):
+                      settings export [file]    Write the settings bundle (stdout by default)
+                      settings import <file>    Apply a bundle ('-' reads stdin)
+    telemetry       Opt-in anonymous usage reports (counts only, no paths or names):
+                      telemetry status | telemetry on | telemetry off
+                      telemetry preview         Print exactly what would be sent
+    --verbose       Write debug details to the log file (logs/devbase.log in the data directory)
+    --portable      Keep the database, config.toml and logs next to the executable
+                    (or create a devbase.portable file next to it)
     --help, -h      Show this help message
     --version, -v   Show version information
 
@@ -87,13 +370,40 @@ INTERACTIVE MODE (default):
     When no command is provided, DevBase starts in interactive mode.
 
 KEYBOARD SHORTCUTS:
-    enter           Open project in VS Code
+    enter           Open project in the default editor (remote projects via Remote-SSH)
+    e               Choose the editor to open the project with
     s               Scan for new projects
-    x               Run project in development mode
-    d               Archive selected project (deletes directory)
-    r               Restore archived project (clones from repo)
+    i               Create a new project (git init, optional GitHub repository)
+    x               Pick a task to run (dev mode, scripts, make targets)
+    a               Open or switch to the project's tmux session
+    C               Open the project in its dev container
+    d               Archive selected project (moves directory to the trash)
+    r               Restore archived project (from the trash, else clones from repo)
     f               Manage root folders (press 'e' there to execute commands)
-    /               Filter/search projects
+    v               Cycle list view (all / active / archived)
+    m               Mark / unmark project for opening as a group
+    P               Pin / unpin project (Windows Terminal profile)
+    U               Lock / unlock project against archive, remove and clear all
+    M               Open all marked projects together
+    W               Save marked projects as a named session
+    w               Open a saved session
+    T               Edit project tags
+    N               Edit the project's notes (esc saves)
+    B               Set the branch or tag the project is restored at
+    H               Show the activity history
+    L               Show errors and warnings logged this session
+    Z               Review stale projects and archive them in bulk
+    R               Delete dependency folders (node_modules, target, ...) to reclaim space
+    X               Browse the output of runs captured to a log (c in the x picker)
+    J               Show background jobs (scans, clones, syncs, archiving) and cancel them
+    K               Manage the project's git worktrees (add, register, remove)
+    D               Toggle the project detail pane
+    n               Toggle the project notes pane (below the detail pane)
+    V               Toggle vim-style keybindings (hjkl, gg/G, dd, :)
+    [ / ]           Shrink / grow the list next to the detail and notes panes
+    { / }           Shrink / grow the notes pane below the detail pane
+    ctrl+p          Open the command palette
+    /               Filter/search projects (tag:, status:, lang: filters)
     q, ctrl+c       Quit
 
 FEATURES:
@@ -111,8 +421,1759 @@ For more information, visit: github.com/example/devbase
 `, version)
 }
 
-func handleScan() {
-	fmt.Println("Scan functionality will be added via the UI.")
-	fmt.Println("Please use interactive mode and press 's' to scan.")
-	os.Exit(1)
+// handleInline runs the compact picker without the alternate screen. The picker is drawn on
+// stderr so stdout only carries the chosen path, and the exit code is 1 when nothing is picked.
+func handleInline(opts ui.InlineOptions) {
+	// Bubble Tea handles SIGINT and SIGTERM for the picker
+	if err := initDB(true); err != nil {
+		fatal("%v", err)
+	}
+
+	picker, err := ui.NewInlinePicker(opts)
+	if err != nil {
+		closeDB()
+		fatal("%v", err)
+	}
+	// A query naming a single project needs no picker
+	if opts.Query != "" {
+		if project, ok := picker.SoleMatch(); ok {
+			closeDB()
+			fmt.Println(project.Path)
+			return
+		}
+	}
+
+	result, err := tea.NewProgram(picker, tea.WithOutput(os.Stderr)).Run()
+	if errors.Is(err, tea.ErrProgramPanic) {
+		reportCrash("panic in a background task", nil)
+		closeDB()
+		os.Exit(1)
+	}
+	closeDB()
+	if err != nil {
+		fatal("Error running picker: %v", err)
+	}
+
+	project, ok := result.(ui.InlinePicker).Selected()
+	if !ok {
+		os.Exit(1)
+	}
+	fmt.Println(project.Path)
+}
+
+// pickBindings are the shell snippets printed by "devbase pick --shell", binding ctrl+g to
+// picking a project and changing to its directory. The picker reads keys from the terminal,
+// which zsh widgets don't have on stdin.
+var pickBindings = map[string]string{
+	"bash": `__devbase_pick() {
+  local dir
+  dir="$(devbase pick)" && cd -- "$dir"
+}
+bind -x '"\C-g": __devbase_pick'
+`,
+	"zsh": `__devbase_pick() {
+  local dir
+  dir="$(devbase pick </dev/tty)" && cd -- "$dir"
+  zle reset-prompt
+}
+zle -N __devbase_pick
+bindkey '^G' __devbase_pick
+`,
+	"fish": `function __devbase_pick
+    set -l dir (devbase pick)
+    and cd -- $dir
+    commandline -f repaint
+end
+bind \cg __devbase_pick
+`,
+	"pwsh": `Set-PSReadLineKeyHandler -Chord Ctrl+g -ScriptBlock {
+    $dir = devbase pick
+    if ($LASTEXITCODE -eq 0 -and $dir) {
+        Set-Location -LiteralPath $dir
+        [Microsoft.PowerShell.PSConsoleReadLine]::InvokePrompt()
+    }
+}
+`,
+}
+
+// handlePick prints the path of a project chosen in the inline picker, made for shell key
+// bindings: archived projects are left out, and a query matching one project prints it right
+// away. "--shell <shell>" prints the key binding instead.
+func handlePick(args []string) {
+	if len(args) > 0 && args[0] == "--shell" {
+		if len(args) != 2 || pickBindings[args[1]] == "" {
+			fmt.Fprintln(os.Stderr, "Usage: devbase pick --shell <bash|zsh|fish|pwsh>")
+			os.Exit(2)
+		}
+		fmt.Print(pickBindings[args[1]])
+		return
+	}
+	handleInline(ui.InlineOptions{Query: strings.Join(args, " "), ActiveOnly: true})
+}
+
+// handleScan scans root folders without starting the TUI, e.g. from cron or CI: the given
+// one, every one with --all, or else the one last scanned (the root_scan_path config key).
+// What a scan skipped is counted, and listed with --report.
+func handleScan(args []string) {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	all := fs.Bool("all", false, "scan every root folder")
+	report := fs.Bool("report", false, "list the skipped, unreadable, duplicate and registered paths")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: devbase scan [--report] [--all | <root folder path>]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 || (*all && fs.NArg() > 0) {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	roots, err := scanTargets(fs.Arg(0), *all)
+	if err != nil {
+		closeDB()
+		fatal("%v", err)
+	}
+
+	failed := false
+	for _, root := range roots {
+		result, err := engine.ScanRootFolder(context.Background(), root.ID, root.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to scan %s: %v\n", root.Path, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("Scanned %s\n", result)
+		if *report {
+			printScanReport(result.Report)
+		} else if result.Report.Problems() {
+			fmt.Printf("  %s (--report lists them)\n", result.Report.Summary())
+		}
+	}
+	closeDB()
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printScanReport lists the paths a scan skipped or found registered already
+func printScanReport(report engine.ScanReport) {
+	registered := make([]engine.ScanSkip, len(report.Registered))
+	for i, path := range report.Registered {
+		registered[i] = engine.ScanSkip{Path: path}
+	}
+	for _, section := range []struct {
+		title   string
+		entries []engine.ScanSkip
+	}{
+		{"Skipped by ignore rules", report.Ignored},
+		{"Unreadable", report.Unreadable},
+		{"Duplicate paths", report.Duplicates},
+		{"Already registered", registered},
+	} {
+		fmt.Printf("  %s: %d\n", section.title, len(section.entries))
+		for _, entry := range section.entries {
+			if entry.Reason != "" {
+				fmt.Printf("    %s (%s)\n", entry.Path, entry.Reason)
+			} else {
+				fmt.Printf("    %s\n", entry.Path)
+			}
+		}
+	}
+	if report.IgnoredBuiltin > 0 {
+		fmt.Printf("  Dependency and build directories skipped: %d\n", report.IgnoredBuiltin)
+	}
+}
+
+// scanTargets returns the root folders devbase scan scans. Only registered root folders are
+// scanned, since a scan removes the active projects of its root folder that it didn't find.
+func scanTargets(path string, all bool) ([]models.RootFolder, error) {
+	if all {
+		roots, err := db.GetAllRootFolders()
+		if err == nil && len(roots) == 0 {
+			err = fmt.Errorf("no root folders yet; add one in the TUI first (press 'f')")
+		}
+		return roots, err
+	}
+
+	if path == "" {
+		path, _ = db.GetConfig("root_scan_path")
+		if path == "" {
+			root, err := db.GetActiveRootFolder()
+			if errors.Is(err, db.ErrRootFolderNotFound) {
+				return nil, fmt.Errorf("no active root folder; add one in the TUI first (press 'f')")
+			}
+			if err != nil {
+				return nil, err
+			}
+			return []models.RootFolder{*root}, nil
+		}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root, err := db.GetRootFolderByPath(abs)
+	if errors.Is(err, db.ErrRootFolderNotFound) {
+		return nil, fmt.Errorf("%s is not a root folder; add it in the TUI first (press 'f')", abs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []models.RootFolder{*root}, nil
+}
+
+// handleImport registers projects from another tool's data, e.g. "devbase import zoxide", or
+// from a project dump
+func handleImport(args []string) {
+	if len(args) > 0 && args[0] == "projects" {
+		handleImportProjects(args[1:])
+		return
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: devbase import <zoxide|autojump|jetbrains>\n       devbase import projects [--format json|csv] <file>")
+		os.Exit(2)
+	}
+
+	// Read the tool's data before opening the database so read errors leave it untouched
+	var importer func() (engine.ImportResult, error)
+	switch args[0] {
+	case "zoxide", "autojump":
+		read := engine.ReadZoxide
+		if args[0] == "autojump" {
+			read = engine.ReadAutojump
+		}
+		entries, err := read()
+		if err != nil {
+			fatal("%v", err)
+		}
+		importer = func() (engine.ImportResult, error) { return engine.ImportFrecency(entries) }
+	case "jetbrains":
+		projects, err := engine.ReadJetBrainsProjects()
+		if err != nil {
+			fatal("%v", err)
+		}
+		importer = func() (engine.ImportResult, error) { return engine.ImportJetBrains(projects) }
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown import source %q (expected zoxide, autojump or jetbrains)\n", args[0])
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+
+	result, err := importer()
+	if err == nil {
+		_ = db.LogActivity(models.ActivityScan, 0, fmt.Sprintf("%s import: %d added, %d updated", args[0], result.Added, result.Updated))
+	}
+	closeDB()
+	if err != nil {
+		fatal("Import failed: %v", err)
+	}
+	fmt.Printf("Read %d directories from %s: %d projects added, %d updated\n", result.Entries, args[0], result.Added, result.Updated)
+}
+
+// handleDoctor reports the programs DevBase runs and the git credentials it can clone and
+// restore private repositories with
+func handleDoctor() {
+	check := func(ok bool, label, detail string) {
+		mark := "✓"
+		if !ok {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %-20s %s\n", mark, label, detail)
+	}
+
+	fmt.Println("DevBase doctor")
+	fmt.Println()
+
+	// The configured editor and terminal, when the database can be read
+	var editor, terminal, cloneProtocol string
+	if err := openDB(); err == nil {
+		editor, _ = db.GetConfig("editor")
+		terminal, _ = db.GetConfig("terminal")
+		cloneProtocol, _ = db.GetConfig("clone_protocol")
+		closeDB()
+	}
+
+	version, err := exec.Command("git", "--version").Output()
+	check(err == nil, "git", strings.TrimSpace(string(version)))
+	for _, dep := range engine.CheckDependencies(engine.EditorByCommand(editor), engine.TerminalByCommand(terminal)) {
+		if dep.Command == "git" {
+			continue
+		}
+		detail := dep.Path
+		if dep.Err != nil {
+			detail = dep.Err.Error()
+		}
+		check(dep.Err == nil, dep.Name, detail)
+	}
+	if err != nil {
+		fmt.Println("\nInstall git and make sure it is on PATH to clone and restore projects.")
+		os.Exit(1)
+	}
+
+	auth := engine.DetectGitAuth()
+	helper := auth.CredentialHelper
+	if helper == "" {
+		helper = "not configured"
+	}
+	check(auth.CredentialHelper != "", "Credential helper", helper)
+
+	agent := "no agent or no keys loaded"
+	if auth.SSHAgent {
+		agent = "running with keys loaded"
+	}
+	check(auth.SSHAgent, "SSH agent", agent)
+
+	keys := "none in ~/.ssh"
+	if len(auth.SSHKeys) > 0 {
+		keys = strings.Join(auth.SSHKeys, ", ")
+	}
+	check(len(auth.SSHKeys) > 0, "SSH keys", keys)
+
+	fmt.Println()
+	switch {
+	case auth.PreferSSH():
+		fmt.Println("Private repositories are cloned over SSH first, falling back to HTTPS.")
+	case auth.CredentialHelper != "":
+		fmt.Println("Private repositories are cloned over HTTPS first, falling back to SSH when keys exist.")
+	default:
+		fmt.Println(engine.AuthGuidance(auth))
+	}
+
+	prefs, err := engine.ParseProtocolPreferences(cloneProtocol)
+	if err != nil {
+		fmt.Printf("The clone_protocol config key is invalid, so clones fail: %v\n", err)
+		os.Exit(1)
+	}
+	for _, pref := range prefs {
+		host := pref.Host
+		if host == "" {
+			host = "other hosts"
+		}
+		fmt.Printf("Repositories on %s are only cloned over %s (clone_protocol).\n", host, strings.ToUpper(pref.Protocol))
+	}
+}
+
+// remoteUsage lists the "devbase remote" subcommands
+const remoteUsage = `Usage:
+  devbase remote add <name> <destination>
+  devbase remote list
+  devbase remote rm <name>
+  devbase remote scan <name> <path>
+  devbase remote register <name> <path>`
+
+// handleRemote manages SSH hosts and registers projects that live on them
+func handleRemote(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, remoteUsage)
+		os.Exit(2)
+	}
+	arity := map[string]int{"add": 3, "list": 1, "rm": 2, "scan": 3, "register": 3}
+	if n, ok := arity[args[0]]; !ok || len(args) != n {
+		fmt.Fprintln(os.Stderr, remoteUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runRemote(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runRemote runs a validated "devbase remote" subcommand against the open database
+func runRemote(args []string) error {
+	switch args[0] {
+	case "add":
+		host := &models.RemoteHost{Name: args[1], Destination: args[2]}
+		if err := db.AddRemoteHost(host); err != nil {
+			return err
+		}
+		fmt.Printf("Added remote host %s (%s)\n", host.Name, host.Destination)
+		return nil
+
+	case "list":
+		hosts, err := db.GetRemoteHosts()
+		if err != nil {
+			return err
+		}
+		if len(hosts) == 0 {
+			fmt.Println("No remote hosts. Add one with: devbase remote add <name> <destination>")
+		}
+		for _, host := range hosts {
+			fmt.Printf("%-16s %s\n", host.Name, host.Destination)
+		}
+		return nil
+	}
+
+	host, err := db.GetRemoteHostByName(args[1])
+	if err != nil {
+		return fmt.Errorf("unknown remote host %q, see 'devbase remote list'", args[1])
+	}
+
+	switch args[0] {
+	case "rm":
+		if err := db.DeleteRemoteHost(host.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Removed remote host %s and its project entries\n", host.Name)
+		return nil
+
+	case "register":
+		dir, err := engine.ResolveRemoteDir(*host, args[2])
+		if err != nil {
+			return err
+		}
+		return addRemoteProjects(*host, dir, []models.Project{engine.RemoteProject(*host, dir, "")})
+	}
+
+	projects, err := engine.ScanRemote(*host, args[2])
+	if err != nil {
+		return err
+	}
+	return addRemoteProjects(*host, args[2], projects)
+}
+
+// addRemoteProjects adds remote projects to the active root folder so they show in the list
+func addRemoteProjects(host models.RemoteHost, path string, projects []models.Project) error {
+	var rootFolderID uint
+	if activeRoot, err := db.GetActiveRootFolder(); err == nil && activeRoot != nil {
+		rootFolderID = activeRoot.ID
+	}
+
+	added := 0
+	for i := range projects {
+		projects[i].RootFolderID = rootFolderID
+		if err := db.AddProject(&projects[i]); err == nil {
+			added++
+		}
+	}
+	_ = db.LogActivity(models.ActivityScan, 0, fmt.Sprintf("%s:%s: found %d, added %d", host.Name, path, len(projects), added))
+	fmt.Printf("Found %d projects on %s: %d added, %d already registered\n", len(projects), host.Name, added, len(projects)-added)
+	return nil
+}
+
+// handleOpen opens a project by name or path in its preferred editor, e.g. from an OS launcher
+func handleOpen(args []string) {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	first := fs.Bool("first", false, "open the best match when several projects match")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: devbase open [--first] <name|prefix|path>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	target := fs.Arg(0)
+	if fs.NArg() > 0 {
+		fs.Parse(fs.Args()[1:])
+	}
+	if target == "" || fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	project, err := engine.MatchProject(target, *first)
+	if err == nil {
+		err = ui.OpenProject(*project)
+	}
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// handleLock locks ("lock") or unlocks ("unlock") a project by name or path
+func handleLock(command string, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: devbase %s <name|path>\n", command)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	project, err := engine.FindProject(args[0])
+	if err == nil {
+		err = db.SetProjectLocked(project.ID, command == "lock")
+	}
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+	if command == "lock" {
+		fmt.Printf("%s is locked: archiving, removing and clearing it are refused\n", project.Name)
+	} else {
+		fmt.Printf("%s is unlocked\n", project.Name)
+	}
+}
+
+// exportUsage describes "devbase export"
+const exportUsage = `Usage:
+  devbase export projects [--format json|csv] [file]
+                                     Every project with tags, notes and root folder, for
+                                     "devbase import projects" on another machine
+  devbase export json [file]         Generic JSON catalog (PowerToys Run plugins, scripts)
+  devbase export alfred [file]       Alfred Script Filter JSON
+  devbase export raycast <directory> Raycast Script Commands, one per project`
+
+// handleExport writes a launcher catalog of the active projects whose entries run "devbase open".
+// Catalogs go to stdout unless a file is given; exporting again replaces the previous export.
+// "devbase export projects" writes a project dump instead.
+func handleExport(args []string) {
+	if len(args) > 0 && args[0] == "projects" {
+		handleExportProjects(args[1:])
+		return
+	}
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, exportUsage)
+		os.Exit(2)
+	}
+	format := args[0]
+	var output string
+	if len(args) == 2 {
+		output = args[1]
+	}
+	switch format {
+	case engine.LauncherJSON, engine.LauncherAlfred:
+	case engine.LauncherRaycast:
+		if output == "" {
+			fmt.Fprintln(os.Stderr, exportUsage)
+			os.Exit(2)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown launcher format %q\n%s\n", format, exportUsage)
+		os.Exit(2)
+	}
+
+	// Launchers don't share the shell's PATH, so entries run this executable directly
+	executable, err := os.Executable()
+	if err != nil {
+		fatal("Failed to locate the devbase executable: %v", err)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	projects, err := db.GetProjects()
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+	entries := engine.LauncherEntries(projects, executable)
+
+	if format == engine.LauncherRaycast {
+		written, err := engine.WriteRaycastScripts(entries, output)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Printf("Wrote %d Raycast scripts to %s\n", written, output)
+		return
+	}
+
+	data, err := engine.LauncherCatalog(format, entries)
+	if err != nil {
+		fatal("%v", err)
+	}
+	data = append(data, '\n')
+	if output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		fatal("Failed to write %s: %v", output, err)
+	}
+	fmt.Printf("Exported %d projects to %s\n", len(entries), output)
+}
+
+// projectDumpFormat returns the dump format asked for, or else the one of the file's
+// extension, JSON by default. Reading leaves an unknown format to detection.
+func projectDumpFormat(format, file string, reading bool) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		return engine.DumpCSV
+	}
+	if reading && !strings.EqualFold(filepath.Ext(file), ".json") {
+		return ""
+	}
+	return engine.DumpJSON
+}
+
+// handleExportProjects writes every project with its tags, notes, timestamps and root folder
+// to a JSON or CSV dump, for moving to another machine without the cloud backup
+func handleExportProjects(args []string) {
+	fs := flag.NewFlagSet("export projects", flag.ExitOnError)
+	format := fs.String("format", "", "json or csv (default: from the file extension, or json)")
+	fs.Parse(args)
+	if fs.NArg() > 1 || (*format != "" && *format != engine.DumpJSON && *format != engine.DumpCSV) {
+		fmt.Fprintln(os.Stderr, "Usage: devbase export projects [--format json|csv] [file]")
+		os.Exit(2)
+	}
+	output := fs.Arg(0)
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	projects, err := engine.ExportProjects()
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+	data, err := engine.EncodeProjects(projects, projectDumpFormat(*format, output, false))
+	if err != nil {
+		fatal("%v", err)
+	}
+	if output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		fatal("Failed to write %s: %v", output, err)
+	}
+	fmt.Printf("Exported %d projects to %s\n", len(projects), output)
+}
+
+// handleImportProjects registers the projects of a dump written by "devbase export projects",
+// skipping paths that are already registered
+func handleImportProjects(args []string) {
+	fs := flag.NewFlagSet("import projects", flag.ExitOnError)
+	format := fs.String("format", "", "json or csv (default: from the file extension or contents)")
+	fs.Parse(args)
+	if fs.NArg() != 1 || (*format != "" && *format != engine.DumpJSON && *format != engine.DumpCSV) {
+		fmt.Fprintln(os.Stderr, "Usage: devbase import projects [--format json|csv] <file>   ('-' reads stdin)")
+		os.Exit(2)
+	}
+	input := fs.Arg(0)
+
+	// Read the dump before opening the database so a bad file leaves it untouched
+	var data []byte
+	var err error
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		fatal("%v", err)
+	}
+	projects, err := engine.DecodeProjects(data, projectDumpFormat(*format, input, true))
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	result, err := engine.ImportProjects(projects)
+	if result.Added > 0 {
+		_ = db.LogActivity(models.ActivityScan, 0, fmt.Sprintf("project import: %d added, %d duplicates", result.Added, len(result.Duplicates)))
+	}
+	closeDB()
+	if err != nil {
+		fatal("Import failed after adding %d projects: %v", result.Added, err)
+	}
+	fmt.Printf("Read %d projects: %d added, %d already registered\n", len(projects), result.Added, len(result.Duplicates))
+	for _, path := range result.Duplicates {
+		fmt.Printf("  skipped %s\n", path)
+	}
+	if result.Archived > 0 {
+		fmt.Printf("%d of them are archived, as their directory isn't on this machine; 'r' in the TUI restores them from their repository\n", result.Archived)
+	}
+}
+
+// wtUsage lists the "devbase wt" subcommands
+const wtUsage = `Usage:
+  devbase wt pin <name|path> [command]
+  devbase wt unpin <name|path>
+  devbase wt list
+  devbase wt sync`
+
+// handleWT pins projects and keeps their Windows Terminal profile fragment up to date
+func handleWT(args []string) {
+	valid := len(args) > 0
+	if valid {
+		switch args[0] {
+		case "pin":
+			valid = len(args) >= 2
+		case "unpin":
+			valid = len(args) == 2
+		case "list", "sync":
+			valid = len(args) == 1
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, wtUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runWT(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runWT runs a validated "devbase wt" subcommand against the open database
+func runWT(args []string) error {
+	switch args[0] {
+	case "pin", "unpin":
+		project, err := engine.FindProject(args[1])
+		if err != nil {
+			return err
+		}
+		if project.RemoteHostID != 0 {
+			return fmt.Errorf("%s is a remote project and can't get a terminal profile", project.Name)
+		}
+		pinned, command := args[0] == "pin", strings.Join(args[2:], " ")
+		if err := db.SetProjectPinned(project.ID, pinned, command); err != nil {
+			return err
+		}
+		if pinned {
+			fmt.Printf("Pinned %s\n", project.Name)
+		} else {
+			fmt.Printf("Unpinned %s\n", project.Name)
+		}
+
+	case "list":
+		projects, err := db.GetPinnedProjects()
+		if err != nil {
+			return err
+		}
+		if len(projects) == 0 {
+			fmt.Println("No pinned projects. Pin one with: devbase wt pin <name> [command]")
+			return nil
+		}
+		for _, p := range projects {
+			line := fmt.Sprintf("%-24s %s", p.Name, p.Path)
+			if p.StartCommand != "" {
+				line += "  → " + p.StartCommand
+			}
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	// pin, unpin and sync regenerate the fragment
+	path, profiles, err := engine.SyncWTFragment()
+	if errors.Is(err, engine.ErrNoWindowsTerminal) && args[0] != "sync" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d Windows Terminal profiles in %s\n", profiles, path)
+	return nil
+}
+
+// handleServe runs the daemon with its metrics and health endpoints until it fails or gets
+// SIGINT or SIGTERM, which stop the running scan before the database is closed
+func handleServe(args []string) {
+	if err := initDB(true); err != nil {
+		fatal("%v", err)
+	}
+
+	// Defaults come from the serve_* config keys, e.g. [serve] in config.toml
+	defaultAddr, _ := db.GetConfig("serve_addr")
+	if defaultAddr == "" {
+		defaultAddr = "127.0.0.1:9273"
+	}
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", defaultAddr, "listen address of /metrics and /healthz")
+	scanInterval := fs.Duration("scan-interval", configDuration("serve_scan_interval", time.Hour), "rescan every root folder this often (0 disables)")
+	syncInterval := fs.Duration("sync-interval", configDuration("serve_sync_interval", 0), "push root folders with a cloud backup this often (0 disables)")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := engine.Serve(ctx, engine.ServeOptions{
+		Addr:         *addr,
+		ScanInterval: *scanInterval,
+		SyncInterval: *syncInterval,
+	})
+	stop()
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// handleInit creates a new project in the active root folder. Flags may come before or
+// after the name.
+func handleInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	github := fs.Bool("github", false, "create a GitHub repository and set it as origin")
+	private := fs.Bool("private", false, "make the GitHub repository private")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: devbase init <name> [--github] [--private]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	name := fs.Arg(0)
+	if fs.NArg() > 0 {
+		fs.Parse(fs.Args()[1:])
+	}
+	if name == "" || fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	project, err := initProject(name, *github, *private)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+	fmt.Printf("Created %s\n", project.Path)
+	if project.RepoURL != "" {
+		fmt.Printf("Origin: %s\n", project.RepoURL)
+	}
+}
+
+// initProject creates the project in the active root folder with the saved GitHub token
+func initProject(name string, github, private bool) (*models.Project, error) {
+	root, err := db.GetActiveRootFolder()
+	if errors.Is(err, db.ErrRootFolderNotFound) {
+		return nil, fmt.Errorf("no active root folder; add one in the TUI first (press 'f')")
+	}
+	if err != nil {
+		return nil, err
+	}
+	token, _ := db.GetConfig("github_token")
+	return engine.InitProject(engine.InitOptions{Name: name, Root: root.Path, GitHub: github || private, Private: private, Token: token})
+}
+
+// handleAdd registers one directory as a project without starting the TUI, e.g. from a
+// post-clone git hook
+func handleAdd(args []string) {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: devbase add [path]")
+		os.Exit(2)
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	project, added, err := engine.AddDirectory(dir)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+	if !added {
+		fmt.Printf("%s is already registered at %s\n", project.Name, project.Path)
+		return
+	}
+	fmt.Printf("Added %s at %s\n", project.Name, project.Path)
+	if project.RepoURL != "" {
+		fmt.Printf("Origin: %s\n", project.RepoURL)
+	}
+}
+
+// handleList prints the projects of the active root folder, most recently opened first, as
+// a table or as JSON for scripts
+func handleList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the projects as a JSON array")
+	status := fs.String("status", "", "only projects with this status: active or archived")
+	tag := fs.String("tag", "", "only projects with these tags, comma-separated")
+	fs.Parse(args)
+	if fs.NArg() > 0 || (*status != "" && *status != "active" && *status != "archived") {
+		fmt.Fprintln(os.Stderr, "Usage: devbase list [--json] [--status active|archived] [--tag name[,name...]]")
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	projects, err := engine.ListProjects(*status, *tag)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if *asJSON {
+		out, err := json.MarshalIndent(projects, "", "  ")
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Println(string(out))
+		return
+	}
+	if len(projects) == 0 {
+		fmt.Fprintln(os.Stderr, "No projects found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tLANGUAGE\tTAGS\tPATH")
+	for _, p := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, p.Status, p.Language, strings.Join(p.Tags, ","), p.Path)
+	}
+	w.Flush()
+}
+
+// handleStale prints the stale project report for the active root folder
+func handleStale(args []string) {
+	fs := flag.NewFlagSet("stale", flag.ExitOnError)
+	days := fs.Int("days", 0, "days without opens and commits (default: stale_days config key, or 90)")
+	fs.Parse(args)
+	if fs.NArg() > 0 || *days < 0 {
+		fmt.Fprintln(os.Stderr, "Usage: devbase stale [--days N]")
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	if *days == 0 {
+		*days = engine.StaleDays()
+	}
+	stale, err := engine.StaleProjects(*days)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+	if len(stale) == 0 {
+		fmt.Printf("No projects without opens and commits in %d days\n", *days)
+		return
+	}
+
+	var total int64
+	for _, p := range stale {
+		fmt.Printf("%-24s %-10s %9s  %s\n", p.Project.Name, p.LastActivity.Format(time.DateOnly), engine.FormatSize(p.Size), p.Status())
+		total += p.Size
+	}
+	fmt.Printf("\n%d projects without opens and commits in %d days, %s. Press 'Z' in the TUI to archive them.\n", len(stale), *days, engine.FormatSize(total))
+}
+
+// ignoreUsage lists the "devbase ignore" subcommands
+const ignoreUsage = `Usage:
+  devbase ignore list
+  devbase ignore add <directory | repository URL> [root folder path]
+  devbase ignore rm <entry> [root folder path]
+
+Scans never register the directories (and the projects inside them) and repositories on a
+root folder's list. A directory belongs to the root folder containing it; URLs and entries
+as listed go to the given root folder, or the active one.`
+
+// handleIgnore manages the never-register lists of the root folders
+func handleIgnore(args []string) {
+	valid := len(args) > 0
+	if valid {
+		switch args[0] {
+		case "list":
+			valid = len(args) == 1
+		case "add", "rm":
+			valid = len(args) == 2 || len(args) == 3
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, ignoreUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runIgnore(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runIgnore runs a validated "devbase ignore" subcommand against the open database
+func runIgnore(args []string) error {
+	if args[0] == "list" {
+		rootFolders, err := db.GetAllRootFolders()
+		if err != nil {
+			return err
+		}
+		listed := false
+		for _, rootFolder := range rootFolders {
+			for _, entry := range rootFolder.NeverRegister {
+				fmt.Printf("%-24s %s\n", rootFolder.Name, entry)
+				listed = true
+			}
+		}
+		if !listed {
+			fmt.Println("Nothing is ignored. Add a directory or repository with: devbase ignore add <directory | repository URL>")
+		}
+		return nil
+	}
+
+	rootPath := ""
+	if len(args) == 3 {
+		rootPath = args[2]
+	}
+	rootFolder, err := ignoreRootFolder(args[1], rootPath)
+	if err != nil {
+		return err
+	}
+	if args[0] == "rm" {
+		if err := engine.AllowRegister(rootFolder, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s from the never-register list of %s; the next scan registers it again\n", args[1], rootFolder.Name)
+		return nil
+	}
+	entry, removed, err := engine.NeverRegister(rootFolder, args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scans of %s skip %s", rootFolder.Name, entry)
+	if removed > 0 {
+		fmt.Printf("; removed %d project(s)", removed)
+	}
+	fmt.Println()
+	return nil
+}
+
+// ignoreRootFolder returns the root folder an ignore entry belongs to: the one at rootPath
+// when given, the one containing a directory entry, or the active one
+func ignoreRootFolder(entry, rootPath string) (*models.RootFolder, error) {
+	if rootPath != "" {
+		path, err := filepath.Abs(rootPath)
+		if err != nil {
+			return nil, err
+		}
+		return db.GetRootFolderByPath(path)
+	}
+	if _, ok := engine.ParseRepoURL(entry); !ok {
+		if path, err := filepath.Abs(entry); err == nil {
+			if rootFolder, err := db.GetRootFolderForPath(path); err != nil {
+				return nil, err
+			} else if rootFolder != nil {
+				return rootFolder, nil
+			}
+		}
+	}
+	rootFolder, err := db.GetActiveRootFolder()
+	if errors.Is(err, db.ErrRootFolderNotFound) {
+		return nil, fmt.Errorf("no active root folder; give the root folder path")
+	}
+	return rootFolder, err
+}
+
+// pathMapUsage lists the "devbase pathmap" subcommands
+const pathMapUsage = `Usage:
+  devbase pathmap list
+  devbase pathmap add <from> <to>
+  devbase pathmap rm <from>
+  devbase pathmap test <path>
+
+Rules are kept in this machine's database. When projects are loaded from the cloud, paths
+under <from> (as written on the other machine) are moved under <to>.`
+
+// handlePathMap manages the path mapping rules applied to projects loaded from the cloud
+func handlePathMap(args []string) {
+	valid := len(args) > 0
+	if valid {
+		switch args[0] {
+		case "list":
+			valid = len(args) == 1
+		case "add":
+			valid = len(args) == 3
+		case "rm", "test":
+			valid = len(args) == 2
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, pathMapUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runPathMap(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runPathMap runs a validated "devbase pathmap" subcommand against the open database
+func runPathMap(args []string) error {
+	mappings, err := engine.PathMappings()
+	if err != nil {
+		return fmt.Errorf("path_map config key: %w", err)
+	}
+
+	switch args[0] {
+	case "add":
+		return engine.SetPathMappings(engine.ReplacePathMapping(mappings, engine.PathMapping{From: args[1], To: args[2]}))
+	case "rm":
+		kept, removed := engine.RemovePathMapping(mappings, args[1])
+		if !removed {
+			return fmt.Errorf("no rule for %s", args[1])
+		}
+		return engine.SetPathMappings(kept)
+	case "test":
+		var root string
+		if rootFolder, err := db.GetActiveRootFolder(); err == nil {
+			root = rootFolder.Path
+		}
+		projects := []models.Project{{Path: args[1]}}
+		engine.MapCloudPaths(projects, mappings, root)
+		fmt.Println(projects[0].Path)
+		return nil
+	}
+
+	if len(mappings) == 0 {
+		fmt.Println("No path mapping rules. Add one with: devbase pathmap add <from> <to>")
+	}
+	for _, m := range mappings {
+		fmt.Println(m)
+	}
+	return nil
+}
+
+// reclaimUsage lists the "devbase reclaim" subcommands
+const reclaimUsage = `Usage:
+  devbase reclaim [--delete]
+  devbase reclaim exclude <project>
+  devbase reclaim include <project>
+
+Dependency and build folders such as node_modules, target and .venv are measured in every
+active project; --delete deletes them, except in excluded projects.`
+
+// handleReclaim measures or deletes dependency folders, and opts projects out of it
+func handleReclaim(args []string) {
+	valid := len(args) == 0
+	if len(args) > 0 {
+		switch args[0] {
+		case "--delete":
+			valid = len(args) == 1
+		case "exclude", "include":
+			valid = len(args) == 2
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, reclaimUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runReclaim(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runReclaim runs a validated "devbase reclaim" subcommand against the open database
+func runReclaim(args []string) error {
+	if len(args) == 2 {
+		project, err := engine.FindProject(args[1])
+		if err != nil {
+			return err
+		}
+		exclude := args[0] == "exclude"
+		if err := db.SetProjectNoReclaim(project.ID, exclude); err != nil {
+			return err
+		}
+		if exclude {
+			fmt.Printf("%s is excluded from reclaiming\n", project.Name)
+		} else {
+			fmt.Printf("%s is included in reclaiming again\n", project.Name)
+		}
+		return nil
+	}
+
+	projects, err := engine.ReclaimableSpace()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, p := range projects {
+		names := make([]string, len(p.Dirs))
+		for i, dir := range p.Dirs {
+			names[i] = dir.Name
+		}
+		note := ""
+		if p.Project.NoReclaim {
+			note = "  (excluded)"
+		} else {
+			total += p.Size
+		}
+		fmt.Printf("%-24s %9s  %s%s\n", p.Project.Name, engine.FormatSize(p.Size), strings.Join(names, ", "), note)
+	}
+	if len(args) == 0 {
+		fmt.Printf("\n%s reclaimable. Run \"devbase reclaim --delete\" to delete these folders.\n", engine.FormatSize(total))
+		return nil
+	}
+
+	freed, err := engine.ReclaimSpace(projects)
+	fmt.Printf("\nFreed %s\n", engine.FormatSize(freed))
+	return err
+}
+
+// configDuration reads a duration such as "30m" from config, falling back when it is unset
+// or invalid
+func configDuration(key string, fallback time.Duration) time.Duration {
+	value, _ := db.GetConfig(key)
+	if d, err := time.ParseDuration(value); err == nil {
+		return d
+	}
+	return fallback
+}
+
+// webhookUsage lists the "devbase webhook" subcommands
+const webhookUsage = `Usage:
+  devbase webhook add <url> [json|slack|discord] [event,...]
+  devbase webhook list
+  devbase webhook rm <id>
+  devbase webhook test [id]
+
+Events: archive, restore, sync_failed, stale (default: all)`
+
+// handleWebhook manages the webhooks notified of lifecycle events
+func handleWebhook(args []string) {
+	valid := len(args) > 0
+	if valid {
+		switch args[0] {
+		case "add":
+			valid = len(args) >= 2 && len(args) <= 4
+		case "list":
+			valid = len(args) == 1
+		case "rm":
+			valid = len(args) == 2
+		case "test":
+			valid = len(args) <= 2
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, webhookUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runWebhook(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runWebhook runs a validated "devbase webhook" subcommand against the open database
+func runWebhook(args []string) error {
+	switch args[0] {
+	case "add":
+		webhook := &models.Webhook{URL: args[1], Format: models.WebhookJSON}
+		if len(args) > 2 {
+			webhook.Format = args[2]
+		}
+		switch webhook.Format {
+		case models.WebhookJSON, models.WebhookSlack, models.WebhookDiscord:
+		default:
+			return fmt.Errorf("unknown webhook format %q (expected json, slack or discord)", webhook.Format)
+		}
+		if len(args) > 3 {
+			for _, event := range strings.Split(args[3], ",") {
+				event = strings.TrimSpace(event)
+				if !slices.Contains(engine.EventKinds, event) {
+					return fmt.Errorf("unknown event %q (expected %s)", event, strings.Join(engine.EventKinds, ", "))
+				}
+				webhook.Events = append(webhook.Events, event)
+			}
+		}
+		if err := db.AddWebhook(webhook); err != nil {
+			return err
+		}
+		fmt.Printf("Added webhook %d (%s)\n", webhook.ID, webhook.Format)
+		return nil
+
+	case "list":
+		webhooks, err := db.GetWebhooks()
+		if err != nil {
+			return err
+		}
+		if len(webhooks) == 0 {
+			fmt.Println("No webhooks. Add one with: devbase webhook add <url> [json|slack|discord] [event,...]")
+		}
+		for _, w := range webhooks {
+			events := "all events"
+			if len(w.Events) > 0 {
+				events = strings.Join(w.Events, ",")
+			}
+			fmt.Printf("%-4d %-8s %-32s %s\n", w.ID, w.Format, events, w.URL)
+		}
+		return nil
+
+	case "rm":
+		id, err := strconv.ParseUint(args[1], 10, 0)
+		if err != nil {
+			return fmt.Errorf("invalid webhook id %q, see 'devbase webhook list'", args[1])
+		}
+		if err := db.DeleteWebhook(uint(id)); err != nil {
+			return err
+		}
+		fmt.Printf("Removed webhook %d\n", id)
+		return nil
+	}
+
+	// test sends to one webhook regardless of its events, or to all of them
+	webhooks, err := db.GetWebhooks()
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		id, err := strconv.ParseUint(args[1], 10, 0)
+		if err != nil {
+			return fmt.Errorf("invalid webhook id %q, see 'devbase webhook list'", args[1])
+		}
+		webhooks = slices.DeleteFunc(webhooks, func(w models.Webhook) bool { return w.ID != uint(id) })
+		if len(webhooks) == 0 {
+			return fmt.Errorf("%w: %d", db.ErrWebhookNotFound, id)
+		}
+	}
+	event := engine.Event{Kind: engine.EventTest, Time: time.Now()}
+	failed := 0
+	for _, w := range webhooks {
+		if err := engine.SendWebhook(w, event); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Delivered to webhook %d\n", w.ID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d webhooks failed", failed, len(webhooks))
+	}
+	return nil
+}
+
+// scriptUsage lists the "devbase script" subcommands
+const scriptUsage = `Usage:
+  devbase script list
+  devbase script run <name> [project]
+
+Scripts are Starlark <name>.star files in the scripts directory next to config.toml.
+"run" calls the script's action(project) function.`
+
+// handleScript lists and runs automation scripts
+func handleScript(args []string) {
+	valid := len(args) > 0
+	if valid {
+		switch args[0] {
+		case "list":
+			valid = len(args) == 1
+		case "run":
+			valid = len(args) == 2 || len(args) == 3
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, scriptUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runScript(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runScript runs a validated "devbase script" subcommand against the open database
+func runScript(args []string) error {
+	if args[0] == "list" {
+		scripts, err := engine.Scripts()
+		if err != nil {
+			return err
+		}
+		if len(scripts) == 0 {
+			dir, _ := db.ScriptsDir()
+			fmt.Printf("No scripts. Add <name>%s files to %s\n", engine.ScriptExt, dir)
+		}
+		for _, s := range scripts {
+			var functions []string
+			if s.Action {
+				functions = append(functions, "action")
+			}
+			for _, event := range s.Events {
+				functions = append(functions, "on_"+event)
+			}
+			title := s.Title
+			if s.Err != nil {
+				title = s.Err.Error()
+			}
+			fmt.Printf("%-20s %-24s %s\n", s.Name, strings.Join(functions, ", "), title)
+		}
+		return nil
+	}
+
+	script, err := engine.FindScript(args[1])
+	if err != nil {
+		return err
+	}
+	var project *models.Project
+	if len(args) == 3 {
+		if project, err = engine.FindProject(args[2]); err != nil {
+			return err
+		}
+	}
+	output, err := script.Run(project)
+	if output != "" {
+		fmt.Println(output)
+	}
+	return err
+}
+
+// trashUsage lists the "devbase trash" subcommands
+const trashUsage = `Usage:
+  devbase trash [list]
+  devbase trash purge
+
+Archiving keeps a project's checkout in the trash for archive_trash_days days
+(0, the default, deletes it right away).`
+
+// handleTrash shows and purges the checkouts of archived projects in the trash
+func handleTrash(args []string) {
+	command := "list"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	if len(args) > 1 || (command != "list" && command != "purge") {
+		fmt.Fprintln(os.Stderr, trashUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runTrash(command)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runTrash runs a validated "devbase trash" subcommand against the open database
+func runTrash(command string) error {
+	if command == "purge" {
+		purged, err := engine.PurgeTrash()
+		var size int64
+		for _, purge := range purged {
+			fmt.Printf("Purged %s (%s, %s)\n", purge.Entry.Name, engine.FormatSize(purge.Entry.Size), purge.Reason)
+			size += purge.Entry.Size
+		}
+		fmt.Printf("Purged %d archived projects, freed %s\n", len(purged), engine.FormatSize(size))
+		return err
+	}
+
+	report, err := engine.TrashReport()
+	if err != nil {
+		return err
+	}
+	retention := engine.TrashRetentionConfig()
+	if retention.Days == 0 {
+		fmt.Println("Archiving deletes checkouts (archive_trash_days is 0)")
+	} else {
+		fmt.Printf("Keeping archived checkouts for %d days\n", retention.Days)
+	}
+	if len(report) == 0 {
+		fmt.Println("The trash is empty")
+		return nil
+	}
+	now := time.Now()
+	for _, purge := range report {
+		when := purge.At.Format(time.DateOnly)
+		if purge.Due(now) {
+			when = "next cleanup"
+		}
+		fmt.Printf("%-24s %9s  archived %s  purged %-12s %s\n", purge.Entry.Name, engine.FormatSize(purge.Entry.Size),
+			purge.Entry.CreatedAt.Format(time.DateOnly), when, purge.Reason)
+	}
+	return nil
+}
+
+// telemetryUsage lists the "devbase telemetry" subcommands
+const telemetryUsage = `Usage:
+  devbase telemetry [status]
+  devbase telemetry on | off
+  devbase telemetry preview`
+
+// handleTelemetry shows, previews and toggles the opt-in usage reports
+func handleTelemetry(args []string) {
+	command := "status"
+	if len(args) == 1 {
+		command = args[0]
+	}
+	if len(args) > 1 || !slices.Contains([]string{"status", "on", "off", "preview"}, command) {
+		fmt.Fprintln(os.Stderr, telemetryUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runTelemetry(command)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runTelemetry runs a validated "devbase telemetry" subcommand against the open database
+func runTelemetry(command string) error {
+	switch command {
+	case "on", "off":
+		if err := engine.SetTelemetry(command == "on"); err != nil {
+			return err
+		}
+		if command == "off" {
+			fmt.Println("Telemetry is off; nothing will be sent")
+			return nil
+		}
+		fmt.Println("Telemetry is on. A report like 'devbase telemetry preview' shows is sent once a week")
+		if url, _ := db.GetConfig("telemetry_url"); url == "" {
+			fmt.Println("No reports are sent until the telemetry_url config key is set")
+		}
+		return nil
+
+	case "preview":
+		report, err := engine.BuildTelemetryReport()
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if !engine.TelemetryEnabled() {
+		fmt.Println("Telemetry is off (default). Turn it on with: devbase telemetry on")
+		return nil
+	}
+	url, _ := db.GetConfig("telemetry_url")
+	last, _ := db.GetConfig("telemetry_last_sent")
+	if last == "" {
+		last = "never"
+	}
+	fmt.Printf("Telemetry is on\nEndpoint:  %s\nLast sent: %s\n", url, last)
+	return nil
+}
+
+// worktreeUsage lists the "devbase worktree" subcommands
+const worktreeUsage = `Usage:
+  devbase worktree list <project>
+  devbase worktree add <project> <branch>
+  devbase worktree rm <worktree>
+
+Projects are given by name or path. New worktrees go next to the project, named after it
+and the branch, and are registered as projects linked to it.`
+
+// handleWorktree lists, adds and removes the git worktrees of a project
+func handleWorktree(args []string) {
+	arity := map[string]int{"list": 2, "add": 3, "rm": 2}
+	if len(args) == 0 || arity[args[0]] != len(args) {
+		fmt.Fprintln(os.Stderr, worktreeUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runWorktree(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runWorktree runs a validated "devbase worktree" subcommand against the open database
+func runWorktree(args []string) error {
+	project, err := engine.FindProject(args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		if project.ParentID != 0 {
+			return fmt.Errorf("%s is a worktree; add worktrees to its project instead", project.Name)
+		}
+		worktree, err := engine.AddWorktree(*project, args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Added worktree %s for %s at %s\n", worktree.Name, args[2], worktree.Path)
+		return nil
+
+	case "rm":
+		if project.ParentID == 0 {
+			return fmt.Errorf("%s is not registered as a worktree", project.Name)
+		}
+		parent, err := db.GetProjectByID(project.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := engine.RemoveWorktree(*parent, *project); err != nil {
+			return err
+		}
+		fmt.Printf("Removed worktree %s\n", project.Path)
+		return nil
+	}
+
+	worktrees, err := engine.ListWorktrees(project.Path)
+	if err != nil {
+		return err
+	}
+	for _, wt := range worktrees {
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		entry := "not registered"
+		if wt.Main {
+			entry = "main"
+		} else if p, err := db.GetProjectByPath(wt.Path); err == nil {
+			entry = p.Name
+		}
+		fmt.Printf("%-28s %-24s %s\n", branch, entry, wt.Path)
+	}
+	return nil
+}
+
+// settingsUsage lists the "devbase settings" subcommands
+const settingsUsage = `Usage:
+  devbase settings export [file]
+  devbase settings import <file>
+
+The bundle holds keybindings, theme and layout, editor and terminal preferences, ignore
+lists and root folders. Tokens, gist IDs and this machine's path mappings stay out.
+Import reads stdin when <file> is "-".`
+
+// handleSettings exports and imports the settings bundle
+func handleSettings(args []string) {
+	valid := len(args) > 0
+	if valid {
+		switch args[0] {
+		case "export":
+			valid = len(args) <= 2
+		case "import":
+			valid = len(args) == 2
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, settingsUsage)
+		os.Exit(2)
+	}
+
+	if err := openDB(); err != nil {
+		fatal("%v", err)
+	}
+	err := runSettings(args)
+	closeDB()
+	if err != nil {
+		fatal("%v", err)
+	}
+}
+
+// runSettings runs a validated "devbase settings" subcommand against the open database
+func runSettings(args []string) error {
+	if args[0] == "export" {
+		settings, err := engine.ExportSettings()
+		if err != nil {
+			return err
+		}
+		data, err := engine.EncodeSettings(settings)
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 || args[1] == "-" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(args[1], data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", args[1], err)
+		}
+		fmt.Printf("Exported %d settings and %d root folders to %s\n", len(settings.Config), len(settings.RootFolders), args[1])
+		return nil
+	}
+
+	var data []byte
+	var err error
+	if args[1] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[1], err)
+	}
+	settings, err := engine.DecodeSettings(data)
+	if err != nil {
+		return err
+	}
+	result, err := engine.ImportSettings(settings)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d settings", len(result.Config))
+	if len(result.Config) > 0 {
+		fmt.Printf(": %s", strings.Join(result.Config, ", "))
+	}
+	fmt.Println()
+	for _, path := range result.RootFolders {
+		fmt.Printf("Added root folder %s\n", path)
+	}
+	if result.Activated != "" {
+		fmt.Printf("Active root folder: %s\n", result.Activated)
+	}
+	for _, path := range result.Missing {
+		fmt.Printf("Skipped root folder %s (not found here; add a rule with 'devbase pathmap add')\n", path)
+	}
+	if len(result.Overridden) > 0 {
+		fmt.Printf("config.toml still sets %s; edit it to use the imported values\n", strings.Join(result.Overridden, ", "))
+	}
+	if len(result.Ignored) > 0 {
+		fmt.Printf("Ignored unknown keys: %s\n", strings.Join(result.Ignored, ", "))
+	}
+	return nil
 }
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"devbase/models"
+)
+
+// benchProjects creates n projects in a root folder, every third one archived
+func benchProjects(rootFolderID uint, n int) []models.Project {
+	projects := make([]models.Project, n)
+	for i := range projects {
+		projects[i] = models.Project{
+			Name:         fmt.Sprintf("project-%d", i),
+			Path:         fmt.Sprintf("/bench/project-%d", i),
+			RepoURL:      fmt.Sprintf("https://github.com/example/project-%d.git", i),
+			Status:       "active",
+			Language:     "go",
+			Tags:         []string{"bench"},
+			RootFolderID: rootFolderID,
+		}
+		if i%3 == 0 {
+			projects[i].Status = "archived"
+		}
+	}
+	return projects
+}
+
+// benchRootFolder adds the active root folder the benchmark projects belong to
+func benchRootFolder(b *testing.B) uint {
+	b.Helper()
+	root := models.RootFolder{Name: "Bench", Path: "/bench", IsActive: true}
+	if err := AddRootFolder(&root); err != nil {
+		b.Fatalf("AddRootFolder failed: %v", err)
+	}
+	return root.ID
+}
+
+// BenchmarkReconcileProjects measures storing the first scan of a root folder with 1000
+// projects, the bulk insert path of scans
+func BenchmarkReconcileProjects(b *testing.B) {
+	for b.Loop() {
+		b.StopTimer()
+		setupTestDB(b)
+		rootID := benchRootFolder(b)
+		scanned := benchProjects(rootID, 1000)
+		b.StartTimer()
+
+		if _, err := ReconcileProjects(rootID, scanned, nil); err != nil {
+			b.Fatalf("ReconcileProjects failed: %v", err)
+		}
+
+		b.StopTimer()
+		teardownTestDB(b)
+		b.StartTimer()
+	}
+}
+
+// BenchmarkAddProject measures inserting projects one at a time, as imports and clones do
+func BenchmarkAddProject(b *testing.B) {
+	setupTestDB(b)
+	defer teardownTestDB(b)
+	rootID := benchRootFolder(b)
+
+	i := 0
+	for b.Loop() {
+		project := benchProjects(rootID, 1)[0]
+		project.Path = fmt.Sprintf("/bench/added-%d", i)
+		if err := AddProject(&project); err != nil {
+			b.Fatalf("AddProject failed: %v", err)
+		}
+		i++
+	}
+}
+
+// BenchmarkGetProjects measures loading the project list of a root folder with 5000 projects
+func BenchmarkGetProjects(b *testing.B) {
+	setupTestDB(b)
+	defer teardownTestDB(b)
+	rootID := benchRootFolder(b)
+	if _, err := ReconcileProjects(rootID, benchProjects(rootID, 5000), nil); err != nil {
+		b.Fatalf("ReconcileProjects failed: %v", err)
+	}
+
+	for b.Loop() {
+		projects, err := GetProjects()
+		if err != nil {
+			b.Fatalf("GetProjects failed: %v", err)
+		}
+		if len(projects) != 5000 {
+			b.Fatalf("Expected 5000 projects, got %d", len(projects))
+		}
+	}
+}
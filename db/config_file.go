@@ -0,0 +1,310 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConfigFileName is the declarative config file in the DevBase config directory
+const ConfigFileName = "config.toml"
+
+// fileConfig holds the values read from config.toml, keyed like the Config table
+var fileConfig struct {
+	sync.RWMutex
+	values  map[string]string
+	modTime time.Time
+	size    int64
+}
+
+// ConfigFilePath returns the location of config.toml, next to the saved database location
+func ConfigFilePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigFileName), nil
+}
+
+// ScriptsDir returns the directory of automation scripts, next to config.toml
+func ScriptsDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scripts"), nil
+}
+
+// LoadConfigFile reads config.toml. Its values take precedence over the Config table in
+// GetConfig. A missing file clears them; a file with errors leaves the last values in place.
+func LoadConfigFile() error {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		fileConfig.Lock()
+		fileConfig.values, fileConfig.modTime, fileConfig.size = nil, time.Time{}, 0
+		fileConfig.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Remember the version even when it fails to parse, so it is reported only once
+	fileConfig.Lock()
+	defer fileConfig.Unlock()
+	fileConfig.modTime, fileConfig.size = info.ModTime(), info.Size()
+	values, err := ParseConfigFile(data)
+	if err != nil {
+		return fmt.Errorf("%s:%w", path, err)
+	}
+	fileConfig.values = values
+	return nil
+}
+
+// ReloadConfigFile loads config.toml again when it was created, changed or removed since
+// the last load, and reports whether it did
+func ReloadConfigFile() (bool, error) {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return false, err
+	}
+
+	var modTime time.Time
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	}
+
+	fileConfig.RLock()
+	unchanged := modTime.Equal(fileConfig.modTime) && size == fileConfig.size
+	fileConfig.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	return true, LoadConfigFile()
+}
+
+// fileConfigValue returns the config.toml value of a config key
+func fileConfigValue(key string) (string, bool) {
+	fileConfig.RLock()
+	defer fileConfig.RUnlock()
+	value, ok := fileConfig.values[key]
+	return value, ok
+}
+
+// ParseConfigFile parses the TOML subset used by config.toml into config keys. A key in a
+// [table] becomes "table_key" (so [editor] prompt = true sets editor_prompt) and arrays are
+// joined with commas. Strings, booleans, numbers and single-line arrays are supported.
+func ParseConfigFile(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	table := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") || strings.HasPrefix(text, "[[") {
+				return nil, fmt.Errorf("%d: invalid table header %q", line, text)
+			}
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if !validConfigKey(name) {
+				return nil, fmt.Errorf("%d: invalid table name %q", line, name)
+			}
+			table = strings.ReplaceAll(name, ".", "_")
+			continue
+		}
+
+		rawKey, rawValue, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%d: expected key = value", line)
+		}
+		key := strings.TrimSpace(rawKey)
+		if !validConfigKey(key) {
+			return nil, fmt.Errorf("%d: invalid key %q", line, key)
+		}
+		key = strings.ReplaceAll(key, ".", "_")
+		if table != "" {
+			key = table + "_" + key
+		}
+
+		value, err := parseConfigValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("%d: %s: %w", line, key, err)
+		}
+		if _, exists := values[key]; exists {
+			return nil, fmt.Errorf("%d: %s is set twice", line, key)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// validConfigKey reports whether a key or table name is bare, optionally dotted
+func validConfigKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, part := range strings.Split(key, ".") {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseConfigValue converts a TOML value to its config string
+func parseConfigValue(raw string) (string, error) {
+	switch {
+	case raw == "":
+		return "", fmt.Errorf("missing value")
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return "", fmt.Errorf("arrays must be on one line")
+		}
+		items, err := splitConfigArray(raw[1 : len(raw)-1])
+		if err != nil {
+			return "", err
+		}
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			value, err := parseConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, value)
+		}
+		return strings.Join(parts, ","), nil
+	case strings.HasPrefix(raw, `"`):
+		if len(raw) < 2 || !strings.HasSuffix(raw, `"`) {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return unescapeConfigString(raw[1 : len(raw)-1])
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return raw[1 : len(raw)-1], nil
+	case raw == "true" || raw == "false":
+		return raw, nil
+	}
+
+	// Numbers (and durations written without quotes are rejected here, e.g. 30m)
+	for i, r := range raw {
+		if !(r >= '0' && r <= '9' || r == '.' || r == '_' || (i == 0 && (r == '-' || r == '+'))) {
+			return "", fmt.Errorf("invalid value %q (quote strings)", raw)
+		}
+	}
+	return strings.ReplaceAll(raw, "_", ""), nil
+}
+
+// splitConfigArray splits the inside of an array at commas outside of strings
+func splitConfigArray(inner string) ([]string, error) {
+	var items []string
+	var current strings.Builder
+	var quote rune
+	escaped := false
+	for _, r := range inner {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == ',':
+			items = append(items, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in array")
+	}
+	// A trailing comma is allowed
+	if last := strings.TrimSpace(current.String()); last != "" {
+		items = append(items, last)
+	}
+	for _, item := range items {
+		if item == "" {
+			return nil, fmt.Errorf("empty array element")
+		}
+	}
+	return items, nil
+}
+
+// unescapeConfigString resolves the escapes of a basic TOML string
+func unescapeConfigString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", fmt.Errorf("unterminated escape")
+		}
+		switch s[i] {
+		case '\\', '"':
+			b.WriteByte(s[i])
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			return "", fmt.Errorf(`unsupported escape \%c`, s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// stripComment removes a # comment that is outside of strings
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -2,12 +2,19 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 
 	"devbase/models"
@@ -17,6 +24,41 @@ import (
 
 var DB *gorm.DB
 
+// Errors returned when a record doesn't exist, wrapped with the ID, path or name that was
+// looked up. Check them with errors.Is.
+var (
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrRootFolderNotFound = errors.New("root folder not found")
+	ErrRemoteHostNotFound = errors.New("remote host not found")
+	ErrWebhookNotFound    = errors.New("webhook not found")
+)
+
+// ErrProjectLocked is returned when archiving or deleting a locked project, wrapped with the
+// names of the locked projects. Unlock them first with SetProjectLocked.
+var ErrProjectLocked = errors.New("project is locked")
+
+// lockedProjects returns an ErrProjectLocked naming the locked projects the condition
+// matches, or nil when there are none
+func lockedProjects(tx *gorm.DB, query string, args ...any) error {
+	var names []string
+	if err := tx.Model(&models.Project{}).Where("locked = ?", true).Where(query, args...).Order("name").Pluck("name", &names).Error; err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %s", ErrProjectLocked, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// notFound replaces gorm's ErrRecordNotFound with the sentinel of the record that was looked
+// up, naming it by key. Other errors are returned as they are.
+func notFound(err, sentinel error, key any) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %v", sentinel, key)
+	}
+	return err
+}
+
 // InitDB initializes the SQLite database connection with optimal performance settings
 func InitDB(dbPath string) error {
 	var err error
@@ -29,7 +71,8 @@ func InitDB(dbPath string) error {
 
 	// Open SQLite connection using modernc.org/sqlite (pure Go, no CGO)
 	// Add DSN parameters for proper datetime handling
-	dsn := dbPath + "?_pragma=busy_timeout(5000)&_time_format=sqlite"
+	// Transactions take the write lock when they begin, so busy_timeout covers them
+	dsn := dbPath + "?_pragma=busy_timeout(5000)&_time_format=sqlite&_txlock=immediate"
 	sqlDB, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return fmt.Errorf("failed to open sqlite database: %w", err)
@@ -60,38 +103,55 @@ func InitDB(dbPath string) error {
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
 	// Auto-migrate the schema
-	if err := DB.AutoMigrate(&models.RootFolder{}, &models.Project{}, &models.Config{}); err != nil {
+	if err := DB.AutoMigrate(&models.RootFolder{}, &models.Project{}, &models.Config{}, &models.Session{}, &models.Activity{}, &models.RemoteHost{}, &models.RepoMetadata{}, &models.Webhook{}, &models.DevRun{}, &models.TrashEntry{}); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	log.Println("Database initialized successfully with WAL mode and optimized settings")
+	slog.Debug("Database initialized with WAL mode", "path", dbPath)
 	return nil
 }
 
 // GetProjects retrieves all projects sorted by LastOpened descending
 // If a root folder is active, only returns projects from that root folder
 func GetProjects() ([]models.Project, error) {
+	return GetProjectsByStatus("")
+}
+
+// GetProjectsByStatus retrieves projects with the given status sorted by LastOpened descending
+// An empty status returns projects of every status. Results are scoped to the active root folder if one exists
+func GetProjectsByStatus(status string) ([]models.Project, error) {
 	var projects []models.Project
 
+	query := DB.Model(&models.Project{})
+
 	// Try to get active root folder
 	activeRoot, err := GetActiveRootFolder()
 	if err == nil && activeRoot != nil {
 		// Filter by active root folder
-		result := DB.Where("root_folder_id = ?", activeRoot.ID).Order("last_opened DESC").Find(&projects)
-		if result.Error != nil {
-			return nil, fmt.Errorf("failed to retrieve projects: %w", result.Error)
-		}
-	} else {
-		// No active root folder, return all projects
-		result := DB.Order("last_opened DESC").Find(&projects)
-		if result.Error != nil {
-			return nil, fmt.Errorf("failed to retrieve projects: %w", result.Error)
-		}
+		query = query.Where("root_folder_id = ?", activeRoot.ID)
+	}
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	result := query.Order("last_opened DESC").Find(&projects)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve projects: %w", result.Error)
 	}
 
 	return projects, nil
 }
 
+// GetAllProjects retrieves the projects of every root folder and status sorted by path
+func GetAllProjects() ([]models.Project, error) {
+	var projects []models.Project
+	if err := DB.Order("path ASC").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve projects: %w", err)
+	}
+	return projects, nil
+}
+
 // AddProject adds a new project to the database
 func AddProject(project *models.Project) error {
 	// Set LastOpened to current time if not set
@@ -109,9 +169,13 @@ func AddProject(project *models.Project) error {
 		return fmt.Errorf("invalid status: must be 'active' or 'archived'")
 	}
 
-	result := DB.Create(project)
-	if result.Error != nil {
-		return fmt.Errorf("failed to add project: %w", result.Error)
+	// Projects loaded from the cloud keep the machine that registered them
+	if project.Machine == "" {
+		project.Machine = MachineName()
+	}
+
+	if err := write(func(tx *gorm.DB) error { return tx.Create(project).Error }); err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
 	}
 	return nil
 }
@@ -121,7 +185,7 @@ func GetProjectByID(id uint) (*models.Project, error) {
 	var project models.Project
 	result := DB.First(&project, id)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to retrieve project: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve project: %w", notFound(result.Error, ErrProjectNotFound, id))
 	}
 	return &project, nil
 }
@@ -131,72 +195,535 @@ func GetProjectByPath(path string) (*models.Project, error) {
 	var project models.Project
 	result := DB.Where("path = ?", path).First(&project)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to retrieve project: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve project: %w", notFound(result.Error, ErrProjectNotFound, path))
 	}
 	return &project, nil
 }
 
+// GetProjectsByName retrieves the projects with a name, ignoring case. Names are only
+// unique within a root folder, so several projects may match.
+func GetProjectsByName(name string) ([]models.Project, error) {
+	var projects []models.Project
+	result := DB.Where("LOWER(name) = LOWER(?)", name).Order("status ASC, last_opened DESC").Find(&projects)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve projects: %w", result.Error)
+	}
+	return projects, nil
+}
+
 // UpdateProject updates an existing project
 func UpdateProject(project *models.Project) error {
-	result := DB.Save(project)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update project: %w", result.Error)
+	if err := write(func(tx *gorm.DB) error { return tx.Save(project).Error }); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
 	}
 	return nil
 }
 
-// DeleteProject soft deletes a project
+// DeleteProject soft deletes a project. Locked projects are refused with ErrProjectLocked.
 func DeleteProject(id uint) error {
-	result := DB.Delete(&models.Project{}, id)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete project: %w", result.Error)
+	err := write(func(tx *gorm.DB) error {
+		if err := lockedProjects(tx, "id = ?", id); err != nil {
+			return err
+		}
+		// Worktrees of the project stay registered as standalone projects
+		if err := tx.Model(&models.Project{}).Where("parent_id = ?", id).Update("parent_id", 0).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Project{}, id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
 	}
 	return nil
 }
 
-// UpdateLastOpened updates the LastOpened timestamp for a project
-func UpdateLastOpened(id uint) error {
-	result := DB.Model(&models.Project{}).Where("id = ?", id).Update("last_opened", time.Now())
+// PurgeProjects permanently deletes projects, so a scan can add them again, unlike
+// DeleteProject. Nothing is deleted while one of them is locked.
+func PurgeProjects(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := write(func(tx *gorm.DB) error {
+		if err := lockedProjects(tx, "id IN ?", ids); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Project{}).Where("parent_id IN ?", ids).Update("parent_id", 0).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Project{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete projects: %w", err)
+	}
+	return nil
+}
+
+// GetWorktreeProjects retrieves the projects registered as git worktrees of a project
+func GetWorktreeProjects(parentID uint) ([]models.Project, error) {
+	var projects []models.Project
+	result := DB.Where("parent_id = ?", parentID).Order("name ASC").Find(&projects)
 	if result.Error != nil {
-		return fmt.Errorf("failed to update last_opened: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve worktrees: %w", result.Error)
+	}
+	return projects, nil
+}
+
+// UpdateLastOpened updates the LastOpened timestamp for a project and counts the open
+func UpdateLastOpened(id uint) error {
+	if err := write(func(tx *gorm.DB) error { return countOpen(tx, id) }); err != nil {
+		return fmt.Errorf("failed to update last_opened: %w", err)
 	}
 	return nil
 }
 
-// DeleteAllProjects permanently deletes all projects and root folders from the database
-func DeleteAllProjects() (int, error) {
-	var count int64
+// countOpen sets LastOpened to now and increments OpenCount
+func countOpen(tx *gorm.DB, id uint) error {
+	return tx.Model(&models.Project{}).Where("id = ?", id).Updates(map[string]any{
+		"last_opened": time.Now(),
+		"open_count":  gorm.Expr("open_count + 1"),
+	}).Error
+}
+
+// OpenDebounce is how long repeated opens of a project count as one in RecordOpen
+const OpenDebounce = time.Minute
+
+// RecordOpen counts an open of a project like UpdateLastOpened, unless it was already
+// opened from DevBase within OpenDebounce. It reports whether the open was counted.
+func RecordOpen(id uint) (bool, error) {
+	counted := false
+	err := write(func(tx *gorm.DB) error {
+		var project models.Project
+		if err := tx.First(&project, id).Error; err != nil {
+			return fmt.Errorf("failed to retrieve project: %w", notFound(err, ErrProjectNotFound, id))
+		}
+		// LastOpened of a project that was never opened is the time it was added
+		if project.OpenCount > 0 && time.Since(project.LastOpened) < OpenDebounce {
+			return nil
+		}
+		if err := countOpen(tx, id); err != nil {
+			return fmt.Errorf("failed to update last_opened: %w", err)
+		}
+		counted = true
+		return nil
+	})
+	return counted, err
+}
+
+// UpdateProjectEditor sets the preferred editor command of a project (empty uses the default)
+func UpdateProjectEditor(id uint, editor string) error {
+	err := write(func(tx *gorm.DB) error {
+		return tx.Model(&models.Project{}).Where("id = ?", id).Update("editor", editor).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update project editor: %w", err)
+	}
+	return nil
+}
+
+// UpdateProjectIcon sets the emoji or label shown before a project's name (empty for none)
+func UpdateProjectIcon(id uint, icon string) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Update("icon", icon)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update icon: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update icon: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
 
-	// Count projects before deletion
-	if err := DB.Model(&models.Project{}).Count(&count).Error; err != nil {
-		return 0, fmt.Errorf("failed to count projects: %w", err)
+// UpdateProjectLastError keeps why the last archive or restore of a project failed, with the
+// time it failed. An empty message clears it.
+func UpdateProjectLastError(id uint, message string) error {
+	var failedAt time.Time
+	if message != "" {
+		failedAt = time.Now()
+	}
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).
+			Updates(map[string]any{"last_error": message, "last_error_at": failedAt})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update last error: %w", err)
 	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update last error: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
 
-	// Delete all projects (includes soft-deleted records)
-	result := DB.Unscoped().Where("1 = 1").Delete(&models.Project{})
+// UpdateProjectLocation moves a project's record to another path and root folder (0 for
+// none), e.g. when it is restored somewhere else than it was archived from
+func UpdateProjectLocation(id uint, path string, rootFolderID uint) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).
+			Updates(map[string]any{"path": path, "root_folder_id": rootFolderID})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update project location: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update project location: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// UpdateProjectCloneOptions sets the clone options a project is restored with (empty for the
+// global ones)
+func UpdateProjectCloneOptions(id uint, options string) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Update("clone_options", options)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update clone options: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update clone options: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// UpdateProjectRestoreRef sets the branch or tag a project is restored at (empty for the
+// default branch)
+func UpdateProjectRestoreRef(id uint, ref string) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Update("restore_ref", ref)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update restore ref: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update restore ref: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// SeedProjectUsage raises a project's LastOpened and OpenCount to the given values when they
+// are higher, so imported usage data never hides more recent DevBase activity
+func SeedProjectUsage(id uint, lastOpened time.Time, openCount int) error {
+	return write(func(tx *gorm.DB) error {
+		var project models.Project
+		if err := tx.First(&project, id).Error; err != nil {
+			return fmt.Errorf("failed to retrieve project: %w", notFound(err, ErrProjectNotFound, id))
+		}
+
+		updates := map[string]any{}
+		if lastOpened.After(project.LastOpened) {
+			updates["last_opened"] = lastOpened
+		}
+		if openCount > project.OpenCount {
+			updates["open_count"] = openCount
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Project{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to seed project usage: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateProjectNotes replaces the Markdown notes of a project
+func UpdateProjectNotes(id uint, notes string) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Update("notes", notes)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notes: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update notes: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// SetProjectPinned pins or unpins a project, setting the command its terminal profile runs
+func SetProjectPinned(id uint, pinned bool, startCommand string) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Updates(map[string]any{"pinned": pinned, "start_command": startCommand})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update pin: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update pin: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// SetProjectLocked locks or unlocks a project. Locked projects can't be archived, removed
+// or deleted by clearing all projects, removing their root folder or remote host.
+func SetProjectLocked(id uint, locked bool) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Update("locked", locked)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update lock: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update lock: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// SetProjectNoReclaim opts a project out of, or back into, deleting its dependency folders
+// to reclaim space
+func SetProjectNoReclaim(id uint, noReclaim bool) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).Where("id = ?", id).Update("no_reclaim", noReclaim)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update reclaim opt-out: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to update reclaim opt-out: %w: %d", ErrProjectNotFound, id)
+	}
+	return nil
+}
+
+// GetActiveProjects retrieves the active projects of every root folder, sorted by name
+func GetActiveProjects() ([]models.Project, error) {
+	var projects []models.Project
+	result := DB.Where("status = ?", "active").Order("name ASC").Find(&projects)
 	if result.Error != nil {
-		return 0, fmt.Errorf("failed to delete all projects: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve projects: %w", result.Error)
+	}
+	return projects, nil
+}
+
+// GetPinnedProjects retrieves the pinned active projects, sorted by name
+func GetPinnedProjects() ([]models.Project, error) {
+	var projects []models.Project
+	result := DB.Where("pinned = ? AND status = ?", true, "active").Order("name ASC").Find(&projects)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve pinned projects: %w", result.Error)
+	}
+	return projects, nil
+}
+
+// NormalizeTag lowercases a tag and replaces whitespace with dashes
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// GetAllTags returns every distinct tag used by projects, sorted alphabetically
+func GetAllTags() ([]string, error) {
+	var projects []models.Project
+	if err := DB.Select("tags").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve tags: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, p := range projects {
+		for _, tag := range p.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
 	}
+	sort.Strings(tags)
+	return tags, nil
+}
 
-	// Delete all root folders as well
-	if err := DB.Unscoped().Where("1 = 1").Delete(&models.RootFolder{}).Error; err != nil {
-		return 0, fmt.Errorf("failed to delete all root folders: %w", err)
+// AddProjectTag adds a tag to a project, ignoring tags it already has
+func AddProjectTag(id uint, tag string) error {
+	tag = NormalizeTag(tag)
+	if tag == "" {
+		return fmt.Errorf("tag cannot be empty")
 	}
 
+	return updateProjectTags(id, func(tags []string) []string {
+		if slices.Contains(tags, tag) {
+			return tags
+		}
+		return append(tags, tag)
+	})
+}
+
+// RemoveProjectTag removes a tag from a project
+func RemoveProjectTag(id uint, tag string) error {
+	return updateProjectTags(id, func(tags []string) []string {
+		return slices.DeleteFunc(tags, func(t string) bool { return t == tag })
+	})
+}
+
+// updateProjectTags replaces the tags of a project with change applied to them
+func updateProjectTags(id uint, change func(tags []string) []string) error {
+	return write(func(tx *gorm.DB) error {
+		var project models.Project
+		if err := tx.First(&project, id).Error; err != nil {
+			return fmt.Errorf("failed to retrieve project: %w", notFound(err, ErrProjectNotFound, id))
+		}
+		tags := change(project.Tags)
+		if tags == nil {
+			tags = []string{}
+		}
+		if err := tx.Model(&models.Project{ID: id}).Select("tags").Updates(models.Project{Tags: tags}).Error; err != nil {
+			return fmt.Errorf("failed to update tags: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteAllProjects permanently deletes all projects and root folders from the database.
+// Nothing is deleted while a project is locked; the ErrProjectLocked names them.
+func DeleteAllProjects() (int, error) {
+	var count int64
+	err := write(func(tx *gorm.DB) error {
+		if err := lockedProjects(tx, "1 = 1"); err != nil {
+			return err
+		}
+
+		// Count projects before deletion
+		if err := tx.Model(&models.Project{}).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count projects: %w", err)
+		}
+
+		// Delete all projects (includes soft-deleted records)
+		if err := tx.Unscoped().Where("1 = 1").Delete(&models.Project{}).Error; err != nil {
+			return fmt.Errorf("failed to delete all projects: %w", err)
+		}
+
+		// Delete all root folders as well
+		if err := tx.Unscoped().Where("1 = 1").Delete(&models.RootFolder{}).Error; err != nil {
+			return fmt.Errorf("failed to delete all root folders: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
 	return int(count), nil
 }
 
-// CloseDB closes the database connection
+// ClearSummary is what clearing the projects of a root folder, or of all of them, removes
+type ClearSummary struct {
+	Active   int64
+	Archived int64
+	Locked   []string // Names of locked projects, which make clearing fail
+}
+
+// SummarizeClear counts the projects ClearRootFolderProjects (or DeleteAllProjects for
+// rootFolderID 0) would delete, without deleting anything
+func SummarizeClear(rootFolderID uint) (ClearSummary, error) {
+	var summary ClearSummary
+	scope := func() *gorm.DB {
+		query := DB.Model(&models.Project{})
+		if rootFolderID != 0 {
+			query = query.Where("root_folder_id = ?", rootFolderID)
+		}
+		return query
+	}
+	if err := scope().Where("status = ?", "archived").Count(&summary.Archived).Error; err != nil {
+		return ClearSummary{}, fmt.Errorf("failed to count projects: %w", err)
+	}
+	if err := scope().Where("status <> ?", "archived").Count(&summary.Active).Error; err != nil {
+		return ClearSummary{}, fmt.Errorf("failed to count projects: %w", err)
+	}
+	if err := scope().Where("locked = ?", true).Order("name").Pluck("name", &summary.Locked).Error; err != nil {
+		return ClearSummary{}, fmt.Errorf("failed to look up locked projects: %w", err)
+	}
+	return summary, nil
+}
+
+// ClearRootFolderProjects permanently deletes the projects of a root folder, keeping the
+// root folder, so a scan can add them again. Nothing is deleted while one of them is
+// locked; the ErrProjectLocked names them.
+func ClearRootFolderProjects(rootFolderID uint) (int, error) {
+	var count int64
+	err := write(func(tx *gorm.DB) error {
+		if err := lockedProjects(tx, "root_folder_id = ?", rootFolderID); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Project{}).Where("root_folder_id = ?", rootFolderID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count projects: %w", err)
+		}
+		// Soft-deleted projects go too, so they can be added again
+		if err := tx.Unscoped().Where("root_folder_id = ?", rootFolderID).Delete(&models.Project{}).Error; err != nil {
+			return fmt.Errorf("failed to delete projects: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// CloseDB checkpoints the write-ahead log into the database file and closes the
+// connection, so a stopped DevBase leaves no pending writes in devbase.db-wal. A write
+// that is running finishes first.
 func CloseDB() error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	return closeDB()
+}
+
+// closeDB closes the database for CloseDB and SwitchDB, which hold writeMu
+func closeDB() error {
+	if DB == nil {
+		return nil
+	}
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
 	}
+	// A failed checkpoint loses nothing, the log is applied on the next open
+	if err := DB.Exec("PRAGMA wal_checkpoint(TRUNCATE);").Error; err != nil {
+		slog.Warn("Failed to checkpoint database", "err", err)
+	}
 	return sqlDB.Close()
 }
 
-// GetConfig retrieves a configuration value by key
+// Ping checks that the database connection is usable
+func Ping() error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+	return sqlDB.Ping()
+}
+
+// GetConfig retrieves a configuration value by key. Values set in config.toml take
+// precedence over the Config table.
 func GetConfig(key string) (string, error) {
+	if value, ok := fileConfigValue(key); ok {
+		return value, nil
+	}
+
 	var config models.Config
 	result := DB.Where("key = ?", key).First(&config)
 	if result.Error != nil {
@@ -205,20 +732,55 @@ func GetConfig(key string) (string, error) {
 	return config.Value, nil
 }
 
+// MachineName identifies this machine in the projects it registers: the "machine_name"
+// config key, or the hostname
+func MachineName() string {
+	if name, _ := GetConfig("machine_name"); strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// GetAllConfig returns every configuration value, config.toml values taking precedence
+// over the Config table like in GetConfig
+func GetAllConfig() (map[string]string, error) {
+	var configs []models.Config
+	if err := DB.Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve config: %w", err)
+	}
+	values := make(map[string]string, len(configs))
+	for _, config := range configs {
+		values[config.Key] = config.Value
+	}
+
+	fileConfig.RLock()
+	defer fileConfig.RUnlock()
+	for key, value := range fileConfig.values {
+		values[key] = value
+	}
+	return values, nil
+}
+
 // SetConfig sets a configuration value
 func SetConfig(key, value string) error {
-	var config models.Config
-	result := DB.Where("key = ?", key).First(&config)
+	return write(func(tx *gorm.DB) error {
+		var config models.Config
+		result := tx.Where("key = ?", key).First(&config)
 
-	if result.Error == nil {
-		// Update existing
-		config.Value = value
-		return DB.Save(&config).Error
-	}
+		if result.Error == nil {
+			// Update existing
+			config.Value = value
+			return tx.Save(&config).Error
+		}
 
-	// Create new
-	config = models.Config{Key: key, Value: value}
-	return DB.Create(&config).Error
+		// Create new
+		config = models.Config{Key: key, Value: value}
+		return tx.Create(&config).Error
+	})
 }
 
 // ========== RootFolder Management Functions ==========
@@ -238,7 +800,7 @@ func GetActiveRootFolder() (*models.RootFolder, error) {
 	var rootFolder models.RootFolder
 	result := DB.Where("is_active = ?", true).First(&rootFolder)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to retrieve active root folder: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve active root folder: %w", notFound(result.Error, ErrRootFolderNotFound, "none is active"))
 	}
 	return &rootFolder, nil
 }
@@ -248,7 +810,7 @@ func GetRootFolderByID(id uint) (*models.RootFolder, error) {
 	var rootFolder models.RootFolder
 	result := DB.First(&rootFolder, id)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to retrieve root folder: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve root folder: %w", notFound(result.Error, ErrRootFolderNotFound, id))
 	}
 	return &rootFolder, nil
 }
@@ -258,32 +820,62 @@ func GetRootFolderByPath(path string) (*models.RootFolder, error) {
 	var rootFolder models.RootFolder
 	result := DB.Where("path = ?", path).First(&rootFolder)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to retrieve root folder: %w", result.Error)
+		return nil, fmt.Errorf("failed to retrieve root folder: %w", notFound(result.Error, ErrRootFolderNotFound, path))
 	}
 	return &rootFolder, nil
 }
 
+// GetRootFolderForPath returns the root folder containing path, preferring the most specific
+// one when root folders are nested. It returns nil when no root folder contains the path.
+func GetRootFolderForPath(path string) (*models.RootFolder, error) {
+	rootFolders, err := GetAllRootFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.RootFolder
+	for i, rootFolder := range rootFolders {
+		rel, err := filepath.Rel(rootFolder.Path, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if best == nil || len(rootFolder.Path) > len(best.Path) {
+			best = &rootFolders[i]
+		}
+	}
+	return best, nil
+}
+
+// insideAny reports whether path is one of dirs or inside one of them
+func insideAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 // AddRootFolder adds a new root folder to the database
 func AddRootFolder(rootFolder *models.RootFolder) error {
-	result := DB.Create(rootFolder)
-	if result.Error != nil {
-		return fmt.Errorf("failed to add root folder: %w", result.Error)
+	if err := write(func(tx *gorm.DB) error { return tx.Create(rootFolder).Error }); err != nil {
+		return fmt.Errorf("failed to add root folder: %w", err)
 	}
 	return nil
 }
 
 // UpdateRootFolder updates an existing root folder
 func UpdateRootFolder(rootFolder *models.RootFolder) error {
-	result := DB.Save(rootFolder)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update root folder: %w", result.Error)
+	if err := write(func(tx *gorm.DB) error { return tx.Save(rootFolder).Error }); err != nil {
+		return fmt.Errorf("failed to update root folder: %w", err)
 	}
 	return nil
 }
 
 // SetActiveRootFolder sets a root folder as active and deactivates all others
 func SetActiveRootFolder(id uint) error {
-	return DB.Transaction(func(tx *gorm.DB) error {
+	return write(func(tx *gorm.DB) error {
 		// Deactivate all root folders
 		if err := tx.Model(&models.RootFolder{}).Where("1 = 1").Update("is_active", false).Error; err != nil {
 			return fmt.Errorf("failed to deactivate root folders: %w", err)
@@ -298,9 +890,14 @@ func SetActiveRootFolder(id uint) error {
 	})
 }
 
-// DeleteRootFolder deletes a root folder and all its associated projects
+// DeleteRootFolder deletes a root folder and all its associated projects, unless one of
+// them is locked
 func DeleteRootFolder(id uint) error {
-	return DB.Transaction(func(tx *gorm.DB) error {
+	return write(func(tx *gorm.DB) error {
+		if err := lockedProjects(tx, "root_folder_id = ?", id); err != nil {
+			return err
+		}
+
 		// Delete all projects in this root folder (hard delete to allow re-adding)
 		if err := tx.Unscoped().Where("root_folder_id = ?", id).Delete(&models.Project{}).Error; err != nil {
 			return fmt.Errorf("failed to delete projects: %w", err)
@@ -315,6 +912,61 @@ func DeleteRootFolder(id uint) error {
 	})
 }
 
+// CountProjectsByRootFolder returns the number of projects in each root folder keyed by root folder ID
+func CountProjectsByRootFolder() (map[uint]int64, error) {
+	var rows []struct {
+		RootFolderID uint
+		Count        int64
+	}
+	result := DB.Model(&models.Project{}).Select("root_folder_id, COUNT(*) AS count").Group("root_folder_id").Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count projects: %w", result.Error)
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.RootFolderID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountProjectsByStatus returns the number of projects in every root folder keyed by status
+func CountProjectsByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	result := DB.Model(&models.Project{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count projects: %w", result.Error)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// CountProjectsByLanguage returns the number of active projects in every root folder keyed
+// by detected language, "" for none
+func CountProjectsByLanguage() (map[string]int64, error) {
+	var rows []struct {
+		Language string
+		Count    int64
+	}
+	result := DB.Model(&models.Project{}).Select("language, COUNT(*) AS count").Where("status = ?", "active").Group("language").Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count projects: %w", result.Error)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Language] = row.Count
+	}
+	return counts, nil
+}
+
 // GetProjectsByRootFolder retrieves all projects for a specific root folder
 func GetProjectsByRootFolder(rootFolderID uint) ([]models.Project, error) {
 	var projects []models.Project
@@ -324,3 +976,417 @@ func GetProjectsByRootFolder(rootFolderID uint) ([]models.Project, error) {
 	}
 	return projects, nil
 }
+
+// reconcileBatchSize is the number of rows written per statement when storing a scan
+const reconcileBatchSize = 100
+
+// ReconcileResult counts the changes ReconcileProjects made
+type ReconcileResult struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// ReconcileProjects stores a scan of a root folder in one transaction: scanned projects
+// that aren't stored yet are added, changed repository URLs, languages and dev container
+// flags are updated, and active local projects that weren't found are removed unless they
+// are locked or at or under one of the unscanned directories, which the scan didn't look
+// into. Either all of it is stored or, when a statement fails, none of it.
+func ReconcileProjects(rootFolderID uint, scanned []models.Project, unscanned []string) (ReconcileResult, error) {
+	var result ReconcileResult
+	machine := MachineName()
+	err := write(func(tx *gorm.DB) error {
+		// A retried attempt starts counting again
+		result = ReconcileResult{}
+
+		var existing []models.Project
+		if err := tx.Where("root_folder_id = ?", rootFolderID).Find(&existing).Error; err != nil {
+			return fmt.Errorf("failed to retrieve projects: %w", err)
+		}
+		existingByPath := make(map[string]models.Project, len(existing))
+		for _, p := range existing {
+			if p.RemoteHostID == 0 {
+				existingByPath[p.Path] = p
+			}
+		}
+
+		scannedPaths := make(map[string]bool, len(scanned))
+		var added []models.Project
+		for _, p := range scanned {
+			if scannedPaths[p.Path] {
+				continue
+			}
+			scannedPaths[p.Path] = true
+
+			current, ok := existingByPath[p.Path]
+			if !ok {
+				p.ID = 0
+				p.RootFolderID = rootFolderID
+				if p.LastOpened.IsZero() {
+					p.LastOpened = time.Now()
+				}
+				if p.Status == "" {
+					p.Status = "active"
+				}
+				p.Machine = machine
+				added = append(added, p)
+				continue
+			}
+
+			// Projects registered before machines were recorded are found on this one
+			changed := current.RepoURL != p.RepoURL || current.Language != p.Language || current.DevContainer != p.DevContainer
+			if !changed && current.Machine != "" {
+				continue
+			}
+			if current.Machine == "" {
+				current.Machine = machine
+			}
+			if err := tx.Model(&models.Project{}).Where("id = ?", current.ID).Updates(map[string]any{
+				"repo_url":      p.RepoURL,
+				"language":      p.Language,
+				"dev_container": p.DevContainer,
+				"machine":       current.Machine,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update project %s: %w", current.Name, err)
+			}
+			if changed {
+				result.Updated++
+			}
+		}
+
+		// Vanished projects are deleted for good so they can be added again if they come back.
+		// Locked ones stay and show as missing, and unscanned ones weren't looked for.
+		var removed []uint
+		for _, p := range existingByPath {
+			if p.Status == "active" && !scannedPaths[p.Path] && !p.Locked && !insideAny(p.Path, unscanned) {
+				removed = append(removed, p.ID)
+			}
+		}
+		for ids := range slices.Chunk(removed, reconcileBatchSize) {
+			if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Project{}).Error; err != nil {
+				return fmt.Errorf("failed to remove projects: %w", err)
+			}
+		}
+		result.Removed = len(removed)
+
+		// Projects removed by hand keep their row (soft delete), which blocks adding them again
+		if len(added) > 0 {
+			insert := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&added, reconcileBatchSize)
+			if insert.Error != nil {
+				return fmt.Errorf("failed to add projects: %w", insert.Error)
+			}
+			result.Added = int(insert.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+	return result, nil
+}
+
+// GetSessions retrieves all saved sessions sorted by name
+func GetSessions() ([]models.Session, error) {
+	var sessions []models.Session
+	result := DB.Order("name ASC").Find(&sessions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve sessions: %w", result.Error)
+	}
+	return sessions, nil
+}
+
+// SaveSession creates a session or replaces the projects of an existing one with the same name
+func SaveSession(name string, projectIDs []uint) (*models.Session, error) {
+	if name == "" {
+		return nil, fmt.Errorf("session name cannot be empty")
+	}
+	if len(projectIDs) == 0 {
+		return nil, fmt.Errorf("session must contain at least one project")
+	}
+
+	var session models.Session
+	err := write(func(tx *gorm.DB) error {
+		session = models.Session{}
+		result := tx.Where("name = ?", name).First(&session)
+		if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to retrieve session: %w", result.Error)
+		}
+
+		session.Name = name
+		session.ProjectIDs = projectIDs
+		if err := tx.Save(&session).Error; err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// DeleteSession deletes a saved session
+func DeleteSession(id uint) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Delete(&models.Session{}, id).Error }); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// LogActivity records an event in the activity history.
+// projectID is 0 for events not tied to a single project, such as scans and syncs.
+func LogActivity(kind string, projectID uint, detail string) error {
+	err := write(func(tx *gorm.DB) error {
+		activity := models.Activity{Kind: kind, ProjectID: projectID, Detail: detail}
+		if projectID != 0 {
+			var project models.Project
+			if err := tx.Unscoped().Select("name").First(&project, projectID).Error; err == nil {
+				activity.ProjectName = project.Name
+			}
+		}
+		return tx.Create(&activity).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log activity: %w", err)
+	}
+	return nil
+}
+
+// GetActivities retrieves the most recent activities first, up to limit (0 for all).
+// A non-zero projectID limits the history to that project.
+func GetActivities(projectID uint, limit int) ([]models.Activity, error) {
+	query := DB.Order("created_at DESC, id DESC")
+	if projectID != 0 {
+		query = query.Where("project_id = ?", projectID)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var activities []models.Activity
+	if err := query.Find(&activities).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve activities: %w", err)
+	}
+	return activities, nil
+}
+
+// LastActivity returns the most recent activity of a kind, nil when there is none
+func LastActivity(kind string) (*models.Activity, error) {
+	var activities []models.Activity
+	if err := DB.Where("kind = ?", kind).Order("created_at DESC, id DESC").Limit(1).Find(&activities).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve activities: %w", err)
+	}
+	if len(activities) == 0 {
+		return nil, nil
+	}
+	return &activities[0], nil
+}
+
+// CountActivitiesSince returns the number of activities recorded after since, keyed by kind
+func CountActivitiesSince(since time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Kind  string
+		Count int64
+	}
+	result := DB.Model(&models.Activity{}).Select("kind, COUNT(*) AS count").Where("created_at > ?", since).Group("kind").Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count activities: %w", result.Error)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Kind] = row.Count
+	}
+	return counts, nil
+}
+
+// ========== RemoteHost Management Functions ==========
+
+// GetRemoteHosts retrieves all remote hosts sorted by name
+func GetRemoteHosts() ([]models.RemoteHost, error) {
+	var hosts []models.RemoteHost
+	result := DB.Order("name ASC").Find(&hosts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve remote hosts: %w", result.Error)
+	}
+	return hosts, nil
+}
+
+// GetRemoteHostByID retrieves a remote host by its ID
+func GetRemoteHostByID(id uint) (*models.RemoteHost, error) {
+	var host models.RemoteHost
+	result := DB.First(&host, id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve remote host: %w", notFound(result.Error, ErrRemoteHostNotFound, id))
+	}
+	return &host, nil
+}
+
+// GetRemoteHostByName retrieves a remote host by its name
+func GetRemoteHostByName(name string) (*models.RemoteHost, error) {
+	var host models.RemoteHost
+	result := DB.Where("name = ?", name).First(&host)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve remote host: %w", notFound(result.Error, ErrRemoteHostNotFound, name))
+	}
+	return &host, nil
+}
+
+// AddRemoteHost adds a new remote host
+func AddRemoteHost(host *models.RemoteHost) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Create(host).Error }); err != nil {
+		return fmt.Errorf("failed to add remote host: %w", err)
+	}
+	return nil
+}
+
+// DeleteRemoteHost deletes a remote host and the project entries on it (remote files are
+// untouched), unless one of them is locked
+func DeleteRemoteHost(id uint) error {
+	return write(func(tx *gorm.DB) error {
+		if err := lockedProjects(tx, "remote_host_id = ?", id); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("remote_host_id = ?", id).Delete(&models.Project{}).Error; err != nil {
+			return fmt.Errorf("failed to delete remote projects: %w", err)
+		}
+		if err := tx.Delete(&models.RemoteHost{}, id).Error; err != nil {
+			return fmt.Errorf("failed to delete remote host: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRepoMetadata retrieves the cached details of a repository, or nil if none are cached
+func GetRepoMetadata(repo string) (*models.RepoMetadata, error) {
+	var meta models.RepoMetadata
+	result := DB.Where("repo = ?", repo).Limit(1).Find(&meta)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve repository details: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &meta, nil
+}
+
+// SaveRepoMetadata stores the details of a repository, replacing any cached ones
+func SaveRepoMetadata(meta *models.RepoMetadata) error {
+	return write(func(tx *gorm.DB) error {
+		var existing models.RepoMetadata
+		result := tx.Where("repo = ?", meta.Repo).Limit(1).Find(&existing)
+		if result.Error != nil {
+			return fmt.Errorf("failed to retrieve repository details: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			meta.ID = existing.ID
+		}
+		if err := tx.Save(meta).Error; err != nil {
+			return fmt.Errorf("failed to save repository details: %w", err)
+		}
+		return nil
+	})
+}
+
+// ========== Webhook Management Functions ==========
+
+// GetWebhooks retrieves all webhooks in the order they were added
+func GetWebhooks() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := DB.Order("id ASC").Find(&webhooks)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", result.Error)
+	}
+	return webhooks, nil
+}
+
+// AddWebhook adds a new webhook
+func AddWebhook(webhook *models.Webhook) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Create(webhook).Error }); err != nil {
+		return fmt.Errorf("failed to add webhook: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes a webhook by its ID
+func DeleteWebhook(id uint) error {
+	var affected int64
+	err := write(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Webhook{}, id)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %d", ErrWebhookNotFound, id)
+	}
+	return nil
+}
+
+// ========== Dev Run Functions ==========
+
+// GetDevRuns retrieves the recorded dev runs of all projects, newest first
+func GetDevRuns() ([]models.DevRun, error) {
+	var runs []models.DevRun
+	if err := DB.Order("started_at DESC, id DESC").Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve dev runs: %w", err)
+	}
+	return runs, nil
+}
+
+// AddDevRun records a dev run that started
+func AddDevRun(run *models.DevRun) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Create(run).Error }); err != nil {
+		return fmt.Errorf("failed to record dev run: %w", err)
+	}
+	return nil
+}
+
+// SetDevRunPort records the port a dev run listens on
+func SetDevRunPort(id uint, port int) error {
+	err := write(func(tx *gorm.DB) error {
+		return tx.Model(&models.DevRun{}).Where("id = ?", id).Update("port", port).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save dev run port: %w", err)
+	}
+	return nil
+}
+
+// DeleteDevRun forgets a dev run that ended; unknown IDs are ignored
+func DeleteDevRun(id uint) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Delete(&models.DevRun{}, id).Error }); err != nil {
+		return fmt.Errorf("failed to delete dev run: %w", err)
+	}
+	return nil
+}
+
+// ========== Trash Functions ==========
+
+// GetTrashEntries retrieves the archived checkouts in the trash of all projects, newest first
+func GetTrashEntries() ([]models.TrashEntry, error) {
+	var entries []models.TrashEntry
+	if err := DB.Order("created_at DESC, id DESC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve trash: %w", err)
+	}
+	return entries, nil
+}
+
+// AddTrashEntry records an archived checkout moved into the trash
+func AddTrashEntry(entry *models.TrashEntry) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Create(entry).Error }); err != nil {
+		return fmt.Errorf("failed to record trash entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteTrashEntry forgets an archived checkout that was purged or restored; unknown IDs
+// are ignored
+func DeleteTrashEntry(id uint) error {
+	if err := write(func(tx *gorm.DB) error { return tx.Delete(&models.TrashEntry{}, id).Error }); err != nil {
+		return fmt.Errorf("failed to delete trash entry: %w", err)
+	}
+	return nil
+}
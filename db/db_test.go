@@ -1,15 +1,21 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"devbase/models"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
 // setupTestDB initializes a test database in a temporary location
-func setupTestDB(t *testing.T) string {
+func setupTestDB(t testing.TB) string {
 	// Create a temporary database file
 	tempDir := t.TempDir()
 	dbPath := filepath.Join(tempDir, "test.db")
@@ -24,7 +30,7 @@ func setupTestDB(t *testing.T) string {
 }
 
 // teardownTestDB closes the database connection
-func teardownTestDB(t *testing.T) {
+func teardownTestDB(t testing.TB) {
 	if err := CloseDB(); err != nil {
 		t.Errorf("Failed to close test database: %v", err)
 	}
@@ -269,6 +275,16 @@ func TestProjectWithRootFolder(t *testing.T) {
 		t.Errorf("Expected 2 projects from active root folder, got %d", len(allProjects))
 	}
 
+	// Count projects per root folder
+	counts, err := CountProjectsByRootFolder()
+	if err != nil {
+		t.Fatalf("CountProjectsByRootFolder failed: %v", err)
+	}
+
+	if counts[rootFolder.ID] != 2 || counts[rootFolder2.ID] != 1 {
+		t.Errorf("Expected counts 2 and 1, got %d and %d", counts[rootFolder.ID], counts[rootFolder2.ID])
+	}
+
 	// Switch active root folder
 	err = SetActiveRootFolder(rootFolder2.ID)
 	if err != nil {
@@ -312,6 +328,1261 @@ func TestProjectWithRootFolder(t *testing.T) {
 	}
 }
 
+// TestGetProjectsByStatus tests filtering projects by status
+func TestGetProjectsByStatus(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	rootFolder := &models.RootFolder{
+		Name:     "Test Projects",
+		Path:     "/path/to/projects",
+		IsActive: true,
+	}
+	if err := AddRootFolder(rootFolder); err != nil {
+		t.Fatalf("AddRootFolder failed: %v", err)
+	}
+
+	statuses := []string{"active", "active", "archived"}
+	for i, status := range statuses {
+		project := &models.Project{
+			Name:         "Project " + string(rune('A'+i)),
+			Path:         filepath.Join("/path/to/projects", string(rune('a'+i))),
+			Status:       status,
+			RootFolderID: rootFolder.ID,
+		}
+		if err := AddProject(project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	active, err := GetProjectsByStatus("active")
+	if err != nil {
+		t.Fatalf("GetProjectsByStatus(active) failed: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("Expected 2 active projects, got %d", len(active))
+	}
+
+	archived, err := GetProjectsByStatus("archived")
+	if err != nil {
+		t.Fatalf("GetProjectsByStatus(archived) failed: %v", err)
+	}
+	if len(archived) != 1 {
+		t.Errorf("Expected 1 archived project, got %d", len(archived))
+	}
+
+	all, err := GetProjectsByStatus("")
+	if err != nil {
+		t.Fatalf("GetProjectsByStatus(all) failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected 3 projects, got %d", len(all))
+	}
+}
+
+// TestUpdateProjectNotes tests saving and clearing project notes
+func TestUpdateProjectNotes(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	project := &models.Project{
+		Name: "Notes Project",
+		Path: "/path/to/notes-project",
+	}
+	if err := AddProject(project); err != nil {
+		t.Fatalf("AddProject failed: %v", err)
+	}
+
+	notes := "# Todo\n- fix login"
+	if err := UpdateProjectNotes(project.ID, notes); err != nil {
+		t.Fatalf("UpdateProjectNotes failed: %v", err)
+	}
+
+	retrieved, err := GetProjectByID(project.ID)
+	if err != nil {
+		t.Fatalf("GetProjectByID failed: %v", err)
+	}
+	if retrieved.Notes != notes {
+		t.Errorf("Expected notes %q, got %q", notes, retrieved.Notes)
+	}
+
+	// Clearing notes must persist the empty value
+	if err := UpdateProjectNotes(project.ID, ""); err != nil {
+		t.Fatalf("UpdateProjectNotes (clear) failed: %v", err)
+	}
+	retrieved, err = GetProjectByID(project.ID)
+	if err != nil {
+		t.Fatalf("GetProjectByID failed: %v", err)
+	}
+	if retrieved.Notes != "" {
+		t.Errorf("Expected empty notes, got %q", retrieved.Notes)
+	}
+
+	if err := UpdateProjectNotes(9999, notes); err == nil {
+		t.Error("Expected error updating notes of a missing project")
+	}
+}
+
+// TestProjectTags tests adding, listing and removing project tags
+func TestProjectTags(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	projectA := &models.Project{Name: "Project A", Path: "/path/to/a"}
+	projectB := &models.Project{Name: "Project B", Path: "/path/to/b"}
+	for _, p := range []*models.Project{projectA, projectB} {
+		if err := AddProject(p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	if err := AddProjectTag(projectA.ID, " Web App "); err != nil {
+		t.Fatalf("AddProjectTag failed: %v", err)
+	}
+	if err := AddProjectTag(projectA.ID, "go"); err != nil {
+		t.Fatalf("AddProjectTag failed: %v", err)
+	}
+	// Duplicate tags are ignored
+	if err := AddProjectTag(projectA.ID, "GO"); err != nil {
+		t.Fatalf("AddProjectTag (duplicate) failed: %v", err)
+	}
+	if err := AddProjectTag(projectB.ID, "api"); err != nil {
+		t.Fatalf("AddProjectTag failed: %v", err)
+	}
+	if err := AddProjectTag(projectB.ID, "  "); err == nil {
+		t.Error("Expected error adding an empty tag")
+	}
+
+	retrieved, err := GetProjectByID(projectA.ID)
+	if err != nil {
+		t.Fatalf("GetProjectByID failed: %v", err)
+	}
+	if len(retrieved.Tags) != 2 || retrieved.Tags[0] != "web-app" || retrieved.Tags[1] != "go" {
+		t.Errorf("Expected tags [web-app go], got %v", retrieved.Tags)
+	}
+
+	tags, err := GetAllTags()
+	if err != nil {
+		t.Fatalf("GetAllTags failed: %v", err)
+	}
+	if len(tags) != 3 || tags[0] != "api" || tags[1] != "go" || tags[2] != "web-app" {
+		t.Errorf("Expected tags [api go web-app], got %v", tags)
+	}
+
+	if err := RemoveProjectTag(projectA.ID, "web-app"); err != nil {
+		t.Fatalf("RemoveProjectTag failed: %v", err)
+	}
+	if err := RemoveProjectTag(projectA.ID, "go"); err != nil {
+		t.Fatalf("RemoveProjectTag failed: %v", err)
+	}
+	retrieved, err = GetProjectByID(projectA.ID)
+	if err != nil {
+		t.Fatalf("GetProjectByID failed: %v", err)
+	}
+	if len(retrieved.Tags) != 0 {
+		t.Errorf("Expected no tags after removal, got %v", retrieved.Tags)
+	}
+}
+
+// TestSessionCRUD tests saving, replacing, listing and deleting sessions
+func TestSessionCRUD(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	session, err := SaveSession("frontend", []uint{1, 2})
+	if err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if session.ID == 0 {
+		t.Error("Session ID should be set after saving")
+	}
+
+	// Saving with the same name replaces the projects
+	replaced, err := SaveSession("frontend", []uint{3})
+	if err != nil {
+		t.Fatalf("SaveSession (replace) failed: %v", err)
+	}
+	if replaced.ID != session.ID {
+		t.Errorf("Expected session %d to be replaced, got new session %d", session.ID, replaced.ID)
+	}
+
+	if _, err := SaveSession("api", []uint{4, 5}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	sessions, err := GetSessions()
+	if err != nil {
+		t.Fatalf("GetSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].Name != "api" || sessions[1].Name != "frontend" {
+		t.Errorf("Expected sessions sorted by name, got %s, %s", sessions[0].Name, sessions[1].Name)
+	}
+	if len(sessions[1].ProjectIDs) != 1 || sessions[1].ProjectIDs[0] != 3 {
+		t.Errorf("Expected frontend session projects [3], got %v", sessions[1].ProjectIDs)
+	}
+
+	if _, err := SaveSession("", []uint{1}); err == nil {
+		t.Error("Expected error saving a session without a name")
+	}
+	if _, err := SaveSession("empty", nil); err == nil {
+		t.Error("Expected error saving a session without projects")
+	}
+
+	if err := DeleteSession(session.ID); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	sessions, err = GetSessions()
+	if err != nil {
+		t.Fatalf("GetSessions failed: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("Expected 1 session after delete, got %d", len(sessions))
+	}
+}
+
+// TestActivityLog tests recording and retrieving the activity history
+func TestActivityLog(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	project := &models.Project{Name: "webapp", Path: "/path/to/webapp", Status: "active", LastOpened: time.Now()}
+	if err := AddProject(project); err != nil {
+		t.Fatalf("AddProject failed: %v", err)
+	}
+
+	if err := LogActivity(models.ActivityScan, 0, "found 3"); err != nil {
+		t.Fatalf("LogActivity failed: %v", err)
+	}
+	if err := LogActivity(models.ActivityOpen, project.ID, "VS Code"); err != nil {
+		t.Fatalf("LogActivity failed: %v", err)
+	}
+	if err := LogActivity(models.ActivityArchive, project.ID, ""); err != nil {
+		t.Fatalf("LogActivity failed: %v", err)
+	}
+
+	activities, err := GetActivities(0, 0)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	if len(activities) != 3 {
+		t.Fatalf("Expected 3 activities, got %d", len(activities))
+	}
+	if activities[0].Kind != models.ActivityArchive {
+		t.Errorf("Expected newest activity first, got %s", activities[0].Kind)
+	}
+	if activities[0].ProjectName != "webapp" {
+		t.Errorf("Expected project name to be recorded, got %q", activities[0].ProjectName)
+	}
+
+	activities, err = GetActivities(project.ID, 0)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	if len(activities) != 2 {
+		t.Errorf("Expected 2 activities for the project, got %d", len(activities))
+	}
+
+	activities, err = GetActivities(0, 1)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	if len(activities) != 1 {
+		t.Errorf("Expected limit to cap activities at 1, got %d", len(activities))
+	}
+
+	counts, err := CountActivitiesSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CountActivitiesSince failed: %v", err)
+	}
+	if counts[models.ActivityScan] != 1 || counts[models.ActivityOpen] != 1 || counts[models.ActivityArchive] != 1 {
+		t.Errorf("Expected one activity of each kind, got %v", counts)
+	}
+	if counts, _ := CountActivitiesSince(time.Now().Add(time.Hour)); len(counts) != 0 {
+		t.Errorf("Expected no activities after now, got %v", counts)
+	}
+}
+
+// TestParseProjectFilter tests parsing structured filter queries
+func TestParseProjectFilter(t *testing.T) {
+	filter := ParseProjectFilter("tag:Go status:Active lang:ts web app TAG:cli")
+	if filter.Text != "web app" {
+		t.Errorf("Expected free text 'web app', got %q", filter.Text)
+	}
+	if len(filter.Tags) != 2 || filter.Tags[0] != "go" || filter.Tags[1] != "cli" {
+		t.Errorf("Expected tags [go cli], got %v", filter.Tags)
+	}
+	if filter.Status != "active" {
+		t.Errorf("Expected status 'active', got %q", filter.Status)
+	}
+	if filter.Language != "typescript" {
+		t.Errorf("Expected lang:ts to resolve to 'typescript', got %q", filter.Language)
+	}
+
+	// topic: is an alias of tag:
+	filter = ParseProjectFilter("topic:Backend lang:Go")
+	if len(filter.Tags) != 1 || filter.Tags[0] != "backend" || filter.Language != "go" {
+		t.Errorf("Expected topic:Backend to filter tag 'backend' and lang 'go', got %+v", filter)
+	}
+
+	// Unknown fields and empty values stay in the free text
+	filter = ParseProjectFilter("owner:me tag: api")
+	if filter.Text != "owner:me tag: api" || filter.HasFields() {
+		t.Errorf("Expected only free text, got %+v", filter)
+	}
+}
+
+// TestFilterProjects tests filtering projects by fields and free text
+func TestFilterProjects(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	projects := []models.Project{
+		{Name: "webapp", Path: "/p/webapp", Status: "active", Language: "typescript", Tags: []string{"frontend"}, LastOpened: time.Now()},
+		{Name: "api", Path: "/p/api", Status: "active", Language: "go", Tags: []string{"backend", "go"}, LastOpened: time.Now()},
+		{Name: "old-api", Path: "/p/old-api", Status: "archived", Language: "go", Tags: []string{"backend"}, LastOpened: time.Now()},
+	}
+	for i := range projects {
+		if err := AddProject(&projects[i]); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 3},
+		{"lang:go", 2},
+		{"lang:golang status:active", 1},
+		{"tag:backend", 2},
+		{"tag:backend tag:go", 1},
+		{"status:archived api", 1},
+		{"FRONT", 1},
+		{"lang:rust", 0},
+	}
+	for _, tt := range tests {
+		got, err := FilterProjects(ParseProjectFilter(tt.query))
+		if err != nil {
+			t.Fatalf("FilterProjects(%q) failed: %v", tt.query, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("FilterProjects(%q) returned %d projects, want %d", tt.query, len(got), tt.want)
+		}
+	}
+}
+
+// TestDBLocation tests saving the database location and switching to it
+func TestDBLocation(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	t.Setenv("AppData", filepath.Join(tempDir, "config"))
+
+	defaultPath, err := DefaultDBPath()
+	if err != nil {
+		t.Fatalf("DefaultDBPath failed: %v", err)
+	}
+	if defaultPath == filepath.Join(tempDir, DefaultDBFileName) {
+		t.Errorf("Expected the default database outside the home directory, got %s", defaultPath)
+	}
+	resolved, err := ResolveDBPath()
+	if err != nil {
+		t.Fatalf("ResolveDBPath failed: %v", err)
+	}
+	if resolved != defaultPath {
+		t.Errorf("Expected default path %s before saving, got %s", defaultPath, resolved)
+	}
+
+	setupTestDB(t)
+	newPath := filepath.Join(tempDir, "profiles", "work", "devbase.db")
+	if err := SwitchDB(newPath); err != nil {
+		t.Fatalf("SwitchDB failed: %v", err)
+	}
+	defer teardownTestDB(t)
+
+	if _, err := os.Stat(newPath); err != nil {
+		t.Errorf("Expected database file at %s: %v", newPath, err)
+	}
+	if err := SetConfig("editor", "code"); err != nil {
+		t.Errorf("Expected switched database to be usable: %v", err)
+	}
+
+	resolved, err = ResolveDBPath()
+	if err != nil {
+		t.Fatalf("ResolveDBPath failed: %v", err)
+	}
+	if resolved != newPath {
+		t.Errorf("Expected saved path %s, got %s", newPath, resolved)
+	}
+}
+
+// TestMigrateLegacyDB tests moving ~/devbase.db to the data directory
+func TestMigrateLegacyDB(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	t.Setenv("AppData", filepath.Join(tempDir, "config"))
+
+	legacy := filepath.Join(tempDir, DefaultDBFileName)
+	if err := os.WriteFile(legacy, []byte("db"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy+"-wal", []byte("wal"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := MigrateLegacyDB()
+	if err != nil {
+		t.Fatalf("MigrateLegacyDB failed: %v", err)
+	}
+	defaultPath, _ := DefaultDBPath()
+	if migrated != defaultPath {
+		t.Errorf("Expected database moved to %s, got %q", defaultPath, migrated)
+	}
+	if data, err := os.ReadFile(defaultPath + "-wal"); err != nil || string(data) != "wal" {
+		t.Errorf("Expected WAL file moved along: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be gone after migrating", legacy)
+	}
+
+	// An existing database in the data directory is never overwritten
+	if err := os.WriteFile(legacy, []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if migrated, err := MigrateLegacyDB(); err != nil || migrated != "" {
+		t.Errorf("Expected no migration when the data directory has a database, got %q, %v", migrated, err)
+	}
+	if data, _ := os.ReadFile(defaultPath); string(data) != "db" {
+		t.Errorf("Expected migrated database to be kept, got %q", data)
+	}
+}
+
+// TestPortableMode tests the portable marker and keeping the database location relative to
+// the executable
+func TestPortableMode(t *testing.T) {
+	exeDir, err := executableDir()
+	if err != nil {
+		t.Fatalf("executableDir failed: %v", err)
+	}
+	marker := filepath.Join(exeDir, PortableMarker)
+	if PortableMarkerExists() {
+		t.Fatalf("Expected no %s next to the test binary", PortableMarker)
+	}
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Skipf("Executable directory isn't writable: %v", err)
+	}
+	defer os.Remove(marker)
+	if !PortableMarkerExists() {
+		t.Errorf("Expected %s to be found", marker)
+	}
+
+	SetPortable(true)
+	defer SetPortable(false)
+	if !Portable() {
+		t.Error("Expected portable mode to be on")
+	}
+	if dir, _ := DataDir(); dir != exeDir {
+		t.Errorf("Expected data directory %s, got %s", exeDir, dir)
+	}
+	if dir, _ := ConfigDir(); dir != exeDir {
+		t.Errorf("Expected config directory %s, got %s", exeDir, dir)
+	}
+
+	file, _ := locationFile()
+	defer os.Remove(file)
+	inside := filepath.Join(exeDir, "data", DefaultDBFileName)
+	if err := SaveDBPath(inside); err != nil {
+		t.Fatalf("SaveDBPath failed: %v", err)
+	}
+	if data, _ := os.ReadFile(file); strings.TrimSpace(string(data)) != filepath.Join("data", DefaultDBFileName) {
+		t.Errorf("Expected a location relative to the executable, got %q", data)
+	}
+	if resolved, _ := ResolveDBPath(); resolved != inside {
+		t.Errorf("Expected %s, got %s", inside, resolved)
+	}
+
+	outside := filepath.Join(t.TempDir(), DefaultDBFileName)
+	if err := SaveDBPath(outside); err != nil {
+		t.Fatalf("SaveDBPath failed: %v", err)
+	}
+	if resolved, _ := ResolveDBPath(); resolved != outside {
+		t.Errorf("Expected %s, got %s", outside, resolved)
+	}
+}
+
+// TestProjectUsage tests open counting and seeding usage from imported data
+func TestProjectUsage(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	lastOpened := time.Now().Add(-48 * time.Hour)
+	project := &models.Project{
+		Name:       "Usage Project",
+		Path:       "/path/to/usage-project",
+		LastOpened: lastOpened,
+	}
+	if err := AddProject(project); err != nil {
+		t.Fatalf("AddProject failed: %v", err)
+	}
+
+	if err := UpdateLastOpened(project.ID); err != nil {
+		t.Fatalf("UpdateLastOpened failed: %v", err)
+	}
+	retrieved, err := GetProjectByID(project.ID)
+	if err != nil {
+		t.Fatalf("GetProjectByID failed: %v", err)
+	}
+	if retrieved.OpenCount != 1 {
+		t.Errorf("Expected open count 1, got %d", retrieved.OpenCount)
+	}
+	if !retrieved.LastOpened.After(lastOpened) {
+		t.Error("Expected LastOpened to move forward")
+	}
+
+	// Older, larger imported values only raise the open count
+	opened := retrieved.LastOpened
+	if err := SeedProjectUsage(project.ID, lastOpened, 12); err != nil {
+		t.Fatalf("SeedProjectUsage failed: %v", err)
+	}
+	retrieved, err = GetProjectByID(project.ID)
+	if err != nil {
+		t.Fatalf("GetProjectByID failed: %v", err)
+	}
+	if retrieved.OpenCount != 12 {
+		t.Errorf("Expected open count 12, got %d", retrieved.OpenCount)
+	}
+	if !retrieved.LastOpened.Equal(opened) {
+		t.Errorf("Expected LastOpened %v to be kept, got %v", opened, retrieved.LastOpened)
+	}
+}
+
+// TestGetRootFolderForPath tests finding the most specific root folder of a path
+func TestGetRootFolderForPath(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	outer := &models.RootFolder{Name: "Code", Path: filepath.Join("/home", "me", "code")}
+	inner := &models.RootFolder{Name: "Work", Path: filepath.Join("/home", "me", "code", "work")}
+	for _, rootFolder := range []*models.RootFolder{outer, inner} {
+		if err := AddRootFolder(rootFolder); err != nil {
+			t.Fatalf("AddRootFolder failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want uint
+	}{
+		{filepath.Join(outer.Path, "app"), outer.ID},
+		{filepath.Join(inner.Path, "api"), inner.ID},
+		{filepath.Join("/home", "me", "codebase"), 0},
+		{filepath.Join("/tmp", "app"), 0},
+	}
+	for _, tt := range tests {
+		rootFolder, err := GetRootFolderForPath(tt.path)
+		if err != nil {
+			t.Fatalf("GetRootFolderForPath(%s) failed: %v", tt.path, err)
+		}
+		var got uint
+		if rootFolder != nil {
+			got = rootFolder.ID
+		}
+		if got != tt.want {
+			t.Errorf("GetRootFolderForPath(%s) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestRemoteHostCRUD tests adding, listing and deleting remote hosts
+func TestRemoteHostCRUD(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	devbox := &models.RemoteHost{Name: "devbox", Destination: "me@devbox.local"}
+	if err := AddRemoteHost(devbox); err != nil {
+		t.Fatalf("AddRemoteHost failed: %v", err)
+	}
+	if err := AddRemoteHost(&models.RemoteHost{Name: "build", Destination: "build"}); err != nil {
+		t.Fatalf("AddRemoteHost failed: %v", err)
+	}
+	if err := AddRemoteHost(&models.RemoteHost{Name: "devbox", Destination: "other"}); err == nil {
+		t.Error("Expected error adding a remote host with a duplicate name")
+	}
+
+	hosts, err := GetRemoteHosts()
+	if err != nil {
+		t.Fatalf("GetRemoteHosts failed: %v", err)
+	}
+	if len(hosts) != 2 || hosts[0].Name != "build" || hosts[1].Name != "devbox" {
+		t.Fatalf("Expected hosts [build devbox], got %v", hosts)
+	}
+
+	found, err := GetRemoteHostByName("devbox")
+	if err != nil {
+		t.Fatalf("GetRemoteHostByName failed: %v", err)
+	}
+	if found.Destination != "me@devbox.local" {
+		t.Errorf("Unexpected remote host: %+v", found)
+	}
+	if byID, err := GetRemoteHostByID(devbox.ID); err != nil || byID.Name != "devbox" {
+		t.Errorf("GetRemoteHostByID(%d) = %v, %v", devbox.ID, byID, err)
+	}
+
+	// A remote project may share its path with a local one
+	local := &models.Project{Name: "app", Path: "/srv/app"}
+	remote := &models.Project{Name: "app", Path: "/srv/app", RemoteHostID: devbox.ID}
+	for _, project := range []*models.Project{local, remote} {
+		if err := AddProject(project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	// Deleting the host removes its projects but keeps local ones
+	if err := DeleteRemoteHost(devbox.ID); err != nil {
+		t.Fatalf("DeleteRemoteHost failed: %v", err)
+	}
+	if _, err := GetRemoteHostByName("devbox"); err == nil {
+		t.Error("Expected remote host to be deleted")
+	}
+	if _, err := GetProjectByID(remote.ID); err == nil {
+		t.Error("Expected remote project to be deleted with its host")
+	}
+	if _, err := GetProjectByID(local.ID); err != nil {
+		t.Errorf("Expected local project to remain: %v", err)
+	}
+}
+
+// TestRepoMetadataCache tests caching repository details
+func TestRepoMetadataCache(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	meta, err := GetRepoMetadata("github.com/acme/app")
+	if err != nil {
+		t.Fatalf("GetRepoMetadata failed: %v", err)
+	}
+	if meta != nil {
+		t.Fatalf("Expected no cached details, got %+v", meta)
+	}
+
+	fetched := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := SaveRepoMetadata(&models.RepoMetadata{Repo: "github.com/acme/app", Stars: 10, FetchedAt: fetched}); err != nil {
+		t.Fatalf("SaveRepoMetadata failed: %v", err)
+	}
+	// Saving again replaces the cached details instead of adding a row
+	if err := SaveRepoMetadata(&models.RepoMetadata{Repo: "github.com/acme/app", Description: "App", Stars: 12, OpenPRs: 2, FetchedAt: fetched}); err != nil {
+		t.Fatalf("SaveRepoMetadata (replace) failed: %v", err)
+	}
+
+	meta, err = GetRepoMetadata("github.com/acme/app")
+	if err != nil {
+		t.Fatalf("GetRepoMetadata failed: %v", err)
+	}
+	if meta == nil || meta.Stars != 12 || meta.OpenPRs != 2 || meta.Description != "App" {
+		t.Fatalf("Unexpected cached details: %+v", meta)
+	}
+	if !meta.FetchedAt.Equal(fetched) {
+		t.Errorf("Expected FetchedAt %v, got %v", fetched, meta.FetchedAt)
+	}
+
+	var count int64
+	DB.Model(&models.RepoMetadata{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 cached repository, got %d", count)
+	}
+}
+
+// TestGetProjectsByName tests looking up projects by name regardless of case
+func TestGetProjectsByName(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	for _, p := range []models.Project{
+		{Name: "api", Path: "/work/api", Status: "active"},
+		{Name: "API", Path: "/personal/api", Status: "archived"},
+		{Name: "web", Path: "/work/web", Status: "active"},
+	} {
+		if err := AddProject(&p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	projects, err := GetProjectsByName("Api")
+	if err != nil {
+		t.Fatalf("GetProjectsByName failed: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects named api, got %d", len(projects))
+	}
+	// Active projects come first
+	if projects[0].Path != "/work/api" {
+		t.Errorf("Expected the active project first, got %s", projects[0].Path)
+	}
+
+	projects, err = GetProjectsByName("missing")
+	if err != nil || len(projects) != 0 {
+		t.Errorf("Expected no projects, got %v (err %v)", projects, err)
+	}
+}
+
+// TestPinnedProjects tests pinning projects and listing the active pinned ones
+func TestPinnedProjects(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	web := models.Project{Name: "web", Path: "/p/web", Status: "active"}
+	api := models.Project{Name: "api", Path: "/p/api", Status: "active"}
+	old := models.Project{Name: "old", Path: "/p/old", Status: "archived"}
+	for _, p := range []*models.Project{&web, &api, &old} {
+		if err := AddProject(p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	for _, p := range []*models.Project{&web, &api, &old} {
+		if err := SetProjectPinned(p.ID, true, ""); err != nil {
+			t.Fatalf("SetProjectPinned failed: %v", err)
+		}
+	}
+	if err := SetProjectPinned(api.ID, true, "npm run dev"); err != nil {
+		t.Fatalf("SetProjectPinned failed: %v", err)
+	}
+
+	pinned, err := GetPinnedProjects()
+	if err != nil {
+		t.Fatalf("GetPinnedProjects failed: %v", err)
+	}
+	// Archived projects are left out, the rest is sorted by name
+	if len(pinned) != 2 || pinned[0].Name != "api" || pinned[1].Name != "web" {
+		t.Fatalf("Expected pinned [api web], got %+v", pinned)
+	}
+	if pinned[0].StartCommand != "npm run dev" {
+		t.Errorf("Expected start command 'npm run dev', got %q", pinned[0].StartCommand)
+	}
+
+	if err := SetProjectPinned(web.ID, false, ""); err != nil {
+		t.Fatalf("SetProjectPinned (unpin) failed: %v", err)
+	}
+	pinned, _ = GetPinnedProjects()
+	if len(pinned) != 1 {
+		t.Errorf("Expected 1 pinned project after unpinning, got %d", len(pinned))
+	}
+
+	if err := SetProjectPinned(9999, true, ""); err == nil {
+		t.Error("Expected an error pinning a missing project")
+	}
+}
+
+func TestLockedProjects(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	root := &models.RootFolder{Name: "Projects", Path: "/projects"}
+	if err := AddRootFolder(root); err != nil {
+		t.Fatalf("AddRootFolder failed: %v", err)
+	}
+	monorepo := models.Project{Name: "monorepo", Path: "/projects/monorepo", RootFolderID: root.ID}
+	scratch := models.Project{Name: "scratch", Path: "/projects/scratch", RootFolderID: root.ID}
+	for _, p := range []*models.Project{&monorepo, &scratch} {
+		if err := AddProject(p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+	if err := SetProjectLocked(monorepo.ID, true); err != nil {
+		t.Fatalf("SetProjectLocked failed: %v", err)
+	}
+
+	if err := DeleteProject(monorepo.ID); !errors.Is(err, ErrProjectLocked) {
+		t.Errorf("Expected ErrProjectLocked deleting a locked project, got %v", err)
+	}
+	if _, err := DeleteAllProjects(); !errors.Is(err, ErrProjectLocked) || !strings.Contains(err.Error(), "monorepo") {
+		t.Errorf("Expected ErrProjectLocked naming monorepo clearing all projects, got %v", err)
+	}
+	if err := DeleteRootFolder(root.ID); !errors.Is(err, ErrProjectLocked) {
+		t.Errorf("Expected ErrProjectLocked deleting the root folder, got %v", err)
+	}
+	if _, err := ClearRootFolderProjects(root.ID); !errors.Is(err, ErrProjectLocked) {
+		t.Errorf("Expected ErrProjectLocked clearing the root folder, got %v", err)
+	}
+	if summary, _ := SummarizeClear(root.ID); summary.Active != 2 || len(summary.Locked) != 1 || summary.Locked[0] != "monorepo" {
+		t.Errorf("Expected 2 active projects with monorepo locked, got %+v", summary)
+	}
+	if projects, _ := GetProjects(); len(projects) != 2 {
+		t.Fatalf("Expected both projects kept, got %d", len(projects))
+	}
+
+	// Scans keep a locked project whose directory vanished
+	result, err := ReconcileProjects(root.ID, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+	if result.Removed != 1 {
+		t.Errorf("Expected only the unlocked project removed, got %+v", result)
+	}
+	if _, err := GetProjectByID(monorepo.ID); err != nil {
+		t.Errorf("Expected the locked project kept, got %v", err)
+	}
+
+	if err := SetProjectLocked(monorepo.ID, false); err != nil {
+		t.Fatalf("SetProjectLocked (unlock) failed: %v", err)
+	}
+	if err := DeleteProject(monorepo.ID); err != nil {
+		t.Errorf("Expected an unlocked project to be deleted, got %v", err)
+	}
+	if err := SetProjectLocked(9999, true); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound locking a missing project, got %v", err)
+	}
+}
+
+func TestCountProjectsByStatus(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	root1 := models.RootFolder{Name: "One", Path: "/one", IsActive: true}
+	root2 := models.RootFolder{Name: "Two", Path: "/two"}
+	for _, r := range []*models.RootFolder{&root1, &root2} {
+		if err := AddRootFolder(r); err != nil {
+			t.Fatalf("AddRootFolder failed: %v", err)
+		}
+	}
+
+	projects := []models.Project{
+		{Name: "a", Path: "/one/a", Status: "active", RootFolderID: root1.ID},
+		{Name: "b", Path: "/one/b", Status: "archived", RootFolderID: root1.ID},
+		{Name: "c", Path: "/two/c", Status: "active", RootFolderID: root2.ID},
+	}
+	for i := range projects {
+		if err := AddProject(&projects[i]); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	// Counts span every root folder, not just the active one
+	counts, err := CountProjectsByStatus()
+	if err != nil {
+		t.Fatalf("CountProjectsByStatus failed: %v", err)
+	}
+	if counts["active"] != 2 || counts["archived"] != 1 {
+		t.Errorf("Expected 2 active and 1 archived, got %v", counts)
+	}
+
+	if err := Ping(); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+}
+
+func TestWebhookCRUD(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	all := &models.Webhook{URL: "https://example.com/hook"}
+	slack := &models.Webhook{URL: "https://hooks.slack.com/services/x", Format: models.WebhookSlack, Events: []string{"archive", "sync_failed"}}
+	for _, w := range []*models.Webhook{all, slack} {
+		if err := AddWebhook(w); err != nil {
+			t.Fatalf("AddWebhook failed: %v", err)
+		}
+	}
+
+	webhooks, err := GetWebhooks()
+	if err != nil {
+		t.Fatalf("GetWebhooks failed: %v", err)
+	}
+	if len(webhooks) != 2 {
+		t.Fatalf("Expected 2 webhooks, got %d", len(webhooks))
+	}
+	if webhooks[0].Format != models.WebhookJSON {
+		t.Errorf("Expected default format %q, got %q", models.WebhookJSON, webhooks[0].Format)
+	}
+	if len(webhooks[1].Events) != 2 || webhooks[1].Events[1] != "sync_failed" {
+		t.Errorf("Expected events [archive sync_failed], got %v", webhooks[1].Events)
+	}
+
+	if err := DeleteWebhook(all.ID); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if err := DeleteWebhook(all.ID); err == nil {
+		t.Error("Expected an error deleting a missing webhook")
+	}
+	webhooks, _ = GetWebhooks()
+	if len(webhooks) != 1 || webhooks[0].ID != slack.ID {
+		t.Errorf("Expected only the Slack webhook to remain, got %v", webhooks)
+	}
+}
+
+func TestParseConfigFile(t *testing.T) {
+	values, err := ParseConfigFile([]byte(`
+# Top-level keys are config keys as they are
+editor = "cursor"  # trailing comment
+theme = 'high-contrast'
+
+[editor]
+prompt = true
+
+[scanner]
+ignore = ["vendor", "tmp", "a#b",]
+
+[layout]
+list_ratio = 60
+`))
+	if err != nil {
+		t.Fatalf("ParseConfigFile failed: %v", err)
+	}
+	expected := map[string]string{
+		"editor":            "cursor",
+		"theme":             "high-contrast",
+		"editor_prompt":     "true",
+		"scanner_ignore":    "vendor,tmp,a#b",
+		"layout_list_ratio": "60",
+	}
+	if len(values) != len(expected) {
+		t.Errorf("Expected %d values, got %v", len(expected), values)
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Errorf("%s = %q, expected %q", key, values[key], want)
+		}
+	}
+
+	for _, bad := range []string{"editor = cursor", "editor", "[scanner", `editor = "a`, "a = 1\na = 2", "ignore = [\"a\""} {
+		if _, err := ParseConfigFile([]byte(bad)); err == nil {
+			t.Errorf("Expected an error parsing %q", bad)
+		}
+	}
+}
+
+func TestConfigFileOverride(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Setenv("AppData", filepath.Join(tempDir, "config"))
+	setupTestDB(t)
+	defer teardownTestDB(t)
+	defer LoadConfigFile()
+
+	if err := SetConfig("editor", "code"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	path, err := ConfigFilePath()
+	if err != nil {
+		t.Fatalf("ConfigFilePath failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`editor = "nvim"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := ReloadConfigFile(); err != nil || !changed {
+		t.Fatalf("ReloadConfigFile = %v, %v, expected a change", changed, err)
+	}
+	if value, _ := GetConfig("editor"); value != "nvim" {
+		t.Errorf("Expected config.toml to override the editor, got %q", value)
+	}
+	if changed, _ := ReloadConfigFile(); changed {
+		t.Error("Expected no change when the file is untouched")
+	}
+
+	// Removing the file brings back the database value
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := ReloadConfigFile(); err != nil || !changed {
+		t.Fatalf("ReloadConfigFile = %v, %v after removal", changed, err)
+	}
+	if value, _ := GetConfig("editor"); value != "code" {
+		t.Errorf("Expected the database editor after removing config.toml, got %q", value)
+	}
+}
+
+// TestReconcileProjects tests storing a scan of a root folder
+func TestReconcileProjects(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	root := &models.RootFolder{Name: "Projects", Path: "/projects"}
+	if err := AddRootFolder(root); err != nil {
+		t.Fatalf("AddRootFolder failed: %v", err)
+	}
+	for _, p := range []models.Project{
+		{Name: "kept", Path: "/projects/kept", Language: "javascript"},
+		{Name: "gone", Path: "/projects/gone"},
+		{Name: "archived", Path: "/projects/archived", Status: "archived"},
+	} {
+		p.RootFolderID = root.ID
+		if err := AddProject(&p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+
+	scanned := []models.Project{
+		{Name: "kept", Path: "/projects/kept", Language: "typescript"},
+		{Name: "new", Path: "/projects/new", Language: "go"},
+	}
+	result, err := ReconcileProjects(root.ID, scanned, nil)
+	if err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+	if result != (ReconcileResult{Added: 1, Updated: 1, Removed: 1}) {
+		t.Errorf("Expected 1 added, updated and removed, got %+v", result)
+	}
+
+	projects, _ := GetProjectsByRootFolder(root.ID)
+	byName := make(map[string]models.Project)
+	for _, p := range projects {
+		byName[p.Name] = p
+	}
+	if len(projects) != 3 || byName["gone"].ID != 0 {
+		t.Errorf("Expected kept, new and archived to remain, got %v", projects)
+	}
+	if byName["kept"].Language != "typescript" {
+		t.Errorf("Expected kept to be updated to typescript, got %q", byName["kept"].Language)
+	}
+	if byName["new"].Status != "active" || byName["new"].LastOpened.IsZero() {
+		t.Errorf("Expected new project to be active with a LastOpened time, got %+v", byName["new"])
+	}
+
+	// A vanished project that comes back is added again
+	scanned = append(scanned, models.Project{Name: "gone", Path: "/projects/gone"})
+	result, err = ReconcileProjects(root.ID, scanned, nil)
+	if err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+	if result != (ReconcileResult{Added: 1}) {
+		t.Errorf("Expected only gone to be added again, got %+v", result)
+	}
+
+	// Projects in a directory the scan didn't enter weren't looked for, so they stay
+	for _, path := range []string{"/projects/share/app", "/projects/shared-lib"} {
+		p := models.Project{Name: filepath.Base(path), Path: path, RootFolderID: root.ID}
+		if err := AddProject(&p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+	result, err = ReconcileProjects(root.ID, scanned, []string{"/projects/share"})
+	if err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+	if result.Removed != 1 {
+		t.Errorf("Expected only shared-lib removed, got %+v", result)
+	}
+	if _, err := GetProjectByPath("/projects/share/app"); err != nil {
+		t.Errorf("Expected the project in the unscanned directory kept, got %v", err)
+	}
+}
+
+// TestRecordOpen tests that repeated opens within OpenDebounce are counted once
+func TestRecordOpen(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	project := &models.Project{Name: "app", Path: "/projects/app"}
+	if err := AddProject(project); err != nil {
+		t.Fatalf("AddProject failed: %v", err)
+	}
+
+	// The first open counts although LastOpened was just set by AddProject
+	counted, err := RecordOpen(project.ID)
+	if err != nil || !counted {
+		t.Fatalf("Expected first open to be counted, got %v, %v", counted, err)
+	}
+	counted, err = RecordOpen(project.ID)
+	if err != nil || counted {
+		t.Errorf("Expected second open to be debounced, got %v, %v", counted, err)
+	}
+	if p, _ := GetProjectByID(project.ID); p.OpenCount != 1 {
+		t.Errorf("Expected open count 1, got %d", p.OpenCount)
+	}
+
+	// Opens after the debounce window count again
+	if err := DB.Model(&models.Project{}).Where("id = ?", project.ID).Update("last_opened", time.Now().Add(-2*OpenDebounce)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if counted, err := RecordOpen(project.ID); err != nil || !counted {
+		t.Errorf("Expected open after the debounce window to be counted, got %v, %v", counted, err)
+	}
+	if p, _ := GetProjectByID(project.ID); p.OpenCount != 2 {
+		t.Errorf("Expected open count 2, got %d", p.OpenCount)
+	}
+
+	if _, err := RecordOpen(9999); err == nil {
+		t.Error("Expected an error for an unknown project")
+	}
+}
+
+// TestCloseDBCheckpoint tests that closing the database leaves no pending writes in the WAL
+func TestCloseDBCheckpoint(t *testing.T) {
+	dbPath := setupTestDB(t)
+
+	if err := AddProject(&models.Project{Name: "app", Path: "/projects/app"}); err != nil {
+		t.Fatalf("AddProject failed: %v", err)
+	}
+	if err := CloseDB(); err != nil {
+		t.Fatalf("CloseDB failed: %v", err)
+	}
+	if info, err := os.Stat(dbPath + "-wal"); err == nil && info.Size() > 0 {
+		t.Errorf("Expected an empty or removed WAL after closing, got %d bytes", info.Size())
+	}
+
+	// The project is in the database file itself
+	if err := InitDB(dbPath); err != nil {
+		t.Fatalf("InitDB failed: %v", err)
+	}
+	defer teardownTestDB(t)
+	if projects, _ := GetProjects(); len(projects) != 1 {
+		t.Errorf("Expected the project to be stored, got %d projects", len(projects))
+	}
+}
+
+// TestConcurrentWrites tests that read-modify-write updates from many goroutines don't
+// overwrite each other
+func TestConcurrentWrites(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	project := &models.Project{Name: "app", Path: "/projects/app"}
+	if err := AddProject(project); err != nil {
+		t.Fatalf("AddProject failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- AddProjectTag(project.ID, fmt.Sprintf("tag%02d", i))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("AddProjectTag failed: %v", err)
+		}
+	}
+
+	if p, _ := GetProjectByID(project.ID); len(p.Tags) != 20 {
+		t.Errorf("Expected 20 tags, got %d: %v", len(p.Tags), p.Tags)
+	}
+}
+
+// TestWriteWaitsForLock tests that a write waits for another connection, like the serve
+// daemon, to release the database
+func TestWriteWaitsForLock(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t)
+
+	other, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	conn, err := other.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(context.Background(), "BEGIN IMMEDIATE"); err != nil {
+		t.Fatalf("Failed to lock database: %v", err)
+	}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		conn.ExecContext(context.Background(), "COMMIT")
+	}()
+
+	if err := SetConfig("editor", "vim"); err != nil {
+		t.Fatalf("SetConfig failed while the database was locked: %v", err)
+	}
+	if value, _ := GetConfig("editor"); value != "vim" {
+		t.Errorf("Expected editor vim, got %q", value)
+	}
+}
+
+// TestNotFoundErrors tests that missing records are reported with their sentinel errors
+func TestNotFoundErrors(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	if _, err := GetProjectByID(9999); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound by ID, got %v", err)
+	}
+	if _, err := GetProjectByPath("/nowhere"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound by path, got %v", err)
+	}
+	if err := UpdateProjectNotes(9999, "notes"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound updating notes, got %v", err)
+	}
+	if err := AddProjectTag(9999, "go"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound adding a tag, got %v", err)
+	}
+	if _, err := GetRootFolderByID(9999); !errors.Is(err, ErrRootFolderNotFound) {
+		t.Errorf("Expected ErrRootFolderNotFound, got %v", err)
+	}
+	if _, err := GetActiveRootFolder(); !errors.Is(err, ErrRootFolderNotFound) {
+		t.Errorf("Expected ErrRootFolderNotFound without an active root folder, got %v", err)
+	}
+	if _, err := GetRemoteHostByName("nas"); !errors.Is(err, ErrRemoteHostNotFound) {
+		t.Errorf("Expected ErrRemoteHostNotFound, got %v", err)
+	}
+	if err := DeleteWebhook(9999); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("Expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+// TestProjectMachine tests recording the machine that registered a project
+func TestProjectMachine(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t)
+
+	if err := SetConfig("machine_name", "laptop"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	root := &models.RootFolder{Name: "Code", Path: "/code"}
+	if err := AddRootFolder(root); err != nil {
+		t.Fatalf("AddRootFolder failed: %v", err)
+	}
+
+	local := &models.Project{Name: "api", Path: "/code/api", RootFolderID: root.ID}
+	synced := &models.Project{Name: "web", Path: "/code/web", Status: "archived", Machine: "desktop", RootFolderID: root.ID}
+	for _, p := range []*models.Project{local, synced} {
+		if err := AddProject(p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+	if local.Machine != "laptop" || synced.Machine != "desktop" {
+		t.Errorf("Expected machines laptop and desktop, got %q and %q", local.Machine, synced.Machine)
+	}
+
+	// Projects from before machines were recorded are claimed by the scan that finds them
+	if err := DB.Model(&models.Project{}).Where("id = ?", local.ID).Update("machine", "").Error; err != nil {
+		t.Fatal(err)
+	}
+	scanned := []models.Project{{Name: "api", Path: "/code/api"}, {Name: "cli", Path: "/code/cli"}}
+	result, err := ReconcileProjects(root.ID, scanned, nil)
+	if err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+	if result.Updated != 0 || result.Added != 1 {
+		t.Errorf("Expected 1 added and none updated, got %+v", result)
+	}
+	for path, want := range map[string]string{"/code/api": "laptop", "/code/cli": "laptop", "/code/web": "desktop"} {
+		p, err := GetProjectByPath(path)
+		if err != nil {
+			t.Fatalf("GetProjectByPath failed: %v", err)
+		}
+		if p.Machine != want {
+			t.Errorf("Expected %s on %s, got %q", path, want, p.Machine)
+		}
+	}
+}
+
 // TestMain runs before all tests
 func TestMain(m *testing.M) {
 	// Run tests
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"devbase/models"
+)
+
+// languageAliases maps short language names accepted by lang: to the identifiers
+// stored in Project.Language (see engine.DetectLanguage)
+var languageAliases = map[string]string{
+	"golang": "go",
+	"ts":     "typescript",
+	"js":     "javascript",
+	"py":     "python",
+	"rs":     "rust",
+	"cs":     "csharp",
+	"c#":     "csharp",
+	"rb":     "ruby",
+	"c++":    "cpp",
+}
+
+// ProjectFilter narrows projects by field values and free text
+type ProjectFilter struct {
+	Text     string   // Free text, matched against name and tags
+	Tags     []string // tag:x, every tag must be present
+	Status   string   // status:active or status:archived
+	Language string   // lang:x, aliases like "ts" are resolved
+}
+
+// ParseProjectFilter parses a query such as "tag:go status:active lang:ts webapp".
+// topic: is an alias of tag:, matching GitHub topics in the repository pickers.
+// Words without a known field prefix make up the free text.
+func ParseProjectFilter(query string) ProjectFilter {
+	var filter ProjectFilter
+	var text []string
+	for _, word := range strings.Fields(query) {
+		field, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			text = append(text, word)
+			continue
+		}
+		switch strings.ToLower(field) {
+		case "tag", "tags", "topic", "topics":
+			if tag := NormalizeTag(value); tag != "" && !slices.Contains(filter.Tags, tag) {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		case "status":
+			filter.Status = strings.ToLower(value)
+		case "lang", "language":
+			filter.Language = NormalizeLanguage(value)
+		default:
+			text = append(text, word)
+		}
+	}
+	filter.Text = strings.Join(text, " ")
+	return filter
+}
+
+// NormalizeLanguage resolves a language name or alias to its stored identifier,
+// e.g. "TypeScript" and "ts" to "typescript"
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(language)
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return language
+}
+
+// HasFields reports whether the filter restricts any field besides the free text
+func (f ProjectFilter) HasFields() bool {
+	return len(f.Tags) > 0 || f.Status != "" || f.Language != ""
+}
+
+// MatchesFields reports whether a project satisfies the field filters, ignoring the free text
+func (f ProjectFilter) MatchesFields(project models.Project) bool {
+	if f.Status != "" && project.Status != f.Status {
+		return false
+	}
+	if f.Language != "" && project.Language != f.Language {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.Contains(project.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether a project satisfies the field filters and contains every free text word
+// in its name or tags (case-insensitive)
+func (f ProjectFilter) Matches(project models.Project) bool {
+	if !f.MatchesFields(project) {
+		return false
+	}
+	haystack := strings.ToLower(project.Name + " " + strings.Join(project.Tags, " "))
+	for _, word := range strings.Fields(strings.ToLower(f.Text)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterProjects retrieves the projects matching a filter, sorted by LastOpened descending.
+// If a root folder is active, only its projects are searched.
+func FilterProjects(filter ProjectFilter) ([]models.Project, error) {
+	query := DB.Model(&models.Project{})
+
+	// Try to get active root folder
+	activeRoot, err := GetActiveRootFolder()
+	if err == nil && activeRoot != nil {
+		query = query.Where("root_folder_id = ?", activeRoot.ID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Language != "" {
+		query = query.Where("language = ?", filter.Language)
+	}
+
+	var projects []models.Project
+	if err := query.Order("last_opened DESC").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve projects: %w", err)
+	}
+
+	// Tags are stored as JSON, so they and the free text are matched here
+	matched := projects[:0]
+	for _, project := range projects {
+		if filter.Matches(project) {
+			matched = append(matched, project)
+		}
+	}
+	return matched, nil
+}
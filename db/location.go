@@ -0,0 +1,266 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultDBFileName is the database file name used inside a chosen directory
+const DefaultDBFileName = "devbase.db"
+
+// PortableMarker is the file that turns on portable mode when it is next to the executable,
+// so a copy on a USB drive doesn't need --portable on every run
+const PortableMarker = "devbase.portable"
+
+// portable keeps the database, config and logs next to the executable (--portable)
+var portable bool
+
+// SetPortable switches to keeping all data next to the executable instead of the user's
+// data and config directories
+func SetPortable(enabled bool) {
+	portable = enabled
+}
+
+// Portable reports whether data is kept next to the executable
+func Portable() bool {
+	return portable
+}
+
+// PortableMarkerExists reports whether PortableMarker is next to the executable
+func PortableMarkerExists() bool {
+	dir, err := executableDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, PortableMarker))
+	return err == nil
+}
+
+// executableDir returns the directory of the running executable, with symlinks resolved
+func executableDir() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Dir(exe), nil
+}
+
+// DataDir returns the directory DevBase keeps its data in: %APPDATA%\devbase on Windows,
+// ~/Library/Application Support/devbase on macOS and $XDG_DATA_HOME/devbase (~/.local/share)
+// elsewhere, or the executable's directory in portable mode
+func DataDir() (string, error) {
+	if portable {
+		return executableDir()
+	}
+
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate data directory: %w", err)
+		}
+		return filepath.Join(configDir, "devbase"), nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	return filepath.Join(dataHome, "devbase"), nil
+}
+
+// ConfigDir returns the directory of db_location, config.toml and generated files such as
+// editor workspaces. In portable mode it is the data directory, so nothing is written
+// outside of it.
+func ConfigDir() (string, error) {
+	if portable {
+		return DataDir()
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "devbase"), nil
+}
+
+// TrashDir returns the directory archived checkouts are moved to until they are purged
+func TrashDir() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
+// DefaultDBPath returns the database location used when none has been chosen
+func DefaultDBPath() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, DefaultDBFileName), nil
+}
+
+// legacyDBPath returns where versions before the data directory kept the database (~/devbase.db)
+func legacyDBPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, DefaultDBFileName), nil
+}
+
+// locationFile returns the file remembering a custom database location.
+// It lives outside the database so the location is known before opening it.
+func locationFile() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "db_location"), nil
+}
+
+// savedDBPath returns the database location chosen in setup, if any
+func savedDBPath() string {
+	file, err := locationFile()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(data))
+	// Portable copies save locations inside their directory relative to it, so they still
+	// work when the drive is mounted somewhere else
+	if path != "" && portable && !filepath.IsAbs(path) {
+		if dir, err := DataDir(); err == nil {
+			path = filepath.Join(dir, path)
+		}
+	}
+	return path
+}
+
+// ResolveDBPath returns the saved database location, or the default one if none was saved
+func ResolveDBPath() (string, error) {
+	if path := savedDBPath(); path != "" {
+		return path, nil
+	}
+	return DefaultDBPath()
+}
+
+// MigrateLegacyDB moves ~/devbase.db, with its WAL files, to the data directory when no
+// other location was chosen and the data directory has no database yet. It returns the
+// new location when a database was moved, and "" otherwise.
+func MigrateLegacyDB() (string, error) {
+	if portable || savedDBPath() != "" {
+		return "", nil
+	}
+	legacy, err := legacyDBPath()
+	if err != nil {
+		return "", err
+	}
+	target, err := DefaultDBPath()
+	if err != nil {
+		return "", err
+	}
+	if legacy == target {
+		return "", nil
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		return "", nil
+	}
+	if _, err := os.Stat(target); err == nil {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	// The WAL holds committed changes that aren't in the main file yet, so it moves along
+	for _, suffix := range []string{"-wal", "-shm", ""} {
+		if err := moveFile(legacy+suffix, target+suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to move %s to %s: %w", legacy+suffix, target+suffix, err)
+		}
+	}
+	return target, nil
+}
+
+// moveFile renames a file, copying it when the rename crosses file systems
+func moveFile(from, to string) error {
+	if err := os.Rename(from, to); err == nil || errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(to)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(to)
+		return err
+	}
+	src.Close()
+	return os.Remove(from)
+}
+
+// SaveDBPath remembers the database location for future runs
+func SaveDBPath(path string) error {
+	file, err := locationFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if portable {
+		if dir, err := DataDir(); err == nil {
+			if rel, err := filepath.Rel(dir, path); err == nil && filepath.IsLocal(rel) {
+				path = rel
+			}
+		}
+	}
+	if err := os.WriteFile(file, []byte(path+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save database location: %w", err)
+	}
+	return nil
+}
+
+// SwitchDB closes the current database, opens the one at path and remembers it for future runs
+func SwitchDB(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+	// Writes wait until the new database is open
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if err := closeDB(); err != nil {
+		return err
+	}
+	if err := InitDB(path); err != nil {
+		return err
+	}
+	return SaveDBPath(path)
+}
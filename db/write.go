@@ -0,0 +1,60 @@
+package db
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Retries of a write that found the database locked by another process, such as the serve
+// daemon next to the TUI, after busy_timeout ran out. The delay doubles on every attempt.
+const (
+	writeAttempts   = 5
+	writeRetryDelay = 50 * time.Millisecond
+)
+
+// writeMu serializes writes within the process. CloseDB and SwitchDB hold it too, so the
+// database is never closed or swapped in the middle of a write.
+var writeMu sync.Mutex
+
+// write runs fn in a transaction, one writer at a time, and runs it again when the
+// database is busy. fn must only use tx: DB waits for the connection the transaction
+// holds. Transactions begin immediately (see InitDB), so a busy database fails before fn
+// runs rather than halfway through it.
+func write(fn func(tx *gorm.DB) error) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if DB == nil {
+		return errors.New("database is not open")
+	}
+
+	delay := writeRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := DB.Transaction(fn)
+		if err == nil || !isBusy(err) || attempt == writeAttempts {
+			return err
+		}
+		slog.Debug("Database is busy, retrying write", "attempt", attempt, "err", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isBusy reports whether err means another connection held a lock on the database
+func isBusy(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	// Extended codes such as SQLITE_BUSY_SNAPSHOT keep the primary code in the low byte
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// backupFormat and backupVersion identify the backup envelope written by EncodeBackup
+const (
+	backupFormat  = "devbase-backup"
+	backupVersion = 1
+)
+
+// ErrBackupIntegrity is returned when a backup is damaged or its signature doesn't verify,
+// so it must not replace the local projects
+var ErrBackupIntegrity = errors.New("backup failed its integrity check")
+
+// backupEnvelope wraps the projects of a backup with what is needed to verify them. The
+// checksum and signature cover the compact JSON encoding of Projects.
+type backupEnvelope struct {
+	Format    string          `json:"format"`
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Count     int             `json:"count"`
+	SHA256    string          `json:"sha256"`
+	Signature string          `json:"signature,omitempty"` // ASCII-armored GPG detached signature
+	Projects  json.RawMessage `json:"projects"`
+}
+
+// BackupInfo describes a decoded backup
+type BackupInfo struct {
+	Legacy    bool // A plain project array written before backups were checksummed
+	Signed    bool
+	CreatedAt time.Time
+	Count     int
+}
+
+// BackupSignKey returns the GPG key backups are signed with, from the "backup_sign_key"
+// config key; empty leaves them unsigned
+func BackupSignKey() string {
+	key, _ := db.GetConfig("backup_sign_key")
+	return strings.TrimSpace(key)
+}
+
+// BackupRequireSignature reports whether unsigned backups are refused, set with the
+// "backup_require_signature" config key
+func BackupRequireSignature() bool {
+	require, _ := db.GetConfig("backup_require_signature")
+	return require == "true"
+}
+
+// EncodeBackup writes projects as a backup envelope with their count and checksum, signed
+// with the GPG key signKey unless it is empty
+func EncodeBackup(projects []models.Project, signKey string) (string, error) {
+	if projects == nil {
+		projects = []models.Project{}
+	}
+	data, err := json.Marshal(projects)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode projects: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	envelope := backupEnvelope{
+		Format:    backupFormat,
+		Version:   backupVersion,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Count:     len(projects),
+		SHA256:    hex.EncodeToString(sum[:]),
+		Projects:  data,
+	}
+	if signKey != "" {
+		if envelope.Signature, err = gpgSign(signKey, data); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup: %w", err)
+	}
+	return string(out), nil
+}
+
+// DecodeBackup reads a backup written by EncodeBackup, checking its project count, checksum
+// and, when it is signed, the signature. Damaged backups fail with ErrBackupIntegrity.
+// Plain project arrays from older versions are accepted unless requireSignature is set,
+// which also refuses unsigned envelopes.
+func DecodeBackup(content string, requireSignature bool) ([]models.Project, BackupInfo, error) {
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "[") {
+		if requireSignature {
+			return nil, BackupInfo{}, fmt.Errorf("%w: the backup predates signatures; push it again", ErrBackupIntegrity)
+		}
+		var projects []models.Project
+		if err := json.Unmarshal([]byte(trimmed), &projects); err != nil {
+			return nil, BackupInfo{}, fmt.Errorf("%w: %v", ErrBackupIntegrity, err)
+		}
+		slog.Warn("Loaded a backup without a checksum; push again to add one", "projects", len(projects))
+		return projects, BackupInfo{Legacy: true, Count: len(projects)}, nil
+	}
+
+	var envelope backupEnvelope
+	if err := json.Unmarshal([]byte(trimmed), &envelope); err != nil {
+		return nil, BackupInfo{}, fmt.Errorf("%w: %v", ErrBackupIntegrity, err)
+	}
+	if envelope.Format != backupFormat {
+		return nil, BackupInfo{}, fmt.Errorf("%w: not a DevBase backup", ErrBackupIntegrity)
+	}
+	if envelope.Version > backupVersion {
+		return nil, BackupInfo{}, fmt.Errorf("backup version %d is newer than this DevBase supports; update DevBase", envelope.Version)
+	}
+
+	// Indentation of the envelope doesn't change the compact encoding that was summed
+	var data bytes.Buffer
+	if err := json.Compact(&data, envelope.Projects); err != nil {
+		return nil, BackupInfo{}, fmt.Errorf("%w: %v", ErrBackupIntegrity, err)
+	}
+	sum := sha256.Sum256(data.Bytes())
+	if hex.EncodeToString(sum[:]) != strings.ToLower(envelope.SHA256) {
+		return nil, BackupInfo{}, fmt.Errorf("%w: checksum mismatch", ErrBackupIntegrity)
+	}
+
+	info := BackupInfo{Signed: envelope.Signature != "", CreatedAt: envelope.CreatedAt, Count: envelope.Count}
+	if info.Signed {
+		if err := gpgVerify(data.Bytes(), envelope.Signature); err != nil {
+			return nil, info, err
+		}
+	} else if requireSignature {
+		return nil, info, fmt.Errorf("%w: the backup isn't signed and backup_require_signature is set", ErrBackupIntegrity)
+	}
+
+	var projects []models.Project
+	if err := json.Unmarshal(data.Bytes(), &projects); err != nil {
+		return nil, info, fmt.Errorf("%w: %v", ErrBackupIntegrity, err)
+	}
+	if len(projects) != envelope.Count {
+		return nil, info, fmt.Errorf("%w: %d of %d projects", ErrBackupIntegrity, len(projects), envelope.Count)
+	}
+	return projects, info, nil
+}
+
+// gpgSign creates an ASCII-armored detached signature of data with a key of the user's
+// GPG keyring
+func gpgSign(key string, data []byte) (string, error) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return "", fmt.Errorf("backup_sign_key is set but gpg is not installed or not on PATH")
+	}
+	cmd := exec.Command("gpg", "--batch", "--yes", "--armor", "--detach-sign", "--local-user", key)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to sign the backup with %s: %s", key, gpgError(stderr.String(), err))
+	}
+	return stdout.String(), nil
+}
+
+// gpgVerify checks a detached signature of data against the user's GPG keyring
+func gpgVerify(data []byte, signature string) error {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return fmt.Errorf("the backup is signed, but gpg is not installed to verify it")
+	}
+	dir, err := os.MkdirTemp("", "devbase-verify-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	sigPath := filepath.Join(dir, "backup.json.asc")
+	if err := os.WriteFile(sigPath, []byte(signature), 0600); err != nil {
+		return err
+	}
+
+	// "-" reads the signed data from stdin
+	cmd := exec.Command("gpg", "--batch", "--verify", sigPath, "-")
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: bad or unknown signature: %s", ErrBackupIntegrity, gpgError(stderr.String(), err))
+	}
+	return nil
+}
+
+// gpgError returns the last line gpg printed, or err when it printed nothing
+func gpgError(stderr string, err error) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return strings.TrimPrefix(last, "gpg: ")
+	}
+	return err.Error()
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"devbase/models"
+)
+
+func TestBackupIntegrity(t *testing.T) {
+	projects := []models.Project{
+		{Name: "api", Path: "/code/api", RepoURL: "https://github.com/acme/api", Status: "active", Tags: []string{"work"}},
+		{Name: "site", Path: "/code/site", Status: "archived"},
+	}
+	content, err := EncodeBackup(projects, "")
+	if err != nil {
+		t.Fatalf("EncodeBackup failed: %v", err)
+	}
+	decoded, info, err := DecodeBackup(content, false)
+	if err != nil {
+		t.Fatalf("DecodeBackup failed: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].RepoURL != projects[0].RepoURL || info.Count != 2 || info.Legacy || info.Signed {
+		t.Errorf("DecodeBackup returned %+v, %+v", decoded, info)
+	}
+
+	damaged := map[string]string{
+		"edited":    strings.Replace(content, `"site"`, `"sight"`, 1),
+		"truncated": content[:len(content)/2],
+		"dropped":   strings.Replace(content, `"count": 2`, `"count": 3`, 1),
+		"other":     `{"format": "something-else"}`,
+	}
+	for name, c := range damaged {
+		if _, _, err := DecodeBackup(c, false); !errors.Is(err, ErrBackupIntegrity) {
+			t.Errorf("%s backup: DecodeBackup returned %v, want ErrBackupIntegrity", name, err)
+		}
+	}
+	if _, _, err := DecodeBackup(content, true); !errors.Is(err, ErrBackupIntegrity) {
+		t.Errorf("unsigned backup with a signature required: DecodeBackup returned %v", err)
+	}
+
+	// Backups written before the envelope are plain arrays
+	legacy, _ := json.Marshal(projects)
+	if decoded, info, err := DecodeBackup(string(legacy), false); err != nil || len(decoded) != 2 || !info.Legacy {
+		t.Errorf("legacy backup: DecodeBackup returned %d projects, %+v, %v", len(decoded), info, err)
+	}
+}
+
+func TestBackupSignature(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+	// A short home directory, as gpg-agent's socket path is limited in length
+	home, err := os.MkdirTemp("", "gpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// gpg-agent sockets must not outlive the test
+	t.Cleanup(func() {
+		exec.Command("gpgconf", "--homedir", home, "--kill", "all").Run()
+		os.RemoveAll(home)
+	})
+	t.Setenv("GNUPGHOME", home)
+	key := "DevBase Test <backup@example.com>"
+	if output, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", key, "ed25519", "sign", "never").CombinedOutput(); err != nil {
+		t.Skipf("can't create a test key: %s", output)
+	}
+
+	projects := []models.Project{{Name: "api", Path: "/code/api", Status: "active"}}
+	content, err := EncodeBackup(projects, "backup@example.com")
+	if err != nil {
+		t.Fatalf("EncodeBackup failed: %v", err)
+	}
+	if _, info, err := DecodeBackup(content, true); err != nil || !info.Signed {
+		t.Fatalf("DecodeBackup of a signed backup returned %+v, %v", info, err)
+	}
+
+	// A re-summed edit still fails the signature
+	var envelope backupEnvelope
+	if err := json.Unmarshal([]byte(content), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	envelope.Projects, _ = json.Marshal([]models.Project{{Name: "evil", Path: "/code/api", Status: "active"}})
+	sum := sha256.Sum256(envelope.Projects)
+	envelope.SHA256 = hex.EncodeToString(sum[:])
+	forged, _ := json.Marshal(envelope)
+	if _, _, err := DecodeBackup(string(forged), false); !errors.Is(err, ErrBackupIntegrity) {
+		t.Errorf("forged backup: DecodeBackup returned %v, want ErrBackupIntegrity", err)
+	}
+}
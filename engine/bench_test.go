@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// benchTree creates a synthetic tree of 100 groups with 100 directories each. Every tenth
+// directory is a project; the others hold a file and a nested directory, so the scanner
+// walks 10k directories that aren't projects.
+func benchTree(b *testing.B) (string, int) {
+	b.Helper()
+	root := b.TempDir()
+	projects := 0
+	for g := range 100 {
+		for d := range 100 {
+			dir := filepath.Join(root, fmt.Sprintf("group-%d", g), fmt.Sprintf("dir-%d", d))
+			if d%10 == 0 {
+				writeFile(b, filepath.Join(dir, "go.mod"), "module bench\n")
+				projects++
+				continue
+			}
+			writeFile(b, filepath.Join(dir, "notes.txt"), "not a project\n")
+			if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	return root, projects
+}
+
+// BenchmarkScanDirectory measures a full scan of a tree with 10k directories
+func BenchmarkScanDirectory(b *testing.B) {
+	setupIntegrationDB(b)
+	root, want := benchTree(b)
+
+	for b.Loop() {
+		projects, err := ScanDirectory(context.Background(), root)
+		if err != nil {
+			b.Fatalf("ScanDirectory failed: %v", err)
+		}
+		if len(projects) != want {
+			b.Fatalf("Expected %d projects, got %d", want, len(projects))
+		}
+	}
+}
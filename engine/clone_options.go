@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// CloneOptions are the git clone options of clones and restores. The zero value clones the
+// whole history of every branch.
+type CloneOptions struct {
+	Depth        int    // Commits of history to fetch, 0 for all of them
+	Filter       string // Partial clone filter, e.g. "blob:none"
+	SingleBranch bool   // Fetch only the checked out branch (implied by a depth)
+}
+
+// DefaultCloneOptions are used when neither the project nor the clone_options config key
+// sets any: the latest commit only
+var DefaultCloneOptions = CloneOptions{Depth: 1}
+
+// ParseCloneOptions parses clone options written as comma-separated "depth=N", "full" (the
+// whole history), "filter=<spec>" and "single-branch", e.g. "filter=blob:none, single-branch".
+// An empty value returns ok false, meaning no options are set.
+func ParseCloneOptions(value string) (opts CloneOptions, ok bool, err error) {
+	for _, option := range strings.Split(value, ",") {
+		option = strings.TrimSpace(option)
+		name, arg, _ := strings.Cut(option, "=")
+		switch {
+		case option == "":
+			continue
+		case option == "full":
+			opts.Depth = 0
+		case option == "single-branch":
+			opts.SingleBranch = true
+		case name == "depth":
+			depth, err := strconv.Atoi(arg)
+			if err != nil || depth < 1 {
+				return CloneOptions{}, false, fmt.Errorf("invalid clone depth %q, expected a number of commits", arg)
+			}
+			opts.Depth = depth
+		case name == "filter":
+			if !validCloneFilter(arg) {
+				return CloneOptions{}, false, fmt.Errorf("invalid clone filter %q, expected blob:none, blob:limit=<size> or tree:0", arg)
+			}
+			opts.Filter = arg
+		default:
+			return CloneOptions{}, false, fmt.Errorf("unknown clone option %q, expected depth=N, full, filter=<spec> or single-branch", option)
+		}
+		ok = true
+	}
+	return opts, ok, nil
+}
+
+// cloneFilterPattern matches the partial clone filters offered: no blobs, blobs up to a size
+// (e.g. blob:limit=1m) and no trees
+var cloneFilterPattern = regexp.MustCompile(`^(blob:none|blob:limit=\d+[kmgKMG]?|tree:0)$`)
+
+// validCloneFilter reports whether spec is a partial clone filter git accepts for clones
+func validCloneFilter(spec string) bool {
+	return cloneFilterPattern.MatchString(spec)
+}
+
+// String formats the options as ParseCloneOptions reads them
+func (o CloneOptions) String() string {
+	var options []string
+	if o.Depth > 0 {
+		options = append(options, fmt.Sprintf("depth=%d", o.Depth))
+	} else {
+		options = append(options, "full")
+	}
+	if o.Filter != "" {
+		options = append(options, "filter="+o.Filter)
+	}
+	if o.SingleBranch {
+		options = append(options, "single-branch")
+	}
+	return strings.Join(options, ", ")
+}
+
+// args returns the options as git clone arguments
+func (o CloneOptions) args() []string {
+	var args []string
+	if o.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(o.Depth))
+	}
+	if o.Filter != "" {
+		args = append(args, "--filter="+o.Filter)
+	}
+	if o.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	return args
+}
+
+// GlobalCloneOptions returns the options of the clone_options config key, or
+// DefaultCloneOptions when it isn't set
+func GlobalCloneOptions() (CloneOptions, error) {
+	value, _ := db.GetConfig("clone_options")
+	opts, ok, err := ParseCloneOptions(value)
+	if err != nil {
+		return CloneOptions{}, fmt.Errorf("clone_options config key: %w", err)
+	}
+	if !ok {
+		return DefaultCloneOptions, nil
+	}
+	return opts, nil
+}
+
+// ProjectCloneOptions returns the options a project is restored with: its own when set,
+// the global ones otherwise
+func ProjectCloneOptions(project models.Project) (CloneOptions, error) {
+	opts, ok, err := ParseCloneOptions(project.CloneOptions)
+	if err != nil {
+		return CloneOptions{}, fmt.Errorf("clone options of %s: %w", project.Name, err)
+	}
+	if !ok {
+		return GlobalCloneOptions()
+	}
+	return opts, nil
+}
+
+// SetProjectCloneOptions sets the clone options a project is restored with, written as
+// ParseCloneOptions reads them. An empty value uses the global options again.
+func SetProjectCloneOptions(projectID uint, value string) error {
+	opts, ok, err := ParseCloneOptions(value)
+	if err != nil {
+		return err
+	}
+	if ok {
+		value = opts.String()
+	} else {
+		value = ""
+	}
+	return db.UpdateProjectCloneOptions(projectID, value)
+}
@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"devbase/db"
+	"devbase/logging"
+)
+
+// IssueURL is where crashes are reported
+const IssueURL = "https://github.com/maleesha-pramud/devbase/issues/new"
+
+// crashLogLines is how many lines of the log file go into a diagnostic bundle
+const crashLogLines = 500
+
+// sensitiveConfigParts mark config keys whose values are left out of diagnostic bundles
+var sensitiveConfigParts = []string{"token", "secret", "password", "_id", "_url"}
+
+var (
+	crashMu     sync.Mutex
+	crashBundle string // Bundle written by RecordCrash, empty before a crash
+)
+
+// RecordCrash writes a diagnostic bundle for a recovered panic, once per process, and
+// returns its path. Later panics, such as the re-panics of the same crash, return the
+// first bundle.
+func RecordCrash(value any, stack []byte) string {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	if crashBundle == "" {
+		path, err := WriteCrashBundle(value, stack)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write diagnostic bundle: %v\n", err)
+			return ""
+		}
+		crashBundle = path
+	}
+	return crashBundle
+}
+
+// CrashBundle returns the bundle written by RecordCrash, or "" when there was no crash
+func CrashBundle() string {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	return crashBundle
+}
+
+// WriteCrashBundle writes a zip with the panic and its stack trace, the end of the log file
+// and the config with secrets left out to the crashes directory in the data directory. A
+// nil stack is noted as printed to the terminal instead.
+func WriteCrashBundle(value any, stack []byte) (string, error) {
+	dataDir, err := db.DataDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(dataDir, "crashes")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create crash directory: %w", err)
+	}
+	path := filepath.Join(dir, "devbase-crash-"+time.Now().Format("20060102-150405")+".zip")
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range []struct {
+		name    string
+		content string
+	}{
+		{"crash.txt", crashReport(value, stack)},
+		{"devbase.log", recentLogLines()},
+		{"config.txt", sanitizedConfig()},
+	} {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return "", err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("failed to write diagnostic bundle: %w", err)
+	}
+	return path, nil
+}
+
+// crashReport describes the panic and the environment it happened in
+func crashReport(value any, stack []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "DevBase %s (%s, %s/%s)\n", Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Time: %s\n\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "panic: %v\n\n", value)
+	if stack == nil {
+		b.WriteString("The stack trace was printed to the terminal when DevBase exited.\n")
+	} else {
+		b.Write(stack)
+	}
+	return b.String()
+}
+
+// recentLogLines returns the end of the log file
+func recentLogLines() string {
+	path, err := logging.Path()
+	if err != nil {
+		return fmt.Sprintf("No log file: %v\n", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("Failed to read %s: %v\n", path, err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if len(lines) > crashLogLines {
+		lines = lines[len(lines)-crashLogLines:]
+	}
+	return strings.Join(lines, "")
+}
+
+// sanitizedConfig lists the config keys, leaving out the values of tokens, IDs and URLs
+func sanitizedConfig() string {
+	if db.DB == nil {
+		return "The database was not open.\n"
+	}
+	values, err := db.GetAllConfig()
+	if err != nil {
+		return fmt.Sprintf("Failed to read config: %v\n", err)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := values[key]
+		if value != "" && slices.ContainsFunc(sensitiveConfigParts, func(part string) bool { return strings.Contains(key, part) }) {
+			value = "[redacted]"
+		}
+		fmt.Fprintf(&b, "%s = %q\n", key, value)
+	}
+	return b.String()
+}
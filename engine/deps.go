@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// MissingCommandError is returned when a program DevBase runs isn't on PATH. It explains
+// what to do instead of the bare "executable file not found" of os/exec, which it wraps.
+type MissingCommandError struct {
+	Name     string // What the program is, e.g. "VS Code CLI"
+	Command  string // Executable looked up on PATH
+	Guidance string // How to fix it
+}
+
+func (e *MissingCommandError) Error() string {
+	return fmt.Sprintf("%s not found — %s", e.Name, e.Guidance)
+}
+
+func (e *MissingCommandError) Unwrap() error {
+	return exec.ErrNotFound
+}
+
+// Dependency is a program DevBase runs and whether it was found
+type Dependency struct {
+	Name    string
+	Command string
+	Path    string // Where it was found, empty when missing
+	Err     error  // *MissingCommandError when missing
+}
+
+// LookupGit returns a *MissingCommandError when git isn't on PATH. Without it projects
+// can't be cloned, restored, created or given worktrees.
+func LookupGit() error {
+	return lookupCommand("git", "git", "install git and make sure it is on PATH to clone, restore and create projects")
+}
+
+// LookupEditor returns a *MissingCommandError when the editor's command isn't on PATH, or
+// for URL editors the program opening URLs
+func LookupEditor(editor Editor) error {
+	if editor.URL {
+		return lookupCommand("URL opener '"+urlOpener()+"'", urlOpener(), "install it (xdg-utils on Linux) or set an editor command in settings")
+	}
+	name := editor.Name
+	if editor.Command == "code" {
+		name = "VS Code CLI"
+	}
+	return lookupCommand(name, editor.Command, fmt.Sprintf("install '%s' or set editor in settings", editor.Command))
+}
+
+// LookupTerminal returns a *MissingCommandError when the terminal's command isn't on PATH
+func LookupTerminal(terminal Terminal) error {
+	return lookupCommand("Terminal '"+terminal.Command+"'", terminal.Command, "install it or set terminal in settings")
+}
+
+// lookupCommand looks a command up on PATH
+func lookupCommand(name, command, guidance string) error {
+	if _, err := exec.LookPath(command); err != nil {
+		return &MissingCommandError{Name: name, Command: command, Guidance: guidance}
+	}
+	return nil
+}
+
+// CheckDependencies looks up git, the editor and the terminal projects are opened and run
+// with. A terminal without a command is skipped.
+func CheckDependencies(editor Editor, terminal Terminal) []Dependency {
+	editorCommand := editor.Command
+	if editor.URL {
+		editorCommand = urlOpener()
+	}
+	deps := []Dependency{
+		{Name: "git", Command: "git", Err: LookupGit()},
+		{Name: editor.Name, Command: editorCommand, Err: LookupEditor(editor)},
+	}
+	if terminal.Command != "" {
+		deps = append(deps, Dependency{Name: terminal.Name, Command: terminal.Command, Err: LookupTerminal(terminal)})
+	}
+	for i := range deps {
+		if deps[i].Err == nil {
+			deps[i].Path, _ = exec.LookPath(deps[i].Command)
+		}
+	}
+	return deps
+}
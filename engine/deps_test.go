@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestMissingCommands(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := EditorCommand(EditorByCommand("code"), t.TempDir())
+	var missing *MissingCommandError
+	if !errors.As(err, &missing) || !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("EditorCommand error = %v, want a MissingCommandError", err)
+	}
+	if want := "VS Code CLI not found — install 'code' or set editor in settings"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if _, err := TerminalCommand(TerminalByCommand("wt"), t.TempDir(), "make"); !errors.As(err, &missing) || missing.Command != "wt" {
+		t.Errorf("TerminalCommand error = %v, want wt to be missing", err)
+	}
+	if err := cloneWithAuthFallback(context.Background(), "https://example.com/a.git", filepath.Join(t.TempDir(), "a"), "", DefaultCloneOptions); !errors.As(err, &missing) || missing.Command != "git" {
+		t.Errorf("clone error = %v, want git to be missing", err)
+	}
+
+	deps := CheckDependencies(EditorByCommand("nvim"), Terminal{})
+	if len(deps) != 2 || deps[0].Err == nil || deps[1].Err == nil || deps[1].Name != "Neovim" {
+		t.Errorf("CheckDependencies = %+v, want git and Neovim missing and no terminal", deps)
+	}
+}
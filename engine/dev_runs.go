@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// portScanBytes is how much of the start of a run log is searched for the listening port
+const portScanBytes = 64 * 1024
+
+// portPatterns find the port in what dev servers print when they start listening, e.g.
+// "http://localhost:5173/", "Listening on 0.0.0.0:8080" or "started on port 3000"
+var portPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1?\]|\[::\]):(\d{2,5})\b`),
+	regexp.MustCompile(`(?i)\bport\s*[:=]?\s*(\d{2,5})\b`),
+}
+
+// DetectPort returns the first port a dev server reports listening on in its output, or 0
+func DetectPort(output string) int {
+	for _, pattern := range portPatterns {
+		if match := pattern.FindStringSubmatch(output); match != nil {
+			if port, err := strconv.Atoi(match[1]); err == nil && port > 0 && port <= 65535 {
+				return port
+			}
+		}
+	}
+	return 0
+}
+
+// trackDevRun records a run of a saved project, so the project shows as running until it
+// exits. It returns nil for runs that aren't recorded.
+func trackDevRun(opts RunOptions, pid int, logPath string, streamed bool) *models.DevRun {
+	if opts.Project.ID == 0 {
+		return nil
+	}
+	task := opts.Task
+	if task == "" {
+		task = opts.Command
+	}
+	run := &models.DevRun{
+		ProjectID: opts.Project.ID,
+		PID:       pid,
+		Task:      task,
+		EnvSource: opts.EnvSource,
+		Streamed:  streamed,
+		LogPath:   logPath,
+		StartedAt: time.Now(),
+	}
+	if err := db.AddDevRun(run); err != nil {
+		slog.Warn("Failed to record dev run", "project", opts.Project.Name, "err", err)
+		return nil
+	}
+	return run
+}
+
+// untrackDevRun forgets a run recorded by trackDevRun once it exited
+func untrackDevRun(run *models.DevRun) {
+	if run == nil {
+		return
+	}
+	if err := db.DeleteDevRun(run.ID); err != nil {
+		slog.Debug("Failed to forget dev run", "pid", run.PID, "err", err)
+	}
+}
+
+// ActiveDevRuns returns the recorded dev runs that are still going by project ID, newest
+// first. Runs whose process is gone, e.g. captured runs that ended after DevBase exited,
+// are forgotten, and ports are looked up in the logs of runs that have none yet.
+func ActiveDevRuns() (map[uint][]models.DevRun, error) {
+	runs, err := db.GetDevRuns()
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[uint][]models.DevRun)
+	for _, run := range runs {
+		if !processAlive(run.PID) {
+			untrackDevRun(&run)
+			continue
+		}
+		if run.Port == 0 {
+			if port := logPort(run.LogPath); port != 0 {
+				run.Port = port
+				if err := db.SetDevRunPort(run.ID, port); err != nil {
+					slog.Debug("Failed to save dev run port", "pid", run.PID, "err", err)
+				}
+			}
+		}
+		active[run.ProjectID] = append(active[run.ProjectID], run)
+	}
+	return active, nil
+}
+
+// logPort looks for the listening port at the start of a run log, below the command line
+func logPort(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, portScanBytes))
+	if err != nil {
+		return 0
+	}
+	// The command line itself may name a port, which isn't proof the server listens on it
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		data = data[i+1:]
+	}
+	return DetectPort(string(data))
+}
+
+// StopDevRun stops a dev run and the processes it started, killing them when they don't
+// exit within a few seconds, and waits for them to be gone
+func StopDevRun(run models.DevRun) error {
+	// Streamed runs of this process stop through their handle, which marks them stopped
+	streamedRuns.Lock()
+	var streamed *StreamedRun
+	for r := range streamedRuns.runs {
+		if r.PID == run.PID {
+			streamed = r
+		}
+	}
+	streamedRuns.Unlock()
+	if streamed != nil {
+		if err := streamed.Stop(); err != nil {
+			return err
+		}
+		<-streamed.Done()
+		return nil
+	}
+
+	if err := terminateProcessTree(run.PID); err != nil {
+		return err
+	}
+	for deadline := time.Now().Add(stopGracePeriod); processAlive(run.PID); time.Sleep(100 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			if err := killProcessTree(run.PID); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	untrackDevRun(&run)
+	return nil
+}
@@ -0,0 +1,25 @@
+package engine
+
+import "testing"
+
+// TestDetectPort tests finding the port in the output of common dev servers
+func TestDetectPort(t *testing.T) {
+	tests := []struct {
+		output string
+		want   int
+	}{
+		{"  VITE v5.0.0  ready in 300 ms\n  ➜  Local:   http://localhost:5173/", 5173},
+		{"Listening on 0.0.0.0:8080", 8080},
+		{"Server started on port 3000", 3000},
+		{"* Running on http://127.0.0.1:5000", 5000},
+		{"listening on [::]:4000", 4000},
+		{"PORT=9229 debugger", 9229},
+		{"compiled 42 modules in 1200ms", 0},
+		{"localhost:99999", 0},
+	}
+	for _, tt := range tests {
+		if got := DetectPort(tt.output); got != tt.want {
+			t.Errorf("DetectPort(%q) = %d, want %d", tt.output, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+)
+
+// devContainerFiles are the locations the Dev Containers spec reads its config from
+var devContainerFiles = []string{
+	filepath.Join(".devcontainer", "devcontainer.json"),
+	".devcontainer.json",
+}
+
+// workspaceFolderPattern finds the "workspaceFolder" setting. devcontainer.json allows
+// comments and trailing commas, so it isn't parsed as strict JSON.
+var workspaceFolderPattern = regexp.MustCompile(`"workspaceFolder"\s*:\s*"([^"]+)"`)
+
+// DevContainerConfig returns the path of the project's devcontainer.json, or "" if it has none
+func DevContainerConfig(dir string) string {
+	for _, name := range devContainerFiles {
+		file := filepath.Join(dir, name)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+	return ""
+}
+
+// HasDevContainer reports whether the project defines a dev container
+func HasDevContainer(dir string) bool {
+	return DevContainerConfig(dir) != ""
+}
+
+// DevContainerAvailable reports whether the devcontainer CLI is installed on PATH
+func DevContainerAvailable() bool {
+	_, err := exec.LookPath("devcontainer")
+	return err == nil
+}
+
+// DevContainerURI builds the folder URI VS Code's Dev Containers extension opens. The
+// authority is the hex-encoded host folder and the path is the folder inside the container,
+// "/workspaces/<name>" unless devcontainer.json sets workspaceFolder.
+func DevContainerURI(dir string) string {
+	workspace := path.Join("/workspaces", filepath.Base(dir))
+	if content, err := os.ReadFile(DevContainerConfig(dir)); err == nil {
+		if match := workspaceFolderPattern.FindSubmatch(content); match != nil {
+			workspace = string(match[1])
+		}
+	}
+	return "vscode-remote://dev-container+" + hex.EncodeToString([]byte(dir)) + workspace
+}
+
+// DevContainerCommand builds the command that opens a project in its dev container. Editors
+// with Dev Containers support (VS Code, Cursor, Windsurf) open the container folder directly;
+// otherwise the devcontainer CLI builds and starts the container.
+func DevContainerCommand(editor Editor, dir string) (*exec.Cmd, error) {
+	if !HasDevContainer(dir) {
+		return nil, fmt.Errorf("no .devcontainer/devcontainer.json in %s", dir)
+	}
+	if editor.Workspace {
+		return exec.Command(editor.Command, "--folder-uri", DevContainerURI(dir)), nil
+	}
+	if DevContainerAvailable() {
+		return exec.Command("devcontainer", "up", "--workspace-folder", dir), nil
+	}
+	return nil, fmt.Errorf("%s can't open dev containers: choose VS Code, Cursor or Windsurf, or install the devcontainer CLI", editor.Name)
+}
@@ -0,0 +1,230 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"devbase/db"
+)
+
+// Editor describes an editor DevBase can open projects with
+type Editor struct {
+	Name      string // Display name
+	Command   string // Executable looked up on PATH, or the URL template of URL editors
+	Terminal  bool   // Runs inside the terminal (DevBase suspends while it is open)
+	Workspace bool   // Opens multi-root .code-workspace files
+	URL       bool   // Opens a URL built from Command with the system's URL handler (see EditorURL)
+}
+
+// DefaultEditorCommand is used when no "editor" config value is set
+const DefaultEditorCommand = "code"
+
+// knownEditors lists the editors detected on PATH, in display order
+var knownEditors = []Editor{
+	{Name: "VS Code", Command: "code", Workspace: true},
+	{Name: "Cursor", Command: "cursor", Workspace: true},
+	{Name: "Windsurf", Command: "windsurf", Workspace: true},
+	{Name: "Zed", Command: "zed"},
+	{Name: "Sublime Text", Command: "subl"},
+	{Name: "IntelliJ IDEA", Command: "idea"},
+	{Name: "GoLand", Command: "goland"},
+	{Name: "PyCharm", Command: "pycharm"},
+	{Name: "WebStorm", Command: "webstorm"},
+	{Name: "CLion", Command: "clion"},
+	{Name: "Rider", Command: "rider"},
+	{Name: "PhpStorm", Command: "phpstorm"},
+	{Name: "RubyMine", Command: "rubymine"},
+	{Name: "Neovim", Command: "nvim", Terminal: true},
+	{Name: "Vim", Command: "vim", Terminal: true},
+}
+
+// urlSchemeNames name the editors behind common URL schemes
+var urlSchemeNames = map[string]string{
+	"vscode":    "VS Code",
+	"cursor":    "Cursor",
+	"windsurf":  "Windsurf",
+	"zed":       "Zed",
+	"subl":      "Sublime Text",
+	"idea":      "IntelliJ IDEA",
+	"jetbrains": "JetBrains Toolbox",
+}
+
+// DetectEditors returns the known editors whose command is available on PATH
+func DetectEditors() []Editor {
+	var editors []Editor
+	for _, editor := range knownEditors {
+		if _, err := exec.LookPath(editor.Command); err == nil {
+			editors = append(editors, editor)
+		}
+	}
+	return editors
+}
+
+// EditorByCommand returns the editor for a command, treating unknown commands as GUI editors.
+// Commands with a scheme, such as vscode://file/{path}, are URL editors.
+func EditorByCommand(command string) Editor {
+	if command == "" {
+		command = DefaultEditorCommand
+	}
+	for _, editor := range knownEditors {
+		if editor.Command == command {
+			return editor
+		}
+	}
+	if scheme, _, ok := strings.Cut(command, "://"); ok && scheme != "" {
+		name := urlSchemeNames[strings.ToLower(scheme)]
+		if name == "" {
+			name = scheme
+		}
+		return Editor{Name: name + " (" + scheme + "://)", Command: command, URL: true}
+	}
+	return Editor{Name: command, Command: command}
+}
+
+// EditorURL fills in the URL template of a URL editor for a project directory. {path} is the
+// absolute path with forward slashes and without the leading slash, so vscode://file/{path}
+// works on every platform, and {name} is the directory name; both are escaped for URLs.
+func EditorURL(template, dir string) string {
+	segments := strings.Split(strings.TrimPrefix(filepath.ToSlash(dir), "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.NewReplacer(
+		"{path}", strings.Join(segments, "/"),
+		"{name}", url.QueryEscape(filepath.Base(dir)),
+	).Replace(template)
+}
+
+// urlOpener returns the program that opens URLs with the handler registered for their scheme
+func urlOpener() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "rundll32"
+	case "darwin":
+		return "open"
+	}
+	return "xdg-open"
+}
+
+// OpenURLCommand builds the command that opens a URL with the handler registered for its
+// scheme, such as an editor for vscode:// or the browser for https://
+func OpenURLCommand(u string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command(urlOpener(), "url.dll,FileProtocolHandler", u)
+	}
+	return exec.Command(urlOpener(), u)
+}
+
+// EditorCommand builds the command that opens a project directory in the editor
+func EditorCommand(editor Editor, path string) (*exec.Cmd, error) {
+	if editor.Command == "" {
+		return nil, fmt.Errorf("no editor command configured")
+	}
+	if err := LookupEditor(editor); err != nil {
+		return nil, err
+	}
+	if editor.URL {
+		return OpenURLCommand(EditorURL(editor.Command, path)), nil
+	}
+	cmd := exec.Command(editor.Command, path)
+	cmd.Dir = path
+	return cmd, nil
+}
+
+// EditorSessionCommand builds the command that opens several project directories at once.
+// Editors with workspace support get a multi-root workspace file named after the session,
+// other GUI editors receive all paths in a single invocation.
+func EditorSessionCommand(editor Editor, sessionName string, paths []string) (*exec.Cmd, error) {
+	if editor.Command == "" {
+		return nil, fmt.Errorf("no editor command configured")
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no projects to open")
+	}
+	if editor.Terminal || editor.URL {
+		return nil, fmt.Errorf("%s can't open several projects at once, choose a GUI editor", editor.Name)
+	}
+	if err := LookupEditor(editor); err != nil {
+		return nil, err
+	}
+
+	if editor.Workspace {
+		workspaceFile, err := WriteWorkspaceFile(sessionName, paths)
+		if err != nil {
+			return nil, err
+		}
+		return exec.Command(editor.Command, workspaceFile), nil
+	}
+	return exec.Command(editor.Command, paths...), nil
+}
+
+// WorkspaceFilePath returns where the .code-workspace file of a session is kept: the
+// workspaces directory in the DevBase config directory
+func WorkspaceFilePath(name string) (string, error) {
+	configDir, err := db.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "workspaces", sanitizeFileName(name)+".code-workspace"), nil
+}
+
+// WriteWorkspaceFile writes a multi-root .code-workspace file for the given project paths
+// to the DevBase config directory and returns its path. Settings and other keys the editor
+// saved in an existing file are kept; only its folders are replaced.
+func WriteWorkspaceFile(name string, paths []string) (string, error) {
+	file, err := WorkspaceFilePath(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+
+	type folder struct {
+		Path string `json:"path"`
+	}
+	folders := make([]folder, len(paths))
+	for i, path := range paths {
+		folders[i] = folder{Path: path}
+	}
+
+	// A file that isn't plain JSON, e.g. one with comments, is replaced
+	workspace := make(map[string]any)
+	if existing, err := os.ReadFile(file); err == nil {
+		if json.Unmarshal(existing, &workspace) != nil {
+			workspace = make(map[string]any)
+		}
+	}
+	workspace["folders"] = folders
+
+	data, err := json.MarshalIndent(workspace, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode workspace: %w", err)
+	}
+
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write workspace file: %w", err)
+	}
+	return file, nil
+}
+
+// sanitizeFileName replaces characters that aren't safe in file names
+func sanitizeFileName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "session"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestURLEditor(t *testing.T) {
+	editor := EditorByCommand("vscode://file/{path}?name={name}")
+	if !editor.URL || editor.Name != "VS Code (vscode://)" {
+		t.Errorf("Expected a VS Code URL editor, got %+v", editor)
+	}
+	if editor := EditorByCommand("myide://open?dir={path}"); !editor.URL || editor.Name != "myide (myide://)" {
+		t.Errorf("Expected an unknown scheme to be named after itself, got %+v", editor)
+	}
+	if EditorByCommand("code").URL {
+		t.Error("Expected code to stay a command")
+	}
+
+	if got, want := EditorURL(editor.Command, "/home/me/my app"), "vscode://file/home/me/my%20app?name=my+app"; got != want {
+		t.Errorf("EditorURL = %q, want %q", got, want)
+	}
+	if _, err := EditorSessionCommand(editor, "work", []string{"/a", "/b"}); err == nil {
+		t.Error("Expected URL editors to refuse opening several projects")
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	var missing *MissingCommandError
+	if _, err := EditorCommand(editor, "/code/api"); !errors.As(err, &missing) || missing.Command != "xdg-open" {
+		t.Errorf("EditorCommand error = %v, want xdg-open to be missing", err)
+	}
+	writeFile(t, filepath.Join(bin, "xdg-open"), "#!/bin/sh\n")
+	if err := os.Chmod(filepath.Join(bin, "xdg-open"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := EditorCommand(editor, "/code/api")
+	if err != nil {
+		t.Fatalf("EditorCommand failed: %v", err)
+	}
+	if want := []string{"xdg-open", "vscode://file/code/api?name=api"}; !slices.Equal(cmd.Args, want) {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Modes of the "run_env" config key, choosing where project runs get extra variables from
+const (
+	EnvModeOff    = "off"    // Run with DevBase's own environment
+	EnvModeAuto   = "auto"   // direnv when the project has an .envrc and direnv is installed, .env otherwise
+	EnvModeDotEnv = "dotenv" // Only the project's .env file
+	EnvModeDirenv = "direnv" // Only direnv
+)
+
+// Environment sources reported to the user
+const (
+	EnvSourceDotEnv = ".env"
+	EnvSourceDirenv = "direnv"
+)
+
+// DetectEnvSource returns the environment source a run in dir would use for the mode,
+// or "" when there is none
+func DetectEnvSource(dir, mode string) string {
+	hasFile := func(name string) bool {
+		info, err := os.Stat(filepath.Join(dir, name))
+		return err == nil && !info.IsDir()
+	}
+	_, direnvErr := exec.LookPath("direnv")
+	direnv := direnvErr == nil && hasFile(".envrc")
+
+	switch mode {
+	case EnvModeAuto:
+		if direnv {
+			return EnvSourceDirenv
+		}
+		if hasFile(".env") {
+			return EnvSourceDotEnv
+		}
+	case EnvModeDotEnv:
+		if hasFile(".env") {
+			return EnvSourceDotEnv
+		}
+	case EnvModeDirenv:
+		if direnv {
+			return EnvSourceDirenv
+		}
+	}
+	return ""
+}
+
+// LoadProjectEnv returns the "KEY=value" variables the source adds for a run in dir.
+// Variables direnv would unset are left alone.
+func LoadProjectEnv(dir, source string) ([]string, error) {
+	switch source {
+	case "":
+		return nil, nil
+	case EnvSourceDotEnv:
+		content, err := os.ReadFile(filepath.Join(dir, ".env"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .env: %w", err)
+		}
+		return ParseDotEnv(string(content)), nil
+	case EnvSourceDirenv:
+		return direnvEnv(dir)
+	}
+	return nil, fmt.Errorf("unknown environment source %q", source)
+}
+
+// ParseDotEnv parses .env content into "KEY=value" pairs in file order. It accepts
+// "export " prefixes, comments, and single- or double-quoted values; double-quoted
+// values expand \n escapes. Later assignments of a key win.
+func ParseDotEnv(content string) []string {
+	var vars []string
+	index := make(map[string]int)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			// Unquoted values end at an inline comment
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		if i, seen := index[key]; seen {
+			vars[i] = key + "=" + value
+			continue
+		}
+		index[key] = len(vars)
+		vars = append(vars, key+"="+value)
+	}
+	return vars
+}
+
+// direnvEnv asks direnv for the variables of dir's .envrc. direnv refuses .envrc files
+// that weren't approved with "direnv allow", which is reported as an error.
+func direnvEnv(dir string) ([]string, error) {
+	cmd := exec.Command("direnv", "export", "json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			lines := strings.Split(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+			return nil, fmt.Errorf("direnv: %s", strings.TrimPrefix(lines[len(lines)-1], "direnv: "))
+		}
+		return nil, fmt.Errorf("failed to run direnv: %w", err)
+	}
+
+	// Empty output means the environment is already up to date
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	var exported map[string]*string
+	if err := json.Unmarshal(output, &exported); err != nil {
+		return nil, fmt.Errorf("failed to parse direnv output: %w", err)
+	}
+
+	var vars []string
+	for key, value := range exported {
+		// direnv bookkeeping variables only matter to its shell hook
+		if value == nil || strings.HasPrefix(key, "DIRENV_") {
+			continue
+		}
+		vars = append(vars, key+"="+*value)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
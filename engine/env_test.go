@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParseDotEnv tests parsing .env files: quoting, comments, export prefixes, blank and
+// malformed lines
+func TestParseDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"plain", "PORT=3000\nHOST=localhost\n", []string{"PORT=3000", "HOST=localhost"}},
+		{"spaces around", "  PORT = 3000  \n", []string{"PORT=3000"}},
+		{"empty value", "TOKEN=\n", []string{"TOKEN="}},
+		{"equals in value", "URL=postgres://db?sslmode=off\n", []string{"URL=postgres://db?sslmode=off"}},
+		{"export prefix", "export PORT=3000\n", []string{"PORT=3000"}},
+		{"double quotes", `GREETING="hello # world"`, []string{"GREETING=hello # world"}},
+		{"double quote escapes", `MSG="line1\nline2 \"quoted\" C:\\dir"`, []string{"MSG=line1\nline2 \"quoted\" C:\\dir"}},
+		{"single quotes are literal", `RAW='a\nb # c'`, []string{`RAW=a\nb # c`}},
+		{"unbalanced quote kept", `NAME="open`, []string{`NAME="open`}},
+		{"comment lines", "# settings\n  # indented\nPORT=3000\n", []string{"PORT=3000"}},
+		{"inline comment", "PORT=3000 # dev server\nCOLOR=#fff\n", []string{"PORT=3000", "COLOR=#fff"}},
+		{"blank lines and CRLF", "\r\n\nPORT=3000\r\n\r\nHOST=localhost\r\n", []string{"PORT=3000", "HOST=localhost"}},
+		{"malformed lines", "no equals\n=value\nMY KEY=1\nOK=1\n", []string{"OK=1"}},
+		{"later assignment wins in place", "A=1\nB=2\nA=3\n", []string{"A=3", "B=2"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseDotEnv(tt.content); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ParseDotEnv(%q) = %q, want %q", tt.name, tt.content, got, tt.want)
+		}
+	}
+}
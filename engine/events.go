@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// Lifecycle event kinds delivered to hooks
+const (
+	EventArchive    = "archive"     // A project was archived
+	EventRestore    = "restore"     // An archived project was restored
+	EventSyncFailed = "sync_failed" // Pushing projects to the cloud failed
+	EventStale      = "stale"       // A project's directory was found missing
+	EventTest       = "test"        // Sent by "devbase webhook test"
+	EventOpen       = "open"        // A project was opened, delivered to plugins only
+	EventScan       = "scan"        // A root folder scan finished, delivered to plugins only
+)
+
+// EventKinds lists the event kinds webhooks can subscribe to
+var EventKinds = []string{EventArchive, EventRestore, EventSyncFailed, EventStale}
+
+// Event is a lifecycle event emitted to hooks
+type Event struct {
+	Kind    string    `json:"event"`
+	Project string    `json:"project,omitempty"`
+	Path    string    `json:"path,omitempty"`
+	Detail  string    `json:"detail,omitempty"` // Error of a failed sync, or other context
+	Time    time.Time `json:"time"`
+}
+
+// ProjectEvent creates an event about a project
+func ProjectEvent(kind string, project models.Project, detail string) Event {
+	return Event{Kind: kind, Project: project.Name, Path: project.Path, Detail: detail, Time: time.Now()}
+}
+
+// Message describes the event in one line for chat notifications
+func (e Event) Message() string {
+	var msg string
+	switch e.Kind {
+	case EventArchive:
+		msg = fmt.Sprintf("Archived %s (%s)", e.Project, e.Path)
+	case EventRestore:
+		msg = fmt.Sprintf("Restored %s (%s)", e.Project, e.Path)
+	case EventSyncFailed:
+		msg = "Cloud sync failed"
+	case EventStale:
+		msg = fmt.Sprintf("Directory of %s is missing (%s)", e.Project, e.Path)
+	case EventOpen:
+		msg = fmt.Sprintf("Opened %s (%s)", e.Project, e.Path)
+	case EventScan:
+		msg = "Scanned " + e.Path
+	case EventTest:
+		msg = "Test notification"
+	default:
+		msg = e.Kind
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return "DevBase: " + msg
+}
+
+// Hook receives emitted events
+type Hook func(Event) error
+
+var (
+	hooksMu sync.Mutex
+	hooks   = []Hook{deliverWebhooks, deliverPlugins, runScriptHooks}
+)
+
+// RegisterHook adds a hook that receives every event emitted from now on
+func RegisterHook(hook Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// Emit delivers the event to every hook, configured webhooks included. Failures are logged
+// and returned joined. Hooks are run synchronously, so call it from background work.
+func Emit(event Event) error {
+	hooksMu.Lock()
+	current := slices.Clone(hooks)
+	hooksMu.Unlock()
+
+	var errs []error
+	for _, hook := range current {
+		if err := hook(event); err != nil {
+			slog.Warn("Event delivery failed", "event", event.Kind, "project", event.Project, "err", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// webhookClient posts webhooks, with a timeout so an unreachable endpoint can't stall DevBase
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// deliverWebhooks posts the event to the webhooks subscribed to its kind
+func deliverWebhooks(event Event) error {
+	// Frequent events like opens would flood chat channels
+	if !slices.Contains(EventKinds, event.Kind) {
+		return nil
+	}
+	webhooks, err := db.GetWebhooks()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		if len(webhook.Events) > 0 && !slices.Contains(webhook.Events, event.Kind) {
+			continue
+		}
+		if err := SendWebhook(webhook, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendWebhook posts the event to one webhook in its format
+func SendWebhook(webhook models.Webhook, event Event) error {
+	var payload any
+	switch webhook.Format {
+	case models.WebhookSlack:
+		payload = map[string]string{"text": event.Message()}
+	case models.WebhookDiscord:
+		payload = map[string]string{"content": event.Message()}
+	default:
+		payload = event
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	resp, err := webhookClient.Post(webhook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s failed: %w", webhook.URL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", webhook.URL, resp.Status)
+	}
+	return nil
+}
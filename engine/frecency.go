@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// FrecencyEntry is a directory with its score from a jump tool such as zoxide or autojump
+type FrecencyEntry struct {
+	Path  string
+	Score float64
+}
+
+// ReadZoxide returns the directories known to zoxide with their scores.
+// zoxide's database is a binary format, so the entries are read through its CLI.
+func ReadZoxide() ([]FrecencyEntry, error) {
+	if _, err := exec.LookPath("zoxide"); err != nil {
+		return nil, fmt.Errorf("zoxide is not installed or not on PATH")
+	}
+
+	output, err := exec.Command("zoxide", "query", "--list", "--score").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query zoxide: %w", err)
+	}
+	return parseZoxide(output)
+}
+
+// parseZoxide parses the output of "zoxide query --list --score". Each line is the score
+// followed by the path, e.g. "  24.0 /home/me/code/app"; other lines are skipped.
+func parseZoxide(output []byte) ([]FrecencyEntry, error) {
+	var entries []FrecencyEntry
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		scoreText, path, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		score, err := strconv.ParseFloat(scoreText, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, FrecencyEntry{Path: strings.TrimSpace(path), Score: score})
+	}
+	return entries, scanner.Err()
+}
+
+// autojumpDataFile returns the location of autojump's data file for this platform
+func autojumpDataFile() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "autojump", "autojump.txt"), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "autojump", "autojump.txt"), nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "autojump", "autojump.txt"), nil
+}
+
+// ReadAutojump returns the directories known to autojump with their weights
+func ReadAutojump() ([]FrecencyEntry, error) {
+	dataFile, err := autojumpDataFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate autojump data: %w", err)
+	}
+
+	data, err := os.ReadFile(dataFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read autojump data: %w", err)
+	}
+	return parseAutojump(string(data)), nil
+}
+
+// parseAutojump parses autojump.txt. Each line is the weight and the path separated by a tab;
+// other lines are skipped.
+func parseAutojump(data string) []FrecencyEntry {
+	var entries []FrecencyEntry
+	for _, line := range strings.Split(data, "\n") {
+		weightText, path, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		weight, err := strconv.ParseFloat(weightText, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, FrecencyEntry{Path: path, Score: weight})
+	}
+	return entries
+}
+
+// frecencyWindowDays is how far back imported scores reach when DevBase has no opens of its
+// own to compare them with
+const frecencyWindowDays = 30
+
+// ImportFrecency registers the entries that are project roots and seeds their usage from their
+// scores: the rounded score becomes the open count, and the score sets LastOpened within the
+// span of the existing opens (see frecencyWindow and frecencyLastOpened), so imported projects
+// sort among the ones opened from DevBase instead of all above them.
+func ImportFrecency(entries []FrecencyEntry) (ImportResult, error) {
+	projects, err := db.GetProjects()
+	if err != nil {
+		return ImportResult{}, err
+	}
+	oldest, newest := frecencyWindow(projects, time.Now())
+
+	var best float64
+	for _, entry := range entries {
+		best = max(best, entry.Score)
+	}
+	candidates := make([]importCandidate, len(entries))
+	for i, entry := range entries {
+		candidates[i] = importCandidate{
+			path:       entry.Path,
+			lastOpened: frecencyLastOpened(entry.Score, best, oldest, newest),
+			openCount:  int(math.Round(entry.Score)),
+		}
+	}
+	return importProjects(candidates)
+}
+
+// frecencyWindow returns the span imported scores are spread over: from the least to the most
+// recently opened of the projects opened from DevBase. With fewer than two, it ends at the one
+// or now and reaches back frecencyWindowDays.
+func frecencyWindow(projects []models.Project, now time.Time) (oldest, newest time.Time) {
+	for _, project := range projects {
+		// LastOpened of a project that was never opened is the time it was added
+		if project.OpenCount == 0 || project.LastOpened.IsZero() {
+			continue
+		}
+		if newest.IsZero() || project.LastOpened.After(newest) {
+			newest = project.LastOpened
+		}
+		if oldest.IsZero() || project.LastOpened.Before(oldest) {
+			oldest = project.LastOpened
+		}
+	}
+	if newest.IsZero() {
+		newest = now
+	}
+	if oldest.IsZero() || !oldest.Before(newest) {
+		oldest = newest.AddDate(0, 0, -frecencyWindowDays)
+	}
+	return oldest, newest
+}
+
+// frecencyLastOpened places a score within the window: the best score at newest and the others
+// by their share of it. Jump tools add to a score on every visit, so the share is taken on a
+// log scale, keeping one heavily used directory from pushing all others to oldest.
+func frecencyLastOpened(score, best float64, oldest, newest time.Time) time.Time {
+	if best <= 0 || score <= 0 {
+		return oldest
+	}
+	share := min(1, math.Log1p(score)/math.Log1p(best))
+	return oldest.Add(time.Duration(share * float64(newest.Sub(oldest))))
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"devbase/models"
+)
+
+// TestParseZoxide tests reading the score and path of each line of "zoxide query --score"
+func TestParseZoxide(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []FrecencyEntry
+	}{
+		{"padded scores", "  24.0 /home/me/code/app\n   3.5 /home/me/code/api\n", []FrecencyEntry{{"/home/me/code/app", 24}, {"/home/me/code/api", 3.5}}},
+		{"path with spaces", "  1.0 /home/me/My Projects/site\n", []FrecencyEntry{{"/home/me/My Projects/site", 1}}},
+		{"windows line endings", "12.25 C:\\code\\app\r\n", []FrecencyEntry{{"C:\\code\\app", 12.25}}},
+		{"no final newline", "8 /srv/app", []FrecencyEntry{{"/srv/app", 8}}},
+		{"blank and malformed lines", "\n   \n/home/me/no-score\nhigh /home/me/app\n2.0 /home/me/ok\n", []FrecencyEntry{{"/home/me/ok", 2}}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseZoxide([]byte(tt.output))
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseZoxide = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+// TestParseAutojump tests reading the weight and path of each line of autojump.txt
+func TestParseAutojump(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []FrecencyEntry
+	}{
+		{"tab separated", "24.49\t/home/me/code/app\n10.0\t/home/me/code/api\n", []FrecencyEntry{{"/home/me/code/app", 24.49}, {"/home/me/code/api", 10}}},
+		{"path with spaces", "6.0\t/home/me/My Projects/site\n", []FrecencyEntry{{"/home/me/My Projects/site", 6}}},
+		{"windows line endings", "3.0\tC:\\code\\app\r\n", []FrecencyEntry{{"C:\\code\\app", 3}}},
+		{"space instead of tab", "5.0 /home/me/code/app\n", nil},
+		{"blank and malformed lines", "\n\t\nweight\t/home/me/app\n1.5\t/home/me/ok\n", []FrecencyEntry{{"/home/me/ok", 1.5}}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		if got := parseAutojump(tt.data); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseAutojump = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestFrecencyLastOpened tests spreading imported scores over the span of the existing opens
+func TestFrecencyLastOpened(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	projects := []models.Project{
+		{Name: "recent", OpenCount: 4, LastOpened: now.Add(-day)},
+		{Name: "old", OpenCount: 1, LastOpened: now.Add(-11 * day)},
+		{Name: "never opened", LastOpened: now.Add(-100 * day)},
+	}
+	oldest, newest := frecencyWindow(projects, now)
+	if !oldest.Equal(now.Add(-11*day)) || !newest.Equal(now.Add(-day)) {
+		t.Errorf("frecencyWindow = %v..%v, want the span of the opened projects", oldest, newest)
+	}
+	if oldest, newest := frecencyWindow(projects[2:], now); !oldest.Equal(now.AddDate(0, 0, -frecencyWindowDays)) || !newest.Equal(now) {
+		t.Errorf("frecencyWindow without opens = %v..%v, want the last %d days", oldest, newest, frecencyWindowDays)
+	}
+	if oldest, newest := frecencyWindow(projects[:1], now); !oldest.Equal(now.Add(-day).AddDate(0, 0, -frecencyWindowDays)) || !newest.Equal(now.Add(-day)) {
+		t.Errorf("frecencyWindow with one open = %v..%v, want %d days up to it", oldest, newest, frecencyWindowDays)
+	}
+
+	tests := []struct {
+		score, best float64
+		want        time.Time
+	}{
+		{99, 99, newest},
+		{0, 99, oldest},
+		{-1, 99, oldest},
+		{9, 99, oldest.Add(5 * day)}, // log(10)/log(100) is half the window
+		{5, 0, oldest},
+	}
+	for _, tt := range tests {
+		if got := frecencyLastOpened(tt.score, tt.best, oldest, newest); !got.Equal(tt.want) {
+			t.Errorf("frecencyLastOpened(%v, %v) = %v, want %v", tt.score, tt.best, got, tt.want)
+		}
+	}
+}
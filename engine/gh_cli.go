@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GHCLIAvailable reports whether the GitHub CLI (gh) is installed on PATH
+func GHCLIAvailable() bool {
+	_, err := exec.LookPath("gh")
+	return err == nil
+}
+
+// GHCLIToken returns the token the GitHub CLI is authenticated with for github.com
+func GHCLIToken() (string, error) {
+	output, err := exec.Command("gh", "auth", "token", "--hostname", "github.com").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("gh is not authenticated: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("failed to run gh auth token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("gh is not authenticated, run 'gh auth login' first")
+	}
+	return token, nil
+}
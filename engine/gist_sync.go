@@ -1,16 +1,19 @@
 package engine
 
 import (
-	"bytes"
 	"devbase/db"
 	"devbase/models"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 )
 
+// ErrNoCloudBackup is returned when no gist has been created for the root folder yet
+var ErrNoCloudBackup = errors.New("no cloud backup found. Please sync to cloud first")
+
 // GistClient handles GitHub Gist operations
 type GistClient struct {
 	Token        string // GitHub token
@@ -44,38 +47,12 @@ func NewGistClient(token string, rootFolderID uint) (*GistClient, error) {
 
 // ValidateToken checks if the GitHub token is valid by making a test API call
 func (c *GistClient) ValidateToken() error {
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return fmt.Errorf("failed to create validation request: %w", err)
-	}
-
-	// Support both OAuth tokens (Bearer) and PATs (token)
-	// Try Bearer first (OAuth), then fall back to token (PAT)
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to validate token: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 401 {
-		return fmt.Errorf("invalid GitHub token")
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("GitHub API error during token validation: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.api().ValidateToken()
 }
 
-// getAuthHeader returns the appropriate Authorization header value
-func (c *GistClient) getAuthHeader() string {
-	// OAuth tokens use Bearer, PATs use token
-	// We'll default to Bearer for new OAuth flow
-	return "Bearer " + c.Token
+// api returns the GitHub API client of the token
+func (c *GistClient) api() *GitHubClient {
+	return NewGitHubClient(c.Token)
 }
 
 // SaveToGist saves project data to a GitHub Gist
@@ -105,58 +82,41 @@ func (c *GistClient) SaveToGist(projects []models.Project) error {
 		filename = fmt.Sprintf("devbase_%s.json", sanitizedName)
 	}
 
+	// Possible secrets block the push or are redacted, as sync_secret_action says
+	payload, _, err := PrepareSyncPayload(projects)
+	if err != nil {
+		return err
+	}
+
+	// The backup carries a checksum, and a signature when a key is configured
+	content, err := EncodeBackup(payload, BackupSignKey())
+	if err != nil {
+		return err
+	}
+
 	// Prepare data for gist
 	data := map[string]interface{}{
 		"description": description,
 		"public":      false,
 		"files": map[string]interface{}{
 			filename: map[string]interface{}{
-				"content": c.projectsToJSON(projects),
+				"content": content,
 			},
 		},
 	}
 
 	// If gistID is provided, update existing gist
-	var url string
-	var method string
+	path, method := "/gists", "POST"
 	if c.GistID != "" {
-		url = fmt.Sprintf("https://api.github.com/gists/%s", c.GistID)
-		method = "PATCH"
-	} else {
-		url = "https://api.github.com/gists"
-		method = "POST"
-	}
-
-	// Create request
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		path, method = "/gists/"+c.GistID, "PATCH"
 	}
-
-	req.Header.Set("Authorization", c.getAuthHeader())
-	req.Header.Set("Content-Type", "application/json")
-
-	// Execute request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+	var gistResp struct {
+		ID string `json:"id"`
 	}
+	err = c.api().Send(method, path, data, &gistResp)
 
 	// Handle 404 - gist was deleted, create a new one
-	if resp.StatusCode == 404 && c.GistID != "" {
+	if GitHubStatus(err) == http.StatusNotFound && c.GistID != "" {
 		// Clear the old gist ID
 		c.GistID = ""
 		if c.RootFolderID > 0 {
@@ -172,108 +132,137 @@ func (c *GistClient) SaveToGist(projects []models.Project) error {
 		// Retry as a POST to create new gist
 		return c.SaveToGist(projects)
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("GitHub API error: %s", string(body))
+	if err != nil {
+		return err
 	}
 
-	// Parse response to get gist ID (only for new gists)
+	// Store the ID of a new gist from the response
 	if c.GistID == "" {
-		var gistResp struct {
-			ID string `json:"id"`
-		}
-		if err := json.Unmarshal(body, &gistResp); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
-		}
+		return c.storeGistID(gistResp.ID)
+	}
 
-		// Store the new gist ID
-		c.GistID = gistResp.ID
+	return nil
+}
 
-		// Save to root folder if specified, otherwise use old config method
-		if c.RootFolderID > 0 {
-			rootFolder, err := db.GetRootFolderByID(c.RootFolderID)
-			if err != nil {
-				return fmt.Errorf("failed to get root folder: %w", err)
-			}
-			rootFolder.GistID = c.GistID
-			if err := db.UpdateRootFolder(rootFolder); err != nil {
-				return fmt.Errorf("failed to save gist ID to root folder: %w", err)
-			}
-		} else {
-			// Backward compatibility: save to config
-			if err := db.SetConfig("gist_id", c.GistID); err != nil {
-				return fmt.Errorf("failed to save gist ID: %w", err)
-			}
+// storeGistID makes the gist the backup of the client's root folder
+func (c *GistClient) storeGistID(gistID string) error {
+	c.GistID = gistID
+
+	// Save to root folder if specified, otherwise use old config method
+	if c.RootFolderID > 0 {
+		rootFolder, err := db.GetRootFolderByID(c.RootFolderID)
+		if err != nil {
+			return fmt.Errorf("failed to get root folder: %w", err)
+		}
+		rootFolder.GistID = gistID
+		if err := db.UpdateRootFolder(rootFolder); err != nil {
+			return fmt.Errorf("failed to save gist ID to root folder: %w", err)
+		}
+	} else {
+		// Backward compatibility: save to config
+		if err := db.SetConfig("gist_id", gistID); err != nil {
+			return fmt.Errorf("failed to save gist ID: %w", err)
 		}
 	}
-
 	return nil
 }
 
-// LoadFromGist loads project data from a GitHub Gist
-func (c *GistClient) LoadFromGist() ([]models.Project, error) {
-	if c.GistID == "" {
-		return nil, fmt.Errorf("no cloud backup found. Please sync to cloud first")
-	}
+// CloudBackup is a gist of the user's that holds a DevBase backup
+type CloudBackup struct {
+	GistID      string
+	Description string // e.g. "DevBase: Work"
+	File        string // Name of the backup file in the gist
+	UpdatedAt   time.Time
+}
 
-	url := fmt.Sprintf("https://api.github.com/gists/%s", c.GistID)
+// isBackupFile reports whether a gist file is a DevBase backup: devbase_projects.json, or
+// devbase_<root folder>.json for the backup of a named root folder
+func isBackupFile(name string) bool {
+	return strings.HasPrefix(name, "devbase_") && strings.HasSuffix(name, ".json")
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// FindBackups lists the user's gists holding a DevBase backup, most recently updated first.
+// A machine without a stored gist ID adopts one of them with AdoptBackup instead of having to
+// push first.
+func (c *GistClient) FindBackups() ([]CloudBackup, error) {
+	type gist struct {
+		ID          string              `json:"id"`
+		Description string              `json:"description"`
+		UpdatedAt   time.Time           `json:"updated_at"`
+		Files       map[string]struct{} `json:"files"`
+	}
+	gists, err := GitHubGetAll[gist](c.api(), "/gists?per_page=100")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to list gists: %w", err)
 	}
 
-	req.Header.Set("Authorization", c.getAuthHeader())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	var backups []CloudBackup
+	for _, g := range gists {
+		var files []string
+		for name := range g.Files {
+			if isBackupFile(name) {
+				files = append(files, name)
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+		// The standard file name comes first, like LoadFromGist looks for it first
+		slices.Sort(files)
+		file := files[0]
+		if slices.Contains(files, "devbase_projects.json") {
+			file = "devbase_projects.json"
+		}
+		backups = append(backups, CloudBackup{GistID: g.ID, Description: g.Description, File: file, UpdatedAt: g.UpdatedAt})
 	}
-	defer resp.Body.Close()
+	slices.SortStableFunc(backups, func(a, b CloudBackup) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	return backups, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// AdoptBackup makes a gist found by FindBackups the backup of the client's root folder, so
+// loading reads it and pushing updates it
+func (c *GistClient) AdoptBackup(gistID string) error {
+	return c.storeGistID(gistID)
+}
+
+// LoadFromGist loads project data from a GitHub Gist. Paths are rewritten for this machine
+// with MapCloudPaths, so a backup pushed from another OS points to local locations.
+func (c *GistClient) LoadFromGist() ([]models.Project, error) {
+	if c.GistID == "" {
+		return nil, ErrNoCloudBackup
 	}
 
-	if resp.StatusCode == 404 {
+	var gistResp struct {
+		Files map[string]gistFile `json:"files"`
+	}
+	err := c.api().Get("/gists/"+c.GistID, &gistResp)
+	if GitHubStatus(err) == http.StatusNotFound {
 		// Gist was deleted, clear the stored ID
 		c.GistID = ""
 		db.SetConfig("gist_id", "")
 		return nil, fmt.Errorf("cloud backup not found (gist may have been deleted). Please sync to cloud first")
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("GitHub API error: %s", string(body))
-	}
-
-	// Parse gist response
-	var gistResp struct {
-		Files map[string]struct {
-			Content string `json:"content"`
-		} `json:"files"`
-	}
-
-	if err := json.Unmarshal(body, &gistResp); err != nil {
-		return nil, fmt.Errorf("failed to parse gist response: %w", err)
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract project data from the gist file
 	// Try to find the file - it could be named either "devbase_projects.json"
 	// or "devbase_<rootfolder>.json"
-	var fileContent string
+	var file gistFile
 	var found bool
 
 	// First try the standard filename
-	if file, exists := gistResp.Files["devbase_projects.json"]; exists {
-		fileContent = file.Content
+	if f, exists := gistResp.Files["devbase_projects.json"]; exists {
+		file = f
 		found = true
 	} else {
 		// Try to find any file that starts with "devbase_" and ends with ".json"
-		for filename, file := range gistResp.Files {
-			if strings.HasPrefix(filename, "devbase_") && strings.HasSuffix(filename, ".json") {
-				fileContent = file.Content
+		for filename, f := range gistResp.Files {
+			if isBackupFile(filename) {
+				file = f
 				found = true
 				break
 			}
@@ -284,25 +273,86 @@ func (c *GistClient) LoadFromGist() ([]models.Project, error) {
 		return nil, fmt.Errorf("no DevBase project file found in gist")
 	}
 
-	return c.jsonToProjects(fileContent)
+	fileContent, err := c.fileContent(file)
+	if err != nil {
+		return nil, err
+	}
+	projects, _, err := DecodeBackup(fileContent, BackupRequireSignature())
+	if err != nil {
+		return nil, fmt.Errorf("cloud backup: %w", err)
+	}
+	mappings, err := PathMappings()
+	if err != nil {
+		return nil, fmt.Errorf("path_map config key: %w", err)
+	}
+	var root string
+	if c.RootFolderID > 0 {
+		if rootFolder, err := db.GetRootFolderByID(c.RootFolderID); err == nil {
+			root = rootFolder.Path
+		}
+	}
+	MapCloudPaths(projects, mappings, root)
+	return projects, nil
 }
 
-// projectsToJSON converts projects slice to JSON string
-func (c *GistClient) projectsToJSON(projects []models.Project) string {
-	data, _ := json.MarshalIndent(projects, "", "  ")
-	return string(data)
+// gistFile is a file of a gist as the API returns it. The API cuts the content of large
+// files short and flags them as truncated; the full content is at RawURL.
+type gistFile struct {
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated"`
+	RawURL    string `json:"raw_url"`
 }
 
-// jsonToProjects converts JSON string to projects slice
-func (c *GistClient) jsonToProjects(jsonStr string) ([]models.Project, error) {
-	var projects []models.Project
-	if err := json.Unmarshal([]byte(jsonStr), &projects); err != nil {
-		return nil, fmt.Errorf("failed to parse projects JSON: %w", err)
+// fileContent returns the full content of a gist file, downloading truncated ones
+func (c *GistClient) fileContent(file gistFile) (string, error) {
+	if !file.Truncated {
+		return file.Content, nil
 	}
-	return projects, nil
+	resp, err := c.api().Do("GET", file.RawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the backup: %w", err)
+	}
+	return string(resp.Body), nil
 }
 
 // ListProjectsFromGist lists project names from a GitHub Gist without loading full data
 func (c *GistClient) ListProjectsFromGist() ([]models.Project, error) {
 	return c.LoadFromGist()
 }
+
+// StoreCloudProjects stores projects loaded from a cloud backup in a root folder. They are
+// marked as archived, since their files aren't on this machine yet; projects already stored
+// at the same path are updated. It returns how many were stored.
+func StoreCloudProjects(rootFolderID uint, projects []models.Project) int {
+	stored := 0
+	for _, project := range projects {
+		// Reset ID for new insertion and mark as archived. Worktree links refer to the IDs
+		// of the machine that synced, so worktrees come in as standalone projects.
+		project.ID = 0
+		project.ParentID = 0
+		project.Status = "archived"
+		project.RootFolderID = rootFolderID
+		// Failures on the machine that synced don't apply here
+		project.LastError = ""
+		project.LastErrorAt = time.Time{}
+
+		// Check if project already exists
+		if existing, err := db.GetProjectByPath(project.Path); err == nil {
+			// Update existing project, which keeps the machine it was registered on
+			project.ID = existing.ID
+			if existing.Machine != "" {
+				project.Machine = existing.Machine
+			}
+			if err := db.UpdateProject(&project); err != nil {
+				continue
+			}
+		} else {
+			// Add new project
+			if err := db.AddProject(&project); err != nil {
+				continue
+			}
+		}
+		stored++
+	}
+	return stored
+}
@@ -0,0 +1,313 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"devbase/db"
+)
+
+// Protocols a clone_protocol rule can ask for
+const (
+	ProtocolSSH   = "ssh"
+	ProtocolHTTPS = "https"
+)
+
+// GitAuth describes the git credentials available on this machine
+type GitAuth struct {
+	CredentialHelper string // credential.helper from git config, empty when none is set
+	SSHAgent         bool   // An SSH agent is running and holds at least one key
+	SSHKeys          []string
+}
+
+// DetectGitAuth inspects the git credential helper, the SSH agent and the default SSH keys
+func DetectGitAuth() GitAuth {
+	var auth GitAuth
+	if output, err := exec.Command("git", "config", "--get", "credential.helper").Output(); err == nil {
+		auth.CredentialHelper = strings.TrimSpace(string(output))
+	}
+
+	// ssh-add exits 0 when the agent has identities, 1 when it has none and 2 without an agent
+	if _, err := exec.LookPath("ssh-add"); err == nil {
+		auth.SSHAgent = exec.Command("ssh-add", "-l").Run() == nil
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			key := filepath.Join(home, ".ssh", name)
+			if _, err := os.Stat(key); err == nil {
+				auth.SSHKeys = append(auth.SSHKeys, key)
+			}
+		}
+	}
+	return auth
+}
+
+// PreferSSH reports whether SSH is more likely to succeed than HTTPS for private repositories
+func (a GitAuth) PreferSSH() bool {
+	return a.SSHAgent || (len(a.SSHKeys) > 0 && a.CredentialHelper == "")
+}
+
+// CanUseSSH reports whether any SSH credentials are available
+func (a GitAuth) CanUseSSH() bool {
+	return a.SSHAgent || len(a.SSHKeys) > 0
+}
+
+// httpsToSSH converts "https://host/owner/repo(.git)" to "git@host:owner/repo.git"
+func httpsToSSH(repoURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(repoURL, "https://")
+	if !ok {
+		return "", false
+	}
+	host, path, ok := strings.Cut(rest, "/")
+	// Drop credentials embedded in the URL, they don't apply to SSH
+	if _, after, found := strings.Cut(host, "@"); found {
+		host = after
+	}
+	if !ok || host == "" || path == "" {
+		return "", false
+	}
+	return fmt.Sprintf("git@%s:%s.git", host, strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")), true
+}
+
+// sshToHTTPS converts "git@host:owner/repo.git" or "ssh://git@host/owner/repo.git" to HTTPS
+func sshToHTTPS(repoURL string) (string, bool) {
+	var host, path string
+	if rest, ok := strings.CutPrefix(repoURL, "ssh://"); ok {
+		var found bool
+		if host, path, found = strings.Cut(rest, "/"); !found {
+			return "", false
+		}
+	} else if user, rest, ok := strings.Cut(repoURL, "@"); ok && !strings.Contains(user, "/") {
+		var found bool
+		if host, path, found = strings.Cut(rest, ":"); !found {
+			return "", false
+		}
+		host = user + "@" + host
+	} else {
+		return "", false
+	}
+
+	if _, after, found := strings.Cut(host, "@"); found {
+		host = after
+	}
+	// A port only applies to SSH
+	host, _, _ = strings.Cut(host, ":")
+	if host == "" || path == "" {
+		return "", false
+	}
+	return fmt.Sprintf("https://%s/%s", host, path), true
+}
+
+// ProtocolPreference is a rule of the clone_protocol config key: repositories on Host, or on
+// every host when Host is empty, are cloned over Protocol only
+type ProtocolPreference struct {
+	Host     string
+	Protocol string // ProtocolSSH or ProtocolHTTPS
+}
+
+// String formats the rule as it is written in the clone_protocol config key
+func (p ProtocolPreference) String() string {
+	if p.Host == "" {
+		return p.Protocol
+	}
+	return p.Host + "=" + p.Protocol
+}
+
+// ParseProtocolPreferences parses the clone_protocol config key: comma-separated
+// "host=protocol" rules, and a bare protocol for the other hosts (e.g. "github.com=ssh, https")
+func ParseProtocolPreferences(value string) ([]ProtocolPreference, error) {
+	var prefs []ProtocolPreference
+	for _, rule := range strings.Split(value, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		host, protocol, ok := strings.Cut(rule, "=")
+		if !ok {
+			host, protocol = "", host
+		}
+		host, protocol = strings.ToLower(strings.TrimSpace(host)), strings.ToLower(strings.TrimSpace(protocol))
+		if protocol != ProtocolSSH && protocol != ProtocolHTTPS {
+			return nil, fmt.Errorf("invalid clone protocol rule %q, expected \"host=ssh\", \"host=https\", \"ssh\" or \"https\"", rule)
+		}
+		if ok && host == "" {
+			return nil, fmt.Errorf("invalid clone protocol rule %q, the host is missing", rule)
+		}
+		prefs = append(prefs, ProtocolPreference{Host: host, Protocol: protocol})
+	}
+	return prefs, nil
+}
+
+// ProtocolPreferences returns the clone protocol rules of the clone_protocol config key
+func ProtocolPreferences() ([]ProtocolPreference, error) {
+	value, _ := db.GetConfig("clone_protocol")
+	return ParseProtocolPreferences(value)
+}
+
+// preferredProtocol returns the protocol the rules ask for on a repository's host, "" when
+// none applies. A rule naming the host wins over one for every host.
+func preferredProtocol(prefs []ProtocolPreference, repoURL string) string {
+	host := repoHost(repoURL)
+	protocol := ""
+	for _, pref := range prefs {
+		if pref.Host == host && host != "" {
+			return pref.Protocol
+		}
+		if pref.Host == "" {
+			protocol = pref.Protocol
+		}
+	}
+	return protocol
+}
+
+// repoHost returns the lower-case host of an HTTPS or SSH repository URL, "" for others
+func repoHost(repoURL string) string {
+	if httpsURL, ok := sshToHTTPS(repoURL); ok {
+		repoURL = httpsURL
+	}
+	rest, ok := strings.CutPrefix(repoURL, "https://")
+	if !ok {
+		return ""
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	if _, after, found := strings.Cut(host, "@"); found {
+		host = after
+	}
+	host, _, _ = strings.Cut(host, ":")
+	return strings.ToLower(host)
+}
+
+// rewriteProtocol returns the URL of a repository over protocol, converting between HTTPS
+// and SSH as needed
+func rewriteProtocol(repoURL, protocol string) (string, bool) {
+	_, isHTTPS := httpsToSSH(repoURL)
+	_, isSSH := sshToHTTPS(repoURL)
+	switch {
+	case protocol == ProtocolSSH && isSSH, protocol == ProtocolHTTPS && isHTTPS:
+		return repoURL, true
+	case protocol == ProtocolSSH && isHTTPS:
+		return httpsToSSH(repoURL)
+	case protocol == ProtocolHTTPS && isSSH:
+		return sshToHTTPS(repoURL)
+	}
+	return "", false
+}
+
+// CloneURLs returns the URLs to try when cloning a repository, most likely to succeed first.
+// When a clone_protocol rule applies to the repository's host, only its protocol is tried.
+// Otherwise the project's own URL is kept first unless the other protocol has credentials and
+// it does not.
+func CloneURLs(repoURL string, auth GitAuth, prefs []ProtocolPreference) []string {
+	if protocol := preferredProtocol(prefs, repoURL); protocol != "" {
+		if url, ok := rewriteProtocol(repoURL, protocol); ok {
+			return []string{url}
+		}
+	}
+
+	if sshURL, ok := httpsToSSH(repoURL); ok {
+		if !auth.CanUseSSH() {
+			return []string{repoURL}
+		}
+		if auth.PreferSSH() {
+			return []string{sshURL, repoURL}
+		}
+		return []string{repoURL, sshURL}
+	}
+
+	if httpsURL, ok := sshToHTTPS(repoURL); ok {
+		if !auth.CanUseSSH() {
+			return []string{httpsURL, repoURL}
+		}
+		return []string{repoURL, httpsURL}
+	}
+	return []string{repoURL}
+}
+
+// authErrorMarkers are substrings of git output that indicate missing or rejected credentials
+var authErrorMarkers = []string{
+	"authentication failed",
+	"could not read username",
+	"could not read password",
+	"terminal prompts disabled",
+	"permission denied (publickey",
+	"repository not found",
+	"host key verification failed",
+	"the requested url returned error: 403",
+	"the requested url returned error: 401",
+}
+
+// isAuthError reports whether git output describes an authentication failure. GitHub answers
+// "repository not found" for private repositories the credentials can't see.
+func isAuthError(output string) bool {
+	output = strings.ToLower(output)
+	for _, marker := range authErrorMarkers {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// GitAuthError is returned when every clone attempt failed because of credentials
+type GitAuthError struct {
+	RepoURL  string
+	Output   string // git output of the last attempt
+	Guidance string // What to set up so the clone succeeds
+}
+
+func (e *GitAuthError) Error() string {
+	return fmt.Sprintf("authentication failed for %s. %s Run 'devbase doctor' to check your setup.", e.RepoURL, e.Guidance)
+}
+
+// AuthGuidance explains how to get a private repository clone working with the given setup
+func AuthGuidance(auth GitAuth) string {
+	switch {
+	case auth.CredentialHelper == "" && !auth.CanUseSSH():
+		return "No git credentials found: configure a credential helper (e.g. 'gh auth setup-git' or Git Credential Manager) or add an SSH key with 'ssh-add'."
+	case auth.CredentialHelper == "":
+		return "HTTPS has no credential helper and the SSH key was rejected: run 'ssh-add' and check the key is added to your account, or run 'gh auth setup-git'."
+	case !auth.CanUseSSH():
+		return fmt.Sprintf("The '%s' credential helper has no valid login for this host: sign in again (e.g. 'gh auth login') or add an SSH key.", auth.CredentialHelper)
+	default:
+		return "Both HTTPS and SSH credentials were rejected: check that your account can access the repository."
+	}
+}
+
+// cloneWithAuthFallback clones with the URLs from CloneURLs, moving to the next one only when
+// git reports an authentication problem. Prompts are disabled so the TUI never hangs.
+func cloneWithAuthFallback(ctx context.Context, repoURL, destPath, ref string, opts CloneOptions) error {
+	if err := LookupGit(); err != nil {
+		return err
+	}
+	prefs, err := ProtocolPreferences()
+	if err != nil {
+		return fmt.Errorf("clone_protocol config key: %w", err)
+	}
+	auth := DetectGitAuth()
+	var lastOutput string
+	for _, url := range CloneURLs(repoURL, auth, prefs) {
+		output, err := runGitClone(ctx, url, destPath, ref, opts)
+		if err == nil {
+			return nil
+		}
+		_ = os.RemoveAll(destPath)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isAuthError(output) {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		lastOutput = output
+	}
+	guidance := AuthGuidance(auth)
+	if _, ok := rewriteProtocol(repoURL, preferredProtocol(prefs, repoURL)); ok {
+		protocol := preferredProtocol(prefs, repoURL)
+		guidance = fmt.Sprintf("The clone_protocol config key only allows %s for %s; check your %s credentials or change the rule.", protocol, repoHost(repoURL), protocol)
+	}
+	return &GitAuthError{RepoURL: repoURL, Output: lastOutput, Guidance: guidance}
+}
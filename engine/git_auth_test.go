@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestCloneProtocol tests the clone_protocol rules that pin the protocol clones use per host
+func TestCloneProtocol(t *testing.T) {
+	prefs, err := ParseProtocolPreferences(" GitHub.com=ssh , https ")
+	if err != nil || len(prefs) != 2 || prefs[0].String() != "github.com=ssh" || prefs[1].String() != "https" {
+		t.Fatalf("ParseProtocolPreferences = %v, %v", prefs, err)
+	}
+	for _, value := range []string{"github.com=ftp", "=ssh", "git"} {
+		if _, err := ParseProtocolPreferences(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+
+	// Credentials don't matter once a rule applies
+	auth := GitAuth{CredentialHelper: "manager"}
+	tests := []struct {
+		repoURL string
+		want    []string
+	}{
+		{"https://github.com/acme/api", []string{"git@github.com:acme/api.git"}},
+		{"git@github.com:acme/api.git", []string{"git@github.com:acme/api.git"}},
+		{"ssh://git@gitlab.com:2222/acme/web.git", []string{"https://gitlab.com/acme/web.git"}},
+		{"https://gitlab.com/acme/web.git", []string{"https://gitlab.com/acme/web.git"}},
+		{"/srv/git/local.git", []string{"/srv/git/local.git"}},
+	}
+	for _, tt := range tests {
+		if got := CloneURLs(tt.repoURL, auth, prefs); !slices.Equal(got, tt.want) {
+			t.Errorf("CloneURLs(%q) = %v, want %v", tt.repoURL, got, tt.want)
+		}
+	}
+	if got := CloneURLs("https://github.com/acme/api", auth, nil); !slices.Equal(got, []string{"https://github.com/acme/api"}) {
+		t.Errorf("Expected HTTPS only without rules and SSH keys, got %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// GitClient describes a graphical git client DevBase can open repositories in
+type GitClient struct {
+	Name    string   // Display name
+	Command string   // Executable found on PATH or at a known install location
+	Args    []string // Arguments placed before the repository path
+}
+
+// gitClientSpec describes where a git client is found on each platform
+type gitClientSpec struct {
+	name     string
+	commands []string // Launchers looked up on PATH
+	args     []string // Arguments before the repository path for the launcher
+	windows  []string // Globs below %LOCALAPPDATA% for Windows installs
+	winArgs  []string // Arguments before the repository path for the Windows executable
+	macApp   string   // Application bundle in /Applications, opened with "open -a"
+}
+
+// knownGitClients lists the git clients DevBase detects, in display order
+var knownGitClients = []gitClientSpec{
+	{name: "GitHub Desktop", commands: []string{"github", "github-desktop"}, windows: []string{`GitHubDesktop\bin\github.bat`}, macApp: "GitHub Desktop"},
+	{name: "GitKraken", commands: []string{"gitkraken"}, args: []string{"-p"}, windows: []string{`gitkraken\app-*\gitkraken.exe`}, winArgs: []string{"-p"}, macApp: "GitKraken"},
+	{name: "Fork", commands: []string{"fork"}, windows: []string{`Fork\Fork.exe`, `Fork\current\Fork.exe`}, macApp: "Fork"},
+	{name: "Sourcetree", commands: []string{"stree", "sourcetree"}, windows: []string{`SourceTree\SourceTree.exe`}, winArgs: []string{"-f"}, macApp: "Sourcetree"},
+}
+
+// DetectGitClients returns the known git clients installed on this system, found on PATH,
+// in their default Windows install locations or as macOS applications
+func DetectGitClients() []GitClient {
+	var clients []GitClient
+	for _, spec := range knownGitClients {
+		if client, ok := spec.detect(); ok {
+			clients = append(clients, client)
+		}
+	}
+	return clients
+}
+
+// detect resolves how to launch the client, preferring its command-line launcher
+func (s gitClientSpec) detect() (GitClient, bool) {
+	for _, command := range s.commands {
+		if path, err := exec.LookPath(command); err == nil {
+			return GitClient{Name: s.name, Command: path, Args: s.args}, true
+		}
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		base := os.Getenv("LOCALAPPDATA")
+		if base == "" {
+			return GitClient{}, false
+		}
+		for _, pattern := range s.windows {
+			matches, _ := filepath.Glob(filepath.Join(base, pattern))
+			if len(matches) == 0 {
+				continue
+			}
+			// Versioned install directories sort oldest first
+			sort.Strings(matches)
+			return GitClient{Name: s.name, Command: matches[len(matches)-1], Args: s.winArgs}, true
+		}
+	case "darwin":
+		if s.macApp == "" {
+			return GitClient{}, false
+		}
+		if _, err := os.Stat(filepath.Join("/Applications", s.macApp+".app")); err == nil {
+			args := []string{"-a", s.macApp}
+			if len(s.args) > 0 {
+				// Arguments reach the application itself, not open
+				args = append(append([]string{"-n"}, args...), append([]string{"--args"}, s.args...)...)
+			}
+			return GitClient{Name: s.name, Command: "open", Args: args}, true
+		}
+	}
+	return GitClient{}, false
+}
+
+// GitClientCommand builds the command that opens a repository in the git client
+func GitClientCommand(client GitClient, path string) (*exec.Cmd, error) {
+	if client.Command == "" {
+		return nil, fmt.Errorf("no git client command configured")
+	}
+	args := append(append([]string{}, client.Args...), path)
+	cmd := exec.Command(client.Command, args...)
+	cmd.Dir = path
+	return cmd, nil
+}
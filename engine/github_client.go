@@ -0,0 +1,437 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitHubAPI is the base URL of GitHub API paths; tests point it at a local server
+var GitHubAPI = "https://api.github.com"
+
+// Limits shared by every GitHubClient
+var (
+	// githubHTTP is the HTTP client of GitHub requests; its timeout covers each attempt
+	githubHTTP = &http.Client{Timeout: 30 * time.Second}
+
+	// githubRetries is how many times a request is retried after a network error, a 5xx
+	// response or a short rate limit, waiting githubRetryDelay and then twice as long each time
+	githubRetries    = 3
+	githubRetryDelay = time.Second
+
+	// githubMaxRateWait is the longest a request waits for a rate limit to reset before
+	// failing with ErrGitHubRateLimited
+	githubMaxRateWait = time.Minute
+
+	// githubSlots limits the GitHub requests in flight, so background fetches of many
+	// projects don't trip the secondary rate limits
+	githubSlots = make(chan struct{}, 4)
+)
+
+// ErrGitHubRateLimited is returned when GitHub's rate limit won't reset soon enough to wait
+var ErrGitHubRateLimited = errors.New("GitHub rate limit exceeded")
+
+// GitHubError is a GitHub API response with an error status
+type GitHubError struct {
+	StatusCode int
+	Message    string    // The API's error message, or the start of the body
+	Reset      time.Time // When the rate limit resets, for rate-limited responses
+}
+
+func (e *GitHubError) Error() string {
+	if !e.Reset.IsZero() {
+		return fmt.Sprintf("%v until %s", ErrGitHubRateLimited, e.Reset.Local().Format("15:04"))
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("GitHub API error: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("GitHub API error: %d - %s", e.StatusCode, e.Message)
+}
+
+// Is makes rate-limited responses match ErrGitHubRateLimited
+func (e *GitHubError) Is(target error) bool {
+	return target == ErrGitHubRateLimited && !e.Reset.IsZero()
+}
+
+// GitHubStatus returns the HTTP status of a GitHubError in err's chain, 0 otherwise
+func GitHubStatus(err error) int {
+	var ghErr *GitHubError
+	if errors.As(err, &ghErr) {
+		return ghErr.StatusCode
+	}
+	return 0
+}
+
+// GitHubClient makes GitHub requests with a token. It retries failed requests, waits out
+// short rate limits and answers repeated GETs from a cache revalidated with ETags, which
+// don't count against the rate limit.
+type GitHubClient struct {
+	Token string // Sent as a Bearer token (OAuth and personal access tokens alike), if set
+}
+
+// NewGitHubClient creates a GitHubClient for a token
+func NewGitHubClient(token string) *GitHubClient {
+	return &GitHubClient{Token: token}
+}
+
+// Get requests an API path (or a full URL) and decodes the JSON response into v
+func (c *GitHubClient) Get(path string, v any) error {
+	_, err := c.GetPage(path, v)
+	return err
+}
+
+// GetPage is Get for paginated lists; next is the URL of the following page from the Link
+// header, empty on the last page
+func (c *GitHubClient) GetPage(path string, v any) (next string, err error) {
+	resp, err := c.Do("GET", path, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Next, decodeGitHub(resp.Body, v)
+}
+
+// GitHubGetAll requests every page of a list, following the Link headers
+func GitHubGetAll[T any](c *GitHubClient, path string) ([]T, error) {
+	var all []T
+	for path != "" {
+		var page []T
+		next, err := c.GetPage(path, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		path = next
+	}
+	return all, nil
+}
+
+// Send sends body as JSON with a POST, PATCH or other method and decodes the JSON response
+// into v unless it is nil
+func (c *GitHubClient) Send(method, path string, body, v any) error {
+	resp, err := c.Do(method, path, body)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return decodeGitHub(resp.Body, v)
+}
+
+// GitHubResponse is a successful response read by GitHubClient.Do
+type GitHubResponse struct {
+	StatusCode int
+	Body       []byte
+	Next       string      // URL of the next page, from the Link header
+	Header     http.Header // For answers from the cache, the headers of the 304
+}
+
+// Do sends a request to an API path or a full URL, with body encoded as JSON unless it is
+// nil. Statuses outside 2xx are returned as a *GitHubError.
+func (c *GitHubClient) Do(method, path string, body any) (*GitHubResponse, error) {
+	target := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		target = GitHubAPI + path
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	githubSlots <- struct{}{}
+	defer func() { <-githubSlots }()
+
+	cacheKey := ""
+	if method == "GET" {
+		cacheKey = c.cacheKey(target)
+	}
+	resource := githubResource(target)
+	delay := githubRetryDelay
+	for attempt := 0; ; attempt++ {
+		if err := githubRate.wait(resource); err != nil {
+			return nil, err
+		}
+		resp, err := c.send(method, target, payload, cacheKey)
+		retry := attempt < githubRetries
+		if err != nil {
+			// POST isn't retried after network errors, which may have created something
+			if !retry || method == "POST" {
+				return nil, err
+			}
+			slog.Debug("Retrying GitHub request", "method", method, "url", target, "err", err)
+			time.Sleep(delay)
+			delay *= 2
+			continue
+		}
+
+		var ghErr *GitHubError
+		if !errors.As(resp.err, &ghErr) {
+			return &resp.GitHubResponse, nil
+		}
+		switch {
+		case !ghErr.Reset.IsZero():
+			if wait := time.Until(ghErr.Reset); retry && wait <= githubMaxRateWait {
+				slog.Info("Waiting for the GitHub rate limit", "wait", wait.Round(time.Second))
+				time.Sleep(max(wait, 0))
+				continue
+			}
+		case ghErr.StatusCode >= 500 && retry && method != "POST":
+			slog.Debug("Retrying GitHub request", "method", method, "url", target, "status", ghErr.StatusCode)
+			time.Sleep(delay)
+			delay *= 2
+			continue
+		}
+		return nil, ghErr
+	}
+}
+
+// githubAttempt is the outcome of one request
+type githubAttempt struct {
+	GitHubResponse
+	err error
+}
+
+// send makes one attempt of a request. GETs are conditional when the cache holds an ETag
+// for the URL; a 304 answers them from the cache.
+func (c *GitHubClient) send(method, target string, payload []byte, cacheKey string) (*githubAttempt, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if strings.HasPrefix(target, GitHubAPI) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	} else {
+		// The OAuth endpoints answer form-encoded unless JSON is asked for
+		req.Header.Set("Accept", "application/json")
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	cached, hasCached := githubCache.get(cacheKey)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := githubHTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	githubRate.update(resp.Header)
+
+	attempt := &githubAttempt{GitHubResponse: GitHubResponse{StatusCode: resp.StatusCode, Body: data, Next: nextPage(resp.Header.Get("Link")), Header: resp.Header}}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		attempt.StatusCode, attempt.Body, attempt.Next = http.StatusOK, cached.body, cached.next
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if etag := resp.Header.Get("ETag"); cacheKey != "" && etag != "" {
+			githubCache.put(cacheKey, githubCached{etag: etag, body: data, next: attempt.Next})
+		}
+	default:
+		attempt.err = githubError(resp, data)
+	}
+	return attempt, nil
+}
+
+// githubError builds the error of a failed response. Rate-limited responses (429, or 403
+// with no requests left or a Retry-After) get the time the limit resets.
+func githubError(resp *http.Response, data []byte) *GitHubError {
+	ghErr := &GitHubError{StatusCode: resp.StatusCode}
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+		ghErr.Message = apiErr.Message
+	} else {
+		ghErr.Message = strings.TrimSpace(string(data[:min(len(data), 200)]))
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return ghErr
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		ghErr.Reset = time.Now().Add(time.Duration(seconds) * time.Second)
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		ghErr.Reset = rateLimitReset(resp.Header)
+	} else if resp.StatusCode == http.StatusTooManyRequests {
+		ghErr.Reset = time.Now().Add(githubRetryDelay)
+	}
+	return ghErr
+}
+
+// decodeGitHub decodes a JSON response body
+func decodeGitHub(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	return nil
+}
+
+// linkNextPattern matches the next page of a Link header, e.g. <https://...&page=2>; rel="next"
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPage returns the URL of the next page from a Link header
+func nextPage(link string) string {
+	if m := linkNextPattern.FindStringSubmatch(link); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// githubResource returns the rate limit bucket of an API URL: search has its own, smaller
+// limit. Requests outside the API (OAuth, raw gist files) aren't tracked.
+func githubResource(target string) string {
+	path, ok := strings.CutPrefix(target, GitHubAPI)
+	switch {
+	case !ok:
+		return ""
+	case strings.HasPrefix(path, "/search/"):
+		return "search"
+	}
+	return "core"
+}
+
+// rateLimitReset reads X-RateLimit-Reset, the Unix time the limit resets
+func rateLimitReset(header http.Header) time.Time {
+	if seconds, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(seconds, 0)
+	}
+	return time.Now().Add(time.Minute)
+}
+
+// githubRate remembers the rate limits GitHub reported, so requests wait (or fail) before
+// being sent once none are left, instead of each being rejected
+var githubRate = &githubRateLimits{exhausted: make(map[string]time.Time)}
+
+type githubRateLimits struct {
+	mu        sync.Mutex
+	exhausted map[string]time.Time // Reset time of the resources without requests left
+}
+
+// update records the rate limit headers of a response
+func (r *githubRateLimits) update(header http.Header) {
+	resource := header.Get("X-RateLimit-Resource")
+	remaining := header.Get("X-RateLimit-Remaining")
+	if resource == "" || remaining == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if remaining == "0" {
+		r.exhausted[resource] = rateLimitReset(header)
+	} else {
+		delete(r.exhausted, resource)
+	}
+}
+
+// wait blocks until the resource's rate limit resets, or fails when that is further away
+// than githubMaxRateWait
+func (r *githubRateLimits) wait(resource string) error {
+	r.mu.Lock()
+	reset, ok := r.exhausted[resource]
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	wait := time.Until(reset)
+	if wait > githubMaxRateWait {
+		return &GitHubError{StatusCode: http.StatusForbidden, Reset: reset}
+	}
+	if wait > 0 {
+		slog.Info("Waiting for the GitHub rate limit", "resource", resource, "wait", wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+	r.mu.Lock()
+	delete(r.exhausted, resource)
+	r.mu.Unlock()
+	return nil
+}
+
+// githubCacheSize bounds the responses kept for conditional requests
+const githubCacheSize = 256
+
+// githubCache holds GET responses with their ETags, keyed by token and URL
+var githubCache = &githubResponseCache{entries: make(map[string]githubCached)}
+
+type githubCached struct {
+	etag string
+	body []byte
+	next string
+}
+
+type githubResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]githubCached
+}
+
+// cacheKey keys a URL by a hash of the token, so accounts never see each other's responses
+func (c *GitHubClient) cacheKey(target string) string {
+	sum := sha256.Sum256([]byte(c.Token))
+	return hex.EncodeToString(sum[:8]) + " " + target
+}
+
+func (g *githubResponseCache) get(key string) (githubCached, bool) {
+	if key == "" {
+		return githubCached{}, false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	entry, ok := g.entries[key]
+	return entry, ok
+}
+
+func (g *githubResponseCache) put(key string, entry githubCached) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.entries[key]; !ok && len(g.entries) >= githubCacheSize {
+		// Responses are cheap to fetch again; start over rather than track their age
+		clear(g.entries)
+	}
+	g.entries[key] = entry
+}
+
+// ValidateToken checks that GitHub accepts the token
+func (c *GitHubClient) ValidateToken() error {
+	_, err := c.User()
+	if GitHubStatus(err) == http.StatusUnauthorized {
+		return fmt.Errorf("invalid GitHub token")
+	}
+	return err
+}
+
+// User returns the login of the token's user
+func (c *GitHubClient) User() (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.Get("/user", &user); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGitHubClient(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Path]++
+		n := calls[r.URL.Path]
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		switch r.URL.Path {
+		case "/user":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		case "/flaky":
+			if n == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		case "/items":
+			if r.URL.Query().Get("page") != "2" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next", <%s/items?page=2>; rel="last"`, srv.URL, srv.URL))
+				fmt.Fprint(w, `[1,2]`)
+				return
+			}
+			fmt.Fprint(w, `[3]`)
+		case "/limited":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	defer srv.Close()
+
+	api, delay := GitHubAPI, githubRetryDelay
+	GitHubAPI, githubRetryDelay = srv.URL, time.Millisecond
+	t.Cleanup(func() {
+		GitHubAPI, githubRetryDelay = api, delay
+		githubRate = &githubRateLimits{exhausted: make(map[string]time.Time)}
+	})
+	client := NewGitHubClient("secret")
+
+	// Repeated GETs are revalidated with the ETag and answered from the cache
+	for range 2 {
+		if login, err := client.User(); err != nil || login != "octocat" {
+			t.Fatalf("User() = %q, %v; want octocat", login, err)
+		}
+	}
+	if calls["/user"] != 2 {
+		t.Errorf("/user requested %d times, want 2", calls["/user"])
+	}
+	if err := NewGitHubClient("wrong").ValidateToken(); err == nil || !strings.Contains(err.Error(), "invalid GitHub token") {
+		t.Errorf("ValidateToken with a bad token returned %v", err)
+	}
+
+	if err := client.Get("/flaky", &struct{}{}); err != nil || calls["/flaky"] != 2 {
+		t.Errorf("Get of a failing endpoint = %v after %d requests, want a retry to succeed", err, calls["/flaky"])
+	}
+
+	items, err := GitHubGetAll[int](client, "/items?page=1")
+	if err != nil || !slices.Equal(items, []int{1, 2, 3}) {
+		t.Errorf("GitHubGetAll = %v, %v; want [1 2 3]", items, err)
+	}
+
+	err = client.Get("/missing", &struct{}{})
+	if GitHubStatus(err) != http.StatusNotFound || calls["/missing"] != 1 {
+		t.Errorf("Get of a missing path = %v after %d requests, want one 404", err, calls["/missing"])
+	}
+
+	// Once the limit is exhausted, requests fail without reaching GitHub until it resets
+	if err := client.Get("/limited", &struct{}{}); !errors.Is(err, ErrGitHubRateLimited) {
+		t.Errorf("rate-limited Get returned %v, want ErrGitHubRateLimited", err)
+	}
+	if err := client.Get("/flaky", &struct{}{}); !errors.Is(err, ErrGitHubRateLimited) || calls["/flaky"] != 2 {
+		t.Errorf("Get after the limit ran out = %v after %d requests, want ErrGitHubRateLimited without a request", err, calls["/flaky"])
+	}
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"devbase/models"
+)
+
+// GitHubRepoFromURL returns "github.com/owner/name" for GitHub remote URLs in any form
+func GitHubRepoFromURL(repoURL string) (string, bool) {
+	ref, ok := ParseRepoURL(repoURL)
+	if !ok || ref.Provider != ProviderGitHub || strings.Count(ref.Path, "/") != 1 {
+		return "", false
+	}
+	return ref.Key(), true
+}
+
+// FetchGitHubRepoMetadata fetches the description, stars and open issue and pull request
+// counts of a "github.com/owner/name" repository
+func FetchGitHubRepoMetadata(token, repo string) (models.RepoMetadata, error) {
+	fullName, ok := strings.CutPrefix(repo, "github.com/")
+	if !ok {
+		return models.RepoMetadata{}, fmt.Errorf("not a GitHub repository: %s", repo)
+	}
+
+	var details struct {
+		Description     string `json:"description"`
+		StargazersCount int    `json:"stargazers_count"`
+		OpenIssuesCount int    `json:"open_issues_count"` // Includes pull requests
+	}
+	client := NewGitHubClient(token)
+	if err := client.Get("/repos/"+fullName, &details); err != nil {
+		return models.RepoMetadata{}, err
+	}
+
+	var pulls struct {
+		TotalCount int `json:"total_count"`
+	}
+	query := url.QueryEscape("repo:" + fullName + " is:pr is:open")
+	if err := client.Get("/search/issues?per_page=1&q="+query, &pulls); err != nil {
+		return models.RepoMetadata{}, err
+	}
+
+	return models.RepoMetadata{
+		Repo:        repo,
+		Description: details.Description,
+		Stars:       details.StargazersCount,
+		OpenIssues:  max(0, details.OpenIssuesCount-pulls.TotalCount),
+		OpenPRs:     pulls.TotalCount,
+		FetchedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"devbase/db"
+)
+
+// Where the saved GitHub token came from, kept in the github_token_source config key
+const (
+	TokenSourceOAuth = "oauth"
+	TokenSourcePAT   = "pat"
+	TokenSourceGHCLI = "gh"
+)
+
+// fineGrainedScopes is stored in the github_token_scopes config key for tokens without
+// OAuth scopes
+const fineGrainedScopes = "fine-grained"
+
+// TokenScopes are the OAuth scopes granted to a GitHub token
+type TokenScopes struct {
+	Scopes []string
+	// FineGrained is set for fine-grained personal access tokens and GitHub App tokens,
+	// which have permissions instead of scopes that the API doesn't report
+	FineGrained bool
+}
+
+// String lists the scopes for display
+func (s TokenScopes) String() string {
+	switch {
+	case s.FineGrained:
+		return "fine-grained token (permissions aren't reported)"
+	case len(s.Scopes) == 0:
+		return "no scopes"
+	}
+	return strings.Join(s.Scopes, ", ")
+}
+
+// usedScopes are the scopes DevBase uses: gist for cloud sync, repo or public_repo for
+// creating and listing repositories, read:org for organizations and read:user for the username
+var usedScopes = []string{"gist", "repo", "public_repo", "read:org", "read:user", "user:email"}
+
+// broadScopes explain scopes granting far more than DevBase uses
+var broadScopes = map[string]string{
+	"repo":            "full control of all your private repositories; only creating private repositories (i) and listing private ones need it, cloud sync needs just gist",
+	"delete_repo":     "can delete repositories, which DevBase never does",
+	"workflow":        "can change GitHub Actions workflows, which DevBase never does",
+	"user":            "can change your profile; DevBase only reads it",
+	"write:org":       "can manage your organizations; browsing them (O) needs read:org at most",
+	"admin:org":       "can administer your organizations; browsing them (O) needs read:org at most",
+	"write:packages":  "can publish packages, which DevBase never does",
+	"delete:packages": "can delete packages, which DevBase never does",
+}
+
+// FetchTokenScopes asks GitHub for the scopes of a token, which also checks that it works.
+// Classic tokens list them in the X-OAuth-Scopes header; other tokens don't send it.
+func FetchTokenScopes(token string) (TokenScopes, error) {
+	resp, err := NewGitHubClient(token).Do("GET", "/user", nil)
+	if GitHubStatus(err) == http.StatusUnauthorized {
+		return TokenScopes{}, fmt.Errorf("invalid GitHub token")
+	}
+	if err != nil {
+		return TokenScopes{}, err
+	}
+	values, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return TokenScopes{FineGrained: true}, nil
+	}
+	return TokenScopes{Scopes: parseScopes(strings.Join(values, ","))}, nil
+}
+
+// parseScopes splits a comma-separated scope list
+func parseScopes(list string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(list, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// SaveTokenScopes stores where the saved token came from and its scopes
+func SaveTokenScopes(source string, scopes TokenScopes) error {
+	if err := db.SetConfig("github_token_source", source); err != nil {
+		return err
+	}
+	value := strings.Join(scopes.Scopes, ",")
+	if scopes.FineGrained {
+		value = fineGrainedScopes
+	}
+	return db.SetConfig("github_token_scopes", value)
+}
+
+// SavedTokenScopes returns the scopes and source stored with SaveTokenScopes; ok is false
+// for tokens saved before scopes were recorded
+func SavedTokenScopes() (scopes TokenScopes, source string, ok bool) {
+	source, err := db.GetConfig("github_token_source")
+	if err != nil || source == "" {
+		return TokenScopes{}, "", false
+	}
+	value, _ := db.GetConfig("github_token_scopes")
+	if value == fineGrainedScopes {
+		return TokenScopes{FineGrained: true}, source, true
+	}
+	return TokenScopes{Scopes: parseScopes(value)}, source, true
+}
+
+// ScopeWarnings points out least-privilege problems of a token: scopes DevBase doesn't
+// need and a missing gist scope. Tokens from the OAuth flow get the scopes DevBase asks
+// for, so only tokens the user created or reused (PAT and GitHub CLI) are checked.
+func ScopeWarnings(scopes TokenScopes, source string) []string {
+	if source != TokenSourcePAT && source != TokenSourceGHCLI || scopes.FineGrained {
+		return nil
+	}
+	var warnings []string
+	if !slices.Contains(scopes.Scopes, "gist") {
+		warnings = append(warnings, "gist is missing, so cloud sync (u, l) will fail")
+	}
+	for _, scope := range scopes.Scopes {
+		switch reason, broad := broadScopes[scope]; {
+		case broad:
+			warnings = append(warnings, scope+": "+reason)
+		case strings.HasPrefix(scope, "admin:"):
+			warnings = append(warnings, scope+": administrative access, which DevBase never uses")
+		case !slices.Contains(usedScopes, scope):
+			warnings = append(warnings, scope+": not used by DevBase")
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// GitInfoTTL is how long git details of a directory are reused before they are read again
+const GitInfoTTL = time.Minute
+
+// GitInfo holds the git details of a project directory
+type GitInfo struct {
+	RemoteURL  string    // URL of the origin remote
+	Branch     string    // Checked out branch, empty when HEAD is detached or unborn
+	Dirty      bool      // The worktree has uncommitted changes
+	LastCommit time.Time // Author time of the HEAD commit, zero when there are no commits
+}
+
+// gitInfoEntry is a cached read of a directory, failed reads included so a directory that
+// isn't a repository isn't opened on every render
+type gitInfoEntry struct {
+	info GitInfo
+	err  error
+	read time.Time
+}
+
+var gitInfoCache = struct {
+	sync.Mutex
+	entries map[string]gitInfoEntry
+}{entries: make(map[string]gitInfoEntry)}
+
+// GetGitInfo returns the git details of the repository at dir. They are read once per
+// GitInfoTTL; opening or scanning a project invalidates them sooner.
+func GetGitInfo(dir string) (GitInfo, error) {
+	key := filepath.Clean(dir)
+	gitInfoCache.Lock()
+	entry, ok := gitInfoCache.entries[key]
+	gitInfoCache.Unlock()
+	if ok && time.Since(entry.read) < GitInfoTTL {
+		return entry.info, entry.err
+	}
+
+	info, err := readGitInfo(key)
+	gitInfoCache.Lock()
+	gitInfoCache.entries[key] = gitInfoEntry{info: info, err: err, read: time.Now()}
+	gitInfoCache.Unlock()
+	return info, err
+}
+
+// InvalidateGitInfo drops the cached details of the given directories, or of every
+// directory when none are given
+func InvalidateGitInfo(dirs ...string) {
+	gitInfoCache.Lock()
+	defer gitInfoCache.Unlock()
+	if len(dirs) == 0 {
+		clear(gitInfoCache.entries)
+		return
+	}
+	for _, dir := range dirs {
+		delete(gitInfoCache.entries, filepath.Clean(dir))
+	}
+}
+
+// readGitInfo opens the repository at dir and reads its details
+func readGitInfo(dir string) (GitInfo, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return GitInfo{}, err
+	}
+
+	var info GitInfo
+	if remote, err := repo.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+		info.RemoteURL = remote.Config().URLs[0]
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		// A repository without commits has no HEAD yet
+		return info, nil
+	}
+	if head.Name().IsBranch() {
+		info.Branch = head.Name().Short()
+	}
+	if commit, err := repo.CommitObject(head.Hash()); err == nil {
+		info.LastCommit = commit.Author.When
+	}
+	info.Dirty = worktreeDirty(repo, dir)
+	return info, nil
+}
+
+// worktreeDirty reports whether the worktree has uncommitted changes. The git command is
+// much faster than go-git's status on large worktrees, so it is used when installed.
+func worktreeDirty(repo *git.Repository, dir string) bool {
+	if _, err := exec.LookPath("git"); err == nil {
+		if output, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output(); err == nil {
+			return strings.TrimSpace(string(output)) != ""
+		}
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return false
+	}
+	return !status.IsClean()
+}
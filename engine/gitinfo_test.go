@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+)
+
+// TestGetGitInfo tests reading git details through the cache and invalidating them
+func TestGetGitInfo(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to init git repository: %v", err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/example/app.git"}}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "go.mod"), "module app\n")
+	commitAll(t, repo, "init")
+	head, _ := repo.Head()
+
+	info, err := GetGitInfo(dir)
+	if err != nil {
+		t.Fatalf("GetGitInfo failed: %v", err)
+	}
+	if info.RemoteURL != "https://github.com/example/app.git" || info.Branch != head.Name().Short() || info.Dirty || info.LastCommit.IsZero() {
+		t.Errorf("Unexpected git details of a clean repository: %+v", info)
+	}
+
+	// Changes show up once the cached details are invalidated
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	if info, _ := GetGitInfo(dir); info.Dirty {
+		t.Error("Expected cached details until invalidated")
+	}
+	InvalidateGitInfo(dir)
+	if info, _ := GetGitInfo(dir); !info.Dirty {
+		t.Error("Expected an untracked file to make the worktree dirty")
+	}
+
+	if _, err := GetGitInfo(t.TempDir()); err == nil {
+		t.Error("Expected an error for a directory that is not a repository")
+	}
+}
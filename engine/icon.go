@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"devbase/db"
+)
+
+// MaxIconWidth is how many terminal cells a project icon may take: an emoji or a short
+// label such as "API"
+const MaxIconWidth = 6
+
+// SetProjectIcon sets the emoji or short label shown before a project's name. An empty icon
+// removes it.
+func SetProjectIcon(projectID uint, icon string) error {
+	icon = strings.TrimSpace(icon)
+	if err := ValidateIcon(icon); err != nil {
+		return err
+	}
+	return db.UpdateProjectIcon(projectID, icon)
+}
+
+// ValidateIcon checks that icon fits in front of a name in the project list: one line of at
+// most MaxIconWidth cells
+func ValidateIcon(icon string) error {
+	if strings.ContainsFunc(icon, unicode.IsControl) {
+		return fmt.Errorf("%q can't be used as an icon", icon)
+	}
+	if width := ansi.StringWidth(icon); width > MaxIconWidth {
+		return fmt.Errorf("icon %q is %d cells wide, at most %d fit", icon, width, MaxIconWidth)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ImportResult summarizes an import of projects known to another tool
+type ImportResult struct {
+	Entries int // Directories read from the tool
+	Added   int // New projects registered
+	Updated int // Existing projects whose usage was seeded
+}
+
+// importCandidate is a directory suggested by another tool with the usage to seed
+type importCandidate struct {
+	path       string
+	lastOpened time.Time // Zero leaves LastOpened to the default
+	openCount  int
+	editor     string // Preferred editor command, empty keeps the default editor
+	trusted    bool   // The tool knows it's a project, so project markers aren't required
+}
+
+// importProjects registers the candidates that are project directories and seeds their usage.
+// New projects join the root folder containing them, or the active one otherwise, unless they
+// are on its never-register list. Existing projects only have their usage raised and get the
+// editor when they have none yet.
+func importProjects(candidates []importCandidate) (ImportResult, error) {
+	result := ImportResult{Entries: len(candidates)}
+
+	activeRoot, _ := db.GetActiveRootFolder() // nil when none is active
+
+	for _, candidate := range candidates {
+		project, ok, err := inspectDirectory(candidate.path)
+		if err != nil {
+			continue
+		}
+		if !ok {
+			if info, err := os.Stat(candidate.path); !candidate.trusted || err != nil || !info.IsDir() {
+				continue
+			}
+			project = models.Project{
+				Name:     filepath.Base(candidate.path),
+				Path:     candidate.path,
+				Status:   "active",
+				RepoURL:  getGitRemoteURL(candidate.path),
+				Language: DetectLanguage(candidate.path),
+			}
+		}
+
+		existing, err := db.GetProjectByPath(project.Path)
+		if err != nil && !errors.Is(err, db.ErrProjectNotFound) {
+			return result, err
+		}
+		if err == nil {
+			if err := db.SeedProjectUsage(existing.ID, candidate.lastOpened, candidate.openCount); err != nil {
+				return result, err
+			}
+			if candidate.editor != "" && existing.Editor == "" {
+				if err := db.UpdateProjectEditor(existing.ID, candidate.editor); err != nil {
+					return result, err
+				}
+			}
+			result.Updated++
+			continue
+		}
+
+		rootFolder, err := db.GetRootFolderForPath(project.Path)
+		if err != nil {
+			return result, err
+		}
+		if rootFolder == nil {
+			rootFolder = activeRoot
+		}
+		if rootFolder != nil {
+			if newNeverRegisterList(*rootFolder).matches(project) {
+				continue
+			}
+			project.RootFolderID = rootFolder.ID
+		}
+		project.LastOpened = candidate.lastOpened
+		project.OpenCount = candidate.openCount
+		project.Editor = candidate.editor
+		if err := db.AddProject(&project); err != nil {
+			return result, err
+		}
+		result.Added++
+	}
+	return result, nil
+}
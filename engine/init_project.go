@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// starterGitignore is written to new projects; it covers what most stacks leave around
+const starterGitignore = `# Dependencies and build output
+node_modules/
+vendor/
+dist/
+build/
+target/
+
+# Environment and secrets
+.env
+.env.local
+
+# Editors and OS files
+.vscode/
+.idea/
+.DS_Store
+Thumbs.db
+
+# Logs
+*.log
+`
+
+// projectNamePattern matches names that work as a directory and a GitHub repository name
+var projectNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ValidateProjectName checks that a name works as a project directory name
+func ValidateProjectName(name string) error {
+	if !projectNamePattern.MatchString(name) || name == "." || name == ".." {
+		return fmt.Errorf("invalid project name %q (use letters, digits, '.', '-' and '_')", name)
+	}
+	return nil
+}
+
+// InitOptions configures InitProject
+type InitOptions struct {
+	Name    string // Directory and repository name
+	Root    string // Directory the project is created in, usually the active root folder
+	GitHub  bool   // Create a GitHub repository and set it as origin
+	Private bool   // Make the GitHub repository private
+	Token   string // GitHub token, needed with GitHub
+}
+
+// InitProject creates a new project: a directory under opts.Root with a git repository, a
+// starter .gitignore and README, optionally a GitHub repository as origin, registered in
+// the database. The GitHub repository is created first, so a rejected name leaves nothing
+// behind locally.
+func InitProject(opts InitOptions) (*models.Project, error) {
+	if err := ValidateProjectName(opts.Name); err != nil {
+		return nil, err
+	}
+	if opts.Root == "" {
+		return nil, fmt.Errorf("no root folder to create the project in")
+	}
+	path := filepath.Join(opts.Root, opts.Name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("project %w: %s", ErrPathExists, path)
+	}
+	if _, err := db.GetProjectByPath(path); err == nil {
+		return nil, fmt.Errorf("project %w: %s", ErrPathExists, path)
+	} else if !errors.Is(err, db.ErrProjectNotFound) {
+		return nil, err
+	}
+
+	if err := LookupGit(); err != nil {
+		return nil, err
+	}
+
+	var repoURL string
+	if opts.GitHub {
+		if opts.Token == "" {
+			return nil, fmt.Errorf("GitHub authentication required to create the repository (press 't')")
+		}
+		repo, err := NewOAuthClient().CreateRepository(opts.Token, opts.Name, opts.Private)
+		if err != nil {
+			return nil, err
+		}
+		repoURL = repo.CloneURL
+	}
+
+	if err := createProjectFiles(path, opts.Name, repoURL); err != nil {
+		os.RemoveAll(path)
+		if repoURL != "" {
+			return nil, fmt.Errorf("%w (the GitHub repository %s was created)", err, repoURL)
+		}
+		return nil, err
+	}
+
+	project := &models.Project{Name: opts.Name, Path: path, RepoURL: repoURL, Status: "active"}
+	if rootFolder, err := db.GetRootFolderForPath(path); err != nil {
+		return nil, err
+	} else if rootFolder != nil {
+		project.RootFolderID = rootFolder.ID
+	}
+	if err := db.AddProject(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// createProjectFiles creates the project directory, runs git init and writes the starter
+// files, adding origin when there is a remote repository
+func createProjectFiles(path, name, repoURL string) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	// git init picks up the user's init.defaultBranch and templates
+	if output, err := exec.Command("git", "init", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("git init failed: %s", strings.TrimSpace(string(output)))
+	}
+	if repoURL != "" {
+		if output, err := exec.Command("git", "-C", path, "remote", "add", "origin", repoURL).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set origin: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	files := map[string]string{
+		".gitignore": starterGitignore,
+		"README.md":  fmt.Sprintf("# %s\n", name),
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(path, file), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jetbrainsProducts maps JetBrains config directory prefixes to the launcher command that
+// Toolbox generates for the IDE. Longer prefixes come first so "PyCharmCE" isn't read as "PyCharm".
+var jetbrainsProducts = []struct {
+	prefix  string
+	command string
+}{
+	{"IntelliJIdea", "idea"},
+	{"IdeaIC", "idea"},
+	{"GoLand", "goland"},
+	{"PyCharmCE", "pycharm"},
+	{"PyCharm", "pycharm"},
+	{"WebStorm", "webstorm"},
+	{"CLion", "clion"},
+	{"Rider", "rider"},
+	{"PhpStorm", "phpstorm"},
+	{"RubyMine", "rubymine"},
+}
+
+// jetbrainsRecentProjects is the part of recentProjects.xml that lists projects. Current IDEs
+// keep them as additionalInfo map entries, older ones as a recentPaths list.
+type jetbrainsRecentProjects struct {
+	Components []struct {
+		Options []struct {
+			Name    string `xml:"name,attr"`
+			Entries []struct {
+				Key     string `xml:"key,attr"`
+				Options []struct {
+					Name  string `xml:"name,attr"`
+					Value string `xml:"value,attr"`
+				} `xml:"value>RecentProjectMetaInfo>option"`
+			} `xml:"map>entry"`
+			Paths []struct {
+				Value string `xml:"value,attr"`
+			} `xml:"list>option"`
+		} `xml:"option"`
+	} `xml:"component"`
+}
+
+// JetBrainsProject is a project from an IDE's recent projects list
+type JetBrainsProject struct {
+	Path       string
+	Editor     string    // Launcher command of the IDE that opened it
+	LastOpened time.Time // Zero when the IDE didn't record it
+}
+
+// ReadJetBrainsProjects returns the recent projects of every JetBrains IDE installation,
+// including the ones managed by Toolbox. When several IDEs know a project, the one that
+// opened it last wins.
+func ReadJetBrainsProjects() ([]JetBrainsProject, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate config directory: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(configDir, "JetBrains", "*", "options", "recentProjects.xml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no JetBrains IDE settings found in %s", filepath.Join(configDir, "JetBrains"))
+	}
+	// Version directories sort oldest first, so newer installations override older ones
+	sort.Strings(files)
+
+	home, _ := os.UserHomeDir()
+	byPath := make(map[string]JetBrainsProject)
+	var order []string
+	for _, file := range files {
+		editor := jetbrainsEditor(filepath.Base(filepath.Dir(filepath.Dir(file))))
+		if editor == "" {
+			continue
+		}
+		projects, err := parseJetBrainsRecentProjects(file, home)
+		if err != nil {
+			continue
+		}
+		for _, project := range projects {
+			project.Editor = editor
+			existing, seen := byPath[project.Path]
+			if !seen {
+				order = append(order, project.Path)
+			} else if existing.LastOpened.After(project.LastOpened) {
+				continue
+			}
+			byPath[project.Path] = project
+		}
+	}
+
+	projects := make([]JetBrainsProject, len(order))
+	for i, path := range order {
+		projects[i] = byPath[path]
+	}
+	return projects, nil
+}
+
+// jetbrainsEditor returns the launcher command for a config directory such as "GoLand2024.1"
+func jetbrainsEditor(dirName string) string {
+	for _, product := range jetbrainsProducts {
+		if strings.HasPrefix(dirName, product.prefix) {
+			return product.command
+		}
+	}
+	return ""
+}
+
+// parseJetBrainsRecentProjects reads the project paths and open times from recentProjects.xml,
+// expanding the $USER_HOME$ macro the IDEs store paths with
+func parseJetBrainsRecentProjects(file, home string) ([]JetBrainsProject, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var recent jetbrainsRecentProjects
+	if err := xml.Unmarshal(data, &recent); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	expand := func(path string) string {
+		return filepath.Clean(filepath.FromSlash(strings.ReplaceAll(path, "$USER_HOME$", home)))
+	}
+
+	var projects []JetBrainsProject
+	for _, component := range recent.Components {
+		for _, option := range component.Options {
+			switch option.Name {
+			case "additionalInfo":
+				for _, entry := range option.Entries {
+					project := JetBrainsProject{Path: expand(entry.Key)}
+					for _, info := range entry.Options {
+						if info.Name != "activationTimestamp" && info.Name != "projectOpenTimestamp" {
+							continue
+						}
+						// Timestamps are in milliseconds; keep the latest of the two
+						if ms, err := strconv.ParseInt(info.Value, 10, 64); err == nil {
+							if opened := time.UnixMilli(ms); opened.After(project.LastOpened) {
+								project.LastOpened = opened
+							}
+						}
+					}
+					projects = append(projects, project)
+				}
+			case "recentPaths":
+				for _, path := range option.Paths {
+					projects = append(projects, JetBrainsProject{Path: expand(path.Value)})
+				}
+			}
+		}
+	}
+	return projects, nil
+}
+
+// ImportJetBrains registers JetBrains recent projects with the IDE that opened them as their
+// preferred editor. The IDE already treats them as projects, so no marker files are required.
+func ImportJetBrains(projects []JetBrainsProject) (ImportResult, error) {
+	candidates := make([]importCandidate, len(projects))
+	for i, project := range projects {
+		candidates[i] = importCandidate{
+			path:       project.Path,
+			lastOpened: project.LastOpened,
+			editor:     project.Editor,
+			trusted:    true,
+		}
+	}
+	return importProjects(candidates)
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestParseJetBrainsRecentProjects tests reading recentProjects.xml of current and older IDEs,
+// with $USER_HOME$ expanded and the latest of a project's timestamps as its last open
+func TestParseJetBrainsRecentProjects(t *testing.T) {
+	home := filepath.Join(string(filepath.Separator), "home", "me")
+
+	projects, err := parseJetBrainsRecentProjects(filepath.Join("testdata", "jetbrains", "recentProjects.xml"), home)
+	if err != nil {
+		t.Fatalf("parseJetBrainsRecentProjects failed: %v", err)
+	}
+	want := []JetBrainsProject{
+		{Path: filepath.Join(home, "code", "api"), LastOpened: time.UnixMilli(1718000000000)},
+		{Path: filepath.Join(string(filepath.Separator), "srv", "tools"), LastOpened: time.UnixMilli(1716000000000)},
+	}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("recentProjects.xml = %+v, want %+v", projects, want)
+	}
+
+	projects, err = parseJetBrainsRecentProjects(filepath.Join("testdata", "jetbrains", "recentProjects_legacy.xml"), home)
+	if err != nil {
+		t.Fatalf("parseJetBrainsRecentProjects failed on the recentPaths format: %v", err)
+	}
+	want = []JetBrainsProject{{Path: filepath.Join(home, "code", "legacy")}, {Path: filepath.Join(home, "code", "api")}}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("recentProjects_legacy.xml = %+v, want %+v", projects, want)
+	}
+
+	broken := filepath.Join(t.TempDir(), "recentProjects.xml")
+	writeFile(t, broken, "<application><component")
+	if _, err := parseJetBrainsRecentProjects(broken, home); err == nil {
+		t.Error("Expected malformed XML to be reported")
+	}
+}
+
+// TestJetBrainsEditor tests mapping IDE config directories to their launcher commands
+func TestJetBrainsEditor(t *testing.T) {
+	tests := map[string]string{
+		"IntelliJIdea2024.1": "idea",
+		"IdeaIC2023.3":       "idea",
+		"GoLand2024.1":       "goland",
+		"PyCharmCE2024.1":    "pycharm",
+		"PyCharm2023.2":      "pycharm",
+		"WebStorm2024.1":     "webstorm",
+		"CLion2024.1":        "clion",
+		"Rider2024.1":        "rider",
+		"PhpStorm2024.1":     "phpstorm",
+		"RubyMine2024.1":     "rubymine",
+		"DataGrip2024.1":     "",
+		"Toolbox":            "",
+	}
+	for dir, want := range tests {
+		if got := jetbrainsEditor(dir); got != want {
+			t.Errorf("jetbrainsEditor(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+// TestReadJetBrainsProjects tests collecting the recent projects of every IDE in the config
+// directory, each with the IDE that opened it last
+func TestReadJetBrainsProjects(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("APPDATA", filepath.Join(home, "AppData", "Roaming"))
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadJetBrainsProjects(); err == nil {
+		t.Error("Expected an error without JetBrains settings")
+	}
+
+	install := func(ide, fixture string) {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join("testdata", "jetbrains", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(configDir, "JetBrains", ide, "options", "recentProjects.xml"), string(data))
+	}
+	install("GoLand2024.1", "recentProjects.xml")
+	install("IdeaIC2019.3", "recentProjects_legacy.xml")
+	install("DataGrip2024.1", "recentProjects_legacy.xml")
+	writeFile(t, filepath.Join(configDir, "JetBrains", "WebStorm2024.1", "options", "recentProjects.xml"), "<application")
+
+	projects, err := ReadJetBrainsProjects()
+	if err != nil {
+		t.Fatalf("ReadJetBrainsProjects failed: %v", err)
+	}
+	want := []JetBrainsProject{
+		{Path: filepath.Join(home, "code", "api"), Editor: "goland", LastOpened: time.UnixMilli(1718000000000)},
+		{Path: filepath.Join(string(filepath.Separator), "srv", "tools"), Editor: "goland", LastOpened: time.UnixMilli(1716000000000)},
+		{Path: filepath.Join(home, "code", "legacy"), Editor: "idea"},
+	}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("ReadJetBrainsProjects = %+v, want %+v", projects, want)
+	}
+}
@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Kinds of jobs, so callers can tell whether an operation of a kind is already running
+const (
+	JobScan    = "scan"
+	JobClone   = "clone"
+	JobArchive = "archive"
+	JobSize    = "size"
+	JobSync    = "sync"
+	JobReclaim = "reclaim"
+)
+
+// JobState is the state of a job
+type JobState string
+
+// Job states
+const (
+	JobRunning   JobState = "running"
+	JobDone      JobState = "done"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// maxFinishedJobs is how many finished jobs a JobManager remembers
+const maxFinishedJobs = 50
+
+// Job is a long-running operation tracked by a JobManager. The manager hands out copies,
+// so a Job is a snapshot; the work reports through the manager's methods.
+type Job struct {
+	ID          int
+	Kind        string
+	Title       string
+	State       JobState
+	Done        int    // Steps completed
+	Total       int    // Steps in all, 0 when unknown
+	Detail      string // What the job is working on
+	Err         error  // Why the job failed
+	Cancellable bool
+	Cancelling  bool // Cancel was called and the work hasn't stopped yet
+	Started     time.Time
+	Finished    time.Time
+}
+
+// Elapsed returns how long the job ran, or has been running
+func (j Job) Elapsed() time.Duration {
+	if j.State == JobRunning {
+		return time.Since(j.Started)
+	}
+	return j.Finished.Sub(j.Started)
+}
+
+// JobManager tracks the background jobs of a DevBase process. It is safe for concurrent use.
+type JobManager struct {
+	mu      sync.Mutex
+	nextID  int
+	jobs    []*Job // Oldest first
+	cancels map[int]context.CancelFunc
+}
+
+// NewJobManager creates an empty job manager
+func NewJobManager() *JobManager {
+	return &JobManager{cancels: make(map[int]context.CancelFunc)}
+}
+
+// Start records a running job that can't be cancelled and returns its ID
+func (jm *JobManager) Start(kind, title string) int {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	return jm.add(kind, title, false)
+}
+
+// StartCancellable records a running job and returns its ID with a context, derived from
+// parent, that Cancel cancels. The work must stop when the context is done.
+func (jm *JobManager) StartCancellable(parent context.Context, kind, title string) (context.Context, int) {
+	ctx, cancel := context.WithCancel(parent)
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	id := jm.add(kind, title, true)
+	jm.cancels[id] = cancel
+	return ctx, id
+}
+
+// add appends a running job; jm.mu must be held
+func (jm *JobManager) add(kind, title string, cancellable bool) int {
+	jm.nextID++
+	jm.jobs = append(jm.jobs, &Job{
+		ID:          jm.nextID,
+		Kind:        kind,
+		Title:       title,
+		State:       JobRunning,
+		Cancellable: cancellable,
+		Started:     time.Now(),
+	})
+	return jm.nextID
+}
+
+// find returns a job by ID; jm.mu must be held
+func (jm *JobManager) find(id int) *Job {
+	i := slices.IndexFunc(jm.jobs, func(j *Job) bool { return j.ID == id })
+	if i < 0 {
+		return nil
+	}
+	return jm.jobs[i]
+}
+
+// Progress reports the steps a running job has completed and what it is working on
+func (jm *JobManager) Progress(id, done, total int, detail string) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	if job := jm.find(id); job != nil && job.State == JobRunning {
+		job.Done, job.Total, job.Detail = done, total, detail
+	}
+}
+
+// Finish ends a job with the error of its work. A cancelled job that returns an error ends
+// as cancelled, so work may return ctx.Err() or whatever it stopped on; one that completed
+// anyway ends as done.
+func (jm *JobManager) Finish(id int, err error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	job := jm.find(id)
+	if job == nil || job.State != JobRunning {
+		return
+	}
+
+	cancelled := errors.Is(err, context.Canceled) || job.Cancelling && err != nil
+	if cancel, ok := jm.cancels[id]; ok {
+		delete(jm.cancels, id)
+		cancel()
+	}
+	job.Cancelling = false
+	switch {
+	case cancelled:
+		job.State = JobCancelled
+	case err != nil:
+		job.State, job.Err = JobFailed, err
+	default:
+		job.State = JobDone
+	}
+	job.Finished = time.Now()
+	jm.prune()
+}
+
+// Cancel asks a running job to stop and reports whether it could be asked. The job stays
+// running until its work returns.
+func (jm *JobManager) Cancel(id int) bool {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	cancel, ok := jm.cancels[id]
+	if ok {
+		jm.find(id).Cancelling = true
+		cancel()
+	}
+	return ok
+}
+
+// prune forgets the oldest finished jobs beyond maxFinishedJobs; jm.mu must be held
+func (jm *JobManager) prune() {
+	finished := 0
+	for i := len(jm.jobs) - 1; i >= 0; i-- {
+		if jm.jobs[i].State == JobRunning {
+			continue
+		}
+		if finished++; finished > maxFinishedJobs {
+			jm.jobs = slices.Delete(jm.jobs, i, i+1)
+		}
+	}
+}
+
+// ClearFinished forgets all finished jobs
+func (jm *JobManager) ClearFinished() {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.jobs = slices.DeleteFunc(jm.jobs, func(j *Job) bool { return j.State != JobRunning })
+}
+
+// Jobs returns a snapshot of the jobs, running ones first, newest first within each group
+func (jm *JobManager) Jobs() []Job {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jobs := make([]Job, 0, len(jm.jobs))
+	for _, running := range []bool{true, false} {
+		for i := len(jm.jobs) - 1; i >= 0; i-- {
+			if (jm.jobs[i].State == JobRunning) == running {
+				jobs = append(jobs, *jm.jobs[i])
+			}
+		}
+	}
+	return jobs
+}
+
+// Running returns the running jobs of a kind, or of all kinds when kind is empty
+func (jm *JobManager) Running(kind string) []Job {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	var running []Job
+	for _, job := range jm.jobs {
+		if job.State == JobRunning && (kind == "" || job.Kind == kind) {
+			running = append(running, *job)
+		}
+	}
+	return running
+}
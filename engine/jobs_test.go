@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestJobManager(t *testing.T) {
+	jobs := NewJobManager()
+
+	syncID := jobs.Start(JobSync, "Sync")
+	ctx, scanID := jobs.StartCancellable(context.Background(), JobScan, "Scan")
+	_, cloneID := jobs.StartCancellable(context.Background(), JobClone, "Clone")
+	jobs.Progress(scanID, 2, 5, "api")
+
+	if running := jobs.Running(JobScan); len(running) != 1 || running[0].Done != 2 || running[0].Total != 5 || running[0].Detail != "api" {
+		t.Errorf("Expected the scan running with its progress, got %+v", running)
+	}
+	if jobs.Cancel(syncID) {
+		t.Error("Expected a job started with Start not to be cancellable")
+	}
+
+	// Cancelling asks the work to stop; the job ends when the work returns
+	if !jobs.Cancel(scanID) {
+		t.Fatal("Expected the scan to be cancellable")
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected the scan's context to be cancelled")
+	}
+	if running := jobs.Running(JobScan); len(running) != 1 || !running[0].Cancelling {
+		t.Errorf("Expected the scan to be cancelling until it returns, got %+v", running)
+	}
+	jobs.Finish(scanID, fmt.Errorf("walk stopped: %w", ctx.Err()))
+	jobs.Finish(syncID, errors.New("token rejected"))
+
+	list := jobs.Jobs()
+	if len(list) != 3 || list[0].ID != cloneID {
+		t.Fatalf("Expected the running clone listed first, got %+v", list)
+	}
+	states := map[int]JobState{}
+	for _, job := range list {
+		states[job.ID] = job.State
+	}
+	if states[scanID] != JobCancelled || states[syncID] != JobFailed || states[cloneID] != JobRunning {
+		t.Errorf("Unexpected job states: %v", states)
+	}
+	if list[1].ID != scanID {
+		t.Errorf("Expected finished jobs newest first, got %+v", list[1:])
+	}
+	if len(jobs.Running("")) != 1 {
+		t.Errorf("Expected only the clone running, got %+v", jobs.Running(""))
+	}
+
+	jobs.Finish(cloneID, nil)
+	jobs.ClearFinished()
+	if list := jobs.Jobs(); len(list) != 0 {
+		t.Errorf("Expected no jobs after clearing finished ones, got %+v", list)
+	}
+
+	// Only the newest finished jobs are remembered
+	for range maxFinishedJobs + 10 {
+		jobs.Finish(jobs.Start(JobSize, "Sizes"), nil)
+	}
+	if list := jobs.Jobs(); len(list) != maxFinishedJobs || list[0].ID != list[len(list)-1].ID+maxFinishedJobs-1 {
+		t.Errorf("Expected the last %d jobs to be kept, got %d", maxFinishedJobs, len(list))
+	}
+}
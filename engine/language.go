@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"path/filepath"
+)
+
+// Language identifiers returned by DetectLanguage
+const (
+	LangGo         = "go"
+	LangTypeScript = "typescript"
+	LangJavaScript = "javascript"
+	LangPython     = "python"
+	LangRust       = "rust"
+	LangJava       = "java"
+	LangCSharp     = "csharp"
+	LangRuby       = "ruby"
+	LangPHP        = "php"
+	LangDart       = "dart"
+	LangCpp        = "cpp"
+)
+
+// languageMarkers maps marker files (or glob patterns) to the language they indicate.
+// Order matters: the first match wins, so more specific markers come first.
+var languageMarkers = []struct {
+	pattern  string
+	language string
+}{
+	{"go.mod", LangGo},
+	{"Cargo.toml", LangRust},
+	{"tsconfig.json", LangTypeScript},
+	{"package.json", LangJavaScript},
+	{"pyproject.toml", LangPython},
+	{"requirements.txt", LangPython},
+	{"setup.py", LangPython},
+	{"Pipfile", LangPython},
+	{"pom.xml", LangJava},
+	{"build.gradle", LangJava},
+	{"build.gradle.kts", LangJava},
+	{"*.csproj", LangCSharp},
+	{"*.sln", LangCSharp},
+	{"Gemfile", LangRuby},
+	{"composer.json", LangPHP},
+	{"pubspec.yaml", LangDart},
+	{"CMakeLists.txt", LangCpp},
+}
+
+// DetectLanguage returns the primary language of a project directory based on marker files.
+// An empty string is returned when no known marker is present.
+func DetectLanguage(dir string) string {
+	for _, marker := range languageMarkers {
+		if matches, _ := filepath.Glob(filepath.Join(dir, marker.pattern)); len(matches) > 0 {
+			return marker.language
+		}
+	}
+	return ""
+}
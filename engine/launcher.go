@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"devbase/models"
+)
+
+// Launcher catalog formats written by "devbase export"
+const (
+	LauncherJSON    = "json"    // Generic catalog, e.g. for PowerToys Run plugins
+	LauncherAlfred  = "alfred"  // Alfred Script Filter output
+	LauncherRaycast = "raycast" // Directory of Raycast Script Commands
+)
+
+// raycastScriptPrefix starts the file names of generated Raycast scripts, so stale ones
+// can be removed when exporting again
+const raycastScriptPrefix = "devbase-open-"
+
+// LauncherEntry is a project as offered by an OS launcher
+type LauncherEntry struct {
+	Title    string   `json:"title"`
+	Subtitle string   `json:"subtitle"`
+	Target   string   `json:"target"`   // Argument of "devbase open": the name, or the path when the name is ambiguous
+	Command  []string `json:"command"`  // Full command line that opens the project
+	Keywords []string `json:"keywords"` // Tags and language, for launcher search
+}
+
+// LauncherEntries builds launcher entries for the active projects. devbase is the
+// executable the entries invoke.
+func LauncherEntries(projects []models.Project, devbase string) []LauncherEntry {
+	names := make(map[string]int)
+	for _, p := range projects {
+		if p.Status != "archived" {
+			names[strings.ToLower(p.Name)]++
+		}
+	}
+
+	var entries []LauncherEntry
+	for _, p := range projects {
+		if p.Status == "archived" {
+			continue
+		}
+		target := p.Name
+		if names[strings.ToLower(p.Name)] > 1 {
+			target = p.Path
+		}
+		keywords := append([]string{}, p.Tags...)
+		if p.Language != "" {
+			keywords = append(keywords, p.Language)
+		}
+		entries = append(entries, LauncherEntry{
+			Title:    p.Name,
+			Subtitle: p.Path,
+			Target:   target,
+			Command:  []string{devbase, "open", target},
+			Keywords: keywords,
+		})
+	}
+	return entries
+}
+
+// LauncherCatalog renders entries in the json or alfred format
+func LauncherCatalog(format string, entries []LauncherEntry) ([]byte, error) {
+	switch format {
+	case LauncherJSON:
+		return json.MarshalIndent(entries, "", "  ")
+	case LauncherAlfred:
+		// The workflow's Run Script action receives arg, e.g. devbase open "$1"
+		type alfredItem struct {
+			UID          string `json:"uid"`
+			Title        string `json:"title"`
+			Subtitle     string `json:"subtitle"`
+			Arg          string `json:"arg"`
+			Autocomplete string `json:"autocomplete"`
+			Match        string `json:"match"`
+		}
+		items := make([]alfredItem, len(entries))
+		for i, e := range entries {
+			items[i] = alfredItem{
+				UID:          e.Subtitle,
+				Title:        e.Title,
+				Subtitle:     e.Subtitle,
+				Arg:          e.Target,
+				Autocomplete: e.Title,
+				Match:        strings.Join(append([]string{e.Title}, e.Keywords...), " "),
+			}
+		}
+		return json.MarshalIndent(map[string]any{"items": items}, "", "  ")
+	}
+	return nil, fmt.Errorf("unknown launcher format %q (expected json, alfred or raycast)", format)
+}
+
+// WriteRaycastScripts writes one Raycast Script Command per entry into dir, replacing the
+// scripts of an earlier export, and returns how many were written
+func WriteRaycastScripts(entries []LauncherEntry, dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	stale, _ := filepath.Glob(filepath.Join(dir, raycastScriptPrefix+"*.sh"))
+	for _, path := range stale {
+		os.Remove(path)
+	}
+
+	used := make(map[string]bool)
+	for _, e := range entries {
+		name := raycastScriptPrefix + strings.ToLower(sanitizeFileName(e.Title))
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s%s-%d", raycastScriptPrefix, strings.ToLower(sanitizeFileName(e.Title)), i)
+		}
+		used[name] = true
+
+		quoted := make([]string, len(e.Command))
+		for i, arg := range e.Command {
+			quoted[i] = shellQuote(arg)
+		}
+		script := fmt.Sprintf(`#!/bin/bash
+
+# Required parameters:
+# @raycast.schemaVersion 1
+# @raycast.title Open %s
+# @raycast.mode silent
+
+# Optional parameters:
+# @raycast.packageName DevBase
+# @raycast.description %s
+
+exec %s
+`, e.Title, e.Subtitle, strings.Join(quoted, " "))
+
+		if err := os.WriteFile(filepath.Join(dir, name+".sh"), []byte(script), 0755); err != nil {
+			return 0, fmt.Errorf("failed to write Raycast script: %w", err)
+		}
+	}
+	return len(entries), nil
+}
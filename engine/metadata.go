@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DirSizeTTL is how long a computed directory size is reused by CachedDirSize
+const DirSizeTTL = 10 * time.Minute
+
+// dirSizeEntry is a computed size of a directory
+type dirSizeEntry struct {
+	size int64
+	read time.Time
+}
+
+var dirSizeCache = struct {
+	sync.Mutex
+	entries map[string]dirSizeEntry
+}{entries: make(map[string]dirSizeEntry)}
+
+// ProjectMetadata holds details about a project directory that are expensive to compute,
+// so they are gathered in the background rather than stored in the database
+type ProjectMetadata struct {
+	Size       int64     // Total size of the files in bytes, 0 when not computed
+	LastCommit time.Time // Author time of the HEAD commit, zero when unknown or not a git repo
+	Branch     string    // Checked out branch, empty when unknown or detached
+	Dirty      bool      // Uncommitted changes in the worktree
+}
+
+// DirSize returns the total size of the regular files under dir. Symlinks are not followed
+// and unreadable entries are skipped.
+func DirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// CachedDirSize returns the size of dir like DirSize, reusing a size computed in the last
+// DirSizeTTL by this function or CollectProjectMetadata
+func CachedDirSize(dir string) int64 {
+	key := filepath.Clean(dir)
+	dirSizeCache.Lock()
+	entry, ok := dirSizeCache.entries[key]
+	dirSizeCache.Unlock()
+	if ok && time.Since(entry.read) < DirSizeTTL {
+		return entry.size
+	}
+	size := DirSize(key)
+	storeDirSize(key, size)
+	return size
+}
+
+// storeDirSize caches a size computed by DirSize
+func storeDirSize(dir string, size int64) {
+	dirSizeCache.Lock()
+	dirSizeCache.entries[filepath.Clean(dir)] = dirSizeEntry{size: size, read: time.Now()}
+	dirSizeCache.Unlock()
+}
+
+// InvalidateDirSize drops the cached sizes of the given directories
+func InvalidateDirSize(dirs ...string) {
+	dirSizeCache.Lock()
+	defer dirSizeCache.Unlock()
+	for _, dir := range dirs {
+		delete(dirSizeCache.entries, filepath.Clean(dir))
+	}
+}
+
+// FormatSize renders a byte count with a binary unit (e.g. "12.3 MB")
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// CollectProjectMetadata gathers metadata for a set of project paths keyed by project ID.
+// Directory sizes and git details are only computed when requested since walking large
+// projects is slow; git details come from the GetGitInfo cache. progress, when not nil, is
+// called with the number of projects done after each one. Cancelling ctx stops the
+// collection and returns what was gathered so far.
+func CollectProjectMetadata(ctx context.Context, paths map[uint]string, withSize, withGit bool, progress func(done int)) map[uint]ProjectMetadata {
+	metadata := make(map[uint]ProjectMetadata, len(paths))
+	for id, path := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		var meta ProjectMetadata
+		if withSize {
+			meta.Size = DirSize(path)
+			storeDirSize(path, meta.Size)
+		}
+		if withGit {
+			if info, err := GetGitInfo(path); err == nil {
+				meta.LastCommit, meta.Branch, meta.Dirty = info.LastCommit, info.Branch, info.Dirty
+			}
+		}
+		metadata[id] = meta
+		if progress != nil {
+			progress(len(metadata))
+		}
+	}
+	return metadata
+}
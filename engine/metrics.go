@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"devbase/db"
+)
+
+// Metrics records what "devbase serve" did and renders it in the Prometheus text format
+// together with the project counts from the database
+type Metrics struct {
+	mu           sync.Mutex
+	scans        map[string]int     // Scans by result ("success" or "failure")
+	scanSeconds  float64            // Total duration of all scans
+	lastScan     map[string]float64 // Duration of the last scan by root folder path
+	lastScanTime time.Time
+	syncs        map[string]int // Cloud syncs by result
+	lastSyncTime time.Time      // Last successful sync
+}
+
+// NewMetrics creates an empty metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{
+		scans:    map[string]int{"success": 0, "failure": 0},
+		lastScan: make(map[string]float64),
+		syncs:    map[string]int{"success": 0, "failure": 0},
+	}
+}
+
+// ObserveScan records a finished scan of a root folder
+func (m *Metrics) ObserveScan(root string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scans[outcome(err)]++
+	m.scanSeconds += duration.Seconds()
+	m.lastScan[root] = duration.Seconds()
+	m.lastScanTime = time.Now()
+}
+
+// ObserveSync records a finished cloud sync
+func (m *Metrics) ObserveSync(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.syncs[outcome(err)]++
+	if err == nil {
+		m.lastSyncTime = time.Now()
+	}
+}
+
+// outcome labels an operation outcome
+func outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// ServeHTTP serves the metrics to a Prometheus scrape
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	counts, err := db.CountProjectsByStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	roots, err := db.GetAllRootFolders()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var b bytes.Buffer
+	writeMetricHeader(&b, "devbase_projects", "gauge", "Projects in the database by status.")
+	for _, status := range sortedKeys(counts) {
+		fmt.Fprintf(&b, "devbase_projects{status=%s} %d\n", promLabel(status), counts[status])
+	}
+	writeMetricHeader(&b, "devbase_root_folders", "gauge", "Registered root folders.")
+	fmt.Fprintf(&b, "devbase_root_folders %d\n", len(roots))
+
+	m.mu.Lock()
+	writeMetricHeader(&b, "devbase_scans_total", "counter", "Root folder scans by result.")
+	for _, res := range sortedKeys(m.scans) {
+		fmt.Fprintf(&b, "devbase_scans_total{result=%s} %d\n", promLabel(res), m.scans[res])
+	}
+	writeMetricHeader(&b, "devbase_scan_duration_seconds", "summary", "Duration of root folder scans.")
+	fmt.Fprintf(&b, "devbase_scan_duration_seconds_sum %s\n", promNumber(m.scanSeconds))
+	fmt.Fprintf(&b, "devbase_scan_duration_seconds_count %d\n", m.scans["success"]+m.scans["failure"])
+	writeMetricHeader(&b, "devbase_last_scan_duration_seconds", "gauge", "Duration of the last scan of each root folder.")
+	for _, root := range sortedKeys(m.lastScan) {
+		fmt.Fprintf(&b, "devbase_last_scan_duration_seconds{root=%s} %s\n", promLabel(root), promNumber(m.lastScan[root]))
+	}
+	writeMetricHeader(&b, "devbase_last_scan_timestamp_seconds", "gauge", "Unix time of the last scan, 0 before the first.")
+	fmt.Fprintf(&b, "devbase_last_scan_timestamp_seconds %d\n", unixSeconds(m.lastScanTime))
+	writeMetricHeader(&b, "devbase_syncs_total", "counter", "Cloud syncs by result.")
+	for _, res := range sortedKeys(m.syncs) {
+		fmt.Fprintf(&b, "devbase_syncs_total{result=%s} %d\n", promLabel(res), m.syncs[res])
+	}
+	writeMetricHeader(&b, "devbase_last_sync_success_timestamp_seconds", "gauge", "Unix time of the last successful sync, 0 before the first.")
+	fmt.Fprintf(&b, "devbase_last_sync_success_timestamp_seconds %d\n", unixSeconds(m.lastSyncTime))
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(b.Bytes())
+}
+
+// writeMetricHeader writes the HELP and TYPE lines of a metric
+func writeMetricHeader(b *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// promLabel quotes a label value, escaping backslashes, quotes and newlines
+func promLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// promNumber formats a sample value
+func promNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// unixSeconds returns the Unix time of t, or 0 for the zero time
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// sortedKeys returns the keys of a map in order, so scrapes are stable
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
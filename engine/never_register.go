@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ErrNeverRegistered is returned by AddDirectory for directories on the never-register list
+// of their root folder
+var ErrNeverRegistered = errors.New("on the never-register list")
+
+// neverRegisterList matches projects against the never-register list of a root folder:
+// directories relative to the root folder, which cover the projects inside them too, and
+// repository URLs, which match any URL form of the same repository
+type neverRegisterList struct {
+	root  string
+	paths []string
+	repos map[string]bool
+}
+
+// newNeverRegisterList reads the never-register list of a root folder
+func newNeverRegisterList(rootFolder models.RootFolder) neverRegisterList {
+	list := neverRegisterList{root: rootFolder.Path, repos: make(map[string]bool)}
+	for _, entry := range rootFolder.NeverRegister {
+		if ref, ok := ParseRepoURL(entry); ok {
+			list.repos[ref.Key()] = true
+		} else {
+			list.paths = append(list.paths, filepath.ToSlash(entry))
+		}
+	}
+	return list
+}
+
+// matches reports whether a project is on the list
+func (l neverRegisterList) matches(project models.Project) bool {
+	if ref, ok := ParseRepoURL(project.RepoURL); ok && l.repos[ref.Key()] {
+		return true
+	}
+	rel, err := filepath.Rel(l.root, project.Path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, path := range l.paths {
+		if rel == path || strings.HasPrefix(rel, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// filter drops the projects on the list, returning the paths of those it dropped
+func (l neverRegisterList) filter(projects []models.Project) ([]models.Project, []string) {
+	if len(l.paths) == 0 && len(l.repos) == 0 {
+		return projects, nil
+	}
+	var dropped []string
+	kept := slices.DeleteFunc(projects, func(project models.Project) bool {
+		if l.matches(project) {
+			dropped = append(dropped, project.Path)
+			return true
+		}
+		return false
+	})
+	return kept, dropped
+}
+
+// neverRegisterEntry turns a directory or repository URL into an entry of the root folder's
+// list. Directories are stored relative to the root folder, so the list still fits when the
+// root folder is somewhere else on another machine.
+func neverRegisterEntry(rootFolder models.RootFolder, entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return "", fmt.Errorf("no directory or repository URL given")
+	}
+	if _, ok := ParseRepoURL(entry); ok {
+		return entry, nil
+	}
+	path, err := filepath.Abs(entry)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	rel, err := filepath.Rel(rootFolder.Path, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not inside root folder %s", path, rootFolder.Path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// NeverRegister adds a directory or repository URL to the never-register list of a root
+// folder, so scans and devbase add skip it, and removes the projects it matches for good, so
+// they come back with a scan once the entry is removed. Locked projects stay. It returns the
+// stored entry and the number of projects removed.
+func NeverRegister(rootFolder *models.RootFolder, entry string) (string, int, error) {
+	entry, err := neverRegisterEntry(*rootFolder, entry)
+	if err != nil {
+		return "", 0, err
+	}
+	if !slices.Contains(rootFolder.NeverRegister, entry) {
+		rootFolder.NeverRegister = append(rootFolder.NeverRegister, entry)
+		if err := db.UpdateRootFolder(rootFolder); err != nil {
+			return "", 0, err
+		}
+	}
+
+	projects, err := db.GetProjectsByRootFolder(rootFolder.ID)
+	if err != nil {
+		return entry, 0, err
+	}
+	list := newNeverRegisterList(models.RootFolder{Path: rootFolder.Path, NeverRegister: []string{entry}})
+	var removed []uint
+	for _, project := range projects {
+		if project.RemoteHostID == 0 && !project.Locked && list.matches(project) {
+			removed = append(removed, project.ID)
+		}
+	}
+	if err := db.PurgeProjects(removed); err != nil {
+		return entry, 0, err
+	}
+	for _, id := range removed {
+		SyncSessionWorkspaces(id)
+	}
+	return entry, len(removed), nil
+}
+
+// AllowRegister removes an entry from the never-register list of a root folder. The projects
+// it kept out are found by the next scan.
+func AllowRegister(rootFolder *models.RootFolder, entry string) error {
+	entry = strings.TrimSpace(entry)
+	// Entries can be given as they are listed, relative to the root folder
+	i := slices.Index(rootFolder.NeverRegister, filepath.ToSlash(entry))
+	if normalized, err := neverRegisterEntry(*rootFolder, entry); i < 0 && err == nil {
+		i = slices.Index(rootFolder.NeverRegister, normalized)
+	}
+	if i < 0 {
+		return fmt.Errorf("%s is not on the never-register list of %s", entry, rootFolder.Name)
+	}
+	rootFolder.NeverRegister = slices.Delete(rootFolder.NeverRegister, i, i+1)
+	return db.UpdateRootFolder(rootFolder)
+}
@@ -1,22 +1,47 @@
 package engine
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
 	"time"
-)
 
-// GitHub OAuth App credentials for DevBase
-// You'll need to create a GitHub OAuth App at https://github.com/settings/developers
-const (
-	// Note: This is a placeholder. For production use, create your own OAuth App
-	// at https://github.com/settings/developers and replace this with your client ID
-	ClientID = "Ov23liNemMNmQpa1yLxG" // Placeholder - replace with actual OAuth App client ID
+	"devbase/db"
 )
 
+// ClientID is the client ID of DevBase's GitHub OAuth App. Self-hosters and forks use their
+// own App, created at https://github.com/settings/developers with Device Flow enabled, by
+// setting DEVBASE_GITHUB_CLIENT_ID or the github_client_id config key (see OAuthClientID).
+const ClientID = "Ov23liNemMNmQpa1yLxG"
+
+// clientIDPattern matches GitHub client IDs: 20 hex digits for older OAuth Apps, or an "Ov"
+// (OAuth App) or "Iv" (GitHub App) prefix and 18 more characters, e.g. Ov23li... or Iv1....
+var clientIDPattern = regexp.MustCompile(`^(?:[0-9a-f]{20}|[OI]v[0-9A-Za-z.]{18})$`)
+
+// OAuthClientID returns the OAuth App client ID to sign in with and where it came from:
+// DEVBASE_GITHUB_CLIENT_ID, then the github_client_id config key, then ClientID
+func OAuthClientID() (id, source string) {
+	if env := strings.TrimSpace(os.Getenv("DEVBASE_GITHUB_CLIENT_ID")); env != "" {
+		return env, "DEVBASE_GITHUB_CLIENT_ID"
+	}
+	if value, _ := db.GetConfig("github_client_id"); strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value), "the github_client_id config key"
+	}
+	return ClientID, "the built-in client ID"
+}
+
+// ValidateClientID checks that id looks like a GitHub OAuth App client ID
+func ValidateClientID(id string) error {
+	if !clientIDPattern.MatchString(id) {
+		return fmt.Errorf("%q is not a GitHub OAuth App client ID (expected e.g. Ov23li and 14 more characters)", id)
+	}
+	return nil
+}
+
 // DeviceCodeResponse represents the response from GitHub's device code endpoint
 type DeviceCodeResponse struct {
 	DeviceCode      string `json:"device_code"`
@@ -24,6 +49,8 @@ type DeviceCodeResponse struct {
 	VerificationURI string `json:"verification_uri"`
 	ExpiresIn       int    `json:"expires_in"`
 	Interval        int    `json:"interval"`
+	Error           string `json:"error"`
+	ErrorDesc       string `json:"error_description"`
 }
 
 // AccessTokenResponse represents the response from GitHub's access token endpoint
@@ -38,67 +65,61 @@ type AccessTokenResponse struct {
 // OAuthClient handles GitHub OAuth device flow operations
 type OAuthClient struct {
 	ClientID string
+	Source   string // Where ClientID came from, named in setup errors
 }
 
-// NewOAuthClient creates a new OAuthClient
+// NewOAuthClient creates an OAuthClient for the client ID from OAuthClientID
 func NewOAuthClient() *OAuthClient {
+	id, source := OAuthClientID()
 	return &OAuthClient{
-		ClientID: ClientID,
+		ClientID: id,
+		Source:   source,
 	}
 }
 
+// setupError explains a client ID problem and how to fix it
+func (c *OAuthClient) setupError(problem string) error {
+	source := c.Source
+	if source == "" {
+		source = "the configured client ID"
+	}
+	return fmt.Errorf("OAuth not configured: %s (from %s). Create an OAuth App with Device Flow enabled at https://github.com/settings/developers and set DEVBASE_GITHUB_CLIENT_ID or github_client_id to its Client ID, or sign in with a personal access token instead", problem, source)
+}
+
 // InitiateDeviceFlow starts the OAuth device flow
 func (c *OAuthClient) InitiateDeviceFlow() (*DeviceCodeResponse, error) {
 	// Check if we have a valid client ID
-	if c.ClientID == "" || c.ClientID == "Iv1.0000000000000000" {
-		return nil, fmt.Errorf("OAuth not configured: Please create a GitHub OAuth App at https://github.com/settings/developers and update the ClientID constant")
+	if c.ClientID == "" {
+		return nil, c.setupError("no client ID is set")
+	}
+	if err := ValidateClientID(c.ClientID); err != nil {
+		return nil, c.setupError(err.Error())
 	}
-
-	url := "https://github.com/login/device/code"
 
 	data := map[string]string{
 		"client_id": c.ClientID,
 		"scope":     "gist repo",
 	}
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	var deviceResp DeviceCodeResponse
+	err := NewGitHubClient("").Send("POST", "https://github.com/login/device/code", data, &deviceResp)
+	switch GitHubStatus(err) {
+	case http.StatusNotFound:
+		return nil, c.setupError(fmt.Sprintf("GitHub has no OAuth App with the client ID %s", c.ClientID))
+	case http.StatusUnprocessableEntity:
+		return nil, c.setupError(fmt.Sprintf("GitHub rejected the client ID %s; check the App's settings", c.ClientID))
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == 404 {
-			return nil, fmt.Errorf("OAuth client not found: Please ensure your GitHub OAuth App client ID is correct")
-		}
-		if resp.StatusCode == 422 {
-			return nil, fmt.Errorf("OAuth configuration error: Please check your GitHub OAuth App settings")
-		}
-		return nil, fmt.Errorf("GitHub API error: %d - %s", resp.StatusCode, string(body))
+		return nil, err
 	}
-
-	var deviceResp DeviceCodeResponse
-	if err := json.Unmarshal(body, &deviceResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	switch deviceResp.Error {
+	case "":
+	case "device_flow_disabled":
+		return nil, c.setupError(fmt.Sprintf("Device Flow is disabled for the OAuth App %s; enable it in the App's settings", c.ClientID))
+	case "incorrect_client_credentials":
+		return nil, c.setupError(fmt.Sprintf("GitHub has no OAuth App with the client ID %s", c.ClientID))
+	default:
+		return nil, fmt.Errorf("OAuth error: %s - %s", deviceResp.Error, deviceResp.ErrorDesc)
 	}
 
 	return &deviceResp, nil
@@ -106,8 +127,6 @@ func (c *OAuthClient) InitiateDeviceFlow() (*DeviceCodeResponse, error) {
 
 // PollForAccessToken polls GitHub for the access token
 func (c *OAuthClient) PollForAccessToken(deviceCode string, interval int) (string, error) {
-	url := "https://github.com/login/oauth/access_token"
-
 	pollInterval := time.Duration(interval) * time.Second
 	if pollInterval < 5*time.Second {
 		pollInterval = 5 * time.Second
@@ -131,34 +150,10 @@ func (c *OAuthClient) PollForAccessToken(deviceCode string, interval int) (strin
 				"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
 			}
 
-			jsonData, err := json.Marshal(data)
-			if err != nil {
-				return "", fmt.Errorf("failed to marshal request: %w", err)
-			}
-
-			req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-			if err != nil {
-				return "", fmt.Errorf("failed to create request: %w", err)
-			}
-
-			req.Header.Set("Accept", "application/json")
-			req.Header.Set("Content-Type", "application/json")
-
-			client := &http.Client{Timeout: 10 * time.Second}
-			resp, err := client.Do(req)
-			if err != nil {
-				return "", fmt.Errorf("failed to execute request: %w", err)
-			}
-
-			body, err := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			if err != nil {
-				return "", fmt.Errorf("failed to read response: %w", err)
-			}
-
+			// GitHub answers pending and failed polls with 200 and an error field
 			var tokenResp AccessTokenResponse
-			if err := json.Unmarshal(body, &tokenResp); err != nil {
-				return "", fmt.Errorf("failed to unmarshal response: %w", err)
+			if err := NewGitHubClient("").Send("POST", "https://github.com/login/oauth/access_token", data, &tokenResp); err != nil {
+				return "", err
 			}
 
 			// Check for errors
@@ -190,95 +185,83 @@ func (c *OAuthClient) PollForAccessToken(deviceCode string, interval int) (strin
 
 // ValidateToken checks if the GitHub token is valid by making a test API call
 func (c *OAuthClient) ValidateToken(token string) error {
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return fmt.Errorf("failed to create validation request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to validate token: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 401 {
-		return fmt.Errorf("invalid GitHub token")
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("GitHub API error during token validation: %d", resp.StatusCode)
-	}
-
-	return nil
+	return NewGitHubClient(token).ValidateToken()
 }
 
 // GitHubRepository represents a GitHub repository from the API
 type GitHubRepository struct {
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	FullName    string `json:"full_name"`
-	Description string `json:"description"`
-	CloneURL    string `json:"clone_url"`
-	HTMLURL     string `json:"html_url"`
-	Private     bool   `json:"private"`
-	Language    string `json:"language"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	FullName    string   `json:"full_name"`
+	Description string   `json:"description"`
+	CloneURL    string   `json:"clone_url"`
+	HTMLURL     string   `json:"html_url"`
+	Private     bool     `json:"private"`
+	Archived    bool     `json:"archived"`
+	Language    string   `json:"language"`
+	Topics      []string `json:"topics"`
+	UpdatedAt   string   `json:"updated_at"`
 }
 
 // FetchUserRepositories retrieves all repositories for the authenticated user
 func (c *OAuthClient) FetchUserRepositories(token string) ([]GitHubRepository, error) {
-	var allRepos []GitHubRepository
-	page := 1
-	perPage := 100
-
-	for {
-		url := fmt.Sprintf("https://api.github.com/user/repos?per_page=%d&page=%d&sort=updated&visibility=all&affiliation=owner,collaborator,organization_member", perPage, page)
-
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		req.Header.Set("Authorization", "Bearer "+token)
-		req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch repositories: %w", err)
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("GitHub API error: %d - %s", resp.StatusCode, string(body))
-		}
+	repos, err := GitHubGetAll[GitHubRepository](NewGitHubClient(token), "/user/repos?per_page=100&sort=updated&visibility=all&affiliation=owner,collaborator,organization_member")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+	}
+	return repos, nil
+}
 
-		var repos []GitHubRepository
-		if err := json.Unmarshal(body, &repos); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-		}
+// repoPageSize is the number of starred or organization repositories fetched per page
+const repoPageSize = 50
 
-		if len(repos) == 0 {
-			break
-		}
+// FetchStarredRepositories retrieves one page (starting at 1) of the repositories the
+// authenticated user starred, most recently starred first. more reports whether another
+// page may follow.
+func (c *OAuthClient) FetchStarredRepositories(token string, page int) (repos []GitHubRepository, more bool, err error) {
+	path := fmt.Sprintf("/user/starred?per_page=%d&page=%d&sort=created&direction=desc", repoPageSize, page)
+	next, err := NewGitHubClient(token).GetPage(path, &repos)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch starred repositories: %w", err)
+	}
+	return repos, next != "", nil
+}
 
-		allRepos = append(allRepos, repos...)
+// FetchOrgRepositories retrieves one page (starting at 1) of an organization's repositories
+// visible to the authenticated user, most recently pushed first. Archived repositories are
+// left out; more reports whether another page may follow.
+func (c *OAuthClient) FetchOrgRepositories(token, org string, page int) ([]GitHubRepository, bool, error) {
+	path := fmt.Sprintf("/orgs/%s/repos?type=all&sort=pushed&per_page=%d&page=%d", url.PathEscape(org), repoPageSize, page)
+	var repos []GitHubRepository
+	next, err := NewGitHubClient(token).GetPage(path, &repos)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch repositories of %s: %w", org, err)
+	}
 
-		// If we got fewer than perPage results, we're done
-		if len(repos) < perPage {
-			break
+	more := next != ""
+	active := repos[:0]
+	for _, repo := range repos {
+		if !repo.Archived {
+			active = append(active, repo)
 		}
-
-		page++
 	}
+	return active, more, nil
+}
 
-	return allRepos, nil
+// CreateRepository creates a repository owned by the authenticated user. The token needs
+// the repo scope (public_repo for public repositories).
+func (c *OAuthClient) CreateRepository(token, name string, private bool) (*GitHubRepository, error) {
+	var repo GitHubRepository
+	err := NewGitHubClient(token).Send("POST", "/user/repos", map[string]any{"name": name, "private": private}, &repo)
+	if err == nil {
+		return &repo, nil
+	}
+	switch status := GitHubStatus(err); {
+	case errors.Is(err, ErrGitHubRateLimited):
+	case status == http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("GitHub rejected the repository %s; it may already exist", name)
+	case status == http.StatusForbidden || status == http.StatusNotFound:
+		return nil, fmt.Errorf("GitHub token can't create repositories; re-authenticate with the repo scope (press 't')")
+	}
+	return nil, err
 }
@@ -1,23 +1,87 @@
 package engine
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
 
 	"github.com/go-git/go-git/v5"
 
 	"devbase/db"
+	"devbase/models"
 )
 
-// ArchiveProject archives a project by updating its status and deleting the physical directory
+// Errors of archive, restore and clone operations, wrapped with the project or path they
+// are about. Check them with errors.Is.
+var (
+	ErrAlreadyArchived = errors.New("project is already archived")
+	ErrAlreadyActive   = errors.New("project is already active")
+	ErrNoRepoURL       = errors.New("project has no repository URL")
+	ErrPathExists      = errors.New("path already exists")
+)
+
+// operationRefusals are errors of archive and restore that refuse the operation before it
+// starts, so they aren't kept as the project's last error
+var operationRefusals = []error{ErrAlreadyArchived, ErrAlreadyActive, ErrRemoteProject, ErrWorktreeProject, ErrHasWorktrees, db.ErrProjectLocked, db.ErrProjectNotFound}
+
+// recordOperationError keeps why an archive or restore of a project failed, so it can still be
+// shown once the status line moved on, and clears it when one succeeds. It returns err.
+func recordOperationError(projectID uint, operation string, err error) error {
+	for _, refusal := range operationRefusals {
+		if errors.Is(err, refusal) {
+			return err
+		}
+	}
+	message := ""
+	if err != nil {
+		message = operation + " failed: " + err.Error()
+	}
+	if dbErr := db.UpdateProjectLastError(projectID, message); dbErr != nil {
+		slog.Warn("Failed to record the result of an operation", "project", projectID, "operation", operation, "err", dbErr)
+	}
+	return err
+}
+
+// ArchiveProject archives a project by updating its status and deleting its directory, or
+// moving it into the trash when archive_trash_days is set (see TrashRetentionConfig). When it
+// fails, the error is kept as the project's last error until an archive or restore succeeds.
 func ArchiveProject(projectID uint) error {
+	err := recordOperationError(projectID, "archive", archiveProject(projectID))
+	if err == nil {
+		SyncSessionWorkspaces(projectID)
+	}
+	return err
+}
+
+// archiveProject archives a project without recording the outcome
+func archiveProject(projectID uint) error {
 	// Retrieve the project from the database
 	project, err := db.GetProjectByID(projectID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve project: %w", err)
 	}
+	if project.RemoteHostID != 0 {
+		return ErrRemoteProject
+	}
+	if project.ParentID != 0 {
+		return fmt.Errorf("%w: %s", ErrWorktreeProject, project.Name)
+	}
+	if project.Status == "archived" {
+		return fmt.Errorf("%w: %s", ErrAlreadyArchived, project.Name)
+	}
+	if project.Locked {
+		return fmt.Errorf("%w: %s", db.ErrProjectLocked, project.Name)
+	}
+	if err := checkNoWorktrees(project); err != nil {
+		return err
+	}
 
 	// Verify the path exists before attempting deletion
 	if _, err := os.Stat(project.Path); err != nil {
@@ -25,6 +89,11 @@ func ArchiveProject(projectID uint) error {
 			return fmt.Errorf("failed to stat project path: %w", err)
 		}
 		// Path doesn't exist, but we'll still update the status
+	} else if TrashRetentionConfig().Days > 0 {
+		// The checkout stays restorable until the retention policy purges it
+		if err := trashCheckout(project); err != nil {
+			return err
+		}
 	} else {
 		// Path exists, delete it recursively
 		if err := os.RemoveAll(project.Path); err != nil {
@@ -38,25 +107,91 @@ func ArchiveProject(projectID uint) error {
 		return fmt.Errorf("failed to update project status: %w", err)
 	}
 
+	// Webhook failures don't undo the archive
+	_ = Emit(ProjectEvent(EventArchive, *project, ""))
+	return nil
+}
+
+// RestoreProjectTo restores an archived project into another directory, for when drive
+// letters or the directory layout changed since it was archived. The project moves to path
+// and the root folder containing it; when the restore fails it keeps its old location.
+func RestoreProjectTo(projectID uint, path string) error {
+	project, err := db.GetProjectByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if project.RemoteHostID != 0 {
+		return ErrRemoteProject
+	}
+	if project.Status == "active" {
+		return fmt.Errorf("%w: %s", ErrAlreadyActive, project.Name)
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return fmt.Errorf("no directory to restore %s into", project.Name)
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid restore path: %w", err)
+	}
+	if path == project.Path {
+		return RestoreProject(projectID)
+	}
+
+	var rootFolderID uint
+	if rootFolder, err := db.GetRootFolderForPath(path); err != nil {
+		return err
+	} else if rootFolder != nil {
+		rootFolderID = rootFolder.ID
+	}
+	if err := db.UpdateProjectLocation(projectID, path, rootFolderID); err != nil {
+		return err
+	}
+	if err := RestoreProject(projectID); err != nil {
+		_ = db.UpdateProjectLocation(projectID, project.Path, project.RootFolderID)
+		return err
+	}
 	return nil
 }
 
-// RestoreProject restores a project by cloning its repository and updating the status
+// RestoreProject restores a project by moving its checkout back from the trash, or cloning its
+// repository when there is none, and updating the status. When it fails, the error is kept as
+// the project's last error like that of ArchiveProject.
 func RestoreProject(projectID uint) error {
+	err := recordOperationError(projectID, "restore", restoreProject(projectID))
+	if err == nil {
+		SyncSessionWorkspaces(projectID)
+	}
+	return err
+}
+
+// restoreProject restores a project without recording the outcome
+func restoreProject(projectID uint) error {
 	// Retrieve the project from the database
 	project, err := db.GetProjectByID(projectID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve project: %w", err)
 	}
+	if project.RemoteHostID != 0 {
+		return ErrRemoteProject
+	}
+	if project.Status == "active" {
+		return fmt.Errorf("%w: %s", ErrAlreadyActive, project.Name)
+	}
 
+	trashed, err := latestTrashEntry(project.ID)
+	if err != nil {
+		return err
+	}
 	// Validate that the project has a RepoURL
-	if project.RepoURL == "" {
-		return fmt.Errorf("project %s has no repository URL", project.Name)
+	if trashed == nil && project.RepoURL == "" {
+		return fmt.Errorf("%w: %s", ErrNoRepoURL, project.Name)
 	}
 
 	// Ensure the directory does not currently exist
 	if _, err := os.Stat(project.Path); err == nil {
-		return fmt.Errorf("project directory already exists at %s", project.Path)
+		return fmt.Errorf("project directory %w: %s", ErrPathExists, project.Path)
 	} else if !os.IsNotExist(err) {
 		return fmt.Errorf("failed to check project path: %w", err)
 	}
@@ -67,35 +202,68 @@ func RestoreProject(projectID uint) error {
 		return fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
-	// For private repositories, we need to use system git with credential helper
-	// The go-git library doesn't easily integrate with Windows Credential Manager
-	// So we'll fall back to using system git command for authentication
-
-	// Try using system git command which has credential helper configured
-	err = cloneWithSystemGit(project.RepoURL, project.Path)
-	if err != nil {
-		// Clean up the directory if clone fails
-		_ = os.RemoveAll(project.Path)
-		return fmt.Errorf("failed to clone repository from %s: %w", project.RepoURL, err)
+	if trashed != nil {
+		// The checkout in the trash has the uncommitted work too, and needs no network
+		if err := moveDir(trashed.Path, project.Path); err != nil {
+			return fmt.Errorf("failed to restore %s from the trash: %w", project.Name, err)
+		}
+	} else if err := cloneProject(project); err != nil {
+		return err
 	}
 
 	// Update the project status to "active" in the database
 	project.Status = "active"
 	if err := db.UpdateProject(project); err != nil {
 		// Attempt to clean up on update failure
-		_ = os.RemoveAll(project.Path)
+		if trashed != nil {
+			_ = moveDir(project.Path, trashed.Path)
+		} else {
+			_ = os.RemoveAll(project.Path)
+		}
 		return fmt.Errorf("failed to update project status: %w", err)
 	}
+	if trashed != nil {
+		if err := db.DeleteTrashEntry(trashed.ID); err != nil {
+			slog.Warn("Failed to forget a restored project's trash entry", "project", project.Name, "err", err)
+		}
+	}
 
 	// Update the LastOpened timestamp
 	if err := db.UpdateLastOpened(projectID); err != nil {
 		return fmt.Errorf("failed to update last opened timestamp: %w", err)
 	}
 
+	_ = Emit(ProjectEvent(EventRestore, *project, ""))
 	return nil
 }
 
-// DeleteProjectPermanently completely removes a project (DB record + files)
+// cloneProject clones the repository of a project being restored into its path
+func cloneProject(project *models.Project) error {
+	// For private repositories, we need to use system git with credential helper
+	// The go-git library doesn't easily integrate with Windows Credential Manager
+	// So we'll fall back to using system git command for authentication
+
+	opts, err := ProjectCloneOptions(*project)
+	if err != nil {
+		return err
+	}
+
+	// Try using system git command which has credential helper configured
+	// A pinned restore ref is checked out by the clone itself, so long-lived branches survive
+	err = cloneWithSystemGit(context.Background(), project.RepoURL, project.Path, project.RestoreRef, opts)
+	if err != nil {
+		// Clean up the directory if clone fails
+		_ = os.RemoveAll(project.Path)
+		if project.RestoreRef != "" {
+			return fmt.Errorf("failed to clone %s of repository %s: %w", project.RestoreRef, project.RepoURL, err)
+		}
+		return fmt.Errorf("failed to clone repository from %s: %w", project.RepoURL, err)
+	}
+	return nil
+}
+
+// DeleteProjectPermanently completely removes a project (DB record + files, including its
+// checkouts in the trash)
 // WARNING: This is destructive and cannot be undone
 func DeleteProjectPermanently(projectID uint) error {
 	// Retrieve the project from the database
@@ -103,20 +271,32 @@ func DeleteProjectPermanently(projectID uint) error {
 	if err != nil {
 		return fmt.Errorf("failed to retrieve project: %w", err)
 	}
+	if project.Locked {
+		return fmt.Errorf("%w: %s", db.ErrProjectLocked, project.Name)
+	}
 
-	// Delete the physical directory if it exists
-	if _, err := os.Stat(project.Path); err == nil {
-		if err := os.RemoveAll(project.Path); err != nil {
-			return fmt.Errorf("failed to delete project directory: %w", err)
+	// Delete the physical directory if it exists. Remote files are never touched.
+	if project.RemoteHostID == 0 {
+		if err := checkNoWorktrees(project); err != nil {
+			return err
+		}
+		if _, err := os.Stat(project.Path); err == nil {
+			if err := os.RemoveAll(project.Path); err != nil {
+				return fmt.Errorf("failed to delete project directory: %w", err)
+			}
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to check project path: %w", err)
+		}
+		if err := purgeProjectTrash(projectID); err != nil {
+			return err
 		}
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to check project path: %w", err)
 	}
 
 	// Delete the database record
 	if err := db.DeleteProject(projectID); err != nil {
 		return fmt.Errorf("failed to delete project from database: %w", err)
 	}
+	SyncSessionWorkspaces(projectID)
 
 	return nil
 }
@@ -146,6 +326,32 @@ func VerifyProjectPath(projectID uint) (bool, error) {
 	return false, err // Error checking path
 }
 
+// MissingProjectPaths checks a set of project paths keyed by project ID and returns the IDs
+// whose directory no longer exists. Paths that can't be checked for other reasons are not reported,
+// including paths in WSL distributions that aren't reachable.
+func MissingProjectPaths(paths map[uint]string) map[uint]bool {
+	missing := make(map[uint]bool)
+	reachable := make(map[string]bool)
+	for id, path := range paths {
+		// A Linux path registered from inside WSL can't be checked without knowing its distribution
+		if runtime.GOOS == "windows" && strings.HasPrefix(path, "/") {
+			continue
+		}
+		if w, ok := ParseWSLPath(path); ok {
+			if _, checked := reachable[w.Distro]; !checked {
+				reachable[w.Distro] = wslDistroReachable(w.Distro)
+			}
+			if !reachable[w.Distro] {
+				continue
+			}
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			missing[id] = true
+		}
+	}
+	return missing
+}
+
 // GetLatestCommitHash retrieves the latest commit hash of a project's git repository
 func GetLatestCommitHash(projectID uint) (string, error) {
 	project, err := db.GetProjectByID(projectID)
@@ -194,7 +400,7 @@ func ArchiveWithVerification(projectID uint) error {
 
 	// Skip if already archived
 	if project.Status == "archived" {
-		return fmt.Errorf("project is already archived")
+		return fmt.Errorf("%w: %s", ErrAlreadyArchived, project.Name)
 	}
 
 	// Verify the path exists and is readable
@@ -218,23 +424,57 @@ func RestoreWithVerification(projectID uint) error {
 
 	// Skip if already active
 	if project.Status == "active" {
-		return fmt.Errorf("project is already active")
+		return fmt.Errorf("%w: %s", ErrAlreadyActive, project.Name)
 	}
 
-	// Verify the project has a repository URL
+	// Verify the project has a repository URL or a checkout in the trash
 	if project.RepoURL == "" {
-		return fmt.Errorf("cannot restore project without a repository URL")
+		if trashed, err := latestTrashEntry(project.ID); err != nil {
+			return err
+		} else if trashed == nil {
+			return fmt.Errorf("cannot restore project: %w: %s", ErrNoRepoURL, project.Name)
+		}
 	}
 
 	// Proceed with restoration
 	return RestoreProject(projectID)
 }
 
-// CloneRepository clones a git repository to the specified destination path
-func CloneRepository(repoURL, destPath string) error {
+// SetRestoreRef pins the branch or tag a project is checked out at when it is restored. An
+// empty ref restores the default branch again.
+func SetRestoreRef(projectID uint, ref string) error {
+	ref = strings.TrimSpace(ref)
+	if err := ValidateRef(ref); err != nil {
+		return err
+	}
+	return db.UpdateProjectRestoreRef(projectID, ref)
+}
+
+// ValidateRef checks that ref can name a branch or tag, following git check-ref-format.
+// Refs starting with "-" are rejected so they can't be taken for git options.
+func ValidateRef(ref string) error {
+	if ref == "" {
+		return nil
+	}
+	invalid := ref == "@" || strings.HasPrefix(ref, "-") || strings.HasPrefix(ref, "/") ||
+		strings.HasSuffix(ref, "/") || strings.HasSuffix(ref, ".") || strings.HasSuffix(ref, ".lock") ||
+		strings.Contains(ref, "..") || strings.Contains(ref, "//") || strings.Contains(ref, "@{") ||
+		strings.ContainsAny(ref, " ~^:?*[\\") || strings.ContainsFunc(ref, unicode.IsControl)
+	for _, part := range strings.Split(ref, "/") {
+		invalid = invalid || strings.HasPrefix(part, ".")
+	}
+	if invalid {
+		return fmt.Errorf("%q is not a valid branch or tag name", ref)
+	}
+	return nil
+}
+
+// CloneRepository clones a git repository to the specified destination path. Cancelling
+// ctx stops git and removes what was cloned.
+func CloneRepository(ctx context.Context, repoURL, destPath string) error {
 	// Ensure the directory does not currently exist
 	if _, err := os.Stat(destPath); err == nil {
-		return fmt.Errorf("destination path already exists: %s", destPath)
+		return fmt.Errorf("destination %w: %s", ErrPathExists, destPath)
 	} else if !os.IsNotExist(err) {
 		return fmt.Errorf("failed to check destination path: %w", err)
 	}
@@ -245,21 +485,50 @@ func CloneRepository(repoURL, destPath string) error {
 		return fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
+	opts, err := GlobalCloneOptions()
+	if err != nil {
+		return err
+	}
+
 	// Clone using system git
-	return cloneWithSystemGit(repoURL, destPath)
+	return cloneWithSystemGit(ctx, repoURL, destPath, "", opts)
 }
 
 // cloneWithSystemGit uses the system's git command to clone a repository
 // This allows using the system's credential helper (Windows Credential Manager, etc.)
-func cloneWithSystemGit(repoURL, destPath string) error {
-	// Use git clone with depth 1 for faster cloning
-	cmd := exec.Command("git", "clone", "--depth", "1", repoURL, destPath)
+// and falls back to the other protocol (HTTPS or SSH) when credentials are rejected
+func cloneWithSystemGit(ctx context.Context, repoURL, destPath, ref string, opts CloneOptions) error {
+	return cloneWithAuthFallback(ctx, repoURL, destPath, ref, opts)
+}
+
+// runGitClone runs a git clone of ref, or of the default branch when ref is empty, with the
+// clone options (by default only the latest commit), and returns its combined output
+func runGitClone(ctx context.Context, repoURL, destPath, ref string, opts CloneOptions) (string, error) {
+	args := append([]string{"clone"}, opts.args()...)
+	if ref != "" {
+		// --branch takes tags too and checks the ref out
+		args = append(args, "--branch", ref)
+	}
+	cmd := exec.CommandContext(ctx, "git", append(args, "--", repoURL, destPath)...)
+	// Fail instead of waiting for a username or passphrase the TUI can't show
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" && exec.Command("git", "config", "--get", "core.sshCommand").Run() != nil {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
 
 	// Capture output for better error messages
 	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// RecordOpen counts an open of a project like db.RecordOpen and tells the plugins about it.
+// Opens within db.OpenDebounce aren't counted but still emitted. Plugins run before it
+// returns, so call it from background work.
+func RecordOpen(project models.Project) (bool, error) {
+	counted, err := db.RecordOpen(project.ID)
 	if err != nil {
-		return fmt.Errorf("%w: %s", err, string(output))
+		return false, err
 	}
-
-	return nil
+	_ = Emit(ProjectEvent(EventOpen, project, ""))
+	return counted, nil
 }
@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCloneRepositoryCancelled(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	source := t.TempDir()
+	dest := filepath.Join(t.TempDir(), "clone")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := CloneRepository(ctx, source, dest); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled clone to fail with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("Expected a cancelled clone to leave nothing behind")
+	}
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// pathMapSeparator separates the prefixes of a rule in the path_map config key
+const pathMapSeparator = "=>"
+
+// windowsDrivePattern matches paths starting with a drive letter, e.g. D:\ or d:/
+var windowsDrivePattern = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// PathMapping rewrites paths synced from another machine: paths under From are moved
+// under To. From is written as on the other machine, To as on this one.
+type PathMapping struct {
+	From string
+	To   string
+}
+
+// String formats the mapping as it is stored in the path_map config key
+func (p PathMapping) String() string {
+	return p.From + " " + pathMapSeparator + " " + p.To
+}
+
+// ParsePathMappings parses the path_map config key: comma-separated "from => to" rules
+func ParsePathMappings(value string) ([]PathMapping, error) {
+	var mappings []PathMapping
+	for _, rule := range strings.Split(value, ",") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(rule, pathMapSeparator)
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid path mapping %q, expected \"from %s to\"", strings.TrimSpace(rule), pathMapSeparator)
+		}
+		mappings = append(mappings, PathMapping{From: from, To: to})
+	}
+	return mappings, nil
+}
+
+// PathMappings returns this machine's path mapping rules from the path_map config key
+func PathMappings() ([]PathMapping, error) {
+	value, _ := db.GetConfig("path_map")
+	return ParsePathMappings(value)
+}
+
+// SetPathMappings stores the path mapping rules in the path_map config key
+func SetPathMappings(mappings []PathMapping) error {
+	rules := make([]string, len(mappings))
+	for i, m := range mappings {
+		if strings.Contains(m.From+m.To, ",") || strings.Contains(m.From+m.To, pathMapSeparator) {
+			return fmt.Errorf("paths in a mapping can't contain ',' or %q", pathMapSeparator)
+		}
+		rules[i] = m.String()
+	}
+	return db.SetConfig("path_map", strings.Join(rules, ", "))
+}
+
+// MapPath rewrites a path from another machine with the longest matching rule. Prefixes
+// match whole path elements, with / and \ treated alike and Windows paths compared without
+// case. The rest of the path is joined to To with this machine's separator.
+func MapPath(path string, mappings []PathMapping) (string, bool) {
+	best, bestLen := "", -1
+	for _, m := range mappings {
+		rest, ok := cutPathPrefix(path, m.From)
+		if !ok || len(m.From) <= bestLen {
+			continue
+		}
+		best, bestLen = filepath.Join(append([]string{expandHome(m.To)}, splitForeignPath(rest)...)...), len(m.From)
+	}
+	return best, bestLen >= 0
+}
+
+// cutPathPrefix returns the rest of path after prefix when prefix is made of its leading elements
+func cutPathPrefix(path, prefix string) (string, bool) {
+	p := strings.ReplaceAll(path, `\`, "/")
+	pre := strings.TrimRight(strings.ReplaceAll(prefix, `\`, "/"), "/")
+	if pre == "" || len(p) < len(pre) {
+		return "", false
+	}
+	head, rest := p[:len(pre)], p[len(pre):]
+	equal := head == pre
+	if windowsDrivePattern.MatchString(pre+"/") || strings.HasPrefix(pre, "//") {
+		equal = strings.EqualFold(head, pre)
+	}
+	if !equal || rest != "" && rest[0] != '/' {
+		return "", false
+	}
+	return strings.TrimLeft(rest, "/"), true
+}
+
+// splitForeignPath splits a path written with / or \ separators into its elements
+func splitForeignPath(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' })
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// MapCloudPaths rewrites the paths of projects loaded from a cloud backup for this machine.
+// Paths matching a rule are rewritten with it; paths that can't exist here, such as
+// D:\Projects\X on Linux or /home/me/x on Windows, are placed in root by their last element.
+// Other paths are kept. It returns how many paths changed.
+func MapCloudPaths(projects []models.Project, mappings []PathMapping, root string) int {
+	changed := 0
+	for i := range projects {
+		path := projects[i].Path
+		if mapped, ok := MapPath(path, mappings); ok {
+			path = mapped
+		} else if root != "" && !filepath.IsAbs(path) {
+			if elements := splitForeignPath(path); len(elements) > 0 {
+				path = filepath.Join(root, elements[len(elements)-1])
+			}
+		}
+		if path != projects[i].Path {
+			projects[i].Path = path
+			changed++
+		}
+	}
+	return changed
+}
+
+// ReplacePathMapping adds a rule, replacing the rule for the same prefix
+func ReplacePathMapping(mappings []PathMapping, mapping PathMapping) []PathMapping {
+	mappings = slices.DeleteFunc(slices.Clone(mappings), func(m PathMapping) bool {
+		return samePathPrefix(m.From, mapping.From)
+	})
+	return append(mappings, mapping)
+}
+
+// RemovePathMapping removes the rule for a prefix and reports whether there was one
+func RemovePathMapping(mappings []PathMapping, from string) ([]PathMapping, bool) {
+	kept := slices.DeleteFunc(slices.Clone(mappings), func(m PathMapping) bool {
+		return samePathPrefix(m.From, from)
+	})
+	return kept, len(kept) < len(mappings)
+}
+
+// samePathPrefix reports whether two prefixes name the same directory
+func samePathPrefix(a, b string) bool {
+	rest, ok := cutPathPrefix(a, b)
+	return ok && rest == ""
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"devbase/models"
+)
+
+func TestMapCloudPaths(t *testing.T) {
+	mappings, err := ParsePathMappings(`D:\Projects => /home/me/code, D:\Projects\clients => /srv/clients, /Users/me/dev => /home/me/code`)
+	if err != nil {
+		t.Fatalf("ParsePathMappings failed: %v", err)
+	}
+	if _, err := ParsePathMappings("D:\\Projects /home/me/code"); err == nil {
+		t.Error("Expected a rule without => to be rejected")
+	}
+
+	root := filepath.Join(t.TempDir(), "code")
+	projects := []models.Project{
+		{Name: "api", Path: `D:\Projects\api`},
+		{Name: "portal", Path: `d:/projects/clients/Portal`},
+		{Name: "site", Path: "/Users/me/dev/site"},
+		{Name: "similar", Path: "/Users/me/developer/tool"},
+		{Name: "stray", Path: `E:\Stuff\stray`},
+	}
+	MapCloudPaths(projects, mappings, root)
+
+	want := map[string]string{
+		"api":    filepath.Join("/home/me/code", "api"),
+		"portal": filepath.Join("/srv/clients", "Portal"),
+		"site":   filepath.Join("/home/me/code", "site"),
+	}
+	if runtime.GOOS != "windows" {
+		// Unix paths are kept as they can exist here; drive paths can't, so they go to the root
+		want["similar"] = "/Users/me/developer/tool"
+		want["stray"] = filepath.Join(root, "stray")
+	}
+	for _, p := range projects {
+		if expected, ok := want[p.Name]; ok && p.Path != expected {
+			t.Errorf("%s: expected %s, got %s", p.Name, expected, p.Path)
+		}
+	}
+
+	mappings = ReplacePathMapping(mappings, PathMapping{From: `d:/projects/`, To: "~/work"})
+	if len(mappings) != 3 || mappings[2].To != "~/work" {
+		t.Errorf("Expected the D:\\Projects rule to be replaced, got %v", mappings)
+	}
+	if mappings, removed := RemovePathMapping(mappings, "/Users/me/dev"); !removed || len(mappings) != 2 {
+		t.Errorf("Expected the /Users/me/dev rule to be removed, got %v", mappings)
+	}
+}
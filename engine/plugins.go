@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// PluginPrefix starts the names of plugin executables found on PATH, e.g. devbase-deploy
+const PluginPrefix = "devbase-"
+
+// How long a plugin may take to describe itself, handle an event and run an action
+const (
+	pluginDescribeTimeout = 3 * time.Second
+	pluginEventTimeout    = 10 * time.Second
+	pluginActionTimeout   = time.Minute
+)
+
+// Plugin is an external executable that receives events and contributes actions. The
+// protocol is one run per request with JSON on stdin and stdout:
+//
+//	devbase-x describe      prints {"name": ..., "events": [...], "actions": [{"id": ..., "title": ...}]}
+//	devbase-x event         reads an Event
+//	devbase-x action <id>   reads {"action": id, "project": Project}, prints a status line
+type Plugin struct {
+	Name    string         `json:"name"`
+	Path    string         `json:"-"`
+	Events  []string       `json:"events"` // Event kinds the plugin receives, empty for none
+	Actions []PluginAction `json:"actions"`
+}
+
+// PluginAction is a command a plugin adds to the command palette
+type PluginAction struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// pluginActionRequest is written to a plugin running an action
+type pluginActionRequest struct {
+	Action  string          `json:"action"`
+	Project *models.Project `json:"project,omitempty"`
+}
+
+var (
+	pluginsOnce sync.Once
+	plugins     []Plugin
+)
+
+// Plugins returns the installed plugins: executables named devbase-* on PATH and the paths
+// in the "plugins" config key (plugins = [...] in config.toml). They are discovered on the
+// first call; plugins that fail to describe themselves are logged and skipped.
+func Plugins() []Plugin {
+	pluginsOnce.Do(func() {
+		for _, path := range pluginPaths() {
+			plugin, err := describePlugin(path)
+			if err != nil {
+				slog.Warn("Plugin ignored", "path", path, "err", err)
+				continue
+			}
+			plugins = append(plugins, plugin)
+		}
+		if len(plugins) > 0 {
+			slog.Debug("Loaded plugins", "count", len(plugins))
+		}
+	})
+	return plugins
+}
+
+// pluginPaths lists the plugin executables, configured ones first. A name found in several
+// PATH directories is used from the first, like the shell does.
+func pluginPaths() []string {
+	var paths []string
+	if configured, err := db.GetConfig("plugins"); err == nil {
+		for _, path := range strings.Split(configured, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, PluginPrefix) || entry.IsDir() || seen[pluginName(name)] {
+				continue
+			}
+			// LookPath checks the executable bit, or PATHEXT on Windows
+			path, err := exec.LookPath(filepath.Join(dir, name))
+			if err != nil || slices.Contains(paths, path) {
+				continue
+			}
+			seen[pluginName(name)] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// pluginName derives a plugin's name from its executable, e.g. devbase-deploy.exe is deploy
+func pluginName(file string) string {
+	name := strings.TrimPrefix(filepath.Base(file), PluginPrefix)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// describePlugin asks a plugin for its name, events and actions
+func describePlugin(path string) (Plugin, error) {
+	out, err := runPlugin(path, pluginDescribeTimeout, nil, "describe")
+	if err != nil {
+		return Plugin{}, err
+	}
+	var plugin Plugin
+	if err := json.Unmarshal(out, &plugin); err != nil {
+		return Plugin{}, fmt.Errorf("invalid describe output: %w", err)
+	}
+	plugin.Path = path
+	if plugin.Name == "" {
+		plugin.Name = pluginName(path)
+	}
+	plugin.Actions = slices.DeleteFunc(plugin.Actions, func(a PluginAction) bool { return a.ID == "" || a.Title == "" })
+	return plugin, nil
+}
+
+// RunAction runs one of the plugin's actions for a project (nil when none is selected) and
+// returns the last line it printed
+func (p Plugin) RunAction(id string, project *models.Project) (string, error) {
+	input, err := json.Marshal(pluginActionRequest{Action: id, Project: project})
+	if err != nil {
+		return "", err
+	}
+	out, err := runPlugin(p.Path, pluginActionTimeout, input, "action", id)
+	if err != nil {
+		return "", fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// deliverPlugins sends the event to the plugins subscribed to its kind
+func deliverPlugins(event Event) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, plugin := range Plugins() {
+		if !slices.Contains(plugin.Events, event.Kind) {
+			continue
+		}
+		if _, err := runPlugin(plugin.Path, pluginEventTimeout, input, "event"); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", plugin.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runPlugin runs a plugin with input on stdin and returns its stdout. A failing plugin's
+// error includes the last line it wrote to stderr.
+func runPlugin(path string, timeout time.Duration, input []byte, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s timed out after %s", args[0], timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			lines := strings.Split(msg, "\n")
+			return nil, fmt.Errorf("%s failed: %s", args[0], lines[len(lines)-1])
+		}
+		return nil, fmt.Errorf("%s failed: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
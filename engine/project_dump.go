@@ -0,0 +1,395 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// Formats of project dumps
+const (
+	DumpJSON = "json"
+	DumpCSV  = "csv"
+)
+
+const (
+	dumpFormat  = "devbase-projects"
+	dumpVersion = 1
+)
+
+// DumpProject is a project in a dump written by ExportProjects. IDs only mean something in
+// one database, so the project's root folder is named by its path.
+type DumpProject struct {
+	Name         string    `json:"name"`
+	Path         string    `json:"path"`
+	RootFolder   string    `json:"root_folder,omitempty"` // Path of the root folder, empty for none
+	Status       string    `json:"status"`
+	RepoURL      string    `json:"repo_url,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Language     string    `json:"language,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+	Icon         string    `json:"icon,omitempty"`
+	Editor       string    `json:"editor,omitempty"`
+	RestoreRef   string    `json:"restore_ref,omitempty"`
+	CloneOptions string    `json:"clone_options,omitempty"`
+	Locked       bool      `json:"locked,omitempty"`
+	NoReclaim    bool      `json:"no_reclaim,omitempty"`
+	OpenCount    int       `json:"open_count,omitempty"`
+	Machine      string    `json:"machine,omitempty"`
+	LastOpened   time.Time `json:"last_opened"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// projectDump is the JSON form of a dump
+type projectDump struct {
+	Format     string        `json:"format"`
+	Version    int           `json:"version"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Projects   []DumpProject `json:"projects"`
+}
+
+// dumpColumns are the columns of a CSV dump, in order. Tags are joined with commas.
+var dumpColumns = []string{
+	"name", "path", "root_folder", "status", "repo_url", "description", "language", "tags", "notes", "icon", "editor",
+	"restore_ref", "clone_options", "locked", "no_reclaim", "open_count", "machine", "last_opened", "created_at", "updated_at",
+}
+
+// DumpImport reports what ImportProjects stored
+type DumpImport struct {
+	Added      int
+	Archived   int      // Added as archived because their directory isn't on this machine
+	Duplicates []string // Paths already registered, which are left as they are
+}
+
+// ExportProjects collects the projects of every root folder for a dump. Remote projects
+// belong to their SSH host rather than a machine to migrate, and are left out.
+func ExportProjects() ([]DumpProject, error) {
+	projects, err := db.GetAllProjects()
+	if err != nil {
+		return nil, err
+	}
+	rootFolders, err := db.GetAllRootFolders()
+	if err != nil {
+		return nil, err
+	}
+	rootPaths := make(map[uint]string, len(rootFolders))
+	for _, rootFolder := range rootFolders {
+		rootPaths[rootFolder.ID] = rootFolder.Path
+	}
+
+	dump := []DumpProject{}
+	for _, p := range projects {
+		if p.RemoteHostID != 0 {
+			continue
+		}
+		dump = append(dump, DumpProject{
+			Name:         p.Name,
+			Path:         p.Path,
+			RootFolder:   rootPaths[p.RootFolderID],
+			Status:       p.Status,
+			RepoURL:      p.RepoURL,
+			Description:  p.Description,
+			Language:     p.Language,
+			Tags:         p.Tags,
+			Notes:        p.Notes,
+			Icon:         p.Icon,
+			Editor:       p.Editor,
+			RestoreRef:   p.RestoreRef,
+			CloneOptions: p.CloneOptions,
+			Locked:       p.Locked,
+			NoReclaim:    p.NoReclaim,
+			OpenCount:    p.OpenCount,
+			Machine:      p.Machine,
+			LastOpened:   p.LastOpened,
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
+		})
+	}
+	return dump, nil
+}
+
+// EncodeProjects writes a dump as indented JSON, or as CSV with a header row
+func EncodeProjects(projects []DumpProject, format string) ([]byte, error) {
+	switch format {
+	case DumpJSON:
+		data, err := json.MarshalIndent(projectDump{
+			Format:     dumpFormat,
+			Version:    dumpVersion,
+			ExportedAt: time.Now().UTC().Truncate(time.Second),
+			Projects:   projects,
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode projects: %w", err)
+		}
+		return append(data, '\n'), nil
+	case DumpCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write(dumpColumns)
+		formatTime := func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.UTC().Format(time.RFC3339)
+		}
+		for _, p := range projects {
+			_ = w.Write([]string{
+				p.Name, p.Path, p.RootFolder, p.Status, p.RepoURL, p.Description, p.Language, strings.Join(p.Tags, ","), p.Notes, p.Icon, p.Editor,
+				p.RestoreRef, p.CloneOptions, strconv.FormatBool(p.Locked), strconv.FormatBool(p.NoReclaim), strconv.Itoa(p.OpenCount), p.Machine,
+				formatTime(p.LastOpened), formatTime(p.CreatedAt), formatTime(p.UpdatedAt),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("failed to encode projects: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown dump format %q (expected json or csv)", format)
+}
+
+// DecodeProjects reads a dump written by EncodeProjects. An empty format is detected from
+// the data; JSON may also be a plain array of projects, such as devbase list --json prints.
+func DecodeProjects(data []byte, format string) ([]DumpProject, error) {
+	trimmed := bytes.TrimSpace(data)
+	if format == "" {
+		format = DumpCSV
+		if bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")) {
+			format = DumpJSON
+		}
+	}
+
+	var projects []DumpProject
+	switch format {
+	case DumpJSON:
+		if bytes.HasPrefix(trimmed, []byte("[")) {
+			if err := json.Unmarshal(trimmed, &projects); err != nil {
+				return nil, fmt.Errorf("invalid project dump: %w", err)
+			}
+			break
+		}
+		var dump projectDump
+		if err := json.Unmarshal(trimmed, &dump); err != nil {
+			return nil, fmt.Errorf("invalid project dump: %w", err)
+		}
+		if dump.Format != dumpFormat {
+			return nil, fmt.Errorf("not a DevBase project dump")
+		}
+		if dump.Version > dumpVersion {
+			return nil, fmt.Errorf("project dump version %d is newer than this DevBase supports; update DevBase", dump.Version)
+		}
+		projects = dump.Projects
+	case DumpCSV:
+		var err error
+		if projects, err = decodeProjectsCSV(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown dump format %q (expected json or csv)", format)
+	}
+
+	for i, p := range projects {
+		if strings.TrimSpace(p.Path) == "" {
+			return nil, fmt.Errorf("project %d (%s) has no path", i+1, p.Name)
+		}
+	}
+	return projects, nil
+}
+
+// decodeProjectsCSV reads a CSV dump by its header, so columns may be reordered or left out
+// when the file was edited in a spreadsheet. Only the path column is required.
+func decodeProjectsCSV(data []byte) ([]DumpProject, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid project dump: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("invalid project dump: no header row")
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["path"]; !ok {
+		return nil, fmt.Errorf("invalid project dump: no path column")
+	}
+
+	projects := make([]DumpProject, 0, len(records)-1)
+	for n, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := n + 2
+		parseTime := func(name string) (time.Time, error) {
+			value := field(name)
+			if value == "" {
+				return time.Time{}, nil
+			}
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("row %d: invalid %s %q", row, name, value)
+			}
+			return t, nil
+		}
+		parseBool := func(name string) (bool, error) {
+			value := field(name)
+			if value == "" {
+				return false, nil
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return false, fmt.Errorf("row %d: invalid %s %q", row, name, value)
+			}
+			return b, nil
+		}
+
+		p := DumpProject{
+			Name:         field("name"),
+			Path:         field("path"),
+			RootFolder:   field("root_folder"),
+			Status:       field("status"),
+			RepoURL:      field("repo_url"),
+			Description:  field("description"),
+			Language:     field("language"),
+			Notes:        field("notes"),
+			Icon:         field("icon"),
+			Editor:       field("editor"),
+			RestoreRef:   field("restore_ref"),
+			CloneOptions: field("clone_options"),
+			Machine:      field("machine"),
+		}
+		for _, tag := range strings.Split(field("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				p.Tags = append(p.Tags, tag)
+			}
+		}
+		if value := field("open_count"); value != "" {
+			if p.OpenCount, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("row %d: invalid open_count %q", row, value)
+			}
+		}
+		if p.Locked, err = parseBool("locked"); err != nil {
+			return nil, err
+		}
+		if p.NoReclaim, err = parseBool("no_reclaim"); err != nil {
+			return nil, err
+		}
+		if p.LastOpened, err = parseTime("last_opened"); err != nil {
+			return nil, err
+		}
+		if p.CreatedAt, err = parseTime("created_at"); err != nil {
+			return nil, err
+		}
+		if p.UpdatedAt, err = parseTime("updated_at"); err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, nil
+}
+
+// ImportProjects registers the projects of a dump, after rewriting their paths with the
+// path_map rules. Projects whose path is already registered are duplicates and left as they
+// are. A project joins the registered root folder the dump names, or else the one containing
+// it, or the active one. Projects whose directory isn't on this machine come in archived, so
+// r restores them from their repository like projects loaded from the cloud.
+func ImportProjects(projects []DumpProject) (*DumpImport, error) {
+	result := &DumpImport{}
+	mappings, err := PathMappings()
+	if err != nil {
+		return result, fmt.Errorf("path_map config key: %w", err)
+	}
+	mapPath := func(path string) string {
+		if mapped, ok := MapPath(path, mappings); ok {
+			path = mapped
+		}
+		return filepath.Clean(expandHome(path))
+	}
+	var activeRootID uint
+	if activeRoot, err := db.GetActiveRootFolder(); err == nil {
+		activeRootID = activeRoot.ID
+	}
+
+	for _, entry := range projects {
+		path := mapPath(entry.Path)
+		if _, err := db.GetProjectByPath(path); err == nil {
+			result.Duplicates = append(result.Duplicates, path)
+			continue
+		} else if !errors.Is(err, db.ErrProjectNotFound) {
+			return result, err
+		}
+
+		project := models.Project{
+			Name:         entry.Name,
+			Path:         path,
+			RepoURL:      entry.RepoURL,
+			Description:  entry.Description,
+			Status:       "active",
+			LastOpened:   entry.LastOpened,
+			OpenCount:    entry.OpenCount,
+			Tags:         entry.Tags,
+			Language:     entry.Language,
+			Notes:        entry.Notes,
+			Editor:       entry.Editor,
+			NoReclaim:    entry.NoReclaim,
+			Locked:       entry.Locked,
+			Icon:         entry.Icon,
+			RestoreRef:   entry.RestoreRef,
+			CloneOptions: entry.CloneOptions,
+			Machine:      entry.Machine,
+			RootFolderID: activeRootID,
+			CreatedAt:    entry.CreatedAt,
+		}
+		if project.Name == "" {
+			project.Name = filepath.Base(path)
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() || entry.Status == "archived" {
+			project.Status = "archived"
+		} else {
+			project.DevContainer = HasDevContainer(path)
+		}
+
+		var rootFolder *models.RootFolder
+		if entry.RootFolder != "" {
+			rootFolder, err = db.GetRootFolderByPath(mapPath(entry.RootFolder))
+			if errors.Is(err, db.ErrRootFolderNotFound) {
+				rootFolder, err = nil, nil
+			}
+		}
+		if rootFolder == nil && err == nil {
+			rootFolder, err = db.GetRootFolderForPath(path)
+		}
+		if err != nil {
+			return result, err
+		}
+		if rootFolder != nil {
+			project.RootFolderID = rootFolder.ID
+		}
+
+		if err := db.AddProject(&project); err != nil {
+			return result, fmt.Errorf("%s: %w", path, err)
+		}
+		result.Added++
+		if project.Status == "archived" {
+			result.Archived++
+		}
+	}
+	slices.Sort(result.Duplicates)
+	return result, nil
+}
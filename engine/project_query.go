@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ListProjects returns the projects of the active root folder with a status ("active",
+// "archived", or "" for both) that carry every one of the comma-separated tags, which are
+// normalized like stored ones, most recently opened first. It returns an empty list, not nil, when none match.
+func ListProjects(status, tags string) ([]models.Project, error) {
+	all, err := db.GetProjectsByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	var wanted []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = db.NormalizeTag(tag); tag != "" {
+			wanted = append(wanted, tag)
+		}
+	}
+	projects := []models.Project{}
+	for _, project := range all {
+		if !slices.ContainsFunc(wanted, func(tag string) bool { return !slices.Contains(project.Tags, tag) }) {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+// ErrNoProjectNamed is returned by FindProject when no project has the name
+var ErrNoProjectNamed = errors.New("no project named")
+
+// FindProject resolves a project path or name (ignoring case). When several projects share
+// the name, the only active one wins; otherwise the caller has to pass a path.
+func FindProject(target string) (*models.Project, error) {
+	if abs, err := filepath.Abs(target); err == nil {
+		if project, err := db.GetProjectByPath(abs); err == nil || !errors.Is(err, db.ErrProjectNotFound) {
+			return project, err
+		}
+	}
+	if project, err := db.GetProjectByPath(target); err == nil || !errors.Is(err, db.ErrProjectNotFound) {
+		return project, err
+	}
+
+	projects, err := db.GetProjectsByName(target)
+	if err != nil {
+		return nil, err
+	}
+	var active []models.Project
+	var paths []string
+	for _, p := range projects {
+		if p.Status != "archived" {
+			active = append(active, p)
+		}
+		paths = append(paths, p.Path)
+	}
+	switch {
+	case len(projects) == 0:
+		return nil, fmt.Errorf("%w %q", ErrNoProjectNamed, target)
+	case len(projects) == 1 && len(active) == 0:
+		return nil, fmt.Errorf("%s is archived; restore it in DevBase first", projects[0].Name)
+	case len(active) == 1:
+		return &active[0], nil
+	}
+	return nil, fmt.Errorf("%d projects are named %q, pass the path instead:\n  %s", len(projects), target, strings.Join(paths, "\n  "))
+}
+
+// MatchProject resolves a project like FindProject, falling back to the active projects
+// whose name starts with the query (ignoring case), or else those it fuzzy-matches. One match
+// is used; of several, first picks the most recently opened prefix match or the best fuzzy
+// match, and without it they are listed.
+func MatchProject(query string, first bool) (*models.Project, error) {
+	project, err := FindProject(query)
+	if !errors.Is(err, ErrNoProjectNamed) {
+		return project, err
+	}
+
+	active, err := db.GetActiveProjects()
+	if err != nil {
+		return nil, err
+	}
+	var candidates []models.Project
+	for _, p := range active {
+		if strings.HasPrefix(strings.ToLower(p.Name), strings.ToLower(query)) {
+			candidates = append(candidates, p)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b models.Project) int {
+		return b.LastOpened.Compare(a.LastOpened)
+	})
+	if len(candidates) == 0 {
+		names := make([]string, len(active))
+		for i, p := range active {
+			names[i] = p.Name
+		}
+		for _, match := range fuzzy.Find(query, names) {
+			candidates = append(candidates, active[match.Index])
+		}
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("no active project matches %q", query)
+	case len(candidates) == 1 || first:
+		return &candidates[0], nil
+	}
+	names := make([]string, 0, min(len(candidates), 10))
+	for _, p := range candidates[:cap(names)] {
+		names = append(names, p.Name)
+	}
+	if len(candidates) > len(names) {
+		names = append(names, "…")
+	}
+	return nil, fmt.Errorf("%d projects match %q: %s; be more specific or pass --first", len(candidates), query, strings.Join(names, ", "))
+}
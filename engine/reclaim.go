@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// reclaimRule is a dependency or build folder that tools recreate, so deleting it only
+// costs a reinstall or rebuild. The folder only counts when one of its markers exists,
+// so e.g. a "build" directory of hand-written files is never offered.
+type reclaimRule struct {
+	dir     string
+	markers []string // Paths relative to the project
+}
+
+var reclaimRules = []reclaimRule{
+	{"node_modules", []string{"package.json"}},
+	{".next", []string{"package.json"}},
+	{".nuxt", []string{"package.json"}},
+	{".svelte-kit", []string{"package.json"}},
+	{".turbo", []string{"package.json"}},
+	{".parcel-cache", []string{"package.json"}},
+	{"target", []string{"Cargo.toml", "pom.xml"}},
+	{".venv", []string{".venv/pyvenv.cfg"}},
+	{"venv", []string{"venv/pyvenv.cfg"}},
+	{".tox", []string{"tox.ini"}},
+	{".pytest_cache", []string{".pytest_cache/CACHEDIR.TAG"}},
+	{".mypy_cache", []string{".mypy_cache/CACHEDIR.TAG"}},
+	{".gradle", []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}},
+	{"build", []string{"build.gradle", "build.gradle.kts"}},
+	{".dart_tool", []string{"pubspec.yaml"}},
+}
+
+// ReclaimDir is a dependency or build folder that can be deleted to reclaim space
+type ReclaimDir struct {
+	Name string // Folder name, e.g. "node_modules"
+	Path string
+	Size int64
+}
+
+// ReclaimableProject is a project with dependency or build folders
+type ReclaimableProject struct {
+	Project models.Project
+	Dirs    []ReclaimDir
+	Size    int64 // Total size of Dirs
+}
+
+// ReclaimableSpace measures the dependency and build folders at the top level of every
+// active local project, in all root folders, largest first. Projects that opted out are
+// included, so they can opt back in; check Project.NoReclaim before deleting.
+func ReclaimableSpace() ([]ReclaimableProject, error) {
+	projects, err := db.GetActiveProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	const workerCount = 10
+	jobs := make(chan models.Project)
+	var (
+		mu          sync.Mutex
+		wg          sync.WaitGroup
+		reclaimable []ReclaimableProject
+	)
+	for range workerCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for project := range jobs {
+				if dirs := reclaimDirs(project.Path); len(dirs) > 0 {
+					p := ReclaimableProject{Project: project, Dirs: dirs}
+					for _, dir := range dirs {
+						p.Size += dir.Size
+					}
+					mu.Lock()
+					reclaimable = append(reclaimable, p)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, project := range projects {
+		if project.RemoteHostID == 0 {
+			jobs <- project
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	slices.SortFunc(reclaimable, func(a, b ReclaimableProject) int {
+		if c := cmp.Compare(b.Size, a.Size); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Project.Name, b.Project.Name)
+	})
+	return reclaimable, nil
+}
+
+// reclaimDirs returns the dependency and build folders of a project directory with their sizes
+func reclaimDirs(projectPath string) []ReclaimDir {
+	var dirs []ReclaimDir
+	for _, rule := range reclaimRules {
+		if rule.matches(projectPath) {
+			path := filepath.Join(projectPath, rule.dir)
+			dirs = append(dirs, ReclaimDir{Name: rule.dir, Path: path, Size: DirSize(path)})
+		}
+	}
+	return dirs
+}
+
+// matches reports whether the project has the rule's folder next to one of its markers.
+// Symlinked folders don't match, since deleting them would reach outside the project.
+func (r reclaimRule) matches(projectPath string) bool {
+	info, err := os.Lstat(filepath.Join(projectPath, r.dir))
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	return slices.ContainsFunc(r.markers, func(marker string) bool {
+		_, err := os.Stat(filepath.Join(projectPath, marker))
+		return err == nil
+	})
+}
+
+// ReclaimSpace deletes the dependency and build folders of the given projects, skipping
+// projects that opted out, and returns the bytes freed. Each folder is checked against the
+// rules again first, so only folders ReclaimableSpace would offer are ever deleted.
+func ReclaimSpace(projects []ReclaimableProject) (int64, error) {
+	var freed int64
+	for _, p := range projects {
+		if p.Project.NoReclaim {
+			continue
+		}
+		for _, dir := range p.Dirs {
+			i := slices.IndexFunc(reclaimRules, func(r reclaimRule) bool { return r.dir == dir.Name })
+			if i < 0 || dir.Path != filepath.Join(p.Project.Path, dir.Name) || !reclaimRules[i].matches(p.Project.Path) {
+				continue
+			}
+			if err := os.RemoveAll(dir.Path); err != nil {
+				return freed, fmt.Errorf("failed to delete %s: %w", dir.Path, err)
+			}
+			freed += dir.Size
+			slog.Info("Reclaimed space", "path", dir.Path, "bytes", dir.Size)
+		}
+		InvalidateDirSize(p.Project.Path)
+	}
+	return freed, nil
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"devbase/models"
+)
+
+// ErrRemoteProject is returned by operations that only make sense for local checkouts
+var ErrRemoteProject = errors.New("not supported for remote projects: files live on the SSH host")
+
+// remoteIgnore lists the directories a remote scan prunes, mirroring the heavy entries skipped locally
+var remoteIgnore = []string{
+	"node_modules", "dist", "build", ".next", ".venv", "venv", "__pycache__",
+	"vendor", "target", "bin", "obj", ".gradle", ".idea", ".vscode",
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// remoteShellPath quotes a remote path, leaving a leading "~/" for the remote shell to expand
+func remoteShellPath(p string) string {
+	if p == "~" {
+		return `"$HOME"`
+	}
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		return `"$HOME"/` + shellQuote(rest)
+	}
+	return shellQuote(p)
+}
+
+// sshCommand builds an ssh invocation that runs script with sh on the host. BatchMode makes
+// ssh fail instead of prompting, since DevBase owns the terminal while it runs.
+func sshCommand(host models.RemoteHost, script string) *exec.Cmd {
+	return exec.Command("ssh", "-o", "BatchMode=yes", host.Destination, "sh -c "+shellQuote(script))
+}
+
+// remoteScanScript finds project directories under root and prints "dir<TAB>origin URL" lines
+func remoteScanScript(root string) string {
+	prune := make([]string, len(remoteIgnore))
+	for i, name := range remoteIgnore {
+		prune[i] = "-name " + shellQuote(name)
+	}
+	return fmt.Sprintf(`find %s \( -name .git -prune -print \) -o \( \( %s \) -prune \) -o \( \( -name go.mod -o -name package.json \) -print \) 2>/dev/null |
+sed 's|/[^/]*$||' | sort -u |
+while IFS= read -r dir; do
+	printf '%%s\t%%s\n' "$dir" "$(git -C "$dir" config --get remote.origin.url 2>/dev/null)"
+done`, remoteShellPath(root), strings.Join(prune, " -o "))
+}
+
+// ScanRemote scans a directory on an SSH host for projects (package.json, go.mod, .git)
+// with a single ssh round trip. The returned projects belong to the host.
+func ScanRemote(host models.RemoteHost, root string) ([]models.Project, error) {
+	output, err := sshCommand(host, remoteScanScript(root)).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to scan %s on %s: %s", root, host.Name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to scan %s on %s: %w", root, host.Name, err)
+	}
+
+	var projects []models.Project
+	for _, line := range strings.Split(string(output), "\n") {
+		dir, repoURL, _ := strings.Cut(line, "\t")
+		if dir == "" {
+			continue
+		}
+		projects = append(projects, RemoteProject(host, dir, strings.TrimSpace(repoURL)))
+	}
+	return projects, nil
+}
+
+// RemoteProject builds a project entry for a directory on an SSH host
+func RemoteProject(host models.RemoteHost, dir, repoURL string) models.Project {
+	dir = path.Clean(dir)
+	return models.Project{
+		Name:         path.Base(dir),
+		Path:         dir,
+		RepoURL:      repoURL,
+		Status:       "active",
+		LastOpened:   time.Now(),
+		RemoteHostID: host.ID,
+	}
+}
+
+// ResolveRemoteDir returns the absolute path of a directory on the SSH host, expanding "~"
+func ResolveRemoteDir(host models.RemoteHost, dir string) (string, error) {
+	output, err := sshCommand(host, "cd "+remoteShellPath(dir)+" && pwd -P").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() != 255 {
+			return "", fmt.Errorf("%s is not a directory on %s", dir, host.Name)
+		}
+		return "", fmt.Errorf("failed to reach %s: %w", host.Name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RemoteEditorCommand builds the command that opens a remote project. Editors with
+// Remote-SSH support (VS Code, Cursor, Windsurf) open the folder over SSH; terminal
+// editors run on the host in an interactive ssh session.
+func RemoteEditorCommand(editor Editor, host models.RemoteHost, dir string) (*exec.Cmd, error) {
+	switch {
+	case editor.Workspace:
+		return exec.Command(editor.Command, "--remote", "ssh-remote+"+host.Destination, dir), nil
+	case editor.Terminal:
+		script := "cd " + remoteShellPath(dir) + " && exec " + editor.Command + " ."
+		return exec.Command("ssh", "-t", host.Destination, script), nil
+	}
+	return nil, fmt.Errorf("%s can't open projects over SSH, choose VS Code, Cursor, Windsurf or a terminal editor", editor.Name)
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Git hosting providers recognized from remote URLs
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderBitbucket = "bitbucket"
+	ProviderCodeberg  = "codeberg" // Codeberg and other Gitea/Forgejo instances
+	ProviderOther     = ""
+)
+
+// providerHosts maps well-known hosts to their provider
+var providerHosts = map[string]string{
+	"github.com":    ProviderGitHub,
+	"gitlab.com":    ProviderGitLab,
+	"bitbucket.org": ProviderBitbucket,
+	"codeberg.org":  ProviderCodeberg,
+}
+
+// RepoRef identifies a hosted repository parsed from a remote URL
+type RepoRef struct {
+	Host     string // e.g. "gitlab.com", without user or port
+	Path     string // e.g. "group/subgroup/name", without ".git"
+	Provider string // One of the Provider* constants
+}
+
+// ParseRepoURL parses HTTPS, HTTP, git://, ssh:// and scp-like ("git@host:owner/name.git")
+// remote URLs. Self-hosted instances are recognized by "gitlab", "bitbucket", "gitea" or
+// "forgejo" in their host name.
+func ParseRepoURL(raw string) (RepoRef, bool) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+
+	if scheme, rest, ok := strings.Cut(raw, "://"); ok {
+		switch strings.ToLower(scheme) {
+		case "https", "http", "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return RepoRef{}, false
+		}
+		if host, path, ok = strings.Cut(rest, "/"); !ok {
+			return RepoRef{}, false
+		}
+		// A port belongs to the transport, not the repository
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+		host, _, _ = strings.Cut(host, ":")
+	} else if user, rest, ok := strings.Cut(raw, "@"); ok && !strings.ContainsAny(user, "/:") {
+		// scp-like syntax: user@host:path
+		if host, path, ok = strings.Cut(rest, ":"); !ok {
+			return RepoRef{}, false
+		}
+	} else {
+		return RepoRef{}, false
+	}
+
+	host = strings.ToLower(host)
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	// Web URLs copied from a browser may point inside the repository (".../-/tree/main")
+	if i := strings.Index(path, "/-/"); i >= 0 {
+		path = path[:i]
+	}
+	if host == "" || !strings.Contains(path, "/") {
+		return RepoRef{}, false
+	}
+	return RepoRef{Host: host, Path: path, Provider: repoProvider(host)}, true
+}
+
+// NormalizeRepoURL checks that raw is a repository URL git can clone over HTTPS or SSH and
+// returns it cleaned up: fragments and queries are dropped, http:// becomes https:// and
+// browser URLs pointing inside a GitHub, Bitbucket or Codeberg repository (".../tree/main")
+// or a GitLab one (".../-/tree/main") are cut back to the repository. Users and ports are kept.
+func NormalizeRepoURL(raw string) (string, error) {
+	url := strings.TrimSpace(raw)
+	url, _, _ = strings.Cut(url, "#")
+	ref, ok := ParseRepoURL(url)
+	if !ok {
+		return "", fmt.Errorf("not a repository URL: %s", strings.TrimSpace(raw))
+	}
+
+	scheme, rest, hasScheme := strings.Cut(url, "://")
+	if !hasScheme {
+		// scp-like syntax is SSH
+		return strings.TrimRight(url, "/"), nil
+	}
+	rest, _, _ = strings.Cut(rest, "?")
+	hostPart, _, _ := strings.Cut(rest, "/")
+
+	switch strings.ToLower(scheme) {
+	case "https", "http":
+		// Only the repository path is cloneable, and ParseRepoURL already trimmed GitLab's
+		path := ref.Path
+		if ref.Provider != ProviderGitLab && ref.Provider != ProviderOther {
+			if parts := strings.SplitN(path, "/", 3); len(parts) == 3 {
+				path = parts[0] + "/" + parts[1]
+			}
+		}
+		return "https://" + hostPart + "/" + path, nil
+	case "ssh", "git+ssh", "ssh+git":
+		return "ssh://" + strings.TrimRight(rest, "/"), nil
+	}
+	return "", fmt.Errorf("unsupported protocol %s://, use an https:// or ssh URL", scheme)
+}
+
+// repoProvider returns the provider of a host, guessing self-hosted instances by name
+func repoProvider(host string) string {
+	if provider, ok := providerHosts[host]; ok {
+		return provider
+	}
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return ProviderGitLab
+	case strings.Contains(host, "bitbucket"):
+		return ProviderBitbucket
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"):
+		return ProviderCodeberg
+	}
+	return ProviderOther
+}
+
+// Name returns the repository name, the last path segment
+func (r RepoRef) Name() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
+
+// Key returns "host/path", which identifies the repository across URL forms
+func (r RepoRef) Key() string {
+	return r.Host + "/" + r.Path
+}
+
+// WebURL returns the repository's page in a browser
+func (r RepoRef) WebURL() string {
+	return "https://" + r.Key()
+}
@@ -0,0 +1,30 @@
+package engine
+
+import "testing"
+
+// TestNormalizeRepoURL tests cleaning up pasted repository URLs and rejecting what isn't one
+func TestNormalizeRepoURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/acme/api":                       "https://github.com/acme/api",
+		"  https://github.com/acme/api.git\n":               "https://github.com/acme/api",
+		"http://github.com/acme/api#readme":                 "https://github.com/acme/api",
+		"https://github.com/acme/api/tree/main/src?tab=a":   "https://github.com/acme/api",
+		"https://gitlab.com/group/sub/api/-/tree/main":      "https://gitlab.com/group/sub/api",
+		"https://git.example.com:8443/team/tools/api":       "https://git.example.com:8443/team/tools/api",
+		"git@github.com:acme/api.git":                       "git@github.com:acme/api.git",
+		"ssh://git@gitlab.example.com:2222/group/api.git#x": "ssh://git@gitlab.example.com:2222/group/api.git",
+		"git+ssh://git@bitbucket.org/acme/api.git":          "ssh://git@bitbucket.org/acme/api.git",
+	}
+	for raw, want := range tests {
+		got, err := NormalizeRepoURL(raw)
+		if err != nil || got != want {
+			t.Errorf("NormalizeRepoURL(%q) = %q, %v; expected %q", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "not a url", "https://github.com/acme", "git://github.com/acme/api", "ftp://example.com/acme/api"} {
+		if got, err := NormalizeRepoURL(raw); err == nil {
+			t.Errorf("Expected %q to be rejected, got %q", raw, got)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RunConfigFile is the run configuration a project can carry: named commands that the run
+// picker (x) lists before the tasks detected from the project's files
+const RunConfigFile = "devbase.yaml"
+
+// runConfigNamePattern matches task names in devbase.yaml
+var runConfigNamePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// goMainPattern matches the package clause of a Go program
+var goMainPattern = regexp.MustCompile(`(?m)^package main\b`)
+
+// HasRunConfig reports whether a project directory has a devbase.yaml
+func HasRunConfig(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, RunConfigFile))
+	return err == nil
+}
+
+// SuggestRunConfig returns run and test commands for a project directory, detected from its
+// stack: the usual package.json scripts, or the standard commands of Go, Rust, Python, Maven,
+// Gradle and .NET projects. It returns nil for a stack it doesn't know.
+func SuggestRunConfig(dir string) []Task {
+	has := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	suggest := func(source string, commands ...string) []Task {
+		tasks := make([]Task, 0, len(commands)/2)
+		for i := 0; i+1 < len(commands); i += 2 {
+			tasks = append(tasks, Task{Name: commands[i], Command: commands[i+1], Source: source})
+		}
+		return tasks
+	}
+
+	switch {
+	case has("package.json"):
+		var tasks []Task
+		scripts := make(map[string]string)
+		for _, task := range npmTasks(dir) {
+			scripts[task.Name] = task.Command
+		}
+		for _, name := range []string{"dev", "start", "test", "build", "lint"} {
+			if command, ok := scripts[name]; ok {
+				tasks = append(tasks, Task{Name: name, Command: command, Source: "package.json"})
+			}
+		}
+		if len(tasks) == 0 {
+			tasks = suggest("package.json", "install", npmClient(dir)+" install")
+		}
+		return tasks
+	case has("go.mod"):
+		tasks := suggest("go", "test", "go test ./...", "build", "go build ./...")
+		if goMainPackage(dir) {
+			tasks = append(suggest("go", "run", "go run ."), tasks...)
+		}
+		return tasks
+	case has("Cargo.toml"):
+		return suggest("cargo", "run", "cargo run", "test", "cargo test", "build", "cargo build")
+	case has("pyproject.toml") || has("requirements.txt") || has("setup.py"):
+		var tasks []Task
+		if has("requirements.txt") {
+			tasks = suggest("python", "install", "python -m pip install -r requirements.txt")
+		}
+		if has("main.py") {
+			tasks = append(tasks, suggest("python", "run", "python main.py")...)
+		}
+		return append(tasks, suggest("python", "test", "python -m pytest")...)
+	case has("pom.xml"):
+		return suggest("maven", "test", "mvn test", "build", "mvn package")
+	case has("build.gradle") || has("build.gradle.kts"):
+		gradle := "gradle"
+		if has("gradlew") {
+			gradle = "./gradlew"
+		}
+		return suggest("gradle", "run", gradle+" run", "test", gradle+" test", "build", gradle+" build")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.csproj")); len(matches) > 0 {
+		return suggest("dotnet", "run", "dotnet run", "test", "dotnet test", "build", "dotnet build")
+	}
+	return nil
+}
+
+// goMainPackage reports whether the Go files at the top of a module build a program
+func goMainPackage(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err == nil && goMainPattern.Match(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteRunConfig writes tasks to the project's devbase.yaml, refusing to replace one that
+// exists. It returns the path of the file.
+func WriteRunConfig(dir string, tasks []Task) (string, error) {
+	path := filepath.Join(dir, RunConfigFile)
+	var b strings.Builder
+	b.WriteString("# Commands listed first in DevBase's run picker (x)\n")
+	b.WriteString("tasks:\n")
+	for _, task := range tasks {
+		if !runConfigNamePattern.MatchString(task.Name) {
+			return "", fmt.Errorf("invalid task name %q", task.Name)
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", task.Name, strconv.Quote(task.Command))
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("%s %w", RunConfigFile, ErrPathExists)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", RunConfigFile, err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(b.String()); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", RunConfigFile, err)
+	}
+	return path, nil
+}
+
+// CommitRunConfig commits the project's devbase.yaml on its own, leaving other changes out
+func CommitRunConfig(dir string) error {
+	if _, err := gitOutput(dir, "add", "--", RunConfigFile); err != nil {
+		return err
+	}
+	_, err := gitOutput(dir, "commit", "-m", "Add DevBase run configuration", "--", RunConfigFile)
+	return err
+}
+
+// runConfigTasks returns the tasks of the project's devbase.yaml, nil without one or when it
+// doesn't parse, like a package.json that doesn't
+func runConfigTasks(dir string) []Task {
+	content, err := os.ReadFile(filepath.Join(dir, RunConfigFile))
+	if err != nil {
+		return nil
+	}
+	tasks, err := ParseRunConfig(content)
+	if err != nil {
+		return nil
+	}
+	return tasks
+}
+
+// ParseRunConfig reads the tasks of a devbase.yaml: a "tasks" mapping of names to commands.
+// Commands are plain or quoted YAML scalars; other top-level keys are ignored.
+func ParseRunConfig(data []byte) ([]Task, error) {
+	var tasks []Task
+	inTasks := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			inTasks = trimmed == "tasks:"
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+
+		name, value, ok := strings.Cut(trimmed, ":")
+		name = strings.TrimSpace(name)
+		if !ok || !runConfigNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s line %d: expected \"name: command\"", RunConfigFile, n)
+		}
+		command, err := parseRunConfigValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", RunConfigFile, n, err)
+		}
+		if command == "" {
+			return nil, fmt.Errorf("%s line %d: task %s has no command", RunConfigFile, n, name)
+		}
+		tasks = append(tasks, Task{Name: name, Command: command, Source: RunConfigFile})
+	}
+	return tasks, scanner.Err()
+}
+
+// parseRunConfigValue reads a double-quoted, single-quoted or plain YAML scalar, dropping a
+// trailing comment from a plain one
+func parseRunConfigValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := strings.LastIndex(value, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return strconv.Unquote(value[:end+1])
+	case strings.HasPrefix(value, "'"):
+		end := strings.LastIndex(value, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return strings.ReplaceAll(value[1:end], "''", "'"), nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value), nil
+}
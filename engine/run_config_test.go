@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+)
+
+// TestRunConfig tests suggesting, writing, committing and reading a devbase.yaml
+func TestRunConfig(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("committing uses the git command, which is not installed")
+	}
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "DevBase")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "devbase@example.com")
+	}
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to init: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "go.mod"), "module api\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+	commitAll(t, repo, "init")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "not part of the commit\n")
+
+	tasks := SuggestRunConfig(dir)
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name+"="+task.Command)
+	}
+	if want := []string{"run=go run .", "test=go test ./...", "build=go build ./..."}; !slices.Equal(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+	if SuggestRunConfig(t.TempDir()) != nil {
+		t.Error("Expected no suggestion for an unknown stack")
+	}
+
+	tasks = append(tasks, Task{Name: "serve", Command: `sh -c "echo 'a: b' # not a comment"`})
+	if _, err := WriteRunConfig(dir, tasks); err != nil {
+		t.Fatalf("WriteRunConfig failed: %v", err)
+	}
+	if _, err := WriteRunConfig(dir, tasks); !errors.Is(err, ErrPathExists) {
+		t.Errorf("Expected ErrPathExists writing it again, got %v", err)
+	}
+	if err := CommitRunConfig(dir); err != nil {
+		t.Fatalf("CommitRunConfig failed: %v", err)
+	}
+	if status, _ := gitOutput(dir, "status", "--porcelain"); strings.TrimSpace(status) != "?? notes.txt" {
+		t.Errorf("Expected only devbase.yaml to be committed, got status %q", status)
+	}
+
+	detected := DetectTasks(dir)
+	if len(detected) < len(tasks) {
+		t.Fatalf("Expected the devbase.yaml tasks first, got %+v", detected)
+	}
+	for i, task := range tasks {
+		if detected[i].Name != task.Name || detected[i].Command != task.Command || detected[i].Source != RunConfigFile {
+			t.Errorf("Task %d: expected %s: %s from devbase.yaml, got %+v", i, task.Name, task.Command, detected[i])
+		}
+	}
+
+	parsed, err := ParseRunConfig([]byte("name: api\ntasks:\n  dev: npm run dev # plain\n  lint: 'eslint ''src'''\n"))
+	if err != nil || len(parsed) != 2 || parsed[0].Command != "npm run dev" || parsed[1].Command != "eslint 'src'" {
+		t.Errorf("Unexpected tasks %+v (%v)", parsed, err)
+	}
+	if _, err := ParseRunConfig([]byte("tasks:\n  bad name: x\n")); err == nil {
+		t.Error("Expected an invalid task name to be rejected")
+	}
+}
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// MaxRunLogs is how many run logs are kept per project; older ones are deleted when a run starts
+const MaxRunLogs = 20
+
+// runLogTimeLayout names log files by start time, so they sort chronologically
+const runLogTimeLayout = "20060102-150405.000"
+
+// RunLog is the captured output of a command started by StartCapturedRun
+type RunLog struct {
+	Project string // Project name, as its log folder is named
+	Path    string
+	Started time.Time
+	Size    int64
+	Running bool // Started by this DevBase process and not finished yet
+}
+
+// runningLogs holds the logs of runs started by this process that haven't exited yet
+var runningLogs = struct {
+	sync.Mutex
+	paths map[string]bool
+}{paths: make(map[string]bool)}
+
+// RunLogDir returns the directory holding the run logs, one folder per project
+func RunLogDir() (string, error) {
+	dataDir, err := db.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "runs"), nil
+}
+
+// runLogFolder turns a project name into a folder name that is valid on every platform
+func runLogFolder(projectName string) string {
+	folder := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.Trim(projectName, ". "))
+	if folder == "" {
+		return "_"
+	}
+	return folder
+}
+
+// shellCommand runs a command line with the platform's shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("powershell", "-NoProfile", "-Command", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// RunOptions describe a command started by StartCapturedRun or StartStreamedRun
+type RunOptions struct {
+	Project models.Project // Runs of saved projects are tracked as dev runs (see ActiveDevRuns)
+	Dir     string
+	Command string   // Command line run by the shell
+	Env     []string // Added to the environment
+	// Task is the command as chosen, before it was wrapped for WSL; Command when empty
+	Task string
+	// EnvSource is where Env came from (see LoadProjectEnv), to load it again on restart
+	EnvSource string
+}
+
+// StartCapturedRun starts a command line as a child process in a process group of its own,
+// writing its output to a new log file of the project instead of a terminal window. It
+// returns the log path; the log ends with the exit status once the command exits.
+func StartCapturedRun(opts RunOptions) (string, error) {
+	file, started, err := openRunLog(opts.Project.Name, opts.Command)
+	if err != nil {
+		return "", err
+	}
+	path := file.Name()
+
+	cmd := shellCommand(opts.Command)
+	cmd.Dir = opts.Dir
+	cmd.Stdout = file
+	cmd.Stderr = file
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	setRunLogRunning(path, true)
+	devRun := trackDevRun(opts, cmd.Process.Pid, path, false)
+	slog.Info("Started captured run", "project", opts.Project.Name, "command", opts.Command, "log", path)
+
+	go func() {
+		err := cmd.Wait()
+		fmt.Fprintf(file, "\n[%s after %s]\n", exitSummary(err), time.Since(started).Round(time.Second))
+		file.Close()
+		setRunLogRunning(path, false)
+		untrackDevRun(devRun)
+	}()
+	return path, nil
+}
+
+// openRunLog creates a new log of the project, named by the start time, with the command
+// line at its top. Old logs beyond MaxRunLogs are deleted first.
+func openRunLog(projectName, command string) (*os.File, time.Time, error) {
+	logDir, err := RunLogDir()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	projectDir := filepath.Join(logDir, runLogFolder(projectName))
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to create run log folder: %w", err)
+	}
+	pruneRunLogs(projectDir, MaxRunLogs-1)
+
+	started := time.Now()
+	path := filepath.Join(projectDir, started.Format(runLogTimeLayout)+".log")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to create run log: %w", err)
+	}
+	fmt.Fprintf(file, "$ %s\n\n", command)
+	return file, started, nil
+}
+
+// setRunLogRunning marks a log as belonging to a run of this process that is still going
+func setRunLogRunning(path string, running bool) {
+	runningLogs.Lock()
+	defer runningLogs.Unlock()
+	if running {
+		runningLogs.paths[path] = true
+	} else {
+		delete(runningLogs.paths, path)
+	}
+}
+
+// exitSummary describes how a captured run ended
+func exitSummary(err error) string {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return "exited with code 0"
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		return fmt.Sprintf("exited with code %d", exitErr.ExitCode())
+	}
+	return err.Error()
+}
+
+// pruneRunLogs deletes the oldest logs of a project folder beyond keep, leaving running ones
+func pruneRunLogs(projectDir string, keep int) {
+	paths, _ := filepath.Glob(filepath.Join(projectDir, "*.log"))
+	slices.Sort(paths)
+	for _, path := range paths[:max(0, len(paths)-keep)] {
+		if !runLogRunning(path) {
+			os.Remove(path)
+		}
+	}
+}
+
+// runLogRunning reports whether the log belongs to a run of this process that is still going
+func runLogRunning(path string) bool {
+	runningLogs.Lock()
+	defer runningLogs.Unlock()
+	return runningLogs.paths[path]
+}
+
+// RecentRunLogs returns the run logs of all projects, newest first, at most limit of them
+// (all of them when limit is 0)
+func RecentRunLogs(limit int) ([]RunLog, error) {
+	logDir, err := RunLogDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(logDir, "*", "*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []RunLog
+	for _, path := range paths {
+		started, err := time.ParseInLocation(runLogTimeLayout, strings.TrimSuffix(filepath.Base(path), ".log"), time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		logs = append(logs, RunLog{
+			Project: filepath.Base(filepath.Dir(path)),
+			Path:    path,
+			Started: started,
+			Size:    info.Size(),
+			Running: runLogRunning(path),
+		})
+	}
+
+	slices.SortFunc(logs, func(a, b RunLog) int {
+		if c := b.Started.Compare(a.Started); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Project, b.Project)
+	})
+	if limit > 0 && len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
+
+// TailRunLog returns the last lines of a run log, at most maxLines of them, reading no
+// more than the end of large logs
+func TailRunLog(path string, maxLines int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	const maxTailBytes = 256 * 1024
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(0, info.Size()-maxTailBytes)
+	data := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if offset > 0 {
+		// Drop the partial first line
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	return outputLines(data, maxLines), nil
+}
+
+// outputLines splits command output into its last lines, at most maxLines of them
+func outputLines(data []byte, maxLines int) []string {
+	text := strings.TrimRight(string(data), "\r\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	for i, line := range lines {
+		// Progress output redraws a line after \r; keep what was drawn last
+		line = strings.TrimSuffix(line, "\r")
+		lines[i] = line[strings.LastIndexByte(line, '\r')+1:]
+	}
+	return lines
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// streamOutputBytes is how much of a streamed run's output is kept in memory; all of it
+// is in the run log
+const streamOutputBytes = 512 * 1024
+
+// stopGracePeriod is how long a stopped run gets to exit before it is killed
+const stopGracePeriod = 5 * time.Second
+
+// StreamedRun is a command started by StartStreamedRun. Its output goes to a run log like
+// that of a captured run and is also kept in memory, so it can be shown as it arrives.
+type StreamedRun struct {
+	Project string
+	Command string
+	LogPath string
+	Started time.Time
+	PID     int
+
+	cmd  *exec.Cmd
+	done chan struct{}
+
+	mu      sync.Mutex
+	output  []byte
+	exit    string // How the run ended, empty while it is running
+	stopped bool
+}
+
+// streamedRuns holds the streamed runs that haven't exited yet, stopped when DevBase exits
+var streamedRuns = struct {
+	sync.Mutex
+	runs map[*StreamedRun]bool
+}{runs: make(map[*StreamedRun]bool)}
+
+// StartStreamedRun starts a command line as a child process like StartCapturedRun. The
+// command and the processes it starts can be stopped with Stop, and they are stopped when
+// DevBase exits.
+func StartStreamedRun(opts RunOptions) (*StreamedRun, error) {
+	file, started, err := openRunLog(opts.Project.Name, opts.Command)
+	if err != nil {
+		return nil, err
+	}
+	run := &StreamedRun{
+		Project: opts.Project.Name,
+		Command: opts.Command,
+		LogPath: file.Name(),
+		Started: started,
+		done:    make(chan struct{}),
+	}
+	// The pane shows the command line like the top of the log
+	run.Write([]byte("$ " + opts.Command + "\n\n"))
+
+	out := io.MultiWriter(file, run)
+	cmd := shellCommand(opts.Command)
+	cmd.Dir = opts.Dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// Processes the command left behind could keep its output open after it exited
+	cmd.WaitDelay = stopGracePeriod
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		file.Close()
+		os.Remove(run.LogPath)
+		return nil, err
+	}
+	run.cmd = cmd
+	run.PID = cmd.Process.Pid
+
+	setRunLogRunning(run.LogPath, true)
+	devRun := trackDevRun(opts, cmd.Process.Pid, run.LogPath, true)
+	streamedRuns.Lock()
+	streamedRuns.runs[run] = true
+	streamedRuns.Unlock()
+	slog.Info("Started streamed run", "project", opts.Project.Name, "command", opts.Command, "log", run.LogPath)
+
+	go func() {
+		err := cmd.Wait()
+		run.mu.Lock()
+		exit := exitSummary(err)
+		if run.stopped {
+			exit = "stopped"
+		}
+		run.exit = exit
+		run.mu.Unlock()
+
+		fmt.Fprintf(out, "\n[%s after %s]\n", exit, time.Since(started).Round(time.Second))
+		file.Close()
+		setRunLogRunning(run.LogPath, false)
+		untrackDevRun(devRun)
+		streamedRuns.Lock()
+		delete(streamedRuns.runs, run)
+		streamedRuns.Unlock()
+		close(run.done)
+	}()
+	return run, nil
+}
+
+// Write keeps output of the run in memory, dropping the oldest lines beyond
+// streamOutputBytes
+func (r *StreamedRun) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.output = append(r.output, p...)
+	if excess := len(r.output) - streamOutputBytes; excess > 0 {
+		drop := excess
+		if i := bytes.IndexByte(r.output[excess:], '\n'); i >= 0 {
+			drop += i + 1
+		}
+		r.output = append(r.output[:0], r.output[drop:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns the last lines of output, at most maxLines of them
+func (r *StreamedRun) Lines(maxLines int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return outputLines(r.output, maxLines)
+}
+
+// Done is closed once the command exited
+func (r *StreamedRun) Done() <-chan struct{} {
+	return r.done
+}
+
+// Exit describes how the run ended, e.g. "exited with code 1" or "stopped"; ok is false
+// while it is running
+func (r *StreamedRun) Exit() (exit string, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.exit, r.exit != ""
+}
+
+// Stop ends the command and the processes it started, killing them when they don't exit
+// within a few seconds. It doesn't wait for them; see Done.
+func (r *StreamedRun) Stop() error {
+	select {
+	case <-r.done:
+		return nil
+	default:
+	}
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+
+	if err := terminateProcessTree(r.PID); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", r.Command, err)
+	}
+	go func() {
+		select {
+		case <-r.done:
+		case <-time.After(stopGracePeriod):
+			if err := killProcessTree(r.PID); err != nil {
+				slog.Warn("Failed to kill streamed run", "command", r.Command, "err", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// StopStreamedRuns stops the streamed runs that are still going and waits for them to exit,
+// as they end with DevBase
+func StopStreamedRuns() {
+	streamedRuns.Lock()
+	runs := make([]*StreamedRun, 0, len(streamedRuns.runs))
+	for run := range streamedRuns.runs {
+		runs = append(runs, run)
+	}
+	streamedRuns.Unlock()
+
+	for _, run := range runs {
+		if err := run.Stop(); err != nil {
+			slog.Warn("Failed to stop streamed run", "command", run.Command, "err", err)
+		}
+	}
+	for _, run := range runs {
+		<-run.Done()
+	}
+}
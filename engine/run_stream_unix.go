@@ -0,0 +1,42 @@
+//go:build !windows
+
+package engine
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts a command in a process group of its own, so stopping it also
+// stops the processes its shell started
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateProcessTree asks the process group of a command started with setProcessGroup,
+// led by pid, to exit
+func terminateProcessTree(pid int) error {
+	return signalProcessGroup(pid, syscall.SIGTERM)
+}
+
+// killProcessTree kills the process group of a command started with setProcessGroup
+func killProcessTree(pid int) error {
+	return signalProcessGroup(pid, syscall.SIGKILL)
+}
+
+// signalProcessGroup sends a signal to the process group led by pid; a group whose
+// processes all exited already isn't an error
+func signalProcessGroup(pid int, sig syscall.Signal) error {
+	// A negative PID stands for the group
+	if err := syscall.Kill(-pid, sig); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
+
+// processAlive reports whether a process with the PID exists. Processes of other users
+// count, as signalling them is merely not permitted.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
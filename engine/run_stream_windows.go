@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup does nothing on Windows, where taskkill finds the child processes
+func setProcessGroup(*exec.Cmd) {}
+
+// terminateProcessTree ends a process and its child processes. Console programs can't be
+// asked to exit from outside, so they are ended right away.
+func terminateProcessTree(pid int) error {
+	return killProcessTree(pid)
+}
+
+// killProcessTree ends a process and its child processes
+func killProcessTree(pid int) error {
+	if !processAlive(pid) {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
+
+// processAlive reports whether a process with the PID exists; opening it fails otherwise
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
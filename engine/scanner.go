@@ -1,22 +1,123 @@
 package engine
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"devbase/db"
 	"devbase/models"
 )
 
+// ScanSkip is a directory a scan left out, with the reason
+type ScanSkip struct {
+	Path   string
+	Reason string
+}
+
+// ScanReport details what a scan left out or found registered already. The lists are
+// sorted by path.
+type ScanReport struct {
+	Ignored        []ScanSkip // Skipped by scanner_ignore, never-register lists, other drives, junctions and cloud folders
+	IgnoredBuiltin int        // Dependency and build directories skipped by the built-in list (node_modules, target, …)
+	Unreadable     []ScanSkip // Directories that couldn't be read, with the error
+	Duplicates     []ScanSkip // Projects found twice, or registered in another root folder too
+	Registered     []string   // Projects found that were registered already
+
+	unscanned []string // Directories the scan didn't enter, whose projects weren't looked for
+}
+
+// Summary counts the entries of the report, e.g. "2 ignored, 1 unreadable"
+func (r ScanReport) Summary() string {
+	var parts []string
+	for _, count := range []struct {
+		n     int
+		label string
+	}{
+		{len(r.Ignored), "ignored"},
+		{len(r.Unreadable), "unreadable"},
+		{len(r.Duplicates), "duplicates"},
+		{len(r.Registered), "already registered"},
+	} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.label))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Problems reports whether the scan skipped anything besides the built-in ignores and
+// projects it had registered before
+func (r ScanReport) Problems() bool {
+	return len(r.Ignored) > 0 || len(r.Unreadable) > 0 || len(r.Duplicates) > 0
+}
+
+// sort orders the lists of the report by path, as workers finish in any order. A directory
+// that both the walk and a worker failed to read is listed once.
+func (r *ScanReport) sort() {
+	for _, skips := range [][]ScanSkip{r.Ignored, r.Unreadable, r.Duplicates} {
+		slices.SortFunc(skips, func(a, b ScanSkip) int { return strings.Compare(a.Path, b.Path) })
+	}
+	r.Unreadable = slices.CompactFunc(r.Unreadable, func(a, b ScanSkip) bool { return a.Path == b.Path })
+	slices.Sort(r.Registered)
+}
+
 // ScanDirectory concurrently scans a root directory for projects and returns discovered projects.
 // A worker pool evaluates directories for project markers (package.json, go.mod, .git).
-func ScanDirectory(rootPath string) ([]models.Project, error) {
+// On Windows, junctions, symbolic links and online-only cloud folders aren't entered (see
+// scanSkipReason). Cancelling ctx stops the walk and returns its error.
+func ScanDirectory(ctx context.Context, rootPath string) ([]models.Project, error) {
+	projects, _, err := scanDirectory(ctx, rootPath)
+	return projects, err
+}
+
+// scanDirectory scans like ScanDirectory and reports the directories it skipped. Directories
+// below rootPath that can't be read are reported instead of failing the scan, and listed as
+// unscanned so the projects registered in them aren't taken for vanished.
+func scanDirectory(ctx context.Context, rootPath string) ([]models.Project, ScanReport, error) {
 	const workerCount = 10
 	jobs := make(chan string, workerCount*4)
 	results := make(chan models.Project, workerCount*4)
 
+	// The walk, the workers and the collector all add to the report
+	var report ScanReport
+	var reportMu sync.Mutex
+	skip := func(list *[]ScanSkip, path, reason string) {
+		reportMu.Lock()
+		defer reportMu.Unlock()
+		*list = append(*list, ScanSkip{Path: path, Reason: reason})
+	}
+	// Projects in unscanned directories weren't looked for, so reconciling keeps them
+	unscanned := func(path string) {
+		reportMu.Lock()
+		defer reportMu.Unlock()
+		report.unscanned = append(report.unscanned, path)
+	}
+
+	// Collect results while the walk runs, so full channels never block the workers
+	var projects []models.Project
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		seen := make(map[string]struct{})
+		for p := range results {
+			if _, exists := seen[p.Path]; exists {
+				skip(&report.Duplicates, p.Path, "found twice")
+				continue
+			}
+			seen[p.Path] = struct{}{}
+			projects = append(projects, p)
+		}
+	}()
+
 	// Worker pool to process directory paths.
 	var wg sync.WaitGroup
 	for i := 0; i < workerCount; i++ {
@@ -24,7 +125,10 @@ func ScanDirectory(rootPath string) ([]models.Project, error) {
 		go func() {
 			defer wg.Done()
 			for dir := range jobs {
-				if project, ok, err := inspectDirectory(dir); err == nil && ok {
+				if project, ok, err := inspectDirectory(dir); err != nil {
+					skip(&report.Unreadable, dir, pathErrorReason(err))
+					unscanned(dir)
+				} else if ok {
 					results <- project
 				}
 			}
@@ -68,9 +172,30 @@ func ScanDirectory(rootPath string) ([]models.Project, error) {
 		".DS_Store":           {},
 		".git":                {},
 	}
+	// Extra names from the "scanner_ignore" config key ([scanner] ignore in config.toml)
+	configured := make(map[string]bool)
+	if extra, err := db.GetConfig("scanner_ignore"); err == nil {
+		for _, name := range strings.Split(extra, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				if _, builtin := ignore[name]; !builtin {
+					configured[name] = true
+				}
+				ignore[name] = struct{}{}
+			}
+		}
+	}
 
 	walkErr := filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
+			if path == rootPath {
+				return err
+			}
+			// Called again after a directory couldn't be listed; the rest of the tree is walked
+			skip(&report.Unreadable, path, pathErrorReason(err))
+			unscanned(path)
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
@@ -79,8 +204,22 @@ func ScanDirectory(rootPath string) ([]models.Project, error) {
 		}
 
 		name := d.Name()
-		if _, skip := ignore[name]; skip {
-			return filepath.SkipDir // prune heavy directories early
+		if _, ignored := ignore[name]; ignored {
+			// prune heavy directories early
+			if configured[name] {
+				skip(&report.Ignored, path, "scanner_ignore")
+			} else {
+				report.IgnoredBuiltin++
+			}
+			return filepath.SkipDir
+		}
+		if path != rootPath {
+			if reason := scanSkipReason(path, d); reason != "" {
+				slog.Info("Skipped directory during scan", "path", path, "reason", reason)
+				skip(&report.Ignored, path, reason)
+				unscanned(path)
+				return filepath.SkipDir
+			}
 		}
 
 		jobs <- path
@@ -90,23 +229,178 @@ func ScanDirectory(rootPath string) ([]models.Project, error) {
 	close(jobs)
 	wg.Wait()
 	close(results)
+	<-collected
 
 	if walkErr != nil {
-		return nil, walkErr
+		return nil, ScanReport{}, walkErr
 	}
+	report.sort()
+	return projects, report, nil
+}
 
-	// Collect results
-	var projects []models.Project
-	seen := make(map[string]struct{})
-	for p := range results {
-		if _, exists := seen[p.Path]; exists {
-			continue
+// pathErrorReason returns the error of a failed file operation without the path, which the
+// report shows already
+func pathErrorReason(err error) string {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// ScanResult summarizes a finished root folder scan
+type ScanResult struct {
+	Path    string
+	Found   int
+	Added   int
+	Updated int
+	Removed int
+	Report  ScanReport
+}
+
+// String describes the scan for the activity history
+func (r ScanResult) String() string {
+	return r.Path + ": " + r.Summary()
+}
+
+// Summary counts the projects found, added, updated and removed
+func (r ScanResult) Summary() string {
+	return fmt.Sprintf("found %d, added %d, updated %d, removed %d", r.Found, r.Added, r.Updated, r.Removed)
+}
+
+// ScanRootFolder scans scanPath for the root folder and stores the result in one
+// transaction: new projects are added, changed details updated and active local projects
+// that are no longer on disk removed. Archived projects are gone from disk on purpose, and
+// remote ones aren't scanned here, so both are kept, as are projects in directories the scan
+// didn't enter or couldn't read. Projects on the root folder's never-register list are left
+// out, and removed when they were registered before. The result's report lists what was
+// skipped and which projects were registered already. The scan is logged as activity. A scan
+// cancelled through ctx stores nothing.
+func ScanRootFolder(ctx context.Context, rootFolderID uint, scanPath string) (ScanResult, error) {
+	result := ScanResult{Path: scanPath}
+	projects, report, err := scanDirectory(ctx, scanPath)
+	if err != nil {
+		return result, err
+	}
+	if rootFolder, err := db.GetRootFolderByID(rootFolderID); err == nil {
+		var dropped []string
+		projects, dropped = newNeverRegisterList(*rootFolder).filter(projects)
+		for _, path := range dropped {
+			report.Ignored = append(report.Ignored, ScanSkip{Path: path, Reason: "never-register list"})
+		}
+	} else if !errors.Is(err, db.ErrRootFolderNotFound) {
+		return result, err
+	}
+	result.Found = len(projects)
+	if err := reportRegistered(&report, rootFolderID, projects); err != nil {
+		return result, err
+	}
+	report.sort()
+	result.Report = report
+
+	// The scan may follow a checkout, commit or pull outside DevBase
+	for _, p := range projects {
+		InvalidateGitInfo(p.Path)
+	}
+
+	changes, err := db.ReconcileProjects(rootFolderID, projects, report.unscanned)
+	if err != nil {
+		return result, err
+	}
+	result.Added, result.Updated, result.Removed = changes.Added, changes.Updated, changes.Removed
+
+	_ = db.LogActivity(models.ActivityScan, 0, result.String())
+	_ = Emit(Event{Kind: EventScan, Path: scanPath, Detail: result.Summary(), Time: time.Now()})
+	return result, nil
+}
+
+// reportRegistered adds the projects a scan found that are registered already to the report:
+// in the scanned root folder, or in another one, which makes them duplicates
+func reportRegistered(report *ScanReport, rootFolderID uint, projects []models.Project) error {
+	registered, err := db.GetAllProjects()
+	if err != nil {
+		return err
+	}
+	rootFolders, err := db.GetAllRootFolders()
+	if err != nil {
+		return err
+	}
+	rootNames := make(map[uint]string, len(rootFolders))
+	for _, rootFolder := range rootFolders {
+		rootNames[rootFolder.ID] = rootFolder.Name
+	}
+	byPath := make(map[string][]uint, len(registered))
+	for _, p := range registered {
+		if p.RemoteHostID == 0 {
+			byPath[p.Path] = append(byPath[p.Path], p.RootFolderID)
+		}
+	}
+
+	for _, p := range projects {
+		for _, id := range byPath[p.Path] {
+			switch {
+			case id == rootFolderID:
+				report.Registered = append(report.Registered, p.Path)
+			case id == 0:
+				report.Duplicates = append(report.Duplicates, ScanSkip{Path: p.Path, Reason: "also registered without a root folder"})
+			default:
+				report.Duplicates = append(report.Duplicates, ScanSkip{Path: p.Path, Reason: "also registered in root folder " + rootNames[id]})
+			}
 		}
-		seen[p.Path] = struct{}{}
-		projects = append(projects, p)
 	}
+	return nil
+}
+
+// ErrNotProject is returned by AddDirectory for directories without project markers
+var ErrNotProject = errors.New("no package.json, go.mod or .git found")
 
-	return projects, nil
+// AddDirectory registers a single directory as a project, inspected like the directories a
+// scan finds, e.g. from a post-clone git hook. The project goes into the root folder
+// containing the directory, if any, unless it is on that root folder's never-register list.
+// A directory that is already registered isn't added again: added is false and an archived
+// project there is marked as active.
+func AddDirectory(dir string) (project *models.Project, added bool, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid path: %w", err)
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, false, err
+	} else if !info.IsDir() {
+		return nil, false, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if existing, err := db.GetProjectByPath(dir); err == nil {
+		if existing.Status == "archived" {
+			existing.Status = "active"
+			if err := db.UpdateProject(existing); err != nil {
+				return nil, false, err
+			}
+		}
+		return existing, false, nil
+	} else if !errors.Is(err, db.ErrProjectNotFound) {
+		return nil, false, err
+	}
+
+	inspected, ok, err := inspectDirectory(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, fmt.Errorf("%w in %s", ErrNotProject, dir)
+	}
+	if rootFolder, err := db.GetRootFolderForPath(dir); err != nil {
+		return nil, false, err
+	} else if rootFolder != nil {
+		if newNeverRegisterList(*rootFolder).matches(inspected) {
+			return nil, false, fmt.Errorf("%s is %w of root folder %s", dir, ErrNeverRegistered, rootFolder.Name)
+		}
+		inspected.RootFolderID = rootFolder.ID
+	}
+	if err := db.AddProject(&inspected); err != nil {
+		return nil, false, err
+	}
+	return &inspected, true, nil
 }
 
 // inspectDirectory checks if a directory contains project markers and constructs a Project.
@@ -123,11 +417,16 @@ func inspectDirectory(dir string) (models.Project, bool, error) {
 				LastOpened: time.Now(),
 			}
 
-			// Try to get git remote URL
-			if gitURL := getGitRemoteURL(dir); gitURL != "" {
-				project.RepoURL = gitURL
+			// Try to get git remote URL, unless reading it would download it
+			if !cloudOnly(filepath.Join(dir, ".git", "config")) {
+				if gitURL := getGitRemoteURL(dir); gitURL != "" {
+					project.RepoURL = gitURL
+				}
 			}
 
+			project.Language = DetectLanguage(dir)
+			project.DevContainer = HasDevContainer(dir)
+
 			return project, true, nil
 		}
 	}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package engine
+
+import "os"
+
+// scanSkipReason returns why a scan doesn't enter a directory, or "" to scan it. Every
+// directory is scanned here: symbolic links are never followed by the walk, and mount points
+// are scanned like any directory, as bind mounts, subvolumes and network shares under a root
+// folder hold projects too.
+func scanSkipReason(string, os.DirEntry) string {
+	return ""
+}
+
+// cloudOnly reports whether reading a file would download it first. Cloud placeholders are
+// a Windows feature, so it is always false here.
+func cloudOnly(string) bool {
+	return false
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"os"
+	"syscall"
+)
+
+// File attributes and reparse tags of directory entries the scan treats specially
+const (
+	fileAttributeReparsePoint       = 0x400
+	fileAttributeOffline            = 0x1000
+	fileAttributeRecallOnOpen       = 0x40000    // Listing the directory downloads it (OneDrive online-only folders)
+	fileAttributeRecallOnDataAccess = 0x400000   // Reading the file downloads it (OneDrive online-only files)
+	reparseTagMountPoint            = 0xA0000003 // NTFS junctions and volume mount points
+	reparseTagSymlink               = 0xA000000C
+)
+
+// scanSkipReason returns why a scan doesn't enter a directory, or "" to scan it. Skipped are
+// junctions and mount points, which can lead to other drives or back up the tree into a loop,
+// symbolic links, and online-only folders of OneDrive and other cloud storage, which would be
+// downloaded.
+func scanSkipReason(path string, _ os.DirEntry) string {
+	data, ok := findData(path)
+	if !ok {
+		return ""
+	}
+	if data.FileAttributes&fileAttributeReparsePoint != 0 {
+		// Reserved0 holds the reparse tag; cloud files have tags of their own and are
+		// checked below
+		switch data.Reserved0 {
+		case reparseTagMountPoint:
+			return "junction or mount point"
+		case reparseTagSymlink:
+			return "symbolic link"
+		}
+	}
+	if data.FileAttributes&(fileAttributeOffline|fileAttributeRecallOnOpen|fileAttributeRecallOnDataAccess) != 0 {
+		return "online-only cloud folder"
+	}
+	return ""
+}
+
+// cloudOnly reports whether reading a file would download it first
+func cloudOnly(path string) bool {
+	data, ok := findData(path)
+	return ok && data.FileAttributes&(fileAttributeOffline|fileAttributeRecallOnDataAccess) != 0
+}
+
+// findData reads the attributes of a file or directory as listed in its parent, which
+// doesn't open it and so never triggers a download
+func findData(path string) (syscall.Win32finddata, bool) {
+	var data syscall.Win32finddata
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return data, false
+	}
+	handle, err := syscall.FindFirstFile(name, &data)
+	if err != nil {
+		return data, false
+	}
+	syscall.FindClose(handle)
+	return data, true
+}
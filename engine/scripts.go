@@ -0,0 +1,347 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ScriptExt is the extension of automation scripts in db.ScriptsDir
+const ScriptExt = ".star"
+
+// scriptHookPrefix starts the names of the functions that run on events, e.g. on_archive
+const scriptHookPrefix = "on_"
+
+// scriptMaxSteps bounds the work of one script run, so a runaway loop can't hang DevBase
+const scriptMaxSteps = 10_000_000
+
+// Script is a user automation written in Starlark, a small Python dialect. Scripts can only
+// reach DevBase through the devbase module (scriptModule), so they can't run programs or
+// write files. A script can define action(project), run from the command palette or with
+// "devbase script run", and on_<event>(event) functions that run as hooks.
+type Script struct {
+	Name   string // File name without the extension
+	Title  string // First line of the module docstring, the name without one
+	Path   string
+	Action bool     // The script defines action()
+	Events []string // Event kinds the script has on_<event> functions for
+	Err    error    // Why the script doesn't parse; it can't run until fixed
+}
+
+// scriptModule is the project API available to scripts besides the Starlark builtins
+var scriptModule = &starlarkstruct.Module{
+	Name: "devbase",
+	Members: starlark.StringDict{
+		"projects":   starlark.NewBuiltin("projects", scriptProjects),
+		"has_file":   starlark.NewBuiltin("has_file", scriptHasFile),
+		"has_tag":    starlark.NewBuiltin("has_tag", scriptHasTag),
+		"add_tag":    starlark.NewBuiltin("add_tag", scriptAddTag),
+		"remove_tag": starlark.NewBuiltin("remove_tag", scriptRemoveTag),
+	},
+}
+
+// Scripts lists the scripts in db.ScriptsDir sorted by name. A missing directory has none.
+// Listing only parses the scripts; none of their code runs.
+func Scripts() ([]Script, error) {
+	dir, err := db.ScriptsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scripts: %w", err)
+	}
+
+	var scripts []Script
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ScriptExt {
+			continue
+		}
+		script := Script{Name: strings.TrimSuffix(entry.Name(), ScriptExt), Path: filepath.Join(dir, entry.Name())}
+		script.Title = script.Name
+		script.Err = script.parse()
+		scripts = append(scripts, script)
+	}
+	return scripts, nil
+}
+
+// parse reads the script's title and the functions it defines
+func (s *Script) parse() error {
+	file, err := syntax.Parse(s.Path, nil, 0)
+	if err != nil {
+		return fmt.Errorf("script %s: %w", s.Name, err)
+	}
+	for i, stmt := range file.Stmts {
+		if expr, ok := stmt.(*syntax.ExprStmt); ok && i == 0 {
+			if doc, ok := expr.X.(*syntax.Literal); ok && doc.Token == syntax.STRING {
+				if title, _, _ := strings.Cut(strings.TrimSpace(doc.Value.(string)), "\n"); title != "" {
+					s.Title = title
+				}
+			}
+		}
+		def, ok := stmt.(*syntax.DefStmt)
+		if !ok {
+			continue
+		}
+		if def.Name.Name == "action" {
+			s.Action = true
+		} else if kind, ok := strings.CutPrefix(def.Name.Name, scriptHookPrefix); ok {
+			s.Events = append(s.Events, kind)
+		}
+	}
+	return nil
+}
+
+// FindScript returns the script with the given name that defines action()
+func FindScript(name string) (Script, error) {
+	scripts, err := Scripts()
+	if err != nil {
+		return Script{}, err
+	}
+	for _, script := range scripts {
+		if script.Name != name {
+			continue
+		}
+		if script.Err != nil {
+			return Script{}, script.Err
+		}
+		if script.Action {
+			return script, nil
+		}
+	}
+	return Script{}, fmt.Errorf("no script named %q with an action", name)
+}
+
+// Run calls the script's action with a project (None for nil) and returns what it printed
+// followed by the string it returned, trimmed. fail() or an API error stops the script.
+func (s Script) Run(project *models.Project) (string, error) {
+	arg := starlark.Value(starlark.None)
+	if project != nil {
+		arg = scriptProject(*project)
+	}
+	return s.call("action", arg)
+}
+
+// call executes the script and calls one of its functions. The argument is dropped for
+// functions that take none.
+func (s Script) call(function string, arg starlark.Value) (string, error) {
+	var out strings.Builder
+	thread := &starlark.Thread{
+		Name:  s.Name,
+		Print: func(_ *starlark.Thread, msg string) { out.WriteString(msg + "\n") },
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+
+	globals, err := starlark.ExecFile(thread, s.Path, nil, starlark.StringDict{"devbase": scriptModule})
+	if err != nil {
+		return strings.TrimSpace(out.String()), scriptError(s.Name, err)
+	}
+	fn, ok := globals[function].(*starlark.Function)
+	if !ok {
+		return "", fmt.Errorf("script %s: %s is not a function", s.Name, function)
+	}
+	var args starlark.Tuple
+	if fn.NumParams() > 0 {
+		args = starlark.Tuple{arg}
+	}
+	result, err := starlark.Call(thread, fn, args, nil)
+	if err != nil {
+		return strings.TrimSpace(out.String()), scriptError(s.Name, err)
+	}
+	switch result := result.(type) {
+	case starlark.NoneType:
+	case starlark.String:
+		out.WriteString(string(result))
+	default:
+		return strings.TrimSpace(out.String()), fmt.Errorf("script %s: %s returned a %s, not a string or None", s.Name, function, result.Type())
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// scriptError names the script and the line of the script an error stopped it at
+func scriptError(name string, err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		for i := range evalErr.CallStack {
+			// Builtins such as fail() have no position, the line that called them has
+			if pos := evalErr.CallStack.At(i).Pos; pos.Line > 0 {
+				return fmt.Errorf("script %s: line %d: %s", name, pos.Line, evalErr.Msg)
+			}
+		}
+	}
+	return fmt.Errorf("script %s: %w", name, err)
+}
+
+// scriptProject converts a project to the struct scripts see
+func scriptProject(p models.Project) *starlarkstruct.Struct {
+	tags := make([]starlark.Value, len(p.Tags))
+	for i, tag := range p.Tags {
+		tags[i] = starlark.String(tag)
+	}
+	return starlarkstruct.FromStringDict(starlark.String("project"), starlark.StringDict{
+		"id":          starlark.MakeUint(p.ID),
+		"name":        starlark.String(p.Name),
+		"path":        starlark.String(p.Path),
+		"status":      starlark.String(p.Status),
+		"language":    starlark.String(p.Language),
+		"repo_url":    starlark.String(p.RepoURL),
+		"description": starlark.String(p.Description),
+		"tags":        starlark.Tuple(tags),
+	})
+}
+
+// scriptArgProject looks up the project a script passed to a function by its id, so the
+// function works on the stored project rather than on values the script controls
+func scriptArgProject(v starlark.Value) (*models.Project, error) {
+	s, ok := v.(*starlarkstruct.Struct)
+	if !ok {
+		return nil, fmt.Errorf("got %s, want a project", v.Type())
+	}
+	idValue, err := s.Attr("id")
+	if err != nil {
+		return nil, fmt.Errorf("got %s, want a project", v.Type())
+	}
+	var id uint
+	if err := starlark.AsInt(idValue, &id); err != nil {
+		return nil, err
+	}
+	return db.GetProjectByID(id)
+}
+
+// scriptProjects returns the projects of the active root folder, of one status if given
+func scriptProjects(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var status string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "status?", &status); err != nil {
+		return nil, err
+	}
+	projects, err := db.GetProjectsByStatus(status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	list := make([]starlark.Value, len(projects))
+	for i, p := range projects {
+		list[i] = scriptProject(p)
+	}
+	return starlark.NewList(list), nil
+}
+
+// scriptHasFile reports whether a file or directory exists in the project's directory.
+// Names must stay inside it, so ../ or absolute paths are rejected.
+func scriptHasFile(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var value starlark.Value
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "project", &value, "name", &name); err != nil {
+		return nil, err
+	}
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("%s: %q is not inside the project", b.Name(), name)
+	}
+	project, err := scriptArgProject(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	_, err = os.Stat(filepath.Join(project.Path, name))
+	return starlark.Bool(err == nil), nil
+}
+
+// scriptHasTag reports whether the project has a tag
+func scriptHasTag(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	project, tag, err := scriptTagArgs(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.Bool(slices.Contains(project.Tags, db.NormalizeTag(tag))), nil
+}
+
+// scriptAddTag adds a tag to the project
+func scriptAddTag(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	project, tag, err := scriptTagArgs(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AddProjectTag(project.ID, tag); err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// scriptRemoveTag removes a tag from the project
+func scriptRemoveTag(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	project, tag, err := scriptTagArgs(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.RemoveProjectTag(project.ID, db.NormalizeTag(tag)); err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// scriptTagArgs unpacks the (project, tag) arguments of the tag functions
+func scriptTagArgs(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (*models.Project, string, error) {
+	var value starlark.Value
+	var tag string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "project", &value, "tag", &tag); err != nil {
+		return nil, "", err
+	}
+	project, err := scriptArgProject(value)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	return project, tag, nil
+}
+
+// runScriptHooks calls the on_<event> functions of the scripts with the event. A string a
+// hook returns is logged.
+func runScriptHooks(event Event) error {
+	scripts, err := Scripts()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, script := range scripts {
+		if !slices.Contains(script.Events, event.Kind) {
+			continue
+		}
+		output, err := script.call(scriptHookPrefix+event.Kind, scriptEvent(event))
+		if err != nil {
+			errs = append(errs, err)
+		} else if output != "" {
+			slog.Info("Script hook", "script", script.Name, "event", event.Kind, "output", output)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// scriptEvent converts an event to the struct hooks see. Its project is None when the event
+// isn't about a registered project.
+func scriptEvent(event Event) *starlarkstruct.Struct {
+	project := starlark.Value(starlark.None)
+	if event.Project != "" {
+		if p, err := db.GetProjectByPath(event.Path); err == nil {
+			project = scriptProject(*p)
+		}
+	}
+	return starlarkstruct.FromStringDict(starlark.String("event"), starlark.StringDict{
+		"kind":    starlark.String(event.Kind),
+		"project": project,
+		"path":    starlark.String(event.Path),
+		"detail":  starlark.String(event.Detail),
+		"time":    starlark.String(event.Time.Format(time.RFC3339)),
+	})
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// What happens to possible secrets in the projects pushed to a gist, set with the
+// "sync_secret_action" config key
+const (
+	SecretActionBlock  = "block"  // Refuse to push (default)
+	SecretActionRedact = "redact" // Push with the matches replaced by secretRedacted
+	SecretActionOff    = "off"    // Don't scan
+)
+
+// secretRedacted replaces the matches of redacted pushes
+const secretRedacted = "[redacted]"
+
+// ErrSecretsFound is returned when the projects to push contain possible secrets and
+// sync_secret_action is block
+var ErrSecretsFound = errors.New("possible secrets in the projects to sync")
+
+// SecretRule is a pattern of text that must not end up in a gist
+type SecretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// builtinSecretRules find tokens and keys of common services and credentials in URLs
+var builtinSecretRules = []SecretRule{
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"Stripe key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{16,}`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"password in URL", regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:[^/\s@]+@`)},
+	{"secret assignment", regexp.MustCompile(`(?i)\b(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)\s*[:=]\s*\S{8,}`)},
+}
+
+// SecretMatch is a possible secret found in a project about to be pushed
+type SecretMatch struct {
+	Project string // Name of the project
+	Field   string // JSON name of the field, e.g. "notes"
+	Rule    string // Name of the built-in rule, or the configured pattern
+	Excerpt string // The match with all but its first characters masked
+}
+
+// String describes a match for messages
+func (m SecretMatch) String() string {
+	return fmt.Sprintf("%s (%s: %s %s)", m.Project, m.Field, m.Rule, m.Excerpt)
+}
+
+// SecretAction returns the "sync_secret_action" config key, block when it is unset or unknown
+func SecretAction() string {
+	action, _ := db.GetConfig("sync_secret_action")
+	switch action = strings.ToLower(strings.TrimSpace(action)); action {
+	case SecretActionRedact, SecretActionOff:
+		return action
+	}
+	return SecretActionBlock
+}
+
+// SecretRules returns the built-in rules and the sensitive patterns of the
+// "sync_sensitive_patterns" config key, comma-separated case-insensitive regular expressions
+// for text such as client names or internal hostnames
+func SecretRules() ([]SecretRule, error) {
+	rules := append([]SecretRule(nil), builtinSecretRules...)
+	value, _ := db.GetConfig("sync_sensitive_patterns")
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("sync_sensitive_patterns: invalid pattern %q: %w", pattern, err)
+		}
+		rules = append(rules, SecretRule{Name: pattern, Pattern: re})
+	}
+	return rules, nil
+}
+
+// ScanProjects finds the matches of rules in the text fields of projects
+func ScanProjects(projects []models.Project, rules []SecretRule) []SecretMatch {
+	var matches []SecretMatch
+	for i := range projects {
+		eachTextField(&projects[i], func(field string, value *string) {
+			for _, rule := range rules {
+				for _, found := range rule.Pattern.FindAllString(*value, -1) {
+					matches = append(matches, SecretMatch{
+						Project: projects[i].Name,
+						Field:   field,
+						Rule:    rule.Name,
+						Excerpt: maskSecret(found),
+					})
+				}
+			}
+		})
+	}
+	return matches
+}
+
+// RedactProjects returns copies of projects with the matches of rules replaced
+func RedactProjects(projects []models.Project, rules []SecretRule) []models.Project {
+	redacted := make([]models.Project, len(projects))
+	for i, project := range projects {
+		project.Tags = append([]string(nil), project.Tags...)
+		eachTextField(&project, func(_ string, value *string) {
+			for _, rule := range rules {
+				*value = rule.Pattern.ReplaceAllLiteralString(*value, secretRedacted)
+			}
+		})
+		redacted[i] = project
+	}
+	return redacted
+}
+
+// PrepareSyncPayload applies the sync_secret_action policy to the projects about to be pushed
+// to a gist, which are easy to share by accident. It returns the projects to upload and the
+// possible secrets found; with block, finding any fails with ErrSecretsFound.
+func PrepareSyncPayload(projects []models.Project) ([]models.Project, []SecretMatch, error) {
+	action := SecretAction()
+	if action == SecretActionOff {
+		return projects, nil, nil
+	}
+	rules, err := SecretRules()
+	if err != nil {
+		return nil, nil, err
+	}
+	matches := ScanProjects(projects, rules)
+	switch {
+	case len(matches) == 0:
+		return projects, nil, nil
+	case action == SecretActionRedact:
+		return RedactProjects(projects, rules), matches, nil
+	}
+	return nil, matches, secretsError(matches)
+}
+
+// secretsError wraps ErrSecretsFound with the first matches and how to get past them
+func secretsError(matches []SecretMatch) error {
+	const shown = 3
+	list := make([]string, 0, shown)
+	for i, match := range matches {
+		if i == shown {
+			list = append(list, fmt.Sprintf("and %d more", len(matches)-shown))
+			break
+		}
+		list = append(list, match.String())
+	}
+	return fmt.Errorf("%w: %s; remove them, or set sync_secret_action to redact",
+		ErrSecretsFound, strings.Join(list, ", "))
+}
+
+// eachTextField calls fn with the JSON name and a pointer to each string of a project,
+// including its tags
+func eachTextField(project *models.Project, fn func(field string, value *string)) {
+	v := reflect.ValueOf(project).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		field := v.Field(i)
+		switch {
+		case field.Kind() == reflect.String:
+			fn(name, field.Addr().Interface().(*string))
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+			for j := 0; j < field.Len(); j++ {
+				fn(name, field.Index(j).Addr().Interface().(*string))
+			}
+		}
+	}
+}
+
+// maskSecret keeps the first characters of a match, enough to find it again
+func maskSecret(s string) string {
+	runes := []rune(s)
+	keep := 4
+	if len(runes) <= keep*2 {
+		keep = len(runes) / 2
+	}
+	return string(runes[:keep]) + strings.Repeat("*", min(len(runes)-keep, 8))
+}
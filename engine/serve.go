@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// configCheckInterval is how often the daemon checks config.toml for changes
+const configCheckInterval = 10 * time.Second
+
+// telemetryCheckInterval is how often the daemon checks whether a telemetry report is due
+const telemetryCheckInterval = 24 * time.Hour
+
+// trashCheckInterval is how often the daemon purges archived projects from the trash
+const trashCheckInterval = time.Hour
+
+// shutdownTimeout is how long open requests get to finish when the daemon stops
+const shutdownTimeout = 5 * time.Second
+
+// ServeOptions configures "devbase serve"
+type ServeOptions struct {
+	Addr         string        // Listen address of /metrics and /healthz
+	ScanInterval time.Duration // How often every local root folder is rescanned, 0 disables scans
+	SyncInterval time.Duration // How often root folders with a cloud backup are pushed, 0 disables syncs
+}
+
+// Serve runs DevBase as a daemon: root folders are rescanned and synced on their intervals
+// while /metrics and /healthz are served on opts.Addr. Progress goes to the default slog
+// logger. It returns when the server fails, or nil once ctx is cancelled and the running
+// scan, sync and requests have stopped.
+func Serve(ctx context.Context, opts ServeOptions) error {
+	token, _ := db.GetConfig("github_token")
+	if opts.SyncInterval > 0 && token == "" {
+		return fmt.Errorf("syncing needs GitHub authentication; authenticate in the TUI first (press 't')")
+	}
+
+	metrics := NewMetrics()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	mux.HandleFunc("/healthz", serveHealth)
+
+	// Background work is tied to ctx; Serve waits for it so the database isn't closed under
+	// a scan or sync
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, job := range []struct {
+		interval time.Duration
+		fn       func()
+	}{
+		{configCheckInterval, reloadConfigFile},
+		{telemetryCheckInterval, sendTelemetry},
+		{trashCheckInterval, purgeTrash},
+		{opts.ScanInterval, func() { scanRootFolders(ctx, metrics) }},
+		{opts.SyncInterval, func() { syncRootFolders(token, metrics) }},
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			every(ctx, job.interval, job.fn)
+		}()
+	}
+
+	server := &http.Server{Addr: opts.Addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelShutdown()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("Metrics server did not shut down cleanly", "err", err)
+		}
+	}()
+
+	slog.Info("Serving metrics", "url", "http://"+opts.Addr+"/metrics", "scan_interval", opts.ScanInterval, "sync_interval", opts.SyncInterval)
+	err := server.ListenAndServe()
+	cancel()
+	wg.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		slog.Info("Stopped serving")
+		return nil
+	}
+	return err
+}
+
+// reloadConfigFile picks up config.toml changes, such as new scanner ignores, while serving.
+// Listen address and intervals only change on restart.
+func reloadConfigFile() {
+	changed, err := db.ReloadConfigFile()
+	if err != nil {
+		slog.Error("Config file ignored", "err", err)
+	} else if changed {
+		slog.Info("Reloaded config file")
+	}
+}
+
+// sendTelemetry sends the usage report when the user opted in and one is due
+func sendTelemetry() {
+	if err := SendTelemetryIfDue(); err != nil {
+		slog.Warn("Telemetry not sent", "err", err)
+	}
+}
+
+// purgeTrash deletes the archived checkouts the retention policy has made due
+func purgeTrash() {
+	if _, err := PurgeTrash(); err != nil {
+		slog.Warn("Failed to purge the trash", "err", err)
+	}
+}
+
+// serveHealth reports whether the database can be reached
+func serveHealth(w http.ResponseWriter, r *http.Request) {
+	if err := db.Ping(); err != nil {
+		http.Error(w, "database unavailable: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+// every runs fn right away and then once per interval until ctx is cancelled. A zero
+// interval never runs it.
+func every(ctx context.Context, interval time.Duration, fn func()) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fn()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanRootFolders rescans every root folder like the TUI's scan does. Cancelling ctx stops
+// the running scan and skips the remaining folders.
+func scanRootFolders(ctx context.Context, metrics *Metrics) {
+	roots, err := db.GetAllRootFolders()
+	if err != nil {
+		slog.Error("Scan skipped", "err", err)
+		return
+	}
+	for _, root := range roots {
+		if ctx.Err() != nil {
+			return
+		}
+		start := time.Now()
+		result, err := ScanRootFolder(ctx, root.ID, root.Path)
+		if errors.Is(err, context.Canceled) {
+			slog.Info("Scan cancelled", "root", root.Path)
+			return
+		}
+		metrics.ObserveScan(root.Path, time.Since(start), err)
+		if err != nil {
+			slog.Error("Scan failed", "root", root.Path, "err", err)
+			continue
+		}
+		slog.Info("Scanned root folder", "root", root.Path, "found", result.Found, "added", result.Added, "updated", result.Updated, "removed", result.Removed)
+	}
+}
+
+// syncRootFolders pushes the projects of every root folder that was synced before.
+// Folders without a cloud backup are left alone so the daemon never creates gists.
+func syncRootFolders(token string, metrics *Metrics) {
+	roots, err := db.GetAllRootFolders()
+	if err != nil {
+		metrics.ObserveSync(err)
+		slog.Error("Sync skipped", "err", err)
+		return
+	}
+	for _, root := range roots {
+		if root.GistID == "" {
+			continue
+		}
+		err := PushRootFolder(token, root)
+		metrics.ObserveSync(err)
+		if err != nil {
+			slog.Error("Sync failed", "root", root.Name, "err", err)
+			_ = Emit(Event{Kind: EventSyncFailed, Detail: fmt.Sprintf("%s: %v", root.Name, err), Time: time.Now()})
+		}
+	}
+}
+
+// PushRootFolder pushes the projects of one root folder to its gist, creating the gist
+// when the root folder has none yet
+func PushRootFolder(token string, root models.RootFolder) error {
+	client, err := NewGistClient(token, root.ID)
+	if err != nil {
+		return err
+	}
+	projects, err := db.GetProjectsByRootFolder(root.ID)
+	if err != nil {
+		return err
+	}
+	if err := client.SaveToGist(projects); err != nil {
+		return err
+	}
+	_ = db.LogActivity(models.ActivitySync, 0, fmt.Sprintf("Pushed %d projects of %s to the cloud", len(projects), root.Name))
+	return nil
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// SessionProjects returns the projects of a session that can be opened locally, in the
+// session's order. Archived, remote and deleted projects and those whose directory is missing
+// are counted as skipped.
+func SessionProjects(projectIDs []uint) (projects []models.Project, skipped int) {
+	for _, id := range projectIDs {
+		project, err := db.GetProjectByID(id)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if _, err := os.Stat(project.Path); project.Status == "archived" || project.RemoteHostID != 0 || err != nil {
+			skipped++
+			continue
+		}
+		projects = append(projects, *project)
+	}
+	return projects, skipped
+}
+
+// WriteSessionWorkspace writes the .code-workspace file of a saved session with the
+// directories of its projects that are available, and returns its path
+func WriteSessionWorkspace(session models.Session) (string, error) {
+	projects, _ := SessionProjects(session.ProjectIDs)
+	paths := make([]string, len(projects))
+	for i, project := range projects {
+		paths[i] = project.Path
+	}
+	return WriteWorkspaceFile(session.Name, paths)
+}
+
+// RemoveSessionWorkspace deletes the .code-workspace file of a session
+func RemoveSessionWorkspace(name string) error {
+	file, err := WorkspaceFilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove workspace file: %w", err)
+	}
+	return nil
+}
+
+// SyncSessionWorkspaces rewrites the workspace files of the saved sessions containing a
+// project after it was archived, restored, moved or deleted. Failures are logged; the files
+// are written again when a session is opened.
+func SyncSessionWorkspaces(projectID uint) {
+	sessions, err := db.GetSessions()
+	if err != nil {
+		slog.Warn("Failed to update session workspaces", "project", projectID, "err", err)
+		return
+	}
+	for _, session := range sessions {
+		if !slices.Contains(session.ProjectIDs, projectID) {
+			continue
+		}
+		if _, err := WriteSessionWorkspace(session); err != nil {
+			slog.Warn("Failed to update session workspace", "session", session.Name, "err", err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// settingsFormat and settingsVersion identify the bundle written by ExportSettings
+const (
+	settingsFormat  = "devbase-settings"
+	settingsVersion = 1
+)
+
+// SettingsKeys are the config keys a settings bundle carries: preferences that make sense
+// on another machine or for a team. Tokens, gist IDs, telemetry IDs and machine-specific
+// keys such as path_map, plugins and wsl_distro stay out.
+var SettingsKeys = []string{
+	"keymap", "recent_hotkeys", "theme", "nerd_font", "language", "layout_detail", "layout_notes", "layout_list_ratio", "layout_notes_ratio", "list_columns",
+	"editor", "editor_prompt", "terminal", "git_client", "tmux_layout", "run_env", "run_output", "run_config_offer",
+	"scanner_ignore", "stale_days", "archive_trash_days", "log_level", "github_org", "github_client_id", "backup_require_signature",
+	"sync_sensitive_patterns", "sync_secret_action",
+	"serve_addr", "serve_scan_interval", "serve_sync_interval",
+}
+
+// Settings is a bundle of preferences and root folders to move between machines
+type Settings struct {
+	Format      string               `json:"format"`
+	Version     int                  `json:"version"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Config      map[string]string    `json:"config"`
+	RootFolders []SettingsRootFolder `json:"root_folders"`
+}
+
+// SettingsRootFolder is a root folder of a settings bundle. Paths under the home directory
+// are written relative to it as ~/..., so they fit another user's home.
+type SettingsRootFolder struct {
+	Name          string   `json:"name"`
+	Path          string   `json:"path"`
+	Active        bool     `json:"active,omitempty"`
+	NeverRegister []string `json:"never_register,omitempty"` // Relative to the root folder, so they need no rewriting
+}
+
+// SettingsImport reports what ImportSettings changed
+type SettingsImport struct {
+	Config      []string // Keys set, sorted
+	Overridden  []string // Keys set but still taken from config.toml
+	Ignored     []string // Keys of the bundle that aren't settings, e.g. from a newer version
+	RootFolders []string // Root folders added
+	Missing     []string // Root folders skipped because their directory doesn't exist here
+	Activated   string   // Root folder made active because none was
+}
+
+// ExportSettings collects the settings of SettingsKeys that are set and the root folders
+func ExportSettings() (*Settings, error) {
+	config, err := db.GetAllConfig()
+	if err != nil {
+		return nil, err
+	}
+	rootFolders, err := db.GetAllRootFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &Settings{
+		Format:    settingsFormat,
+		Version:   settingsVersion,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Config:    make(map[string]string),
+	}
+	for _, key := range SettingsKeys {
+		if value, ok := config[key]; ok && value != "" {
+			settings.Config[key] = value
+		}
+	}
+	for _, rootFolder := range rootFolders {
+		settings.RootFolders = append(settings.RootFolders, SettingsRootFolder{
+			Name:          rootFolder.Name,
+			Path:          collapseHome(rootFolder.Path),
+			Active:        rootFolder.IsActive,
+			NeverRegister: rootFolder.NeverRegister,
+		})
+	}
+	return settings, nil
+}
+
+// EncodeSettings writes a settings bundle as indented JSON
+func EncodeSettings(settings *Settings) ([]byte, error) {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// DecodeSettings reads a settings bundle written by EncodeSettings
+func DecodeSettings(data []byte) (*Settings, error) {
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("invalid settings bundle: %w", err)
+	}
+	if settings.Format != settingsFormat {
+		return nil, fmt.Errorf("not a DevBase settings bundle")
+	}
+	if settings.Version > settingsVersion {
+		return nil, fmt.Errorf("settings version %d is newer than this DevBase supports; update DevBase", settings.Version)
+	}
+	return &settings, nil
+}
+
+// ImportSettings stores the config keys of a bundle that are SettingsKeys and adds its root
+// folders that exist on this machine, after rewriting their paths with the path_map rules.
+// Root folders already registered are left as they are. When no root folder is active,
+// the bundle's active one (or the first one added) becomes active.
+func ImportSettings(settings *Settings) (*SettingsImport, error) {
+	result := &SettingsImport{}
+	keys := make([]string, 0, len(settings.Config))
+	for key := range settings.Config {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !slices.Contains(SettingsKeys, key) {
+			result.Ignored = append(result.Ignored, key)
+			continue
+		}
+		value := settings.Config[key]
+		if err := db.SetConfig(key, value); err != nil {
+			return result, err
+		}
+		result.Config = append(result.Config, key)
+		if current, _ := db.GetConfig(key); current != value {
+			result.Overridden = append(result.Overridden, key)
+		}
+	}
+
+	mappings, err := PathMappings()
+	if err != nil {
+		return result, fmt.Errorf("path_map config key: %w", err)
+	}
+	var activate *models.RootFolder
+	activateMarked := false
+	for _, entry := range settings.RootFolders {
+		path := expandHome(entry.Path)
+		if mapped, ok := MapPath(entry.Path, mappings); ok {
+			path = mapped
+		}
+		path = filepath.Clean(path)
+
+		if _, err := db.GetRootFolderByPath(path); err == nil {
+			continue
+		} else if !errors.Is(err, db.ErrRootFolderNotFound) {
+			return result, err
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			result.Missing = append(result.Missing, path)
+			continue
+		}
+
+		name := entry.Name
+		if name == "" {
+			name = filepath.Base(path)
+		}
+		rootFolder := &models.RootFolder{Name: name, Path: path, NeverRegister: entry.NeverRegister}
+		if err := db.AddRootFolder(rootFolder); err != nil {
+			return result, err
+		}
+		result.RootFolders = append(result.RootFolders, path)
+		if activate == nil || entry.Active && !activateMarked {
+			activate, activateMarked = rootFolder, entry.Active
+		}
+	}
+
+	if _, err := db.GetActiveRootFolder(); errors.Is(err, db.ErrRootFolderNotFound) && activate != nil {
+		if err := db.SetActiveRootFolder(activate.ID); err != nil {
+			return result, err
+		}
+		if err := db.SetConfig("root_scan_path", activate.Path); err != nil {
+			return result, err
+		}
+		result.Activated = activate.Path
+	}
+	return result, nil
+}
+
+// collapseHome writes a path under the home directory as ~/..., the reverse of expandHome
+func collapseHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	rel, err := filepath.Rel(home, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	if rel == "." {
+		return "~"
+	}
+	return "~/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// DefaultStaleDays is how long a project can go without opens and commits before it is
+// reported as stale, unless the stale_days config key says otherwise
+const DefaultStaleDays = 90
+
+// StaleProject is a project reported by StaleProjects as a candidate for archiving
+type StaleProject struct {
+	Project      models.Project
+	LastActivity time.Time // Later of the last open and the last commit
+	Size         int64     // Total size of the files in bytes
+	Branch       string    // Checked out branch, empty when unknown or not a git repo
+	Dirty        bool      // Uncommitted changes, which archiving would lose
+}
+
+// Restorable reports whether the project can be cloned back after archiving
+func (s StaleProject) Restorable() bool {
+	return s.Project.RepoURL != ""
+}
+
+// Safe reports whether archiving loses nothing: the project can be restored and has no
+// uncommitted changes
+func (s StaleProject) Safe() bool {
+	return s.Restorable() && !s.Dirty
+}
+
+// Status describes the repository state that matters for archiving, e.g. "main, clean"
+func (s StaleProject) Status() string {
+	status := "clean"
+	switch {
+	case !s.Restorable():
+		status = "no remote, can't be restored"
+	case s.Dirty:
+		status = "uncommitted changes"
+	}
+	if s.Branch != "" {
+		status = s.Branch + ", " + status
+	}
+	return status
+}
+
+// StaleDays returns the stale_days config key, or DefaultStaleDays when it is unset or invalid
+func StaleDays() int {
+	value, err := db.GetConfig("stale_days")
+	if err != nil {
+		return DefaultStaleDays
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		return DefaultStaleDays
+	}
+	return days
+}
+
+// StaleProjects returns the active local projects of the active root folder that were
+// neither opened nor committed to in the last days, least recently active first. Projects
+// whose directory is missing are left to the path check. Sizes are computed for every
+// candidate, so this walks their directories.
+func StaleProjects(days int) ([]StaleProject, error) {
+	projects, err := db.GetProjectsByStatus("active")
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	// Projects with worktrees can't be archived either
+	parents := make(map[uint]bool)
+	for _, project := range projects {
+		if project.ParentID != 0 {
+			parents[project.ParentID] = true
+		}
+	}
+
+	var stale []StaleProject
+	for _, project := range projects {
+		// Locked projects can't be archived, so there is no point reporting them
+		if project.RemoteHostID != 0 || project.ParentID != 0 || parents[project.ID] || project.Locked || project.LastOpened.After(cutoff) {
+			continue
+		}
+		if _, err := os.Stat(project.Path); err != nil {
+			continue
+		}
+		candidate := StaleProject{Project: project, LastActivity: project.LastOpened}
+		if info, err := GetGitInfo(project.Path); err == nil {
+			if info.LastCommit.After(cutoff) {
+				continue
+			}
+			if info.LastCommit.After(candidate.LastActivity) {
+				candidate.LastActivity = info.LastCommit
+			}
+			candidate.Branch, candidate.Dirty = info.Branch, info.Dirty
+		}
+		candidate.Size = DirSize(project.Path)
+		stale = append(stale, candidate)
+	}
+
+	slices.SortStableFunc(stale, func(a, b StaleProject) int {
+		return a.LastActivity.Compare(b.LastActivity)
+	})
+	return stale, nil
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ProjectStats summarizes the projects of the active root folder
+type ProjectStats struct {
+	Total      int
+	Active     int
+	Archived   int
+	RootFolder string    // Name of the active root folder, empty without one
+	DiskUsage  int64     // Total size of the active local projects in bytes
+	LastScan   time.Time // Time of the last scan, zero before the first
+}
+
+// CollectProjectStats counts the projects of the active root folder and adds up the sizes
+// of the active local ones, which are reused for DirSizeTTL. toHost translates project
+// paths for this machine. Cancelling ctx stops adding up sizes.
+func CollectProjectStats(ctx context.Context, toHost func(string) string) (ProjectStats, error) {
+	var stats ProjectStats
+	if root, err := db.GetActiveRootFolder(); err == nil {
+		stats.RootFolder = root.Name
+	} else if !errors.Is(err, db.ErrRootFolderNotFound) {
+		return stats, err
+	}
+	if scan, err := db.LastActivity(models.ActivityScan); err != nil {
+		return stats, err
+	} else if scan != nil {
+		stats.LastScan = scan.CreatedAt
+	}
+
+	projects, err := db.GetProjects()
+	if err != nil {
+		return stats, err
+	}
+	stats.Total = len(projects)
+	for _, p := range projects {
+		if p.Status == "archived" {
+			stats.Archived++
+			continue
+		}
+		stats.Active++
+		if p.RemoteHostID == 0 && p.Path != "" && ctx.Err() == nil {
+			stats.DiskUsage += CachedDirSize(toHost(p.Path))
+		}
+	}
+	return stats, ctx.Err()
+}
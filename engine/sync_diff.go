@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"slices"
+	"sort"
+
+	"devbase/models"
+)
+
+// ProjectChange describes a project present on both sides whose synced fields differ
+type ProjectChange struct {
+	Name   string
+	Path   string
+	Fields []string // Names of the fields that differ (e.g. "status", "tags")
+}
+
+// SyncDiff summarizes what applying a source project list onto a target will do
+type SyncDiff struct {
+	Added     []models.Project // Only in the source, will be created on the target
+	Removed   []models.Project // Only in the target, will be dropped from it
+	Changed   []ProjectChange  // On both sides with different values, the source wins
+	Unchanged int              // On both sides and identical
+}
+
+// Empty reports whether applying the source would not change the target
+func (d SyncDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffProjects compares two project lists matched by path
+func DiffProjects(source, target []models.Project) SyncDiff {
+	var diff SyncDiff
+
+	targetByPath := make(map[string]models.Project, len(target))
+	for _, p := range target {
+		targetByPath[p.Path] = p
+	}
+
+	sourcePaths := make(map[string]bool, len(source))
+	for _, p := range source {
+		sourcePaths[p.Path] = true
+		existing, ok := targetByPath[p.Path]
+		if !ok {
+			diff.Added = append(diff.Added, p)
+			continue
+		}
+		if fields := changedFields(p, existing); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ProjectChange{Name: p.Name, Path: p.Path, Fields: fields})
+		} else {
+			diff.Unchanged++
+		}
+	}
+
+	for _, p := range target {
+		if !sourcePaths[p.Path] {
+			diff.Removed = append(diff.Removed, p)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	return diff
+}
+
+// changedFields lists the synced fields that differ between two versions of a project
+func changedFields(a, b models.Project) []string {
+	var fields []string
+	if a.Name != b.Name {
+		fields = append(fields, "name")
+	}
+	if a.RepoURL != b.RepoURL {
+		fields = append(fields, "repo")
+	}
+	if a.Status != b.Status {
+		fields = append(fields, "status")
+	}
+	if !slices.Equal(a.Tags, b.Tags) {
+		fields = append(fields, "tags")
+	}
+	if a.Language != b.Language {
+		fields = append(fields, "language")
+	}
+	if a.Notes != b.Notes {
+		fields = append(fields, "notes")
+	}
+	if a.Icon != b.Icon {
+		fields = append(fields, "icon")
+	}
+	if a.RestoreRef != b.RestoreRef {
+		fields = append(fields, "restore ref")
+	}
+	if a.CloneOptions != b.CloneOptions {
+		fields = append(fields, "clone options")
+	}
+	if a.Machine != b.Machine {
+		fields = append(fields, "machine")
+	}
+	return fields
+}
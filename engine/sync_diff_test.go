@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"devbase/models"
+)
+
+// TestDiffProjects tests matching projects by path into added, removed, changed and unchanged
+func TestDiffProjects(t *testing.T) {
+	api := models.Project{Name: "api", Path: "/code/api", Status: "active", Tags: []string{"go"}}
+	web := models.Project{Name: "web", Path: "/code/web", Status: "active"}
+	docs := models.Project{Name: "docs", Path: "/code/docs", Status: "archived"}
+
+	tests := []struct {
+		name           string
+		source, target []models.Project
+		added, removed []string
+		changed        []ProjectChange
+		unchanged      int
+	}{
+		{name: "both empty"},
+		{name: "identical", source: []models.Project{api, web}, target: []models.Project{web, api}, unchanged: 2},
+		{name: "added sorted by name", source: []models.Project{web, api}, added: []string{"api", "web"}},
+		{name: "removed sorted by name", target: []models.Project{web, docs}, removed: []string{"docs", "web"}},
+		{
+			name:    "matched by path",
+			source:  []models.Project{{Name: "api", Path: "/work/api", Status: "active", Tags: []string{"go"}}},
+			target:  []models.Project{api},
+			added:   []string{"api"},
+			removed: []string{"api"},
+		},
+		{
+			name:      "changed",
+			source:    []models.Project{{Name: "api-v2", Path: "/code/api", Status: "archived", Tags: []string{"go"}}, web},
+			target:    []models.Project{api, web},
+			changed:   []ProjectChange{{Name: "api-v2", Path: "/code/api", Fields: []string{"name", "status"}}},
+			unchanged: 1,
+		},
+		{
+			name:    "all at once",
+			source:  []models.Project{{Name: "web", Path: "/code/web", Status: "active", Tags: []string{"js"}}, docs},
+			target:  []models.Project{api, web},
+			added:   []string{"docs"},
+			removed: []string{"api"},
+			changed: []ProjectChange{{Name: "web", Path: "/code/web", Fields: []string{"tags"}}},
+		},
+	}
+	names := func(projects []models.Project) []string {
+		var names []string
+		for _, project := range projects {
+			names = append(names, project.Name)
+		}
+		return names
+	}
+	for _, tt := range tests {
+		diff := DiffProjects(tt.source, tt.target)
+		if !slices.Equal(names(diff.Added), tt.added) || !slices.Equal(names(diff.Removed), tt.removed) ||
+			!reflect.DeepEqual(diff.Changed, tt.changed) || diff.Unchanged != tt.unchanged {
+			t.Errorf("%s: DiffProjects = added %v, removed %v, changed %+v, %d unchanged; want %v, %v, %+v, %d", tt.name,
+				names(diff.Added), names(diff.Removed), diff.Changed, diff.Unchanged, tt.added, tt.removed, tt.changed, tt.unchanged)
+		}
+		if want := len(tt.added) == 0 && len(tt.removed) == 0 && len(tt.changed) == 0; diff.Empty() != want {
+			t.Errorf("%s: Empty() = %v, want %v", tt.name, diff.Empty(), want)
+		}
+	}
+}
+
+// TestChangedFields tests which fields count as a change: the synced details do, while
+// machine-local usage, settings and bookkeeping don't
+func TestChangedFields(t *testing.T) {
+	base := models.Project{Name: "api", Path: "/code/api", Status: "active", Tags: []string{"go", "work"}}
+
+	tests := []struct {
+		name   string
+		change func(p *models.Project)
+		want   []string
+	}{
+		{"name", func(p *models.Project) { p.Name = "api2" }, []string{"name"}},
+		{"repository", func(p *models.Project) { p.RepoURL = "https://github.com/acme/api" }, []string{"repo"}},
+		{"status", func(p *models.Project) { p.Status = "archived" }, []string{"status"}},
+		{"tag order", func(p *models.Project) { p.Tags = []string{"work", "go"} }, []string{"tags"}},
+		{"no tags", func(p *models.Project) { p.Tags = nil }, []string{"tags"}},
+		{"language", func(p *models.Project) { p.Language = "go" }, []string{"language"}},
+		{"notes", func(p *models.Project) { p.Notes = "# API" }, []string{"notes"}},
+		{"icon", func(p *models.Project) { p.Icon = "🛒" }, []string{"icon"}},
+		{"restore ref", func(p *models.Project) { p.RestoreRef = "develop" }, []string{"restore ref"}},
+		{"clone options", func(p *models.Project) { p.CloneOptions = "depth=1" }, []string{"clone options"}},
+		{"machine", func(p *models.Project) { p.Machine = "laptop" }, []string{"machine"}},
+		{"several in order", func(p *models.Project) { p.Status = "archived"; p.Name = "old"; p.Machine = "nas" }, []string{"name", "status", "machine"}},
+		{"local fields", func(p *models.Project) {
+			p.ID = 7
+			p.LastOpened = time.Now()
+			p.OpenCount = 12
+			p.Description = "An API"
+			p.Editor = "goland"
+			p.Pinned = true
+			p.Locked = true
+			p.NoReclaim = true
+			p.StartCommand = "make dev"
+			p.DevContainer = true
+			p.LastError = "clone failed"
+			p.RootFolderID = 3
+			p.ParentID = 2
+		}, nil},
+	}
+	for _, tt := range tests {
+		changed := base
+		changed.Tags = slices.Clone(base.Tags)
+		tt.change(&changed)
+		if got := changedFields(changed, base); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: changedFields = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
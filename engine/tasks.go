@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// Task is a runnable command detected in a project
+type Task struct {
+	Name    string // Short name shown in the picker (e.g. "test")
+	Command string // Shell command run from the project directory
+	Source  string // Where the task was found (e.g. "package.json", "Makefile")
+}
+
+// makeTargetPattern matches explicit Makefile target definitions, skipping variable assignments
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_./-]*)\s*:([^=]|$)`)
+
+// DetectTasks returns the tasks defined in a project directory: those of its devbase.yaml,
+// package.json scripts, Makefile targets and the standard commands of Go and Rust projects
+func DetectTasks(dir string) []Task {
+	tasks := runConfigTasks(dir)
+	tasks = append(tasks, npmTasks(dir)...)
+	tasks = append(tasks, makeTasks(dir)...)
+
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		tasks = append(tasks,
+			Task{Name: "test", Command: "go test ./...", Source: "go"},
+			Task{Name: "build", Command: "go build ./...", Source: "go"},
+			Task{Name: "vet", Command: "go vet ./...", Source: "go"},
+		)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "Cargo.toml")); err == nil {
+		tasks = append(tasks,
+			Task{Name: "test", Command: "cargo test", Source: "cargo"},
+			Task{Name: "build", Command: "cargo build", Source: "cargo"},
+		)
+	}
+
+	return tasks
+}
+
+// npmTasks returns the scripts from package.json, run with the package manager matching the lockfile
+func npmTasks(dir string) []Task {
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil
+	}
+
+	runner := npmClient(dir) + " run"
+	names := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tasks := make([]Task, 0, len(names))
+	for _, name := range names {
+		tasks = append(tasks, Task{Name: name, Command: runner + " " + name, Source: "package.json"})
+	}
+	return tasks
+}
+
+// npmClient returns the package manager matching the project's lockfile: npm, pnpm or yarn
+func npmClient(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "pnpm-lock.yaml")); err == nil {
+		return "pnpm"
+	} else if _, err := os.Stat(filepath.Join(dir, "yarn.lock")); err == nil {
+		return "yarn"
+	}
+	return "npm"
+}
+
+// makeTasks returns the explicit targets defined in the project's Makefile
+func makeTasks(dir string) []Task {
+	file, err := os.Open(filepath.Join(dir, "Makefile"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var tasks []Task
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := makeTargetPattern.FindStringSubmatch(scanner.Text())
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		tasks = append(tasks, Task{Name: match[1], Command: "make " + match[1], Source: "Makefile"})
+	}
+	return tasks
+}
@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"time"
+
+	"devbase/db"
+)
+
+// Version is the DevBase version included in telemetry reports, set by the executable
+var Version = "dev"
+
+// TelemetryInterval is how often a report is sent while telemetry is on
+const TelemetryInterval = 7 * 24 * time.Hour
+
+// telemetryClient sends reports, with a timeout so an unreachable endpoint can't stall DevBase
+var telemetryClient = &http.Client{Timeout: 10 * time.Second}
+
+// TelemetryReport is everything telemetry sends: counts and settings from fixed lists, never
+// paths, names, URLs or tags. "devbase telemetry preview" prints it as it would be sent.
+type TelemetryReport struct {
+	InstallID   string            `json:"install_id"` // Random ID created on opt-in, not derived from the machine
+	Version     string            `json:"version"`
+	OS          string            `json:"os"`
+	Arch        string            `json:"arch"`
+	Days        int               `json:"days"`         // Days covered by Activity
+	Projects    map[string]int64  `json:"projects"`     // Projects by status
+	Languages   map[string]int64  `json:"languages"`    // Active projects by detected language
+	RootFolders int               `json:"root_folders"` // Number of root folders
+	Activity    map[string]int64  `json:"activity"`     // Opens, scans, syncs, archives and restores in the period
+	Features    map[string]int    `json:"features"`     // How many of each optional feature are set up
+	Settings    map[string]string `json:"settings"`     // Keymap and theme
+}
+
+// TelemetryEnabled reports whether the user opted in to telemetry
+func TelemetryEnabled() bool {
+	enabled, _ := db.GetConfig("telemetry")
+	return enabled == "true"
+}
+
+// SetTelemetry turns telemetry on or off. Turning it on creates the install ID, turning it
+// off forgets it, so a later opt-in can't be linked to earlier reports.
+func SetTelemetry(enabled bool) error {
+	if !enabled {
+		if err := db.SetConfig("telemetry_id", ""); err != nil {
+			return err
+		}
+		return db.SetConfig("telemetry", "false")
+	}
+	if id, _ := db.GetConfig("telemetry_id"); id == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		if err := db.SetConfig("telemetry_id", hex.EncodeToString(b)); err != nil {
+			return err
+		}
+	}
+	return db.SetConfig("telemetry", "true")
+}
+
+// BuildTelemetryReport collects the report for the activity since the last one was sent,
+// or the last TelemetryInterval before the first
+func BuildTelemetryReport() (TelemetryReport, error) {
+	since := time.Now().Add(-TelemetryInterval)
+	if last, err := db.GetConfig("telemetry_last_sent"); err == nil {
+		if t, err := time.Parse(time.RFC3339, last); err == nil {
+			since = t
+		}
+	}
+
+	report := TelemetryReport{
+		Version:  Version,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Days:     int(time.Since(since).Hours()/24 + 0.5),
+		Features: make(map[string]int),
+		Settings: make(map[string]string),
+	}
+	report.InstallID, _ = db.GetConfig("telemetry_id")
+
+	var err error
+	if report.Projects, err = db.CountProjectsByStatus(); err != nil {
+		return report, err
+	}
+	if report.Languages, err = db.CountProjectsByLanguage(); err != nil {
+		return report, err
+	}
+	if report.Activity, err = db.CountActivitiesSince(since); err != nil {
+		return report, err
+	}
+
+	roots, err := db.GetAllRootFolders()
+	if err != nil {
+		return report, err
+	}
+	report.RootFolders = len(roots)
+	for _, root := range roots {
+		if root.GistID != "" {
+			report.Features["cloud_sync"]++
+		}
+	}
+	hosts, err := db.GetRemoteHosts()
+	if err != nil {
+		return report, err
+	}
+	sessions, err := db.GetSessions()
+	if err != nil {
+		return report, err
+	}
+	pinned, err := db.GetPinnedProjects()
+	if err != nil {
+		return report, err
+	}
+	webhooks, err := db.GetWebhooks()
+	if err != nil {
+		return report, err
+	}
+	scripts, _ := Scripts()
+	report.Features["remote_hosts"] = len(hosts)
+	report.Features["sessions"] = len(sessions)
+	report.Features["pinned"] = len(pinned)
+	report.Features["webhooks"] = len(webhooks)
+	report.Features["plugins"] = len(pluginPaths())
+	report.Features["scripts"] = len(scripts)
+
+	// Only values from known sets, so nothing typed by the user is sent
+	if keymap, _ := db.GetConfig("keymap"); keymap == "vim" {
+		report.Settings["keymap"] = "vim"
+	} else {
+		report.Settings["keymap"] = "default"
+	}
+	if theme, _ := db.GetConfig("theme"); theme == "high-contrast" {
+		report.Settings["theme"] = "high-contrast"
+	} else {
+		report.Settings["theme"] = "default"
+	}
+	return report, nil
+}
+
+// SendTelemetry posts the report to the telemetry_url config key and remembers when
+func SendTelemetry(report TelemetryReport) error {
+	url, _ := db.GetConfig("telemetry_url")
+	if url == "" {
+		return fmt.Errorf("no telemetry endpoint; set the telemetry_url config key")
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	resp, err := telemetryClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	slog.Info("Sent telemetry report", "url", url)
+	return db.SetConfig("telemetry_last_sent", time.Now().UTC().Format(time.RFC3339))
+}
+
+// SendTelemetryIfDue sends a report when telemetry is on and the last one is older than
+// TelemetryInterval. It does nothing for users who didn't opt in.
+func SendTelemetryIfDue() error {
+	if !TelemetryEnabled() {
+		return nil
+	}
+	if last, err := db.GetConfig("telemetry_last_sent"); err == nil {
+		if t, err := time.Parse(time.RFC3339, last); err == nil && time.Since(t) < TelemetryInterval {
+			return nil
+		}
+	}
+	report, err := BuildTelemetryReport()
+	if err != nil {
+		return err
+	}
+	return SendTelemetry(report)
+}
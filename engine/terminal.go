@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Terminal describes a terminal DevBase can open new windows with
+type Terminal struct {
+	Name    string // Display name
+	Command string // Executable looked up on PATH
+}
+
+// knownTerminals lists the terminals detected on PATH, in display order
+var knownTerminals = []Terminal{
+	{Name: "Windows Terminal", Command: "wt"},
+	{Name: "PowerShell 7", Command: "pwsh"},
+	{Name: "Windows PowerShell", Command: "powershell"},
+	{Name: "Command Prompt", Command: "cmd"},
+	{Name: "GNOME Terminal", Command: "gnome-terminal"},
+	{Name: "xterm", Command: "xterm"},
+}
+
+// DetectTerminals returns the known terminals whose command is available on PATH
+func DetectTerminals() []Terminal {
+	var terminals []Terminal
+	for _, terminal := range knownTerminals {
+		if _, err := exec.LookPath(terminal.Command); err == nil {
+			terminals = append(terminals, terminal)
+		}
+	}
+	return terminals
+}
+
+// TerminalByCommand returns the terminal for a command, treating unknown commands as PowerShell-compatible
+func TerminalByCommand(command string) Terminal {
+	for _, terminal := range knownTerminals {
+		if terminal.Command == command {
+			return terminal
+		}
+	}
+	return Terminal{Name: command, Command: command}
+}
+
+// TerminalCommand builds the command that opens a new terminal window in dir and runs command there.
+// The window stays open after the command finishes.
+func TerminalCommand(terminal Terminal, dir, command string) (*exec.Cmd, error) {
+	if terminal.Command == "" {
+		return nil, fmt.Errorf("no terminal command configured")
+	}
+	if err := LookupTerminal(terminal); err != nil {
+		return nil, err
+	}
+	switch terminal.Command {
+	case "wt":
+		return exec.Command("wt", "-d", dir, "powershell", "-NoExit", "-Command", command), nil
+	case "cmd":
+		return exec.Command("cmd", "/c", "start", "cmd", "/k", fmt.Sprintf("cd /d %s && %s", dir, command)), nil
+	case "gnome-terminal":
+		return exec.Command("gnome-terminal", "--working-directory="+dir, "--", "sh", "-c", command+"; exec \"${SHELL:-sh}\""), nil
+	case "xterm":
+		cmd := exec.Command("xterm", "-e", "sh", "-c", command+"; exec \"${SHELL:-sh}\"")
+		cmd.Dir = dir
+		return cmd, nil
+	default:
+		// PowerShell opens a new window of itself, change directory, and execute the command
+		psCommand := fmt.Sprintf("Start-Process %s -ArgumentList '-NoExit', '-Command', 'Set-Location -Path \"%s\"; %s'", terminal.Command, dir, command)
+		return exec.Command(terminal.Command, "-Command", psCommand), nil
+	}
+}
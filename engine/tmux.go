@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// TmuxWindow is a window created in new project sessions, optionally running a command
+type TmuxWindow struct {
+	Name    string
+	Command string
+}
+
+// TmuxAvailable reports whether tmux is installed on PATH
+func TmuxAvailable() bool {
+	_, err := exec.LookPath("tmux")
+	return err == nil
+}
+
+// InsideTmux reports whether DevBase itself runs inside a tmux client
+func InsideTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// TmuxSessionName turns a project name into a valid tmux session name.
+// tmux reserves '.' and ':' for window and pane targets.
+func TmuxSessionName(name string) string {
+	name = strings.TrimSpace(strings.NewReplacer(".", "_", ":", "_").Replace(name))
+	if name == "" {
+		return "devbase"
+	}
+	return name
+}
+
+// ParseTmuxLayout parses a window layout such as "editor:nvim .;server:npm run dev;shell".
+// Windows are separated by ';' and a window's command follows the first ':'.
+func ParseTmuxLayout(spec string) []TmuxWindow {
+	var windows []TmuxWindow
+	for _, entry := range strings.Split(spec, ";") {
+		name, command, _ := strings.Cut(entry, ":")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if name == "" {
+			continue
+		}
+		windows = append(windows, TmuxWindow{Name: name, Command: command})
+	}
+	return windows
+}
+
+// EnsureTmuxSession creates a detached tmux session in dir unless one with that name
+// already exists. New sessions get one window per layout entry. It reports whether
+// the session was created.
+func EnsureTmuxSession(session, dir string, windows []TmuxWindow) (bool, error) {
+	// "=" makes tmux match the session name exactly instead of by prefix
+	if exec.Command("tmux", "has-session", "-t", "="+session).Run() == nil {
+		return false, nil
+	}
+
+	args := []string{"new-session", "-d", "-s", session, "-c", dir}
+	if len(windows) > 0 {
+		args = append(args, "-n", windows[0].Name)
+	}
+	if output, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("failed to create tmux session: %s", strings.TrimSpace(string(output)))
+	}
+
+	for i, window := range windows {
+		target := fmt.Sprintf("=%s:%s", session, window.Name)
+		if i > 0 {
+			if output, err := exec.Command("tmux", "new-window", "-t", "="+session+":", "-n", window.Name, "-c", dir).CombinedOutput(); err != nil {
+				return true, fmt.Errorf("failed to create tmux window %q: %s", window.Name, strings.TrimSpace(string(output)))
+			}
+		}
+		if window.Command != "" {
+			if output, err := exec.Command("tmux", "send-keys", "-t", target, window.Command, "Enter").CombinedOutput(); err != nil {
+				return true, fmt.Errorf("failed to start %q in tmux: %s", window.Command, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+
+	// Start in the first window
+	if len(windows) > 1 {
+		exec.Command("tmux", "select-window", "-t", fmt.Sprintf("=%s:%s", session, windows[0].Name)).Run()
+	}
+	return true, nil
+}
+
+// TmuxAttachCommand builds the command that moves the user to a session: switching the
+// current client when already inside tmux, attaching this terminal otherwise
+func TmuxAttachCommand(session string) *exec.Cmd {
+	if InsideTmux() {
+		return exec.Command("tmux", "switch-client", "-t", "="+session)
+	}
+	return exec.Command("tmux", "attach-session", "-t", "="+session)
+}
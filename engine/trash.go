@@ -0,0 +1,257 @@
+package engine
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// DefaultTrashDays is the archive retention used when the archive_trash_days config key is
+// unset or invalid. The trash is off by default, so archiving frees the disk space right away.
+const DefaultTrashDays = 0
+
+// TrashRetention is how long the checkouts of archived projects stay in the trash
+type TrashRetention struct {
+	Days int // Days a checkout is kept; 0 deletes checkouts when they are archived
+}
+
+// TrashPurge is a checkout in the trash and when the retention policy purges it
+type TrashPurge struct {
+	Entry  models.TrashEntry
+	At     time.Time // When it is purged
+	Reason string
+}
+
+// Due reports whether the next cleanup purges the checkout
+func (p TrashPurge) Due(now time.Time) bool {
+	return !p.At.After(now)
+}
+
+// TrashRetentionConfig returns the archive retention set with the archive_trash_days config key
+func TrashRetentionConfig() TrashRetention {
+	retention := TrashRetention{Days: DefaultTrashDays}
+	if value, err := db.GetConfig("archive_trash_days"); err == nil {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			retention.Days = days
+		}
+	}
+	return retention
+}
+
+// PlanTrashPurges returns when each checkout in the trash is purged, next purge first: once it
+// is retention.Days old. Checkouts left from a longer retention are due right away.
+func PlanTrashPurges(entries []models.TrashEntry, retention TrashRetention) []TrashPurge {
+	purges := make([]TrashPurge, 0, len(entries))
+	for _, entry := range entries {
+		purges = append(purges, TrashPurge{
+			Entry:  entry,
+			At:     entry.CreatedAt.AddDate(0, 0, retention.Days),
+			Reason: fmt.Sprintf("kept %d days", retention.Days),
+		})
+	}
+	slices.SortStableFunc(purges, func(a, b TrashPurge) int {
+		return cmp.Or(a.At.Compare(b.At), cmp.Compare(a.Entry.Name, b.Entry.Name))
+	})
+	return purges
+}
+
+// TrashReport returns the checkouts in the trash with when the configured retention purges
+// them, next purge first
+func TrashReport() ([]TrashPurge, error) {
+	entries, err := db.GetTrashEntries()
+	if err != nil {
+		return nil, err
+	}
+	return PlanTrashPurges(entries, TrashRetentionConfig()), nil
+}
+
+// PurgeTrash deletes the checkouts the retention policy has made due and returns them. It
+// runs in the background of the TUI and "devbase serve", and with "devbase trash purge".
+func PurgeTrash() ([]TrashPurge, error) {
+	report, err := TrashReport()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var purged []TrashPurge
+	var errs []error
+	var size int64
+	for _, purge := range report {
+		if !purge.Due(now) {
+			continue
+		}
+		if err := purgeTrashEntry(purge.Entry); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		purged = append(purged, purge)
+		size += purge.Entry.Size
+	}
+	if len(purged) > 0 {
+		slog.Info("Purged archived projects from the trash", "count", len(purged), "size", FormatSize(size))
+	}
+	return purged, errors.Join(errs...)
+}
+
+// PurgeTrashEntry deletes one checkout from the trash before the retention policy would
+func PurgeTrashEntry(id uint) error {
+	entries, err := db.GetTrashEntries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.ID == id {
+			return purgeTrashEntry(entry)
+		}
+	}
+	return fmt.Errorf("no archived project %d in the trash", id)
+}
+
+// purgeTrashEntry deletes a checkout and forgets it
+func purgeTrashEntry(entry models.TrashEntry) error {
+	if err := os.RemoveAll(entry.Path); err != nil {
+		return fmt.Errorf("failed to purge %s from the trash: %w", entry.Name, err)
+	}
+	return db.DeleteTrashEntry(entry.ID)
+}
+
+// purgeProjectTrash deletes every checkout of a project from the trash
+func purgeProjectTrash(projectID uint) error {
+	entries, err := db.GetTrashEntries()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, entry := range entries {
+		if entry.ProjectID == projectID {
+			errs = append(errs, purgeTrashEntry(entry))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// latestTrashEntry returns the newest checkout of a project in the trash, nil when it has
+// none. Entries whose directory was deleted by hand are forgotten.
+func latestTrashEntry(projectID uint) (*models.TrashEntry, error) {
+	entries, err := db.GetTrashEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.ProjectID != projectID {
+			continue
+		}
+		if _, err := os.Stat(entry.Path); err == nil {
+			return &entry, nil
+		}
+		slog.Warn("Archived project is missing from the trash", "project", entry.Name, "path", entry.Path)
+		if err := db.DeleteTrashEntry(entry.ID); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// trashCheckout moves the checkout of a project being archived into the trash
+func trashCheckout(project *models.Project) error {
+	dir, err := db.TrashDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	entry := models.TrashEntry{
+		ProjectID:    project.ID,
+		Name:         project.Name,
+		OriginalPath: project.Path,
+		Path:         filepath.Join(dir, fmt.Sprintf("%d-%s", project.ID, time.Now().Format("20060102-150405.000"))),
+		Size:         DirSize(project.Path),
+	}
+	if err := moveDir(project.Path, entry.Path); err != nil {
+		return fmt.Errorf("failed to move %s to the trash: %w", project.Path, err)
+	}
+	if err := db.AddTrashEntry(&entry); err != nil {
+		if moveErr := moveDir(entry.Path, project.Path); moveErr != nil {
+			slog.Error("Failed to move an archived project back from the trash", "path", entry.Path, "err", moveErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// moveDir moves a directory, copying it when it can't be renamed, e.g. to another drive
+func moveDir(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyDir(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	if err := os.RemoveAll(src); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return nil
+}
+
+// copyDir copies a directory tree with its file modes and symlinks
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// copyFile copies a regular file
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
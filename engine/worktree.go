@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ErrWorktreeProject is returned when archiving a project registered as a worktree, which
+// restoring couldn't bring back; it is removed with RemoveWorktree instead
+var ErrWorktreeProject = errors.New("worktrees are removed from their project, not archived")
+
+// ErrHasWorktrees is returned when archiving or deleting a project that has worktrees
+// registered, whose .git files point into its repository; they are removed first (K)
+var ErrHasWorktrees = errors.New("project has worktrees; remove them first")
+
+// checkNoWorktrees refuses to delete the checkout of a project with registered worktrees,
+// which would break them and leave their entries linked to a project that is gone
+func checkNoWorktrees(project *models.Project) error {
+	worktrees, err := db.GetWorktreeProjects(project.ID)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, worktree := range worktrees {
+		if worktree.ID != project.ID {
+			names = append(names, worktree.Name)
+		}
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %s has %s", ErrHasWorktrees, project.Name, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// Worktree is a working tree of a git repository, as listed by git worktree list
+type Worktree struct {
+	Path     string
+	Branch   string // Checked out branch, empty when the HEAD is detached
+	Head     string // Checked out commit
+	Main     bool   // The repository's main worktree, which can't be removed
+	Locked   bool
+	Prunable bool // Its directory is gone; git forgets it on git worktree prune
+}
+
+// gitOutput runs git in dir and returns its output, or an error with what git printed
+func gitOutput(dir string, args ...string) (string, error) {
+	if err := LookupGit(); err != nil {
+		return "", err
+	}
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(output), nil
+}
+
+// ListWorktrees returns the worktrees of the repository in dir, the main one first
+func ListWorktrees(dir string) ([]Worktree, error) {
+	output, err := gitOutput(dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktreeList(output), nil
+}
+
+// parseWorktreeList parses the output of git worktree list --porcelain: one block of
+// attribute lines per worktree, separated by blank lines
+func parseWorktreeList(output string) []Worktree {
+	var worktrees []Worktree
+	for _, block := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n\n") {
+		var wt Worktree
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = filepath.Clean(filepath.FromSlash(value))
+			case "HEAD":
+				wt.Head = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare":
+				wt.Main = true
+			case "locked":
+				wt.Locked = true
+			case "prunable":
+				wt.Prunable = true
+			}
+		}
+		if wt.Path == "" {
+			continue
+		}
+		// git lists the main worktree first
+		wt.Main = wt.Main || len(worktrees) == 0
+		worktrees = append(worktrees, wt)
+	}
+	return worktrees
+}
+
+// WorktreePath returns where AddWorktree puts a branch of a project: next to the project,
+// named after it and the branch (e.g. "api-feature-login" for feature/login), so scans of
+// the root folder find it too
+func WorktreePath(parent models.Project, branch string) string {
+	return filepath.Join(filepath.Dir(parent.Path), filepath.Base(parent.Path)+"-"+strings.ReplaceAll(branch, "/", "-"))
+}
+
+// AddWorktree checks out a branch of a project in a new worktree at WorktreePath and
+// registers it as a project linked to the parent. A branch that doesn't exist yet, locally
+// or on origin, is created from the parent's HEAD.
+func AddWorktree(parent models.Project, branch string) (*models.Project, error) {
+	if branch == "" {
+		return nil, fmt.Errorf("branch name is required")
+	}
+	if err := ValidateRef(branch); err != nil {
+		return nil, err
+	}
+	path := WorktreePath(parent, branch)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("worktree %w: %s", ErrPathExists, path)
+	}
+
+	args := []string{"worktree", "add", path, branch}
+	if !branchExists(parent.Path, branch) {
+		args = []string{"worktree", "add", "-b", branch, path}
+	}
+	if _, err := gitOutput(parent.Path, args...); err != nil {
+		return nil, err
+	}
+
+	project, err := LinkWorktree(parent, path)
+	if err != nil {
+		// Leave the repository as it was
+		_, _ = gitOutput(parent.Path, "worktree", "remove", "--force", path)
+		return nil, err
+	}
+	return project, nil
+}
+
+// branchExists reports whether a branch exists locally or on origin, where git worktree add
+// creates a tracking branch for it
+func branchExists(dir, branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		if _, err := gitOutput(dir, "rev-parse", "--verify", "--quiet", ref); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkWorktree registers an existing worktree directory as a project linked to the parent.
+// A project already registered at the path, e.g. found by a scan, is linked instead.
+func LinkWorktree(parent models.Project, path string) (*models.Project, error) {
+	project, err := db.GetProjectByPath(path)
+	if err == nil {
+		project.ParentID = parent.ID
+		if err := db.UpdateProject(project); err != nil {
+			return nil, err
+		}
+		return project, nil
+	} else if !errors.Is(err, db.ErrProjectNotFound) {
+		return nil, err
+	}
+
+	project = &models.Project{
+		Name:         filepath.Base(path),
+		Path:         path,
+		RepoURL:      parent.RepoURL,
+		Status:       "active",
+		Language:     DetectLanguage(path),
+		DevContainer: HasDevContainer(path),
+		ParentID:     parent.ID,
+		RootFolderID: parent.RootFolderID,
+		LastOpened:   time.Now(),
+	}
+	if err := db.AddProject(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// RemoveWorktree deletes the worktree of a linked project with git worktree remove and
+// removes its entry. git refuses worktrees with uncommitted changes, which are kept.
+func RemoveWorktree(parent, project models.Project) error {
+	if project.ParentID != parent.ID {
+		return fmt.Errorf("%s is not a worktree of %s", project.Name, parent.Name)
+	}
+	if project.Locked {
+		return fmt.Errorf("%w: %s", db.ErrProjectLocked, project.Name)
+	}
+	if _, err := os.Stat(project.Path); err == nil {
+		if _, err := gitOutput(parent.Path, "worktree", "remove", project.Path); err != nil {
+			return err
+		}
+	} else if _, err := gitOutput(parent.Path, "worktree", "prune"); err != nil {
+		return err
+	}
+	InvalidateGitInfo(parent.Path)
+	return db.DeleteProject(project.ID)
+}
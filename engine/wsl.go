@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// WSLPath is a directory inside a WSL distribution, as seen from Windows
+type WSLPath struct {
+	Distro string // Distribution name, e.g. "Ubuntu"
+	Path   string // Linux path inside the distribution, e.g. "/home/me/app"
+}
+
+// wslShares are the UNC hosts Windows exposes WSL distributions under
+var wslShares = []string{`\\wsl$\`, `\\wsl.localhost\`}
+
+// InsideWSL reports whether DevBase runs inside a WSL distribution
+func InsideWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
+
+// ParseWSLPath recognizes \\wsl$\<distro>\... and \\wsl.localhost\<distro>\... paths,
+// with either slash direction
+func ParseWSLPath(p string) (WSLPath, bool) {
+	p = strings.ReplaceAll(p, "/", `\`)
+	for _, share := range wslShares {
+		if len(p) < len(share) || !strings.EqualFold(p[:len(share)], share) {
+			continue
+		}
+		distro, rest, _ := strings.Cut(p[len(share):], `\`)
+		if distro == "" {
+			return WSLPath{}, false
+		}
+		return WSLPath{Distro: distro, Path: path.Clean("/" + strings.ReplaceAll(rest, `\`, "/"))}, true
+	}
+	return WSLPath{}, false
+}
+
+// UNC returns the Windows path of the directory
+func (w WSLPath) UNC() string {
+	return `\\wsl.localhost\` + w.Distro + strings.ReplaceAll(w.Path, "/", `\`)
+}
+
+// HostPath translates a project path for the environment DevBase runs in. Inside WSL,
+// Windows drive paths become /mnt/<drive>/... and UNC paths into the current distribution
+// become Linux paths. On Windows, Linux paths registered from inside WSL become UNC paths
+// into distro when one is given. Other paths are returned unchanged.
+func HostPath(p, distro string) string {
+	switch {
+	case InsideWSL():
+		if w, ok := ParseWSLPath(p); ok && strings.EqualFold(w.Distro, os.Getenv("WSL_DISTRO_NAME")) {
+			return w.Path
+		}
+		if len(p) >= 3 && p[1] == ':' && (p[2] == '\\' || p[2] == '/') {
+			drive := strings.ToLower(p[:1])
+			return path.Join("/mnt", drive, strings.ReplaceAll(p[3:], `\`, "/"))
+		}
+	case runtime.GOOS == "windows":
+		if distro != "" && strings.HasPrefix(p, "/") {
+			return WSLPath{Distro: distro, Path: path.Clean(p)}.UNC()
+		}
+	}
+	return p
+}
+
+// WSLEditorCommand builds the command that opens a WSL project from Windows. Editors with
+// Remote-WSL support (VS Code, Cursor, Windsurf) open it inside the distribution, terminal
+// editors run there through wsl.exe and other editors get the UNC path.
+func WSLEditorCommand(editor Editor, w WSLPath) (*exec.Cmd, error) {
+	switch {
+	case editor.Command == "":
+		return nil, fmt.Errorf("no editor command configured")
+	case editor.Workspace:
+		return exec.Command(editor.Command, "--remote", "wsl+"+w.Distro, w.Path), nil
+	case editor.Terminal:
+		return exec.Command("wsl.exe", "-d", w.Distro, "--cd", w.Path, "--", editor.Command, "."), nil
+	case editor.URL:
+		return OpenURLCommand(EditorURL(editor.Command, w.UNC())), nil
+	}
+	return exec.Command(editor.Command, w.UNC()), nil
+}
+
+// WSLShellCommand wraps a shell command so a Windows terminal runs it inside the distribution,
+// in the project directory and through a login shell so the user's PATH applies
+func WSLShellCommand(w WSLPath, command string) string {
+	return fmt.Sprintf(`wsl.exe -d %s --cd "%s" -- bash -lc "%s"`, w.Distro, w.Path, strings.ReplaceAll(command, `"`, `\"`))
+}
+
+// wslDistroReachable reports whether the distribution's file share answers. The share is
+// missing while WSL is shut down, which must not make every project look deleted.
+func wslDistroReachable(distro string) bool {
+	_, err := os.Stat(WSLPath{Distro: distro, Path: "/"}.UNC())
+	return err == nil
+}
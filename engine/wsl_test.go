@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"runtime"
+	"testing"
+)
+
+// TestParseWSLPath tests recognizing the UNC paths of WSL distributions, translating them to
+// Linux paths and rejecting other paths
+func TestParseWSLPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want WSLPath
+		ok   bool
+	}{
+		{`\\wsl$\Ubuntu\home\me\app`, WSLPath{Distro: "Ubuntu", Path: "/home/me/app"}, true},
+		{`\\wsl.localhost\Ubuntu\home\me\app`, WSLPath{Distro: "Ubuntu", Path: "/home/me/app"}, true},
+		{`//wsl.localhost/Debian/srv/api/`, WSLPath{Distro: "Debian", Path: "/srv/api"}, true},
+		{`\\WSL$\Ubuntu-22.04\home\me\..\you\.\site`, WSLPath{Distro: "Ubuntu-22.04", Path: "/home/you/site"}, true},
+		{`\\wsl.localhost\Ubuntu`, WSLPath{Distro: "Ubuntu", Path: "/"}, true},
+		{`\\wsl$\`, WSLPath{}, false},
+		{`\\wsl.localhost\\home\me`, WSLPath{}, false},
+		{`\\server\share\app`, WSLPath{}, false},
+		{`\\wsl`, WSLPath{}, false},
+		{`C:\code\app`, WSLPath{}, false},
+		{`/home/me/app`, WSLPath{}, false},
+		{``, WSLPath{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseWSLPath(tt.path)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseWSLPath(%q) = %+v, %v; want %+v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if unc := (WSLPath{Distro: "Ubuntu", Path: "/home/me/app"}).UNC(); unc != `\\wsl.localhost\Ubuntu\home\me\app` {
+		t.Errorf("UNC() = %q", unc)
+	}
+}
+
+// TestHostPath tests translating Windows paths for a DevBase running inside WSL
+func TestHostPath(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("translating for WSL happens on Linux")
+	}
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+
+	tests := map[string]string{
+		`\\wsl.localhost\Ubuntu\home\me\app`: "/home/me/app",
+		`\\wsl$\ubuntu\home\me\app`:          "/home/me/app",
+		`\\wsl$\Debian\home\me\app`:          `\\wsl$\Debian\home\me\app`,
+		`C:\Users\me\code\app`:               "/mnt/c/Users/me/code/app",
+		`D:/work/api`:                        "/mnt/d/work/api",
+		"/home/me/app":                       "/home/me/app",
+	}
+	for path, want := range tests {
+		if got := HostPath(path, ""); got != want {
+			t.Errorf("HostPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// ErrNoWindowsTerminal is returned when Windows Terminal fragments can't be written on this system
+var ErrNoWindowsTerminal = errors.New("Windows Terminal profiles are only generated on Windows")
+
+// wtProfile is a profile in a Windows Terminal JSON fragment
+type wtProfile struct {
+	Name              string `json:"name"`
+	Commandline       string `json:"commandline"`
+	StartingDirectory string `json:"startingDirectory,omitempty"`
+	TabTitle          string `json:"tabTitle"`
+	Icon              string `json:"icon"`
+}
+
+// WTFragmentPath returns the fragment file Windows Terminal loads DevBase's profiles from
+func WTFragmentPath() (string, error) {
+	base := os.Getenv("LOCALAPPDATA")
+	if runtime.GOOS != "windows" || base == "" {
+		return "", ErrNoWindowsTerminal
+	}
+	return filepath.Join(base, "Microsoft", "Windows Terminal", "Fragments", "DevBase", "projects.json"), nil
+}
+
+// wtProfiles builds one profile per project. Profiles start a PowerShell in the project
+// directory (a login shell inside the distribution for WSL projects) and run the project's
+// start command first when it has one. Remote projects are left out.
+func wtProfiles(projects []models.Project, distro string) []wtProfile {
+	shell := "powershell.exe"
+	if _, err := exec.LookPath("pwsh"); err == nil {
+		shell = "pwsh.exe"
+	}
+
+	names := make(map[string]int)
+	for _, p := range projects {
+		names[p.Name]++
+	}
+
+	profiles := []wtProfile{}
+	for _, p := range projects {
+		if p.RemoteHostID != 0 {
+			continue
+		}
+		// Windows Terminal derives profile GUIDs from their names, which must be unique
+		name := p.Name
+		if names[p.Name] > 1 {
+			name = fmt.Sprintf("%s (%s)", p.Name, p.Path)
+		}
+		profile := wtProfile{Name: name, TabTitle: p.Name, Icon: "📁"}
+
+		dir := HostPath(p.Path, distro)
+		if w, ok := ParseWSLPath(dir); ok {
+			profile.Commandline = fmt.Sprintf(`wsl.exe -d %s --cd "%s"`, w.Distro, w.Path)
+			if p.StartCommand != "" {
+				profile.Commandline = WSLShellCommand(w, p.StartCommand+`; exec "$SHELL" -l`)
+			}
+		} else {
+			profile.StartingDirectory = dir
+			profile.Commandline = shell + " -NoLogo"
+			if p.StartCommand != "" {
+				profile.Commandline = fmt.Sprintf(`%s -NoLogo -NoExit -Command "%s"`, shell, strings.ReplaceAll(p.StartCommand, `"`, `\"`))
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// SyncWTFragment regenerates the fragment from the pinned projects, writing only when it
+// changed and removing it when nothing is pinned. It returns the fragment path and the
+// number of profiles.
+func SyncWTFragment() (string, int, error) {
+	path, err := WTFragmentPath()
+	if err != nil {
+		return "", 0, err
+	}
+	projects, err := db.GetPinnedProjects()
+	if err != nil {
+		return "", 0, err
+	}
+
+	if len(projects) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return "", 0, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return path, 0, nil
+	}
+
+	distro, _ := db.GetConfig("wsl_distro")
+	profiles := wtProfiles(projects, distro)
+	data, err := json.MarshalIndent(map[string]any{"profiles": profiles}, "", "  ")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to build fragment: %w", err)
+	}
+
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		return path, len(profiles), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, len(profiles), nil
+}
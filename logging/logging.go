@@ -0,0 +1,215 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxFileSize = 5 << 20 // Size at which the log file is rotated
+	keepFiles   = 3       // Rotated files kept next to the current one
+	keepEntries = 200     // Warnings and errors kept in memory for the log viewer
+)
+
+// Options configures Init
+type Options struct {
+	Dir     string // Directory of the log files
+	Verbose bool   // Log debug records; otherwise the level comes from SetLevel (info by default)
+	Stderr  bool   // Also write records to stderr, for daemons
+}
+
+// Entry is a warning or error kept for the in-app log viewer
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   string // Attributes in key=value form
+}
+
+var (
+	level   = new(slog.LevelVar)
+	verbose bool
+	logDir  string
+
+	recentMu sync.Mutex
+	recent   []Entry
+)
+
+// Dir returns the directory log files are written to, as passed to Init
+func Dir() (string, error) {
+	if logDir == "" {
+		return "", fmt.Errorf("no log directory")
+	}
+	return logDir, nil
+}
+
+// Path returns the current log file
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "devbase.log"), nil
+}
+
+// Init makes slog, and the standard log package through it, write to the rotated log file.
+// When the file can't be opened records are only kept for the log viewer (and stderr), and
+// the error is returned.
+func Init(opts Options) error {
+	verbose = opts.Verbose
+	logDir = opts.Dir
+	if verbose {
+		level.Set(slog.LevelDebug)
+	}
+
+	var writers []io.Writer
+	path, err := Path()
+	if err == nil {
+		var file *rotatingFile
+		if file, err = openRotatingFile(path); err == nil {
+			writers = append(writers, file)
+		}
+	}
+	if opts.Stderr {
+		writers = append(writers, os.Stderr)
+	}
+
+	text := slog.NewTextHandler(io.MultiWriter(writers...), &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(&recordingHandler{next: text}))
+	return err
+}
+
+// SetLevel sets the verbosity from a config value: debug, info, warn or error. --verbose wins.
+func SetLevel(name string) error {
+	if verbose || name == "" {
+		return nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", name)
+	}
+	level.Set(l)
+	return nil
+}
+
+// Recent returns the warnings and errors logged in this process, newest first
+func Recent() []Entry {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	entries := slices.Clone(recent)
+	slices.Reverse(entries)
+	return entries
+}
+
+// recordingHandler keeps warnings and errors in memory before passing records on
+type recordingHandler struct {
+	next  slog.Handler
+	attrs []slog.Attr
+}
+
+func (h *recordingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return l >= slog.LevelWarn || h.next.Enabled(ctx, l)
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		var attrs []string
+		add := func(a slog.Attr) bool {
+			attrs = append(attrs, a.String())
+			return true
+		}
+		for _, a := range h.attrs {
+			add(a)
+		}
+		r.Attrs(add)
+
+		recentMu.Lock()
+		recent = append(recent, Entry{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: strings.Join(attrs, " ")})
+		if len(recent) > keepEntries {
+			recent = slices.Delete(recent, 0, len(recent)-keepEntries)
+		}
+		recentMu.Unlock()
+	}
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordingHandler{next: h.next.WithAttrs(attrs), attrs: append(slices.Clip(h.attrs), attrs...)}
+}
+
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	return &recordingHandler{next: h.next.WithGroup(name), attrs: h.attrs}
+}
+
+// rotatingFile is a log file that is renamed to .1 (shifting older ones up to .3) once it
+// reaches maxFileSize
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+// openRotatingFile opens the log file for appending, creating its directory
+func openRotatingFile(path string) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	r := &rotatingFile{path: path}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.size+int64(len(p)) > maxFileSize {
+		r.rotate()
+	}
+	if r.file == nil {
+		return len(p), nil
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated files up by one and starts a new file. Records are dropped
+// when the new file can't be opened rather than failing the caller.
+func (r *rotatingFile) rotate() {
+	r.file.Close()
+	r.file = nil
+	os.Remove(fmt.Sprintf("%s.%d", r.path, keepFiles))
+	for i := keepFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	os.Rename(r.path, r.path+".1")
+	_ = r.open()
+}
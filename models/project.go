@@ -15,28 +15,138 @@ type Config struct {
 
 // RootFolder represents a root directory path where projects are stored
 type RootFolder struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"not null" json:"name"`                    // User-friendly name for this root folder
-	Path      string         `gorm:"not null;unique" json:"path"`             // Absolute path to the root folder
-	IsActive  bool           `gorm:"not null;default:false" json:"is_active"` // Currently active root folder
-	GistID    string         `json:"gist_id"`                                 // Gist ID for this root folder's cloud backup
-	CreatedAt time.Time      `gorm:"type:datetime" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"type:datetime" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	Projects  []Project      `gorm:"foreignKey:RootFolderID" json:"projects,omitempty"` // Projects in this root folder
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Name          string         `gorm:"not null" json:"name"`                    // User-friendly name for this root folder
+	Path          string         `gorm:"not null;unique" json:"path"`             // Absolute path to the root folder
+	IsActive      bool           `gorm:"not null;default:false" json:"is_active"` // Currently active root folder
+	GistID        string         `json:"gist_id"`                                 // Gist ID for this root folder's cloud backup
+	NeverRegister []string       `gorm:"serializer:json" json:"never_register"`   // Directories (relative to Path) and repository URLs scans skip
+	CreatedAt     time.Time      `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"type:datetime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Projects      []Project      `gorm:"foreignKey:RootFolderID" json:"projects,omitempty"` // Projects in this root folder
 }
 
 // Project represents a development project in the database
 type Project struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
 	Name         string         `gorm:"not null" json:"name"`
-	Path         string         `gorm:"not null;uniqueIndex:idx_root_path" json:"path"` // Composite unique with RootFolderID
+	Path         string         `gorm:"not null;uniqueIndex:idx_root_path" json:"path"` // Composite unique with RootFolderID and RemoteHostID
 	RepoURL      string         `json:"repo_url"`
+	Description  string         `json:"description"`                           // Repository description, for projects cloned from GitHub
 	Status       string         `gorm:"not null;default:active" json:"status"` // "active" or "archived"
 	LastOpened   time.Time      `gorm:"not null;type:datetime" json:"last_opened"`
+	OpenCount    int            `gorm:"not null;default:0" json:"open_count"` // Times opened from DevBase, seeded by frecency imports
 	Tags         []string       `gorm:"serializer:json" json:"tags"`
+	Language     string         `json:"language"`                                                        // Primary language detected from marker files (e.g. "go", "typescript")
+	DevContainer bool           `json:"dev_container"`                                                   // Has .devcontainer/devcontainer.json
+	Notes        string         `json:"notes"`                                                           // Free-form Markdown notes
+	Editor       string         `json:"editor"`                                                          // Preferred editor command, empty uses the default editor
+	Pinned       bool           `json:"pinned"`                                                          // Has a Windows Terminal profile
+	StartCommand string         `json:"start_command"`                                                   // Run when the project's terminal profile opens
+	NoReclaim    bool           `json:"no_reclaim"`                                                      // Dependency folders are left alone by the reclaimable-space analyzer
+	Locked       bool           `json:"locked"`                                                          // Can't be archived, removed or cleared (see db.ErrProjectLocked)
+	Icon         string         `json:"icon"`                                                            // Emoji or short label shown before the name, empty for none
+	RestoreRef   string         `json:"restore_ref"`                                                     // Branch or tag checked out on restore, empty for the default branch
+	CloneOptions string         `json:"clone_options"`                                                   // git clone options of restores (see engine.ParseCloneOptions), empty for the global ones
+	LastError    string         `json:"last_error"`                                                      // Why the last archive or restore failed, empty once one succeeds
+	LastErrorAt  time.Time      `gorm:"type:datetime" json:"last_error_at"`                              // When LastError happened
+	Machine      string         `json:"machine"`                                                         // Machine that registered the project (see db.MachineName), kept through cloud sync
+	ParentID     uint           `gorm:"default:0;index" json:"parent_id"`                                // Project this one is a git worktree of, 0 for standalone projects
 	RootFolderID uint           `gorm:"default:0;index;uniqueIndex:idx_root_path" json:"root_folder_id"` // Foreign key to RootFolder, composite unique with Path
+	RemoteHostID uint           `gorm:"default:0;index;uniqueIndex:idx_root_path" json:"remote_host_id"` // Foreign key to RemoteHost, 0 for local projects
 	CreatedAt    time.Time      `gorm:"type:datetime" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"type:datetime" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+// DevRun is a command DevBase started for a project as a child process, kept while it runs
+// so the project shows as running and the command can be stopped or restarted
+type DevRun struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProjectID uint      `gorm:"not null;index" json:"project_id"`
+	PID       int       `gorm:"not null" json:"pid"` // Leader of the run's process group
+	Task      string    `json:"task"`                // Command as chosen in the run picker
+	EnvSource string    `json:"env_source"`          // Environment loaded for the run, loaded again on restart
+	Streamed  bool      `json:"streamed"`            // Output streams into the output pane, so the run ends with DevBase
+	Port      int       `json:"port"`                // Listening port found in the output, 0 until one is
+	LogPath   string    `json:"log_path"`            // Run log holding the output
+	StartedAt time.Time `gorm:"type:datetime" json:"started_at"`
+}
+
+// TrashEntry is the checkout of an archived project, kept in the trash directory until the
+// archive retention policy purges it or the project is restored from it
+type TrashEntry struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProjectID    uint      `gorm:"not null;index" json:"project_id"`
+	Name         string    `json:"name"`                            // Project name when it was archived
+	OriginalPath string    `json:"original_path"`                   // Where the checkout was
+	Path         string    `gorm:"not null" json:"path"`            // The checkout in the trash directory
+	Size         int64     `json:"size"`                            // Bytes of the checkout
+	CreatedAt    time.Time `gorm:"type:datetime" json:"created_at"` // When the project was archived
+}
+
+// RemoteHost is a machine reached over SSH that holds remote projects
+type RemoteHost struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"not null;unique" json:"name"` // Short name used in the UI and CLI
+	Destination string    `gorm:"not null" json:"destination"` // ssh destination: "user@host" or a Host alias from ~/.ssh/config
+	CreatedAt   time.Time `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:datetime" json:"updated_at"`
+}
+
+// Session represents a named set of projects that are opened together
+type Session struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Name       string    `gorm:"not null;unique" json:"name"`
+	ProjectIDs []uint    `gorm:"serializer:json" json:"project_ids"`
+	CreatedAt  time.Time `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"type:datetime" json:"updated_at"`
+}
+
+// Activity kinds recorded in the history
+const (
+	ActivityOpen    = "open"
+	ActivityArchive = "archive"
+	ActivityRestore = "restore"
+	ActivityScan    = "scan"
+	ActivitySync    = "sync"
+)
+
+// Activity is an entry in the history of actions taken in DevBase
+type Activity struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Kind        string    `gorm:"not null;index" json:"kind"` // One of the Activity* kinds
+	ProjectID   uint      `gorm:"index" json:"project_id"`    // 0 for events not tied to a project (scans, syncs)
+	ProjectName string    `json:"project_name"`               // Kept so entries stay readable after the project is removed
+	Detail      string    `json:"detail"`
+	CreatedAt   time.Time `gorm:"type:datetime;index" json:"created_at"`
+}
+
+// RepoMetadata caches details of a hosted repository fetched from its provider's API
+type RepoMetadata struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Repo        string    `gorm:"not null;unique" json:"repo"` // Host and path of the repository, e.g. "github.com/owner/name"
+	Description string    `json:"description"`
+	Stars       int       `json:"stars"`
+	OpenIssues  int       `json:"open_issues"` // Open issues, not counting pull requests
+	OpenPRs     int       `json:"open_prs"`
+	FetchedAt   time.Time `gorm:"type:datetime" json:"fetched_at"` // When the details were fetched, for the cache TTL
+}
+
+// Webhook payload formats
+const (
+	WebhookJSON    = "json"    // The event as a JSON object
+	WebhookSlack   = "slack"   // Slack incoming webhook message
+	WebhookDiscord = "discord" // Discord webhook message
+)
+
+// Webhook is a URL that lifecycle events are POSTed to
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	URL       string    `gorm:"not null" json:"url"`
+	Format    string    `gorm:"not null;default:json" json:"format"` // One of the Webhook* formats
+	Events    []string  `gorm:"serializer:json" json:"events"`       // Event kinds delivered, empty for all
+	CreatedAt time.Time `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:datetime" json:"updated_at"`
+}
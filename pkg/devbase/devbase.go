@@ -0,0 +1,128 @@
+// Package devbase is the Go API of DevBase for tools that embed it, such as editor
+// extensions and chat bots. It works on the same database as the devbase command, so
+// projects added, scanned or archived through it show up in the TUI and the other way round.
+//
+//	client, err := devbase.Open(devbase.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	projects, err := client.Projects.Filter("lang:go status:active")
+//
+// The database connection is shared by the whole process, so only one Client can be open
+// at a time. Its methods may be called from several goroutines.
+package devbase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// Types shared with the rest of DevBase
+type (
+	Project       = models.Project
+	RootFolder    = models.RootFolder
+	ScanResult    = engine.ScanResult
+	SyncDiff      = engine.SyncDiff
+	ProjectChange = engine.ProjectChange
+	CloudBackup   = engine.CloudBackup
+)
+
+// Errors returned by the services, wrapped with details. Check them with errors.Is.
+var (
+	ErrProjectNotFound    = db.ErrProjectNotFound
+	ErrRootFolderNotFound = db.ErrRootFolderNotFound
+	ErrProjectLocked      = db.ErrProjectLocked
+	ErrAlreadyArchived    = engine.ErrAlreadyArchived
+	ErrAlreadyActive      = engine.ErrAlreadyActive
+	ErrNoRepoURL          = engine.ErrNoRepoURL
+	ErrPathExists         = engine.ErrPathExists
+	ErrRemoteProject      = engine.ErrRemoteProject
+	ErrWorktreeProject    = engine.ErrWorktreeProject
+	ErrHasWorktrees       = engine.ErrHasWorktrees
+	ErrNoCloudBackup      = engine.ErrNoCloudBackup
+	ErrBackupIntegrity    = engine.ErrBackupIntegrity
+	ErrGitHubRateLimited  = engine.ErrGitHubRateLimited
+	ErrSecretsFound       = engine.ErrSecretsFound
+
+	// ErrAlreadyOpen is returned by Open while another Client is open
+	ErrAlreadyOpen = errors.New("a devbase client is already open in this process")
+	// ErrNoGitHubToken is returned by SyncService without a GitHub token
+	ErrNoGitHubToken = errors.New("syncing needs a GitHub token; authenticate in the TUI or set Options.GitHubToken")
+)
+
+// Options configures Open
+type Options struct {
+	// DBPath is the database file. Empty uses the database of the devbase command.
+	DBPath string
+	// GitHubToken is used by SyncService. Empty uses the token stored by the TUI.
+	GitHubToken string
+}
+
+// Client gives access to DevBase through its services
+type Client struct {
+	Projects *ProjectService
+	Scans    *ScanService
+	Sync     *SyncService
+}
+
+// open guards the process-wide database connection
+var open struct {
+	sync.Mutex
+	client *Client
+}
+
+// Open opens the DevBase database, creating it when it doesn't exist, and returns a Client
+// for it. config.toml is read like the devbase command does. Close the client when done.
+func Open(opts Options) (*Client, error) {
+	open.Lock()
+	defer open.Unlock()
+	if open.client != nil {
+		return nil, ErrAlreadyOpen
+	}
+
+	path := opts.DBPath
+	if path == "" {
+		var err error
+		if path, err = db.ResolveDBPath(); err != nil {
+			return nil, fmt.Errorf("failed to locate database: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+	if err := db.InitDB(path); err != nil {
+		return nil, err
+	}
+	if err := db.LoadConfigFile(); err != nil {
+		db.CloseDB()
+		return nil, err
+	}
+
+	client := &Client{
+		Projects: &ProjectService{},
+		Scans:    &ScanService{},
+		Sync:     &SyncService{token: opts.GitHubToken},
+	}
+	open.client = client
+	return client, nil
+}
+
+// Close closes the database. The client can't be used afterwards.
+func (c *Client) Close() error {
+	open.Lock()
+	defer open.Unlock()
+	if open.client != c {
+		return nil
+	}
+	open.client = nil
+	return db.CloseDB()
+}
@@ -0,0 +1,129 @@
+package devbase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openTestClient opens a client on a database in a temporary directory
+func openTestClient(t *testing.T) *Client {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	t.Setenv("AppData", filepath.Join(tempDir, "config"))
+
+	client, err := Open(Options{DBPath: filepath.Join(tempDir, "devbase.db")})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+	})
+	return client
+}
+
+// TestOpen tests that only one client can be open at a time
+func TestOpen(t *testing.T) {
+	client := openTestClient(t)
+	if _, err := Open(Options{DBPath: filepath.Join(t.TempDir(), "other.db")}); !errors.Is(err, ErrAlreadyOpen) {
+		t.Errorf("Expected ErrAlreadyOpen, got %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Closing makes room for the next client
+	again, err := Open(Options{DBPath: filepath.Join(t.TempDir(), "other.db")})
+	if err != nil {
+		t.Fatalf("Open after Close failed: %v", err)
+	}
+	again.Close()
+}
+
+// TestScanAndProjects tests scanning a root folder and working with the projects found
+func TestScanAndProjects(t *testing.T) {
+	client := openTestClient(t)
+	root := t.TempDir()
+	for _, name := range []string{"api", "worker"} {
+		if err := os.MkdirAll(filepath.Join(root, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name, "go.mod"), []byte("module "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	folder, err := client.Scans.AddRootFolder(root)
+	if err != nil {
+		t.Fatalf("AddRootFolder failed: %v", err)
+	}
+	if !folder.IsActive {
+		t.Error("Expected the first root folder to be active")
+	}
+	if again, _ := client.Scans.AddRootFolder(root); again == nil || again.ID != folder.ID {
+		t.Errorf("Expected the existing root folder for the same path, got %v", again)
+	}
+
+	results, err := client.Scans.ScanAll(context.Background())
+	if err != nil {
+		t.Fatalf("ScanAll failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Added != 2 {
+		t.Fatalf("Expected one scan adding 2 projects, got %v", results)
+	}
+
+	projects, err := client.Projects.List("active")
+	if err != nil || len(projects) != 2 {
+		t.Fatalf("Expected 2 active projects, got %d, %v", len(projects), err)
+	}
+	api, err := client.Projects.GetByPath(filepath.Join(root, "api"))
+	if err != nil {
+		t.Fatalf("GetByPath failed: %v", err)
+	}
+	if err := client.Projects.AddTag(api.ID, "Backend"); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+	if matched, _ := client.Projects.Filter("tag:backend lang:go"); len(matched) != 1 || matched[0].ID != api.ID {
+		t.Errorf("Expected the filter to match api, got %v", matched)
+	}
+
+	if err := client.Projects.Restore(api.ID); !errors.Is(err, ErrAlreadyActive) {
+		t.Errorf("Expected ErrAlreadyActive, got %v", err)
+	}
+	if _, err := client.Projects.Get(9999); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound, got %v", err)
+	}
+}
+
+// TestSyncService tests the parts of syncing that don't reach GitHub
+func TestSyncService(t *testing.T) {
+	client := openTestClient(t)
+	folder, err := client.Scans.AddRootFolder(t.TempDir())
+	if err != nil {
+		t.Fatalf("AddRootFolder failed: %v", err)
+	}
+
+	if err := client.Sync.Push(folder.ID); !errors.Is(err, ErrNoGitHubToken) {
+		t.Errorf("Expected ErrNoGitHubToken, got %v", err)
+	}
+
+	stored, err := client.Sync.Pull(folder.ID, []Project{
+		{Name: "site", Path: filepath.Join(folder.Path, "site"), RepoURL: "https://github.com/example/site.git", Status: "active"},
+	})
+	if err != nil || stored != 1 {
+		t.Fatalf("Expected 1 pulled project, got %d, %v", stored, err)
+	}
+	if archived, _ := client.Projects.List("archived"); len(archived) != 1 || archived[0].Name != "site" {
+		t.Errorf("Expected the pulled project to be archived, got %v", archived)
+	}
+	if _, err := client.Sync.Pull(9999, nil); !errors.Is(err, ErrRootFolderNotFound) {
+		t.Errorf("Expected ErrRootFolderNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package devbase
+
+import (
+	"devbase/db"
+	"devbase/engine"
+)
+
+// ProjectService reads and changes stored projects. Like the TUI, lists only contain the
+// projects of the active root folder when one is set.
+type ProjectService struct{}
+
+// List returns the projects with a status ("active" or "archived", empty for all), most
+// recently opened first
+func (s *ProjectService) List(status string) ([]Project, error) {
+	return db.GetProjectsByStatus(status)
+}
+
+// Filter returns the projects matching a query in the syntax of the TUI's filter, such as
+// "tag:work lang:go status:active api"
+func (s *ProjectService) Filter(query string) ([]Project, error) {
+	return db.FilterProjects(db.ParseProjectFilter(query))
+}
+
+// Get returns the project with an ID
+func (s *ProjectService) Get(id uint) (*Project, error) {
+	return db.GetProjectByID(id)
+}
+
+// GetByPath returns the project in a directory
+func (s *ProjectService) GetByPath(path string) (*Project, error) {
+	return db.GetProjectByPath(path)
+}
+
+// Archive deletes the directory of a project and marks it as archived. Its repository URL
+// is kept, so Restore can clone it again.
+func (s *ProjectService) Archive(id uint) error {
+	return engine.ArchiveProject(id)
+}
+
+// SetLocked locks or unlocks a project. Archive and deleting a locked project fail with
+// ErrProjectLocked.
+func (s *ProjectService) SetLocked(id uint, locked bool) error {
+	return db.SetProjectLocked(id, locked)
+}
+
+// SetIcon sets the emoji or short label shown before a project's name; an empty icon removes
+// it. Icons wider than engine.MaxIconWidth cells are refused.
+func (s *ProjectService) SetIcon(id uint, icon string) error {
+	return engine.SetProjectIcon(id, icon)
+}
+
+// Restore clones an archived project from its repository URL and marks it as active
+func (s *ProjectService) Restore(id uint) error {
+	return engine.RestoreProject(id)
+}
+
+// RestoreTo restores an archived project into another directory, moving its record to that
+// path and the root folder containing it
+func (s *ProjectService) RestoreTo(id uint, path string) error {
+	return engine.RestoreProjectTo(id, path)
+}
+
+// RecordOpen counts an open of a project for frecency sorting, ignoring repeated opens
+// within db.OpenDebounce, and sends an open event to the plugins. It reports whether the
+// open was counted.
+func (s *ProjectService) RecordOpen(id uint) (bool, error) {
+	project, err := db.GetProjectByID(id)
+	if err != nil {
+		return false, err
+	}
+	return engine.RecordOpen(*project)
+}
+
+// SetNotes replaces the Markdown notes of a project
+func (s *ProjectService) SetNotes(id uint, notes string) error {
+	return db.UpdateProjectNotes(id, notes)
+}
+
+// AddTag tags a project. Tags are normalized to lowercase.
+func (s *ProjectService) AddTag(id uint, tag string) error {
+	return db.AddProjectTag(id, tag)
+}
+
+// RemoveTag removes a tag from a project
+func (s *ProjectService) RemoveTag(id uint, tag string) error {
+	return db.RemoveProjectTag(id, db.NormalizeTag(tag))
+}
@@ -0,0 +1,73 @@
+package devbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// ScanService manages root folders and scans them for projects
+type ScanService struct{}
+
+// RootFolders returns every root folder
+func (s *ScanService) RootFolders() ([]RootFolder, error) {
+	return db.GetAllRootFolders()
+}
+
+// AddRootFolder adds a directory as a root folder, or returns the existing one for it. The
+// first root folder becomes active.
+func (s *ScanService) AddRootFolder(path string) (*RootFolder, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := db.GetRootFolderByPath(path)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, db.ErrRootFolderNotFound) {
+		return nil, err
+	}
+
+	_, err = db.GetActiveRootFolder()
+	first := errors.Is(err, db.ErrRootFolderNotFound)
+	folder := &models.RootFolder{Name: filepath.Base(path), Path: path, IsActive: first}
+	if err := db.AddRootFolder(folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// Scan scans a root folder like the TUI's scan: new projects are added, changed details
+// updated and active projects that are gone from disk removed. Cancelling ctx stops the
+// scan without storing anything.
+func (s *ScanService) Scan(ctx context.Context, rootFolderID uint) (ScanResult, error) {
+	folder, err := db.GetRootFolderByID(rootFolderID)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	return engine.ScanRootFolder(ctx, folder.ID, folder.Path)
+}
+
+// ScanAll scans every root folder and returns the result of each. It stops at the first
+// failing scan.
+func (s *ScanService) ScanAll(ctx context.Context) ([]ScanResult, error) {
+	folders, err := db.GetAllRootFolders()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]ScanResult, 0, len(folders))
+	for _, folder := range folders {
+		result, err := engine.ScanRootFolder(ctx, folder.ID, folder.Path)
+		if err != nil {
+			return results, fmt.Errorf("failed to scan %s: %w", folder.Path, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
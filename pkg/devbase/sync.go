@@ -0,0 +1,111 @@
+package devbase
+
+import (
+	"fmt"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// SyncService backs the projects of a root folder up to a private GitHub gist and loads
+// them back, like the TUI's cloud sync
+type SyncService struct {
+	token string
+}
+
+// githubToken returns the token of Options or the one stored by the TUI
+func (s *SyncService) githubToken() (string, error) {
+	if s.token != "" {
+		return s.token, nil
+	}
+	if token, _ := db.GetConfig("github_token"); token != "" {
+		return token, nil
+	}
+	return "", ErrNoGitHubToken
+}
+
+// gistClient returns a client for the gist of a root folder
+func (s *SyncService) gistClient(rootFolderID uint) (*engine.GistClient, error) {
+	token, err := s.githubToken()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.GetRootFolderByID(rootFolderID); err != nil {
+		return nil, err
+	}
+	return engine.NewGistClient(token, rootFolderID)
+}
+
+// Push uploads the projects of a root folder to its gist, creating the gist on the first push
+func (s *SyncService) Push(rootFolderID uint) error {
+	token, err := s.githubToken()
+	if err != nil {
+		return err
+	}
+	folder, err := db.GetRootFolderByID(rootFolderID)
+	if err != nil {
+		return err
+	}
+	return engine.PushRootFolder(token, *folder)
+}
+
+// CloudProjects returns the projects in the gist of a root folder, or ErrNoCloudBackup when
+// it was never pushed
+func (s *SyncService) CloudProjects(rootFolderID uint) ([]Project, error) {
+	client, err := s.gistClient(rootFolderID)
+	if err != nil {
+		return nil, err
+	}
+	return client.LoadFromGist()
+}
+
+// Backups lists the gists of the token's user that hold a DevBase backup, most recently
+// updated first
+func (s *SyncService) Backups() ([]CloudBackup, error) {
+	token, err := s.githubToken()
+	if err != nil {
+		return nil, err
+	}
+	client, err := engine.NewGistClient(token, 0)
+	if err != nil {
+		return nil, err
+	}
+	return client.FindBackups()
+}
+
+// Adopt makes a gist found by Backups the backup of a root folder, so CloudProjects and Pull
+// read it and Push updates it
+func (s *SyncService) Adopt(rootFolderID uint, gistID string) error {
+	client, err := s.gistClient(rootFolderID)
+	if err != nil {
+		return err
+	}
+	return client.AdoptBackup(gistID)
+}
+
+// Diff compares the local projects of a root folder with its gist: additions and changes are
+// what Push would upload, removals what it would drop from the backup
+func (s *SyncService) Diff(rootFolderID uint) (SyncDiff, error) {
+	cloud, err := s.CloudProjects(rootFolderID)
+	if err != nil {
+		return SyncDiff{}, err
+	}
+	local, err := db.GetProjectsByRootFolder(rootFolderID)
+	if err != nil {
+		return SyncDiff{}, err
+	}
+	return engine.DiffProjects(local, cloud), nil
+}
+
+// Pull stores projects from the gist of a root folder, typically a selection of
+// CloudProjects. They are added as archived, ready to be restored. It returns how many
+// were stored.
+func (s *SyncService) Pull(rootFolderID uint, projects []Project) (int, error) {
+	if _, err := db.GetRootFolderByID(rootFolderID); err != nil {
+		return 0, err
+	}
+	stored := engine.StoreCloudProjects(rootFolderID, projects)
+	_ = db.LogActivity(models.ActivitySync, 0, fmt.Sprintf("Loaded %d selected projects from the cloud", stored))
+	return stored, nil
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// maxActivities caps how many history entries the activity screen loads
+const maxActivities = 500
+
+// activityKindStyles maps activity kinds to their timeline label and color
+var activityKindStyles = map[string]struct {
+	label string
+	color string
+}{
+	models.ActivityOpen:    {"▶ open", "#00FFFF"},
+	models.ActivityArchive: {"▼ archive", "#FFAA00"},
+	models.ActivityRestore: {"▲ restore", "#00FF00"},
+	models.ActivityScan:    {"⟳ scan", "#AA88FF"},
+	models.ActivitySync:    {"☁ sync", "#5599FF"},
+}
+
+// openActivity shows the activity history, limited to a project when one is given
+func (m model) openActivity(project *projectItem) (tea.Model, tea.Cmd) {
+	var projectID uint
+	if project != nil {
+		projectID = project.project.ID
+	}
+	activities, err := db.GetActivities(projectID, maxActivities)
+	if err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+
+	m.activities = activities
+	m.activityProject = project
+	m.activityOffset = 0
+	m.screen = screenActivity
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// updateActivity handles updates for the activity history screen
+func (m model) updateActivity(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "H":
+		m.screen = screenList
+		m.activities = nil
+		m.activityProject = nil
+		return m, nil
+
+	case "up", "k":
+		if m.activityOffset > 0 {
+			m.activityOffset--
+		}
+
+	case "down", "j":
+		if m.activityOffset < len(m.activities)-1 {
+			m.activityOffset++
+		}
+
+	case "pgup":
+		m.activityOffset = max(0, m.activityOffset-m.activityPageSize())
+
+	case "pgdown":
+		m.activityOffset = max(0, min(len(m.activities)-1, m.activityOffset+m.activityPageSize()))
+
+	case "p", "tab":
+		// Toggle between the whole history and the selected project's history
+		if m.activityProject != nil {
+			return m.openActivity(nil)
+		}
+		if item, ok := m.list.SelectedItem().(projectItem); ok {
+			return m.openActivity(&item)
+		}
+		m.errorMessage = "No project selected"
+	}
+
+	return m, nil
+}
+
+// activityPageSize returns how many timeline rows fit on screen
+func (m model) activityPageSize() int {
+	return max(5, m.height-12)
+}
+
+// viewActivity renders the activity history timeline
+func (m model) viewActivity() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Activity History")
+
+	s := "\n" + titleBox + "\n\n"
+
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	scope := "All projects"
+	if m.activityProject != nil {
+		scope = "Project: " + m.activityProject.project.Name
+	}
+	s += lipgloss.NewStyle().Foreground(colorText).Render(scope) +
+		dimStyle.Render(fmt.Sprintf(" (%d entries)", len(m.activities))) + "\n\n"
+
+	if len(m.activities) == 0 {
+		s += dimStyle.Render("No activity recorded yet") + "\n"
+	}
+
+	now := time.Now()
+	end := min(len(m.activities), m.activityOffset+m.activityPageSize())
+	for _, activity := range m.activities[m.activityOffset:end] {
+		kind, ok := activityKindStyles[activity.Kind]
+		if !ok {
+			kind.label, kind.color = activity.Kind, "#AAAAAA"
+		}
+
+		line := dimStyle.Render(fmt.Sprintf("%-10s", relativeTime(activity.CreatedAt, now))) + " " +
+			lipgloss.NewStyle().Foreground(paletteColor(kind.color)).Render(fmt.Sprintf("%-10s", kind.label))
+		if activity.ProjectName != "" {
+			line += " " + lipgloss.NewStyle().Foreground(colorText).Bold(true).Render(activity.ProjectName)
+		}
+		if activity.Detail != "" {
+			line += dimStyle.Render("  " + activity.Detail)
+		}
+		s += line + "\n"
+	}
+
+	if end < len(m.activities) {
+		s += dimStyle.Render(fmt.Sprintf("… %d older", len(m.activities)-end)) + "\n"
+	}
+
+	s += dimStyle.Render("\n↑↓/pgup/pgdn=scroll  p=toggle selected project / all  esc=back")
+
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+
+	return docStyle.Render(s)
+}
+
+// relativeTime formats a timestamp relative to now, e.g. "5m ago" or "3d ago".
+// Timestamps older than a month are shown as a date.
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return t.Format(time.DateOnly)
+	}
+}
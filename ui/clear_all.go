@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// clearPhrase has to be typed to clear projects, like DELETE for archiving
+const clearPhrase = "CLEAR"
+
+// startClearAll asks to confirm clearing the projects of the active root folder, showing
+// what would be removed first. Without an active root folder every project is cleared.
+func (m model) startClearAll() (tea.Model, tea.Cmd) {
+	m.clearRoot = nil
+	if root, err := db.GetActiveRootFolder(); err == nil {
+		m.clearRoot = root
+	}
+	m.clearEverything = m.clearRoot == nil
+	if err := m.loadClearSummary(); err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Type " + clearPhrase + " to confirm"
+	input.Focus()
+	input.CharLimit = 10
+	input.Width = 30
+	m.clearInput = input
+	m.confirmClearAll = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// loadClearSummary counts what clearing the chosen scope removes
+func (m *model) loadClearSummary() error {
+	summary, err := db.SummarizeClear(m.clearScope())
+	if err != nil {
+		return err
+	}
+	m.clearSummary = summary
+	return nil
+}
+
+// clearScope returns the root folder whose projects are cleared, 0 for all of them
+func (m model) clearScope() uint {
+	if m.clearEverything {
+		return 0
+	}
+	return m.clearRoot.ID
+}
+
+// updateClearAll handles keys while clearing projects is being confirmed
+func (m model) updateClearAll(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.confirmClearAll = false
+		m.statusMessage = "Cancelled"
+		m.errorMessage = ""
+		return m, nil
+
+	case "tab":
+		// Switch between the active root folder and every root folder
+		if m.clearRoot == nil {
+			return m, nil
+		}
+		m.clearEverything = !m.clearEverything
+		if err := m.loadClearSummary(); err != nil {
+			m.errorMessage = err.Error()
+		}
+		return m, nil
+
+	case "enter":
+		if m.clearInput.Value() != clearPhrase {
+			m.errorMessage = fmt.Sprintf("You must type '%s' exactly to confirm", clearPhrase)
+			return m, nil
+		}
+		if len(m.clearSummary.Locked) > 0 {
+			m.errorMessage = fmt.Sprintf("Unlock %s (U) first, or press tab to change what is cleared", strings.Join(m.clearSummary.Locked, ", "))
+			return m, nil
+		}
+		m.confirmClearAll = false
+		m.errorMessage = ""
+		m.statusMessage = "Clearing projects..."
+		if m.clearEverything {
+			return m, clearAllProjectsCmd()
+		}
+		return m, clearRootFolderCmd(*m.clearRoot)
+	}
+
+	var cmd tea.Cmd
+	m.clearInput, cmd = m.clearInput.Update(msg)
+	return m, cmd
+}
+
+// clearRootFolderCmd creates a command that clears the projects of a root folder
+func clearRootFolderCmd(root models.RootFolder) tea.Cmd {
+	return func() tea.Msg {
+		count, err := db.ClearRootFolderProjects(root.ID)
+		return ClearAllMsg{count: count, rootName: root.Name, err: err}
+	}
+}
+
+// viewClearAll renders the summary of what clearing removes and the confirmation input
+func (m model) viewClearAll() string {
+	scope := tr("clear.scope_all")
+	if !m.clearEverything {
+		scope = tr("clear.scope_root", m.clearRoot.Name)
+	}
+	s := "\n\n" + lipgloss.NewStyle().
+		Foreground(colorWarning).
+		Bold(true).
+		Render(tr("clear.warning", scope)) + "\n"
+
+	summary := m.clearSummary
+	s += lipgloss.NewStyle().
+		Foreground(colorText).
+		Render(tr("clear.summary", summary.Active+summary.Archived, summary.Active, summary.Archived)) + "\n"
+	if m.clearEverything {
+		s += lipgloss.NewStyle().Foreground(colorDim).Render(tr("clear.setup_again")) + "\n"
+	}
+	if len(summary.Locked) > 0 {
+		s += lipgloss.NewStyle().
+			Foreground(colorDanger).
+			Render(tr("clear.locked", strings.Join(summary.Locked, ", "))) + "\n"
+	}
+
+	s += "\n" + tr("clear.confirm", clearPhrase) + " " + m.clearInput.View() + "\n"
+	help := tr("clear.help")
+	if m.clearRoot != nil {
+		if m.clearEverything {
+			help = tr("clear.help_root", m.clearRoot.Name)
+		} else {
+			help = tr("clear.help_all")
+		}
+	}
+	return s + lipgloss.NewStyle().Foreground(colorDanger).Render(help)
+}
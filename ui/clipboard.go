@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/engine"
+)
+
+// CloneClipboardMsg is sent when the clipboard held a repository URL as clone mode opened
+type CloneClipboardMsg struct {
+	url string
+}
+
+// cloneClipboardCmd creates a command that reads the clipboard and reports a repository URL
+// in it, normalized for cloning. Anything else in the clipboard, or no clipboard support
+// (e.g. no xclip, xsel or wl-clipboard on Linux), is ignored.
+func cloneClipboardCmd() tea.Cmd {
+	return func() tea.Msg {
+		text, err := clipboard.ReadAll()
+		if err != nil {
+			return nil
+		}
+		url, err := engine.NormalizeRepoURL(text)
+		if err != nil {
+			return nil
+		}
+		return CloneClipboardMsg{url: url}
+	}
+}
+
+// cloneClipboardRead prefills the clone input with the URL from the clipboard, unless the
+// user left clone mode or started typing meanwhile
+func (m model) cloneClipboardRead(msg CloneClipboardMsg) (tea.Model, tea.Cmd) {
+	if !m.confirmClone || m.cloneMode != "url" || m.cloneInput.Value() != "" {
+		return m, nil
+	}
+	m.cloneInput.SetValue(msg.url)
+	m.cloneInput.CursorEnd()
+	m.statusMessage = "Repository URL pasted from the clipboard; enter to clone, or edit it"
+	return m, nil
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// Fields of the clone prompt, in tab order
+const (
+	cloneFieldURL = iota
+	cloneFieldName
+	cloneFieldRoot
+	cloneFieldCount
+)
+
+// openClone shows the clone prompt. The repository is cloned into the active root folder
+// under its name from the URL unless another name or root folder is chosen.
+func (m model) openClone() (tea.Model, tea.Cmd) {
+	m.confirmClone = true
+	m.cloneMode = "url"
+	m.errorMessage = ""
+	m.statusMessage = "Enter repository URL or press 'b' to browse your repositories"
+
+	cloneInput := textinput.New()
+	cloneInput.Placeholder = "https://github.com/owner/repo, git@gitlab.com:group/repo.git or press 'b' to browse"
+	cloneInput.Focus()
+	cloneInput.CharLimit = 256
+	cloneInput.Width = 60
+	m.cloneInput = cloneInput
+
+	nameInput := textinput.New()
+	nameInput.Placeholder = "name from the URL"
+	nameInput.CharLimit = 100
+	nameInput.Width = 40
+	m.cloneNameInput = nameInput
+
+	// The active root folder comes first, then the others in the order they were added
+	m.cloneRoots = []string{m.rootScanPath}
+	if rootFolders, err := db.GetAllRootFolders(); err == nil {
+		for _, rootFolder := range rootFolders {
+			if rootFolder.Path != m.rootScanPath {
+				m.cloneRoots = append(m.cloneRoots, rootFolder.Path)
+			}
+		}
+	}
+	m.cloneRoot = 0
+	m.cloneField = cloneFieldURL
+
+	return m, tea.Batch(textinput.Blink, cloneClipboardCmd())
+}
+
+// focusCloneField moves the focus of the clone prompt to a field
+func (m *model) focusCloneField(field int) tea.Cmd {
+	m.cloneField = field
+	m.cloneInput.Blur()
+	m.cloneNameInput.Blur()
+	switch field {
+	case cloneFieldURL:
+		return m.cloneInput.Focus()
+	case cloneFieldName:
+		return m.cloneNameInput.Focus()
+	}
+	return nil
+}
+
+// updateClonePrompt handles key presses while the clone prompt is open
+func (m model) updateClonePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.confirmClone = false
+		m.statusMessage = "Clone cancelled"
+		m.errorMessage = ""
+		return m, nil
+
+	case "tab":
+		return m, m.focusCloneField((m.cloneField + 1) % cloneFieldCount)
+
+	case "shift+tab":
+		return m, m.focusCloneField((m.cloneField + cloneFieldCount - 1) % cloneFieldCount)
+
+	case "enter":
+		if strings.TrimSpace(m.cloneInput.Value()) == "" {
+			m.errorMessage = "Please enter a valid GitHub repository URL or press 'b' to browse"
+			return m, nil
+		}
+		repoURL, err := engine.NormalizeRepoURL(m.cloneInput.Value())
+		if err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		name := strings.TrimSpace(m.cloneNameInput.Value())
+		if name != "" {
+			if err := engine.ValidateProjectName(name); err != nil {
+				m.errorMessage = err.Error()
+				return m, nil
+			}
+		}
+		m.confirmClone = false
+		m.statusMessage = "Cloning repository..."
+		m.errorMessage = ""
+		return m, m.cloneProjectCmd(repoURL, m.cloneRoots[m.cloneRoot], name)
+	}
+
+	switch m.cloneField {
+	case cloneFieldURL:
+		if msg.String() == "b" {
+			// Switch to browse mode, which needs a GitHub token
+			token, err := db.GetConfig("github_token")
+			if err != nil || token == "" {
+				m.errorMessage = "GitHub authentication required. Press 't' to authenticate with OAuth."
+				return m, nil
+			}
+			m.confirmClone = false
+			m.statusMessage = "Loading your GitHub repositories..."
+			m.errorMessage = ""
+			return m, fetchUserReposCmd()
+		}
+		var cmd tea.Cmd
+		m.cloneInput, cmd = m.cloneInput.Update(msg)
+		return m, cmd
+
+	case cloneFieldName:
+		var cmd tea.Cmd
+		m.cloneNameInput, cmd = m.cloneNameInput.Update(msg)
+		return m, cmd
+
+	case cloneFieldRoot:
+		switch msg.String() {
+		case "right", "l", " ":
+			m.cloneRoot = (m.cloneRoot + 1) % len(m.cloneRoots)
+		case "left", "h":
+			m.cloneRoot = (m.cloneRoot + len(m.cloneRoots) - 1) % len(m.cloneRoots)
+		}
+	}
+	return m, nil
+}
+
+// viewClonePrompt renders the clone prompt. The name field suggests the name from the URL.
+func (m model) viewClonePrompt() string {
+	textStyle := lipgloss.NewStyle().Foreground(colorText)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	label := func(field int, text string) string {
+		if field == m.cloneField {
+			return lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("► " + text)
+		}
+		return textStyle.Render("  " + text)
+	}
+
+	nameInput := m.cloneNameInput
+	if ref, ok := engine.ParseRepoURL(strings.TrimSpace(m.cloneInput.Value())); ok {
+		nameInput.Placeholder = ref.Name()
+	}
+	root := m.cloneRoots[m.cloneRoot]
+	if len(m.cloneRoots) > 1 {
+		root = "◂ " + root + " ▸"
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render(tr("clone.title")) + "\n\n" +
+		label(cloneFieldURL, tr("clone.prompt")) + "\n" +
+		m.cloneInput.View() + "\n\n" +
+		label(cloneFieldName, tr("clone.name")) + "\n" +
+		nameInput.View() + "\n\n" +
+		label(cloneFieldRoot, tr("clone.root")) + "\n" +
+		textStyle.Render("  "+root) + "\n\n" +
+		dimStyle.Render(tr("clone.help"))
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// CloudBackupsMsg is sent when the root folder has no gist yet and the user's gists were
+// searched for DevBase backups to adopt
+type CloudBackupsMsg struct {
+	backups []engine.CloudBackup
+	err     error
+}
+
+// BackupAdoptedMsg is sent when a found backup became the root folder's gist
+type BackupAdoptedMsg struct {
+	backup engine.CloudBackup
+	err    error
+}
+
+// cloudBackupsFound opens the picker of the backups found in the user's gists
+func (m model) cloudBackupsFound(msg CloudBackupsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to list cloud projects: %v", msg.err)
+		m.statusMessage = ""
+		return m, nil
+	}
+	m.cloudBackups = msg.backups
+	m.backupCursor = 0
+	m.statusMessage = ""
+	m.errorMessage = ""
+	return m, nil
+}
+
+// updateBackupPicker handles key presses while a backup to adopt is picked
+func (m model) updateBackupPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.cloudBackups = nil
+		return m, nil
+
+	case "up", "k":
+		if m.backupCursor > 0 {
+			m.backupCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.backupCursor < len(m.cloudBackups)-1 {
+			m.backupCursor++
+		}
+		return m, nil
+
+	case "enter":
+		backup := m.cloudBackups[m.backupCursor]
+		m.cloudBackups = nil
+		m.errorMessage = ""
+		m.statusMessage = "Loading projects from cloud..."
+		return m, adoptBackupCmd(backup)
+	}
+	return m, nil
+}
+
+// adoptBackupCmd creates a command that makes a backup the active root folder's gist
+func adoptBackupCmd(backup engine.CloudBackup) tea.Cmd {
+	return func() tea.Msg {
+		token, err := db.GetConfig("github_token")
+		if err != nil || token == "" {
+			return BackupAdoptedMsg{backup: backup, err: fmt.Errorf("GitHub authentication required. Please authenticate with OAuth (press 't')")}
+		}
+		var rootFolderID uint
+		if activeRoot, err := db.GetActiveRootFolder(); err == nil && activeRoot != nil {
+			rootFolderID = activeRoot.ID
+		}
+		client, err := engine.NewGistClient(token, rootFolderID)
+		if err != nil {
+			return BackupAdoptedMsg{backup: backup, err: fmt.Errorf("failed to create gist client: %w", err)}
+		}
+		return BackupAdoptedMsg{backup: backup, err: client.AdoptBackup(backup.GistID)}
+	}
+}
+
+// backupAdopted lists the projects of the adopted backup to pick from
+func (m model) backupAdopted(msg BackupAdoptedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to use the backup in gist %s: %v", msg.backup.GistID, msg.err)
+		m.statusMessage = ""
+		return m, nil
+	}
+	return m, listCloudProjectsCmd()
+}
+
+// viewBackupPicker renders the backups found in the user's gists
+func (m model) viewBackupPicker() string {
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("☁ CLOUD BACKUPS") + "\n" +
+		lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("This root folder has no backup yet; pick one of your gists to load from and sync to") + "\n\n"
+
+	now := time.Now()
+	for i, backup := range m.cloudBackups {
+		title := backup.Description
+		if title == "" {
+			title = backup.File
+		}
+		hint := lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(fmt.Sprintf("  %s, %s, updated %s", backup.File, backup.GistID, relativeTime(backup.UpdatedAt, now)))
+		if i == m.backupCursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+title) + hint + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+title) + hint + "\n"
+		}
+	}
+
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\n↑↓=navigate  enter=use this backup  esc=close")
+	return s
+}
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/models"
+)
+
+// cloudItem wraps a project loaded from the cloud backup for the multi-select list
+type cloudItem struct {
+	project  models.Project
+	index    int  // Position in the full cloud project slice
+	selected bool // Marked for loading
+}
+
+// FilterValue implements list.Item. The text part covers name, path, repository URL and
+// description; the fields make tag:, status: and lang: filters work as in the project list.
+func (i cloudItem) FilterValue() string {
+	p := i.project
+	text := strings.Join([]string{p.Name, p.Path, p.RepoURL, p.Description}, " ")
+	return strings.Join([]string{text, p.Status, p.Language, strings.Join(p.Tags, ",")}, filterFieldSep)
+}
+
+// cloudDelegate renders cloud items as single-line checkbox rows
+type cloudDelegate struct{}
+
+// Height implements list.ItemDelegate
+func (d cloudDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate
+func (d cloudDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate
+func (d cloudDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+
+// Render implements list.ItemDelegate
+func (d cloudDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	i, ok := item.(cloudItem)
+	if !ok {
+		return
+	}
+
+	isCursor := index == m.Index()
+
+	checkbox := "[ ]"
+	if i.selected {
+		checkbox = "[✓]"
+	}
+
+	cursor := " "
+	if isCursor {
+		cursor = "►"
+	}
+
+	// Additional info if available
+	var additionalInfo string
+	if i.project.RepoURL != "" {
+		iconColor := colorDim
+		if isCursor {
+			iconColor = colorAccent
+		}
+		additionalInfo = lipgloss.NewStyle().
+			Foreground(iconColor).
+			Render(" 🔗")
+	}
+
+	// Style based on cursor position and selection
+	lineStyle := lipgloss.NewStyle()
+	if isCursor && i.selected {
+		lineStyle = lineStyle.
+			Background(colorSuccessDim).
+			Foreground(colorInverse).
+			Bold(true)
+	} else if isCursor {
+		lineStyle = lineStyle.
+			Background(colorSelection).
+			Foreground(colorSelectionText).
+			Bold(true)
+	} else if i.selected {
+		lineStyle = lineStyle.
+			Foreground(colorSuccess).
+			Bold(true)
+	} else {
+		lineStyle = lineStyle.
+			Foreground(colorText)
+	}
+
+	line := fmt.Sprintf("%s %s %3d. %s", cursor, checkbox, index+1, i.project.Name)
+	fmt.Fprint(w, lineStyle.Render(line)+additionalInfo) //nolint: errcheck
+}
+
+// newCloudList creates the multi-select list for cloud projects
+func newCloudList(projects []models.Project, width, height int) list.Model {
+	items := make([]list.Item, len(projects))
+	for i, p := range projects {
+		items[i] = cloudItem{project: p, index: i}
+	}
+
+	l := list.New(items, cloudDelegate{}, width, height)
+	l.Title = "Available Projects"
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	l.Filter = filterProjects
+	l.SetShowHelp(false)
+	l.SetStatusBarItemName("project", "projects")
+	return l
+}
+
+// cloudListSize returns the list dimensions for the cloud select screen
+func cloudListSize(width, height int) (int, int) {
+	listHeight := height - 16 // title, instructions, summary and help
+	if listHeight < 5 {
+		listHeight = 5
+	}
+	return width - 4, listHeight
+}
+
+// selectedCloudIndices returns the cloud project indices currently marked for loading
+func selectedCloudIndices(l list.Model) []int {
+	var indices []int
+	for _, item := range l.Items() {
+		if ci, ok := item.(cloudItem); ok && ci.selected {
+			indices = append(indices, ci.index)
+		}
+	}
+	return indices
+}
+
+// setCloudSelection applies fn to the selection state of the given items.
+// The returned command refreshes the filtered view when a filter is applied.
+func setCloudSelection(l *list.Model, targets []list.Item, fn func(selected bool) bool) tea.Cmd {
+	target := make(map[int]bool, len(targets))
+	for _, item := range targets {
+		if ci, ok := item.(cloudItem); ok {
+			target[ci.index] = true
+		}
+	}
+
+	// A single toggle updates in place, avoiding a full refilter flash
+	if len(targets) == 1 {
+		if ci, ok := targets[0].(cloudItem); ok {
+			ci.selected = fn(ci.selected)
+			return l.SetItem(ci.index, ci)
+		}
+	}
+
+	items := l.Items()
+	for i, item := range items {
+		if ci, ok := item.(cloudItem); ok && target[ci.index] {
+			ci.selected = fn(ci.selected)
+			items[i] = ci
+		}
+	}
+	return l.SetItems(items)
+}
+
+// multiSelectKey applies the selection keys shared by the multi-select screens: space/tab
+// toggles the cursor row, a selects all visible rows, n clears, i inverts and 1-9 toggle
+// visible rows. ok reports whether key was one of them; status describes bulk changes.
+func multiSelectKey(l *list.Model, key, noun string) (cmd tea.Cmd, status string, ok bool) {
+	toggle := func(selected bool) bool { return !selected }
+
+	switch key {
+	case " ", "tab":
+		// Toggle selection at current cursor position
+		item := l.SelectedItem()
+		if item == nil {
+			return nil, "", true
+		}
+		return setCloudSelection(l, []list.Item{item}, toggle), "", true
+
+	case "a":
+		// Select all visible (filtered) items
+		visible := l.VisibleItems()
+		if len(visible) == len(l.Items()) {
+			status = fmt.Sprintf("Selected all %d %s", len(visible), noun)
+		} else {
+			status = fmt.Sprintf("Selected all %d filtered %s", len(visible), noun)
+		}
+		return setCloudSelection(l, visible, func(bool) bool { return true }), status, true
+
+	case "n":
+		// Clear all selections
+		return setCloudSelection(l, l.Items(), func(bool) bool { return false }), "Cleared all selections", true
+
+	case "i":
+		// Invert selection
+		cmd = setCloudSelection(l, l.Items(), toggle)
+		return cmd, fmt.Sprintf("Inverted selection (%d selected)", len(selectedCloudIndices(*l))), true
+	}
+
+	// Number keys for quick selection (1-9) of visible rows
+	if len(key) == 1 {
+		num := int(key[0] - '0')
+		visible := l.VisibleItems()
+		if num >= 1 && num <= min(9, len(visible)) {
+			return setCloudSelection(l, []list.Item{visible[num-1]}, toggle), "", true
+		}
+	}
+	return nil, "", false
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// Columns that can be shown in a project row description, chosen with the comma-separated
+// "list_columns" config key (e.g. "path,lang,size,commit")
+const (
+	columnPath     = "path"
+	columnURL      = "url"
+	columnLanguage = "lang"
+	columnSize     = "size"
+	columnCommit   = "commit"
+	columnBranch   = "branch"
+)
+
+// defaultColumns keeps the original path and repository URL description
+var defaultColumns = []string{columnPath, columnURL}
+
+// descriptionColumns are the columns shown in project rows, set by loadColumns
+var descriptionColumns = defaultColumns
+
+// ProjectMetadataMsg is sent when the background metadata collection completes
+type ProjectMetadataMsg struct {
+	metadata map[uint]engine.ProjectMetadata
+}
+
+// loadColumns reads the visible description columns from config, ignoring unknown names
+func loadColumns() {
+	configured, _ := db.GetConfig("list_columns")
+	var columns []string
+	for _, column := range strings.Split(configured, ",") {
+		switch column = strings.ToLower(strings.TrimSpace(column)); column {
+		case columnPath, columnURL, columnLanguage, columnSize, columnCommit, columnBranch:
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		columns = defaultColumns
+	}
+	descriptionColumns = columns
+}
+
+// columnEnabled reports whether a description column is visible
+func columnEnabled(column string) bool {
+	for _, c := range descriptionColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// describeProject builds the row description from the visible columns
+func describeProject(i projectItem, now time.Time) string {
+	var parts []string
+	for _, column := range descriptionColumns {
+		switch column {
+		case columnPath:
+			if i.project.Path != "" {
+				parts = append(parts, i.project.Path)
+			} else {
+				parts = append(parts, i.project.Status)
+			}
+		case columnURL:
+			if i.project.RepoURL != "" {
+				parts = append(parts, i.project.RepoURL)
+			}
+		case columnLanguage:
+			if i.project.Language != "" {
+				parts = append(parts, i.project.Language)
+			}
+		case columnSize:
+			if i.meta.Size > 0 {
+				parts = append(parts, engine.FormatSize(i.meta.Size))
+			}
+		case columnCommit:
+			if !i.meta.LastCommit.IsZero() {
+				parts = append(parts, tr("list.item.committed", relativeTime(i.meta.LastCommit, now)))
+			}
+		case columnBranch:
+			// A trailing * marks uncommitted changes, like git prompts do
+			if i.meta.Branch != "" {
+				branch := i.meta.Branch
+				if i.meta.Dirty {
+					branch += "*"
+				}
+				parts = append(parts, branch)
+			}
+		}
+	}
+	return strings.Join(parts, " • ")
+}
+
+// projectMetadataCmd creates a command that collects size and git details for active
+// projects as a job. It returns nil when no column needs them.
+func (m model) projectMetadataCmd(items []list.Item) tea.Cmd {
+	withSize, withGit := columnEnabled(columnSize), columnEnabled(columnCommit) || columnEnabled(columnBranch)
+	if !withSize && !withGit {
+		return nil
+	}
+
+	// Snapshot the paths so the walk doesn't touch the list from another goroutine
+	paths := make(map[uint]string)
+	toHost := hostPathFunc()
+	for _, item := range items {
+		if pi, ok := item.(projectItem); ok && pi.project.Status == "active" && pi.project.Path != "" && pi.remoteHost == "" {
+			paths[pi.project.ID] = toHost(pi.project.Path)
+		}
+	}
+	title := fmt.Sprintf("Project details for %d projects", len(paths))
+	if withSize {
+		title = fmt.Sprintf("Sizes of %d projects", len(paths))
+	}
+	return m.cancellableJobCmd(engine.JobSize, title, func(ctx context.Context, id int) (tea.Msg, error) {
+		m.jobs.Progress(id, 0, len(paths), "")
+		metadata := engine.CollectProjectMetadata(ctx, paths, withSize, withGit, func(done int) {
+			m.jobs.Progress(id, done, len(paths), "")
+		})
+		return ProjectMetadataMsg{metadata: metadata}, ctx.Err()
+	})
+}
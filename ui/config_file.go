@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+)
+
+// configCheckInterval is how often config.toml is checked for changes
+const configCheckInterval = 2 * time.Second
+
+// configCheckTickMsg triggers a check of config.toml
+type configCheckTickMsg struct{}
+
+// ConfigFileMsg is sent when config.toml was checked for changes
+type ConfigFileMsg struct {
+	changed bool
+	err     error
+}
+
+// scheduleConfigCheck checks config.toml again after configCheckInterval
+func scheduleConfigCheck() tea.Cmd {
+	return tea.Tick(configCheckInterval, func(time.Time) tea.Msg {
+		return configCheckTickMsg{}
+	})
+}
+
+// checkConfigFileCmd reloads config.toml in the background when it changed
+func checkConfigFileCmd() tea.Cmd {
+	return func() tea.Msg {
+		changed, err := db.ReloadConfigFile()
+		return ConfigFileMsg{changed: changed, err: err}
+	}
+}
+
+// configFileChecked applies a changed config.toml. Settings read on demand (editor,
+// terminal, run environment, …) pick it up by themselves; the ones read at startup are
+// loaded again here.
+func (m model) configFileChecked(msg ConfigFileMsg) (tea.Model, tea.Cmd) {
+	next := scheduleConfigCheck()
+	if !msg.changed {
+		return m, next
+	}
+	if msg.err != nil {
+		m.errorMessage = "Config file ignored: " + msg.err.Error()
+		return m, next
+	}
+
+	loadTheme()
+	loadLocale()
+	loadColumns()
+	nerdFont, _ := db.GetConfig("nerd_font")
+	m.list.SetDelegate(newProjectDelegate(nerdFont))
+	m.list.Title = listTitle(m.statusFilter)
+	m.layout = loadLayout()
+	m.vimMode = loadVimMode()
+	m.vimPending = ""
+	m.recentHotkeys = loadRecentHotkeys()
+	refresh := m.refreshHotkeys()
+	if m.ready {
+		m.list.SetSize(m.listSize())
+	}
+
+	m.errorMessage = ""
+	m.statusMessage = "Reloaded " + db.ConfigFileName
+	return m, tea.Batch(next, refresh)
+}
@@ -0,0 +1,17 @@
+package ui
+
+import (
+	"runtime/debug"
+
+	"devbase/engine"
+)
+
+// recoverCrash is deferred in Update and View. It writes a diagnostic bundle for a panic
+// while the stack trace still shows where it happened, then panics again so Bubble Tea
+// restores the terminal and Run returns tea.ErrProgramPanic.
+func recoverCrash() {
+	if r := recover(); r != nil {
+		engine.RecordCrash(r, debug.Stack())
+		panic(r)
+	}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"devbase/engine"
+)
+
+// languageBadge describes how a detected language is shown in the project list
+type languageBadge struct {
+	label string // Plain-text label used when Nerd Font glyphs are disabled
+	glyph string // Nerd Font glyph
+	color string
+}
+
+// languageBadges maps engine language identifiers to their list badge
+var languageBadges = map[string]languageBadge{
+	engine.LangGo:         {label: "Go", glyph: "\ue627", color: "#00ADD8"},
+	engine.LangTypeScript: {label: "TS", glyph: "\ue628", color: "#3178C6"},
+	engine.LangJavaScript: {label: "JS", glyph: "\ue74e", color: "#F7DF1E"},
+	engine.LangPython:     {label: "Py", glyph: "\ue73c", color: "#4B8BBE"},
+	engine.LangRust:       {label: "Rs", glyph: "\ue7a8", color: "#DEA584"},
+	engine.LangJava:       {label: "Java", glyph: "\ue738", color: "#B07219"},
+	engine.LangCSharp:     {label: "C#", glyph: "\U000f031b", color: "#178600"},
+	engine.LangRuby:       {label: "Rb", glyph: "\ue739", color: "#CC342D"},
+	engine.LangPHP:        {label: "PHP", glyph: "\ue73d", color: "#777BB4"},
+	engine.LangDart:       {label: "Dart", glyph: "\ue798", color: "#00B4AB"},
+	engine.LangCpp:        {label: "C++", glyph: "\ue61d", color: "#F34B7D"},
+}
+
+// badgeWidth is the fixed width of the plain-text badge column so names stay aligned
+const badgeWidth = 4
+
+// projectDelegate renders project rows with a colored language badge in front of the name.
+// It keeps the DefaultDelegate styles and layout but styles the badge separately so the
+// badge color survives selection and filter highlighting only applies to the name.
+type projectDelegate struct {
+	list.DefaultDelegate
+	nerdFont bool // Use Nerd Font glyphs instead of plain-text labels
+}
+
+// newProjectDelegate creates a project delegate, enabling Nerd Font glyphs when
+// the "nerd_font" config key or the DEVBASE_NERD_FONT env var is set to "true"/"1"
+func newProjectDelegate(nerdFontConfig string) projectDelegate {
+	nerdFont := nerdFontConfig == "true" || nerdFontConfig == "1"
+	if env := os.Getenv("DEVBASE_NERD_FONT"); env != "" {
+		nerdFont = env == "true" || env == "1"
+	}
+
+	delegate := list.NewDefaultDelegate()
+	if highContrast {
+		// The default pink and gray row colors are hard to read, so use the theme colors
+		styles := &delegate.Styles
+		styles.SelectedTitle = styles.SelectedTitle.Foreground(colorAccent).BorderForeground(colorAccent)
+		styles.SelectedDesc = styles.SelectedDesc.Foreground(colorAccent).BorderForeground(colorAccent)
+		styles.NormalTitle = styles.NormalTitle.Foreground(colorText)
+		styles.NormalDesc = styles.NormalDesc.Foreground(colorText)
+	}
+	return projectDelegate{
+		DefaultDelegate: delegate,
+		nerdFont:        nerdFont,
+	}
+}
+
+// badge returns the rendered language badge for a project, or blank padding if unknown
+func (d projectDelegate) badge(language string) string {
+	b, ok := languageBadges[language]
+	if !ok {
+		if d.nerdFont {
+			return "  "
+		}
+		return fmt.Sprintf("%-*s ", badgeWidth, "")
+	}
+
+	style := lipgloss.NewStyle().Foreground(paletteColor(b.color)).Bold(true)
+	if d.nerdFont {
+		return style.Render(b.glyph) + " "
+	}
+	return style.Render(fmt.Sprintf("%-*s", badgeWidth, b.label)) + " "
+}
+
+// Render implements list.ItemDelegate
+func (d projectDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	i, ok := item.(projectItem)
+	if !ok {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+
+	if m.Width() <= 0 {
+		return
+	}
+
+	s := &d.Styles
+	name, prefix, suffix := i.titleParts()
+	desc := i.Description()
+
+	// Prevent text from exceeding list width
+	textwidth := m.Width() - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight()
+	badge := d.badge(i.project.Language)
+	nameWidth := textwidth - ansi.StringWidth(badge) - ansi.StringWidth(prefix) - ansi.StringWidth(suffix)
+	if nameWidth < 1 {
+		nameWidth = 1
+	}
+	name = ansi.Truncate(name, nameWidth, "…")
+	desc = ansi.Truncate(desc, textwidth, "…")
+
+	// Conditions
+	var (
+		isSelected  = index == m.Index()
+		emptyFilter = m.FilterState() == list.Filtering && m.FilterValue() == ""
+		isFiltered  = m.FilterState() == list.Filtering || m.FilterState() == list.FilterApplied
+	)
+
+	var matchedRunes []int
+	if isFiltered && index < len(m.VisibleItems()) {
+		matchedRunes = m.MatchesForItem(index)
+	}
+
+	// Pick the frame (border/padding) and text styles for this row
+	titleStyle, descStyle := s.NormalTitle, s.NormalDesc
+	if emptyFilter {
+		titleStyle, descStyle = s.DimmedTitle, s.DimmedDesc
+	} else if isSelected && m.FilterState() != list.Filtering {
+		titleStyle, descStyle = s.SelectedTitle, s.SelectedDesc
+	}
+	textStyle := titleStyle.Inline(true)
+
+	if isFiltered && !emptyFilter {
+		matched := textStyle.Inherit(s.FilterMatch)
+		name = lipgloss.StyleRunes(name, matchedRunes, matched, textStyle)
+	} else {
+		name = textStyle.Render(name)
+	}
+
+	line := badge + textStyle.Render(prefix) + name + textStyle.Render(suffix)
+	title := titleStyle.UnsetForeground().Render(line)
+	desc = descStyle.Render(desc)
+
+	if d.ShowDescription {
+		fmt.Fprintf(w, "%s\n%s", title, desc) //nolint: errcheck
+		return
+	}
+	fmt.Fprintf(w, "%s", title) //nolint: errcheck
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// gitKeys clone or create repositories, so they are disabled while git is missing
+var gitKeys = []string{"g", "b", "S", "O", "r", "A", "i", "K"}
+
+// DependenciesMsg is sent when the programs DevBase runs have been looked up at startup
+type DependenciesMsg struct {
+	deps []engine.Dependency
+}
+
+// checkDependenciesCmd looks up git, the default editor and the configured terminal
+func checkDependenciesCmd() tea.Cmd {
+	return func() tea.Msg {
+		command, _ := db.GetConfig("terminal")
+		return DependenciesMsg{deps: engine.CheckDependencies(defaultEditor(), engine.TerminalByCommand(command))}
+	}
+}
+
+// dependenciesChecked remembers the missing programs. Without git DevBase runs degraded:
+// projects can still be listed and opened, but not cloned, restored or created.
+func (m model) dependenciesChecked(msg DependenciesMsg) (tea.Model, tea.Cmd) {
+	m.missingDeps = nil
+	m.gitMissing = nil
+	for _, dep := range msg.deps {
+		var missing *engine.MissingCommandError
+		if !errors.As(dep.Err, &missing) {
+			continue
+		}
+		m.missingDeps = append(m.missingDeps, missing)
+		if missing.Command == "git" {
+			m.gitMissing = missing
+		}
+	}
+	// The warnings take lines from the list
+	if m.ready {
+		m.list.SetSize(m.listSize())
+	}
+	return m, nil
+}
+
+// gitKeyDisabled reports whether a key needs git while it is missing
+func (m model) gitKeyDisabled(key string) bool {
+	return m.gitMissing != nil && slices.Contains(gitKeys, key)
+}
+
+// viewMissingDeps renders a warning line for each missing program
+func (m model) viewMissingDeps() string {
+	var s string
+	for _, missing := range m.missingDeps {
+		s += "\n" + lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Render("⚠ "+missing.Error())
+	}
+	return s
+}
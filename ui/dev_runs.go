@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/engine"
+	"devbase/models"
+)
+
+// devRunCheckInterval is how often running projects are checked while any are running
+const devRunCheckInterval = 3 * time.Second
+
+// devRunTickMsg triggers a check of the dev runs that are going; ticks carrying an earlier
+// id were replaced by a later one
+type devRunTickMsg struct {
+	id int
+}
+
+// DevRunsMsg is sent when the dev runs that are still going were looked up
+type DevRunsMsg struct {
+	runs map[uint][]models.DevRun // By project ID
+	err  error
+}
+
+// DevRunStoppedMsg is sent when a dev run was stopped, or stopped and started again
+type DevRunStoppedMsg struct {
+	project   models.Project
+	run       models.DevRun
+	restarted tea.Msg // What starting it again reported, nil when it was only stopped
+	err       error
+}
+
+// checkDevRunsCmd looks up the dev runs that are still going
+func checkDevRunsCmd() tea.Cmd {
+	return func() tea.Msg {
+		runs, err := engine.ActiveDevRuns()
+		return DevRunsMsg{runs: runs, err: err}
+	}
+}
+
+// devRunTickCmd schedules the next check of the dev runs
+func devRunTickCmd(id int) tea.Cmd {
+	return tea.Tick(devRunCheckInterval, func(time.Time) tea.Msg {
+		return devRunTickMsg{id: id}
+	})
+}
+
+// devRunsChecked shows which projects are running. The runs are checked again a few
+// seconds later for as long as any are going.
+func (m model) devRunsChecked(msg DevRunsMsg) (tea.Model, tea.Cmd) {
+	// Every check replaces the scheduled one, so only one is pending
+	m.devRunTick++
+	if msg.err != nil {
+		return m, nil
+	}
+	m.devRuns = msg.runs
+
+	var cmds []tea.Cmd
+	for i, item := range m.list.Items() {
+		pi, ok := item.(projectItem)
+		if !ok {
+			continue
+		}
+		runs := msg.runs[pi.project.ID]
+		if !sameDevRuns(pi.runs, runs) {
+			pi.runs = runs
+			cmds = append(cmds, m.list.SetItem(i, pi))
+		}
+	}
+
+	if len(msg.runs) > 0 {
+		cmds = append(cmds, devRunTickCmd(m.devRunTick))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// devRunTicked checks the dev runs again unless a later check scheduled another tick
+func (m model) devRunTicked(msg devRunTickMsg) (tea.Model, tea.Cmd) {
+	if msg.id != m.devRunTick {
+		return m, nil
+	}
+	return m, checkDevRunsCmd()
+}
+
+// sameDevRuns reports whether two lists of runs show the same in the project list
+func sameDevRuns(a, b []models.DevRun) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID || a[i].Port != b[i].Port {
+			return false
+		}
+	}
+	return true
+}
+
+// runningTask returns the project's run of a command, if one is going
+func (m model) runningTask(projectID uint, command string) (models.DevRun, bool) {
+	for _, run := range m.devRuns[projectID] {
+		if run.Task == command {
+			return run, true
+		}
+	}
+	return models.DevRun{}, false
+}
+
+// describeDevRun summarizes a dev run, e.g. "npm run dev (pid 4242, port 5173, 3m)"
+func describeDevRun(run models.DevRun) string {
+	details := fmt.Sprintf("pid %d", run.PID)
+	if run.Port != 0 {
+		details += fmt.Sprintf(", port %d", run.Port)
+	}
+	details += ", " + time.Since(run.StartedAt).Round(time.Second).String()
+	return fmt.Sprintf("%s (%s)", run.Task, details)
+}
+
+// stopDevRunCmd creates a command that stops a dev run of the project and, when restart is
+// set, starts it again the way it ran before
+func stopDevRunCmd(project models.Project, run models.DevRun, restart bool) tea.Cmd {
+	return func() tea.Msg {
+		if err := engine.StopDevRun(run); err != nil {
+			return DevRunStoppedMsg{project: project, run: run, err: err}
+		}
+		msg := DevRunStoppedMsg{project: project, run: run}
+		if restart {
+			if run.Streamed {
+				msg.restarted = streamRunCmd(project, run.Task, run.EnvSource, nil)()
+			} else {
+				msg.restarted = capturedRunCmd(project, run.Task, run.EnvSource)()
+			}
+		}
+		return msg
+	}
+}
+
+// stopDevRuns stops the dev runs of the project in the task picker, or restarts them
+func (m model) stopDevRuns(restart bool) (tea.Model, tea.Cmd) {
+	project := m.taskProject.project
+	runs := m.devRuns[project.ID]
+	if len(runs) == 0 {
+		return m, nil
+	}
+	m.closeTaskPicker()
+	verb := "Stopping"
+	if restart {
+		verb = "Restarting"
+	}
+	m.statusMessage = fmt.Sprintf("%s %d run(s) of %s...", verb, len(runs), project.Name)
+
+	cmds := make([]tea.Cmd, len(runs))
+	for i, run := range runs {
+		cmds[i] = stopDevRunCmd(project, run, restart)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// devRunStopped reports a stopped dev run and hands a restart on to the run it started
+func (m model) devRunStopped(msg DevRunStoppedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to stop %s: %v", msg.run.Task, msg.err)
+		m.statusMessage = ""
+		return m, checkDevRunsCmd()
+	}
+	switch restarted := msg.restarted.(type) {
+	case RunStreamMsg:
+		return m.runStreamStarted(restarted)
+	case RunCapturedMsg:
+		return m.runCaptured(restarted)
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Stopped %s in %s", msg.run.Task, msg.project.Name)
+	return m, checkDevRunsCmd()
+}
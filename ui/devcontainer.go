@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// DevContainerMsg is sent when "devcontainer up" finishes
+type DevContainerMsg struct {
+	err error
+}
+
+// openDevContainer opens the selected project in its dev container with the project's editor
+func (m model) openDevContainer(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "opened in a local dev container")
+		return m, nil
+	}
+	if item.missing || item.project.Status == "archived" {
+		m.errorMessage = "Project directory is not available"
+		return m, nil
+	}
+	if !item.project.DevContainer {
+		m.errorMessage = "Project has no .devcontainer/devcontainer.json"
+		return m, nil
+	}
+
+	editor := projectEditor(item.project)
+	cmd, err := engine.DevContainerCommand(editor, hostPath(item.project.Path))
+	if err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+
+	m.errorMessage = ""
+	projectID := item.project.ID
+
+	// Without editor support the devcontainer CLI builds the container, which can take a while
+	if !editor.Workspace {
+		m.statusMessage = "Starting dev container..."
+		return m, tea.Batch(recordOpenCmd(item.project), func() tea.Msg {
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				return DevContainerMsg{err: fmt.Errorf("devcontainer up failed: %s", lastLine(string(output)))}
+			}
+			_ = db.LogActivity(models.ActivityOpen, projectID, "devcontainer")
+			return DevContainerMsg{}
+		})
+	}
+
+	m.statusMessage = "Opening dev container in " + editor.Name + "..."
+	return m, tea.Batch(recordOpenCmd(item.project), func() tea.Msg {
+		err := cmd.Start()
+		if err == nil {
+			_ = db.LogActivity(models.ActivityOpen, projectID, editor.Name+" (dev container)")
+		}
+		return OpenProjectMsg{projectID: projectID, editor: editor.Name, err: err}
+	})
+}
+
+// devContainerUp reports the result of starting a dev container with the CLI
+func (m model) devContainerUp(msg DevContainerMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = msg.err.Error()
+		m.statusMessage = ""
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = "Dev container is running (attach with 'devcontainer exec' or your editor)"
+	return m, nil
+}
+
+// lastLine returns the last non-empty line of command output, where CLIs put the error
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// defaultEditor returns the editor configured with the "editor" config key
+func defaultEditor() engine.Editor {
+	command, _ := db.GetConfig("editor")
+	return engine.EditorByCommand(command)
+}
+
+// projectEditor returns the project's preferred editor, falling back to the default editor
+func projectEditor(project models.Project) engine.Editor {
+	if project.Editor != "" {
+		return engine.EditorByCommand(project.Editor)
+	}
+	return defaultEditor()
+}
+
+// OpenProject opens a project in its preferred editor outside the TUI, for "devbase open".
+// Terminal editors take over the current terminal until they exit.
+func OpenProject(project models.Project) error {
+	editor := projectEditor(project)
+	cmd, err := projectEditorCommand(project, editor)
+	if err != nil {
+		return err
+	}
+
+	if editor.Terminal {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		err = cmd.Run()
+	} else {
+		err = cmd.Start()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", editor.Name, err)
+	}
+	if _, err := engine.RecordOpen(project); err != nil {
+		slog.Warn("Failed to record project open", "project", project.Name, "err", err)
+	}
+	_ = db.LogActivity(models.ActivityOpen, project.ID, editor.Name)
+	return nil
+}
+
+// openEditorPicker shows the editor picker for a project, preselecting the default editor
+func (m model) openEditorPicker(item projectItem) (tea.Model, tea.Cmd) {
+	editors := engine.DetectEditors()
+	// URL editors aren't found on PATH, so a configured one is offered first
+	for _, editor := range []engine.Editor{defaultEditor(), projectEditor(item.project)} {
+		if editor.URL && !slices.Contains(editors, editor) {
+			editors = append([]engine.Editor{editor}, editors...)
+		}
+	}
+	if len(editors) == 0 {
+		m.errorMessage = "No supported editors found on PATH"
+		return m, nil
+	}
+
+	m.editorChoices = editors
+	m.editorCursor = 0
+	defaultCommand := defaultEditor().Command
+	for i, editor := range editors {
+		if editor.Command == defaultCommand {
+			m.editorCursor = i
+			break
+		}
+	}
+
+	itemCopy := item
+	m.editorProject = &itemCopy
+	m.showEditorPicker = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// updateEditorPicker handles key presses while the editor picker is open
+func (m model) updateEditorPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.showEditorPicker = false
+		m.editorProject = nil
+		return m, nil
+
+	case "up", "k":
+		if m.editorCursor > 0 {
+			m.editorCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.editorCursor < len(m.editorChoices)-1 {
+			m.editorCursor++
+		}
+		return m, nil
+
+	case "*":
+		// Make the highlighted editor the default for enter
+		editor := m.editorChoices[m.editorCursor]
+		if err := db.SetConfig("editor", editor.Command); err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to save default editor: %v", err)
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("%s is now the default editor", editor.Name)
+		return m, nil
+
+	case "enter":
+		editor := m.editorChoices[m.editorCursor]
+		item := *m.editorProject
+		m.showEditorPicker = false
+		m.editorProject = nil
+		m.errorMessage = ""
+
+		return m, tea.Batch(openProjectCmd(item.project, editor), recordOpenCmd(item.project))
+	}
+
+	return m, nil
+}
+
+// viewEditorPicker renders the editor picker
+func (m model) viewEditorPicker() string {
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("✎ OPEN WITH") + "\n\n"
+
+	if m.editorProject != nil {
+		s += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(m.editorProject.project.Name) + "\n\n"
+	}
+
+	defaultCommand := defaultEditor().Command
+	for i, editor := range m.editorChoices {
+		label := editor.Name
+		if editor.Terminal {
+			label += " (terminal)"
+		}
+		if editor.Command == defaultCommand {
+			label += " [default]"
+		}
+		if i == m.editorCursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+label) + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+label) + "\n"
+		}
+	}
+
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\n↑↓=navigate  enter=open  *=set default  esc=cancel")
+	return s
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// projectEnvSources returns the environment source a project offers and the one runs use by
+// default, following the "run_env" config key (off unless set). WSL and remote projects run
+// outside this process's environment, so they get none.
+func projectEnvSources(project models.Project) (found, selected string) {
+	if project.RemoteHostID != 0 {
+		return "", ""
+	}
+	if _, ok := wslProject(project.Path); ok {
+		return "", ""
+	}
+
+	mode, _ := db.GetConfig("run_env")
+	if mode == "" || mode == engine.EnvModeOff {
+		// Still offer the project's environment for the e toggle
+		return engine.DetectEnvSource(hostPath(project.Path), engine.EnvModeAuto), ""
+	}
+	found = engine.DetectEnvSource(hostPath(project.Path), mode)
+	return found, found
+}
+
+// envStatus describes the environment the next run uses in the task picker
+func envStatus(found, selected string) string {
+	switch {
+	case selected != "":
+		return "Environment: " + selected + " (e to skip)"
+	case found != "":
+		return "Environment: none (e to load " + found + ")"
+	}
+	return "Environment: none"
+}
+
+// envSuffix names the environment source in status messages
+func envSuffix(source string) string {
+	if source == "" {
+		return ""
+	}
+	return " with " + source
+}
+
+// startWithProjectEnv starts a terminal command with the variables of the project's
+// environment source added to DevBase's own environment
+func startWithProjectEnv(cmd *exec.Cmd, dir, source string) error {
+	vars, err := engine.LoadProjectEnv(dir, source)
+	if err != nil {
+		return err
+	}
+	if len(vars) > 0 {
+		cmd.Env = append(os.Environ(), vars...)
+	}
+	return cmd.Start()
+}
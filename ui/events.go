@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/engine"
+)
+
+// telemetryCmd sends the weekly usage report in the background when the user opted in
+func telemetryCmd() tea.Cmd {
+	return func() tea.Msg {
+		if err := engine.SendTelemetryIfDue(); err != nil {
+			slog.Warn("Telemetry not sent", "err", err)
+		}
+		return nil
+	}
+}
+
+// purgeTrashCmd deletes the archived checkouts the retention policy has made due in the
+// background
+func purgeTrashCmd() tea.Cmd {
+	return func() tea.Msg {
+		if _, err := engine.PurgeTrash(); err != nil {
+			slog.Warn("Failed to purge the trash", "err", err)
+		}
+		return nil
+	}
+}
+
+// emitCmd delivers lifecycle events to hooks and webhooks in the background. Delivery
+// failures are only logged, so a broken endpoint doesn't interrupt work in the TUI.
+func emitCmd(events ...engine.Event) tea.Cmd {
+	return func() tea.Msg {
+		for _, event := range events {
+			_ = engine.Emit(event)
+		}
+		return nil
+	}
+}
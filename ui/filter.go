@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"devbase/db"
+	"devbase/models"
+)
+
+// filterFieldSep separates the searchable text from the encoded fields in projectItem.FilterValue
+const filterFieldSep = "\x1f"
+
+// encodeFilterValue builds a filter value from the project's searchable text (name and tags,
+// so match highlighting lines up with the name) followed by the fields tag:, status: and lang: match
+func encodeFilterValue(p models.Project) string {
+	text := p.Name
+	if len(p.Tags) > 0 {
+		text += " " + strings.Join(p.Tags, " ")
+	}
+	return strings.Join([]string{text, p.Status, p.Language, strings.Join(p.Tags, ",")}, filterFieldSep)
+}
+
+// decodeFilterValue splits a filter value back into the searchable text and the filterable fields
+func decodeFilterValue(value string) (string, models.Project) {
+	parts := strings.Split(value, filterFieldSep)
+	var project models.Project
+	if len(parts) == 4 {
+		project.Status = parts[1]
+		project.Language = parts[2]
+		if parts[3] != "" {
+			project.Tags = strings.Split(parts[3], ",")
+		}
+	}
+	return parts[0], project
+}
+
+// filterProjects is the project list's filter. Field filters such as "tag:go status:active lang:ts"
+// narrow the projects, and the remaining free text is fuzzy matched against name and tags.
+func filterProjects(term string, targets []string) []list.Rank {
+	filter := db.ParseProjectFilter(term)
+
+	var texts []string
+	var indexes []int
+	for i, target := range targets {
+		text, project := decodeFilterValue(target)
+		if filter.MatchesFields(project) {
+			texts = append(texts, text)
+			indexes = append(indexes, i)
+		}
+	}
+
+	// Only field filters: keep the list order
+	if filter.Text == "" {
+		ranks := make([]list.Rank, len(indexes))
+		for i, index := range indexes {
+			ranks[i] = list.Rank{Index: index}
+		}
+		return ranks
+	}
+
+	ranks := list.DefaultFilter(filter.Text, texts)
+	for i := range ranks {
+		ranks[i].Index = indexes[ranks[i].Index]
+	}
+	return ranks
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+)
+
+// FooterStatsMsg is sent when the project stats of the list footer have been collected
+type FooterStatsMsg struct {
+	stats engine.ProjectStats
+	err   error
+}
+
+// footerStatsCmd collects the footer stats in the background. A request while a collection
+// runs is remembered and served when it completes, so reloads in a row walk projects once.
+func (m *model) footerStatsCmd() tea.Cmd {
+	if m.footerLoading {
+		m.footerStale = true
+		return nil
+	}
+	m.footerLoading, m.footerStale = true, false
+	return collectFooterStats(m.ctx)
+}
+
+// collectFooterStats creates a command that collects the footer stats
+func collectFooterStats(ctx context.Context) tea.Cmd {
+	toHost := hostPathFunc()
+	return func() tea.Msg {
+		stats, err := engine.CollectProjectStats(ctx, toHost)
+		return FooterStatsMsg{stats: stats, err: err}
+	}
+}
+
+// footerStatsLoaded shows the collected stats, collecting them again when the projects
+// changed in the meantime
+func (m model) footerStatsLoaded(msg FooterStatsMsg) (tea.Model, tea.Cmd) {
+	m.footerLoading = false
+	if msg.err == nil {
+		m.footerStats = &msg.stats
+	}
+	if m.footerStale {
+		return m, m.footerStatsCmd()
+	}
+	return m, nil
+}
+
+// viewFooter renders the summary line under the project list: project counts, the active
+// root folder, disk usage of the active projects and the last scan
+func (m model) viewFooter() string {
+	stats := m.footerStats
+	if stats == nil {
+		return ""
+	}
+	parts := []string{tr("footer.counts", stats.Total, stats.Active, stats.Archived)}
+	if stats.RootFolder != "" {
+		parts = append(parts, tr("footer.root", stats.RootFolder))
+	}
+	parts = append(parts, tr("footer.disk", engine.FormatSize(stats.DiskUsage)))
+	if stats.LastScan.IsZero() {
+		parts = append(parts, tr("footer.never_scanned"))
+	} else {
+		parts = append(parts, tr("footer.scanned", relativeTime(stats.LastScan, time.Now())))
+	}
+	if m.streamRunning() {
+		parts = append(parts, tr("footer.running", m.streamCommand))
+	}
+	return lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\n" + strings.Join(parts, "  │  "))
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// openGitClient opens the selected repository in a detected git client. With several
+// installed, a picker is shown with the client used last ("git_client" config key) preselected.
+func (m model) openGitClient(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "opened in a local git client")
+		return m, nil
+	}
+	if item.missing || item.project.Status == "archived" {
+		m.errorMessage = "Project directory is not available"
+		return m, nil
+	}
+	if _, err := os.Stat(filepath.Join(hostPath(item.project.Path), ".git")); err != nil {
+		m.errorMessage = "Project is not a git repository"
+		return m, nil
+	}
+
+	clients := engine.DetectGitClients()
+	switch len(clients) {
+	case 0:
+		m.errorMessage = "No git client found (GitHub Desktop, GitKraken, Fork or Sourcetree)"
+		return m, nil
+	case 1:
+		return m.startGitClient(item.project, clients[0])
+	}
+
+	m.gitClientChoices = clients
+	m.gitClientCursor = 0
+	last, _ := db.GetConfig("git_client")
+	for i, client := range clients {
+		if client.Name == last {
+			m.gitClientCursor = i
+			break
+		}
+	}
+
+	itemCopy := item
+	m.gitClientProject = &itemCopy
+	m.showGitClientPicker = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// startGitClient launches the git client on the project and remembers the choice
+func (m model) startGitClient(project models.Project, client engine.GitClient) (tea.Model, tea.Cmd) {
+	cmd, err := engine.GitClientCommand(client, hostPath(project.Path))
+	if err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+	_ = db.SetConfig("git_client", client.Name)
+
+	m.errorMessage = ""
+	m.statusMessage = "Opening " + project.Name + " in " + client.Name + "..."
+	projectID := project.ID
+	return m, func() tea.Msg {
+		err := cmd.Start()
+		if err == nil {
+			_ = db.LogActivity(models.ActivityOpen, projectID, client.Name)
+		}
+		return OpenProjectMsg{projectID: projectID, editor: client.Name, err: err}
+	}
+}
+
+// updateGitClientPicker handles key presses while the git client picker is open
+func (m model) updateGitClientPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.showGitClientPicker = false
+		m.gitClientProject = nil
+		return m, nil
+
+	case "up", "k":
+		if m.gitClientCursor > 0 {
+			m.gitClientCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.gitClientCursor < len(m.gitClientChoices)-1 {
+			m.gitClientCursor++
+		}
+		return m, nil
+
+	case "enter":
+		client := m.gitClientChoices[m.gitClientCursor]
+		project := m.gitClientProject.project
+		m.showGitClientPicker = false
+		m.gitClientProject = nil
+		return m.startGitClient(project, client)
+	}
+
+	return m, nil
+}
+
+// viewGitClientPicker renders the git client picker
+func (m model) viewGitClientPicker() string {
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("⎇ OPEN IN GIT CLIENT") + "\n\n"
+
+	if m.gitClientProject != nil {
+		s += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(m.gitClientProject.project.Name) + "\n\n"
+	}
+
+	for i, client := range m.gitClientChoices {
+		if i == m.gitClientCursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+client.Name) + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+client.Name) + "\n"
+		}
+	}
+
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\n↑↓=navigate  enter=open  esc=cancel")
+	return s
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// TokenScopesMsg is sent when the scopes of a token saved before they were recorded are known
+type TokenScopesMsg struct {
+	scopes engine.TokenScopes
+	source string // Inferred from the token's prefix, empty when it can't be told
+	err    error
+}
+
+// openGitHubSetup shows the GitHub setup screen with the saved token's scopes. Tokens saved
+// before scopes were recorded are checked in the background.
+func (m model) openGitHubSetup() (tea.Model, tea.Cmd) {
+	m.screen = screenSetupGitHub
+	m.errorMessage = ""
+	m.statusMessage = ""
+
+	m.tokenScopes, m.tokenSource, m.tokenScopesKnown = engine.SavedTokenScopes()
+	token, _ := db.GetConfig("github_token")
+	m.tokenSaved = token != ""
+	if !m.tokenSaved || m.tokenScopesKnown {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		scopes, err := engine.FetchTokenScopes(token)
+		if err != nil {
+			return TokenScopesMsg{err: err}
+		}
+		// Personal access tokens can be told by their prefix; OAuth and GitHub CLI tokens
+		// share gho_, so their source stays unknown and isn't saved
+		source := ""
+		if strings.HasPrefix(token, "ghp_") || strings.HasPrefix(token, "github_pat_") {
+			source = engine.TokenSourcePAT
+			if err := engine.SaveTokenScopes(source, scopes); err != nil {
+				slog.Warn("Failed to save GitHub token scopes", "err", err)
+			}
+		}
+		return TokenScopesMsg{scopes: scopes, source: source}
+	}
+}
+
+// updateTokenScopes shows the scopes of the saved token once they are known
+func (m model) updateTokenScopes(msg TokenScopesMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Couldn't check the saved token: %v", msg.err)
+		return m, nil
+	}
+	m.tokenScopes, m.tokenSource, m.tokenScopesKnown = msg.scopes, msg.source, true
+	return m, nil
+}
+
+// saveGitHubToken stores a token with where it came from and its scopes, when they could be
+// read, and returns what to add to the status message about them
+func (m *model) saveGitHubToken(token, source string, scopes *engine.TokenScopes) string {
+	if err := db.SetConfig("github_token", token); err != nil {
+		slog.Error("Failed to save GitHub token", "err", err)
+	}
+	m.tokenSaved, m.tokenSource, m.tokenScopesKnown = true, source, scopes != nil
+	if scopes == nil {
+		// Checked again the next time the setup screen opens
+		_ = db.SetConfig("github_token_source", "")
+		return ""
+	}
+	m.tokenScopes = *scopes
+	if err := engine.SaveTokenScopes(source, *scopes); err != nil {
+		slog.Warn("Failed to save GitHub token scopes", "err", err)
+	}
+	if warnings := engine.ScopeWarnings(*scopes, source); len(warnings) > 0 {
+		return fmt.Sprintf(" (⚠ %d scope warnings, press 't' to review)", len(warnings))
+	}
+	return ""
+}
+
+// viewTokenScopes renders the saved token's source, scopes and least-privilege warnings on
+// the GitHub setup screen
+func (m model) viewTokenScopes() string {
+	if !m.tokenSaved {
+		return ""
+	}
+	textStyle := lipgloss.NewStyle().Foreground(colorText)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	sources := map[string]string{
+		engine.TokenSourceOAuth: "OAuth device flow",
+		engine.TokenSourcePAT:   "personal access token",
+		engine.TokenSourceGHCLI: "GitHub CLI",
+	}
+	source := sources[m.tokenSource]
+	if source == "" {
+		source = "saved token"
+	}
+	scopes := "checking..."
+	if m.tokenScopesKnown {
+		scopes = m.tokenScopes.String()
+	}
+
+	content := lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("Current token: "+source) + "\n\n" +
+		textStyle.Render("Scopes: "+scopes)
+	warnings := engine.ScopeWarnings(m.tokenScopes, m.tokenSource)
+	switch {
+	case !m.tokenScopesKnown || m.tokenScopes.FineGrained:
+	case len(warnings) > 0:
+		warnStyle := lipgloss.NewStyle().Foreground(colorWarning)
+		lines := make([]string, len(warnings))
+		for i, warning := range warnings {
+			lines[i] = warnStyle.Render("⚠ " + warning)
+		}
+		content += "\n\n" + strings.Join(lines, "\n") + "\n\n" +
+			dimStyle.Render("Replace it with a token with fewer scopes (P), e.g. only 'gist' for cloud sync")
+	case m.tokenSource == engine.TokenSourceOAuth:
+		content += "\n" + dimStyle.Render("The scopes DevBase asks for when signing in")
+	case m.tokenSource != "":
+		content += "\n" + dimStyle.Render("No scopes beyond what DevBase uses")
+	}
+
+	return lipgloss.NewStyle().
+		Width(58).
+		Padding(1, 2).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(colorBorder).
+		Render(content) + "\n\n"
+}
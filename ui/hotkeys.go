@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+)
+
+// defaultRecentHotkeys open the nine most recently used projects
+var defaultRecentHotkeys = []string{"alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9"}
+
+// loadRecentHotkeys reads the quick-switch keys from the "recent_hotkeys" config key: key
+// names separated by commas, assigned to the most recently used projects in order, or "off"
+func loadRecentHotkeys() []string {
+	value, _ := db.GetConfig("recent_hotkeys")
+	switch value = strings.TrimSpace(value); value {
+	case "":
+		return defaultRecentHotkeys
+	case "off":
+		return nil
+	}
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// assignHotkeys gives the keys to the active projects opened most recently, skipping those
+// whose directory is missing, and returns the items with their hotkey set
+func assignHotkeys(items []list.Item, keys []string) []list.Item {
+	var recent []int
+	for i, item := range items {
+		if pi, ok := item.(projectItem); ok && pi.project.Status == "active" && !pi.missing {
+			recent = append(recent, i)
+		}
+	}
+	slices.SortStableFunc(recent, func(a, b int) int {
+		return items[b].(projectItem).project.LastOpened.Compare(items[a].(projectItem).project.LastOpened)
+	})
+
+	hotkeys := make(map[int]string, len(keys))
+	for n, i := range recent {
+		if n == len(keys) {
+			break
+		}
+		hotkeys[i] = keys[n]
+	}
+
+	assigned := make([]list.Item, len(items))
+	for i, item := range items {
+		if pi, ok := item.(projectItem); ok {
+			pi.hotkey = hotkeys[i]
+			item = pi
+		}
+		assigned[i] = item
+	}
+	return assigned
+}
+
+// refreshHotkeys reassigns the quick-switch keys after the list or the open times changed
+func (m *model) refreshHotkeys() tea.Cmd {
+	return m.list.SetItems(assignHotkeys(m.list.Items(), m.recentHotkeys))
+}
+
+// quickSwitch opens the project a quick-switch key is assigned to in its editor, without
+// the editor prompt; ok is false when no project has the key
+func (m model) quickSwitch(key string) (tea.Model, tea.Cmd, bool) {
+	for _, item := range m.list.Items() {
+		pi, isProject := item.(projectItem)
+		if !isProject || pi.hotkey != key {
+			continue
+		}
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Opening %s", pi.project.Name)
+		return m, tea.Batch(openProjectCmd(pi.project, projectEditor(pi.project)), recordOpenCmd(pi.project)), true
+	}
+	return m, nil, false
+}
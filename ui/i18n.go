@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"devbase/db"
+)
+
+// Locales with a message catalog
+const (
+	localeEnglish = "en"
+	localeSpanish = "es"
+)
+
+// catalogs maps a locale to its messages. English is the reference catalog and the
+// fallback for messages missing from the others.
+var catalogs = map[string]map[string]string{
+	localeEnglish: messagesEnglish,
+	localeSpanish: messagesSpanish,
+}
+
+// locale is the active UI locale, set by loadLocale
+var locale = localeEnglish
+
+// loadLocale picks the UI locale from the "language" config key, which the DEVBASE_LANG env
+// var overrides, falling back to the system locale (LC_ALL, LC_MESSAGES, LANG) and English
+func loadLocale() {
+	configured, _ := db.GetConfig("language")
+	if env := os.Getenv("DEVBASE_LANG"); env != "" {
+		configured = env
+	}
+
+	locale = localeEnglish
+	for _, candidate := range []string{configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		// Reduce values like "es_ES.UTF-8" to the language code
+		lang, _, _ := strings.Cut(candidate, ".")
+		lang, _, _ = strings.Cut(lang, "_")
+		lang, _, _ = strings.Cut(lang, "-")
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" {
+			continue
+		}
+		if _, ok := catalogs[lang]; ok {
+			locale = lang
+		}
+		return
+	}
+}
+
+// tr returns the message for key in the active locale, formatted with args when given.
+// Unknown keys are returned as-is so a missing translation is visible but harmless.
+func tr(key string, args ...any) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		if message, ok = messagesEnglish[key]; !ok {
+			message = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+)
+
+// IconMsg is sent when a project's icon was saved
+type IconMsg struct {
+	projectName string
+	icon        string
+	err         error
+}
+
+// openIconEditor starts editing the emoji or short label shown before a project's name
+func (m model) openIconEditor(item projectItem) (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "emoji or label, e.g. 🚀 or API"
+	input.Focus()
+	input.CharLimit = 20
+	input.Width = 40
+	input.SetValue(item.project.Icon)
+
+	itemCopy := item
+	m.iconProject = &itemCopy
+	m.iconInput = input
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// updateIconEditor handles key presses while editing an icon
+func (m model) updateIconEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.iconProject = nil
+		return m, nil
+
+	case "enter":
+		icon := m.iconInput.Value()
+		if err := engine.ValidateIcon(icon); err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		project := m.iconProject.project
+		m.iconProject = nil
+		m.errorMessage = ""
+		return m, func() tea.Msg {
+			return IconMsg{projectName: project.Name, icon: icon, err: engine.SetProjectIcon(project.ID, icon)}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.iconInput, cmd = m.iconInput.Update(msg)
+	return m, cmd
+}
+
+// iconSaved reports the saved icon and reloads the list to show it
+func (m model) iconSaved(msg IconMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to set icon of %s: %v", msg.projectName, msg.err)
+		return m, nil
+	}
+	if msg.icon == "" {
+		m.statusMessage = fmt.Sprintf("Removed the icon of %s", msg.projectName)
+	} else {
+		m.statusMessage = fmt.Sprintf("%s shows as %s %s", msg.projectName, msg.icon, msg.projectName)
+	}
+	return m, reloadProjectsCmd(m.statusFilter)
+}
+
+// viewIconEditor renders the icon input
+func (m model) viewIconEditor() string {
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("✦ ICON: "+m.iconProject.project.Name) + "\n\n" +
+		m.iconInput.View() + "\n\n" +
+		lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(fmt.Sprintf("Shown before the name in the list, at most %d cells wide; empty removes it\nenter=save  esc=cancel", engine.MaxIconWidth))
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// GitHub repositories the new project prompt can create, cycled with tab
+var initRemotes = []string{"", "public", "private"}
+
+// InitProjectMsg is sent when a new project has been created
+type InitProjectMsg struct {
+	project *models.Project
+	err     error
+}
+
+// openInitProject shows the prompt for a new project in the active root folder
+func (m model) openInitProject() (tea.Model, tea.Cmd) {
+	if m.rootScanPath == "" {
+		m.errorMessage = "No scan path configured. Please restart."
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "my-new-project"
+	input.Focus()
+	input.CharLimit = 100
+	input.Width = 40
+
+	m.initInput = input
+	m.initRemote = 0
+	m.showInitProject = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// updateInitProject handles key presses in the new project prompt
+func (m model) updateInitProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.showInitProject = false
+		return m, nil
+
+	case "tab":
+		if token, _ := db.GetConfig("github_token"); token == "" {
+			m.errorMessage = "GitHub authentication required. Press 't' to authenticate with OAuth."
+			return m, nil
+		}
+		m.initRemote = (m.initRemote + 1) % len(initRemotes)
+		return m, nil
+
+	case "enter":
+		name := m.initInput.Value()
+		if name == "" {
+			return m, nil
+		}
+		m.showInitProject = false
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Creating %s...", name)
+		token, _ := db.GetConfig("github_token")
+		return m, initProjectCmd(engine.InitOptions{
+			Name:    name,
+			Root:    m.rootScanPath,
+			GitHub:  initRemotes[m.initRemote] != "",
+			Private: initRemotes[m.initRemote] == "private",
+			Token:   token,
+		})
+	}
+
+	var cmd tea.Cmd
+	m.initInput, cmd = m.initInput.Update(msg)
+	return m, cmd
+}
+
+// initProjectCmd creates a command that creates and registers a new project
+func initProjectCmd(opts engine.InitOptions) tea.Cmd {
+	return func() tea.Msg {
+		project, err := engine.InitProject(opts)
+		return InitProjectMsg{project: project, err: err}
+	}
+}
+
+// projectInitialized reports the new project and reloads the list to show it
+func (m model) projectInitialized(msg InitProjectMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMessage = ""
+		m.errorMessage = fmt.Sprintf("Failed to create project: %v", msg.err)
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Created %s", msg.project.Path)
+	if msg.project.RepoURL != "" {
+		m.statusMessage += " with origin " + msg.project.RepoURL
+	}
+	return m, reloadProjectsCmd(m.statusFilter)
+}
+
+// viewInitProject renders the new project prompt
+func (m model) viewInitProject() string {
+	remote := "no"
+	if r := initRemotes[m.initRemote]; r != "" {
+		remote = r
+	}
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("✚ NEW PROJECT in "+m.rootScanPath) + "\n\n" +
+		m.initInput.View() + "\n\n" +
+		lipgloss.NewStyle().
+			Foreground(colorText).
+			Render("GitHub repository: "+remote) + "\n" +
+		lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("\nenter=create  tab=GitHub repository (no / public / private)  esc=cancel")
+}
@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// inlineRows is how many projects the inline picker shows at once
+const inlineRows = 8
+
+// InlinePicker is a compact project picker rendered without the alternate screen (--inline)
+type InlinePicker struct {
+	input    textinput.Model
+	projects []models.Project
+	targets  []string // Filter values, parallel to projects
+	matches  []int    // Indexes of the projects matching the filter, best first
+	cursor   int
+	width    int
+	selected *models.Project
+	done     bool
+}
+
+// InlineOptions configure an inline picker
+type InlineOptions struct {
+	Query      string // Filter the picker starts with
+	ActiveOnly bool   // Leave out archived projects, whose directories are gone (devbase pick)
+}
+
+// NewInlinePicker creates an inline picker over the local projects of the active root folder.
+// Remote projects are left out since the printed path only makes sense on this machine.
+func NewInlinePicker(opts InlineOptions) (InlinePicker, error) {
+	all, err := db.GetProjects()
+	if err != nil {
+		return InlinePicker{}, fmt.Errorf("failed to load projects: %w", err)
+	}
+	var projects []models.Project
+	for _, project := range all {
+		if project.RemoteHostID != 0 || (opts.ActiveOnly && project.Status == "archived") {
+			continue
+		}
+		projects = append(projects, project)
+	}
+
+	loadTheme()
+	loadLocale()
+
+	input := textinput.New()
+	input.Prompt = "❯ "
+	input.Placeholder = tr("inline.placeholder")
+	input.Focus()
+	input.CharLimit = 100
+	input.SetValue(opts.Query)
+
+	p := InlinePicker{input: input, projects: projects, width: 80}
+	p.targets = make([]string, len(projects))
+	for i, project := range projects {
+		p.targets[i] = encodeFilterValue(project)
+	}
+	p.refilter()
+	return p, nil
+}
+
+// Selected returns the chosen project, if one was picked
+func (p InlinePicker) Selected() (models.Project, bool) {
+	if p.selected == nil {
+		return models.Project{}, false
+	}
+	return *p.selected, true
+}
+
+// SoleMatch picks the project matching the filter when it is the only one, so the picker
+// doesn't need to be shown
+func (p *InlinePicker) SoleMatch() (models.Project, bool) {
+	if len(p.matches) != 1 {
+		return models.Project{}, false
+	}
+	p.pick(p.projects[p.matches[0]])
+	return *p.selected, true
+}
+
+// pick chooses a project and counts it as opened
+func (p *InlinePicker) pick(project models.Project) {
+	p.selected = &project
+	p.done = true
+	if _, err := engine.RecordOpen(project); err != nil {
+		slog.Warn("Failed to record project open", "project", project.Name, "err", err)
+	}
+	_ = db.LogActivity(models.ActivityOpen, project.ID, "inline picker")
+}
+
+// refilter recomputes the matching projects for the current input
+func (p *InlinePicker) refilter() {
+	ranks := filterProjects(p.input.Value(), p.targets)
+	p.matches = make([]int, len(ranks))
+	for i, rank := range ranks {
+		p.matches[i] = rank.Index
+	}
+	p.cursor = min(p.cursor, max(0, len(p.matches)-1))
+}
+
+// Init implements tea.Model
+func (p InlinePicker) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update implements tea.Model
+func (p InlinePicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer recoverCrash()
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+		return p, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			p.done = true
+			return p, tea.Quit
+
+		case "up", "ctrl+p", "ctrl+k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+
+		case "down", "ctrl+n", "ctrl+j":
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+			return p, nil
+
+		case "enter":
+			if len(p.matches) == 0 {
+				return p, nil
+			}
+			p.pick(p.projects[p.matches[p.cursor]])
+			return p, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.refilter()
+	return p, cmd
+}
+
+// View implements tea.Model
+func (p InlinePicker) View() string {
+	// Leave nothing behind in the scrollback once a choice is made
+	if p.done {
+		return ""
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	s := p.input.View() + dimStyle.Render(fmt.Sprintf("  %d/%d", len(p.matches), len(p.projects))) + "\n"
+
+	// Keep the cursor visible within the window of rows
+	start := max(0, p.cursor-inlineRows+1)
+	for i := start; i < len(p.matches) && i < start+inlineRows; i++ {
+		project := p.projects[p.matches[i]]
+		name := project.Name
+		if project.Status == "archived" {
+			name += tr("inline.archived")
+		}
+		line := lipgloss.NewStyle().MaxWidth(p.width).Render(name + dimStyle.Render("  "+project.Path))
+		if i == p.cursor {
+			s += lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("► ") + line + "\n"
+		} else {
+			s += "  " + line + "\n"
+		}
+	}
+	if len(p.matches) == 0 {
+		s += dimStyle.Render(tr("inline.empty")) + "\n"
+	}
+
+	return s + dimStyle.Render(tr("inline.help"))
+}
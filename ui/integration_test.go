@@ -0,0 +1,747 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// harness drives the TUI model like a terminal would: keys go through Update, views are
+// rendered without styling. Commands only run when a test asks for it, so timers and
+// background work never fire on their own.
+type harness struct {
+	t     *testing.T
+	model tea.Model
+}
+
+// newHarness opens an empty database in a temporary directory, lets setup add data, and
+// starts the model at a fixed terminal size
+func newHarness(t *testing.T, setup func()) *harness {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	t.Setenv("AppData", filepath.Join(tempDir, "config"))
+	t.Setenv("DEVBASE_LANG", "en")
+
+	if err := db.InitDB(filepath.Join(tempDir, "test.db")); err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.CloseDB(); err != nil {
+			t.Errorf("Failed to close test database: %v", err)
+		}
+	})
+	if setup != nil {
+		setup()
+	}
+
+	m, err := NewModel()
+	if err != nil {
+		t.Fatalf("NewModel failed: %v", err)
+	}
+	h := &harness{t: t, model: m}
+	h.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return h
+}
+
+// send passes a message to the model and returns the command it asked for
+func (h *harness) send(msg tea.Msg) tea.Cmd {
+	next, cmd := h.model.Update(msg)
+	h.model = next
+	return cmd
+}
+
+// press sends keys by name ("esc", "enter", "down", ...) or as typed text
+func (h *harness) press(keys ...string) tea.Cmd {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		cmd = h.send(keyMsg(key))
+	}
+	return cmd
+}
+
+// run executes a command and sends its message back, as the program loop would
+func (h *harness) run(cmd tea.Cmd) {
+	h.t.Helper()
+	if cmd == nil {
+		h.t.Fatal("Expected a command to run")
+	}
+	h.send(cmd())
+}
+
+// view returns the current screen without ANSI styling
+func (h *harness) view() string {
+	return ansi.Strip(h.model.View())
+}
+
+// expectView fails the test unless the screen shows every text
+func (h *harness) expectView(texts ...string) {
+	h.t.Helper()
+	view := h.view()
+	for _, text := range texts {
+		if !strings.Contains(view, text) {
+			h.t.Errorf("Expected screen to contain %q:\n%s", text, view)
+		}
+	}
+}
+
+// rejectView fails the test if the screen shows any of the texts
+func (h *harness) rejectView(texts ...string) {
+	h.t.Helper()
+	view := h.view()
+	for _, text := range texts {
+		if strings.Contains(view, text) {
+			h.t.Errorf("Expected screen not to contain %q:\n%s", text, view)
+		}
+	}
+}
+
+// keyMsg converts a key name to the message the terminal would send
+func keyMsg(key string) tea.KeyMsg {
+	named := map[string]tea.KeyType{
+		"enter":  tea.KeyEnter,
+		"esc":    tea.KeyEsc,
+		"tab":    tea.KeyTab,
+		"up":     tea.KeyUp,
+		"down":   tea.KeyDown,
+		"space":  tea.KeySpace,
+		"ctrl+p": tea.KeyCtrlP,
+	}
+	if keyType, ok := named[key]; ok {
+		return tea.KeyMsg{Type: keyType}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// addTestProjects adds an active and an archived project
+func addTestProjects(t *testing.T) {
+	for _, p := range []models.Project{
+		{Name: "storefront", Path: filepath.Join(t.TempDir(), "storefront"), Status: "active"},
+		{Name: "legacy-api", Path: filepath.Join(t.TempDir(), "legacy-api"), Status: "archived", RepoURL: "https://github.com/example/legacy-api.git"},
+	} {
+		if err := db.AddProject(&p); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	}
+}
+
+// TestWizardOnEmptyDatabase tests that a new installation starts with the setup wizard
+func TestWizardOnEmptyDatabase(t *testing.T) {
+	h := newHarness(t, nil)
+	h.expectView("Welcome to DevBase", "Where should DevBase keep its database?")
+}
+
+// TestProjectList tests that stored projects are listed
+func TestProjectList(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+	h.expectView("DevBase - Project Manager [All]", "storefront", "legacy-api")
+}
+
+// TestStatusFilterCycle tests cycling the list between all, active and archived projects
+func TestStatusFilterCycle(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+
+	h.run(h.press("v"))
+	h.expectView("[Active]", "storefront")
+	h.rejectView("legacy-api")
+
+	h.run(h.press("v"))
+	h.expectView("[Archived]", "legacy-api")
+	h.rejectView("storefront")
+
+	// The filter is remembered for the next start
+	if filter, _ := db.GetConfig("status_filter"); filter != statusFilterArchived {
+		t.Errorf("Expected status_filter %q to be saved, got %q", statusFilterArchived, filter)
+	}
+}
+
+// TestLogViewer tests opening and closing the log viewer
+func TestLogViewer(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+
+	h.press("L")
+	h.expectView("Errors & Warnings")
+
+	h.press("esc")
+	h.rejectView("Errors & Warnings")
+	h.expectView("storefront")
+}
+
+// TestRecordOpen tests that opening a project updates its list entry once per debounce window
+func TestRecordOpen(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+	projects, _ := db.GetProjectsByName("storefront")
+	if len(projects) != 1 {
+		t.Fatalf("Expected one storefront project, got %d", len(projects))
+	}
+	project := projects[0]
+	id := project.ID
+
+	openCount := func() int {
+		for _, item := range h.model.(model).list.Items() {
+			if pi, ok := item.(projectItem); ok && pi.project.ID == id {
+				return pi.project.OpenCount
+			}
+		}
+		t.Fatal("storefront is not listed")
+		return 0
+	}
+
+	h.run(recordOpenCmd(project))
+	h.run(recordOpenCmd(project))
+	if count := openCount(); count != 1 {
+		t.Errorf("Expected open count 1 in the list, got %d", count)
+	}
+	if stored, _ := db.GetProjectByID(id); stored.OpenCount != 1 {
+		t.Errorf("Expected stored open count 1, got %d", stored.OpenCount)
+	}
+}
+
+// TestLoadingModel tests that the TUI starts before the database is open and hands over
+// to the project list once it is
+func TestLoadingModel(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	t.Setenv("AppData", filepath.Join(tempDir, "config"))
+	t.Setenv("DEVBASE_LANG", "en")
+	defer db.CloseDB()
+
+	h := &harness{t: t, model: NewLoadingModel(context.Background(), func() error {
+		if err := db.InitDB(filepath.Join(tempDir, "test.db")); err != nil {
+			return err
+		}
+		addTestProjects(t)
+		return nil
+	})}
+	h.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	h.expectView("Loading projects")
+
+	h.run(h.model.Init())
+	if _, ok := h.model.(model); !ok {
+		t.Fatalf("Expected the project list after loading, got %T", h.model)
+	}
+	h.expectView("storefront", "legacy-api")
+	if err := StartupError(h.model); err != nil {
+		t.Errorf("Expected no startup error, got %v", err)
+	}
+}
+
+// TestLoadingModelError tests that a database that can't be opened ends the program
+func TestLoadingModelError(t *testing.T) {
+	h := &harness{t: t, model: NewLoadingModel(context.Background(), func() error { return fmt.Errorf("disk full") })}
+	cmd := h.model.Init()
+	if quit := h.send(cmd()); quit == nil {
+		t.Fatal("Expected the program to quit")
+	}
+	if err := StartupError(h.model); err == nil || err.Error() != "disk full" {
+		t.Errorf("Expected the open error, got %v", err)
+	}
+}
+
+// TestRecentHotkeys tests that the most recently used projects get quick-switch keys
+func TestRecentHotkeys(t *testing.T) {
+	h := newHarness(t, func() {
+		addTestProjects(t)
+		older := models.Project{Name: "billing", Path: filepath.Join(t.TempDir(), "billing"), LastOpened: time.Now().Add(-time.Hour)}
+		if err := db.AddProject(&older); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+	h.expectView("storefront ‹alt+1›", "billing ‹alt+2›")
+	h.rejectView("legacy-api ‹")
+
+	if cmd := h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2"), Alt: true}); cmd == nil {
+		t.Error("Expected alt+2 to open billing")
+	}
+	h.expectView("Opening billing")
+	if cmd := h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3"), Alt: true}); cmd != nil {
+		t.Error("Expected alt+3 to do nothing without a third project")
+	}
+
+	if err := db.SetConfig("recent_hotkeys", "F1, f2,f1"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := loadRecentHotkeys(); !slices.Equal(keys, []string{"f1", "f2"}) {
+		t.Errorf("Expected keys f1 and f2, got %v", keys)
+	}
+	if err := db.SetConfig("recent_hotkeys", "off"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := loadRecentHotkeys(); keys != nil {
+		t.Errorf("Expected no keys when off, got %v", keys)
+	}
+}
+
+// TestFooterStats tests the summary line under the project list
+func TestFooterStats(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+	h.rejectView("never scanned")
+
+	h.run(collectFooterStats(h.model.(model).ctx))
+	h.expectView("2 projects (1 active, 1 archived)", "💾 0 B", "never scanned")
+
+	if err := db.LogActivity(models.ActivityScan, 0, "found 2"); err != nil {
+		t.Fatal(err)
+	}
+	h.run(collectFooterStats(h.model.(model).ctx))
+	h.expectView("scanned just now")
+}
+
+// TestGitMissing tests the degraded mode without git: a warning and disabled clone keys
+func TestGitMissing(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+	t.Setenv("PATH", t.TempDir())
+
+	h.run(checkDependenciesCmd())
+	h.expectView("git not found — install git", "VS Code CLI not found — install 'code' or set editor in settings")
+
+	if cmd := h.press("r"); cmd != nil {
+		t.Error("Expected r to do nothing without git")
+	}
+	h.expectView("⚠ git not found")
+	h.press("g")
+	h.rejectView("CLONE GITHUB REPOSITORY")
+}
+
+// TestRunOutputPane tests streaming a run into the output pane and returning to the list
+// while it runs
+func TestRunOutputPane(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Runs through sh")
+	}
+	h := newHarness(t, func() {
+		project := models.Project{Name: "storefront", Path: t.TempDir(), Status: "active"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+
+	h.press("F")
+	h.expectView("Nothing runs in the output pane")
+
+	h.press("x", "c", "c")
+	h.expectView("Output: output pane in DevBase")
+	h.press("enter", "echo streamed")
+	h.run(h.press("enter"))
+	h.expectView("Run Output", "storefront  $ echo streamed")
+
+	m := h.model.(model)
+	<-m.streamRun.Done()
+	h.send(runStreamTickMsg{id: m.streamTick})
+	h.expectView("streamed", "exited with code 0")
+
+	h.press("esc")
+	h.rejectView("Run Output", "▶ echo streamed running")
+	h.press("F")
+	h.expectView("Run Output", "exited with code 0")
+}
+
+// TestRunningProjects tests the running badge of a captured run, refusing to start it twice
+// and stopping it from the run picker
+func TestRunningProjects(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Runs through sh")
+	}
+	h := newHarness(t, func() {
+		project := models.Project{Name: "storefront", Path: t.TempDir(), Status: "active"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+
+	command := `echo "Listening on port 4321"; sleep 30`
+	h.press("x", "c")
+	h.expectView("Output: run log")
+	h.press("enter", command)
+	h.run(h.press("enter"))
+
+	// The badge shows once the run reported its port
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		h.send(checkDevRunsCmd()())
+		if strings.Contains(h.view(), "● running :4321") {
+			break
+		}
+	}
+	h.expectView("storefront ● running :4321")
+
+	// The same command can't start twice
+	h.press("x")
+	h.expectView("● Running: "+command, "port 4321", "s=stop  r=restart")
+	h.press("enter", command, "enter")
+	h.expectView("is already running", "press s to stop it or r to restart it")
+
+	h.press("esc")
+	h.run(h.press("s"))
+	h.expectView("Stopped " + command + " in storefront")
+	h.send(checkDevRunsCmd()())
+	h.rejectView("● running")
+}
+
+// TestLockedProject tests locking a project, which refuses archiving and clearing it
+func TestLockedProject(t *testing.T) {
+	h := newHarness(t, func() {
+		project := models.Project{Name: "monorepo", Path: t.TempDir(), Status: "active"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+
+	h.run(h.press("U"))
+	h.expectView("Locked monorepo")
+	h.run(reloadProjectsCmd(""))
+	h.expectView("🔒 monorepo")
+
+	h.press("d")
+	h.expectView("monorepo is locked; unlock it with U before archiving")
+
+	// Clearing is refused while a project is locked
+	h.press("c", "CLEAR", "enter")
+	h.expectView("Refused while these projects are locked: monorepo", "Unlock monorepo (U) first")
+	if _, err := db.DeleteAllProjects(); !errors.Is(err, db.ErrProjectLocked) {
+		t.Errorf("Expected the database to refuse clearing, got %v", err)
+	}
+}
+
+// TestProjectIcon tests setting the icon shown before a project's name
+func TestProjectIcon(t *testing.T) {
+	h := newHarness(t, func() {
+		project := models.Project{Name: "storefront", Path: t.TempDir(), Status: "active"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+
+	h.press("I", "TOO-LONG", "enter")
+	h.expectView("is 8 cells wide, at most 6 fit")
+
+	h.press("esc")
+	h.run(h.press("I", "🛒", "enter"))
+	h.expectView("storefront shows as 🛒 storefront")
+	h.run(reloadProjectsCmd(""))
+	h.expectView("🛒 storefront")
+}
+
+// TestRestoreTo tests that tab puts an archived project into a root folder before restoring
+// it there
+func TestRestoreTo(t *testing.T) {
+	var project models.Project
+	var rootPath string
+	h := newHarness(t, func() {
+		rootPath = t.TempDir()
+		if err := db.AddRootFolder(&models.RootFolder{Name: "work", Path: rootPath}); err != nil {
+			t.Fatalf("AddRootFolder failed: %v", err)
+		}
+		project = models.Project{Name: "ledger", Path: filepath.Join(t.TempDir(), "old", "ledger"), Status: "archived"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+
+	h.press("A")
+	h.expectView("RESTORE TO: ledger")
+	h.expectView("original location")
+	h.press("tab")
+	h.expectView("root folder work")
+
+	// Without a repository URL the restore fails and the project stays where it was
+	h.run(h.press("enter"))
+	h.expectView("no repository URL")
+	if stored, _ := db.GetProjectByID(project.ID); stored.Path != project.Path {
+		t.Errorf("Expected the failed restore to keep %s, got %s", project.Path, stored.Path)
+	}
+}
+
+// TestLayoutPanes tests toggling and resizing the detail and notes panes next to the list,
+// and that the layout is kept in config
+func TestLayoutPanes(t *testing.T) {
+	h := newHarness(t, func() {
+		project := models.Project{Name: "storefront", Path: filepath.Join(t.TempDir(), "storefront"), Status: "active", Language: "typescript", Notes: "- web on 5173"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+	h.expectView("Language: typescript", "Notes (N to edit)", "• web on 5173")
+
+	h.press("n")
+	h.expectView("Notes pane hidden", "Language: typescript")
+	h.rejectView("web on 5173")
+	h.press("{")
+	h.expectView("press D and n to show both")
+
+	h.press("n", "}")
+	h.expectView("Notes height 50%", "web on 5173")
+	h.press("D")
+	h.expectView("Detail pane hidden", "web on 5173")
+	h.rejectView("Language: typescript")
+	h.press("]")
+	h.expectView("List width 65%")
+
+	// Editing opens in the notes pane
+	h.press("N")
+	h.expectView("✎ NOTES: storefront")
+	h.press("esc")
+
+	h.press("D")
+	for key, want := range map[string]string{"layout_detail": "true", "layout_notes": "true", "layout_list_ratio": "65", "layout_notes_ratio": "50"} {
+		if value, err := db.GetConfig(key); err != nil || value != want {
+			t.Errorf("%s = %q (%v), want %q", key, value, err, want)
+		}
+	}
+	if l := loadLayout(); l != (layout{showDetail: true, showNotes: true, listRatio: 65, notesRatio: 50}) {
+		t.Errorf("loadLayout() = %+v after saving", l)
+	}
+}
+
+// TestRetryFailedRestore tests that a failed restore stays in the detail pane and E retries it
+func TestRetryFailedRestore(t *testing.T) {
+	h := newHarness(t, func() {
+		project := models.Project{Name: "ledger", Path: filepath.Join(t.TempDir(), "ledger"), Status: "archived"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+		if err := db.UpdateProjectLastError(project.ID, "restore failed: network down"); err != nil {
+			t.Fatalf("UpdateProjectLastError failed: %v", err)
+		}
+	})
+
+	h.expectView("network down")
+	h.expectView("E or r to retry")
+
+	cmd := h.press("E")
+	h.expectView("Restoring project...")
+	h.run(cmd)
+	h.expectView("Restore failed: the project has no repository URL")
+	h.run(reloadProjectsCmd(""))
+	h.expectView("restore failed: project has no")
+}
+
+// TestRunConfigOffer tests that a cloned project without a devbase.yaml is offered one from
+// its stack
+func TestRunConfigOffer(t *testing.T) {
+	dir := t.TempDir()
+	h := newHarness(t, func() {
+		project := models.Project{Name: "cli", Path: dir, Status: "active"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+	if err := os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte("[package]\nname = \"cli\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h.run(suggestRunConfigCmd("cli", dir))
+	h.expectView("cli has no devbase.yaml", "test: cargo test", "y=write")
+	h.run(h.press("y"))
+	h.expectView("Wrote " + filepath.Join(dir, "devbase.yaml"))
+
+	// Projects with one aren't offered another
+	if msg := suggestRunConfigCmd("cli", dir)(); msg != nil {
+		t.Errorf("Expected no offer for a project with a devbase.yaml, got %#v", msg)
+	}
+}
+
+// TestRestoreCloneOptions tests setting the clone options a project is restored with next to
+// its restore ref
+func TestRestoreCloneOptions(t *testing.T) {
+	var project models.Project
+	h := newHarness(t, func() {
+		project = models.Project{Name: "monorepo", Path: t.TempDir(), Status: "active"}
+		if err := db.AddProject(&project); err != nil {
+			t.Fatalf("AddProject failed: %v", err)
+		}
+	})
+
+	h.press("B")
+	h.expectView("global: depth=1")
+	h.press("tab", "depth=lots", "enter")
+	h.expectView(`invalid clone depth "lots"`)
+
+	for range "lots" {
+		h.press("backspace")
+	}
+	h.run(h.press("20, filter=blob:none", "enter"))
+	h.expectView("monorepo restores at the default branch (depth=20, filter=blob:none)")
+	if stored, _ := db.GetProjectByID(project.ID); stored.CloneOptions != "depth=20, filter=blob:none" {
+		t.Errorf("Expected the clone options to be stored, got %q", stored.CloneOptions)
+	}
+}
+
+// TestPickProject tests the inline picker as started by devbase pick: archived projects are
+// left out and a query matching one project picks it without showing the picker
+func TestPickProject(t *testing.T) {
+	newHarness(t, func() {
+		for _, p := range []models.Project{
+			{Name: "api", Path: "/code/api", Status: "active"},
+			{Name: "api-legacy", Path: "/code/api-legacy", Status: "archived"},
+			{Name: "web", Path: "/code/web", Status: "active"},
+		} {
+			if err := db.AddProject(&p); err != nil {
+				t.Fatalf("AddProject failed: %v", err)
+			}
+		}
+	})
+
+	picker, err := NewInlinePicker(InlineOptions{Query: "api"})
+	if err != nil {
+		t.Fatalf("NewInlinePicker failed: %v", err)
+	}
+	if _, ok := picker.SoleMatch(); ok {
+		t.Error("Expected no sole match while archived projects are listed")
+	}
+
+	picker, err = NewInlinePicker(InlineOptions{Query: "api", ActiveOnly: true})
+	if err != nil {
+		t.Fatalf("NewInlinePicker failed: %v", err)
+	}
+	if project, ok := picker.SoleMatch(); !ok || project.Path != "/code/api" {
+		t.Errorf("Expected api to be picked, got %v (%v)", project.Path, ok)
+	}
+
+	picker, _ = NewInlinePicker(InlineOptions{ActiveOnly: true})
+	if view := picker.View(); !strings.Contains(view, "2/2") || strings.Contains(view, "api-legacy") {
+		t.Errorf("Expected only the active projects, got:\n%s", view)
+	}
+	model, _ := picker.Update(keyMsg("down"))
+	model, _ = model.Update(keyMsg("enter"))
+	if project, ok := model.(InlinePicker).Selected(); !ok || project.Name != "web" {
+		t.Errorf("Expected web to be picked, got %v (%v)", project.Name, ok)
+	}
+}
+
+// TestClearProjects tests that clearing shows what it removes, needs the phrase typed and
+// only clears the active root folder unless switched to all of them
+func TestClearProjects(t *testing.T) {
+	h := newHarness(t, func() {
+		work := &models.RootFolder{Name: "Work", Path: "/work"}
+		home := &models.RootFolder{Name: "Home", Path: "/home"}
+		for _, root := range []*models.RootFolder{work, home} {
+			if err := db.AddRootFolder(root); err != nil {
+				t.Fatalf("AddRootFolder failed: %v", err)
+			}
+		}
+		if err := db.SetActiveRootFolder(work.ID); err != nil {
+			t.Fatalf("SetActiveRootFolder failed: %v", err)
+		}
+		for _, p := range []models.Project{
+			{Name: "api", Path: "/work/api", Status: "active", RootFolderID: work.ID},
+			{Name: "legacy", Path: "/work/legacy", Status: "archived", RootFolderID: work.ID},
+			{Name: "blog", Path: "/home/blog", Status: "active", RootFolderID: home.ID},
+		} {
+			if err := db.AddProject(&p); err != nil {
+				t.Fatalf("AddProject failed: %v", err)
+			}
+		}
+	})
+
+	h.press("c")
+	h.expectView("Clear the projects of Work from DevBase?", "2 projects would be removed (1 active, 1 archived)", "Tab: every root folder")
+	h.press("tab")
+	h.expectView("Clear ALL projects of every root folder", "3 projects would be removed (2 active, 1 archived)", "setup starts again")
+	h.press("tab", "CLEA", "enter")
+	h.expectView("You must type 'CLEAR' exactly to confirm")
+	if summary, _ := db.SummarizeClear(0); summary.Active+summary.Archived != 3 {
+		t.Fatalf("Expected nothing cleared before confirming, got %+v", summary)
+	}
+
+	h.press("R")
+	h.run(h.press("enter"))
+	h.expectView("Cleared 2 projects of Work")
+	if summary, _ := db.SummarizeClear(0); summary.Active != 1 || summary.Archived != 0 {
+		t.Errorf("Expected only the other root folder's project kept, got %+v", summary)
+	}
+	if _, err := db.GetRootFolderByPath("/work"); err != nil {
+		t.Errorf("Expected the root folder kept, got %v", err)
+	}
+}
+
+// TestScanReport tests the report of what a scan skipped, with collapsible sections
+func TestScanReport(t *testing.T) {
+	var root models.RootFolder
+	h := newHarness(t, func() {
+		root = models.RootFolder{Name: "code", Path: t.TempDir()}
+		if err := db.AddRootFolder(&root); err != nil {
+			t.Fatalf("AddRootFolder failed: %v", err)
+		}
+		if err := db.SetConfig("scanner_ignore", "scratch"); err != nil {
+			t.Fatalf("SetConfig failed: %v", err)
+		}
+		addTestProjects(t)
+	})
+	for _, dir := range []string{"app", filepath.Join("scratch", "tool")} {
+		if err := os.MkdirAll(filepath.Join(root.Path, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root.Path, dir, "go.mod"), []byte("module x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h.press("Y")
+	h.expectView("No scan has finished yet")
+
+	msg, err := scanRootFolder(context.Background(), root.ID, root.Path)
+	if err != nil {
+		t.Fatalf("scanRootFolder failed: %v", err)
+	}
+	h.run(h.send(msg))
+	h.expectView("Found 1 projects, added 1 new (1 ignored; Y=report)")
+
+	h.press("Y")
+	h.expectView("Scan Report", "▾ Skipped by ignore rules", "scratch", "scanner_ignore", "▸ Already registered")
+	h.press("enter")
+	h.expectView("▸ Skipped by ignore rules")
+	h.rejectView("scanner_ignore")
+
+	h.press("esc")
+	h.rejectView("Scan Report")
+	h.expectView("app")
+}
+
+// TestTrash tests that the trash screen lists an archived checkout with when it is purged,
+// and purges it on request
+func TestTrash(t *testing.T) {
+	h := newHarness(t, func() { addTestProjects(t) })
+	if err := db.SetConfig("archive_trash_days", "30"); err != nil {
+		t.Fatal(err)
+	}
+	project := models.Project{Name: "notes", Path: filepath.Join(t.TempDir(), "notes"), Status: "active"}
+	if err := os.MkdirAll(project.Path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddProject(&project); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.ArchiveProject(project.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	h.press("ctrl+p", "trash")
+	h.run(h.press("enter"))
+	h.expectView("Archive Trash", "1 archived projects", "notes", "purged in 29d", "keeping archived checkouts for 30 days")
+
+	h.press("x")
+	h.expectView("Delete the archived checkout of notes")
+	h.run(h.send(h.press("y")()))
+	h.expectView("Purged 1 archived projects", "The trash is empty")
+
+	h.press("esc")
+	h.rejectView("Archive Trash")
+}
@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+)
+
+// jobsTickMsg refreshes the jobs screen; ticks of an earlier screen are ignored
+type jobsTickMsg struct {
+	id int
+}
+
+// jobCmd creates a command that runs work as a job that can't be cancelled, listed on the
+// jobs screen (J). The job ends with the error work returns; its message is delivered as usual.
+func (m model) jobCmd(kind, title string, work func(id int) (tea.Msg, error)) tea.Cmd {
+	id := m.jobs.Start(kind, title)
+	return func() tea.Msg {
+		msg, err := work(id)
+		m.jobs.Finish(id, err)
+		return msg
+	}
+}
+
+// cancellableJobCmd creates a command that runs work as a job that can be cancelled from
+// the jobs screen. work must stop when ctx is done; ctx also ends when DevBase exits.
+func (m model) cancellableJobCmd(kind, title string, work func(ctx context.Context, id int) (tea.Msg, error)) tea.Cmd {
+	ctx, id := m.jobs.StartCancellable(m.ctx, kind, title)
+	return func() tea.Msg {
+		msg, err := work(ctx, id)
+		m.jobs.Finish(id, err)
+		return msg
+	}
+}
+
+// jobRunning reports whether a job of the kind is running
+func (m model) jobRunning(kind string) bool {
+	return len(m.jobs.Running(kind)) > 0
+}
+
+// jobsTickCmd schedules the next refresh of the jobs screen
+func jobsTickCmd(id int) tea.Cmd {
+	return tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg {
+		return jobsTickMsg{id: id}
+	})
+}
+
+// openJobs shows the running and recently finished background jobs
+func (m model) openJobs() (tea.Model, tea.Cmd) {
+	m.jobCursor = 0
+	m.jobTick++
+	m.screen = screenJobs
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, jobsTickCmd(m.jobTick)
+}
+
+// updateJobs handles updates for the jobs screen. The jobs are read from the manager on
+// every render, so ticks only need to trigger one.
+func (m model) updateJobs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case jobsTickMsg:
+		if msg.id != m.jobTick {
+			return m, nil
+		}
+		return m, jobsTickCmd(m.jobTick)
+
+	case tea.KeyMsg:
+		jobs := m.jobs.Jobs()
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "J":
+			m.screen = screenList
+			m.jobTick++
+			return m, nil
+
+		case "up", "k":
+			if m.jobCursor > 0 {
+				m.jobCursor--
+			}
+
+		case "down", "j":
+			if m.jobCursor < len(jobs)-1 {
+				m.jobCursor++
+			}
+
+		case "c":
+			if m.jobCursor >= len(jobs) {
+				return m, nil
+			}
+			job := jobs[m.jobCursor]
+			if job.State != engine.JobRunning {
+				return m, nil
+			}
+			if !m.jobs.Cancel(job.ID) {
+				m.errorMessage = fmt.Sprintf("%s can't be cancelled", job.Title)
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.statusMessage = fmt.Sprintf("Cancelling %s...", job.Title)
+
+		case "d":
+			// Forget the finished jobs
+			m.jobs.ClearFinished()
+			m.jobCursor = 0
+			m.statusMessage = ""
+		}
+	}
+
+	return m, nil
+}
+
+// jobsPageSize returns how many jobs fit on screen
+func (m model) jobsPageSize() int {
+	return max(5, m.height-12)
+}
+
+// jobProgress describes how far a job got, e.g. "3/10 api" or "done in 4s"
+func jobProgress(job engine.Job) string {
+	elapsed := job.Elapsed().Round(time.Second)
+	switch job.State {
+	case engine.JobRunning:
+		progress := elapsed.String()
+		if job.Total > 0 {
+			progress = fmt.Sprintf("%d/%d  %s", job.Done, job.Total, progress)
+		}
+		if job.Cancelling {
+			progress += "  cancelling"
+		}
+		if job.Detail != "" {
+			progress += "  " + job.Detail
+		}
+		return progress
+	case engine.JobFailed:
+		return fmt.Sprintf("failed after %s: %v", elapsed, job.Err)
+	}
+	return fmt.Sprintf("%s in %s", job.State, elapsed)
+}
+
+// viewJobs renders the jobs screen, running jobs first
+func (m model) viewJobs() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Background Jobs")
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	jobs := m.jobs.Jobs()
+	if len(jobs) == 0 {
+		s += dimStyle.Render("No jobs yet; scans, clones, syncs, archiving and size calculations show up here") + "\n"
+	}
+
+	cursor := min(m.jobCursor, max(0, len(jobs)-1))
+	pageSize := m.jobsPageSize()
+	start := max(0, cursor-pageSize+1)
+	end := min(len(jobs), start+pageSize)
+	for i, job := range jobs[start:end] {
+		index := start + i
+		prefix := "  "
+		if index == cursor {
+			prefix = "► "
+		}
+
+		marker, markerStyle := "✓", lipgloss.NewStyle().Foreground(colorSuccess)
+		switch job.State {
+		case engine.JobRunning:
+			marker, markerStyle = "⟳", lipgloss.NewStyle().Foreground(colorAccent)
+		case engine.JobFailed:
+			marker, markerStyle = "✗", errorStyle
+		case engine.JobCancelled:
+			marker, markerStyle = "-", dimStyle
+		}
+
+		style := lipgloss.NewStyle().Foreground(colorText)
+		if index == cursor {
+			style = style.Background(colorSelection).Foreground(colorSelectionText).Bold(true)
+		}
+		s += style.Render(prefix) + markerStyle.Render(marker) + " " +
+			style.Render(fmt.Sprintf("%-40s", job.Title)) + " " +
+			dimStyle.Render(jobProgress(job)) + "\n"
+	}
+	if end < len(jobs) {
+		s += dimStyle.Render(fmt.Sprintf("… %d more", len(jobs)-end)) + "\n"
+	}
+
+	s += dimStyle.Render("\n↑↓=move  c=cancel  d=clear finished  esc=back")
+	if m.statusMessage != "" {
+		s += lipgloss.NewStyle().Foreground(colorSuccessDim).Render("\n✓ " + m.statusMessage)
+	}
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+	return docStyle.Render(s)
+}
+
+// viewJobsIndicator renders the running jobs line under the list, empty when none run. A
+// running scan keeps its own line.
+func (m model) viewJobsIndicator() string {
+	var s string
+	if m.jobRunning(engine.JobScan) {
+		s += lipgloss.NewStyle().
+			Foreground(colorSuccess).
+			Bold(true).
+			Render("\n\n" + tr("list.scanning"))
+	}
+
+	var others []engine.Job
+	for _, job := range m.jobs.Running("") {
+		if job.Kind != engine.JobScan {
+			others = append(others, job)
+		}
+	}
+	switch len(others) {
+	case 0:
+	case 1:
+		s += lipgloss.NewStyle().
+			Foreground(colorSuccess).
+			Render("\n" + tr("list.job", others[0].Title))
+	default:
+		s += lipgloss.NewStyle().
+			Foreground(colorSuccess).
+			Render("\n" + tr("list.jobs", len(others)))
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/engine"
+	"devbase/models"
+)
+
+// LastOpenedMsg is sent when an open of a project has been recorded
+type LastOpenedMsg struct {
+	projectID uint
+	counted   bool // False when the project was opened within db.OpenDebounce
+	at        time.Time
+	err       error
+}
+
+// recordOpenCmd creates a command that updates LastOpened and the open count of a project,
+// debounced by db.OpenDebounce
+func recordOpenCmd(project models.Project) tea.Cmd {
+	return func() tea.Msg {
+		counted, err := engine.RecordOpen(project)
+		return LastOpenedMsg{projectID: project.ID, counted: counted, at: time.Now(), err: err}
+	}
+}
+
+// openRecorded shows the new LastOpened and open count in the list. The list isn't
+// reloaded, so the selection stays on the opened project until the next reload sorts it.
+func (m model) openRecorded(msg LastOpenedMsg) (tea.Model, tea.Cmd) {
+	index, item := -1, projectItem{}
+	for i, listItem := range m.list.Items() {
+		if pi, ok := listItem.(projectItem); ok && pi.project.ID == msg.projectID {
+			index, item = i, pi
+			break
+		}
+	}
+	// Work in the opened project changes its branch and worktree
+	if index >= 0 && item.remoteHost == "" {
+		engine.InvalidateGitInfo(hostPath(item.project.Path))
+	}
+
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to record project open: %v", msg.err)
+		return m, nil
+	}
+	if !msg.counted || index < 0 {
+		return m, nil
+	}
+	item.project.LastOpened = msg.at
+	item.project.OpenCount++
+	cmd := m.list.SetItem(index, item)
+	return m, tea.Batch(cmd, m.refreshHotkeys())
+}
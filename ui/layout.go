@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+)
+
+// Layout limits for the list/side pane split and the detail/notes split
+const (
+	defaultListRatio  = 60  // Percentage of the width given to the project list
+	minListRatio      = 30  // Smallest list share when resizing
+	maxListRatio      = 80  // Largest list share when resizing
+	listRatioStep     = 5   // Percentage points per resize key press
+	minSplitWidth     = 100 // Below this width the side panes are hidden to keep the list usable
+	defaultNotesRatio = 40  // Percentage of the side column's height given to the notes pane
+	minNotesRatio     = 20  // Smallest notes share when resizing
+	maxNotesRatio     = 80  // Largest notes share when resizing
+	notesRatioStep    = 10  // Percentage points per notes resize key press
+)
+
+// layout holds the pane preferences for the project list screen. The detail and notes panes
+// share a column next to the list, the notes below the details.
+type layout struct {
+	showDetail bool // Show the detail pane next to the list
+	showNotes  bool // Show the notes pane next to the list
+	listRatio  int  // Percentage of the width used by the list when a side pane is shown
+	notesRatio int  // Percentage of the side column's height used by the notes pane when both are shown
+}
+
+// loadLayout reads the pane preferences from config, falling back to defaults
+func loadLayout() layout {
+	l := layout{showDetail: true, showNotes: true, listRatio: defaultListRatio, notesRatio: defaultNotesRatio}
+	if value, err := db.GetConfig("layout_detail"); err == nil && value != "" {
+		l.showDetail = value == "true"
+	}
+	if value, err := db.GetConfig("layout_notes"); err == nil && value != "" {
+		l.showNotes = value == "true"
+	}
+	if value, err := db.GetConfig("layout_list_ratio"); err == nil && value != "" {
+		if ratio, err := strconv.Atoi(value); err == nil {
+			l.listRatio = clampRatio(ratio)
+		}
+	}
+	if value, err := db.GetConfig("layout_notes_ratio"); err == nil && value != "" {
+		if ratio, err := strconv.Atoi(value); err == nil {
+			l.notesRatio = clampNotesRatio(ratio)
+		}
+	}
+	return l
+}
+
+// save persists the pane preferences to config
+func (l layout) save() error {
+	for key, value := range map[string]string{
+		"layout_detail":      strconv.FormatBool(l.showDetail),
+		"layout_notes":       strconv.FormatBool(l.showNotes),
+		"layout_list_ratio":  strconv.Itoa(l.listRatio),
+		"layout_notes_ratio": strconv.Itoa(l.notesRatio),
+	} {
+		if err := db.SetConfig(key, value); err != nil {
+			return fmt.Errorf("failed to save layout: %w", err)
+		}
+	}
+	return nil
+}
+
+// clampRatio keeps a list ratio within the allowed resize range
+func clampRatio(ratio int) int {
+	return max(minListRatio, min(maxListRatio, ratio))
+}
+
+// clampNotesRatio keeps a notes ratio within the allowed resize range
+func clampNotesRatio(ratio int) int {
+	return max(minNotesRatio, min(maxNotesRatio, ratio))
+}
+
+// split returns the list and side column widths for the available width.
+// The side width is zero when both side panes are hidden or the terminal is too narrow.
+func (l layout) split(width int) (listWidth, sideWidth int) {
+	if !(l.showDetail || l.showNotes) || width < minSplitWidth {
+		return width, 0
+	}
+	listWidth = width * l.listRatio / 100
+	return listWidth, width - listWidth
+}
+
+// splitSide returns the detail and notes pane heights for the side column's height. A pane
+// that is hidden gets zero and the other one the whole column.
+func (l layout) splitSide(height int) (detailHeight, notesHeight int) {
+	switch {
+	case !l.showNotes:
+		return height, 0
+	case !l.showDetail:
+		return 0, height
+	}
+	notesHeight = height * l.notesRatio / 100
+	return height - notesHeight, notesHeight
+}
+
+// listSize returns the project list dimensions for the current window and layout
+func (m model) listSize() (int, int) {
+	listWidth, _ := m.layout.split(m.width - 4)
+	listHeight := m.height - 9 - len(m.missingDeps)
+	if listHeight < 10 {
+		listHeight = 10
+	}
+	return listWidth, listHeight
+}
+
+// applyLayout resizes the list and persists the pane preferences after a layout change
+func (m model) applyLayout(status string) model {
+	m.list.SetSize(m.listSize())
+	if m.editingNotes {
+		width, height := m.notesEditorSize()
+		m.notesInput.SetWidth(width)
+		m.notesInput.SetHeight(height)
+	}
+	if err := m.layout.save(); err != nil {
+		m.errorMessage = err.Error()
+		return m
+	}
+	m.errorMessage = ""
+	m.statusMessage = status
+	return m
+}
+
+// viewSidePanes renders the detail and notes panes shown next to the list, stacked
+func (m model) viewSidePanes(width, height int) string {
+	detailHeight, notesHeight := m.layout.splitSide(height)
+	var panes []string
+	if detailHeight > 0 {
+		panes = append(panes, m.viewDetailPane(width, detailHeight))
+	}
+	if notesHeight > 0 {
+		panes = append(panes, m.viewNotesPane(width, notesHeight))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, panes...)
+}
+
+// viewDetailPane renders details of the selected project for the right-hand pane
+func (m model) viewDetailPane(width, height int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(colorAccent)
+	valueStyle := lipgloss.NewStyle().Foreground(colorText)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	var s string
+	if m.editingNotes && !m.layout.showNotes {
+		s = m.viewNotesEditor()
+	} else if item, ok := m.list.SelectedItem().(projectItem); ok {
+		p := item.project
+		field := func(label, value string) string {
+			if value == "" {
+				value = dimStyle.Render("-")
+			} else {
+				value = valueStyle.Render(value)
+			}
+			return labelStyle.Render(label+": ") + value + "\n"
+		}
+
+		s = titleStyle.Render(p.Name) + "\n\n"
+		status := p.Status
+		if item.missing {
+			status += " (directory missing)"
+		}
+		if p.Locked {
+			status += " (locked)"
+		}
+		s += field("Status", status)
+		s += field("Language", p.Language)
+		if item.remoteHost != "" {
+			s += field("Host", item.remoteHost)
+		}
+		if p.Machine != "" {
+			s += field("Machine", p.Machine)
+		}
+		s += field("Path", p.Path)
+		s += field("Repo", p.RepoURL)
+		for _, line := range m.repoMetaLines(p) {
+			s += "  " + dimStyle.Render(line) + "\n"
+		}
+		if branch := item.meta.Branch; branch != "" {
+			if item.meta.Dirty {
+				branch += " (uncommitted changes)"
+			}
+			s += field("Branch", branch)
+		}
+		s += field("Tags", strings.Join(p.Tags, ", "))
+		if p.Icon != "" {
+			s += field("Icon", p.Icon)
+		}
+		if p.RestoreRef != "" {
+			s += field("Restore ref", p.RestoreRef)
+		}
+		if p.CloneOptions != "" {
+			s += field("Clone options", p.CloneOptions)
+		}
+		if p.Editor != "" {
+			s += field("Editor", projectEditor(p).Name)
+		}
+		if !p.LastOpened.IsZero() {
+			s += field("Last opened", p.LastOpened.Format(time.DateTime))
+		}
+		if p.LastError != "" {
+			retry := "d"
+			if p.Status == "archived" {
+				retry = "r"
+			}
+			s += labelStyle.Render("Last error: ") + errorStyle.Render(p.LastError) + "\n" +
+				"  " + dimStyle.Render(fmt.Sprintf("%s, E or %s to retry", p.LastErrorAt.Format(time.DateTime), retry)) + "\n"
+		}
+		for _, run := range item.runs {
+			s += field("Running", describeDevRun(run))
+		}
+	} else {
+		s = dimStyle.Render("No project selected")
+	}
+	return renderSidePane(s, width, height)
+}
+
+// viewNotesPane renders the notes of the selected project, or the notes editor while it is open
+func (m model) viewNotesPane(width, height int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(colorAccent)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	var s string
+	if m.editingNotes {
+		s = m.viewNotesEditor()
+	} else if item, ok := m.list.SelectedItem().(projectItem); ok {
+		s = labelStyle.Render("Notes") + dimStyle.Render(" (N to edit)") + "\n"
+		if item.project.Notes == "" {
+			s += dimStyle.Render("No notes yet")
+		} else {
+			s += renderMarkdown(item.project.Notes)
+		}
+	} else {
+		s = dimStyle.Render("No project selected")
+	}
+	return renderSidePane(s, width, height)
+}
+
+// renderSidePane draws a side pane's border, accounting for border and padding so the panes
+// line up with the list
+func renderSidePane(content string, width, height int) string {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorBorder).
+		Padding(0, 1).
+		Width(max(width-2, 1)).
+		Height(max(height-2, 1)).
+		MaxHeight(height).
+		Render(content)
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+)
+
+// LockMsg is sent when a project was locked or unlocked
+type LockMsg struct {
+	projectName string
+	locked      bool
+	err         error
+}
+
+// toggleLock locks or unlocks the project. Locked projects can't be archived, removed or
+// cleared; the database refuses it, so every path to those actions is covered.
+func (m model) toggleLock(item projectItem) (tea.Model, tea.Cmd) {
+	project := item.project
+	m.errorMessage = ""
+	return m, func() tea.Msg {
+		locked := !project.Locked
+		err := db.SetProjectLocked(project.ID, locked)
+		return LockMsg{projectName: project.Name, locked: locked, err: err}
+	}
+}
+
+// lockDone reports a lock change and reloads the list to show it
+func (m model) lockDone(msg LockMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to lock %s: %v", msg.projectName, msg.err)
+		return m, nil
+	}
+
+	m.statusMessage = "Unlocked " + msg.projectName
+	if msg.locked {
+		m.statusMessage = fmt.Sprintf("Locked %s: archive, remove and clear all are refused until it is unlocked (U)", msg.projectName)
+	}
+	return m, reloadProjectsCmd(m.statusFilter)
+}
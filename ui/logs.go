@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/logging"
+)
+
+// logErrorMessages logs error messages that an update put in the status bar, so they can be
+// read later in the log viewer and the log file
+func logErrorMessages(before model, next tea.Model) {
+	if after, ok := next.(model); ok && after.errorMessage != "" && after.errorMessage != before.errorMessage {
+		slog.Error(after.errorMessage, "source", "tui")
+	}
+}
+
+// openLogs shows the warnings and errors logged since DevBase started
+func (m model) openLogs() (tea.Model, tea.Cmd) {
+	m.logEntries = logging.Recent()
+	m.logOffset = 0
+	m.screen = screenLogs
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// updateLogs handles updates for the log viewer
+func (m model) updateLogs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "L":
+		m.screen = screenList
+		m.logEntries = nil
+		return m, nil
+
+	case "up", "k":
+		if m.logOffset > 0 {
+			m.logOffset--
+		}
+
+	case "down", "j":
+		if m.logOffset < len(m.logEntries)-1 {
+			m.logOffset++
+		}
+
+	case "pgup":
+		m.logOffset = max(0, m.logOffset-m.activityPageSize())
+
+	case "pgdown":
+		m.logOffset = max(0, min(len(m.logEntries)-1, m.logOffset+m.activityPageSize()))
+
+	case "r":
+		// Pick up entries logged by background work since the viewer opened
+		m.logEntries = logging.Recent()
+		m.logOffset = 0
+	}
+
+	return m, nil
+}
+
+// viewLogs renders the log viewer, newest entries first
+func (m model) viewLogs() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Errors & Warnings")
+
+	s := "\n" + titleBox + "\n\n"
+
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	if path, err := logging.Path(); err == nil {
+		s += dimStyle.Render("Full log: "+path) + "\n\n"
+	}
+
+	if len(m.logEntries) == 0 {
+		s += dimStyle.Render("Nothing went wrong this session") + "\n"
+	}
+
+	end := min(len(m.logEntries), m.logOffset+m.activityPageSize())
+	for _, entry := range m.logEntries[m.logOffset:end] {
+		levelStyle := lipgloss.NewStyle().Foreground(colorWarning)
+		if entry.Level >= slog.LevelError {
+			levelStyle = errorStyle
+		}
+
+		line := dimStyle.Render(entry.Time.Format("15:04:05")) + " " +
+			levelStyle.Render(fmt.Sprintf("%-5s", entry.Level)) + " " +
+			lipgloss.NewStyle().Foreground(colorText).Render(entry.Message)
+		if entry.Attrs != "" {
+			line += dimStyle.Render("  " + entry.Attrs)
+		}
+		s += line + "\n"
+	}
+
+	if end < len(m.logEntries) {
+		s += dimStyle.Render(fmt.Sprintf("… %d older", len(m.logEntries)-end)) + "\n"
+	}
+
+	s += dimStyle.Render("\n↑↓/pgup/pgdn=scroll  r=refresh  esc=back")
+	return docStyle.Render(s)
+}
@@ -1,22 +1,26 @@
 package ui
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"devbase/db"
 	"devbase/engine"
+	"devbase/logging"
 	"devbase/models"
 )
 
@@ -45,9 +49,10 @@ type ErrorMsg struct {
 	err error
 }
 
-// OpenProjectMsg is sent when opening a project in VS Code completes
+// OpenProjectMsg is sent when opening a project in an editor completes
 type OpenProjectMsg struct {
 	projectID uint
+	editor    string
 	err       error
 }
 
@@ -72,16 +77,19 @@ type ExecuteCommandMsg struct {
 
 // ScanCompleteMsg is sent when directory scan completes
 type ScanCompleteMsg struct {
+	path            string
 	projectsFound   int
 	projectsAdded   int
 	projectsRemoved int
+	report          engine.ScanReport // What the scan skipped, shown by the scan report (Y)
 	err             error
 }
 
 // ClearAllMsg is sent when clearing all projects completes
 type ClearAllMsg struct {
-	count int
-	err   error
+	count    int
+	rootName string // Root folder whose projects were cleared, empty when all were
+	err      error
 }
 
 // SyncToCloudMsg is sent when syncing projects to cloud completes
@@ -120,88 +128,204 @@ type OAuthDeviceCodeMsg struct {
 // OAuthCompleteMsg is sent when OAuth authentication completes
 type OAuthCompleteMsg struct {
 	accessToken string
+	scopes      *engine.TokenScopes // nil when they couldn't be read
 	err         error
 }
 
+// GHTokenMsg is sent when reading and validating the GitHub CLI token completes
+type GHTokenMsg struct {
+	token  string
+	scopes engine.TokenScopes
+	err    error
+}
+
 // GitHubUsernameMsg is sent when fetching GitHub username completes
 type GitHubUsernameMsg struct {
 	username string
 	err      error
 }
 
+// pathCheckInterval is how often project directories are verified in the background
+const pathCheckInterval = 30 * time.Second
+
+// pathCheckTickMsg triggers a background check of project directories
+type pathCheckTickMsg struct{}
+
+// PathCheckMsg is sent when the background project path check completes
+type PathCheckMsg struct {
+	missing    map[uint]bool
+	reschedule bool // Periodic checks schedule the next one when they complete
+}
+
+// RemoveProjectMsg is sent when removing a project entry from the database completes
+type RemoveProjectMsg struct {
+	projectName string
+	err         error
+}
+
 // projectItem wraps a Project and implements the list.Item interface
 type projectItem struct {
-	project   models.Project
-	isLoading bool // Track if operation is in progress
+	project    models.Project
+	isLoading  bool                   // Track if operation is in progress
+	missing    bool                   // Project directory no longer exists on disk
+	marked     bool                   // Marked for opening together with other projects
+	meta       engine.ProjectMetadata // Size and last commit, collected in the background
+	remoteHost string                 // Name of the SSH host for remote projects, empty for local ones
+	elsewhere  string                 // Machine that registered a project loaded from the cloud, empty for this one
+	hotkey     string                 // Quick-switch key opening the project, empty for none
+	runs       []models.DevRun        // Dev runs of the project that are going, newest first
+}
+
+// onlyElsewhere returns the machine an archived project was registered on when that's another
+// machine, i.e. the project exists there but hasn't been cloned here
+func (i projectItem) onlyElsewhere() string {
+	if i.project.Status != "archived" {
+		return ""
+	}
+	return i.elsewhere
 }
 
 // FilterValue implements list.Item
 func (i projectItem) FilterValue() string {
-	return i.project.Name
+	// Include tags and the fields used by tag:, status: and lang: filters
+	return encodeFilterValue(i.project)
 }
 
 // Title implements list.DefaultItem
 func (i projectItem) Title() string {
-	title := i.project.Name
+	name, prefix, suffix := i.titleParts()
+	return prefix + name + suffix
+}
+
+// titleParts splits the title into the project name and the indicators shown around it
+func (i projectItem) titleParts() (name, prefix, suffix string) {
+	name = i.project.Name
 
-	// Add GitHub indicator
+	// Worktrees are listed under their parent project
+	if i.project.ParentID != 0 {
+		prefix = "↳ "
+	}
+
+	// Add mark and GitHub indicators
+	if i.marked {
+		prefix += "◆ "
+	}
+	if i.project.Pinned {
+		prefix += "📌 "
+	}
+	if i.project.Locked {
+		prefix += "🔒 "
+	}
 	if i.project.RepoURL != "" {
-		title = "🔗 " + title
+		prefix += "🔗 "
+	}
+	if i.project.DevContainer {
+		prefix += "🐳 "
+	}
+	if i.project.Icon != "" {
+		prefix += i.project.Icon + " "
 	}
 
 	if i.isLoading {
-		return title + " [Processing...]"
+		suffix = tr("list.item.processing")
+	} else if machine := i.onlyElsewhere(); machine != "" {
+		suffix = tr("list.item.elsewhere", machine)
+	} else if i.project.Status == "archived" {
+		suffix = tr("list.item.archived")
+	} else if i.missing {
+		suffix = tr("list.item.missing")
 	}
-	if i.project.Status == "archived" {
-		return title + " [Archived]"
+	if i.remoteHost != "" {
+		suffix = tr("list.item.remote", i.remoteHost) + suffix
 	}
-	return title
+	if len(i.runs) > 0 {
+		if port := i.runs[0].Port; port != 0 {
+			suffix += tr("list.item.running_port", port)
+		} else {
+			suffix += tr("list.item.running")
+		}
+	}
+	if i.hotkey != "" {
+		suffix += " ‹" + i.hotkey + "›"
+	}
+	return name, prefix, suffix
 }
 
 // Description implements list.DefaultItem
 func (i projectItem) Description() string {
-	desc := ""
-	if i.project.Path != "" {
-		desc = i.project.Path
-	} else {
-		desc = i.project.Status
-	}
-
-	// Add repo URL info if available
-	if i.project.RepoURL != "" {
-		desc += " • " + i.project.RepoURL
-	}
-
-	return desc
+	return describeProject(i, time.Now())
 }
 
 var docStyle = lipgloss.NewStyle().Margin(1, 2)
 
 var errorStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#FF0000")).
+	Foreground(colorDanger).
 	Bold(true)
 
 var titleStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#00FFFF")).
+	Foreground(colorAccent).
 	Bold(true)
 
 var subtitleStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#888888"))
+	Foreground(colorDim)
 
 // screenState represents the current screen being displayed
 type screenState int
 
 const (
-	screenSetupPath screenState = iota
+	screenWizard screenState = iota
 	screenSetupGitHub
 	screenSetupToken
 	screenOAuthWaiting
 	screenCloudSelect
 	screenRootFolderManage
 	screenRepoSelect
+	screenRepoPicker
+	screenSyncDiff
+	screenActivity
+	screenLogs
+	screenStale
+	screenReclaim
+	screenRunLogs
+	screenRunOutput
+	screenJobs
+	screenWorktrees
+	screenScanReport
+	screenTrash
 	screenList
 )
 
+// Status filter values for the project list, cycled with 'v'
+const (
+	statusFilterAll      = ""
+	statusFilterActive   = "active"
+	statusFilterArchived = "archived"
+)
+
+// nextStatusFilter returns the filter that follows the given one (all -> active -> archived -> all)
+func nextStatusFilter(current string) string {
+	switch current {
+	case statusFilterAll:
+		return statusFilterActive
+	case statusFilterActive:
+		return statusFilterArchived
+	default:
+		return statusFilterAll
+	}
+}
+
+// listTitle returns the project list title including the current status filter
+func listTitle(statusFilter string) string {
+	switch statusFilter {
+	case statusFilterActive:
+		return tr("list.title.active")
+	case statusFilterArchived:
+		return tr("list.title.archived")
+	default:
+		return tr("list.title.all")
+	}
+}
+
 // CloneMsg is sent when a clone operation completes
 type CloneMsg struct {
 	projectName string
@@ -217,21 +341,98 @@ type FetchReposMsg struct {
 
 // model represents the Bubble Tea application model
 type model struct {
+	ctx                   context.Context    // Cancelled when DevBase exits, stopping running jobs
+	jobs                  *engine.JobManager // Background jobs shown on the jobs screen (J)
+	jobCursor             int
+	jobTick               int                // Refresh ticks carrying another id belong to a closed jobs screen
+	scanReport            *engine.ScanReport // Report of the last finished scan (Y), nil before one finished
+	scanReportPath        string
+	scanReportCursor      int
+	scanReportOpen        [4]bool        // Expanded sections of the scan report
+	worktreeParent        models.Project // Project whose worktrees the worktrees screen (K) shows
+	worktreeRows          []worktreeRow
+	worktreeCursor        int
+	worktreeInput         textinput.Model // Branch of a new worktree
+	worktreeAdding        bool
+	worktreeConfirm       bool // Asking before a worktree is removed
+	worktreeLoading       bool
+	worktreeChanged       bool // Worktrees were added or removed, so the list reloads on close
 	screen                screenState
-	pathInput             textinput.Model
 	tokenInput            textinput.Model
 	list                  list.Model
 	errorMessage          string
 	statusMessage         string
-	isScanning            bool
 	confirmClearAll       bool
+	clearInput            textinput.Model
+	clearRoot             *models.RootFolder // Active root folder when clearing started, nil without one
+	clearEverything       bool               // Clear every root folder instead of the active one
+	clearSummary          db.ClearSummary    // What clearing would remove
 	confirmArchive        bool
+	showPalette           bool // Command palette (ctrl+p) is open
+	paletteInput          textinput.Model
+	paletteCursor         int
+	plugins               []engine.Plugin // Installed plugins, whose actions are added to the palette
+	scripts               []engine.Script // Automation scripts, listed again when the palette opens
+	showEditorPicker      bool            // "Open with" editor picker is open
+	editorChoices         []engine.Editor // Editors detected on PATH
+	editorCursor          int
+	editorProject         *projectItem       // Project being opened from the picker
+	showGitClientPicker   bool               // Git client picker (G) is open
+	gitClientChoices      []engine.GitClient // Git clients detected on this system
+	gitClientCursor       int
+	gitClientProject      *projectItem  // Project being opened in a git client
+	showTaskPicker        bool          // Run-task picker (x) is open
+	taskChoices           []engine.Task // Default dev command, detected tasks and the custom entry
+	taskCursor            int
+	taskCustom            bool         // Entering a custom command in the task picker
+	taskProject           *projectItem // Project the task runs in
+	taskEnvFound          string       // Environment source the project offers (".env", "direnv" or "")
+	taskEnv               string       // Environment source the next run uses, toggled with e
+	taskOutput            string       // Where the next run writes its output (runOutputTerminal, Log or Pane), switched with c
+	editingNotes          bool         // Notes editor (N) is open
+	notesInput            textarea.Model
+	notesProject          *projectItem  // Project whose notes are being edited
+	marked                map[uint]bool // Project IDs marked for opening together
+	savingSession         bool          // Prompting for a name to save marked projects as a session
+	sessionInput          textinput.Model
+	showSessions          bool // Saved sessions picker (w) is open
+	sessions              []models.Session
+	sessionCursor         int
+	editingTags           bool // Tag editor (T) is open
+	showInitProject       bool // New project prompt (i) is open
+	initInput             textinput.Model
+	initRemote            int          // Index in initRemotes of the GitHub repository to create
+	refProject            *projectItem // Project whose restore ref (B) is being edited, nil when closed
+	refInput              textinput.Model
+	refOptionsInput       textinput.Model // Clone options of the project whose restore ref is edited
+	iconProject           *projectItem    // Project whose icon (I) is being edited, nil when closed
+	iconInput             textinput.Model
+	restoreToProject      *projectItem        // Archived project being restored elsewhere (A), nil when closed
+	restoreToIdx          int                 // List index of restoreToProject, for the rollback of a failed restore
+	restoreToInput        textinput.Model     // Directory restoreToProject is restored into
+	restoreToRoots        []models.RootFolder // Root folders tab cycles through
+	restoreToRoot         int                 // Index in restoreToRoots of the chosen root folder, -1 for the original path
+	runConfigOffer        *RunConfigMsg       // devbase.yaml offered for a cloned project, nil when none is
+	tagInput              textinput.Model
+	tagProject            *projectItem // Project whose tags are being edited
+	allTags               []string     // Existing tags offered as suggestions
+	tagSuggestion         int
+	confirmMissing        bool                            // Showing the "rescan or remove?" prompt for a missing project
+	missingProject        *projectItem                    // Project whose directory is missing
+	missingPaths          map[uint]bool                   // Project IDs whose directory was not found by the last path check
+	metadata              map[uint]engine.ProjectMetadata // Size and last commit per project ID
+	repoMeta              map[string]repoMetaState        // GitHub details per repository, for the detail pane
 	archiveConfirmInput   textinput.Model
 	archiveProject        *projectItem
 	archiveIdx            int
+	archiveTrashDays      int // Days the checkout of archiveProject stays in the trash, 0 when archiving deletes it
 	confirmClone          bool
 	cloneInput            textinput.Model
-	cloneMode             string // "url" or "select"
+	cloneMode             string          // "url", "select" or cloneModeOrg
+	cloneNameInput        textinput.Model // Directory name, empty for the name from the URL
+	cloneRoots            []string        // Root folders to clone into, the active one first
+	cloneRoot             int
+	cloneField            int // Focused field of the clone prompt
 	confirmExecuteCommand bool
 	executeCommandInput   textinput.Model
 	userRepos             []engine.GitHubRepository
@@ -239,21 +440,91 @@ type model struct {
 	repoCursorIndex       int
 	repoFiltering         bool
 	cloudProjects         []models.Project
-	selectedCloudIndices  []int
-	cloudCursorIndex      int
-	cloudFilterInput      textinput.Model
-	cloudFiltering        bool
+	cloudList             list.Model
+	cloudBackups          []engine.CloudBackup // Backups found in the user's gists to adopt, nil when the picker is closed
+	backupCursor          int
+	pickerOrg             string                    // Organization listed in the repository picker, "" for starred repositories
+	pickerRepos           []engine.GitHubRepository // Repositories fetched so far
+	pickerList            list.Model
+	pickerPage            int  // Last fetched page
+	pickerMore            bool // Another page may follow
+	pickerLoading         bool
+	syncDirection         string               // syncDirectionPush or syncDirectionLoad
+	syncDiff              engine.SyncDiff      // Changes the reviewed sync will make
+	syncDamaged           error                // Why the cloud backup a push replaces can't be read
+	syncSecrets           []engine.SecretMatch // Possible secrets in the projects a push uploads
+	syncBlocked           bool                 // The secrets block the push
+	syncDiffCursor        int
+	syncDiffExpanded      [syncSectionCount]bool
+	syncLoadIndices       []int // Cloud projects selected for loading
 	rootScanPath          string
+	statusFilter          string // "", "active" or "archived"
+	layout                layout // Pane visibility and split preferences
 	width                 int
 	height                int
 	ready                 bool
+	wizard                wizardState // First-run setup wizard progress
+	activities            []models.Activity
+	activityProject       *projectItem                  // Project the history is limited to, nil for all projects
+	activityOffset        int                           // First timeline entry shown
+	vimMode               bool                          // Vim-style keybindings (config "keymap" = "vim")
+	recentHotkeys         []string                      // Quick-switch keys of the most recently used projects (config "recent_hotkeys")
+	footerStats           *engine.ProjectStats          // Summary under the list, nil until first collected
+	footerLoading         bool                          // Footer stats are being collected
+	footerStale           bool                          // The projects changed while collecting, so collect again
+	missingDeps           []*engine.MissingCommandError // Programs not found on PATH at startup
+	gitMissing            *engine.MissingCommandError   // Set when git is missing, which disables cloning and restoring
+	vimPending            string                        // First key of a two-key vim sequence (g or d)
+	vimCommandLine        bool                          // Vim ":" command line is open
+	vimInput              textinput.Model
+	logEntries            []logging.Entry // Warnings and errors shown in the log viewer
+	logOffset             int             // First log viewer entry shown
+	staleProjects         []engine.StaleProject
+	staleSelected         map[uint]bool // Stale projects selected for archiving, by project ID
+	staleCursor           int
+	staleDays             int  // Days without opens and commits the report is for
+	staleLoading          bool // The report is being built or projects are being archived
+	staleConfirm          bool // Asking to confirm archiving the selection
+	staleChanged          bool // Projects were archived, so the list is reloaded on leaving
+	reclaimProjects       []engine.ReclaimableProject
+	reclaimSelected       map[uint]bool // Projects whose dependency folders are to be deleted, by ID
+	reclaimCursor         int
+	reclaimLoading        bool // Folders are being measured or deleted
+	reclaimConfirm        bool // Asking to confirm deleting the selection
+	trashPurges           []engine.TrashPurge
+	trashRetention        engine.TrashRetention
+	trashCursor           int
+	trashLoading          bool // The trash is being loaded or purged
+	trashConfirm          bool // Asking to confirm purging the selected checkout
+	runLogs               []engine.RunLog
+	runLogCursor          int
+	runLogPath            string              // Run log being viewed, empty while listing
+	runLogLines           []string            // End of the viewed log
+	runLogOffset          int                 // Lines scrolled up from the end of the viewed log
+	runLogTick            int                 // Refresh ticks carrying another id belong to a closed viewer
+	streamRun             *engine.StreamedRun // Run shown in the output pane (F), nil before the first
+	streamProject         models.Project      // Project, command and environment of the streamed run, to restart it
+	streamCommand         string
+	streamEnv             string
+	streamView            viewport.Model
+	streamFollow          bool                     // The pane scrolls along with new output
+	streamRestarting      bool                     // Waiting for the run to exit before starting it again
+	streamTick            int                      // Refresh ticks carrying another id belong to a closed pane
+	devRuns               map[uint][]models.DevRun // Dev runs that are going, by project ID
+	devRunTick            int                      // Check ticks carrying another id were replaced
 	// OAuth flow fields
 	oauthDeviceCode      string
 	oauthUserCode        string
 	oauthVerificationURI string
 	oauthInterval        int
+	// Saved GitHub token, shown on the setup screen
+	tokenSaved       bool
+	tokenSource      string // engine.TokenSourceOAuth, TokenSourcePAT or TokenSourceGHCLI; empty when unknown
+	tokenScopes      engine.TokenScopes
+	tokenScopesKnown bool
 	// Root folder management fields
 	rootFolders                []models.RootFolder
+	rootFolderCounts           map[uint]int64 // Project count per root folder ID
 	rootFolderCursor           int
 	activeRootFolderID         uint
 	rootFolderInput            textinput.Model
@@ -264,30 +535,148 @@ type model struct {
 
 // Init initializes the model and loads projects from the database
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, schedulePathCheck(0), scheduleConfigCheck(), m.projectMetadataCmd(m.list.Items()), collectFooterStats(m.ctx), checkDependenciesCmd(), loadPluginsCmd(), telemetryCmd(), purgeTrashCmd(), checkDevRunsCmd())
 }
 
-// Update handles messages and updates the model
+// Update handles messages and updates the model. Errors shown in the status bar are logged.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer recoverCrash()
+	next, cmd := m.update(msg)
+	logErrorMessages(m, next)
+	return next, cmd
+}
+
+// update handles messages and updates the model
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle window size first (applies to both screens)
 	if msg, ok := msg.(tea.WindowSizeMsg); ok {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
 
-		// Calculate available space for list (subtract margins, status, help)
-		listWidth := msg.Width - 4
-		listHeight := msg.Height - 8
+		// Calculate available space for list (subtract margins, status, help and detail pane)
+		m.list.SetSize(m.listSize())
+		if m.editingNotes {
+			width, height := m.notesEditorSize()
+			m.notesInput.SetWidth(width)
+			m.notesInput.SetHeight(height)
+		}
+	}
+
+	// Background path and config file checks apply regardless of the current screen
+	switch msg := msg.(type) {
+	case pathCheckTickMsg:
+		return m, checkPathsCmd(m.list.Items(), true)
+
+	case configCheckTickMsg:
+		return m, checkConfigFileCmd()
+
+	case ConfigFileMsg:
+		return m.configFileChecked(msg)
+
+	case FooterStatsMsg:
+		return m.footerStatsLoaded(msg)
 
-		if listHeight < 10 {
-			listHeight = 10
+	case DependenciesMsg:
+		return m.dependenciesChecked(msg)
+
+	case RunStreamMsg:
+		return m.runStreamStarted(msg)
+
+	case RunStreamExitedMsg:
+		return m.runStreamExited(msg)
+
+	case DevRunsMsg:
+		return m.devRunsChecked(msg)
+
+	case devRunTickMsg:
+		return m.devRunTicked(msg)
+
+	case DevRunStoppedMsg:
+		return m.devRunStopped(msg)
+
+	case PathCheckMsg:
+		// Mark rows whose directory no longer exists
+		m.missingPaths = msg.missing
+		var cmds []tea.Cmd
+		var stale []engine.Event
+		for i, item := range m.list.Items() {
+			pi, ok := item.(projectItem)
+			if !ok {
+				continue
+			}
+			missing := msg.missing[pi.project.ID]
+			if pi.missing != missing {
+				if missing {
+					stale = append(stale, engine.ProjectEvent(engine.EventStale, pi.project, ""))
+				}
+				pi.missing = missing
+				cmds = append(cmds, m.list.SetItem(i, pi))
+			}
+		}
+		if len(cmds) > 0 {
+			// Projects whose directory went missing give up their quick-switch key
+			cmds = append(cmds, m.refreshHotkeys())
+		}
+		if len(stale) > 0 {
+			cmds = append(cmds, emitCmd(stale...))
+		}
+		if msg.reschedule {
+			cmds = append(cmds, schedulePathCheck(pathCheckInterval))
 		}
+		return m, tea.Batch(cmds...)
 
-		m.list.SetSize(listWidth, listHeight)
+	case LastOpenedMsg:
+		return m.openRecorded(msg)
+
+	case PluginsMsg:
+		m.plugins = msg.plugins
+		return m, nil
+
+	case PluginActionMsg:
+		return m.pluginActionDone(msg)
+
+	case ScriptsMsg:
+		m.scripts = msg.scripts
+		return m, nil
+
+	case ScriptMsg:
+		return m.scriptDone(msg)
+
+	case RepoMetaMsg:
+		if m.repoMeta == nil {
+			m.repoMeta = make(map[string]repoMetaState)
+		}
+		m.repoMeta[msg.repo] = repoMetaState{meta: msg.meta, err: msg.err}
+		return m, nil
+
+	case ProjectMetadataMsg:
+		// Merge so projects that were not part of this collection keep their details
+		if m.metadata == nil {
+			m.metadata = make(map[uint]engine.ProjectMetadata)
+		}
+		var cmds []tea.Cmd
+		for id, meta := range msg.metadata {
+			m.metadata[id] = meta
+		}
+		for i, item := range m.list.Items() {
+			if pi, ok := item.(projectItem); ok {
+				if meta, ok := msg.metadata[pi.project.ID]; ok {
+					pi.meta = meta
+					cmds = append(cmds, m.list.SetItem(i, pi))
+				}
+			}
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	// Handle first-run wizard
+	if m.screen == screenWizard {
+		return m.updateWizard(msg)
 	}
 
-	// Handle setup screen
-	if m.screen == screenSetupPath || m.screen == screenSetupGitHub || m.screen == screenOAuthWaiting {
+	// Handle GitHub setup screens
+	if m.screen == screenSetupGitHub || m.screen == screenSetupToken || m.screen == screenOAuthWaiting {
 		return m.updateSetup(msg)
 	}
 
@@ -306,49 +695,173 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateRepoSelect(msg)
 	}
 
+	// Handle starred and organization repository picker
+	if m.screen == screenRepoPicker {
+		return m.updateRepoPicker(msg)
+	}
+
+	// Handle sync diff review screen
+	if m.screen == screenSyncDiff {
+		return m.updateSyncDiff(msg)
+	}
+
+	// Handle activity history screen
+	if m.screen == screenActivity {
+		return m.updateActivity(msg)
+	}
+
+	// Handle log viewer
+	if m.screen == screenLogs {
+		return m.updateLogs(msg)
+	}
+
+	// Handle stale project report
+	if m.screen == screenStale {
+		return m.updateStale(msg)
+	}
+
+	// Handle reclaimable-space analyzer
+	if m.screen == screenReclaim {
+		return m.updateReclaim(msg)
+	}
+
+	// Handle run log viewer
+	if m.screen == screenRunLogs {
+		return m.updateRunLogs(msg)
+	}
+
+	// Handle the output pane of streamed runs
+	if m.screen == screenRunOutput {
+		return m.updateRunStream(msg)
+	}
+
+	// Handle background jobs screen
+	if m.screen == screenJobs {
+		return m.updateJobs(msg)
+	}
+
+	// Handle the worktrees screen
+	if m.screen == screenWorktrees {
+		return m.updateWorktrees(msg)
+	}
+
+	// Handle the scan report screen
+	if m.screen == screenScanReport {
+		return m.updateScanReport(msg)
+	}
+
+	// Handle the archive trash screen
+	if m.screen == screenTrash {
+		return m.updateTrash(msg)
+	}
+
 	// Handle list screen
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// If in clone input mode, only handle enter, esc, and 'b' for browse
+		// The command palette captures all keys while open
+		if m.showPalette {
+			return m.updatePalette(msg)
+		}
+
+		// The editor picker captures all keys while open
+		if m.showEditorPicker {
+			return m.updateEditorPicker(msg)
+		}
+
+		// The git client picker captures all keys while open
+		if m.showGitClientPicker {
+			return m.updateGitClientPicker(msg)
+		}
+
+		// The run-task picker captures all keys while open
+		if m.showTaskPicker {
+			return m.updateTaskPicker(msg)
+		}
+
+		// The notes editor captures all keys while open
+		if m.editingNotes {
+			return m.updateNotesEditor(msg)
+		}
+
+		// Session prompts capture all keys while open
+		if m.savingSession {
+			return m.updateSaveSession(msg)
+		}
+		if m.showSessions {
+			return m.updateSessionPicker(msg)
+		}
+
+		// The tag editor captures all keys while open
+		if m.editingTags {
+			return m.updateTagEditor(msg)
+		}
+		if m.showInitProject {
+			return m.updateInitProject(msg)
+		}
+		if m.refProject != nil {
+			return m.updateRestoreRef(msg)
+		}
+		if m.iconProject != nil {
+			return m.updateIconEditor(msg)
+		}
+		if m.restoreToProject != nil {
+			return m.updateRestoreTo(msg)
+		}
+		if m.runConfigOffer != nil {
+			return m.updateRunConfigOffer(msg)
+		}
+		if m.cloudBackups != nil {
+			return m.updateBackupPicker(msg)
+		}
+
+		// The vim command line captures all keys while open
+		if m.vimCommandLine {
+			return m.updateVimCommandLine(msg)
+		}
+
+		// The organization prompt shares the clone input
+		if m.confirmClone && m.cloneMode == cloneModeOrg {
+			return m.updateOrgPrompt(msg)
+		}
+
+		// The clone prompt captures all keys while open
 		if m.confirmClone {
+			return m.updateClonePrompt(msg)
+		}
+
+		// If resolving a missing project, only handle rescan, remove and esc
+		if m.confirmMissing {
 			switch msg.String() {
 			case "ctrl+c":
 				return m, tea.Quit
-			case "b":
-				// Switch to browse mode
-				// Check if GitHub token is configured
-				token, err := db.GetConfig("github_token")
-				if err != nil || token == "" {
-					m.errorMessage = "GitHub authentication required. Press 't' to authenticate with OAuth."
+			case "s":
+				m.confirmMissing = false
+				m.missingProject = nil
+				if m.jobRunning(engine.JobScan) {
 					return m, nil
 				}
-				m.confirmClone = false
-				m.statusMessage = "Loading your GitHub repositories..."
-				m.errorMessage = ""
-				return m, fetchUserReposCmd()
-			case "enter":
-				repoURL := m.cloneInput.Value()
-				if repoURL == "" {
-					m.errorMessage = "Please enter a valid GitHub repository URL or press 'b' to browse"
+				if m.rootScanPath == "" {
+					m.errorMessage = "No scan path configured. Please restart."
 					return m, nil
 				}
-				// Clear confirmation state
-				m.confirmClone = false
-				m.statusMessage = "Cloning repository..."
+				m.statusMessage = "Scanning for projects..."
+				m.errorMessage = ""
+				return m, m.scanProjectsWithPathCmd(m.rootScanPath)
+			case "d":
+				item := *m.missingProject
+				m.confirmMissing = false
+				m.missingProject = nil
 				m.errorMessage = ""
-				// Execute clone
-				return m, cloneProjectCmd(repoURL, m.rootScanPath)
+				m.statusMessage = "Removing project entry..."
+				return m, removeProjectCmd(item.project.ID, item.project.Name)
 			case "esc":
-				m.confirmClone = false
-				m.statusMessage = "Clone cancelled"
+				m.confirmMissing = false
+				m.missingProject = nil
+				m.statusMessage = ""
 				m.errorMessage = ""
 				return m, nil
-			default:
-				// Pass other keys to the text input
-				var cmd tea.Cmd
-				m.cloneInput, cmd = m.cloneInput.Update(msg)
-				return m, cmd
 			}
+			return m, nil
 		}
 
 		// If in archive confirmation mode, only handle enter and esc
@@ -393,6 +906,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if m.confirmClearAll {
+			return m.updateClearAll(msg)
+		}
+
 		// If list is filtering, let it handle all keys
 		if m.list.FilterState() == list.Filtering {
 			var cmd tea.Cmd
@@ -400,10 +917,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Quick-switch keys open one of the most recently used projects
+		if next, cmd, ok := m.quickSwitch(msg.String()); ok {
+			return next, cmd
+		}
+
+		// Vim mode translates its key sequences before the regular keybindings
+		if m.vimMode {
+			key, cmd, handled := m.handleVimKey(msg)
+			if handled {
+				return m, cmd
+			}
+			msg = key
+		}
+
+		if m.gitKeyDisabled(msg.String()) {
+			m.errorMessage = m.gitMissing.Error()
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 
+		case "ctrl+p":
+			// Open the command palette
+			return m.openPalette()
+
 		case "d":
 			// Archive (delete) the selected project - Show confirmation
 			if m.confirmArchive {
@@ -420,11 +960,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+			// Archiving moves the local checkout away, which remote projects don't have
+			if item.remoteHost != "" {
+				m.errorMessage = remoteUnsupported(item, "archived")
+				return m, nil
+			}
+			if item.project.ParentID != 0 {
+				m.errorMessage = fmt.Sprintf("%s is a worktree; remove it from its project's worktrees (K) instead", item.project.Name)
+				return m, nil
+			}
+			if item.project.Locked {
+				m.errorMessage = fmt.Sprintf("%s is locked; unlock it with U before archiving", item.project.Name)
+				return m, nil
+			}
+
 			// Enter confirmation mode
 			m.confirmArchive = true
 			itemCopy := item
 			m.archiveProject = &itemCopy
 			m.archiveIdx = m.list.Index()
+			m.archiveTrashDays = engine.TrashRetentionConfig().Days
 			m.errorMessage = ""
 			m.statusMessage = ""
 
@@ -465,8 +1020,31 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Return command to restore in background
 			return m, restoreProjectCmd(item.project.ID, originalItem, originalIdx)
 
+		case "E":
+			// Retry the archive or restore of the selected project that failed last
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			if item.project.LastError == "" {
+				m.errorMessage = fmt.Sprintf("%s has no failed archive or restore to retry", item.project.Name)
+				return m, nil
+			}
+			if item.project.Status == "archived" {
+				return m.runListKey(keyRune('r'))
+			}
+			return m.runListKey(keyRune('d'))
+
+		case "A":
+			// Restore the selected archived project into another directory or root folder
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openRestoreTo(item)
+
 		case "enter":
-			// Open project in VS Code
+			// Open project in the default editor
 			selectedItem := m.list.SelectedItem()
 			if selectedItem == nil {
 				return m, nil
@@ -477,27 +1055,50 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			// Update LastOpened timestamp
-			go db.UpdateLastOpened(item.project.ID)
+			// Ask how to resolve a project whose directory disappeared
+			if item.missing {
+				itemCopy := item
+				m.confirmMissing = true
+				m.missingProject = &itemCopy
+				m.errorMessage = ""
+				m.statusMessage = ""
+				return m, nil
+			}
+
+			// Let the user choose the editor when prompting is enabled and there is a choice
+			if prompt, _ := db.GetConfig("editor_prompt"); prompt == "true" && len(engine.DetectEditors()) > 1 {
+				return m.openEditorPicker(item)
+			}
 
 			m.errorMessage = "" // Clear any previous errors
 
-			// Return command to open VS Code
-			return m, openProjectCmd(item.project.ID, item.project.Path)
+			// Open the project's editor and update its LastOpened timestamp
+			return m, tea.Batch(openProjectCmd(item.project, projectEditor(item.project)), recordOpenCmd(item.project))
+
+		case "e":
+			// Choose the editor for this open
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			if item.missing || item.project.Status == "archived" {
+				m.errorMessage = "Project directory is not available"
+				return m, nil
+			}
+			return m.openEditorPicker(item)
 
 		case "s":
 			// Scan for new projects
-			if m.isScanning {
+			if m.jobRunning(engine.JobScan) {
 				return m, nil // Already scanning
 			}
 			if m.rootScanPath == "" {
 				m.errorMessage = "No scan path configured. Please restart."
 				return m, nil
 			}
-			m.isScanning = true
 			m.statusMessage = "Scanning for projects..."
 			m.errorMessage = ""
-			return m, scanProjectsWithPathCmd(m.rootScanPath)
+			return m, m.scanProjectsWithPathCmd(m.rootScanPath)
 
 		case "g":
 			// Clone a GitHub repository
@@ -508,21 +1109,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errorMessage = "No scan path configured. Please restart."
 				return m, nil
 			}
-			// Enter clone mode with URL input
-			m.confirmClone = true
-			m.cloneMode = "url"
-			m.errorMessage = ""
-			m.statusMessage = "Enter repository URL or press 'b' to browse your repositories"
-
-			// Create clone input
-			cloneInput := textinput.New()
-			cloneInput.Placeholder = "https://github.com/owner/repo or press 'b' to browse"
-			cloneInput.Focus()
-			cloneInput.CharLimit = 256
-			cloneInput.Width = 60
-			m.cloneInput = cloneInput
-
-			return m, textinput.Blink
+			return m.openClone()
 
 		case "b":
 			// Browse GitHub repositories (shortcut from main screen)
@@ -540,8 +1127,53 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errorMessage = ""
 			return m, fetchUserReposCmd()
 
+		case "S":
+			// Pick starred GitHub repositories to clone into the active root folder
+			if m.rootScanPath == "" {
+				m.errorMessage = "No scan path configured. Please restart."
+				return m, nil
+			}
+			token, err := db.GetConfig("github_token")
+			if err != nil || token == "" {
+				m.errorMessage = "GitHub authentication required. Press 't' to authenticate with OAuth."
+				return m, nil
+			}
+			m.statusMessage = "Loading your starred repositories..."
+			m.errorMessage = ""
+			return m, fetchPickerReposCmd(token, "", 1)
+
+		case "O":
+			// Browse an organization's repositories to clone several at once
+			if m.confirmClone {
+				return m, nil
+			}
+			if m.rootScanPath == "" {
+				m.errorMessage = "No scan path configured. Please restart."
+				return m, nil
+			}
+			token, err := db.GetConfig("github_token")
+			if err != nil || token == "" {
+				m.errorMessage = "GitHub authentication required. Press 't' to authenticate with OAuth."
+				return m, nil
+			}
+			m.confirmClone = true
+			m.cloneMode = cloneModeOrg
+			m.errorMessage = ""
+			m.statusMessage = ""
+
+			// Suggest the organization browsed last
+			orgInput := textinput.New()
+			orgInput.Placeholder = "organization, e.g. my-company"
+			orgInput.SetValue(lastOrg())
+			orgInput.Focus()
+			orgInput.CharLimit = 100
+			orgInput.Width = 60
+			m.cloneInput = orgInput
+
+			return m, textinput.Blink
+
 		case "o":
-			// Open GitHub repository URL in default browser
+			// Open the repository page (GitHub, GitLab, Bitbucket, Codeberg, …) in the default browser
 			selectedItem := m.list.SelectedItem()
 			if selectedItem == nil {
 				return m, nil
@@ -560,8 +1192,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errorMessage = "" // Clear any previous errors
 			m.statusMessage = "Opening repository in browser..."
 
-			// Open URL in default browser
-			return m, openBrowserCmd(item.project.RepoURL)
+			// Open the repository page in the default browser, turning SSH remotes into web URLs
+			repoURL := item.project.RepoURL
+			if ref, ok := engine.ParseRepoURL(repoURL); ok {
+				repoURL = ref.WebURL()
+			}
+			return m, openBrowserCmd(repoURL)
+
+		case "G":
+			// Open the repository in GitHub Desktop, GitKraken, Fork or Sourcetree
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openGitClient(item)
+
+		case "a":
+			// Open or switch to the project's tmux session
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openTmux(item)
+
+		case "C":
+			// Open the project in its dev container
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openDevContainer(item)
 
 		case "x":
 			// Run/execute the selected project
@@ -575,24 +1235,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			m.errorMessage = "" // Clear any previous errors
-			m.statusMessage = "Opening new terminal window to run project in development mode..."
-
-			// Run the project
-			return m, runProjectCmd(item.project.Path)
+			// Pick the task to run, with the dev command preselected
+			return m.openTaskPicker(item)
 
 		case "c":
-			// Clear all projects - ask for confirmation
-			if !m.confirmClearAll {
-				m.confirmClearAll = true
-				m.errorMessage = ""
-				m.statusMessage = ""
-				return m, nil
-			}
-			// Confirmed - clear all
-			m.confirmClearAll = false
-			m.statusMessage = "Clearing all projects..."
-			return m, clearAllProjectsCmd()
+			// Clear the projects of the active root folder after a summary and confirmation
+			return m.startClearAll()
 
 		case "u":
 			// Check if GitHub token is configured
@@ -600,10 +1248,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errorMessage = "GitHub authentication required. Press 't' to authenticate with OAuth."
 				return m, nil
 			}
-			// Sync to cloud (upload projects to GitHub Gist)
+			// Compare with the cloud backup before uploading to GitHub Gist
 			m.errorMessage = ""
-			m.statusMessage = "Syncing projects to cloud..."
-			return m, syncToCloudCmd()
+			m.statusMessage = "Comparing local projects with cloud..."
+			return m, previewPushCmd()
 
 		case "l":
 			// Check if GitHub token is configured
@@ -618,10 +1266,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "t":
 			// Configure GitHub OAuth
-			m.screen = screenSetupGitHub
-			m.errorMessage = ""
-			m.statusMessage = ""
-			return m, nil
+			return m.openGitHubSetup()
+
+		case "P":
+			// Pin or unpin the project (pinned projects get a Windows Terminal profile)
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.togglePin(item)
+
+		case "U":
+			// Lock or unlock the project against archiving and removal
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.toggleLock(item)
 
 		case "p":
 			// Open GitHub profile in browser
@@ -643,69 +1304,268 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.addingRootFolder = false
 
 			// Load root folders
-			rootFolders, err := db.GetAllRootFolders()
+			rootFolders, counts, err := loadRootFolders()
 			if err != nil {
 				m.errorMessage = fmt.Sprintf("Failed to load root folders: %v", err)
 				return m, nil
 			}
 			m.rootFolders = rootFolders
+			m.rootFolderCounts = counts
 			m.rootFolderCursor = 0
 
-			// Get active root folder ID
+			// Get active root folder ID and place the cursor on it
 			activeRoot, err := db.GetActiveRootFolder()
 			if err == nil {
 				m.activeRootFolderID = activeRoot.ID
+				for i, folder := range rootFolders {
+					if folder.ID == activeRoot.ID {
+						m.rootFolderCursor = i
+						break
+					}
+				}
 			}
 
 			return m, nil
 
-		case "esc":
-			// Cancel clear all confirmation
-			if m.confirmClearAll {
-				m.confirmClearAll = false
-				m.statusMessage = "Cancelled"
+		case "v":
+			// Cycle the status filter (all -> active -> archived)
+			m.statusFilter = nextStatusFilter(m.statusFilter)
+			m.list.Title = listTitle(m.statusFilter)
+			m.errorMessage = ""
+			m.statusMessage = ""
+			_ = db.SetConfig("status_filter", m.statusFilter)
+			return m, reloadProjectsCmd(m.statusFilter)
+
+		case "m":
+			// Mark the selected project for opening as a group
+			return m.toggleMark()
+
+		case "M":
+			// Open all marked projects together
+			return m.openMarked()
+
+		case "W":
+			// Save the marked projects as a named session
+			return m.startSaveSession()
+
+		case "w":
+			// Reopen a saved session
+			return m.openSessionPicker()
+
+		case "i":
+			// Create a new project in the active root folder
+			return m.openInitProject()
+
+		case "B":
+			// Pin the branch or tag the selected project is restored at
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openRestoreRef(item)
+
+		case "I":
+			// Set the emoji or label shown before the selected project's name
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openIconEditor(item)
+
+		case "T":
+			// Edit the selected project's tags
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openTagEditor(item)
+
+		case "N":
+			// Edit the selected project's notes
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openNotesEditor(item)
+
+		case "V":
+			// Switch between default and vim-style keybindings
+			return m.toggleVimMode()
+
+		case "H":
+			// Show the activity history
+			return m.openActivity(nil)
+
+		case "L":
+			// Show the warnings and errors logged this session
+			return m.openLogs()
+
+		case "X":
+			// Browse the output of runs captured to a log
+			return m.openRunLogs()
+
+		case "F":
+			// Show the output pane of the streamed run
+			return m.openRunStream()
+
+		case "J":
+			// Show running and finished background jobs
+			return m.openJobs()
+
+		case "Y":
+			// Show what the last scan skipped
+			return m.openScanReport()
+
+		case "K":
+			// Manage the git worktrees of the selected project
+			item, ok := m.list.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			return m.openWorktrees(item)
+
+		case "Z":
+			// Report projects that went unused, to archive them
+			return m.openStale()
+
+		case "R":
+			// Measure dependency folders that can be deleted to reclaim space
+			return m.openReclaim()
+
+		case "D":
+			// Toggle the detail pane
+			m.layout.showDetail = !m.layout.showDetail
+			if m.layout.showDetail {
+				m = m.applyLayout("Detail pane shown")
+				return m, m.selectedRepoMetaCmd()
+			}
+			return m.applyLayout("Detail pane hidden"), nil
+
+		case "n":
+			// Toggle the notes pane
+			m.layout.showNotes = !m.layout.showNotes
+			if m.layout.showNotes {
+				return m.applyLayout("Notes pane shown"), nil
+			}
+			return m.applyLayout("Notes pane hidden"), nil
+
+		case "[", "]":
+			// Shrink or grow the list relative to the side panes
+			if !m.layout.showDetail && !m.layout.showNotes {
+				m.statusMessage = "Detail and notes panes are hidden (press D or n to show them)"
+				return m, nil
+			}
+			step := listRatioStep
+			if msg.String() == "[" {
+				step = -step
+			}
+			m.layout.listRatio = clampRatio(m.layout.listRatio + step)
+			return m.applyLayout(fmt.Sprintf("List width %d%%", m.layout.listRatio)), nil
+
+		case "{", "}":
+			// Shrink or grow the notes pane relative to the detail pane
+			if !m.layout.showDetail || !m.layout.showNotes {
+				m.statusMessage = "The notes pane resizes against the detail pane (press D and n to show both)"
 				return m, nil
 			}
+			step := notesRatioStep
+			if msg.String() == "{" {
+				step = -step
+			}
+			m.layout.notesRatio = clampNotesRatio(m.layout.notesRatio + step)
+			return m.applyLayout(fmt.Sprintf("Notes height %d%%", m.layout.notesRatio)), nil
 		}
 
 	case ArchiveMsg:
 		// Handle archive completion
 		if msg.err != nil {
+			// The project was archived or removed outside this window, e.g. by the CLI
+			if errors.Is(msg.err, engine.ErrAlreadyArchived) || errors.Is(msg.err, db.ErrProjectNotFound) {
+				m.errorMessage = fmt.Sprintf("Archive skipped: %v", msg.err)
+				return m, reloadProjectsCmd(m.statusFilter)
+			}
 			// ROLLBACK: Archive failed, revert the change
 			m.list.SetItem(msg.originalIdx, msg.originalItem)
 			m.errorMessage = fmt.Sprintf("Archive failed: %v", msg.err)
-			return m, nil
+			// The reload shows the failure kept as the project's last error
+			return m, reloadProjectsCmd(m.statusFilter)
 		} else {
 			// Success: Reload list from database to fix filtering and prevent duplicates
 			m.errorMessage = ""
 			m.statusMessage = "Project archived successfully"
-			return m, reloadProjectsCmd()
+			return m, reloadProjectsCmd(m.statusFilter)
 		}
 
 	case RestoreMsg:
 		// Handle restore completion
 		if msg.err != nil {
+			// The project was restored or removed outside this window, e.g. by the CLI
+			if errors.Is(msg.err, engine.ErrAlreadyActive) || errors.Is(msg.err, db.ErrProjectNotFound) {
+				m.errorMessage = fmt.Sprintf("Restore skipped: %v", msg.err)
+				return m, reloadProjectsCmd(m.statusFilter)
+			}
 			// ROLLBACK: Restore failed, revert the change
 			m.list.SetItem(msg.originalIdx, msg.originalItem)
-			m.errorMessage = fmt.Sprintf("Restore failed: %v", msg.err)
-			return m, nil
+			switch {
+			case errors.Is(msg.err, engine.ErrPathExists):
+				m.errorMessage = fmt.Sprintf("Restore failed: %v (move or delete it first)", msg.err)
+			case errors.Is(msg.err, engine.ErrNoRepoURL):
+				m.errorMessage = "Restore failed: the project has no repository URL to clone from"
+			default:
+				m.errorMessage = fmt.Sprintf("Restore failed: %v", msg.err)
+			}
+			// The reload shows the failure kept as the project's last error
+			return m, reloadProjectsCmd(m.statusFilter)
 		} else {
 			// SUCCESS: Reload list from database to fix filtering and prevent duplicates
 			m.errorMessage = ""
 			m.statusMessage = "Project restored successfully"
-			return m, reloadProjectsCmd()
+			return m, reloadProjectsCmd(m.statusFilter)
 		}
 
+	case InitProjectMsg:
+		return m.projectInitialized(msg)
+
+	case RestoreRefMsg:
+		return m.restoreRefSaved(msg)
+
+	case RunConfigMsg:
+		return m.runConfigSuggested(msg)
+
+	case RunConfigWrittenMsg:
+		return m.runConfigWritten(msg)
+
+	case NeverRegisterMsg:
+		return m.neverRegistered(msg)
+
+	case IconMsg:
+		return m.iconSaved(msg)
+
+	case CloudBackupsMsg:
+		return m.cloudBackupsFound(msg)
+
+	case BackupAdoptedMsg:
+		return m.backupAdopted(msg)
+
+	case RunCapturedMsg:
+		return m.runCaptured(msg)
+
+	case CloneClipboardMsg:
+		return m.cloneClipboardRead(msg)
+
 	case CloneMsg:
 		// Handle clone completion
-		if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			m.errorMessage = ""
+			m.statusMessage = "Clone cancelled"
+		} else if msg.err != nil {
 			m.errorMessage = fmt.Sprintf("Clone failed: %v", msg.err)
 			m.statusMessage = ""
 		} else {
 			m.errorMessage = ""
-			m.statusMessage = fmt.Sprintf("Successfully cloned %s", msg.projectName)
-			// Reload the list to show the new project
-			return m, reloadProjectsCmd()
+			m.statusMessage = fmt.Sprintf("Successfully cloned %s into %s", msg.projectName, msg.projectPath)
+			// Reload the list to show the new project, and offer a run configuration when it has none
+			return m, tea.Batch(reloadProjectsCmd(m.statusFilter), suggestRunConfigCmd(msg.projectName, msg.projectPath))
 		}
 		return m, nil
 
@@ -721,14 +1581,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case OpenProjectMsg:
-		// Handle VS Code open completion
+		// Handle editor open completion
 		if msg.err != nil {
-			m.errorMessage = fmt.Sprintf("Failed to open VS Code: %v", msg.err)
+			m.errorMessage = fmt.Sprintf("Failed to open %s: %v", msg.editor, msg.err)
 		} else {
 			m.errorMessage = "" // Clear error on success
 		}
 		return m, nil
 
+	case TmuxSessionMsg:
+		return m.tmuxSessionReady(msg)
+
+	case DevContainerMsg:
+		return m.devContainerUp(msg)
+
+	case PinMsg:
+		return m.pinDone(msg)
+
+	case LockMsg:
+		return m.lockDone(msg)
+
 	case OpenBrowserMsg:
 		// Handle browser open completion
 		if msg.err != nil {
@@ -763,7 +1635,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ScanCompleteMsg:
 		// Handle scan completion
-		m.isScanning = false
+		if errors.Is(msg.err, context.Canceled) {
+			m.errorMessage = ""
+			m.statusMessage = "Scan cancelled"
+			return m, nil
+		}
 		if msg.err != nil {
 			m.errorMessage = fmt.Sprintf("Scan failed: %v", msg.err)
 			m.statusMessage = ""
@@ -773,13 +1649,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.statusMessage = fmt.Sprintf("Scan complete: Found %d projects, added %d new", msg.projectsFound, msg.projectsAdded)
 			}
+			m.scanReported(msg)
 			m.errorMessage = ""
-			// Switch to list view if we're on setup screen
-			if m.screen == screenSetupPath || m.screen == screenSetupGitHub {
-				m.screen = screenList
-			}
 			// Reload the list
-			return m, reloadProjectsCmd()
+			return m, reloadProjectsCmd(m.statusFilter)
 		}
 		return m, nil
 
@@ -788,36 +1661,59 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.errorMessage = fmt.Sprintf("Failed to clear projects: %v", msg.err)
 			m.statusMessage = ""
+		} else if msg.rootName != "" {
+			m.statusMessage = fmt.Sprintf("Cleared %d projects of %s; press s to scan it again", msg.count, msg.rootName)
+			m.errorMessage = ""
+			return m, reloadProjectsCmd(m.statusFilter)
 		} else {
 			m.statusMessage = fmt.Sprintf("Cleared %d projects from database", msg.count)
 			m.errorMessage = ""
 			// Clear the list
 			m.list.SetItems([]list.Item{})
-			// Switch to setup screen
-			m.screen = screenSetupPath
-
-			// Create and focus new path input
-			ti := textinput.New()
-			ti.Placeholder = "Enter path (e.g., D:\\\\Projects)"
-			ti.Focus()
-			ti.CharLimit = 256
-			ti.Width = 60
+			// Set up root folders again, keeping the chosen database
+			return m.startWizard(wizardStepFolders)
+		}
+		return m, nil
 
-			// Reset path input with stored or home directory
-			if m.rootScanPath != "" {
-				ti.SetValue(m.rootScanPath)
-			} else if homeDir, err := os.UserHomeDir(); err == nil {
-				ti.SetValue(homeDir)
+	case reloadMsg:
+		// Reload the list with new items, keeping known missing-path markers, marks and metadata
+		for i, item := range msg.items {
+			if pi, ok := item.(projectItem); ok {
+				pi.missing = m.missingPaths[pi.project.ID]
+				pi.marked = m.marked[pi.project.ID]
+				pi.meta = m.metadata[pi.project.ID]
+				pi.runs = m.devRuns[pi.project.ID]
+				msg.items[i] = pi
 			}
+		}
+		m.list.SetItems(assignHotkeys(msg.items, m.recentHotkeys))
+		return m, tea.Batch(checkPathsCmd(m.list.Items(), false), m.projectMetadataCmd(m.list.Items()), m.selectedRepoMetaCmd(), m.footerStatsCmd())
 
-			m.pathInput = ti
-			return m, textinput.Blink
+	case RemoveProjectMsg:
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to remove project: %v", msg.err)
+			m.statusMessage = ""
+			return m, nil
 		}
-		return m, nil
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Removed %s from DevBase", msg.projectName)
+		return m, reloadProjectsCmd(m.statusFilter)
 
-	case reloadMsg:
-		// Reload the list with new items
-		m.list.SetItems(msg.items)
+	case SyncPreviewMsg:
+		// Review the changes before pushing to the cloud
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to compare with cloud: %v", msg.err)
+			m.statusMessage = ""
+			return m, nil
+		}
+		if msg.diff.Empty() && msg.damaged == nil && !msg.blocked {
+			m.errorMessage = ""
+			m.statusMessage = "Cloud backup is already up to date"
+			return m, nil
+		}
+		m = m.showSyncDiff(syncDirectionPush, msg.diff)
+		m.syncDamaged = msg.damaged
+		m.syncSecrets, m.syncBlocked = msg.secrets, msg.blocked
 		return m, nil
 
 	case SyncToCloudMsg:
@@ -828,8 +1724,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.errorMessage = ""
 			m.statusMessage = fmt.Sprintf("Projects synced to cloud (Gist ID: %s)", msg.gistID)
-			// Save the gist ID to config
-			go db.SetConfig("gist_id", msg.gistID)
 		}
 		return m, nil
 
@@ -842,7 +1736,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errorMessage = ""
 			m.statusMessage = fmt.Sprintf("Loaded %d projects from cloud", msg.projectsLoaded)
 			// Reload the list to show loaded projects
-			return m, reloadProjectsCmd()
+			return m, reloadProjectsCmd(m.statusFilter)
 		}
 		return m, nil
 
@@ -854,8 +1748,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.cloudProjects = msg.projects
-		m.selectedCloudIndices = []int{}
-		m.cloudCursorIndex = 0 // Initialize cursor at first item
+		listWidth, listHeight := cloudListSize(m.width, m.height)
+		m.cloudList = newCloudList(msg.projects, listWidth, listHeight)
 		m.screen = screenCloudSelect
 		m.statusMessage = ""
 		m.errorMessage = ""
@@ -871,7 +1765,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusMessage = fmt.Sprintf("Loaded %d projects from cloud (marked as archived)", msg.projectsLoaded)
 		m.errorMessage = ""
 		// Reload the list to show the new archived projects
-		return m, reloadProjectsCmd()
+		return m, reloadProjectsCmd(m.statusFilter)
 
 	case FetchReposMsg:
 		// Handle fetch user repositories completion
@@ -896,14 +1790,86 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, nil
 
+	case PickerReposMsg:
+		// Handle the first page of starred or organization repositories
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			m.statusMessage = ""
+			return m, nil
+		}
+		if len(msg.repos) == 0 && !msg.more {
+			m.errorMessage = ""
+			m.statusMessage = "You haven't starred any repositories yet"
+			if msg.org != "" {
+				m.statusMessage = fmt.Sprintf("No repositories found in %s", msg.org)
+			}
+			return m, nil
+		}
+		m.pickerOrg = msg.org
+		m.pickerRepos = msg.repos
+		m.pickerPage = msg.page
+		m.pickerMore = msg.more
+		m.pickerLoading = false
+		listWidth, listHeight := cloudListSize(m.width, m.height)
+		m.pickerList = newPickerList(msg.org, msg.repos, m.rootScanPath, listWidth, listHeight)
+		m.screen = screenRepoPicker
+		m.statusMessage = ""
+		m.errorMessage = ""
+		return m, nil
+
+	case CloneReposMsg:
+		// Handle batch clone completion
+		m.statusMessage = cloneReposStatus(msg)
+		m.errorMessage = ""
+		if len(msg.failed) > 0 {
+			m.errorMessage = "Clone failed: " + strings.Join(msg.failed, "; ")
+		}
+		if msg.cloned == 0 {
+			return m, nil
+		}
+		// Reload the list to show the new projects
+		return m, reloadProjectsCmd(m.statusFilter)
+
+	case OpenSessionMsg:
+		// Handle session open completion
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to open %s in %s: %v", msg.name, msg.editor, msg.err)
+			m.statusMessage = ""
+			return m, nil
+		}
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Opened %d projects in %s", msg.opened, msg.editor)
+		if msg.skipped > 0 {
+			m.statusMessage += fmt.Sprintf(" (%d archived or missing skipped)", msg.skipped)
+		}
+		return m, nil
+
+	case SaveNotesMsg:
+		// Handle notes save completion
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to save notes: %v", msg.err)
+			m.statusMessage = ""
+			return m, nil
+		}
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Notes saved for %s", msg.projectName)
+		return m, reloadProjectsCmd(m.statusFilter)
+
 	case ErrorMsg:
 		m.errorMessage = msg.err.Error()
 		return m, nil
 	}
 
+	// Keep the notes editor cursor blinking
+	if m.editingNotes {
+		var cmd tea.Cmd
+		m.notesInput, cmd = m.notesInput.Update(msg)
+		return m, cmd
+	}
+
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	return m, tea.Batch(cmd, m.selectedRepoMetaCmd())
 }
 
 // updateSetup handles updates for the setup screen
@@ -914,40 +1880,7 @@ func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "enter":
-			if m.screen == screenSetupPath {
-				// Handle path input
-				if m.pathInput.Value() == "" {
-					m.errorMessage = "Please enter a valid path"
-					return m, nil
-				}
-
-				pathValue := m.pathInput.Value()
-				folderName := filepath.Base(pathValue)
-
-				// Create a root folder for this path
-				rootFolder := &models.RootFolder{
-					Name:     folderName,
-					Path:     pathValue,
-					IsActive: true, // First folder is active by default
-				}
-
-				if err := db.AddRootFolder(rootFolder); err != nil {
-					m.errorMessage = fmt.Sprintf("Failed to create root folder: %v", err)
-					return m, nil
-				}
-
-				m.isScanning = true
-				m.statusMessage = "Scanning for projects..."
-				m.errorMessage = ""
-				m.rootScanPath = pathValue
-				m.activeRootFolderID = rootFolder.ID
-
-				// Save root path to config for backward compatibility
-				_ = db.SetConfig("root_scan_path", pathValue)
-
-				// Scan with the root folder ID
-				return m, scanRootFolderCmd(rootFolder.ID, pathValue)
-			} else if m.screen == screenSetupGitHub {
+			if m.screen == screenSetupGitHub {
 				// User pressed enter to start OAuth flow
 				m.statusMessage = "Initiating GitHub authentication..."
 				m.errorMessage = ""
@@ -960,30 +1893,22 @@ func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				// Validate token before saving
-				validationClient, err := engine.NewGistClient(token, 0) // Use 0 for validation only
+				// Validate token before saving; this also reads its scopes
+				scopes, err := engine.FetchTokenScopes(token)
 				if err != nil {
-					m.errorMessage = "Failed to create validation client."
-					return m, nil
-				}
-				if err := validationClient.ValidateToken(); err != nil {
 					m.errorMessage = "Invalid GitHub token. Please check your token and try again."
 					return m, nil
 				}
 
 				// Save token to config
-				_ = db.SetConfig("github_token", token)
-				m.statusMessage = "GitHub token configured successfully"
+				m.statusMessage = "GitHub token configured successfully" + m.saveGitHubToken(token, engine.TokenSourcePAT, &scopes)
 				m.errorMessage = ""
-				m.screen = screenList
-				return m, reloadProjectsCmd()
+				return m.leaveGitHubSetup(true)
 			}
 		default:
 			// For any other key, pass it to the appropriate text input
 			var cmd tea.Cmd
-			if m.screen == screenSetupPath {
-				m.pathInput, cmd = m.pathInput.Update(msg)
-			} else if m.screen == screenSetupToken {
+			if m.screen == screenSetupToken {
 				if msg.String() == "esc" {
 					// Go back to GitHub setup screen
 					m.screen = screenSetupGitHub
@@ -996,9 +1921,13 @@ func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// On GitHub setup screen, handle skip or PAT option
 				if msg.String() == "s" {
 					// Skip OAuth setup
-					m.screen = screenList
 					m.statusMessage = "Skipped GitHub authentication. You can configure it later with 't'."
-					return m, reloadProjectsCmd()
+					return m.leaveGitHubSetup(false)
+				} else if msg.String() == "g" && engine.GHCLIAvailable() {
+					// Reuse the GitHub CLI's authentication
+					m.statusMessage = "Reading GitHub CLI token..."
+					m.errorMessage = ""
+					return m, ghTokenCmd()
 				} else if msg.String() == "p" {
 					// Switch to manual token entry
 					m.screen = screenSetupToken
@@ -1011,25 +1940,11 @@ func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ti.CharLimit = 256
 					ti.Width = 60
 					m.tokenInput = ti
-					return m, textinput.Blink
-				}
-			}
-			return m, cmd
-		}
-
-	case ScanCompleteMsg:
-		// Handle scan completion
-		m.isScanning = false
-		if msg.err != nil {
-			m.errorMessage = fmt.Sprintf("Scan failed: %v", msg.err)
-			m.statusMessage = ""
-			return m, nil
+					return m, textinput.Blink
+				}
+			}
+			return m, cmd
 		}
-		m.statusMessage = fmt.Sprintf("Found %d projects, added %d to database", msg.projectsFound, msg.projectsAdded)
-		// Switch to GitHub setup screen
-		m.screen = screenSetupGitHub
-		m.errorMessage = ""
-		return m, nil
 
 	case OAuthDeviceCodeMsg:
 		// Handle device code response
@@ -1074,15 +1989,27 @@ func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, textinput.Blink
 		}
 		// Save token to config
-		_ = db.SetConfig("github_token", msg.accessToken)
-		m.statusMessage = "GitHub authentication successful!"
+		m.statusMessage = "GitHub authentication successful!" + m.saveGitHubToken(msg.accessToken, engine.TokenSourceOAuth, msg.scopes)
 		m.errorMessage = ""
-		m.screen = screenList
-		return m, reloadProjectsCmd()
+		return m.leaveGitHubSetup(true)
+
+	case GHTokenMsg:
+		// Stay on the setup screen so another option can be chosen
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Could not use GitHub CLI: %v", msg.err)
+			m.statusMessage = ""
+			return m, nil
+		}
+		m.statusMessage = "Using GitHub CLI authentication" + m.saveGitHubToken(msg.token, engine.TokenSourceGHCLI, &msg.scopes)
+		m.errorMessage = ""
+		return m.leaveGitHubSetup(true)
+
+	case TokenScopesMsg:
+		return m.updateTokenScopes(msg)
 
 	case reloadMsg:
 		// Load projects into list and switch to list screen
-		m.list.SetItems(msg.items)
+		m.list.SetItems(assignHotkeys(msg.items, m.recentHotkeys))
 		m.screen = screenList
 		return m, nil
 	}
@@ -1093,269 +2020,53 @@ func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 // updateCloudSelect handles updates for the cloud project selection screen
 func (m model) updateCloudSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.cloudList.SetSize(cloudListSize(msg.Width, msg.Height))
+		return m, nil
+
 	case tea.KeyMsg:
-		// Handle filter input when filtering mode is active
-		if m.cloudFiltering {
-			switch msg.String() {
-			case "esc":
-				// Exit filter mode
-				m.cloudFiltering = false
-				m.cloudFilterInput.Blur()
-				m.cloudFilterInput.SetValue("")
-				m.cloudCursorIndex = 0
-				m.errorMessage = ""
-				return m, nil
-			case "enter":
-				// Exit filter mode and keep the filter
-				m.cloudFiltering = false
-				m.cloudFilterInput.Blur()
-				m.cloudCursorIndex = 0
-				m.errorMessage = ""
-				return m, nil
-			default:
-				// Update filter input
-				var cmd tea.Cmd
-				m.cloudFilterInput, cmd = m.cloudFilterInput.Update(msg)
-				// Reset cursor when filter changes
-				m.cloudCursorIndex = 0
-				m.errorMessage = ""
-				return m, cmd
-			}
+		// While typing a filter, let the list handle all keys
+		if m.cloudList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.cloudList, cmd = m.cloudList.Update(msg)
+			m.errorMessage = ""
+			return m, cmd
 		}
 
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.screen = screenList
 			m.cloudProjects = nil
-			m.selectedCloudIndices = nil
-			m.cloudCursorIndex = 0
-			m.cloudFiltering = false
-			m.cloudFilterInput.SetValue("")
 			return m, nil
 
-		case "enter":
-			if len(m.selectedCloudIndices) == 0 {
-				m.errorMessage = "Please select at least one project"
+		case "esc":
+			// Clear an applied filter first, otherwise cancel
+			if m.cloudList.FilterState() == list.FilterApplied {
+				m.cloudList.ResetFilter()
 				return m, nil
 			}
-			// Load selected projects
-			return m, loadSelectedProjectsCmd(m.selectedCloudIndices, m.cloudProjects)
-
-		case "esc":
 			m.screen = screenList
 			m.cloudProjects = nil
-			m.selectedCloudIndices = nil
-			m.cloudCursorIndex = 0
-			m.cloudFiltering = false
-			m.cloudFilterInput.SetValue("")
-			return m, nil
-
-		case "/":
-			// Enter filter mode
-			m.cloudFiltering = true
-			m.cloudFilterInput.Focus()
-			m.errorMessage = ""
-			return m, textinput.Blink
-
-		case "up", "k":
-			// Move cursor up in filtered list
-			filteredIndices := m.getFilteredIndices()
-			if len(filteredIndices) == 0 {
-				return m, nil
-			}
-
-			// Find current position in filtered list
-			currentPos := -1
-			for i, idx := range filteredIndices {
-				if idx == m.cloudCursorIndex {
-					currentPos = i
-					break
-				}
-			}
-
-			// Move to previous item in filtered list
-			if currentPos > 0 {
-				m.cloudCursorIndex = filteredIndices[currentPos-1]
-			} else if currentPos == -1 && len(filteredIndices) > 0 {
-				// Cursor not in filtered list, move to last filtered item
-				m.cloudCursorIndex = filteredIndices[len(filteredIndices)-1]
-			}
-			m.errorMessage = ""
-			return m, nil
-
-		case "down", "j":
-			// Move cursor down in filtered list
-			filteredIndices := m.getFilteredIndices()
-			if len(filteredIndices) == 0 {
-				return m, nil
-			}
-
-			// Find current position in filtered list
-			currentPos := -1
-			for i, idx := range filteredIndices {
-				if idx == m.cloudCursorIndex {
-					currentPos = i
-					break
-				}
-			}
-
-			// Move to next item in filtered list
-			if currentPos >= 0 && currentPos < len(filteredIndices)-1 {
-				m.cloudCursorIndex = filteredIndices[currentPos+1]
-			} else if currentPos == -1 && len(filteredIndices) > 0 {
-				// Cursor not in filtered list, move to first filtered item
-				m.cloudCursorIndex = filteredIndices[0]
-			}
-			m.errorMessage = ""
-			return m, nil
-
-		case "pgup":
-			// Jump up by 10 items in filtered list
-			filteredIndices := m.getFilteredIndices()
-			if len(filteredIndices) == 0 {
-				return m, nil
-			}
-
-			currentPos := -1
-			for i, idx := range filteredIndices {
-				if idx == m.cloudCursorIndex {
-					currentPos = i
-					break
-				}
-			}
-
-			if currentPos >= 0 {
-				newPos := max(0, currentPos-10)
-				m.cloudCursorIndex = filteredIndices[newPos]
-			} else if len(filteredIndices) > 0 {
-				m.cloudCursorIndex = filteredIndices[len(filteredIndices)-1]
-			}
-			m.errorMessage = ""
 			return m, nil
 
-		case "pgdown":
-			// Jump down by 10 items in filtered list
-			filteredIndices := m.getFilteredIndices()
-			if len(filteredIndices) == 0 {
+		case "enter":
+			selected := selectedCloudIndices(m.cloudList)
+			if len(selected) == 0 {
+				m.errorMessage = "Please select at least one project"
 				return m, nil
 			}
-
-			currentPos := -1
-			for i, idx := range filteredIndices {
-				if idx == m.cloudCursorIndex {
-					currentPos = i
-					break
-				}
-			}
-
-			if currentPos >= 0 {
-				newPos := min(len(filteredIndices)-1, currentPos+10)
-				m.cloudCursorIndex = filteredIndices[newPos]
-			} else if len(filteredIndices) > 0 {
-				m.cloudCursorIndex = filteredIndices[0]
-			}
-			m.errorMessage = ""
-			return m, nil
-
-		case "home", "g":
-			// Jump to first item in filtered list
-			filteredIndices := m.getFilteredIndices()
-			if len(filteredIndices) > 0 {
-				m.cloudCursorIndex = filteredIndices[0]
-			}
-			m.errorMessage = ""
-			return m, nil
-
-		case "end", "G":
-			// Jump to last item in filtered list
-			filteredIndices := m.getFilteredIndices()
-			if len(filteredIndices) > 0 {
-				m.cloudCursorIndex = filteredIndices[len(filteredIndices)-1]
-			}
-			m.errorMessage = ""
-			return m, nil
-
-		case " ", "tab":
-			// Toggle selection at current cursor position
-			idx := m.cloudCursorIndex
-			found := false
-			for i, selectedIdx := range m.selectedCloudIndices {
-				if selectedIdx == idx {
-					// Remove from selection
-					m.selectedCloudIndices = append(m.selectedCloudIndices[:i], m.selectedCloudIndices[i+1:]...)
-					found = true
-					break
-				}
-			}
-			if !found {
-				// Add to selection
-				m.selectedCloudIndices = append(m.selectedCloudIndices, idx)
-			}
-			m.errorMessage = ""
-			return m, nil
-
-		case "a":
-			// Select all filtered projects
-			filteredIndices := m.getFilteredIndices()
-			m.selectedCloudIndices = filteredIndices
-			m.errorMessage = ""
-			if len(filteredIndices) == len(m.cloudProjects) {
-				m.statusMessage = fmt.Sprintf("Selected all %d projects", len(filteredIndices))
-			} else {
-				m.statusMessage = fmt.Sprintf("Selected all %d filtered projects", len(filteredIndices))
-			}
-			return m, nil
-
-		case "n":
-			// Clear all selections
-			m.selectedCloudIndices = nil
-			m.errorMessage = ""
-			m.statusMessage = "Cleared all selections"
-			return m, nil
-
-		case "i":
-			// Invert selection
-			newSelection := []int{}
-			for i := range m.cloudProjects {
-				isSelected := false
-				for _, selectedIdx := range m.selectedCloudIndices {
-					if selectedIdx == i {
-						isSelected = true
-						break
-					}
-				}
-				if !isSelected {
-					newSelection = append(newSelection, i)
-				}
-			}
-			m.selectedCloudIndices = newSelection
-			m.errorMessage = ""
-			m.statusMessage = fmt.Sprintf("Inverted selection (%d selected)", len(newSelection))
-			return m, nil
+			// Review what loading will change before applying it
+			m.syncLoadIndices = selected
+			return m.showSyncDiff(syncDirectionLoad, loadDiff(selected, m.cloudProjects)), nil
 
 		default:
-			// Handle number keys for quick selection (1-9) - legacy support
-			if len(msg.String()) == 1 {
-				num := int(msg.String()[0] - '0')
-				if num >= 1 && num <= min(9, len(m.cloudProjects)) {
-					idx := num - 1
-					// Toggle selection
-					found := false
-					for i, selectedIdx := range m.selectedCloudIndices {
-						if selectedIdx == idx {
-							// Remove from selection
-							m.selectedCloudIndices = append(m.selectedCloudIndices[:i], m.selectedCloudIndices[i+1:]...)
-							found = true
-							break
-						}
-					}
-					if !found {
-						// Add to selection
-						m.selectedCloudIndices = append(m.selectedCloudIndices, idx)
-					}
-					m.errorMessage = ""
-					return m, nil
+			// Selection keys: space/tab, a=all, n=none, i=invert, 1-9
+			if cmd, status, ok := multiSelectKey(&m.cloudList, msg.String(), "projects"); ok {
+				m.errorMessage = ""
+				if status != "" {
+					m.statusMessage = status
 				}
+				return m, cmd
 			}
 		}
 
@@ -1368,13 +2079,14 @@ func (m model) updateCloudSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.errorMessage = ""
 		m.screen = screenList
 		m.cloudProjects = nil
-		m.selectedCloudIndices = nil
-		m.cloudCursorIndex = 0
 		// Reload the list to show the new archived projects
-		return m, reloadProjectsCmd()
+		return m, reloadProjectsCmd(m.statusFilter)
 	}
 
-	return m, nil
+	// Navigation, paging and filter results are handled by the list
+	var cmd tea.Cmd
+	m.cloudList, cmd = m.cloudList.Update(msg)
+	return m, cmd
 }
 
 // updateRepoSelect handles updates for the GitHub repository selection screen
@@ -1446,7 +2158,7 @@ func (m model) updateRepoSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMessage = fmt.Sprintf("Cloning %s...", selectedRepo.FullName)
 			m.errorMessage = ""
 
-			return m, cloneProjectCmd(selectedRepo.CloneURL, m.rootScanPath)
+			return m, m.cloneProjectCmd(selectedRepo.CloneURL, m.rootScanPath, "")
 
 		case "/":
 			// Enter filter mode
@@ -1669,7 +2381,7 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errorMessage = ""
 
 				// Execute command in root folder
-				return m, executeCommandCmd(selectedFolder.Path, command)
+				return m, executeCommandCmd(selectedFolder.Path, command, "")
 			case "esc":
 				m.confirmExecuteCommand = false
 				m.statusMessage = "Command execution cancelled"
@@ -1689,12 +2401,21 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "ctrl+c":
 				return m, tea.Quit
 			case "enter":
-				folderPath := m.rootFolderInput.Value()
+				folderPath := strings.TrimSpace(m.rootFolderInput.Value())
 				if folderPath == "" {
 					m.errorMessage = "Please enter a valid folder path"
 					return m, nil
 				}
 
+				// Normalize to an absolute path and make sure it is an existing directory
+				if absPath, err := filepath.Abs(folderPath); err == nil {
+					folderPath = absPath
+				}
+				if info, err := os.Stat(folderPath); err != nil || !info.IsDir() {
+					m.errorMessage = fmt.Sprintf("Folder does not exist: %s", folderPath)
+					return m, nil
+				}
+
 				// Extract folder name from path
 				folderName := filepath.Base(folderPath)
 
@@ -1711,12 +2432,13 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// Reload root folders
-				rootFolders, err := db.GetAllRootFolders()
+				rootFolders, counts, err := loadRootFolders()
 				if err != nil {
 					m.errorMessage = fmt.Sprintf("Failed to reload root folders: %v", err)
 					return m, nil
 				}
 				m.rootFolders = rootFolders
+				m.rootFolderCounts = counts
 
 				m.addingRootFolder = false
 				m.statusMessage = "Root folder added successfully"
@@ -1761,7 +2483,7 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.screen = screenList
 			m.errorMessage = ""
 			m.statusMessage = ""
-			return m, reloadProjectsCmd()
+			return m, reloadProjectsCmd(m.statusFilter)
 
 		case "up", "k":
 			if m.rootFolderCursor > 0 {
@@ -1798,7 +2520,7 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Return to main screen and reload projects
 			m.screen = screenList
-			return m, reloadProjectsCmd()
+			return m, reloadProjectsCmd(m.statusFilter)
 
 		case "a":
 			// Add new root folder
@@ -1841,6 +2563,7 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "y":
 			// Confirm deletion
 			if m.confirmingDeleteRootFolder && m.rootFolderToDelete != nil {
+				wasActive := m.rootFolderToDelete.IsActive
 				if err := db.DeleteRootFolder(m.rootFolderToDelete.ID); err != nil {
 					m.errorMessage = fmt.Sprintf("Failed to remove root folder: %v", err)
 					m.confirmingDeleteRootFolder = false
@@ -1849,7 +2572,7 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// Reload root folders
-				rootFolders, err := db.GetAllRootFolders()
+				rootFolders, counts, err := loadRootFolders()
 				if err != nil {
 					m.errorMessage = fmt.Sprintf("Failed to reload root folders: %v", err)
 					m.confirmingDeleteRootFolder = false
@@ -1857,6 +2580,20 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				m.rootFolders = rootFolders
+				m.rootFolderCounts = counts
+
+				// If the active folder was removed, promote the first remaining folder
+				if wasActive && len(m.rootFolders) > 0 {
+					newActive := m.rootFolders[0]
+					if err := db.SetActiveRootFolder(newActive.ID); err != nil {
+						m.errorMessage = fmt.Sprintf("Failed to set active root folder: %v", err)
+					} else {
+						m.rootFolders[0].IsActive = true
+						m.activeRootFolderID = newActive.ID
+						m.rootScanPath = newActive.Path
+						_ = db.SetConfig("root_scan_path", newActive.Path)
+					}
+				}
 
 				// Adjust cursor if needed
 				if m.rootFolderCursor >= len(m.rootFolders) {
@@ -1892,7 +2629,7 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 			selectedFolder := m.rootFolders[m.rootFolderCursor]
 			m.statusMessage = fmt.Sprintf("Scanning %s...", selectedFolder.Name)
 			m.errorMessage = ""
-			return m, scanRootFolderCmd(selectedFolder.ID, selectedFolder.Path)
+			return m, m.scanRootFolderCmd(selectedFolder.ID, selectedFolder.Path)
 
 		case "e":
 			// Execute a custom command in the selected root folder
@@ -1920,6 +2657,31 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return m, textinput.Blink
 		}
+
+	case ScanCompleteMsg:
+		// Handle scan of the selected root folder
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Scan failed: %v", msg.err)
+			m.statusMessage = ""
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("Scan complete: Found %d, added %d new, removed %d", msg.projectsFound, msg.projectsAdded, msg.projectsRemoved)
+		m.scanReported(msg)
+		m.errorMessage = ""
+		if counts, err := db.CountProjectsByRootFolder(); err == nil {
+			m.rootFolderCounts = counts
+		}
+		return m, nil
+
+	case ExecuteCommandMsg:
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Command execution failed: %v", msg.err)
+			m.statusMessage = ""
+		} else {
+			m.errorMessage = ""
+			m.statusMessage = "Command executed successfully"
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -1927,7 +2689,11 @@ func (m model) updateRootFolderManage(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the UI
 func (m model) View() string {
-	if m.screen == screenSetupPath || m.screen == screenSetupGitHub || m.screen == screenSetupToken || m.screen == screenOAuthWaiting {
+	defer recoverCrash()
+	if m.screen == screenWizard {
+		return m.viewWizard()
+	}
+	if m.screen == screenSetupGitHub || m.screen == screenSetupToken || m.screen == screenOAuthWaiting {
 		return m.viewSetup()
 	}
 	if m.screen == screenCloudSelect {
@@ -1939,6 +2705,42 @@ func (m model) View() string {
 	if m.screen == screenRepoSelect {
 		return m.viewRepoSelect()
 	}
+	if m.screen == screenRepoPicker {
+		return m.viewRepoPicker()
+	}
+	if m.screen == screenSyncDiff {
+		return m.viewSyncDiff()
+	}
+	if m.screen == screenActivity {
+		return m.viewActivity()
+	}
+	if m.screen == screenLogs {
+		return m.viewLogs()
+	}
+	if m.screen == screenStale {
+		return m.viewStale()
+	}
+	if m.screen == screenReclaim {
+		return m.viewReclaim()
+	}
+	if m.screen == screenRunLogs {
+		return m.viewRunLogs()
+	}
+	if m.screen == screenRunOutput {
+		return m.viewRunStream()
+	}
+	if m.screen == screenJobs {
+		return m.viewJobs()
+	}
+	if m.screen == screenWorktrees {
+		return m.viewWorktrees()
+	}
+	if m.screen == screenScanReport {
+		return m.viewScanReport()
+	}
+	if m.screen == screenTrash {
+		return m.viewTrash()
+	}
 	return m.viewList()
 }
 
@@ -1946,62 +2748,30 @@ func (m model) View() string {
 func (m model) viewSetup() string {
 	var s string
 
-	if m.screen == screenSetupPath {
-		// Title
-		title := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FFFF")).
-			Bold(true).
-			Render("\n╔═══════════════════════════════════════════════════════════╗\n" +
-				"║              Welcome to DevBase v1.0.0                  ║\n" +
-				"╚═══════════════════════════════════════════════════════════╝\n")
-		s += title
-
-		// Prompt
-		prompt := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Render("\nEnter the root folder path for your projects:\n")
-		s += prompt
-
-		// Hint
-		hint := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Italic(true).
-			Render("(e.g., D:\\\\Projects or C:\\\\Users\\\\YourName\\\\workspace)\n\n")
-		s += hint
-
-		// Input field
-		s += m.pathInput.View() + "\n"
-
-		// Help text
-		helpText := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Render("\nPress Enter to start scan | Ctrl+C to quit")
-		s += helpText
-
-	} else if m.screen == screenSetupGitHub {
+	if m.screen == screenSetupGitHub {
 		// Title box with consistent styling
 		titleBox := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#00FFFF")).
+			BorderForeground(colorAccent).
 			Padding(0, 2).
 			Bold(true).
-			Foreground(lipgloss.Color("#00FFFF")).
+			Foreground(colorAccent).
 			Render("Configure GitHub Integration")
 
-		s += "\n" + titleBox + "\n\n"
+		s += "\n" + titleBox + "\n\n" + m.viewTokenScopes()
 
 		// Authentication options
 		oauthBox := lipgloss.NewStyle().
 			Width(58).
 			Padding(1, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#00FF00")).
+			BorderForeground(colorSuccess).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true).Render("Option 1: OAuth Device Flow (Recommended)") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("• Secure browser-based authentication") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("• No manual token creation needed") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("• Automatic token management") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Press ENTER to start OAuth flow"),
+				lipgloss.NewStyle().Foreground(colorSuccess).Bold(true).Render("Option 1: OAuth Device Flow (Recommended)") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render("• Secure browser-based authentication") + "\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render("• No manual token creation needed") + "\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render("• Automatic token management") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorDim).Render("Press ENTER to start OAuth flow"),
 			)
 
 		s += oauthBox + "\n\n"
@@ -2010,20 +2780,35 @@ func (m model) viewSetup() string {
 			Width(58).
 			Padding(1, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#FFFF00")).
+			BorderForeground(colorCaution).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true).Render("Option 2: Personal Access Token") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("• Manual token creation required") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("• Create token at github.com/settings/tokens") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("• Requires 'gist' scope only") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Press P for manual token entry"),
+				lipgloss.NewStyle().Foreground(colorCaution).Bold(true).Render("Option 2: Personal Access Token") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render("• Manual token creation required") + "\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render("• Create token at github.com/settings/tokens") + "\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render("• Requires 'gist' scope only") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorDim).Render("Press P for manual token entry"),
 			)
 
 		s += patBox + "\n\n"
 
+		if engine.GHCLIAvailable() {
+			ghBox := lipgloss.NewStyle().
+				Width(58).
+				Padding(1, 2).
+				Border(lipgloss.NormalBorder()).
+				BorderForeground(colorAccent).
+				Render(
+					lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("Option 3: GitHub CLI") + "\n\n" +
+						lipgloss.NewStyle().Foreground(colorText).Render("• Reuse the token of 'gh auth login'") + "\n" +
+						lipgloss.NewStyle().Foreground(colorText).Render("• No browser step if gh is already signed in") + "\n\n" +
+						lipgloss.NewStyle().Foreground(colorDim).Render("Press G to use GitHub CLI authentication"),
+				)
+			s += ghBox + "\n\n"
+		}
+
 		// Help text
 		skipBox := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("Press S to skip setup  •  Ctrl+C to quit")
 
 		s += skipBox
@@ -2032,10 +2817,10 @@ func (m model) viewSetup() string {
 		// Title box
 		titleBox := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#FFFF00")).
+			BorderForeground(colorCaution).
 			Padding(0, 2).
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFF00")).
+			Foreground(colorCaution).
 			Render("Enter GitHub Personal Access Token")
 
 		s += "\n" + titleBox + "\n\n"
@@ -2045,14 +2830,14 @@ func (m model) viewSetup() string {
 			Width(60).
 			Padding(1, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#444444")).
+			BorderForeground(colorBorder).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("Create a Personal Access Token:") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render("1. Visit: https://github.com/settings/tokens") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render("2. Click 'Generate new token (classic)'") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render("3. Select only 'gist' scope") + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render("4. Copy the token and paste below") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Token will be stored securely in your local database."),
+				lipgloss.NewStyle().Foreground(colorText).Render("Create a Personal Access Token:") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorAccent).Render("1. Visit: https://github.com/settings/tokens") + "\n" +
+					lipgloss.NewStyle().Foreground(colorAccent).Render("2. Click 'Generate new token (classic)'") + "\n" +
+					lipgloss.NewStyle().Foreground(colorAccent).Render("3. Select only 'gist' scope") + "\n" +
+					lipgloss.NewStyle().Foreground(colorAccent).Render("4. Copy the token and paste below") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorDim).Render("Token will be stored securely in your local database."),
 			)
 
 		s += instructions + "\n\n"
@@ -2062,7 +2847,7 @@ func (m model) viewSetup() string {
 
 		// Help text
 		helpText := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("Press Enter to save token  •  Press Esc to go back  •  Ctrl+C to quit")
 
 		s += helpText
@@ -2071,17 +2856,17 @@ func (m model) viewSetup() string {
 		// Title box
 		titleBox := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#00FFFF")).
+			BorderForeground(colorAccent).
 			Padding(0, 2).
 			Bold(true).
-			Foreground(lipgloss.Color("#00FFFF")).
+			Foreground(colorAccent).
 			Render("GitHub Authentication in Progress")
 
 		s += "\n" + titleBox + "\n\n"
 
 		// Instructions header
 		instructionsHeader := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
+			Foreground(colorText).
 			Bold(true).
 			Render("Please complete the following steps:")
 
@@ -2092,10 +2877,10 @@ func (m model) viewSetup() string {
 			Width(60).
 			Padding(1, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#00FF00")).
+			BorderForeground(colorSuccess).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true).Render("STEP 1: Visit this URL") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render(m.oauthVerificationURI),
+				lipgloss.NewStyle().Foreground(colorSuccess).Bold(true).Render("STEP 1: Visit this URL") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorText).Render(m.oauthVerificationURI),
 			)
 
 		s += step1Box + "\n\n"
@@ -2105,10 +2890,10 @@ func (m model) viewSetup() string {
 			Width(60).
 			Padding(1, 2).
 			Border(lipgloss.DoubleBorder()).
-			BorderForeground(lipgloss.Color("#FFFF00")).
+			BorderForeground(colorCaution).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true).Render("STEP 2: Enter this code") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true).Render(m.oauthUserCode),
+				lipgloss.NewStyle().Foreground(colorCaution).Bold(true).Render("STEP 2: Enter this code") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorCaution).Bold(true).Render(m.oauthUserCode),
 			)
 
 		s += step2Box + "\n\n"
@@ -2118,22 +2903,22 @@ func (m model) viewSetup() string {
 			Width(60).
 			Padding(1, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#00FF00")).
+			BorderForeground(colorSuccess).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true).Render("STEP 3: Authorize DevBase") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Grant DevBase access to your Gists"),
+				lipgloss.NewStyle().Foreground(colorSuccess).Bold(true).Render("STEP 3: Authorize DevBase") + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorDim).Render("Grant DevBase access to your Gists"),
 			)
 
 		s += step3Box + "\n\n"
 
 		// Waiting indicator with animation suggestion
 		waitingMsg := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FFFF")).
+			Foreground(colorAccent).
 			Bold(true).
 			Render("⟳ Waiting for authorization...")
 
 		waitingSubtext := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Italic(true).
 			Render("This window will automatically continue once you authorize")
 
@@ -2149,17 +2934,17 @@ func (m model) viewSetup() string {
 	// Display status message if present
 	if m.statusMessage != "" {
 		statusView := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
+			Foreground(colorSuccessDim).
 			Render("\n✓ " + m.statusMessage)
 		s += statusView
 	}
 
 	// Add scanning indicator
-	if m.isScanning {
+	if m.jobRunning(engine.JobScan) {
 		scanIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
+			Foreground(colorSuccess).
 			Bold(true).
-			Render("\n\n⟳ Scanning directories...")
+			Render("\n\n" + tr("list.scanning"))
 		s += scanIndicator
 	}
 
@@ -2171,177 +2956,50 @@ func (m model) viewCloudSelect() string {
 	// Title box
 	titleBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00FFFF")).
+		BorderForeground(colorAccent).
 		Padding(0, 2).
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
-		Render("Select Projects from Cloud")
-
-	s := "\n" + titleBox + "\n\n"
-
-	// Instructions box
-	instructionsBox := lipgloss.NewStyle().
-		Width(68).
-		Padding(1, 2).
-		Border(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("#444444")).
-		Render(
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("Select projects to load from cloud") + "\n" +
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Selected projects will be marked as archived for safety"),
-		)
-	s += instructionsBox + "\n\n"
-
-	// Show filter input if filtering is active
-	if m.cloudFiltering {
-		filterBox := lipgloss.NewStyle().
-			Width(68).
-			Padding(0, 2).
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#00FFFF")).
-			Render(m.cloudFilterInput.View())
-		s += filterBox + "\n\n"
-	}
-
-	// Apply filter to projects and track original indices
-	type filteredProject struct {
-		project     models.Project
-		originalIdx int
-	}
-
-	filteredProjects := []filteredProject{}
-	filterText := strings.ToLower(strings.TrimSpace(m.cloudFilterInput.Value()))
-
-	for i, project := range m.cloudProjects {
-		if filterText == "" {
-			filteredProjects = append(filteredProjects, filteredProject{project: project, originalIdx: i})
-		} else if strings.Contains(strings.ToLower(project.Name), filterText) ||
-			strings.Contains(strings.ToLower(project.Path), filterText) ||
-			strings.Contains(strings.ToLower(project.RepoURL), filterText) {
-			filteredProjects = append(filteredProjects, filteredProject{project: project, originalIdx: i})
-		}
-	}
-
-	// Calculate max name length for proper alignment
-	maxNameLen := 0
-	maxNumberLen := len(fmt.Sprintf("%d", len(filteredProjects)))
-	for _, fp := range filteredProjects {
-		if len(fp.project.Name) > maxNameLen {
-			maxNameLen = len(fp.project.Name)
-		}
-	}
-
-	// Project list container with count
-	projectCountInfo := ""
-	if filterText != "" {
-		projectCountInfo = fmt.Sprintf(" (%d of %d)", len(filteredProjects), len(m.cloudProjects))
-	}
-	projectListHeader := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Bold(true).
-		Render("Available Projects:" + projectCountInfo)
-	s += projectListHeader + "\n\n"
-
-	// If no projects match filter
-	if len(filteredProjects) == 0 {
-		noResultsMsg := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Render("  No projects match the filter")
-		s += noResultsMsg + "\n"
-	}
-
-	// List cloud projects with aligned formatting and cursor
-	for i, fp := range filteredProjects {
-		originalIdx := fp.originalIdx
-		project := fp.project
-
-		isSelected := false
-		for _, idx := range m.selectedCloudIndices {
-			if idx == originalIdx {
-				isSelected = true
-				break
-			}
-		}
-
-		isCursor := (originalIdx == m.cloudCursorIndex)
-
-		// Build the line with proper alignment
-		checkbox := "[ ]"
-		if isSelected {
-			checkbox = "[✓]"
-		}
-
-		// Cursor indicator
-		cursor := " "
-		if isCursor {
-			cursor = "►"
-		}
-
-		number := fmt.Sprintf("%*d.", maxNumberLen, i+1)
-		projectName := fmt.Sprintf("%-*s", maxNameLen, project.Name)
-
-		// Additional info if available
-		var additionalInfo string
-		if project.RepoURL != "" {
-			iconColor := "#666666"
-			if isCursor {
-				iconColor = "#00FFFF"
-			}
-			additionalInfo = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(iconColor)).
-				Render(" 🔗")
-		}
+		Foreground(colorAccent).
+		Render("Select Projects from Cloud")
 
-		// Style based on cursor position and selection
-		lineStyle := lipgloss.NewStyle()
+	s := "\n" + titleBox + "\n\n"
 
-		if isCursor && isSelected {
-			// Cursor on selected item
-			lineStyle = lineStyle.
-				Background(lipgloss.Color("#00AA00")).
-				Foreground(lipgloss.Color("#000000")).
-				Bold(true)
-		} else if isCursor {
-			// Cursor on unselected item
-			lineStyle = lineStyle.
-				Background(lipgloss.Color("#444444")).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Bold(true)
-		} else if isSelected {
-			// Selected but not cursor
-			lineStyle = lineStyle.
-				Foreground(lipgloss.Color("#00FF00")).
-				Bold(true)
-		} else {
-			// Normal item
-			lineStyle = lineStyle.
-				Foreground(lipgloss.Color("#FFFFFF"))
-		}
+	// Instructions box
+	instructionsBox := lipgloss.NewStyle().
+		Width(68).
+		Padding(1, 2).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(colorBorder).
+		Render(
+			lipgloss.NewStyle().Foreground(colorText).Render("Select projects to load from cloud") + "\n" +
+				lipgloss.NewStyle().Foreground(colorDim).Render("Selected projects will be marked as archived for safety"),
+		)
+	s += instructionsBox + "\n\n"
 
-		line := fmt.Sprintf("%s %s %s %s%s", cursor, checkbox, number, projectName, additionalInfo)
-		s += lineStyle.Render(line) + "\n"
-	}
+	// Scrollable, filterable project list
+	s += m.cloudList.View() + "\n"
 
 	// Selection summary
-	if len(m.selectedCloudIndices) > 0 {
+	if selected := len(selectedCloudIndices(m.cloudList)); selected > 0 {
 		summaryBox := lipgloss.NewStyle().
 			MarginTop(1).
 			Padding(0, 2).
-			Foreground(lipgloss.Color("#00FF00")).
-			Render(fmt.Sprintf("✓ %d project(s) selected", len(m.selectedCloudIndices)))
+			Foreground(colorSuccess).
+			Render(fmt.Sprintf("✓ %d project(s) selected", selected))
 		s += "\n" + summaryBox + "\n"
 	} else {
 		summaryBox := lipgloss.NewStyle().
 			MarginTop(1).
 			Padding(0, 2).
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("No projects selected")
 		s += "\n" + summaryBox + "\n"
 	}
 
 	// Compact help text - single line format
 	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888888")).
-		Render("\n↑↓/jk=navigate  space=toggle  /=filter  a=all  n=none  enter=load  esc=cancel")
+		Foreground(colorDim).
+		Render("\n↑↓/jk=navigate  ←→=page  space=toggle  /=filter  a=all  n=none  i=invert  enter=load  esc=cancel")
 	s += helpText
 
 	// Display error message if present
@@ -2353,7 +3011,7 @@ func (m model) viewCloudSelect() string {
 	// Display status message if present
 	if m.statusMessage != "" {
 		statusView := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
+			Foreground(colorSuccessDim).
 			Render("\n✓ " + m.statusMessage)
 		s += statusView
 	}
@@ -2361,15 +3019,28 @@ func (m model) viewCloudSelect() string {
 	return docStyle.Render(s)
 }
 
+// loadRootFolders loads all root folders along with their project counts
+func loadRootFolders() ([]models.RootFolder, map[uint]int64, error) {
+	rootFolders, err := db.GetAllRootFolders()
+	if err != nil {
+		return nil, nil, err
+	}
+	counts, err := db.CountProjectsByRootFolder()
+	if err != nil {
+		return nil, nil, err
+	}
+	return rootFolders, counts, nil
+}
+
 // viewRootFolderManage renders the root folder management screen
 func (m model) viewRootFolderManage() string {
 	// Title box
 	titleBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00FFFF")).
+		BorderForeground(colorAccent).
 		Padding(0, 2).
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
+		Foreground(colorAccent).
 		Render("Manage Root Folders")
 
 	s := "\n" + titleBox + "\n\n"
@@ -2377,23 +3048,23 @@ func (m model) viewRootFolderManage() string {
 	// If confirming deletion
 	if m.confirmingDeleteRootFolder && m.rootFolderToDelete != nil {
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
+			Foreground(colorDanger).
 			Bold(true).
 			Render("⚠  CONFIRM REMOVAL\n\n")
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
+			Foreground(colorText).
 			Render(fmt.Sprintf("Remove root folder: %s\n", m.rootFolderToDelete.Name))
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render(fmt.Sprintf("Path: %s\n\n", m.rootFolderToDelete.Path))
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFAA00")).
+			Foreground(colorWarning).
 			Render("This will remove the folder from DevBase and delete all its project entries.\n")
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("The actual folder on disk will NOT be deleted.\n\n")
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
+			Foreground(colorText).
 			Render("Press 'y' to confirm | 'n' or ESC to cancel")
 		return docStyle.Render(s)
 	}
@@ -2401,11 +3072,11 @@ func (m model) viewRootFolderManage() string {
 	// If adding a new root folder
 	if m.addingRootFolder {
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
+			Foreground(colorText).
 			Render("Enter the path for the new root folder:\n\n")
 		s += m.rootFolderInput.View() + "\n\n"
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("Press Enter to add | ESC to cancel")
 		return docStyle.Render(s)
 	}
@@ -2413,24 +3084,24 @@ func (m model) viewRootFolderManage() string {
 	// Display root folders
 	if len(m.rootFolders) == 0 {
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFAA00")).
+			Foreground(colorWarning).
 			Render("No root folders configured. Press 'a' to add one.")
 	} else {
 		for i, folder := range m.rootFolders {
 			style := lipgloss.NewStyle().Padding(0, 2)
 
 			// Highlight cursor
+			prefix := "  "
 			if i == m.rootFolderCursor {
-				style = style.Background(lipgloss.Color("#333333"))
+				prefix = "► "
+				style = style.Background(colorSelection)
 			}
 
 			// Format folder entry
-			prefix := "  "
 			if folder.IsActive {
-				prefix = "► " // Active marker
-				style = style.Bold(true).Foreground(lipgloss.Color("#00FF00"))
+				style = style.Bold(true).Foreground(colorSuccess)
 			} else {
-				style = style.Foreground(lipgloss.Color("#FFFFFF"))
+				style = style.Foreground(colorText)
 			}
 
 			name := folder.Name
@@ -2442,15 +3113,21 @@ func (m model) viewRootFolderManage() string {
 				gistStatus = " ☁"
 			}
 
-			line := fmt.Sprintf("%s%s%s", prefix, name, gistStatus)
+			// Show project count and active label
+			details := fmt.Sprintf(" (%d projects)", m.rootFolderCounts[folder.ID])
+			if folder.IsActive {
+				details += " [active]"
+			}
+
+			line := fmt.Sprintf("%s%s%s%s", prefix, name, gistStatus, details)
 			s += style.Render(line) + "\n"
 
 			// Show path in gray
 			pathStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
+				Foreground(colorDim).
 				Padding(0, 4)
 			if i == m.rootFolderCursor {
-				pathStyle = pathStyle.Background(lipgloss.Color("#333333"))
+				pathStyle = pathStyle.Background(colorSelection)
 			}
 			s += pathStyle.Render(path) + "\n\n"
 		}
@@ -2461,28 +3138,28 @@ func (m model) viewRootFolderManage() string {
 		selectedFolder := m.rootFolders[m.rootFolderCursor]
 		executePrompt := "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#00FFFF")).
+				Foreground(colorAccent).
 				Bold(true).
 				Render("⚡ EXECUTE COMMAND") + "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFFFFF")).
+				Foreground(colorText).
 				Render(fmt.Sprintf("Root Folder: %s", selectedFolder.Name)) + "\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
+				Foreground(colorDim).
 				Render(fmt.Sprintf("Path: %s", selectedFolder.Path)) + "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFFFFF")).
+				Foreground(colorText).
 				Render("Enter command to execute:") + "\n" +
 			m.executeCommandInput.View() + "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
+				Foreground(colorDim).
 				Render("Press Enter to execute | ESC to cancel")
 		s += executePrompt
 	}
 
 	// Help text
 	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888888")).
+		Foreground(colorDim).
 		Render("\n\nKeys: ↑↓/jk=navigate  enter=switch  a=add  d=delete  s=scan  e=execute  esc=back  q=quit")
 	s += helpText
 
@@ -2495,7 +3172,7 @@ func (m model) viewRootFolderManage() string {
 	// Display status message if present
 	if m.statusMessage != "" {
 		statusView := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
+			Foreground(colorSuccessDim).
 			Render("\n✓ " + m.statusMessage)
 		s += statusView
 	}
@@ -2508,10 +3185,10 @@ func (m model) viewRepoSelect() string {
 	// Title box
 	titleBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00FFFF")).
+		BorderForeground(colorAccent).
 		Padding(0, 2).
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
+		Foreground(colorAccent).
 		Render("Select GitHub Repository to Clone")
 
 	s := "\n" + titleBox + "\n\n"
@@ -2521,10 +3198,10 @@ func (m model) viewRepoSelect() string {
 		Width(68).
 		Padding(1, 2).
 		Border(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("#444444")).
+		BorderForeground(colorBorder).
 		Render(
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("Select a repository to clone from your GitHub account") + "\n" +
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Repositories are sorted by most recently updated"),
+			lipgloss.NewStyle().Foreground(colorText).Render("Select a repository to clone from your GitHub account") + "\n" +
+				lipgloss.NewStyle().Foreground(colorDim).Render("Repositories are sorted by most recently updated"),
 		)
 	s += instructionsBox + "\n\n"
 
@@ -2534,7 +3211,7 @@ func (m model) viewRepoSelect() string {
 			Width(68).
 			Padding(0, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#00FFFF")).
+			BorderForeground(colorAccent).
 			Render(m.repoFilterInput.View())
 		s += filterBox + "\n\n"
 	}
@@ -2548,7 +3225,7 @@ func (m model) viewRepoSelect() string {
 		repoCountInfo = fmt.Sprintf(" (%d of %d)", len(filteredRepos), len(m.userRepos))
 	}
 	repoListHeader := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
+		Foreground(colorAccent).
 		Bold(true).
 		Render(fmt.Sprintf("Your Repositories (%d total)%s:", len(m.userRepos), repoCountInfo))
 	s += repoListHeader + "\n\n"
@@ -2556,7 +3233,7 @@ func (m model) viewRepoSelect() string {
 	// If no repositories match filter
 	if len(filteredRepos) == 0 {
 		noResultsMsg := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("  No repositories match the filter")
 		s += noResultsMsg + "\n"
 	}
@@ -2590,7 +3267,7 @@ func (m model) viewRepoSelect() string {
 	// Show scroll indicator if needed
 	if startIdx > 0 {
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("  ▲ More repositories above...\n")
 	}
 
@@ -2622,18 +3299,18 @@ func (m model) viewRepoSelect() string {
 			language = "Unknown"
 		}
 		langBadge := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
+			Foreground(colorSuccessDim).
 			Render(fmt.Sprintf("[%s]", language))
 
 		// Private/Public indicator
 		visibility := "public"
-		visColor := "#00FFFF"
+		visColor := colorAccent
 		if repo.Private {
 			visibility = "private"
-			visColor = "#FFAA00"
+			visColor = colorWarning
 		}
 		visBadge := lipgloss.NewStyle().
-			Foreground(lipgloss.Color(visColor)).
+			Foreground(visColor).
 			Render(fmt.Sprintf("(%s)", visibility))
 
 		// Description
@@ -2649,12 +3326,12 @@ func (m model) viewRepoSelect() string {
 		lineStyle := lipgloss.NewStyle()
 		if isCursor {
 			lineStyle = lineStyle.
-				Background(lipgloss.Color("#444444")).
-				Foreground(lipgloss.Color("#FFFFFF")).
+				Background(colorSelection).
+				Foreground(colorSelectionText).
 				Bold(true)
 		} else {
 			lineStyle = lineStyle.
-				Foreground(lipgloss.Color("#FFFFFF"))
+				Foreground(colorText)
 		}
 
 		line := fmt.Sprintf("%s%s %s %s", cursor, repoName, langBadge, visBadge)
@@ -2663,7 +3340,7 @@ func (m model) viewRepoSelect() string {
 		// Add description on second line if cursor is here
 		if isCursor {
 			descLine := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
+				Foreground(colorDim).
 				Render(fmt.Sprintf("    %s", desc))
 			s += descLine + "\n"
 		}
@@ -2672,13 +3349,13 @@ func (m model) viewRepoSelect() string {
 	// Show scroll indicator if needed
 	if endIdx < len(filteredRepos) {
 		s += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
+			Foreground(colorDim).
 			Render("  ▼ More repositories below...\n")
 	}
 
 	// Compact help text
 	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888888")).
+		Foreground(colorDim).
 		Render("\n↑↓/jk=navigate  /=filter  enter=clone  esc=cancel")
 	s += helpText
 
@@ -2691,7 +3368,7 @@ func (m model) viewRepoSelect() string {
 	// Display status message if present
 	if m.statusMessage != "" {
 		statusView := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
+			Foreground(colorSuccessDim).
 			Render(fmt.Sprintf("\n\n✓ %s", m.statusMessage))
 		s += statusView
 	}
@@ -2703,23 +3380,31 @@ func (m model) viewRepoSelect() string {
 func (m model) viewList() string {
 	// If not ready, show loading state
 	if !m.ready {
-		return "Loading..."
+		return tr("list.loading")
 	}
 
 	view := m.list.View()
 
+	// Show the detail and notes panes next to the list when enabled and the terminal is wide enough
+	if listWidth, sideWidth := m.layout.split(m.width - 4); sideWidth > 0 {
+		_, listHeight := m.listSize()
+		view = lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(listWidth).Render(view),
+			m.viewSidePanes(sideWidth, listHeight))
+	}
+
 	// Add token status indicator
 	var tokenStatus string
 	if token, err := db.GetConfig("github_token"); err != nil || token == "" {
 		tokenStatus = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFAA00")).
-			Render("\n☁ Cloud sync disabled - GitHub OAuth not configured (press 't' to authenticate)")
+			Foreground(colorWarning).
+			Render("\n" + tr("list.cloud.disabled"))
 	} else {
 		tokenStatus = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
-			Render("\n☁ Cloud sync enabled (authenticated)")
+			Foreground(colorSuccessDim).
+			Render("\n" + tr("list.cloud.enabled"))
 	}
-	view += tokenStatus
+	view += tokenStatus + m.viewMissingDeps() + m.viewFooter()
 
 	// Display error message if present
 	if m.errorMessage != "" {
@@ -2727,38 +3412,34 @@ func (m model) viewList() string {
 		view += errorView
 	}
 
-	// Add scanning indicator
-	scanIndicator := ""
-	if m.isScanning {
-		scanIndicator = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
-			Bold(true).
-			Render("\n\n⟳ Scanning directories...")
-	}
+	// Add scanning and background job indicators
+	scanIndicator := m.viewJobsIndicator()
 
 	// Add status message
 	statusView := ""
 	if m.statusMessage != "" {
 		statusView = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00AA00")).
+			Foreground(colorSuccessDim).
 			Render("\n\n✓ " + m.statusMessage)
 	}
 
 	// Add clone input dialog if in clone mode
 	clonePrompt := ""
-	if m.confirmClone {
+	if m.confirmClone && m.cloneMode == cloneModeOrg {
 		clonePrompt = "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#00FFFF")).
+				Foreground(colorAccent).
 				Bold(true).
-				Render("🔗 CLONE GITHUB REPOSITORY") + "\n\n" +
+				Render(tr("org.title")) + "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Render("Enter GitHub repository URL:") + "\n" +
+				Foreground(colorText).
+				Render(tr("org.prompt")) + "\n" +
 			m.cloneInput.View() + "\n\n" +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
-				Render("Press Enter to clone | 'b' to browse your repos | ESC to cancel")
+				Foreground(colorDim).
+				Render(tr("org.help"))
+	} else if m.confirmClone {
+		clonePrompt = "\n\n" + m.viewClonePrompt()
 	}
 
 	// Add archive confirmation dialog if in archive mode
@@ -2769,11 +3450,11 @@ func (m model) viewList() string {
 		// Warning title box
 		warningTitle := lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
-			BorderForeground(lipgloss.Color("#FF0000")).
+			BorderForeground(colorDanger).
 			Padding(0, 2).
 			Bold(true).
-			Foreground(lipgloss.Color("#FF0000")).
-			Render("⚠ WARNING: ARCHIVE PROJECT")
+			Foreground(colorDanger).
+			Render(tr("archive.title"))
 
 		archivePrompt = "\n\n" + warningTitle + "\n\n"
 
@@ -2782,41 +3463,56 @@ func (m model) viewList() string {
 			Width(70).
 			Padding(1, 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#444444")).
+			BorderForeground(colorBorder).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render("Project Details:") + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render("Name: ") +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render(m.archiveProject.project.Name) + "\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render("Path: ") +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(m.archiveProject.project.Path),
+				lipgloss.NewStyle().Foreground(colorText).Bold(true).Render(tr("archive.details")) + "\n\n" +
+					lipgloss.NewStyle().Foreground(colorAccent).Render(tr("archive.name")) +
+					lipgloss.NewStyle().Foreground(colorText).Render(m.archiveProject.project.Name) + "\n" +
+					lipgloss.NewStyle().Foreground(colorAccent).Render(tr("archive.path")) +
+					lipgloss.NewStyle().Foreground(colorDim).Render(m.archiveProject.project.Path),
 			)
 
 		archivePrompt += projectInfoBox + "\n\n"
 
 		// Restore capability box
+		trashNote := ""
+		if m.archiveTrashDays > 0 {
+			trashNote = "\n\n" + lipgloss.NewStyle().Foreground(colorDim).Render(tr("archive.trash", m.archiveTrashDays))
+		}
 		if hasRepoURL {
 			restoreBox := lipgloss.NewStyle().
 				Width(70).
 				Padding(1, 2).
 				Border(lipgloss.NormalBorder()).
-				BorderForeground(lipgloss.Color("#00FF00")).
+				BorderForeground(colorSuccess).
 				Render(
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true).Render("✓ Restore Available") + "\n\n" +
-						lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("This project can be restored from:") + "\n" +
-						lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Render(m.archiveProject.project.RepoURL),
+					lipgloss.NewStyle().Foreground(colorSuccess).Bold(true).Render(tr("archive.restore_available")) + "\n\n" +
+						lipgloss.NewStyle().Foreground(colorText).Render(tr("archive.restore_from")) + "\n" +
+						lipgloss.NewStyle().Foreground(colorAccent).Render(m.archiveProject.project.RepoURL) + trashNote,
 				)
 			archivePrompt += restoreBox + "\n\n"
+		} else if m.archiveTrashDays > 0 {
+			warningBox := lipgloss.NewStyle().
+				Width(70).
+				Padding(1, 2).
+				Border(lipgloss.NormalBorder()).
+				BorderForeground(colorWarning).
+				Render(
+					lipgloss.NewStyle().Foreground(colorDanger).Render(tr("archive.no_repo")) + "\n" +
+						lipgloss.NewStyle().Foreground(colorText).Render(tr("archive.trash_only", m.archiveTrashDays)),
+				)
+			archivePrompt += warningBox + "\n\n"
 		} else {
 			warningBox := lipgloss.NewStyle().
 				Width(70).
 				Padding(1, 2).
 				Border(lipgloss.DoubleBorder()).
-				BorderForeground(lipgloss.Color("#FFAA00")).
+				BorderForeground(colorWarning).
 				Render(
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAA00")).Bold(true).Render("⚠ PERMANENT DELETION WARNING") + "\n\n" +
-						lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Render("No git repository URL found!") + "\n" +
-						lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Render("This project CANNOT be restored after archiving.") + "\n" +
-						lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("All files will be permanently deleted."),
+					lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render(tr("archive.permanent")) + "\n\n" +
+						lipgloss.NewStyle().Foreground(colorDanger).Render(tr("archive.no_repo")) + "\n" +
+						lipgloss.NewStyle().Foreground(colorText).Render(tr("archive.cannot_restore")) + "\n" +
+						lipgloss.NewStyle().Foreground(colorDim).Render(tr("archive.files_deleted")),
 				)
 			archivePrompt += warningBox + "\n\n"
 		}
@@ -2826,26 +3522,51 @@ func (m model) viewList() string {
 			Width(70).
 			Padding(1, 2).
 			Border(lipgloss.ThickBorder()).
-			BorderForeground(lipgloss.Color("#FF0000")).
+			BorderForeground(colorDanger).
 			Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Bold(true).Render("Type 'DELETE' to confirm:") + "\n\n" +
+				lipgloss.NewStyle().Foreground(colorDanger).Bold(true).Render(tr("archive.confirm")) + "\n\n" +
 					m.archiveConfirmInput.View() + "\n\n" +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Press Enter to confirm  •  ESC to cancel"),
+					lipgloss.NewStyle().Foreground(colorDim).Render(tr("archive.confirm_help")),
 			)
 
 		archivePrompt += confirmBox
 	}
 
+	// Add missing directory prompt or hint
+	missingPrompt := ""
+	if m.confirmMissing && m.missingProject != nil {
+		missingPrompt = "\n\n" +
+			lipgloss.NewStyle().
+				Foreground(colorWarning).
+				Bold(true).
+				Render(tr("missing.title")) + "\n\n" +
+			lipgloss.NewStyle().
+				Foreground(colorDim).
+				Render(m.missingProject.project.Path) + "\n\n" +
+			lipgloss.NewStyle().
+				Foreground(colorText).
+				Render(tr("missing.question")) + "\n" +
+			lipgloss.NewStyle().
+				Foreground(colorDim).
+				Render(tr("missing.help"))
+	} else if selected, ok := m.list.SelectedItem().(projectItem); ok && selected.missing && !m.confirmArchive && !m.confirmClone {
+		missingPrompt = lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Render("\n\n" + tr("missing.hint"))
+	} else if ok && selected.onlyElsewhere() != "" && !m.confirmArchive && !m.confirmClone {
+		hint := tr("elsewhere.hint", selected.elsewhere)
+		if selected.project.RepoURL == "" {
+			hint = tr("elsewhere.no_repo", selected.elsewhere)
+		}
+		missingPrompt = lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("\n\n" + hint)
+	}
+
 	// Add confirmation prompt if in clear all mode
 	confirmPrompt := ""
 	if m.confirmClearAll {
-		confirmPrompt = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFAA00")).
-			Bold(true).
-			Render("\n\n⚠ WARNING: Clear ALL projects from database?\n") +
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FF0000")).
-				Render("Press C again to CONFIRM | ESC to Cancel")
+		confirmPrompt = m.viewClearAll()
 	}
 
 	// Add help text
@@ -2853,66 +3574,107 @@ func (m model) viewList() string {
 	if token, err := db.GetConfig("github_token"); err != nil || token == "" {
 		// Token not configured
 		helpText = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Render("\n\nKeys: enter=open  o=browser  x=run  s=scan  g=clone  f=folders  t=github-oauth  c=clear-all  d=archive  r=restore  /=filter  q=quit")
+			Foreground(colorDim).
+			Render("\n\n" + tr("help.keys"))
 	} else {
 		// Token configured
 		helpText = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Render("\n\nKeys: enter=open  o=browser  x=run  s=scan  g=clone  b=browse-repos  p=github-profile  f=folders  u=sync-up  l=select-cloud  t=github-oauth  c=clear-all  d=archive  r=restore  /=filter  q=quit")
+			Foreground(colorDim).
+			Render("\n\n" + tr("help.keys_github"))
+	}
+
+	// Vim mode replaces the keys that changed meaning
+	if m.vimMode {
+		pending := ""
+		if m.vimPending != "" {
+			pending = "  [" + m.vimPending + "-]"
+		}
+		helpText = lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("\n\n" + tr("help.keys_vim") + pending)
 	}
 
 	// Build output without extra docStyle wrapping to avoid layout issues
-	return view + scanIndicator + statusView + clonePrompt + archivePrompt + confirmPrompt + helpText
+	// Add command palette if open
+	palettePrompt := ""
+	if m.showPalette {
+		palettePrompt = "\n\n" + m.viewPalette()
+	} else if m.showEditorPicker {
+		palettePrompt = "\n\n" + m.viewEditorPicker()
+	} else if m.showGitClientPicker {
+		palettePrompt = "\n\n" + m.viewGitClientPicker()
+	} else if m.showTaskPicker {
+		palettePrompt = "\n\n" + m.viewTaskPicker()
+	} else if m.savingSession {
+		palettePrompt = "\n\n" + m.viewSaveSession()
+	} else if m.showSessions {
+		palettePrompt = "\n\n" + m.viewSessionPicker()
+	} else if m.editingTags {
+		palettePrompt = "\n\n" + m.viewTagEditor()
+	} else if m.showInitProject {
+		palettePrompt = "\n\n" + m.viewInitProject()
+	} else if m.refProject != nil {
+		palettePrompt = "\n\n" + m.viewRestoreRef()
+	} else if m.iconProject != nil {
+		palettePrompt = "\n\n" + m.viewIconEditor()
+	} else if m.restoreToProject != nil {
+		palettePrompt = "\n\n" + m.viewRestoreTo()
+	} else if m.runConfigOffer != nil {
+		palettePrompt = "\n\n" + m.viewRunConfigOffer()
+	} else if m.cloudBackups != nil {
+		palettePrompt = "\n\n" + m.viewBackupPicker()
+	} else if m.vimCommandLine {
+		palettePrompt = "\n\n" + m.viewVimCommandLine()
+	} else if _, sideWidth := m.layout.split(m.width - 4); m.editingNotes && sideWidth == 0 {
+		// Without the side panes the notes editor opens below the list
+		palettePrompt = "\n\n" + m.viewNotesEditor()
+	}
+
+	return view + scanIndicator + statusView + clonePrompt + archivePrompt + missingPrompt + confirmPrompt + palettePrompt + helpText
 }
 
 // NewModel creates a new model with projects loaded from the database
 func NewModel() (model, error) {
-	// Load projects from the database
-	projects, err := db.GetProjects()
+	data, err := loadStartupData()
 	if err != nil {
-		return model{}, fmt.Errorf("failed to load projects: %w", err)
+		return model{}, err
 	}
+	return newModel(context.Background(), data), nil
+}
 
+// newModel creates the model from the projects loaded at startup. Background scans stop
+// when ctx is cancelled.
+func newModel(ctx context.Context, data startupData) model {
 	// Load root scan path from config
 	rootPath, _ := db.GetConfig("root_scan_path")
 
+	// Load the last used status filter
+	statusFilter, _ := db.GetConfig("status_filter")
+
+	// Apply the color theme and locale before any styles or titles are built
+	loadTheme()
+	loadLocale()
+	loadColumns()
+
 	// Create the list with reasonable default dimensions
-	delegate := list.NewDefaultDelegate()
+	nerdFont, _ := db.GetConfig("nerd_font")
+	delegate := newProjectDelegate(nerdFont)
 	l := list.New([]list.Item{}, delegate, 80, 20)
-	l.Title = "DevBase - Project Manager"
+	l.Title = listTitle(statusFilter)
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(true)
+	l.Filter = filterProjects
 	l.SetShowHelp(false)
 
-	// If database is empty, start with setup screen
-	if len(projects) == 0 {
-		// Create text input for path
-		ti := textinput.New()
-		ti.Placeholder = "Enter path (e.g., D:\\\\Projects)"
-		ti.Focus()
-		ti.CharLimit = 256
-		ti.Width = 60
-
-		// Get user home directory as default
-		if homeDir, err := os.UserHomeDir(); err == nil {
-			ti.SetValue(homeDir)
-		}
-
-		// Create cloud filter input
-		cloudFilter := textinput.New()
-		cloudFilter.Placeholder = "Type to filter projects..."
-		cloudFilter.CharLimit = 100
-		cloudFilter.Width = 50
-
-		return model{
-			screen:                     screenSetupPath,
-			pathInput:                  ti,
+	// If database is empty, start with the setup wizard
+	if data.empty {
+		m := model{
+			ctx:                        ctx,
 			tokenInput:                 textinput.New(),
 			list:                       l,
 			errorMessage:               "",
 			statusMessage:              "",
-			isScanning:                 false,
+			jobs:                       engine.NewJobManager(),
 			confirmClearAll:            false,
 			confirmArchive:             false,
 			confirmClone:               false,
@@ -2920,11 +3682,11 @@ func NewModel() (model, error) {
 			confirmExecuteCommand:      false,
 			executeCommandInput:        textinput.New(),
 			cloudProjects:              nil,
-			selectedCloudIndices:       nil,
-			cloudCursorIndex:           0,
-			cloudFilterInput:           cloudFilter,
-			cloudFiltering:             false,
 			rootScanPath:               rootPath,
+			statusFilter:               statusFilter,
+			layout:                     loadLayout(),
+			vimMode:                    loadVimMode(),
+			recentHotkeys:              loadRecentHotkeys(),
 			width:                      80,
 			height:                     24,
 			ready:                      false,
@@ -2935,30 +3697,23 @@ func NewModel() (model, error) {
 			addingRootFolder:           false,
 			confirmingDeleteRootFolder: false,
 			rootFolderToDelete:         nil,
-		}, nil
+		}
+		m, _ = m.startWizard(wizardStepDatabase)
+		return m
 	}
 
 	// Convert projects to list items
-	items := make([]list.Item, len(projects))
-	for i, p := range projects {
-		items[i] = projectItem{project: p, isLoading: false}
-	}
-	l.SetItems(items)
-
-	// Create cloud filter input
-	cloudFilter := textinput.New()
-	cloudFilter.Placeholder = "Type to filter projects..."
-	cloudFilter.CharLimit = 100
-	cloudFilter.Width = 50
+	recentHotkeys := loadRecentHotkeys()
+	l.SetItems(assignHotkeys(data.items, recentHotkeys))
 
 	return model{
+		ctx:                        ctx,
 		screen:                     screenList,
-		pathInput:                  textinput.New(),
 		tokenInput:                 textinput.New(),
 		list:                       l,
 		errorMessage:               "",
 		statusMessage:              "",
-		isScanning:                 false,
+		jobs:                       engine.NewJobManager(),
 		confirmClearAll:            false,
 		confirmArchive:             false,
 		confirmClone:               false,
@@ -2966,11 +3721,11 @@ func NewModel() (model, error) {
 		confirmExecuteCommand:      false,
 		executeCommandInput:        textinput.New(),
 		cloudProjects:              nil,
-		selectedCloudIndices:       nil,
-		cloudCursorIndex:           0,
-		cloudFilterInput:           cloudFilter,
-		cloudFiltering:             false,
 		rootScanPath:               rootPath,
+		statusFilter:               statusFilter,
+		layout:                     loadLayout(),
+		vimMode:                    loadVimMode(),
+		recentHotkeys:              recentHotkeys,
 		width:                      80,
 		height:                     24,
 		ready:                      false,
@@ -2981,13 +3736,16 @@ func NewModel() (model, error) {
 		addingRootFolder:           false,
 		confirmingDeleteRootFolder: false,
 		rootFolderToDelete:         nil,
-	}, nil
+	}
 }
 
 // archiveProjectCmd creates a command that archives a project in the background
 func archiveProjectCmd(projectID uint, originalItem projectItem, originalIdx int) tea.Cmd {
 	return func() tea.Msg {
 		err := engine.ArchiveProject(projectID)
+		if err == nil {
+			_ = db.LogActivity(models.ActivityArchive, projectID, "")
+		}
 		return ArchiveMsg{
 			projectID:    projectID,
 			err:          err,
@@ -3001,6 +3759,9 @@ func archiveProjectCmd(projectID uint, originalItem projectItem, originalIdx int
 func restoreProjectCmd(projectID uint, originalItem projectItem, originalIdx int) tea.Cmd {
 	return func() tea.Msg {
 		err := engine.RestoreProject(projectID)
+		if err == nil {
+			_ = db.LogActivity(models.ActivityRestore, projectID, "")
+		}
 		return RestoreMsg{
 			projectID:    projectID,
 			err:          err,
@@ -3010,14 +3771,35 @@ func restoreProjectCmd(projectID uint, originalItem projectItem, originalIdx int
 	}
 }
 
-// openProjectCmd creates a command that opens a project in VS Code
-func openProjectCmd(projectID uint, path string) tea.Cmd {
+// openProjectCmd creates a command that opens a project in the given editor.
+// Terminal editors take over the terminal until they exit.
+func openProjectCmd(project models.Project, editor engine.Editor) tea.Cmd {
+	projectID := project.ID
+	cmd, err := projectEditorCommand(project, editor)
+	if err != nil {
+		return func() tea.Msg {
+			return OpenProjectMsg{projectID: projectID, editor: editor.Name, err: err}
+		}
+	}
+
+	if editor.Terminal {
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			if err == nil {
+				_ = db.LogActivity(models.ActivityOpen, projectID, editor.Name)
+			}
+			return OpenProjectMsg{projectID: projectID, editor: editor.Name, err: err}
+		})
+	}
+
 	return func() tea.Msg {
-		// Open VS Code with the project path
-		cmd := exec.Command("code", path)
+		// Start the editor without waiting for it to close
 		err := cmd.Start()
+		if err == nil {
+			_ = db.LogActivity(models.ActivityOpen, projectID, editor.Name)
+		}
 		return OpenProjectMsg{
 			projectID: projectID,
+			editor:    editor.Name,
 			err:       err,
 		}
 	}
@@ -3036,11 +3818,21 @@ func openBrowserCmd(url string) tea.Cmd {
 	}
 }
 
-// runProjectCmd creates a command that runs/executes a project in a new terminal window
-func runProjectCmd(projectPath string) tea.Cmd {
+// defaultTerminal returns the terminal chosen in setup, or the fallback if none was chosen
+func defaultTerminal(fallback string) engine.Terminal {
+	command, _ := db.GetConfig("terminal")
+	if command == "" {
+		command = fallback
+	}
+	return engine.TerminalByCommand(command)
+}
+
+// runProjectCmd creates a command that runs/executes a project in a new terminal window,
+// with the variables of envSource (see engine.LoadProjectEnv) added to its environment
+func runProjectCmd(projectPath, envSource string) tea.Cmd {
 	return func() tea.Msg {
 		// Detect project type and get the run command
-		cmd, err := detectAndCreateRunCommand(projectPath)
+		cmd, err := detectAndCreateRunCommand(hostPath(projectPath))
 		if err != nil {
 			return RunProjectMsg{
 				projectPath: projectPath,
@@ -3048,15 +3840,17 @@ func runProjectCmd(projectPath string) tea.Cmd {
 			}
 		}
 
-		// Build the full command string
-		args := strings.Join(cmd.Args, " ")
-		fullCommand := fmt.Sprintf("cd /d %s && %s", projectPath, args)
-
-		// Open new terminal window with the command
-		// Use cmd /c start cmd /k to open a new cmd window that stays open
-		terminalCmd := exec.Command("cmd", "/c", "start", "cmd", "/k", fullCommand)
+		// WSL projects run inside their distribution rather than in PowerShell
+		dir, command := hostPath(projectPath), strings.Join(cmd.Args, " ")
+		if w, ok := wslProject(projectPath); ok {
+			dir, command = wslTerminalDir(), engine.WSLShellCommand(w, cmd.Args[len(cmd.Args)-1])
+		}
 
-		err = terminalCmd.Start()
+		// Open new terminal window with the command (a cmd window that stays open by default)
+		terminalCmd, err := engine.TerminalCommand(defaultTerminal("cmd"), dir, command)
+		if err == nil {
+			err = startWithProjectEnv(terminalCmd, hostPath(projectPath), envSource)
+		}
 		return RunProjectMsg{
 			projectPath: projectPath,
 			err:         err,
@@ -3064,8 +3858,9 @@ func runProjectCmd(projectPath string) tea.Cmd {
 	}
 }
 
-// executeCommandCmd creates a command that executes a custom command in the project's root directory
-func executeCommandCmd(projectPath string, command string) tea.Cmd {
+// executeCommandCmd creates a command that executes a custom command in the project's root directory,
+// with the variables of envSource added to its environment
+func executeCommandCmd(projectPath, command, envSource string) tea.Cmd {
 	return func() tea.Msg {
 		if projectPath == "" {
 			return ExecuteCommandMsg{
@@ -3075,14 +3870,15 @@ func executeCommandCmd(projectPath string, command string) tea.Cmd {
 			}
 		}
 
-		// Build the full command string - change to project directory and execute the command
-		// PowerShell command to open a new PowerShell window, change directory, and execute command
-		psCommand := fmt.Sprintf("Start-Process powershell -ArgumentList '-NoExit', '-Command', 'Set-Location -Path \"%s\"; %s'", projectPath, command)
-
-		// Open new PowerShell window with the command
-		terminalCmd := exec.Command("powershell", "-Command", psCommand)
-
-		err := terminalCmd.Start()
+		// Open new terminal window (PowerShell by default), change to project directory and execute the command
+		dir, shellCommand := hostPath(projectPath), command
+		if w, ok := wslProject(projectPath); ok {
+			dir, shellCommand = wslTerminalDir(), engine.WSLShellCommand(w, command)
+		}
+		terminalCmd, err := engine.TerminalCommand(defaultTerminal("powershell"), dir, shellCommand)
+		if err == nil {
+			err = startWithProjectEnv(terminalCmd, hostPath(projectPath), envSource)
+		}
 		return ExecuteCommandMsg{
 			projectPath: projectPath,
 			command:     command,
@@ -3181,132 +3977,122 @@ func detectAndCreateRunCommand(projectPath string) (*exec.Cmd, error) {
 	return nil, fmt.Errorf("unable to detect project type or run command")
 }
 
-// scanRootFolderCmd creates a command that scans a specific root folder
-func scanRootFolderCmd(rootFolderID uint, scanPath string) tea.Cmd {
-	return func() tea.Msg {
-		// Scan for projects at the specified path
-		projects, err := engine.ScanDirectory(scanPath)
-		if err != nil {
-			return ScanCompleteMsg{err: err}
-		}
-
-		// Set the RootFolderID for all scanned projects
-		for i := range projects {
-			projects[i].RootFolderID = rootFolderID
-		}
+// scanRootFolderCmd creates a command that scans a specific root folder as a job, until the
+// job is cancelled or DevBase exits
+func (m model) scanRootFolderCmd(rootFolderID uint, scanPath string) tea.Cmd {
+	return m.cancellableJobCmd(engine.JobScan, "Scan "+scanPath, func(ctx context.Context, _ int) (tea.Msg, error) {
+		return scanRootFolder(ctx, rootFolderID, scanPath)
+	})
+}
 
-		// Get existing projects for this root folder only
-		existingProjects, err := db.GetProjectsByRootFolder(rootFolderID)
-		if err != nil {
-			return ScanCompleteMsg{err: err}
-		}
+// scanRootFolder scans a root folder for projects and reports the result
+func scanRootFolder(ctx context.Context, rootFolderID uint, scanPath string) (ScanCompleteMsg, error) {
+	result, err := engine.ScanRootFolder(ctx, rootFolderID, scanPath)
+	if err != nil {
+		return ScanCompleteMsg{err: err}, err
+	}
+	return ScanCompleteMsg{
+		path:            scanPath,
+		projectsFound:   result.Found,
+		projectsAdded:   result.Added,
+		projectsRemoved: result.Removed,
+		report:          result.Report,
+	}, nil
+}
 
-		// Create map of scanned project paths
-		scannedPaths := make(map[string]bool)
-		for _, p := range projects {
-			scannedPaths[p.Path] = true
+// scanProjectsWithPathCmd creates a command that scans for projects at a specific path
+// and stores them in the active root folder
+func (m model) scanProjectsWithPathCmd(scanPath string) tea.Cmd {
+	return m.cancellableJobCmd(engine.JobScan, "Scan "+scanPath, func(ctx context.Context, _ int) (tea.Msg, error) {
+		var rootFolderID uint
+		if activeRoot, err := db.GetActiveRootFolder(); err == nil && activeRoot != nil {
+			rootFolderID = activeRoot.ID
 		}
+		return scanRootFolder(ctx, rootFolderID, scanPath)
+	})
+}
 
-		// Remove projects that no longer exist (only active ones)
-		removedCount := 0
-		for _, existing := range existingProjects {
-			if existing.Status == "active" && !scannedPaths[existing.Path] {
-				if err := db.DeleteProject(existing.ID); err == nil {
-					removedCount++
-				}
-			}
+// reloadProjectsCmd creates a command that reloads the project list
+// statusFilter limits the list to "active" or "archived" projects; empty shows all
+func reloadProjectsCmd(statusFilter string) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := db.GetProjectsByStatus(statusFilter)
+		if err != nil {
+			return ErrorMsg{err: err}
 		}
 
-		// Add new projects to database
-		addedCount := 0
-		for i := range projects {
-			if err := db.AddProject(&projects[i]); err == nil {
-				addedCount++
-			}
+		// Keep Windows Terminal profiles in step with renamed, archived or removed projects
+		if err := syncTerminalProfiles(); err != nil {
+			slog.Warn("Failed to update Windows Terminal profiles", "err", err)
 		}
 
-		return ScanCompleteMsg{
-			projectsFound:   len(projects),
-			projectsAdded:   addedCount,
-			projectsRemoved: removedCount,
-		}
+		return reloadMsg{items: projectsToItems(projects)}
 	}
 }
 
-// scanProjectsWithPathCmd creates a command that scans for projects at a specific path
-func scanProjectsWithPathCmd(scanPath string) tea.Cmd {
-	return func() tea.Msg {
-		// Scan for projects at the specified path
-		projects, err := engine.ScanDirectory(scanPath)
-		if err != nil {
-			return ScanCompleteMsg{err: err}
-		}
-
-		// Get active root folder to set RootFolderID for scanned projects
-		var rootFolderID uint
-		activeRoot, err := db.GetActiveRootFolder()
-		if err == nil && activeRoot != nil {
-			rootFolderID = activeRoot.ID
+// projectsToItems converts projects to list items, detecting the language and dev container
+// of active projects that were registered before that detection existed
+func projectsToItems(projects []models.Project) []list.Item {
+	hostNames := make(map[uint]string)
+	if hosts, err := db.GetRemoteHosts(); err == nil {
+		for _, host := range hosts {
+			hostNames[host.ID] = host.Name
 		}
+	}
 
-		// Set the RootFolderID for all scanned projects
-		for i := range projects {
-			projects[i].RootFolderID = rootFolderID
+	toHost := hostPathFunc()
+	machine := db.MachineName()
+	items := make([]list.Item, len(projects))
+	for i, p := range projects {
+		if p.RemoteHostID != 0 {
+			items[i] = projectItem{project: p, remoteHost: hostNames[p.RemoteHostID]}
+			continue
 		}
-
-		// Get existing projects from database (filtered by active root folder)
-		existingProjects, err := db.GetProjects()
-		if err != nil {
-			return ScanCompleteMsg{err: err}
+		if p.Language == "" && p.Status == "active" {
+			p.Language = engine.DetectLanguage(toHost(p.Path))
 		}
-
-		// Create map of scanned project paths
-		scannedPaths := make(map[string]bool)
-		for _, p := range projects {
-			scannedPaths[p.Path] = true
+		if !p.DevContainer && p.Status == "active" {
+			p.DevContainer = engine.HasDevContainer(toHost(p.Path))
 		}
-
-		// Remove projects that no longer exist (only active ones)
-		removedCount := 0
-		for _, existing := range existingProjects {
-			if existing.Status == "active" && !scannedPaths[existing.Path] {
-				if err := db.DeleteProject(existing.ID); err == nil {
-					removedCount++
-				}
-			}
+		item := projectItem{project: p, isLoading: false}
+		if p.Machine != machine {
+			item.elsewhere = p.Machine
 		}
+		items[i] = item
+	}
+	return groupWorktrees(items)
+}
 
-		// Add new projects to database
-		addedCount := 0
-		for i := range projects {
-			if err := db.AddProject(&projects[i]); err == nil {
-				addedCount++
-			}
-		}
+// schedulePathCheck creates a command that triggers a project path check after the given delay
+func schedulePathCheck(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return pathCheckTickMsg{}
+	})
+}
 
-		return ScanCompleteMsg{
-			projectsFound:   len(projects),
-			projectsAdded:   addedCount,
-			projectsRemoved: removedCount,
+// checkPathsCmd creates a command that verifies the directories of active projects in the background
+func checkPathsCmd(items []list.Item, reschedule bool) tea.Cmd {
+	// Snapshot the paths so the check doesn't touch the list from another goroutine
+	paths := make(map[uint]string)
+	toHost := hostPathFunc()
+	for _, item := range items {
+		if pi, ok := item.(projectItem); ok && pi.project.Status == "active" && pi.project.Path != "" && pi.remoteHost == "" {
+			paths[pi.project.ID] = toHost(pi.project.Path)
 		}
 	}
+	return func() tea.Msg {
+		return PathCheckMsg{missing: engine.MissingProjectPaths(paths), reschedule: reschedule}
+	}
 }
 
-// reloadProjectsCmd creates a command that reloads the project list
-func reloadProjectsCmd() tea.Cmd {
+// removeProjectCmd creates a command that removes a project entry from the database (files are untouched)
+func removeProjectCmd(projectID uint, projectName string) tea.Cmd {
 	return func() tea.Msg {
-		projects, err := db.GetProjects()
-		if err != nil {
-			return ErrorMsg{err: err}
-		}
-
-		// Convert to list items
-		items := make([]list.Item, len(projects))
-		for i, p := range projects {
-			items[i] = projectItem{project: p, isLoading: false}
+		err := db.DeleteProject(projectID)
+		if err == nil {
+			engine.SyncSessionWorkspaces(projectID)
 		}
-
-		return reloadMsg{items: items}
+		return RemoveProjectMsg{projectName: projectName, err: err}
 	}
 }
 
@@ -3326,95 +4112,130 @@ func clearAllProjectsCmd() tea.Cmd {
 	}
 }
 
-// cloneProjectCmd creates a command that clones a GitHub repository and adds it to the database
-func cloneProjectCmd(repoURL, rootPath string) tea.Cmd {
-	return func() tea.Msg {
-		// Parse repo name from URL
-		// Expected format: https://github.com/owner/repo or https://github.com/owner/repo.git
-		parts := strings.Split(repoURL, "/")
-		if len(parts) < 2 {
-			return CloneMsg{err: fmt.Errorf("invalid GitHub URL format")}
+// cloneProjectCmd creates a command that clones a git repository into rootPath and adds it
+// to the database, under name or the name from the URL when name is empty
+func (m model) cloneProjectCmd(repoURL, rootPath, name string) tea.Cmd {
+	return m.cancellableJobCmd(engine.JobClone, "Clone "+repoURL, func(ctx context.Context, _ int) (tea.Msg, error) {
+		repoName, projectPath, err := cloneProject(ctx, repoURL, rootPath, name)
+		if err != nil {
+			return CloneMsg{err: err}, err
 		}
-		repoName := parts[len(parts)-1]
-		// Remove .git suffix if present
-		repoName = strings.TrimSuffix(repoName, ".git")
+		return CloneMsg{
+			projectName: repoName,
+			projectPath: projectPath,
+		}, nil
+	})
+}
 
-		// Determine project path
-		projectPath := filepath.Join(rootPath, repoName)
+// cloneProject clones a git repository into rootPath and adds it to the database,
+// returning the project's name and path. The directory is named name, or after the
+// repository when name is empty.
+func cloneProject(ctx context.Context, repoURL, rootPath, name string) (string, string, error) {
+	// Parse repo name from the HTTPS or SSH URL of any git host
+	ref, ok := engine.ParseRepoURL(repoURL)
+	if !ok {
+		return "", "", fmt.Errorf("invalid repository URL: %s", repoURL)
+	}
+	repoName := ref.Name()
+	if name != "" {
+		repoName = name
+	}
 
-		// Check if project already exists
-		if _, err := db.GetProjectByPath(projectPath); err == nil {
-			return CloneMsg{err: fmt.Errorf("project already exists at %s", projectPath)}
-		}
+	// Determine project path
+	projectPath := filepath.Join(rootPath, repoName)
 
-		// Clone the repository
-		if err := engine.CloneRepository(repoURL, projectPath); err != nil {
-			return CloneMsg{err: err}
-		}
+	// Check if project already exists
+	if _, err := db.GetProjectByPath(projectPath); err == nil {
+		return "", "", fmt.Errorf("project %w: %s", engine.ErrPathExists, projectPath)
+	} else if !errors.Is(err, db.ErrProjectNotFound) {
+		return "", "", err
+	}
 
-		// Create project record
-		project := &models.Project{
-			Name:    repoName,
-			Path:    projectPath,
-			RepoURL: repoURL,
-			Status:  "active",
-		}
+	// Clone the repository
+	if err := engine.CloneRepository(ctx, repoURL, projectPath); err != nil {
+		return "", "", err
+	}
 
-		// Add to database
-		if err := db.AddProject(project); err != nil {
-			// Clean up cloned directory on failure
-			os.RemoveAll(projectPath)
-			return CloneMsg{err: err}
-		}
+	// Create project record
+	project := &models.Project{
+		Name:    repoName,
+		Path:    projectPath,
+		RepoURL: repoURL,
+		Status:  "active",
+	}
+	if rootFolder, err := db.GetRootFolderForPath(projectPath); err != nil {
+		os.RemoveAll(projectPath)
+		return "", "", err
+	} else if rootFolder != nil {
+		project.RootFolderID = rootFolder.ID
+	}
 
-		return CloneMsg{
-			projectName: repoName,
-			projectPath: projectPath,
-		}
+	// Add to database
+	if err := db.AddProject(project); err != nil {
+		// Clean up cloned directory on failure
+		os.RemoveAll(projectPath)
+		return "", "", err
 	}
+
+	return repoName, projectPath, nil
 }
 
-// syncToCloudCmd creates a command that syncs projects to GitHub Gist
-func syncToCloudCmd() tea.Cmd {
-	return func() tea.Msg {
-		// Get GitHub token from config
-		token, err := db.GetConfig("github_token")
-		if err != nil || token == "" {
-			return SyncToCloudMsg{err: fmt.Errorf("GitHub authentication required. Please authenticate with OAuth (press 't')")}
+// syncToCloudCmd creates a command that syncs projects to GitHub Gist as a job. Failures
+// are emitted as sync_failed events.
+func (m model) syncToCloudCmd() tea.Cmd {
+	return m.jobCmd(engine.JobSync, "Sync projects to the cloud", func(int) (tea.Msg, error) {
+		msg := syncToCloud()
+		if msg.err != nil {
+			_ = engine.Emit(engine.Event{Kind: engine.EventSyncFailed, Detail: msg.err.Error(), Time: time.Now()})
 		}
+		return msg, msg.err
+	})
+}
 
-		// Get active root folder ID
-		var rootFolderID uint
-		activeRoot, err := db.GetActiveRootFolder()
-		if err == nil && activeRoot != nil {
-			rootFolderID = activeRoot.ID
-		}
+// syncToCloud pushes the projects of the active root folder to its gist
+func syncToCloud() SyncToCloudMsg {
+	// Get GitHub token from config
+	token, err := db.GetConfig("github_token")
+	if err != nil || token == "" {
+		return SyncToCloudMsg{err: fmt.Errorf("GitHub authentication required. Please authenticate with OAuth (press 't')")}
+	}
 
-		// Create gist client with root folder ID (loads existing gist ID automatically)
-		client, err := engine.NewGistClient(token, rootFolderID)
-		if err != nil {
-			return SyncToCloudMsg{err: fmt.Errorf("failed to create gist client: %w", err)}
-		}
+	// Get active root folder ID
+	var rootFolderID uint
+	activeRoot, err := db.GetActiveRootFolder()
+	if err == nil && activeRoot != nil {
+		rootFolderID = activeRoot.ID
+	}
 
-		// Validate token
-		if err := client.ValidateToken(); err != nil {
-			return SyncToCloudMsg{err: fmt.Errorf("invalid GitHub token. Please reconfigure your token (press 't')")}
-		}
+	// Create gist client with root folder ID (loads existing gist ID automatically)
+	client, err := engine.NewGistClient(token, rootFolderID)
+	if err != nil {
+		return SyncToCloudMsg{err: fmt.Errorf("failed to create gist client: %w", err)}
+	}
 
-		// Get all projects (filtered by active root folder)
-		projects, err := db.GetProjects()
-		if err != nil {
-			return SyncToCloudMsg{err: fmt.Errorf("failed to get projects: %w", err)}
-		}
+	// Validate token
+	if err := client.ValidateToken(); err != nil {
+		return SyncToCloudMsg{err: fmt.Errorf("invalid GitHub token. Please reconfigure your token (press 't')")}
+	}
 
-		// Save to gist (creates new or updates existing)
-		err = client.SaveToGist(projects)
-		if err != nil {
-			return SyncToCloudMsg{err: err}
-		}
+	// Get all projects (filtered by active root folder)
+	projects, err := db.GetProjects()
+	if err != nil {
+		return SyncToCloudMsg{err: fmt.Errorf("failed to get projects: %w", err)}
+	}
+
+	// Save to gist (creates new or updates existing)
+	err = client.SaveToGist(projects)
+	if err != nil {
+		return SyncToCloudMsg{err: err}
+	}
 
-		return SyncToCloudMsg{gistID: client.GistID}
+	// Save the gist ID to config
+	if err := db.SetConfig("gist_id", client.GistID); err != nil {
+		slog.Warn("Failed to save gist ID", "err", err)
 	}
+	_ = db.LogActivity(models.ActivitySync, 0, fmt.Sprintf("Pushed %d projects to the cloud", len(projects)))
+	return SyncToCloudMsg{gistID: client.GistID}
 }
 
 // loadFromCloudCmd creates a command that loads projects from GitHub Gist
@@ -3462,12 +4283,14 @@ func loadFromCloudCmd() tea.Cmd {
 
 		// Add loaded projects
 		for _, project := range projects {
-			project.ID = 0 // Reset ID for new insertion
+			project.ID = 0       // Reset ID for new insertion
+			project.ParentID = 0 // IDs of the other machine don't link worktrees here
 			if err := db.AddProject(&project); err != nil {
 				return LoadFromCloudMsg{err: fmt.Errorf("failed to add project %s: %w", project.Name, err)}
 			}
 		}
 
+		_ = db.LogActivity(models.ActivitySync, 0, fmt.Sprintf("Loaded %d projects from the cloud", len(projects)))
 		return LoadFromCloudMsg{projectsLoaded: len(projects)}
 	}
 }
@@ -3499,6 +4322,18 @@ func listCloudProjectsCmd() tea.Cmd {
 			return ListCloudProjectsMsg{err: fmt.Errorf("invalid GitHub token")}
 		}
 
+		// Without a gist yet (a new machine), offer the backups in the user's gists
+		if client.GistID == "" {
+			backups, err := client.FindBackups()
+			if err != nil {
+				return ListCloudProjectsMsg{err: err}
+			}
+			if len(backups) == 0 {
+				return ListCloudProjectsMsg{err: engine.ErrNoCloudBackup}
+			}
+			return CloudBackupsMsg{backups: backups}
+		}
+
 		// Load projects from gist (uses internal gist ID)
 		projects, err := client.ListProjectsFromGist()
 		if err != nil {
@@ -3531,11 +4366,10 @@ func fetchUserReposCmd() tea.Cmd {
 	}
 }
 
-// loadSelectedProjectsCmd creates a command that loads selected projects from cloud
-func loadSelectedProjectsCmd(selectedIndices []int, cloudProjects []models.Project) tea.Cmd {
-	return func() tea.Msg {
-		loadedCount := 0
-
+// loadSelectedProjectsCmd creates a command that loads selected projects from cloud as a job
+func (m model) loadSelectedProjectsCmd(selectedIndices []int, cloudProjects []models.Project) tea.Cmd {
+	title := fmt.Sprintf("Load %d projects from the cloud", len(selectedIndices))
+	return m.jobCmd(engine.JobSync, title, func(int) (tea.Msg, error) {
 		// Get active root folder ID
 		var rootFolderID uint
 		activeRoot, err := db.GetActiveRootFolder()
@@ -3543,35 +4377,17 @@ func loadSelectedProjectsCmd(selectedIndices []int, cloudProjects []models.Proje
 			rootFolderID = activeRoot.ID
 		}
 
+		var selected []models.Project
 		for _, idx := range selectedIndices {
-			if idx < 0 || idx >= len(cloudProjects) {
-				continue
-			}
-
-			project := cloudProjects[idx]
-			// Reset ID for new insertion and mark as archived
-			project.ID = 0
-			project.Status = "archived"
-			project.RootFolderID = rootFolderID // Set to active root folder
-
-			// Check if project already exists
-			if existing, err := db.GetProjectByPath(project.Path); err == nil {
-				// Update existing project
-				project.ID = existing.ID
-				if err := db.UpdateProject(&project); err != nil {
-					continue
-				}
-			} else {
-				// Add new project
-				if err := db.AddProject(&project); err != nil {
-					continue
-				}
+			if idx >= 0 && idx < len(cloudProjects) {
+				selected = append(selected, cloudProjects[idx])
 			}
-			loadedCount++
 		}
+		loadedCount := engine.StoreCloudProjects(rootFolderID, selected)
 
-		return LoadSelectedProjectsMsg{projectsLoaded: loadedCount}
-	}
+		_ = db.LogActivity(models.ActivitySync, 0, fmt.Sprintf("Loaded %d selected projects from the cloud", loadedCount))
+		return LoadSelectedProjectsMsg{projectsLoaded: loadedCount}, nil
+	})
 }
 
 // initiateOAuthCmd creates a command that initiates the GitHub OAuth device flow
@@ -3594,6 +4410,21 @@ func initiateOAuthCmd() tea.Cmd {
 	}
 }
 
+// ghTokenCmd creates a command that reads the GitHub CLI token and checks it works
+func ghTokenCmd() tea.Cmd {
+	return func() tea.Msg {
+		token, err := engine.GHCLIToken()
+		if err != nil {
+			return GHTokenMsg{err: err}
+		}
+		scopes, err := engine.FetchTokenScopes(token)
+		if err != nil {
+			return GHTokenMsg{err: err}
+		}
+		return GHTokenMsg{token: token, scopes: scopes}
+	}
+}
+
 // pollForAccessTokenCmd creates a command that polls for the OAuth access token
 func pollForAccessTokenCmd(deviceCode string, interval int) tea.Cmd {
 	return func() tea.Msg {
@@ -3604,10 +4435,13 @@ func pollForAccessTokenCmd(deviceCode string, interval int) tea.Cmd {
 			return OAuthCompleteMsg{err: err}
 		}
 
-		return OAuthCompleteMsg{
-			accessToken: accessToken,
-			err:         nil,
+		msg := OAuthCompleteMsg{accessToken: accessToken}
+		if scopes, err := engine.FetchTokenScopes(accessToken); err == nil {
+			msg.scopes = &scopes
+		} else {
+			slog.Warn("Failed to read the scopes of the new GitHub token", "err", err)
 		}
+		return msg
 	}
 }
 
@@ -3620,42 +4454,13 @@ func getGitHubUsernameCmd() tea.Cmd {
 			return GitHubUsernameMsg{err: fmt.Errorf("GitHub authentication required")}
 		}
 
-		// Make API request to get user info
-		req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
-		if err != nil {
-			return GitHubUsernameMsg{err: fmt.Errorf("failed to create request: %w", err)}
-		}
-
-		req.Header.Set("Authorization", "Bearer "+token)
-		req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		username, err := engine.NewGitHubClient(token).User()
 		if err != nil {
 			return GitHubUsernameMsg{err: fmt.Errorf("failed to fetch user info: %w", err)}
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return GitHubUsernameMsg{err: fmt.Errorf("GitHub API error: %d - %s", resp.StatusCode, string(body))}
-		}
-
-		// Parse response
-		var userInfo struct {
-			Login string `json:"login"`
-		}
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return GitHubUsernameMsg{err: fmt.Errorf("failed to read response: %w", err)}
-		}
-
-		if err := json.Unmarshal(body, &userInfo); err != nil {
-			return GitHubUsernameMsg{err: fmt.Errorf("failed to parse response: %w", err)}
-		}
 
 		return GitHubUsernameMsg{
-			username: userInfo.Login,
+			username: username,
 			err:      nil,
 		}
 	}
@@ -3676,30 +4481,6 @@ func max(a, b int) int {
 	return b
 }
 
-// getFilteredIndices returns a list of original indices that match the filter
-func (m model) getFilteredIndices() []int {
-	filterText := strings.ToLower(strings.TrimSpace(m.cloudFilterInput.Value()))
-	if filterText == "" {
-		// No filter - return all indices
-		indices := make([]int, len(m.cloudProjects))
-		for i := range m.cloudProjects {
-			indices[i] = i
-		}
-		return indices
-	}
-
-	// Filter and return matching indices
-	indices := []int{}
-	for i, project := range m.cloudProjects {
-		if strings.Contains(strings.ToLower(project.Name), filterText) ||
-			strings.Contains(strings.ToLower(project.Path), filterText) ||
-			strings.Contains(strings.ToLower(project.RepoURL), filterText) {
-			indices = append(indices, i)
-		}
-	}
-	return indices
-}
-
 // getFilteredRepos returns filtered GitHub repositories based on the filter input
 func (m model) getFilteredRepos() []engine.GitHubRepository {
 	filterText := strings.ToLower(strings.TrimSpace(m.repoFilterInput.Value()))
@@ -0,0 +1,83 @@
+package ui
+
+// messagesEnglish is the reference message catalog. Add new keys here first; other
+// catalogs fall back to these messages until they are translated.
+var messagesEnglish = map[string]string{
+	"list.loading":           "Loading...",
+	"list.title.all":         "DevBase - Project Manager [All]",
+	"list.title.active":      "DevBase - Project Manager [Active]",
+	"list.title.archived":    "DevBase - Project Manager [Archived]",
+	"list.item.processing":   " [Processing...]",
+	"list.item.archived":     " [Archived]",
+	"list.item.missing":      " ⚠ [Missing]",
+	"list.item.remote":       " [ssh: %s]",
+	"list.item.elsewhere":    " [Only on %s]",
+	"list.item.running":      " ● running",
+	"list.item.running_port": " ● running :%d",
+	"list.item.committed":    "committed %s",
+	"list.cloud.disabled":    "☁ Cloud sync disabled - GitHub OAuth not configured (press 't' to authenticate)",
+	"list.cloud.enabled":     "☁ Cloud sync enabled (authenticated)",
+	"list.scanning":          "⟳ Scanning directories...",
+	"list.job":               "⟳ %s... (J for jobs)",
+	"list.jobs":              "⟳ %d background jobs running (J for jobs)",
+
+	"footer.counts":        "%d projects (%d active, %d archived)",
+	"footer.root":          "📁 %s",
+	"footer.disk":          "💾 %s",
+	"footer.scanned":       "⟳ scanned %s",
+	"footer.never_scanned": "⟳ never scanned",
+	"footer.running":       "▶ %s running (F)",
+
+	"clone.title":  "🔗 CLONE GITHUB REPOSITORY",
+	"clone.prompt": "Enter GitHub repository URL:",
+	"clone.name":   "Directory name (optional):",
+	"clone.root":   "Root folder (←/→ to change):",
+	"clone.help":   "Press Enter to clone | Tab for the next field | 'b' to browse your repos | ESC to cancel",
+
+	"org.title":  "🏢 BROWSE ORGANIZATION REPOSITORIES",
+	"org.prompt": "Enter GitHub organization:",
+	"org.help":   "Press Enter to list its repositories | ESC to cancel",
+
+	"archive.title":             "⚠ WARNING: ARCHIVE PROJECT",
+	"archive.details":           "Project Details:",
+	"archive.name":              "Name: ",
+	"archive.path":              "Path: ",
+	"archive.restore_available": "✓ Restore Available",
+	"archive.restore_from":      "This project can be restored from:",
+	"archive.permanent":         "⚠ PERMANENT DELETION WARNING",
+	"archive.no_repo":           "No git repository URL found!",
+	"archive.cannot_restore":    "This project CANNOT be restored after archiving.",
+	"archive.files_deleted":     "All files will be permanently deleted.",
+	"archive.trash":             "The checkout is kept in the trash for %d days, so its disk space is not freed until it is purged; restoring moves it back.",
+	"archive.trash_only":        "It can only be restored from the trash, where it is kept for %d days.",
+	"archive.confirm":           "Type 'DELETE' to confirm:",
+	"archive.confirm_help":      "Press Enter to confirm  •  ESC to cancel",
+
+	"missing.title":    "⚠ PROJECT DIRECTORY NOT FOUND",
+	"missing.question": "Rescan the root folder or remove this entry?",
+	"missing.help":     "Press S to rescan | D to remove from DevBase | ESC to cancel",
+	"missing.hint":     "⚠ Directory no longer exists - press Enter to rescan or remove",
+
+	"elsewhere.hint":    "💻 Only on %s - press r to clone it here",
+	"elsewhere.no_repo": "💻 Only on %s - no repository URL to clone it from",
+
+	"clear.warning":     "⚠ WARNING: Clear %s from DevBase?",
+	"clear.scope_all":   "ALL projects of every root folder",
+	"clear.scope_root":  "the projects of %s",
+	"clear.summary":     "Dry run: %d projects would be removed (%d active, %d archived). Directories on disk are kept.",
+	"clear.setup_again": "Root folders are removed too and setup starts again.",
+	"clear.locked":      "Refused while these projects are locked: %s",
+	"clear.confirm":     "Type '%s' to confirm:",
+	"clear.help":        "Press Enter to confirm | ESC to Cancel",
+	"clear.help_all":    "Press Enter to confirm | Tab: every root folder | ESC to Cancel",
+	"clear.help_root":   "Press Enter to confirm | Tab: only %s | ESC to Cancel",
+
+	"help.keys":        "Keys: enter=open  e=open-with  o=browser  G=git-client  x=run  a=tmux  C=container  s=scan  g=clone  i=new  f=folders  t=github-oauth  c=clear-all  d=archive  r=restore  A=restore-to  E=retry  m=mark  P=pin  U=lock  M=open-marked  w=sessions  v=view  T=tags  N=notes  B=restore-ref  I=icon  H=history  L=logs  Z=stale  R=reclaim  X=run-logs  F=output  J=jobs  Y=scan-report  K=worktrees  V=vim-keys  D=details  n=notes-pane  [/]=resize  {/}=resize-notes  /=filter  alt+1..9=recent  ctrl+p=commands  q=quit",
+	"help.keys_github": "Keys: enter=open  e=open-with  o=browser  G=git-client  x=run  a=tmux  C=container  s=scan  g=clone  i=new  b=browse-repos  S=starred  O=org-repos  p=github-profile  f=folders  u=sync-up  l=select-cloud  t=github-oauth  c=clear-all  d=archive  r=restore  A=restore-to  E=retry  m=mark  P=pin  U=lock  M=open-marked  w=sessions  v=view  T=tags  N=notes  B=restore-ref  I=icon  H=history  L=logs  Z=stale  R=reclaim  X=run-logs  F=output  J=jobs  Y=scan-report  K=worktrees  V=vim-keys  D=details  n=notes-pane  [/]=resize  {/}=resize-notes  /=filter  alt+1..9=recent  ctrl+p=commands  q=quit",
+	"help.keys_vim":    "Keys (vim): j/k=move  h/l=page  gg/G=first/last  dd=archive  /=search  :=command  enter=open  e=open-with  x=run  a=tmux  C=container  s=scan  r=restore  A=restore-to  E=retry  m=mark  w=sessions  T=tags  N=notes  B=restore-ref  I=icon  H=history  L=logs  Z=stale  R=reclaim  X=run-logs  F=output  J=jobs  Y=scan-report  K=worktrees  V=default-keys  alt+1..9=recent  ctrl+p=commands  q=quit",
+
+	"inline.placeholder": "Filter projects (tag:, status:, lang: work too)",
+	"inline.archived":    " (archived)",
+	"inline.empty":       "  No matching projects",
+	"inline.help":        "↑↓=move  enter=print path  esc=cancel",
+}
@@ -0,0 +1,82 @@
+package ui
+
+// messagesSpanish is the Spanish message catalog
+var messagesSpanish = map[string]string{
+	"list.loading":           "Cargando...",
+	"list.title.all":         "DevBase - Gestor de proyectos [Todos]",
+	"list.title.active":      "DevBase - Gestor de proyectos [Activos]",
+	"list.title.archived":    "DevBase - Gestor de proyectos [Archivados]",
+	"list.item.processing":   " [Procesando...]",
+	"list.item.archived":     " [Archivado]",
+	"list.item.missing":      " ⚠ [No encontrado]",
+	"list.item.remote":       " [ssh: %s]",
+	"list.item.elsewhere":    " [Solo en %s]",
+	"list.item.running":      " ● en ejecución",
+	"list.item.running_port": " ● en ejecución :%d",
+	"list.item.committed":    "último commit %s",
+	"list.cloud.disabled":    "☁ Sincronización desactivada - GitHub OAuth no configurado (pulsa 't' para autenticarte)",
+	"list.cloud.enabled":     "☁ Sincronización activada (autenticado)",
+	"list.scanning":          "⟳ Escaneando directorios...",
+	"list.job":               "⟳ %s... (J para tareas)",
+	"list.jobs":              "⟳ %d tareas en segundo plano (J para tareas)",
+
+	"footer.counts":        "%d proyectos (%d activos, %d archivados)",
+	"footer.root":          "📁 %s",
+	"footer.disk":          "💾 %s",
+	"footer.scanned":       "⟳ escaneado %s",
+	"footer.never_scanned": "⟳ sin escanear",
+	"footer.running":       "▶ %s en ejecución (F)",
+
+	"clone.title":  "🔗 CLONAR REPOSITORIO DE GITHUB",
+	"clone.prompt": "Introduce la URL del repositorio de GitHub:",
+	"clone.name":   "Nombre del directorio (opcional):",
+	"clone.root":   "Carpeta raíz (←/→ para cambiar):",
+	"clone.help":   "Enter para clonar | Tab para el siguiente campo | 'b' para ver tus repositorios | ESC para cancelar",
+
+	"org.title":  "🏢 VER REPOSITORIOS DE ORGANIZACIÓN",
+	"org.prompt": "Introduce la organización de GitHub:",
+	"org.help":   "Enter para ver sus repositorios | ESC para cancelar",
+
+	"archive.title":             "⚠ AVISO: ARCHIVAR PROYECTO",
+	"archive.details":           "Detalles del proyecto:",
+	"archive.name":              "Nombre: ",
+	"archive.path":              "Ruta: ",
+	"archive.restore_available": "✓ Restauración disponible",
+	"archive.restore_from":      "Este proyecto se puede restaurar desde:",
+	"archive.permanent":         "⚠ AVISO DE BORRADO PERMANENTE",
+	"archive.no_repo":           "¡No se encontró la URL del repositorio git!",
+	"archive.cannot_restore":    "Este proyecto NO se podrá restaurar después de archivarlo.",
+	"archive.files_deleted":     "Todos los archivos se borrarán de forma permanente.",
+	"archive.trash":             "La copia se guarda en la papelera durante %d días, así que su espacio no se libera hasta que se purga; restaurar la devuelve.",
+	"archive.trash_only":        "Solo se podrá restaurar desde la papelera, donde se guarda durante %d días.",
+	"archive.confirm":           "Escribe 'DELETE' para confirmar:",
+	"archive.confirm_help":      "Enter para confirmar  •  ESC para cancelar",
+
+	"missing.title":    "⚠ NO SE ENCUENTRA EL DIRECTORIO DEL PROYECTO",
+	"missing.question": "¿Volver a escanear la carpeta raíz o quitar esta entrada?",
+	"missing.help":     "S para escanear | D para quitar de DevBase | ESC para cancelar",
+	"missing.hint":     "⚠ El directorio ya no existe - pulsa Enter para escanear o quitarlo",
+
+	"elsewhere.hint":    "💻 Solo en %s - pulsa r para clonarlo aquí",
+	"elsewhere.no_repo": "💻 Solo en %s - no tiene URL de repositorio para clonarlo",
+
+	"clear.warning":     "⚠ AVISO: ¿Borrar %s de DevBase?",
+	"clear.scope_all":   "TODOS los proyectos de todas las carpetas raíz",
+	"clear.scope_root":  "los proyectos de %s",
+	"clear.summary":     "Simulación: se quitarían %d proyectos (%d activos, %d archivados). Los directorios en disco se conservan.",
+	"clear.setup_again": "También se quitan las carpetas raíz y la configuración empieza de nuevo.",
+	"clear.locked":      "Rechazado mientras estos proyectos estén bloqueados: %s",
+	"clear.confirm":     "Escribe '%s' para confirmar:",
+	"clear.help":        "Pulsa Enter para confirmar | ESC para cancelar",
+	"clear.help_all":    "Pulsa Enter para confirmar | Tab: todas las carpetas raíz | ESC para cancelar",
+	"clear.help_root":   "Pulsa Enter para confirmar | Tab: solo %s | ESC para cancelar",
+
+	"help.keys":        "Teclas: enter=abrir  e=abrir-con  o=navegador  G=cliente-git  x=ejecutar  a=tmux  C=container  s=escanear  g=clonar  i=nuevo  f=carpetas  t=github-oauth  c=borrar-todo  d=archivar  r=restaurar  A=restaurar-en  E=reintentar  m=marcar  P=fijar  U=bloquear  M=abrir-marcados  w=sesiones  v=vista  T=etiquetas  N=notas  B=ref-restaurar  I=icono  H=historial  L=registros  Z=inactivos  R=liberar  X=salidas  F=salida  J=tareas  Y=informe-escaneo  K=worktrees  V=teclas-vim  D=detalles  n=panel-notas  [/]=redimensionar  {/}=redimensionar-notas  /=filtrar  alt+1..9=recientes  ctrl+p=comandos  q=salir",
+	"help.keys_github": "Teclas: enter=abrir  e=abrir-con  o=navegador  G=cliente-git  x=ejecutar  a=tmux  C=container  s=escanear  g=clonar  i=nuevo  b=ver-repos  S=destacados  O=repos-org  p=perfil-github  f=carpetas  u=subir  l=elegir-nube  t=github-oauth  c=borrar-todo  d=archivar  r=restaurar  A=restaurar-en  E=reintentar  m=marcar  P=fijar  U=bloquear  M=abrir-marcados  w=sesiones  v=vista  T=etiquetas  N=notas  B=ref-restaurar  I=icono  H=historial  L=registros  Z=inactivos  R=liberar  X=salidas  F=salida  J=tareas  Y=informe-escaneo  K=worktrees  V=teclas-vim  D=detalles  n=panel-notas  [/]=redimensionar  {/}=redimensionar-notas  /=filtrar  alt+1..9=recientes  ctrl+p=comandos  q=salir",
+	"help.keys_vim":    "Teclas (vim): j/k=mover  h/l=página  gg/G=primero/último  dd=archivar  /=buscar  :=comando  enter=abrir  e=abrir-con  x=ejecutar  a=tmux  C=container  s=escanear  r=restaurar  A=restaurar-en  E=reintentar  m=marcar  w=sesiones  T=etiquetas  N=notas  B=ref-restaurar  I=icono  H=historial  L=registros  Z=inactivos  R=liberar  X=salidas  F=salida  J=tareas  Y=informe-escaneo  K=worktrees  V=teclas-normales  alt+1..9=recientes  ctrl+p=comandos  q=salir",
+
+	"inline.placeholder": "Filtrar proyectos (también tag:, status:, lang:)",
+	"inline.archived":    " (archivado)",
+	"inline.empty":       "  Ningún proyecto coincide",
+	"inline.help":        "↑↓=mover  enter=mostrar ruta  esc=cancelar",
+}
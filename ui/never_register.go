@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// NeverRegisterMsg is sent when a project's directory was put on the never-register list of
+// its root folder
+type NeverRegisterMsg struct {
+	projectName string
+	rootName    string
+	removed     int
+	err         error
+}
+
+// neverRegisterSelected removes the selected project and keeps scans from registering its
+// directory again, e.g. for a fork kept only for reference
+func (m model) neverRegisterSelected() (tea.Model, tea.Cmd) {
+	item, ok := m.list.SelectedItem().(projectItem)
+	if !ok {
+		return m, nil
+	}
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "kept out of scans")
+		return m, nil
+	}
+	if item.project.RootFolderID == 0 {
+		m.errorMessage = item.project.Name + " is in no root folder, so no scan registers it"
+		return m, nil
+	}
+	if item.project.Locked {
+		m.errorMessage = fmt.Sprintf("%s is locked; unlock it (U) first", item.project.Name)
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Removing %s for good...", item.project.Name)
+	return m, neverRegisterCmd(item.project)
+}
+
+// neverRegisterCmd creates a command that puts a project's directory on the never-register
+// list of its root folder, which removes the project
+func neverRegisterCmd(project models.Project) tea.Cmd {
+	return func() tea.Msg {
+		rootFolder, err := db.GetRootFolderByID(project.RootFolderID)
+		if err != nil {
+			return NeverRegisterMsg{projectName: project.Name, err: err}
+		}
+		_, removed, err := engine.NeverRegister(rootFolder, project.Path)
+		return NeverRegisterMsg{projectName: project.Name, rootName: rootFolder.Name, removed: removed, err: err}
+	}
+}
+
+// neverRegistered reports the project that scans skip from now on
+func (m model) neverRegistered(msg NeverRegisterMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to ignore %s: %v", msg.projectName, msg.err)
+		m.statusMessage = ""
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Removed %d project(s); scans of %s skip %s (devbase ignore rm undoes it)", msg.removed, msg.rootName, msg.projectName)
+	return m, reloadProjectsCmd(m.statusFilter)
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+)
+
+// SaveNotesMsg is sent when saving a project's notes completes
+type SaveNotesMsg struct {
+	projectName string
+	err         error
+}
+
+// openNotesEditor starts editing the notes of a project
+func (m model) openNotesEditor(item projectItem) (tea.Model, tea.Cmd) {
+	input := textarea.New()
+	input.Placeholder = "Markdown notes: todos, ports, credentials hints..."
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.SetValue(item.project.Notes)
+	width, height := m.notesEditorSize()
+	input.SetWidth(width)
+	input.SetHeight(height)
+	cmd := input.Focus()
+
+	itemCopy := item
+	m.notesProject = &itemCopy
+	m.notesInput = input
+	m.editingNotes = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, cmd
+}
+
+// updateNotesEditor handles key presses while editing notes.
+// Leaving the editor saves the notes when they changed.
+func (m model) updateNotesEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "ctrl+s":
+		project := m.notesProject.project
+		notes := m.notesInput.Value()
+		m.editingNotes = false
+		m.notesProject = nil
+		if notes == project.Notes {
+			return m, nil
+		}
+		m.statusMessage = "Saving notes..."
+		return m, saveNotesCmd(project.ID, project.Name, notes)
+	}
+
+	var cmd tea.Cmd
+	m.notesInput, cmd = m.notesInput.Update(msg)
+	return m, cmd
+}
+
+// notesEditorSize returns the textarea dimensions for the current layout
+func (m model) notesEditorSize() (int, int) {
+	_, listHeight := m.listSize()
+	if _, sideWidth := m.layout.split(m.width - 4); sideWidth > 0 {
+		// Inside the notes pane, or the detail pane when it's hidden: border, padding and the
+		// title lines
+		detailHeight, notesHeight := m.layout.splitSide(listHeight)
+		if m.layout.showNotes {
+			return max(sideWidth-4, 10), max(notesHeight-5, 3)
+		}
+		return max(sideWidth-4, 10), max(detailHeight-5, 3)
+	}
+	return max(m.width-8, 10), 8
+}
+
+// viewNotesEditor renders the notes textarea with its title and key hints
+func (m model) viewNotesEditor() string {
+	name := ""
+	if m.notesProject != nil {
+		name = m.notesProject.project.Name
+	}
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("✎ NOTES: "+name) + "\n" +
+		m.notesInput.View() + "\n" +
+		lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("esc=save & close")
+}
+
+// renderMarkdown applies light terminal styling to Markdown notes: headings, lists,
+// checkboxes, quotes and code blocks
+func renderMarkdown(text string) string {
+	headingStyle := titleStyle
+	subheadingStyle := lipgloss.NewStyle().Foreground(colorText).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(colorNoteText)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim).Italic(true)
+	codeStyle := lipgloss.NewStyle().Foreground(colorWarning)
+
+	var lines []string
+	inCode := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are shown verbatim
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, codeStyle.Render("  "+line))
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		switch {
+		case strings.HasPrefix(trimmed, "# "):
+			lines = append(lines, headingStyle.Render(strings.TrimPrefix(trimmed, "# ")))
+		case strings.HasPrefix(trimmed, "#"):
+			lines = append(lines, subheadingStyle.Render(strings.TrimLeft(trimmed, "# ")))
+		case strings.HasPrefix(trimmed, "- [ ] "), strings.HasPrefix(trimmed, "* [ ] "):
+			lines = append(lines, indent+textStyle.Render("☐ "+trimmed[6:]))
+		case strings.HasPrefix(strings.ToLower(trimmed), "- [x] "), strings.HasPrefix(strings.ToLower(trimmed), "* [x] "):
+			lines = append(lines, indent+dimStyle.Render("☑ "+trimmed[6:]))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			lines = append(lines, indent+textStyle.Render("• "+trimmed[2:]))
+		case strings.HasPrefix(trimmed, "> "):
+			lines = append(lines, dimStyle.Render("│ "+trimmed[2:]))
+		default:
+			lines = append(lines, textStyle.Render(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// saveNotesCmd creates a command that saves a project's notes
+func saveNotesCmd(projectID uint, projectName, notes string) tea.Cmd {
+	return func() tea.Msg {
+		err := db.UpdateProjectNotes(projectID, notes)
+		return SaveNotesMsg{projectName: projectName, err: err}
+	}
+}
@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// paletteCommand is an action listed in the command palette
+type paletteCommand struct {
+	title string                             // Human readable action name, used for fuzzy matching
+	key   tea.KeyMsg                         // Key press replayed on the list screen when the command runs
+	run   func(m model) (tea.Model, tea.Cmd) // Runs the command instead of a key press, e.g. plugin actions
+	hint  string                             // Shown instead of the key, for commands without one
+}
+
+// keyRune builds a key message for a single-character keybinding
+func keyRune(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+// paletteCommands lists every action available from the project list
+var paletteCommands = []paletteCommand{
+	{title: "Open project in default editor", key: tea.KeyMsg{Type: tea.KeyEnter}},
+	{title: "Open project with... (choose editor)", key: keyRune('e')},
+	{title: "Open repository in browser", key: keyRune('o')},
+	{title: "Open repository in git client (GitHub Desktop, GitKraken, Fork, Sourcetree)", key: keyRune('G')},
+	{title: "Run project task (dev, test, build...)", key: keyRune('x')},
+	{title: "Open project in tmux session", key: keyRune('a')},
+	{title: "Open project in dev container", key: keyRune('C')},
+	{title: "Scan for projects", key: keyRune('s')},
+	{title: "Clone repository", key: keyRune('g')},
+	{title: "Create new project (git init, .gitignore, README, optional GitHub repository)", key: keyRune('i')},
+	{title: "Browse GitHub repositories", key: keyRune('b')},
+	{title: "Clone starred GitHub repositories", key: keyRune('S')},
+	{title: "Browse organization repositories", key: keyRune('O')},
+	{title: "Open GitHub profile", key: keyRune('p')},
+	{title: "Switch / manage root folders", key: keyRune('f')},
+	{title: "Sync projects to cloud", key: keyRune('u')},
+	{title: "Load projects from cloud", key: keyRune('l')},
+	{title: "Configure GitHub authentication", key: keyRune('t')},
+	{title: "Archive project", key: keyRune('d')},
+	{title: "Restore archived project", key: keyRune('r')},
+	{title: "Restore archived project to another directory or root folder", key: keyRune('A')},
+	{title: "Retry the failed archive or restore", key: keyRune('E')},
+	{title: "Cycle status filter (all / active / archived)", key: keyRune('v')},
+	{title: "Mark / unmark project", key: keyRune('m')},
+	{title: "Pin / unpin project (Windows Terminal profile)", key: keyRune('P')},
+	{title: "Lock / unlock project (refuse archive, remove and clear all)", key: keyRune('U')},
+	{title: "Show what the last scan skipped (ignored, unreadable, duplicate and registered paths)", key: keyRune('Y')},
+	{title: "Never register project: remove it and skip its directory in scans", hint: "ignore", run: func(m model) (tea.Model, tea.Cmd) { return m.neverRegisterSelected() }},
+	{title: "Open marked projects together", key: keyRune('M')},
+	{title: "Save marked projects as session", key: keyRune('W')},
+	{title: "Open saved session", key: keyRune('w')},
+	{title: "Edit project tags", key: keyRune('T')},
+	{title: "Edit project notes", key: keyRune('N')},
+	{title: "Set the branch or tag and clone options the project is restored with", key: keyRune('B')},
+	{title: "Set project icon (emoji or short label before the name)", key: keyRune('I')},
+	{title: "Show activity history", key: keyRune('H')},
+	{title: "Show errors and warnings (log viewer)", key: keyRune('L')},
+	{title: "Clean up stale projects (not opened, no commits)", key: keyRune('Z')},
+	{title: "Reclaim space from dependency folders (node_modules, target, .venv)", key: keyRune('R')},
+	{title: "Show archived projects in the trash and when they are purged", hint: "trash", run: func(m model) (tea.Model, tea.Cmd) { return m.openTrash() }},
+	{title: "Browse captured run output", key: keyRune('X')},
+	{title: "Show the output pane (stop / restart the streamed run)", key: keyRune('F')},
+	{title: "Show background jobs (scans, clones, syncs)", key: keyRune('J')},
+	{title: "Manage git worktrees of the project", key: keyRune('K')},
+	{title: "Toggle detail pane", key: keyRune('D')},
+	{title: "Toggle vim keybindings", key: keyRune('V')},
+	{title: "Toggle notes pane", key: keyRune('n')},
+	{title: "Shrink list pane", key: keyRune('[')},
+	{title: "Grow list pane", key: keyRune(']')},
+	{title: "Shrink notes pane", key: keyRune('{')},
+	{title: "Grow notes pane", key: keyRune('}')},
+	{title: "Filter projects", key: keyRune('/')},
+	{title: "Clear projects of the root folder (summary and confirmation first)", key: keyRune('c')},
+	{title: "Quit", key: keyRune('q')},
+}
+
+// paletteTitles implements fuzzy.Source over a command slice
+type paletteTitles []paletteCommand
+
+func (p paletteTitles) String(i int) string { return p[i].title }
+func (p paletteTitles) Len() int            { return len(p) }
+
+// filterPaletteCommands returns the commands matching the query, best matches first
+func filterPaletteCommands(commands []paletteCommand, query string) []paletteCommand {
+	if query == "" {
+		return commands
+	}
+	matches := fuzzy.FindFrom(query, paletteTitles(commands))
+	filtered := make([]paletteCommand, len(matches))
+	for i, match := range matches {
+		filtered[i] = commands[match.Index]
+	}
+	return filtered
+}
+
+// openPalette shows the command palette with an empty query
+func (m model) openPalette() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "Type a command..."
+	input.Focus()
+	input.CharLimit = 100
+	input.Width = 50
+	m.paletteInput = input
+	m.paletteCursor = 0
+	m.showPalette = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, tea.Batch(textinput.Blink, loadScriptsCmd())
+}
+
+// updatePalette handles key presses while the command palette is open
+func (m model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filtered := filterPaletteCommands(m.paletteCommands(), m.paletteInput.Value())
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "ctrl+p":
+		m.showPalette = false
+		return m, nil
+
+	case "up", "ctrl+k":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+j":
+		if m.paletteCursor < len(filtered)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+
+	case "enter":
+		if len(filtered) == 0 {
+			return m, nil
+		}
+		command := filtered[min(m.paletteCursor, len(filtered)-1)]
+		m.showPalette = false
+		if command.run != nil {
+			return command.run(m)
+		}
+		// Run the action exactly as if its keybinding was pressed
+		return m.runListKey(command.key)
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+// paletteCommands returns the commands available in the palette, scripts and plugin
+// actions last
+func (m model) paletteCommands() []paletteCommand {
+	if len(m.plugins) == 0 && len(m.scripts) == 0 {
+		return paletteCommands
+	}
+	commands := append(slices.Clip(paletteCommands), m.scriptPaletteCommands()...)
+	return append(commands, m.pluginPaletteCommands()...)
+}
+
+// viewPalette renders the command palette
+func (m model) viewPalette() string {
+	filtered := filterPaletteCommands(m.paletteCommands(), m.paletteInput.Value())
+
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("⌘ COMMAND PALETTE") + "\n\n" +
+		m.paletteInput.View() + "\n\n"
+
+	if len(filtered) == 0 {
+		s += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("  No matching commands") + "\n"
+	}
+
+	// Keep the cursor visible within a fixed-height window
+	const maxVisible = 10
+	start := 0
+	if m.paletteCursor >= maxVisible {
+		start = m.paletteCursor - maxVisible + 1
+	}
+	for i := start; i < len(filtered) && i < start+maxVisible; i++ {
+		command := filtered[i]
+		hint := command.hint
+		if hint == "" {
+			hint = command.key.String()
+		}
+		keyHint := lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(fmt.Sprintf(" (%s)", hint))
+		if i == m.paletteCursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+command.title) + keyHint + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+command.title) + keyHint + "\n"
+		}
+	}
+
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\n↑↓=navigate  enter=run  esc=close")
+	return s
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// PinMsg is sent when a project was pinned or unpinned
+type PinMsg struct {
+	projectName string
+	pinned      bool
+	err         error
+	syncErr     error // Updating the Windows Terminal profiles failed
+}
+
+// togglePin pins or unpins the project. Pinned projects get a Windows Terminal profile.
+func (m model) togglePin(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "pinned to Windows Terminal")
+		return m, nil
+	}
+	if item.project.Status == "archived" {
+		m.errorMessage = "Restore the project before pinning it"
+		return m, nil
+	}
+
+	project := item.project
+	m.errorMessage = ""
+	return m, func() tea.Msg {
+		pinned := !project.Pinned
+		if err := db.SetProjectPinned(project.ID, pinned, project.StartCommand); err != nil {
+			return PinMsg{projectName: project.Name, err: err}
+		}
+		return PinMsg{projectName: project.Name, pinned: pinned, syncErr: syncTerminalProfiles()}
+	}
+}
+
+// pinDone reports a pin change and reloads the list to show it
+func (m model) pinDone(msg PinMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to pin %s: %v", msg.projectName, msg.err)
+		return m, nil
+	}
+
+	m.statusMessage = "Unpinned " + msg.projectName
+	if msg.pinned {
+		m.statusMessage = "Pinned " + msg.projectName
+		if runtime.GOOS == "windows" {
+			m.statusMessage += " (Windows Terminal profile added)"
+		}
+	}
+	if msg.syncErr != nil {
+		m.errorMessage = fmt.Sprintf("Failed to update Windows Terminal profiles: %v", msg.syncErr)
+	}
+	return m, reloadProjectsCmd(m.statusFilter)
+}
+
+// syncTerminalProfiles regenerates the Windows Terminal profiles of pinned projects.
+// Other systems have no Windows Terminal, so nothing is written there.
+func syncTerminalProfiles() error {
+	if runtime.GOOS != "windows" {
+		return nil
+	}
+	_, _, err := engine.SyncWTFragment()
+	return err
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/engine"
+	"devbase/models"
+)
+
+// PluginsMsg is sent once the installed plugins have described themselves
+type PluginsMsg struct {
+	plugins []engine.Plugin
+}
+
+// PluginActionMsg is sent when a plugin action has finished
+type PluginActionMsg struct {
+	title  string
+	output string // Last line the plugin printed
+	err    error
+}
+
+// loadPluginsCmd creates a command that discovers plugins in the background, since every
+// plugin is run once to describe itself
+func loadPluginsCmd() tea.Cmd {
+	return func() tea.Msg {
+		return PluginsMsg{plugins: engine.Plugins()}
+	}
+}
+
+// pluginActionCmd creates a command that runs a plugin action for a project, nil when no
+// project is selected
+func pluginActionCmd(plugin engine.Plugin, action engine.PluginAction, project *models.Project) tea.Cmd {
+	return func() tea.Msg {
+		output, err := plugin.RunAction(action.ID, project)
+		return PluginActionMsg{title: action.Title, output: output, err: err}
+	}
+}
+
+// pluginPaletteCommands lists the actions of the loaded plugins for the command palette
+func (m model) pluginPaletteCommands() []paletteCommand {
+	var commands []paletteCommand
+	for _, plugin := range m.plugins {
+		for _, action := range plugin.Actions {
+			commands = append(commands, paletteCommand{
+				title: action.Title,
+				hint:  "plugin " + plugin.Name,
+				run: func(m model) (tea.Model, tea.Cmd) {
+					var project *models.Project
+					if item, ok := m.list.SelectedItem().(projectItem); ok {
+						project = &item.project
+					}
+					m.statusMessage = fmt.Sprintf("Running %s...", action.Title)
+					return m, pluginActionCmd(plugin, action, project)
+				},
+			})
+		}
+	}
+	return commands
+}
+
+// pluginActionDone shows the result of a plugin action
+func (m model) pluginActionDone(msg PluginActionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMessage = ""
+		m.errorMessage = fmt.Sprintf("%s failed: %v", msg.title, msg.err)
+		return m, nil
+	}
+	m.statusMessage = msg.output
+	if m.statusMessage == "" {
+		m.statusMessage = fmt.Sprintf("%s done", msg.title)
+	}
+	return m, nil
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// ReclaimableMsg is sent when the dependency folders of all projects have been measured
+type ReclaimableMsg struct {
+	projects []engine.ReclaimableProject
+	err      error
+}
+
+// ReclaimedMsg is sent when the selected dependency folders have been deleted
+type ReclaimedMsg struct {
+	freed int64
+	err   error
+}
+
+// openReclaim shows the reclaimable-space analyzer and measures the folders in the background
+func (m model) openReclaim() (tea.Model, tea.Cmd) {
+	m.reclaimProjects = nil
+	m.reclaimSelected = make(map[uint]bool)
+	m.reclaimCursor = 0
+	m.reclaimLoading = true
+	m.reclaimConfirm = false
+	m.screen = screenReclaim
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, reclaimableCmd()
+}
+
+// reclaimableCmd creates a command that measures the dependency folders of all projects
+func reclaimableCmd() tea.Cmd {
+	return func() tea.Msg {
+		projects, err := engine.ReclaimableSpace()
+		return ReclaimableMsg{projects: projects, err: err}
+	}
+}
+
+// reclaimCmd creates a command that deletes the dependency folders of the given projects as a job
+func (m model) reclaimCmd(projects []engine.ReclaimableProject) tea.Cmd {
+	title := fmt.Sprintf("Reclaim space in %d projects", len(projects))
+	return m.jobCmd(engine.JobReclaim, title, func(int) (tea.Msg, error) {
+		freed, err := engine.ReclaimSpace(projects)
+		return ReclaimedMsg{freed: freed, err: err}, err
+	})
+}
+
+// updateReclaim handles updates for the reclaimable-space analyzer
+func (m model) updateReclaim(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ReclaimableMsg:
+		m.reclaimLoading = false
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			return m, nil
+		}
+		m.reclaimProjects = msg.projects
+		m.reclaimCursor = 0
+		// Dependency folders are recreated by a reinstall, so everything not opted out starts selected
+		m.reclaimSelected = make(map[uint]bool)
+		for _, p := range msg.projects {
+			if !p.Project.NoReclaim {
+				m.reclaimSelected[p.Project.ID] = true
+			}
+		}
+		return m, nil
+
+	case ReclaimedMsg:
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to reclaim space: %v", msg.err)
+		}
+		m.statusMessage = fmt.Sprintf("Freed %s", engine.FormatSize(msg.freed))
+		m.reclaimLoading = true
+		return m, reclaimableCmd()
+
+	case tea.KeyMsg:
+		if m.reclaimConfirm {
+			switch msg.String() {
+			case "y", "enter":
+				m.reclaimConfirm = false
+				m.reclaimLoading = true
+				m.statusMessage = "Deleting dependency folders..."
+				return m, m.reclaimCmd(m.selectedReclaim())
+			case "ctrl+c":
+				return m, tea.Quit
+			default:
+				m.reclaimConfirm = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "R":
+			m.screen = screenList
+			m.reclaimProjects = nil
+			return m, nil
+
+		case "up", "k":
+			if m.reclaimCursor > 0 {
+				m.reclaimCursor--
+			}
+
+		case "down", "j":
+			if m.reclaimCursor < len(m.reclaimProjects)-1 {
+				m.reclaimCursor++
+			}
+
+		case " ":
+			if len(m.reclaimProjects) == 0 {
+				return m, nil
+			}
+			p := m.reclaimProjects[m.reclaimCursor].Project
+			if p.NoReclaim {
+				m.errorMessage = fmt.Sprintf("%s is opted out; press x to opt it back in", p.Name)
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.reclaimSelected[p.ID] = !m.reclaimSelected[p.ID]
+
+		case "x":
+			// Opt the project out of reclaiming, or back in, for good
+			if len(m.reclaimProjects) == 0 {
+				return m, nil
+			}
+			p := &m.reclaimProjects[m.reclaimCursor].Project
+			if err := db.SetProjectNoReclaim(p.ID, !p.NoReclaim); err != nil {
+				m.errorMessage = err.Error()
+				return m, nil
+			}
+			p.NoReclaim = !p.NoReclaim
+			m.reclaimSelected[p.ID] = !p.NoReclaim
+			m.errorMessage = ""
+
+		case "a":
+			for _, p := range m.reclaimProjects {
+				if !p.Project.NoReclaim {
+					m.reclaimSelected[p.Project.ID] = true
+				}
+			}
+
+		case "n":
+			m.reclaimSelected = make(map[uint]bool)
+
+		case "d":
+			if m.reclaimLoading {
+				return m, nil
+			}
+			if len(m.selectedReclaim()) == 0 {
+				m.errorMessage = "No projects selected"
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.reclaimConfirm = true
+		}
+	}
+
+	return m, nil
+}
+
+// selectedReclaim returns the selected projects in report order
+func (m model) selectedReclaim() []engine.ReclaimableProject {
+	var selected []engine.ReclaimableProject
+	for _, p := range m.reclaimProjects {
+		if m.reclaimSelected[p.Project.ID] && !p.Project.NoReclaim {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// reclaimPageSize returns how many analyzer rows fit on screen
+func (m model) reclaimPageSize() int {
+	return max(5, m.height-14)
+}
+
+// viewReclaim renders the reclaimable-space analyzer
+func (m model) viewReclaim() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Reclaimable Space")
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	var total, selectedSize int64
+	for _, p := range m.reclaimProjects {
+		if p.Project.NoReclaim {
+			continue
+		}
+		total += p.Size
+		if m.reclaimSelected[p.Project.ID] {
+			selectedSize += p.Size
+		}
+	}
+	selected := m.selectedReclaim()
+	s += lipgloss.NewStyle().Foreground(colorText).Render("Dependency and build folders in all projects") +
+		dimStyle.Render(fmt.Sprintf(" (%s reclaimable; %d selected, %s)",
+			engine.FormatSize(total), len(selected), engine.FormatSize(selectedSize))) + "\n\n"
+
+	switch {
+	case m.reclaimLoading && len(m.reclaimProjects) == 0:
+		s += dimStyle.Render("Measuring folders...") + "\n"
+	case len(m.reclaimProjects) == 0:
+		s += dimStyle.Render("No dependency or build folders found") + "\n"
+	}
+
+	// Keep the cursor on screen
+	pageSize := m.reclaimPageSize()
+	start := max(0, m.reclaimCursor-pageSize+1)
+	end := min(len(m.reclaimProjects), start+pageSize)
+	for i, p := range m.reclaimProjects[start:end] {
+		index := start + i
+		cursor := "  "
+		if index == m.reclaimCursor {
+			cursor = "► "
+		}
+		checkbox := "[ ]"
+		switch {
+		case p.Project.NoReclaim:
+			checkbox = "[-]"
+		case m.reclaimSelected[p.Project.ID]:
+			checkbox = "[✓]"
+		}
+
+		names := make([]string, len(p.Dirs))
+		for j, dir := range p.Dirs {
+			names[j] = fmt.Sprintf("%s %s", dir.Name, engine.FormatSize(dir.Size))
+		}
+		detail := strings.Join(names, ", ")
+		if p.Project.NoReclaim {
+			detail = "kept (opted out)  " + detail
+		}
+
+		style := lipgloss.NewStyle().Foreground(colorText)
+		if p.Project.NoReclaim {
+			style = style.Foreground(colorDim)
+		}
+		if index == m.reclaimCursor {
+			style = style.Background(colorSelection).Foreground(colorSelectionText).Bold(true)
+		}
+		s += style.Render(fmt.Sprintf("%s%s %-24s %9s", cursor, checkbox, p.Project.Name, engine.FormatSize(p.Size))) + " " +
+			dimStyle.Render(detail) + "\n"
+	}
+	if end < len(m.reclaimProjects) {
+		s += dimStyle.Render(fmt.Sprintf("… %d more", len(m.reclaimProjects)-end)) + "\n"
+	}
+
+	if m.reclaimConfirm {
+		s += "\n" + lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Bold(true).
+			Render(fmt.Sprintf("Delete the dependency folders of %d projects (%s)? They come back with a reinstall or rebuild. y/n", len(selected), engine.FormatSize(selectedSize))) + "\n"
+	} else {
+		s += dimStyle.Render("\n↑↓=move  space=toggle  x=opt out/in  a=all  n=none  d=delete selected  esc=back")
+	}
+
+	if m.statusMessage != "" {
+		s += lipgloss.NewStyle().Foreground(colorSuccessDim).Render("\n✓ " + m.statusMessage)
+	}
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+
+	return docStyle.Render(s)
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// projectEditorCommand builds the command that opens a project in the editor, going
+// through the project's SSH host for remote projects and through WSL for WSL projects
+func projectEditorCommand(project models.Project, editor engine.Editor) (*exec.Cmd, error) {
+	if project.RemoteHostID == 0 {
+		if w, ok := wslProject(project.Path); ok {
+			return engine.WSLEditorCommand(editor, w)
+		}
+		return engine.EditorCommand(editor, hostPath(project.Path))
+	}
+	host, err := db.GetRemoteHostByID(project.RemoteHostID)
+	if err != nil {
+		return nil, err
+	}
+	return engine.RemoteEditorCommand(editor, *host, project.Path)
+}
+
+// remoteUnsupported explains that an action needs a local checkout
+func remoteUnsupported(item projectItem, action string) string {
+	return fmt.Sprintf("%s lives on %s and can't be %s", item.project.Name, item.remoteHost, action)
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// repoMetaTTL is how long fetched repository details are reused before fetching again
+const repoMetaTTL = 6 * time.Hour
+
+// repoMetaState tracks the repository details of one repository in the detail pane
+type repoMetaState struct {
+	meta    *models.RepoMetadata
+	loading bool
+	err     error
+}
+
+// RepoMetaMsg is sent when repository details were loaded from the cache or the API
+type RepoMetaMsg struct {
+	repo string
+	meta *models.RepoMetadata
+	err  error
+}
+
+// selectedRepoMetaCmd loads the GitHub details of the selected project for the detail pane.
+// Nothing is fetched without a GitHub token, while the pane is hidden, or when the details
+// were already loaded this session.
+func (m *model) selectedRepoMetaCmd() tea.Cmd {
+	if !m.layout.showDetail {
+		return nil
+	}
+	item, ok := m.list.SelectedItem().(projectItem)
+	if !ok {
+		return nil
+	}
+	repo, ok := engine.GitHubRepoFromURL(item.project.RepoURL)
+	if !ok {
+		return nil
+	}
+	if _, seen := m.repoMeta[repo]; seen {
+		return nil
+	}
+	token, _ := db.GetConfig("github_token")
+	if token == "" {
+		return nil
+	}
+
+	if m.repoMeta == nil {
+		m.repoMeta = make(map[string]repoMetaState)
+	}
+	m.repoMeta[repo] = repoMetaState{loading: true}
+	return fetchRepoMetaCmd(token, repo)
+}
+
+// fetchRepoMetaCmd creates a command that returns cached repository details while they are
+// fresh and fetches them from GitHub otherwise. Stale details are kept if fetching fails.
+func fetchRepoMetaCmd(token, repo string) tea.Cmd {
+	return func() tea.Msg {
+		cached, _ := db.GetRepoMetadata(repo)
+		if cached != nil && time.Since(cached.FetchedAt) < repoMetaTTL {
+			return RepoMetaMsg{repo: repo, meta: cached}
+		}
+
+		meta, err := engine.FetchGitHubRepoMetadata(token, repo)
+		if err != nil {
+			return RepoMetaMsg{repo: repo, meta: cached, err: err}
+		}
+		if err := db.SaveRepoMetadata(&meta); err != nil {
+			slog.Warn("Failed to cache repository details", "repo", repo, "err", err)
+		}
+		return RepoMetaMsg{repo: repo, meta: &meta}
+	}
+}
+
+// repoMetaLines renders the repository details of a project for the detail pane
+func (m model) repoMetaLines(project models.Project) []string {
+	repo, ok := engine.GitHubRepoFromURL(project.RepoURL)
+	if !ok {
+		return nil
+	}
+	state, ok := m.repoMeta[repo]
+	if !ok {
+		return nil
+	}
+	if state.meta == nil {
+		if state.loading {
+			return []string{"Loading GitHub details..."}
+		}
+		return []string{"GitHub details unavailable: " + state.err.Error()}
+	}
+
+	var lines []string
+	if state.meta.Description != "" {
+		lines = append(lines, state.meta.Description)
+	}
+	lines = append(lines, fmt.Sprintf("★ %d  ·  %d open issues  ·  %d open PRs", state.meta.Stars, state.meta.OpenIssues, state.meta.OpenPRs))
+	if state.err != nil {
+		lines = append(lines, fmt.Sprintf("(from %s, refresh failed)", relativeTime(state.meta.FetchedAt, time.Now())))
+	}
+	return lines
+}
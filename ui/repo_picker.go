@@ -0,0 +1,351 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// cloneModeOrg is the clone prompt mode asking for an organization to browse
+const cloneModeOrg = "org"
+
+// PickerReposMsg is sent when a page of starred or organization repositories was fetched
+type PickerReposMsg struct {
+	org   string // "" for starred repositories
+	repos []engine.GitHubRepository
+	page  int
+	more  bool // Another page may follow
+	err   error
+}
+
+// CloneReposMsg is sent when cloning the repositories selected in the picker completes
+type CloneReposMsg struct {
+	cloned    int
+	skipped   int      // Already cloned into the root folder
+	failed    []string // "owner/name: error" per failed clone
+	cancelled bool     // The job was cancelled before all repositories were cloned
+}
+
+// fetchPickerReposCmd creates a command that fetches one page of the user's starred
+// repositories, or of the organization's repositories when org is set
+func fetchPickerReposCmd(token, org string, page int) tea.Cmd {
+	return func() tea.Msg {
+		client := engine.NewOAuthClient()
+		var repos []engine.GitHubRepository
+		var more bool
+		var err error
+		if org != "" {
+			repos, more, err = client.FetchOrgRepositories(token, org, page)
+		} else {
+			repos, more, err = client.FetchStarredRepositories(token, page)
+		}
+		return PickerReposMsg{org: org, repos: repos, page: page, more: more, err: err}
+	}
+}
+
+// cloneReposCmd creates a command that clones the repositories into rootPath one after
+// another as a job, skipping those already cloned there. Cancelling the job stops the
+// clone in progress and skips the rest.
+func (m model) cloneReposCmd(repos []engine.GitHubRepository, rootPath string) tea.Cmd {
+	title := fmt.Sprintf("Clone %d repositories", len(repos))
+	return m.cancellableJobCmd(engine.JobClone, title, func(ctx context.Context, id int) (tea.Msg, error) {
+		var msg CloneReposMsg
+		for i, repo := range repos {
+			if ctx.Err() != nil {
+				break
+			}
+			m.jobs.Progress(id, i, len(repos), repo.FullName)
+			if _, err := db.GetProjectByPath(filepath.Join(rootPath, repo.Name)); err == nil {
+				msg.skipped++
+				continue
+			}
+			if _, _, err := cloneProject(ctx, repo.CloneURL, rootPath, ""); err != nil {
+				if ctx.Err() == nil {
+					msg.failed = append(msg.failed, fmt.Sprintf("%s: %v", repo.FullName, err))
+				}
+				continue
+			}
+			msg.cloned++
+		}
+		if ctx.Err() != nil {
+			msg.cancelled = true
+			return msg, ctx.Err()
+		}
+		if len(msg.failed) > 0 {
+			return msg, fmt.Errorf("%d of %d clones failed", len(msg.failed), len(repos))
+		}
+		return msg, nil
+	})
+}
+
+// cloneReposStatus summarizes a batch clone for the status line
+func cloneReposStatus(msg CloneReposMsg) string {
+	status := fmt.Sprintf("Cloned %d repositories", msg.cloned)
+	if msg.skipped > 0 {
+		status += fmt.Sprintf(" (%d already cloned)", msg.skipped)
+	}
+	if msg.cancelled {
+		status += ", then cancelled"
+	}
+	return status
+}
+
+// repoCandidates turns GitHub repositories into clone candidates for the multi-select
+// list shared with the cloud screen, each at the path it would be cloned to. Topics
+// become tags so the tag:/topic: and lang: filters apply.
+func repoCandidates(repos []engine.GitHubRepository, rootPath string) []models.Project {
+	projects := make([]models.Project, len(repos))
+	for i, repo := range repos {
+		var topics []string
+		for _, topic := range repo.Topics {
+			if tag := db.NormalizeTag(topic); tag != "" {
+				topics = append(topics, tag)
+			}
+		}
+		var language string
+		if repo.Language != "" {
+			language = db.NormalizeLanguage(repo.Language)
+		}
+		projects[i] = models.Project{
+			Name:        repo.FullName,
+			Path:        filepath.Join(rootPath, repo.Name),
+			RepoURL:     repo.CloneURL,
+			Description: repo.Description,
+			Language:    language,
+			Tags:        topics,
+		}
+	}
+	return projects
+}
+
+// newPickerList creates the multi-select list for starred or organization repositories
+func newPickerList(org string, repos []engine.GitHubRepository, rootPath string, width, height int) list.Model {
+	l := newCloudList(repoCandidates(repos, rootPath), width, height)
+	l.Title = "Starred Repositories"
+	if org != "" {
+		l.Title = org + " Repositories"
+	}
+	l.SetStatusBarItemName("repository", "repositories")
+	return l
+}
+
+// lastOrg returns the organization browsed last, remembered in the "github_org" config key
+func lastOrg() string {
+	org, _ := db.GetConfig("github_org")
+	return org
+}
+
+// updateOrgPrompt handles key presses while the organization prompt is open
+func (m model) updateOrgPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.confirmClone = false
+		m.errorMessage = ""
+		return m, nil
+
+	case "enter":
+		// Accept "my-company" as well as "github.com/my-company"
+		org := strings.Trim(strings.TrimSpace(m.cloneInput.Value()), "/")
+		org = strings.TrimPrefix(strings.TrimPrefix(org, "https://"), "github.com/")
+		if org == "" || strings.ContainsAny(org, "/ ") {
+			m.errorMessage = "Please enter an organization name"
+			return m, nil
+		}
+		token, _ := db.GetConfig("github_token")
+		_ = db.SetConfig("github_org", org)
+		m.confirmClone = false
+		m.statusMessage = fmt.Sprintf("Loading repositories of %s...", org)
+		m.errorMessage = ""
+		return m, fetchPickerReposCmd(token, org, 1)
+	}
+
+	var cmd tea.Cmd
+	m.cloneInput, cmd = m.cloneInput.Update(msg)
+	return m, cmd
+}
+
+// closePicker returns to the project list
+func (m model) closePicker() model {
+	m.screen = screenList
+	m.pickerRepos = nil
+	m.pickerLoading = false
+	return m
+}
+
+// updateRepoPicker handles updates for the starred and organization repository picker
+func (m model) updateRepoPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.pickerList.SetSize(cloudListSize(msg.Width, msg.Height))
+		return m, nil
+
+	case tea.KeyMsg:
+		// While typing a filter, let the list handle all keys
+		if m.pickerList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.pickerList, cmd = m.pickerList.Update(msg)
+			m.errorMessage = ""
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m.closePicker(), nil
+
+		case "esc":
+			// Clear an applied filter first, otherwise cancel
+			if m.pickerList.FilterState() == list.FilterApplied {
+				m.pickerList.ResetFilter()
+				return m, nil
+			}
+			return m.closePicker(), nil
+
+		case "enter":
+			var repos []engine.GitHubRepository
+			for _, idx := range selectedCloudIndices(m.pickerList) {
+				repos = append(repos, m.pickerRepos[idx])
+			}
+			if len(repos) == 0 {
+				m.errorMessage = "Please select at least one repository"
+				return m, nil
+			}
+			m = m.closePicker()
+			m.statusMessage = fmt.Sprintf("Cloning %d repositories into %s...", len(repos), m.rootScanPath)
+			m.errorMessage = ""
+			return m, m.cloneReposCmd(repos, m.rootScanPath)
+
+		case "m":
+			// Fetch the next page, keeping the current selection
+			if !m.pickerMore || m.pickerLoading {
+				return m, nil
+			}
+			token, _ := db.GetConfig("github_token")
+			m.pickerLoading = true
+			m.errorMessage = ""
+			return m, fetchPickerReposCmd(token, m.pickerOrg, m.pickerPage+1)
+
+		default:
+			// Selection keys: space/tab, a=all, n=none, i=invert, 1-9
+			if cmd, status, ok := multiSelectKey(&m.pickerList, msg.String(), "repositories"); ok {
+				m.errorMessage = ""
+				if status != "" {
+					m.statusMessage = status
+				}
+				return m, cmd
+			}
+		}
+
+	case PickerReposMsg:
+		m.pickerLoading = false
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			return m, nil
+		}
+		m.pickerPage = msg.page
+		m.pickerMore = msg.more
+
+		// Append the page after the rows already listed; indices continue from there
+		items := m.pickerList.Items()
+		for i, p := range repoCandidates(msg.repos, m.rootScanPath) {
+			items = append(items, cloudItem{project: p, index: len(m.pickerRepos) + i})
+		}
+		m.pickerRepos = append(m.pickerRepos, msg.repos...)
+		m.statusMessage = fmt.Sprintf("Loaded %d repositories", len(m.pickerRepos))
+		return m, m.pickerList.SetItems(items)
+	}
+
+	// Navigation, paging and filter results are handled by the list
+	var cmd tea.Cmd
+	m.pickerList, cmd = m.pickerList.Update(msg)
+	return m, cmd
+}
+
+// viewRepoPicker renders the starred and organization repository picker
+func (m model) viewRepoPicker() string {
+	title := "Clone Starred Repositories"
+	if m.pickerOrg != "" {
+		title = "Clone " + m.pickerOrg + " Repositories"
+	}
+
+	// Title box
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render(title)
+
+	s := "\n" + titleBox + "\n\n"
+
+	// Instructions box
+	instructionsBox := lipgloss.NewStyle().
+		Width(68).
+		Padding(1, 2).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(colorBorder).
+		Render(
+			lipgloss.NewStyle().Foreground(colorText).Render("Select repositories to clone (/ filters, e.g. topic:backend lang:go)") + "\n" +
+				lipgloss.NewStyle().Foreground(colorDim).Render("Clones go into "+m.rootScanPath+"; ones already there are skipped"),
+		)
+	s += instructionsBox + "\n\n"
+
+	// Scrollable, filterable repository list
+	s += m.pickerList.View() + "\n"
+
+	// Selection summary
+	if selected := len(selectedCloudIndices(m.pickerList)); selected > 0 {
+		summaryBox := lipgloss.NewStyle().
+			MarginTop(1).
+			Padding(0, 2).
+			Foreground(colorSuccess).
+			Render(fmt.Sprintf("✓ %d repository(s) selected", selected))
+		s += "\n" + summaryBox + "\n"
+	} else {
+		summaryBox := lipgloss.NewStyle().
+			MarginTop(1).
+			Padding(0, 2).
+			Foreground(colorDim).
+			Render("No repositories selected")
+		s += "\n" + summaryBox + "\n"
+	}
+
+	// Compact help text - single line format
+	help := "\n↑↓/jk=navigate  ←→=page  space=toggle  /=filter  a=all  n=none  i=invert"
+	switch {
+	case m.pickerLoading:
+		help += "  (loading more...)"
+	case m.pickerMore:
+		help += "  m=more"
+	}
+	help += "  enter=clone  esc=cancel"
+	s += lipgloss.NewStyle().Foreground(colorDim).Render(help)
+
+	// Display error message if present
+	if m.errorMessage != "" {
+		errorView := errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+		s += errorView
+	}
+
+	// Display status message if present
+	if m.statusMessage != "" {
+		statusView := lipgloss.NewStyle().
+			Foreground(colorSuccessDim).
+			Render("\n✓ " + m.statusMessage)
+		s += statusView
+	}
+
+	return docStyle.Render(s)
+}
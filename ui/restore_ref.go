@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+)
+
+// RestoreRefMsg is sent when a project's restore ref and clone options were saved
+type RestoreRefMsg struct {
+	projectName string
+	ref         string
+	options     string
+	err         error
+}
+
+// openRestoreRef starts editing the branch or tag a project is restored at, and the clone
+// options it is restored with. Without a ref set yet, the checked out branch is suggested.
+func (m model) openRestoreRef(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "archived and restored")
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "default branch"
+	input.Focus()
+	input.CharLimit = 200
+	input.Width = 40
+	input.SetValue(item.project.RestoreRef)
+	if item.project.RestoreRef == "" && item.project.Status == "active" {
+		if info, err := engine.GetGitInfo(item.project.Path); err == nil {
+			input.SetValue(info.Branch)
+		}
+	}
+
+	options := textinput.New()
+	options.Placeholder = "global: " + globalCloneOptions()
+	options.CharLimit = 100
+	options.Width = 40
+	options.SetValue(item.project.CloneOptions)
+
+	itemCopy := item
+	m.refProject = &itemCopy
+	m.refInput = input
+	m.refOptionsInput = options
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// updateRestoreRef handles key presses while editing a restore ref
+func (m model) updateRestoreRef(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.refProject = nil
+		return m, nil
+
+	case "tab", "shift+tab":
+		// Switch between the ref and the clone options
+		if m.refInput.Focused() {
+			m.refInput.Blur()
+			return m, m.refOptionsInput.Focus()
+		}
+		m.refOptionsInput.Blur()
+		return m, m.refInput.Focus()
+
+	case "enter":
+		ref := m.refInput.Value()
+		if err := engine.ValidateRef(ref); err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		options := m.refOptionsInput.Value()
+		if _, _, err := engine.ParseCloneOptions(options); err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		project := m.refProject.project
+		m.refProject = nil
+		m.errorMessage = ""
+		return m, func() tea.Msg {
+			if err := engine.SetRestoreRef(project.ID, ref); err != nil {
+				return RestoreRefMsg{projectName: project.Name, err: err}
+			}
+			err := engine.SetProjectCloneOptions(project.ID, options)
+			return RestoreRefMsg{projectName: project.Name, ref: ref, options: strings.TrimSpace(options), err: err}
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.refInput.Focused() {
+		m.refInput, cmd = m.refInput.Update(msg)
+	} else {
+		m.refOptionsInput, cmd = m.refOptionsInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// globalCloneOptions describes the clone options projects without their own are restored with
+func globalCloneOptions() string {
+	opts, err := engine.GlobalCloneOptions()
+	if err != nil {
+		return "invalid clone_options"
+	}
+	return opts.String()
+}
+
+// restoreRefSaved reports the saved restore ref and reloads the list to show it
+func (m model) restoreRefSaved(msg RestoreRefMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to set restore ref of %s: %v", msg.projectName, msg.err)
+		return m, nil
+	}
+	options := msg.options
+	if options == "" {
+		options = globalCloneOptions()
+	}
+	if msg.ref == "" {
+		m.statusMessage = fmt.Sprintf("%s restores at the default branch (%s)", msg.projectName, options)
+	} else {
+		m.statusMessage = fmt.Sprintf("%s restores at %s (%s)", msg.projectName, msg.ref, options)
+	}
+	return m, reloadProjectsCmd(m.statusFilter)
+}
+
+// viewRestoreRef renders the restore ref and clone options inputs
+func (m model) viewRestoreRef() string {
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("⎇ RESTORE REF: "+m.refProject.project.Name) + "\n\n" +
+		m.refInput.View() + "\n" +
+		dimStyle.Render("Branch or tag checked out when the project is restored; empty uses the default branch") + "\n\n" +
+		"Clone options: " + m.refOptionsInput.View() + "\n" +
+		dimStyle.Render("depth=N, full, filter=blob:none and single-branch, comma-separated; empty uses the global ones") + "\n\n" +
+		dimStyle.Render("tab=switch field  enter=save  esc=cancel")
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// openRestoreTo asks where to restore an archived project, starting from the path it was
+// archived from. Tab puts it into one of the root folders instead.
+func (m model) openRestoreTo(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "archived and restored")
+		return m, nil
+	}
+	if item.project.Status != "archived" {
+		m.errorMessage = item.project.Name + " is not archived"
+		return m, nil
+	}
+
+	roots, err := db.GetAllRootFolders()
+	if err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "directory to clone into"
+	input.Focus()
+	input.CharLimit = 500
+	input.Width = 60
+	input.SetValue(item.project.Path)
+	input.CursorEnd()
+
+	itemCopy := item
+	m.restoreToProject = &itemCopy
+	m.restoreToIdx = m.list.Index()
+	m.restoreToInput = input
+	m.restoreToRoots = roots
+	m.restoreToRoot = -1
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// updateRestoreTo handles key presses while choosing where to restore a project
+func (m model) updateRestoreTo(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.restoreToProject = nil
+		return m, nil
+
+	case "tab", "shift+tab":
+		// Cycle through the root folders, then back to the original path
+		count := len(m.restoreToRoots) + 1
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = count - 1
+		}
+		m.restoreToRoot = (m.restoreToRoot+1+step)%count - 1
+		m.restoreToInput.SetValue(m.restoreToPath())
+		m.restoreToInput.CursorEnd()
+		return m, nil
+
+	case "enter":
+		path := m.restoreToInput.Value()
+		if path == "" {
+			m.errorMessage = "Enter the directory to restore into"
+			return m, nil
+		}
+		item := *m.restoreToProject
+		m.restoreToProject = nil
+		m.errorMessage = ""
+		m.statusMessage = "Restoring project to " + path + "..."
+		return m, restoreProjectToCmd(item.project.ID, path, item, m.restoreToIdx)
+	}
+
+	var cmd tea.Cmd
+	m.restoreToInput, cmd = m.restoreToInput.Update(msg)
+	return m, cmd
+}
+
+// restoreToPath returns the path the chosen root folder suggests: the project's directory
+// name inside it, or the original path when no root folder is chosen
+func (m model) restoreToPath() string {
+	project := m.restoreToProject.project
+	if m.restoreToRoot < 0 {
+		return project.Path
+	}
+	return filepath.Join(m.restoreToRoots[m.restoreToRoot].Path, filepath.Base(project.Path))
+}
+
+// restoreProjectToCmd creates a command that restores a project into another directory
+func restoreProjectToCmd(projectID uint, path string, originalItem projectItem, originalIdx int) tea.Cmd {
+	return func() tea.Msg {
+		err := engine.RestoreProjectTo(projectID, path)
+		if err == nil {
+			_ = db.LogActivity(models.ActivityRestore, projectID, path)
+		}
+		return RestoreMsg{
+			projectID:    projectID,
+			err:          err,
+			originalItem: originalItem,
+			originalIdx:  originalIdx,
+		}
+	}
+}
+
+// viewRestoreTo renders the restore path input with the root folder it is in
+func (m model) viewRestoreTo() string {
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	root := "original location"
+	if m.restoreToRoot >= 0 {
+		root = "root folder " + m.restoreToRoots[m.restoreToRoot].Name
+	}
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("↺ RESTORE TO: "+m.restoreToProject.project.Name) + "\n\n" +
+		m.restoreToInput.View() + "\n" +
+		dimStyle.Render("Clones the repository into this directory and moves the project there ("+root+")") + "\n\n" +
+		dimStyle.Render("tab=next root folder  enter=restore  esc=cancel")
+}
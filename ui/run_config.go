@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// RunConfigMsg offers a devbase.yaml for a cloned project that has no run configuration
+type RunConfigMsg struct {
+	projectName string
+	dir         string
+	tasks       []engine.Task // Commands detected from the project's stack
+}
+
+// RunConfigWrittenMsg is sent when the offered devbase.yaml was written, and committed when
+// that was chosen
+type RunConfigWrittenMsg struct {
+	projectName string
+	path        string
+	committed   bool
+	err         error
+}
+
+// suggestRunConfigCmd creates a command that looks for run commands to offer as a devbase.yaml
+// after a clone. Projects with one, stacks it doesn't know and the run_config_offer config
+// key set to false get no offer.
+func suggestRunConfigCmd(projectName, dir string) tea.Cmd {
+	return func() tea.Msg {
+		if offer, _ := db.GetConfig("run_config_offer"); offer == "false" || engine.HasRunConfig(dir) {
+			return nil
+		}
+		tasks := engine.SuggestRunConfig(dir)
+		if len(tasks) == 0 {
+			return nil
+		}
+		return RunConfigMsg{projectName: projectName, dir: dir, tasks: tasks}
+	}
+}
+
+// runConfigSuggested shows the offer below the list
+func (m model) runConfigSuggested(msg RunConfigMsg) (tea.Model, tea.Cmd) {
+	m.runConfigOffer = &msg
+	return m, nil
+}
+
+// updateRunConfigOffer handles key presses while a devbase.yaml is offered
+func (m model) updateRunConfigOffer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	offer := *m.runConfigOffer
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "n", "esc":
+		m.runConfigOffer = nil
+		m.statusMessage = fmt.Sprintf("%s left without a %s", offer.projectName, engine.RunConfigFile)
+		return m, nil
+
+	case "y", "c":
+		commit := msg.String() == "c"
+		m.runConfigOffer = nil
+		m.errorMessage = ""
+		return m, func() tea.Msg {
+			path, err := engine.WriteRunConfig(offer.dir, offer.tasks)
+			if err != nil || !commit {
+				return RunConfigWrittenMsg{projectName: offer.projectName, path: path, err: err}
+			}
+			err = engine.CommitRunConfig(offer.dir)
+			return RunConfigWrittenMsg{projectName: offer.projectName, path: path, committed: err == nil, err: err}
+		}
+	}
+	return m, nil
+}
+
+// runConfigWritten reports the written devbase.yaml. A failed commit leaves the file in place.
+func (m model) runConfigWritten(msg RunConfigWrittenMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.err != nil && msg.path != "":
+		m.errorMessage = fmt.Sprintf("Wrote %s but failed to commit it: %v", msg.path, msg.err)
+	case msg.err != nil:
+		m.errorMessage = fmt.Sprintf("Failed to write %s for %s: %v", engine.RunConfigFile, msg.projectName, msg.err)
+	case msg.committed:
+		m.statusMessage = fmt.Sprintf("Wrote and committed %s; its commands are listed first by x", msg.path)
+	default:
+		m.statusMessage = fmt.Sprintf("Wrote %s; its commands are listed first by x", msg.path)
+	}
+	return m, nil
+}
+
+// viewRunConfigOffer renders the commands a devbase.yaml would hold
+func (m model) viewRunConfigOffer() string {
+	offer := m.runConfigOffer
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render(fmt.Sprintf("📝 %s has no %s. Write one with these commands?", offer.projectName, engine.RunConfigFile)) + "\n\n"
+	for _, task := range offer.tasks {
+		s += lipgloss.NewStyle().Foreground(colorText).Render(fmt.Sprintf("  %s: %s", task.Name, task.Command)) + "\n"
+	}
+	return s + "\n" + dimStyle.Render("y=write  c=write and commit  n=skip")
+}
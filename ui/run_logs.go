@@ -0,0 +1,351 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// Where runs started from the task picker write their output, the "run_output" config values
+const (
+	runOutputTerminal = "terminal" // A new terminal window (default)
+	runOutputLog      = "log"      // A run log, browsed with X
+	runOutputPane     = "pane"     // A run log streamed into the output pane (F)
+)
+
+// Run log viewer limits
+const (
+	maxRunLogsShown = 100  // Logs listed by the viewer
+	maxRunLogLines  = 2000 // Lines read from the end of a log
+)
+
+// RunCapturedMsg is sent when a run with captured output has started
+type RunCapturedMsg struct {
+	projectName string
+	command     string
+	logPath     string
+	err         error
+}
+
+// runLogTickMsg refreshes the run log viewer; ticks of an earlier viewer are ignored
+type runLogTickMsg struct {
+	id int
+}
+
+// loadRunOutput returns where runs write their output by default
+func loadRunOutput() string {
+	output, _ := db.GetConfig("run_output")
+	switch output {
+	case runOutputLog, runOutputPane:
+		return output
+	}
+	return runOutputTerminal
+}
+
+// nextRunOutput returns the output that follows the given one (terminal -> log -> pane)
+func nextRunOutput(current string) string {
+	switch current {
+	case runOutputTerminal:
+		return runOutputLog
+	case runOutputLog:
+		return runOutputPane
+	}
+	return runOutputTerminal
+}
+
+// outputStatus describes where the next run's output goes in the task picker
+func outputStatus(output string) string {
+	switch output {
+	case runOutputLog:
+		return "Output: run log (c for the output pane)"
+	case runOutputPane:
+		return "Output: output pane in DevBase (c for a terminal window)"
+	}
+	return "Output: terminal window (c to capture to a run log)"
+}
+
+// capturedRunCmd creates a command that runs a command line in the project directory as a
+// child process with its output written to a run log, with the variables of envSource added
+func capturedRunCmd(project models.Project, command, envSource string) tea.Cmd {
+	return func() tea.Msg {
+		opts, err := childRunOptions(project, command, envSource)
+		if err != nil {
+			return RunCapturedMsg{projectName: project.Name, command: command, err: err}
+		}
+		logPath, err := engine.StartCapturedRun(opts)
+		return RunCapturedMsg{projectName: project.Name, command: command, logPath: logPath, err: err}
+	}
+}
+
+// childRunOptions prepares a run of a command line in the project directory as a child
+// process, with the variables of envSource added
+func childRunOptions(project models.Project, command, envSource string) (engine.RunOptions, error) {
+	env, err := engine.LoadProjectEnv(hostPath(project.Path), envSource)
+	if err != nil {
+		return engine.RunOptions{}, err
+	}
+	opts := engine.RunOptions{
+		Project:   project,
+		Dir:       hostPath(project.Path),
+		Command:   command,
+		Env:       env,
+		Task:      command,
+		EnvSource: envSource,
+	}
+	// WSL projects run inside their distribution, like in a terminal window
+	if w, ok := wslProject(project.Path); ok {
+		opts.Dir, opts.Command = wslTerminalDir(), engine.WSLShellCommand(w, command)
+	}
+	return opts, nil
+}
+
+// runCaptured reports a run started with captured output
+func (m model) runCaptured(msg RunCapturedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to run %s: %v", msg.command, msg.err)
+		m.statusMessage = ""
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Running %s in %s, press X for its output", msg.command, msg.projectName)
+	return m, checkDevRunsCmd()
+}
+
+// runLogTickCmd schedules the next refresh of the run log viewer
+func runLogTickCmd(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return runLogTickMsg{id: id}
+	})
+}
+
+// openRunLogs shows the recent run logs of all projects, newest first
+func (m model) openRunLogs() (tea.Model, tea.Cmd) {
+	logs, err := engine.RecentRunLogs(maxRunLogsShown)
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to list run logs: %v", err)
+		return m, nil
+	}
+	m.runLogs = logs
+	m.runLogCursor = 0
+	m.runLogPath = ""
+	m.runLogLines = nil
+	m.runLogTick++
+	m.screen = screenRunLogs
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, runLogTickCmd(m.runLogTick)
+}
+
+// refreshRunLogs reads the log list and the open log again
+func (m *model) refreshRunLogs() {
+	if logs, err := engine.RecentRunLogs(maxRunLogsShown); err == nil {
+		m.runLogs = logs
+		m.runLogCursor = min(m.runLogCursor, max(0, len(logs)-1))
+	}
+	if m.runLogPath != "" {
+		lines, err := engine.TailRunLog(m.runLogPath, maxRunLogLines)
+		if err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to read %s: %v", m.runLogPath, err)
+			return
+		}
+		m.runLogLines = lines
+		m.runLogOffset = min(m.runLogOffset, max(0, len(lines)-m.runLogPageSize()))
+	}
+}
+
+// updateRunLogs handles updates for the run log viewer
+func (m model) updateRunLogs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case runLogTickMsg:
+		if msg.id != m.runLogTick {
+			return m, nil
+		}
+		m.refreshRunLogs()
+		return m, runLogTickCmd(m.runLogTick)
+
+	case tea.KeyMsg:
+		if m.runLogPath != "" {
+			return m.updateRunLog(msg)
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "X":
+			m.screen = screenList
+			m.runLogs = nil
+			m.runLogTick++
+			return m, nil
+
+		case "up", "k":
+			if m.runLogCursor > 0 {
+				m.runLogCursor--
+			}
+
+		case "down", "j":
+			if m.runLogCursor < len(m.runLogs)-1 {
+				m.runLogCursor++
+			}
+
+		case "enter":
+			if len(m.runLogs) == 0 {
+				return m, nil
+			}
+			m.runLogPath = m.runLogs[m.runLogCursor].Path
+			m.runLogOffset = 0
+			m.errorMessage = ""
+			m.refreshRunLogs()
+
+		case "r":
+			m.refreshRunLogs()
+		}
+	}
+
+	return m, nil
+}
+
+// updateRunLog handles key presses while a run log is open. The offset counts lines from
+// the end, so at 0 the view follows new output.
+func (m model) updateRunLog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxOffset := max(0, len(m.runLogLines)-m.runLogPageSize())
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.runLogPath = ""
+		m.runLogLines = nil
+		m.errorMessage = ""
+
+	case "up", "k":
+		m.runLogOffset = min(maxOffset, m.runLogOffset+1)
+
+	case "down", "j":
+		m.runLogOffset = max(0, m.runLogOffset-1)
+
+	case "pgup":
+		m.runLogOffset = min(maxOffset, m.runLogOffset+m.runLogPageSize())
+
+	case "pgdown":
+		m.runLogOffset = max(0, m.runLogOffset-m.runLogPageSize())
+
+	case "g", "home":
+		m.runLogOffset = maxOffset
+
+	case "G", "end":
+		m.runLogOffset = 0
+	}
+
+	return m, nil
+}
+
+// runLogPageSize returns how many list rows or log lines fit on screen
+func (m model) runLogPageSize() int {
+	return max(5, m.height-12)
+}
+
+// viewRunLogs renders the run log list, or the open log
+func (m model) viewRunLogs() string {
+	title := "Run Logs"
+	if m.runLogPath != "" {
+		title = "Run Log"
+	}
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render(title)
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	if m.runLogPath != "" {
+		s += m.viewRunLog(dimStyle)
+	} else {
+		s += m.viewRunLogList(dimStyle)
+	}
+
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+	return docStyle.Render(s)
+}
+
+// viewRunLogList renders the recent run logs, newest first
+func (m model) viewRunLogList(dimStyle lipgloss.Style) string {
+	var s string
+	if dir, err := engine.RunLogDir(); err == nil {
+		s += dimStyle.Render("Logs in "+dir) + "\n\n"
+	}
+	if len(m.runLogs) == 0 {
+		s += dimStyle.Render("No captured runs yet; press c in the run picker (x) to capture one") + "\n"
+	}
+
+	pageSize := m.runLogPageSize()
+	start := max(0, m.runLogCursor-pageSize+1)
+	end := min(len(m.runLogs), start+pageSize)
+	for i, log := range m.runLogs[start:end] {
+		index := start + i
+		cursor := "  "
+		if index == m.runLogCursor {
+			cursor = "► "
+		}
+		state := "finished"
+		if log.Running {
+			state = "running"
+		}
+
+		style := lipgloss.NewStyle().Foreground(colorText)
+		if index == m.runLogCursor {
+			style = style.Background(colorSelection).Foreground(colorSelectionText).Bold(true)
+		}
+		line := style.Render(fmt.Sprintf("%s%-24s %s", cursor, log.Project, log.Started.Format("2006-01-02 15:04:05"))) + " "
+		if log.Running {
+			line += lipgloss.NewStyle().Foreground(colorSuccess).Render(fmt.Sprintf("%-8s", state))
+		} else {
+			line += dimStyle.Render(fmt.Sprintf("%-8s", state))
+		}
+		s += line + dimStyle.Render(" "+engine.FormatSize(log.Size)) + "\n"
+	}
+	if end < len(m.runLogs) {
+		s += dimStyle.Render(fmt.Sprintf("… %d older", len(m.runLogs)-end)) + "\n"
+	}
+
+	s += dimStyle.Render("\n↑↓=move  enter=view  r=refresh  esc=back")
+	return s
+}
+
+// viewRunLog renders the end of the open log, or the lines scrolled to
+func (m model) viewRunLog(dimStyle lipgloss.Style) string {
+	s := dimStyle.Render(filepath.Base(filepath.Dir(m.runLogPath))+"  "+m.runLogPath) + "\n\n"
+
+	pageSize := m.runLogPageSize()
+	end := len(m.runLogLines) - m.runLogOffset
+	start := max(0, end-pageSize)
+	width := max(20, m.width-4)
+	textStyle := lipgloss.NewStyle().Foreground(colorText)
+	for _, line := range m.runLogLines[start:end] {
+		s += textStyle.Render(ansi.Truncate(ansi.Strip(line), width, "…")) + "\n"
+	}
+	if len(m.runLogLines) == 0 {
+		s += dimStyle.Render("No output yet") + "\n"
+	}
+
+	position := "following"
+	if m.runLogOffset > 0 {
+		position = fmt.Sprintf("%d lines above the end", m.runLogOffset)
+	}
+	s += dimStyle.Render(fmt.Sprintf("\n%s  ↑↓/pgup/pgdn=scroll  g/G=top/end  esc=back", position))
+	return s
+}
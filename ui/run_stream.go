@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"devbase/engine"
+	"devbase/models"
+)
+
+// runStreamInterval is how often the output pane shows new output
+const runStreamInterval = 200 * time.Millisecond
+
+// RunStreamMsg is sent when a run streamed into the output pane has started
+type RunStreamMsg struct {
+	project   models.Project
+	command   string
+	envSource string
+	run       *engine.StreamedRun
+	err       error
+}
+
+// RunStreamExitedMsg is sent when the command of a streamed run exited
+type RunStreamExitedMsg struct {
+	run *engine.StreamedRun
+}
+
+// runStreamTickMsg refreshes the output pane; ticks of an earlier pane are ignored
+type runStreamTickMsg struct {
+	id int
+}
+
+// startStreamedRun starts a command in the project directory with its output streamed into
+// the output pane. One run streams at a time, so a running one has to be stopped first.
+func (m model) startStreamedRun(project models.Project, command, envSource string) (tea.Model, tea.Cmd) {
+	if m.streamRunning() {
+		m.errorMessage = fmt.Sprintf("%s is still running in the output pane; stop it there first (F, then s)", m.streamCommand)
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Starting %s%s...", command, envSuffix(envSource))
+	return m, streamRunCmd(project, command, envSource, nil)
+}
+
+// streamRunCmd creates a command that starts a streamed run of a command line in the project
+// directory, with the variables of envSource added, once the previous run exited
+func streamRunCmd(project models.Project, command, envSource string, previous *engine.StreamedRun) tea.Cmd {
+	return func() tea.Msg {
+		if previous != nil {
+			if err := previous.Stop(); err != nil {
+				return RunStreamMsg{project: project, command: command, envSource: envSource, err: err}
+			}
+			<-previous.Done()
+		}
+
+		opts, err := childRunOptions(project, command, envSource)
+		if err != nil {
+			return RunStreamMsg{project: project, command: command, envSource: envSource, err: err}
+		}
+		run, err := engine.StartStreamedRun(opts)
+		return RunStreamMsg{project: project, command: command, envSource: envSource, run: run, err: err}
+	}
+}
+
+// waitRunStreamCmd reports when the command of a streamed run exited
+func waitRunStreamCmd(run *engine.StreamedRun) tea.Cmd {
+	return func() tea.Msg {
+		<-run.Done()
+		return RunStreamExitedMsg{run: run}
+	}
+}
+
+// runStreamTickCmd schedules the next refresh of the output pane
+func runStreamTickCmd(id int) tea.Cmd {
+	return tea.Tick(runStreamInterval, func(time.Time) tea.Msg {
+		return runStreamTickMsg{id: id}
+	})
+}
+
+// streamRunning reports whether the run in the output pane is still going
+func (m model) streamRunning() bool {
+	if m.streamRun == nil {
+		return false
+	}
+	_, exited := m.streamRun.Exit()
+	return !exited
+}
+
+// runStreamStarted shows the output pane following the run that started
+func (m model) runStreamStarted(msg RunStreamMsg) (tea.Model, tea.Cmd) {
+	m.streamRestarting = false
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to run %s: %v", msg.command, msg.err)
+		m.statusMessage = ""
+		return m, nil
+	}
+	m.streamRun = msg.run
+	m.streamProject = msg.project
+	m.streamCommand = msg.command
+	m.streamEnv = msg.envSource
+	m.streamView = viewport.New(0, 0)
+	m.streamFollow = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	model, cmd := m.openRunStream()
+	return model, tea.Batch(cmd, waitRunStreamCmd(msg.run), checkDevRunsCmd())
+}
+
+// runStreamExited reports the end of the streamed run when the pane isn't shown
+func (m model) runStreamExited(msg RunStreamExitedMsg) (tea.Model, tea.Cmd) {
+	if msg.run != m.streamRun || m.screen == screenRunOutput {
+		return m, checkDevRunsCmd()
+	}
+	exit, _ := msg.run.Exit()
+	m.statusMessage = fmt.Sprintf("%s in %s %s, press F for its output", m.streamCommand, m.streamProject.Name, exit)
+	return m, checkDevRunsCmd()
+}
+
+// openRunStream shows the output pane of the streamed run
+func (m model) openRunStream() (tea.Model, tea.Cmd) {
+	if m.streamRun == nil {
+		m.errorMessage = "Nothing runs in the output pane; press c in the run picker (x) until it shows the output pane"
+		return m, nil
+	}
+	m.screen = screenRunOutput
+	m.streamTick++
+	m.refreshRunStream()
+	return m, runStreamTickCmd(m.streamTick)
+}
+
+// refreshRunStream puts the latest output into the pane, scrolled to the end while following
+func (m *model) refreshRunStream() {
+	m.streamView.Width = max(20, m.width-4)
+	m.streamView.Height = max(5, m.height-12)
+
+	lines := m.streamRun.Lines(maxRunLogLines)
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(ansi.Strip(line), m.streamView.Width, "…")
+	}
+	m.streamView.SetContent(strings.Join(lines, "\n"))
+	if m.streamFollow {
+		m.streamView.GotoBottom()
+	}
+}
+
+// updateRunStream handles updates for the output pane
+func (m model) updateRunStream(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case runStreamTickMsg:
+		if msg.id != m.streamTick {
+			return m, nil
+		}
+		m.refreshRunStream()
+		return m, runStreamTickCmd(m.streamTick)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "F":
+			// The run keeps going; F shows it again
+			m.screen = screenList
+			m.streamTick++
+			m.errorMessage = ""
+			if m.streamRunning() {
+				m.statusMessage = fmt.Sprintf("%s keeps running in %s, press F for its output", m.streamCommand, m.streamProject.Name)
+			}
+			return m, nil
+
+		case "s":
+			if !m.streamRunning() {
+				return m, nil
+			}
+			if err := m.streamRun.Stop(); err != nil {
+				m.errorMessage = err.Error()
+			}
+			return m, nil
+
+		case "r":
+			if m.streamRestarting {
+				return m, nil
+			}
+			m.streamRestarting = true
+			m.errorMessage = ""
+			return m, streamRunCmd(m.streamProject, m.streamCommand, m.streamEnv, m.streamRun)
+
+		case "g", "home":
+			m.streamFollow = false
+			m.streamView.GotoTop()
+			return m, nil
+
+		case "G", "end":
+			m.streamFollow = true
+			m.streamView.GotoBottom()
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.streamView, cmd = m.streamView.Update(msg)
+		m.streamFollow = m.streamView.AtBottom()
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// viewRunStream renders the output pane with the state of the run
+func (m model) viewRunStream() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Run Output")
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	var state string
+	if m.streamRestarting {
+		state = lipgloss.NewStyle().Foreground(colorWarning).Render("restarting...")
+	} else if exit, exited := m.streamRun.Exit(); exited {
+		state = dimStyle.Render(exit)
+	} else {
+		state = lipgloss.NewStyle().Foreground(colorSuccess).
+			Render("running for " + time.Since(m.streamRun.Started).Round(time.Second).String())
+	}
+	s += dimStyle.Render(m.streamProject.Name+"  $ "+m.streamCommand+"  ") + state + "\n\n"
+	s += m.streamView.View() + "\n"
+
+	position := "following"
+	if !m.streamFollow {
+		position = fmt.Sprintf("%3.f%%", m.streamView.ScrollPercent()*100)
+	}
+	s += dimStyle.Render(fmt.Sprintf("\n%s  ↑↓/pgup/pgdn=scroll  g/G=top/follow  s=stop  r=restart  esc=back (keeps running)", position))
+
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+	return docStyle.Render(s)
+}
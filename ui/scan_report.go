@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+)
+
+// scanReportLimit is how many entries an expanded section of the scan report shows
+const scanReportLimit = 15
+
+// scanReportSection is a collapsible list of the scan report
+type scanReportSection struct {
+	title   string
+	entries []engine.ScanSkip
+}
+
+// scanReportSections returns the sections of the last scan's report, in the order shown
+func (m model) scanReportSections() []scanReportSection {
+	report := m.scanReport
+	registered := make([]engine.ScanSkip, len(report.Registered))
+	for i, path := range report.Registered {
+		registered[i] = engine.ScanSkip{Path: path}
+	}
+	return []scanReportSection{
+		{"Skipped by ignore rules", report.Ignored},
+		{"Unreadable", report.Unreadable},
+		{"Duplicate paths", report.Duplicates},
+		{"Already registered", registered},
+	}
+}
+
+// scanReported keeps the report of a finished scan for the report screen (Y) and appends
+// its counts to the status when anything was left out
+func (m *model) scanReported(msg ScanCompleteMsg) {
+	report := msg.report
+	m.scanReport = &report
+	m.scanReportPath = msg.path
+	if report.Problems() {
+		m.statusMessage += fmt.Sprintf(" (%s; Y=report)", report.Summary())
+	}
+}
+
+// openScanReport shows what the last scan skipped, with the sections that have problems
+// expanded
+func (m model) openScanReport() (tea.Model, tea.Cmd) {
+	if m.scanReport == nil {
+		m.errorMessage = "No scan has finished yet; press s to scan"
+		return m, nil
+	}
+	m.scanReportCursor = 0
+	for i, section := range m.scanReportSections() {
+		// Registered projects are expected, so their list starts collapsed
+		m.scanReportOpen[i] = len(section.entries) > 0 && section.title != "Already registered"
+	}
+	m.screen = screenScanReport
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// updateScanReport handles key presses on the scan report screen
+func (m model) updateScanReport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "Y":
+		m.screen = screenList
+		return m, nil
+
+	case "up", "k":
+		if m.scanReportCursor > 0 {
+			m.scanReportCursor--
+		}
+
+	case "down", "j":
+		if m.scanReportCursor < len(m.scanReportOpen)-1 {
+			m.scanReportCursor++
+		}
+
+	case "enter", " ":
+		m.scanReportOpen[m.scanReportCursor] = !m.scanReportOpen[m.scanReportCursor]
+
+	case "right", "l":
+		m.scanReportOpen[m.scanReportCursor] = true
+
+	case "left", "h":
+		m.scanReportOpen[m.scanReportCursor] = false
+	}
+	return m, nil
+}
+
+// viewScanReport renders the sections of the scan report, the expanded ones with their paths
+func (m model) viewScanReport() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Scan Report")
+
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	s := "\n" + titleBox + "\n\n" + dimStyle.Render(m.scanReportPath) + "\n"
+	if m.scanReport.IgnoredBuiltin > 0 {
+		s += dimStyle.Render(fmt.Sprintf("%d dependency and build directories (node_modules, target, …) skipped as usual", m.scanReport.IgnoredBuiltin)) + "\n"
+	}
+	s += "\n"
+
+	for i, section := range m.scanReportSections() {
+		prefix, marker := "  ", "▸"
+		if m.scanReportOpen[i] {
+			marker = "▾"
+		}
+		style := lipgloss.NewStyle().Foreground(colorText).Bold(true)
+		if i == m.scanReportCursor {
+			prefix = "► "
+			style = style.Background(colorSelection).Foreground(colorSelectionText)
+		}
+		countStyle := dimStyle
+		if len(section.entries) > 0 && section.title != "Already registered" {
+			countStyle = lipgloss.NewStyle().Foreground(colorWarning)
+		}
+		s += style.Render(fmt.Sprintf("%s%s %s", prefix, marker, section.title)) + " " +
+			countStyle.Render(fmt.Sprintf("(%d)", len(section.entries))) + "\n"
+
+		if !m.scanReportOpen[i] {
+			continue
+		}
+		if len(section.entries) == 0 {
+			s += dimStyle.Render("      none") + "\n"
+		}
+		for _, entry := range section.entries[:min(len(section.entries), scanReportLimit)] {
+			line := lipgloss.NewStyle().Foreground(colorText).Render("      " + entry.Path)
+			if entry.Reason != "" {
+				line += " " + dimStyle.Render(entry.Reason)
+			}
+			s += line + "\n"
+		}
+		if len(section.entries) > scanReportLimit {
+			s += dimStyle.Render(fmt.Sprintf("      … and %d more", len(section.entries)-scanReportLimit)) + "\n"
+		}
+	}
+
+	s += dimStyle.Render("\n↑↓=move  enter=expand/collapse  esc=back")
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+	return docStyle.Render(s)
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/engine"
+	"devbase/models"
+)
+
+// ScriptsMsg is sent when the automation scripts have been listed
+type ScriptsMsg struct {
+	scripts []engine.Script
+}
+
+// ScriptMsg is sent when an action script has finished
+type ScriptMsg struct {
+	title  string
+	output string
+	err    error
+}
+
+// loadScriptsCmd creates a command that lists the action scripts. It runs whenever the
+// palette opens, so new scripts show up without a restart.
+func loadScriptsCmd() tea.Cmd {
+	return func() tea.Msg {
+		scripts, err := engine.Scripts()
+		if err != nil {
+			slog.Warn("Failed to list scripts", "err", err)
+		}
+		return ScriptsMsg{scripts: scripts}
+	}
+}
+
+// runScriptCmd creates a command that runs an action script for a project, nil when no
+// project is selected
+func runScriptCmd(script engine.Script, project *models.Project) tea.Cmd {
+	return func() tea.Msg {
+		output, err := script.Run(project)
+		return ScriptMsg{title: script.Title, output: output, err: err}
+	}
+}
+
+// scriptPaletteCommands lists the action scripts for the command palette
+func (m model) scriptPaletteCommands() []paletteCommand {
+	var commands []paletteCommand
+	for _, script := range m.scripts {
+		if !script.Action || script.Err != nil {
+			continue
+		}
+		commands = append(commands, paletteCommand{
+			title: script.Title,
+			hint:  "script " + script.Name,
+			run: func(m model) (tea.Model, tea.Cmd) {
+				var project *models.Project
+				if item, ok := m.list.SelectedItem().(projectItem); ok {
+					project = &item.project
+				}
+				m.statusMessage = fmt.Sprintf("Running %s...", script.Title)
+				return m, runScriptCmd(script, project)
+			},
+		})
+	}
+	return commands
+}
+
+// scriptDone shows the result of an action script and reloads the list, since scripts
+// can change tags
+func (m model) scriptDone(msg ScriptMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMessage = ""
+		m.errorMessage = fmt.Sprintf("%s failed: %v", msg.title, msg.err)
+		return m, nil
+	}
+	m.statusMessage = lastLine(msg.output)
+	if m.statusMessage == "" {
+		m.statusMessage = fmt.Sprintf("%s done", msg.title)
+	}
+	return m, reloadProjectsCmd(m.statusFilter)
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// markedSessionName names the workspace used when opening marked projects without a session
+const markedSessionName = "marked-projects"
+
+// OpenSessionMsg is sent when opening a set of projects in the editor completes
+type OpenSessionMsg struct {
+	name    string
+	opened  int
+	skipped int // Projects that are archived or whose directory is missing
+	editor  string
+	err     error
+}
+
+// toggleMark marks or unmarks the selected project for opening as a group
+func (m model) toggleMark() (tea.Model, tea.Cmd) {
+	item, ok := m.list.SelectedItem().(projectItem)
+	if !ok {
+		return m, nil
+	}
+
+	if m.marked == nil {
+		m.marked = make(map[uint]bool)
+	}
+	item.marked = !item.marked
+	if item.marked {
+		m.marked[item.project.ID] = true
+	} else {
+		delete(m.marked, item.project.ID)
+	}
+
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("%d project(s) marked (M=open together, W=save as session)", len(m.marked))
+	return m, m.list.SetItem(m.list.Index(), item)
+}
+
+// markedProjectIDs returns the IDs of the marked projects in list order
+func (m model) markedProjectIDs() []uint {
+	var ids []uint
+	for _, item := range m.list.Items() {
+		if pi, ok := item.(projectItem); ok && m.marked[pi.project.ID] {
+			ids = append(ids, pi.project.ID)
+		}
+	}
+	return ids
+}
+
+// openMarked opens all marked projects together in the default editor
+func (m model) openMarked() (tea.Model, tea.Cmd) {
+	ids := m.markedProjectIDs()
+	if len(ids) == 0 {
+		m.errorMessage = "No projects marked (press m to mark projects)"
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = fmt.Sprintf("Opening %d projects...", len(ids))
+	return m, openSessionCmd(markedSessionName, ids, defaultEditor())
+}
+
+// startSaveSession prompts for a name to save the marked projects as a session
+func (m model) startSaveSession() (tea.Model, tea.Cmd) {
+	if len(m.markedProjectIDs()) == 0 {
+		m.errorMessage = "No projects marked (press m to mark projects)"
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Session name (e.g. frontend + api)"
+	input.Focus()
+	input.CharLimit = 100
+	input.Width = 50
+	m.sessionInput = input
+	m.savingSession = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// updateSaveSession handles key presses while naming a session
+func (m model) updateSaveSession(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.savingSession = false
+		m.statusMessage = "Cancelled"
+		return m, nil
+
+	case "enter":
+		name := strings.TrimSpace(m.sessionInput.Value())
+		if name == "" {
+			m.errorMessage = "Please enter a session name"
+			return m, nil
+		}
+		ids := m.markedProjectIDs()
+		session, err := db.SaveSession(name, ids)
+		if err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		m.savingSession = false
+		m.errorMessage = ""
+		// The workspace file is kept up to date, so the session can be opened outside of DevBase
+		file, err := engine.WriteSessionWorkspace(*session)
+		if err != nil {
+			m.errorMessage = fmt.Sprintf("Saved session %q but failed to write its workspace: %v", name, err)
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("Saved session %q with %d projects as %s (w=sessions)", name, len(ids), file)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.sessionInput, cmd = m.sessionInput.Update(msg)
+	return m, cmd
+}
+
+// openSessionPicker shows the saved sessions
+func (m model) openSessionPicker() (tea.Model, tea.Cmd) {
+	sessions, err := db.GetSessions()
+	if err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+	if len(sessions) == 0 {
+		m.errorMessage = "No saved sessions (mark projects with m, then press W)"
+		return m, nil
+	}
+
+	m.sessions = sessions
+	m.sessionCursor = 0
+	m.showSessions = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// updateSessionPicker handles key presses while the session picker is open
+func (m model) updateSessionPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "w":
+		m.showSessions = false
+		return m, nil
+
+	case "up", "k":
+		if m.sessionCursor > 0 {
+			m.sessionCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.sessionCursor < len(m.sessions)-1 {
+			m.sessionCursor++
+		}
+		return m, nil
+
+	case "enter":
+		session := m.sessions[m.sessionCursor]
+		m.showSessions = false
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Opening session %q...", session.Name)
+		return m, openSessionCmd(session.Name, session.ProjectIDs, defaultEditor())
+
+	case "x", "delete":
+		// Delete the highlighted session
+		session := m.sessions[m.sessionCursor]
+		if err := db.DeleteSession(session.ID); err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		if err := engine.RemoveSessionWorkspace(session.Name); err != nil {
+			slog.Warn("Failed to remove session workspace", "session", session.Name, "err", err)
+		}
+		m.sessions = append(m.sessions[:m.sessionCursor], m.sessions[m.sessionCursor+1:]...)
+		m.statusMessage = fmt.Sprintf("Deleted session %q", session.Name)
+		if len(m.sessions) == 0 {
+			m.showSessions = false
+			return m, nil
+		}
+		m.sessionCursor = min(m.sessionCursor, len(m.sessions)-1)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// viewSaveSession renders the session name prompt
+func (m model) viewSaveSession() string {
+	return lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("💾 SAVE SESSION") + "\n\n" +
+		lipgloss.NewStyle().
+			Foreground(colorText).
+			Render(fmt.Sprintf("Save %d marked projects as:", len(m.markedProjectIDs()))) + "\n" +
+		m.sessionInput.View() + "\n\n" +
+		lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("Press Enter to save | ESC to cancel")
+}
+
+// viewSessionPicker renders the saved sessions
+func (m model) viewSessionPicker() string {
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("🗂 SESSIONS") + "\n\n"
+
+	for i, session := range m.sessions {
+		hint := lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(fmt.Sprintf(" (%d projects)", len(session.ProjectIDs)))
+		if i == m.sessionCursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+session.Name) + hint + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+session.Name) + hint + "\n"
+		}
+	}
+
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\n↑↓=navigate  enter=open  x=delete  esc=close")
+	return s
+}
+
+// openSessionCmd creates a command that opens a set of projects together in the editor.
+// Archived, remote and missing projects are skipped.
+func openSessionCmd(name string, projectIDs []uint, editor engine.Editor) tea.Cmd {
+	return func() tea.Msg {
+		projects, skipped := engine.SessionProjects(projectIDs)
+		if len(projects) == 0 {
+			return OpenSessionMsg{name: name, skipped: skipped, editor: editor.Name, err: fmt.Errorf("none of the projects are available locally")}
+		}
+
+		paths := make([]string, len(projects))
+		for i, p := range projects {
+			paths[i] = p.Path
+		}
+
+		cmd, err := engine.EditorSessionCommand(editor, name, paths)
+		if err == nil {
+			err = cmd.Start()
+		}
+		if err == nil {
+			// Update LastOpened timestamps
+			for _, p := range projects {
+				if _, err := engine.RecordOpen(p); err != nil {
+					slog.Warn("Failed to record project open", "project", p.Name, "err", err)
+				}
+				_ = db.LogActivity(models.ActivityOpen, p.ID, fmt.Sprintf("%s (session %s)", editor.Name, name))
+			}
+		}
+
+		return OpenSessionMsg{name: name, opened: len(projects), skipped: skipped, editor: editor.Name, err: err}
+	}
+}
@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// StaleProjectsMsg is sent when the stale project report has been built
+type StaleProjectsMsg struct {
+	projects []engine.StaleProject
+	err      error
+}
+
+// StaleArchivedMsg is sent when the selected stale projects have been archived
+type StaleArchivedMsg struct {
+	archived int
+	freed    int64 // Bytes of the archived directories
+	err      error
+}
+
+// openStale shows the stale project report and builds it in the background
+func (m model) openStale() (tea.Model, tea.Cmd) {
+	m.staleProjects = nil
+	m.staleSelected = make(map[uint]bool)
+	m.staleCursor = 0
+	m.staleLoading = true
+	m.staleConfirm = false
+	m.staleDays = engine.StaleDays()
+	m.screen = screenStale
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, staleProjectsCmd(m.staleDays)
+}
+
+// staleProjectsCmd creates a command that builds the stale project report
+func staleProjectsCmd(days int) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := engine.StaleProjects(days)
+		return StaleProjectsMsg{projects: projects, err: err}
+	}
+}
+
+// archiveStaleCmd creates a command that archives the given projects one after another as
+// a job. Cancelling the job skips the projects not archived yet.
+func (m model) archiveStaleCmd(projects []engine.StaleProject) tea.Cmd {
+	title := fmt.Sprintf("Archive %d stale projects", len(projects))
+	return m.cancellableJobCmd(engine.JobArchive, title, func(ctx context.Context, id int) (tea.Msg, error) {
+		msg := StaleArchivedMsg{}
+		var errs []error
+		for i, p := range projects {
+			if ctx.Err() != nil {
+				errs = append(errs, ctx.Err())
+				break
+			}
+			m.jobs.Progress(id, i, len(projects), p.Project.Name)
+			if err := engine.ArchiveProject(p.Project.ID); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", p.Project.Name, err))
+				continue
+			}
+			_ = db.LogActivity(models.ActivityArchive, p.Project.ID, "stale")
+			msg.archived++
+			msg.freed += p.Size
+		}
+		msg.err = errors.Join(errs...)
+		return msg, msg.err
+	})
+}
+
+// updateStale handles updates for the stale project report
+func (m model) updateStale(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StaleProjectsMsg:
+		m.staleLoading = false
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			return m, nil
+		}
+		m.staleProjects = msg.projects
+		m.staleCursor = 0
+		// Preselect the projects that archiving loses nothing of
+		m.staleSelected = make(map[uint]bool)
+		for _, p := range msg.projects {
+			if p.Safe() {
+				m.staleSelected[p.Project.ID] = true
+			}
+		}
+		return m, nil
+
+	case StaleArchivedMsg:
+		m.staleChanged = m.staleChanged || msg.archived > 0
+		m.statusMessage = fmt.Sprintf("Archived %d projects, moved %s out of the root folder", msg.archived, engine.FormatSize(msg.freed))
+		if errors.Is(msg.err, context.Canceled) {
+			m.statusMessage += ", then cancelled"
+		} else if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to archive: %v", msg.err)
+		}
+		m.staleLoading = true
+		return m, staleProjectsCmd(m.staleDays)
+
+	case tea.KeyMsg:
+		if m.staleConfirm {
+			switch msg.String() {
+			case "y", "enter":
+				m.staleConfirm = false
+				m.staleLoading = true
+				m.statusMessage = "Archiving..."
+				return m, m.archiveStaleCmd(m.selectedStale())
+			case "ctrl+c":
+				return m, tea.Quit
+			default:
+				m.staleConfirm = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "Z":
+			m.screen = screenList
+			m.staleProjects = nil
+			if m.staleChanged {
+				m.staleChanged = false
+				return m, reloadProjectsCmd(m.statusFilter)
+			}
+			return m, nil
+
+		case "up", "k":
+			if m.staleCursor > 0 {
+				m.staleCursor--
+			}
+
+		case "down", "j":
+			if m.staleCursor < len(m.staleProjects)-1 {
+				m.staleCursor++
+			}
+
+		case " ":
+			if len(m.staleProjects) == 0 {
+				return m, nil
+			}
+			p := m.staleProjects[m.staleCursor]
+			if !p.Restorable() {
+				m.errorMessage = fmt.Sprintf("%s has no repository URL, so it couldn't be restored after archiving", p.Project.Name)
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.staleSelected[p.Project.ID] = !m.staleSelected[p.Project.ID]
+
+		case "a":
+			for _, p := range m.staleProjects {
+				if p.Restorable() {
+					m.staleSelected[p.Project.ID] = true
+				}
+			}
+
+		case "n":
+			m.staleSelected = make(map[uint]bool)
+
+		case "A":
+			if m.staleLoading {
+				return m, nil
+			}
+			if len(m.selectedStale()) == 0 {
+				m.errorMessage = "No projects selected"
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.staleConfirm = true
+		}
+	}
+
+	return m, nil
+}
+
+// selectedStale returns the selected stale projects in report order
+func (m model) selectedStale() []engine.StaleProject {
+	var selected []engine.StaleProject
+	for _, p := range m.staleProjects {
+		if m.staleSelected[p.Project.ID] {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// stalePageSize returns how many report rows fit on screen
+func (m model) stalePageSize() int {
+	return max(5, m.height-14)
+}
+
+// viewStale renders the stale project report
+func (m model) viewStale() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Stale Projects")
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	var total, selectedSize int64
+	for _, p := range m.staleProjects {
+		total += p.Size
+		if m.staleSelected[p.Project.ID] {
+			selectedSize += p.Size
+		}
+	}
+	selected := m.selectedStale()
+	s += lipgloss.NewStyle().Foreground(colorText).Render(fmt.Sprintf("Not opened and no commits in %d days", m.staleDays)) +
+		dimStyle.Render(fmt.Sprintf(" (%d projects, %s; %d selected, %s)",
+			len(m.staleProjects), engine.FormatSize(total), len(selected), engine.FormatSize(selectedSize))) + "\n\n"
+
+	switch {
+	case m.staleLoading && len(m.staleProjects) == 0:
+		s += dimStyle.Render("Checking projects...") + "\n"
+	case len(m.staleProjects) == 0:
+		s += dimStyle.Render("No stale projects") + "\n"
+	}
+
+	// Keep the cursor on screen
+	pageSize := m.stalePageSize()
+	start := max(0, m.staleCursor-pageSize+1)
+	end := min(len(m.staleProjects), start+pageSize)
+	now := time.Now()
+	for i, p := range m.staleProjects[start:end] {
+		index := start + i
+		cursor := "  "
+		if index == m.staleCursor {
+			cursor = "► "
+		}
+		checkbox := "[ ]"
+		if m.staleSelected[p.Project.ID] {
+			checkbox = "[✓]"
+		}
+
+		statusColor := colorSuccess
+		switch {
+		case !p.Restorable():
+			statusColor = colorDanger
+		case p.Dirty:
+			statusColor = colorWarning
+		}
+
+		style := lipgloss.NewStyle().Foreground(colorText)
+		if index == m.staleCursor {
+			style = style.Background(colorSelection).Foreground(colorSelectionText).Bold(true)
+		}
+		s += style.Render(fmt.Sprintf("%s%s %-24s", cursor, checkbox, p.Project.Name)) + " " +
+			dimStyle.Render(fmt.Sprintf("%-10s %9s ", relativeTime(p.LastActivity, now), engine.FormatSize(p.Size))) +
+			lipgloss.NewStyle().Foreground(statusColor).Render(p.Status()) + "\n"
+	}
+	if end < len(m.staleProjects) {
+		s += dimStyle.Render(fmt.Sprintf("… %d more", len(m.staleProjects)-end)) + "\n"
+	}
+
+	if m.staleConfirm {
+		s += "\n" + lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Bold(true).
+			Render(fmt.Sprintf("Archive %d projects and move their directories (%s) out of the root folder? y/n", len(selected), engine.FormatSize(selectedSize))) + "\n"
+	} else {
+		s += dimStyle.Render("\n↑↓=move  space=toggle  a=all  n=none  A=archive selected  esc=back")
+	}
+
+	if m.statusMessage != "" {
+		s += lipgloss.NewStyle().Foreground(colorSuccessDim).Render("\n✓ " + m.statusMessage)
+	}
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+
+	return docStyle.Render(s)
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+)
+
+// startupData is what the model needs from the database to start
+type startupData struct {
+	empty bool        // No projects at all, so setup starts
+	items []list.Item // Projects matching the saved status filter
+}
+
+// StartupMsg is sent when the database is open and the initial projects are loaded
+type StartupMsg struct {
+	data startupData
+	err  error
+}
+
+// loadStartupData loads the projects shown when DevBase starts
+func loadStartupData() (startupData, error) {
+	projects, err := db.GetProjects()
+	if err != nil {
+		return startupData{}, fmt.Errorf("failed to load projects: %w", err)
+	}
+	if len(projects) == 0 {
+		return startupData{empty: true}, nil
+	}
+
+	// Apply the status filter to the initial list
+	if statusFilter, _ := db.GetConfig("status_filter"); statusFilter != statusFilterAll {
+		projects, err = db.GetProjectsByStatus(statusFilter)
+		if err != nil {
+			return startupData{}, fmt.Errorf("failed to load projects: %w", err)
+		}
+	}
+	return startupData{items: projectsToItems(projects)}, nil
+}
+
+// loadingModel is shown while the database opens, so the terminal switches to DevBase
+// right away. It turns into the full model once the projects are loaded.
+type loadingModel struct {
+	ctx    context.Context
+	open   func() error
+	width  int
+	height int
+	err    error // Why startup failed
+}
+
+// NewLoadingModel returns a model that opens the database with open and loads the
+// projects in the background before showing the project list or setup wizard. When
+// that fails the program quits, and StartupError returns the error. Cancelling ctx
+// stops scans that are still running.
+func NewLoadingModel(ctx context.Context, open func() error) tea.Model {
+	return loadingModel{ctx: ctx, open: open}
+}
+
+// StartupError returns why the program quit before the projects were loaded, given the
+// final model of the program
+func StartupError(final tea.Model) error {
+	if m, ok := final.(loadingModel); ok {
+		return m.err
+	}
+	return nil
+}
+
+// Init opens the database in the background
+func (m loadingModel) Init() tea.Cmd {
+	open := m.open
+	return func() tea.Msg {
+		if err := open(); err != nil {
+			return StartupMsg{err: err}
+		}
+		data, err := loadStartupData()
+		return StartupMsg{data: data, err: err}
+	}
+}
+
+// Update waits for the startup data and hands over to the full model
+func (m loadingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer recoverCrash()
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case StartupMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		next := newModel(m.ctx, msg.data)
+		if m.width == 0 {
+			return next, next.Init()
+		}
+		sized, cmd := next.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return sized, tea.Batch(cmd, next.Init())
+	}
+	return m, nil
+}
+
+// View shows a placeholder until the projects are loaded
+func (m loadingModel) View() string {
+	return docStyle.Render("\n" + lipgloss.NewStyle().Foreground(colorDim).Render("Loading projects…"))
+}
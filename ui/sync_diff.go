@@ -0,0 +1,318 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// Sync directions reviewed on the diff screen
+const (
+	syncDirectionPush = "push" // Local projects replace the cloud backup (u)
+	syncDirectionLoad = "load" // Selected cloud projects are loaded locally (l)
+)
+
+// Sections of the diff screen, in display order
+const (
+	syncSectionAdded = iota
+	syncSectionRemoved
+	syncSectionChanged
+	syncSectionCount
+)
+
+// maxDiffDetails caps the entries listed under an expanded section
+const maxDiffDetails = 10
+
+// SyncPreviewMsg is sent when comparing local projects with the cloud backup completes
+type SyncPreviewMsg struct {
+	diff    engine.SyncDiff
+	damaged error                // Why the cloud backup failed its integrity check; the push replaces it
+	secrets []engine.SecretMatch // Possible secrets in the local projects
+	blocked bool                 // sync_secret_action blocks pushing them
+	err     error
+}
+
+// showSyncDiff switches to the diff screen for a sync in the given direction
+func (m model) showSyncDiff(direction string, diff engine.SyncDiff) model {
+	m.syncDirection = direction
+	m.syncDiff = diff
+	m.syncDamaged = nil
+	m.syncSecrets, m.syncBlocked = nil, false
+	m.syncDiffCursor = syncSectionAdded
+	m.syncDiffExpanded = [syncSectionCount]bool{}
+	m.screen = screenSyncDiff
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m
+}
+
+// updateSyncDiff handles updates for the sync diff screen
+func (m model) updateSyncDiff(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "n":
+		// Cancel and go back to where the sync started
+		if m.syncDirection == syncDirectionLoad {
+			m.screen = screenCloudSelect
+		} else {
+			m.screen = screenList
+		}
+		m.statusMessage = "Sync cancelled"
+		return m, nil
+
+	case "up", "k":
+		if m.syncDiffCursor > 0 {
+			m.syncDiffCursor--
+		}
+
+	case "down", "j":
+		if m.syncDiffCursor < syncSectionCount-1 {
+			m.syncDiffCursor++
+		}
+
+	case " ", "tab", "right", "left", "l", "h":
+		// Expand or collapse the section details
+		m.syncDiffExpanded[m.syncDiffCursor] = !m.syncDiffExpanded[m.syncDiffCursor]
+
+	case "enter", "y":
+		if m.syncDirection == syncDirectionPush && m.syncBlocked {
+			m.errorMessage = "Push blocked by possible secrets; remove them or set sync_secret_action to redact"
+			return m, nil
+		}
+		m.screen = screenList
+		if m.syncDirection == syncDirectionLoad {
+			selected := m.syncLoadIndices
+			cloudProjects := m.cloudProjects
+			m.cloudProjects = nil
+			m.statusMessage = "Loading selected projects..."
+			return m, m.loadSelectedProjectsCmd(selected, cloudProjects)
+		}
+		m.statusMessage = "Syncing projects to cloud..."
+		return m, m.syncToCloudCmd()
+	}
+
+	return m, nil
+}
+
+// viewSyncDiff renders the sync diff screen
+func (m model) viewSyncDiff() string {
+	title := "Review Sync: Local → Cloud"
+	labels := [syncSectionCount]string{"Added to cloud", "Removed from cloud", "Changed in cloud"}
+	if m.syncDirection == syncDirectionLoad {
+		title = "Review Load: Cloud → Local"
+		labels = [syncSectionCount]string{"Added locally (as archived)", "Removed locally", "Changed locally"}
+	}
+
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render(title)
+
+	s := "\n" + titleBox + "\n\n"
+
+	d := m.syncDiff
+	s += lipgloss.NewStyle().
+		Foreground(colorText).
+		Render(fmt.Sprintf("%d added, %d removed, %d changed, %d unchanged",
+			len(d.Added), len(d.Removed), len(d.Changed), d.Unchanged)) + "\n\n"
+
+	markers := [syncSectionCount]string{"+", "-", "~"}
+	colors := [syncSectionCount]lipgloss.TerminalColor{colorSuccess, colorDanger, colorWarning}
+	counts := [syncSectionCount]int{len(d.Added), len(d.Removed), len(d.Changed)}
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	for section := 0; section < syncSectionCount; section++ {
+		cursor := "  "
+		if section == m.syncDiffCursor {
+			cursor = "► "
+		}
+		arrow := "▸"
+		if m.syncDiffExpanded[section] {
+			arrow = "▾"
+		}
+		line := fmt.Sprintf("%s%s [%s] %s (%d)", cursor, arrow, markers[section], labels[section], counts[section])
+		style := lipgloss.NewStyle().Foreground(colors[section])
+		if section == m.syncDiffCursor {
+			style = style.Background(colorSelection).Bold(true)
+		}
+		s += style.Render(line) + "\n"
+
+		if !m.syncDiffExpanded[section] {
+			continue
+		}
+
+		var details []string
+		switch section {
+		case syncSectionAdded:
+			details = projectDiffLines(d.Added)
+		case syncSectionRemoved:
+			details = projectDiffLines(d.Removed)
+		case syncSectionChanged:
+			for _, change := range d.Changed {
+				details = append(details, fmt.Sprintf("%s: %s", change.Name, strings.Join(change.Fields, ", ")))
+			}
+		}
+		if len(details) == 0 {
+			details = []string{"nothing"}
+		}
+		for i, detail := range details {
+			if i == maxDiffDetails {
+				s += dimStyle.Render(fmt.Sprintf("      … and %d more", len(details)-maxDiffDetails)) + "\n"
+				break
+			}
+			s += dimStyle.Render("      "+detail) + "\n"
+		}
+	}
+
+	if m.syncDirection == syncDirectionPush && m.syncDamaged != nil {
+		s += "\n" + lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Render(fmt.Sprintf("⚠ The current cloud backup can't be read (%v); pushing replaces it", m.syncDamaged)) + "\n"
+	}
+	if m.syncDirection == syncDirectionPush && len(d.Removed) > 0 {
+		s += "\n" + lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Render("⚠ Projects only in the cloud backup will be dropped from it") + "\n"
+	}
+
+	if m.syncDirection == syncDirectionPush && len(m.syncSecrets) > 0 {
+		s += "\n" + m.viewSyncSecrets()
+	}
+
+	s += dimStyle.Render("\n↑↓=section  space=expand  enter=apply  esc=cancel")
+
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+
+	return docStyle.Render(s)
+}
+
+// viewSyncSecrets lists the possible secrets of a push and whether they block it or are redacted
+func (m model) viewSyncSecrets() string {
+	warnStyle := lipgloss.NewStyle().Foreground(colorWarning)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	s := warnStyle.Render(fmt.Sprintf("⚠ %d possible secrets in the projects to push:", len(m.syncSecrets))) + "\n"
+	for i, match := range m.syncSecrets {
+		if i == maxDiffDetails {
+			s += dimStyle.Render(fmt.Sprintf("      … and %d more", len(m.syncSecrets)-maxDiffDetails)) + "\n"
+			break
+		}
+		s += dimStyle.Render(fmt.Sprintf("      %s · %s · %s %s", match.Project, match.Field, match.Rule, match.Excerpt)) + "\n"
+	}
+	if m.syncBlocked {
+		return s + warnStyle.Render("  The push is blocked; remove them, or set sync_secret_action to redact") + "\n"
+	}
+	return s + dimStyle.Render("  They are replaced with [redacted] in the cloud backup") + "\n"
+}
+
+// projectDiffLines formats projects as "name (path)" detail lines
+func projectDiffLines(projects []models.Project) []string {
+	lines := make([]string, len(projects))
+	for i, p := range projects {
+		lines[i] = fmt.Sprintf("%s (%s)", p.Name, p.Path)
+	}
+	return lines
+}
+
+// loadDiff compares the selected cloud projects with the matching local projects.
+// Loading never removes local projects, so only additions and changes are reported.
+func loadDiff(selectedIndices []int, cloudProjects []models.Project) engine.SyncDiff {
+	var incoming, existing []models.Project
+	for _, idx := range selectedIndices {
+		if idx < 0 || idx >= len(cloudProjects) {
+			continue
+		}
+		// Loaded projects are marked as archived and keep the machine of a project already
+		// stored, as in engine.StoreCloudProjects
+		project := cloudProjects[idx]
+		project.Status = "archived"
+		if local, err := db.GetProjectByPath(project.Path); err == nil {
+			if local.Machine != "" {
+				project.Machine = local.Machine
+			}
+			existing = append(existing, *local)
+		}
+		incoming = append(incoming, project)
+	}
+	return engine.DiffProjects(incoming, existing)
+}
+
+// previewPushCmd creates a command that compares local projects with the cloud backup
+func previewPushCmd() tea.Cmd {
+	return func() tea.Msg {
+		// Get GitHub token from config
+		token, err := db.GetConfig("github_token")
+		if err != nil || token == "" {
+			return SyncPreviewMsg{err: fmt.Errorf("GitHub authentication required. Please authenticate with OAuth (press 't')")}
+		}
+
+		// Get active root folder ID
+		var rootFolderID uint
+		activeRoot, err := db.GetActiveRootFolder()
+		if err == nil && activeRoot != nil {
+			rootFolderID = activeRoot.ID
+		}
+
+		// Create gist client with root folder ID (loads existing gist ID automatically)
+		client, err := engine.NewGistClient(token, rootFolderID)
+		if err != nil {
+			return SyncPreviewMsg{err: fmt.Errorf("failed to create gist client: %w", err)}
+		}
+
+		// Validate token
+		if err := client.ValidateToken(); err != nil {
+			return SyncPreviewMsg{err: fmt.Errorf("invalid GitHub token. Please reconfigure your token (press 't')")}
+		}
+
+		// A first sync compares against an empty backup, and so does one replacing a
+		// damaged backup
+		var damaged error
+		cloudProjects, err := client.LoadFromGist()
+		if errors.Is(err, engine.ErrBackupIntegrity) {
+			damaged = err
+		} else if err != nil && !errors.Is(err, engine.ErrNoCloudBackup) {
+			return SyncPreviewMsg{err: err}
+		}
+
+		// Get all projects (filtered by active root folder)
+		localProjects, err := db.GetProjects()
+		if err != nil {
+			return SyncPreviewMsg{err: fmt.Errorf("failed to get projects: %w", err)}
+		}
+
+		// Compare what the push would upload, after redaction
+		payload, secrets, err := engine.PrepareSyncPayload(localProjects)
+		blocked := errors.Is(err, engine.ErrSecretsFound)
+		if blocked {
+			payload = localProjects
+		} else if err != nil {
+			return SyncPreviewMsg{err: err}
+		}
+
+		return SyncPreviewMsg{
+			diff:    engine.DiffProjects(payload, cloudProjects),
+			damaged: damaged,
+			secrets: secrets,
+			blocked: blocked,
+		}
+	}
+}
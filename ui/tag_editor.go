@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+)
+
+// maxTagSuggestions caps the autocomplete suggestions shown under the tag input
+const maxTagSuggestions = 5
+
+// openTagEditor starts editing the tags of a project
+func (m model) openTagEditor(item projectItem) (tea.Model, tea.Cmd) {
+	allTags, err := db.GetAllTags()
+	if err != nil {
+		m.errorMessage = err.Error()
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Add a tag..."
+	input.Focus()
+	input.CharLimit = 50
+	input.Width = 40
+
+	itemCopy := item
+	m.tagProject = &itemCopy
+	m.tagInput = input
+	m.allTags = allTags
+	m.tagSuggestion = 0
+	m.editingTags = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, textinput.Blink
+}
+
+// tagSuggestions returns existing tags matching the typed prefix that the project doesn't have yet
+func (m model) tagSuggestions() []string {
+	prefix := db.NormalizeTag(m.tagInput.Value())
+	var suggestions []string
+	for _, tag := range m.allTags {
+		if strings.HasPrefix(tag, prefix) && !slices.Contains(m.tagProject.project.Tags, tag) {
+			suggestions = append(suggestions, tag)
+			if len(suggestions) == maxTagSuggestions {
+				break
+			}
+		}
+	}
+	return suggestions
+}
+
+// updateTagEditor handles key presses while editing tags
+func (m model) updateTagEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	suggestions := m.tagSuggestions()
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.editingTags = false
+		m.tagProject = nil
+		return m, nil
+
+	case "up", "ctrl+k":
+		if m.tagSuggestion > 0 {
+			m.tagSuggestion--
+		}
+		return m, nil
+
+	case "down", "ctrl+j":
+		if m.tagSuggestion < len(suggestions)-1 {
+			m.tagSuggestion++
+		}
+		return m, nil
+
+	case "tab":
+		// Complete the input with the highlighted suggestion
+		if len(suggestions) > 0 {
+			m.tagInput.SetValue(suggestions[min(m.tagSuggestion, len(suggestions)-1)])
+			m.tagInput.CursorEnd()
+			m.tagSuggestion = 0
+		}
+		return m, nil
+
+	case "enter":
+		tag := db.NormalizeTag(m.tagInput.Value())
+		if tag == "" {
+			return m, nil
+		}
+		if err := db.AddProjectTag(m.tagProject.project.ID, tag); err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+		if !slices.Contains(m.allTags, tag) {
+			m.allTags = append(m.allTags, tag)
+			slices.Sort(m.allTags)
+		}
+		m.tagInput.SetValue("")
+		m.tagSuggestion = 0
+		m.statusMessage = fmt.Sprintf("Tagged %s with %q", m.tagProject.project.Name, tag)
+		return m, m.refreshTaggedProject()
+
+	case "backspace":
+		// Backspace on an empty input removes the last tag
+		tags := m.tagProject.project.Tags
+		if m.tagInput.Value() == "" && len(tags) > 0 {
+			tag := tags[len(tags)-1]
+			if err := db.RemoveProjectTag(m.tagProject.project.ID, tag); err != nil {
+				m.errorMessage = err.Error()
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("Removed tag %q from %s", tag, m.tagProject.project.Name)
+			return m, m.refreshTaggedProject()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	m.tagSuggestion = 0
+	return m, cmd
+}
+
+// refreshTaggedProject reloads the edited project's tags and updates its list row in place
+func (m *model) refreshTaggedProject() tea.Cmd {
+	project, err := db.GetProjectByID(m.tagProject.project.ID)
+	if err != nil {
+		m.errorMessage = err.Error()
+		return nil
+	}
+	m.tagProject.project.Tags = project.Tags
+	m.errorMessage = ""
+
+	for i, item := range m.list.Items() {
+		if pi, ok := item.(projectItem); ok && pi.project.ID == project.ID {
+			pi.project.Tags = project.Tags
+			return m.list.SetItem(i, pi)
+		}
+	}
+	return nil
+}
+
+// viewTagEditor renders the tag editor with the current tags and suggestions
+func (m model) viewTagEditor() string {
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("🏷 TAGS: "+m.tagProject.project.Name) + "\n\n"
+
+	chipStyle := lipgloss.NewStyle().
+		Background(colorSelection).
+		Foreground(colorSelectionText).
+		Padding(0, 1)
+	if len(m.tagProject.project.Tags) == 0 {
+		s += lipgloss.NewStyle().Foreground(colorDim).Render("No tags yet")
+	} else {
+		chips := make([]string, len(m.tagProject.project.Tags))
+		for i, tag := range m.tagProject.project.Tags {
+			chips[i] = chipStyle.Render(tag)
+		}
+		s += strings.Join(chips, " ")
+	}
+	s += "\n\n" + m.tagInput.View() + "\n"
+
+	for i, suggestion := range m.tagSuggestions() {
+		if i == m.tagSuggestion {
+			s += lipgloss.NewStyle().
+				Foreground(colorAccent).
+				Render("  ► "+suggestion) + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorDim).
+				Render("    "+suggestion) + "\n"
+		}
+	}
+
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render("\nenter=add  tab=complete  backspace=remove last  esc=done")
+	return s
+}
@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+	"devbase/models"
+)
+
+// Sources of the picker entries that aren't detected project tasks
+const (
+	taskSourceDefault = "default" // The heuristic dev command, launched as before
+	taskSourceCustom  = "custom"  // Prompts for a command to run
+)
+
+// openTaskPicker shows the run-task picker for a project with the dev command preselected
+func (m model) openTaskPicker(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "run locally")
+		return m, nil
+	}
+
+	var tasks []engine.Task
+
+	// The heuristic dev command stays the default choice
+	path := hostPath(item.project.Path)
+	if cmd, err := detectAndCreateRunCommand(path); err == nil {
+		tasks = append(tasks, engine.Task{Name: "dev", Command: cmd.Args[len(cmd.Args)-1], Source: taskSourceDefault})
+	}
+	tasks = append(tasks, engine.DetectTasks(path)...)
+	tasks = append(tasks, engine.Task{Name: "Custom command...", Source: taskSourceCustom})
+
+	m.taskEnvFound, m.taskEnv = projectEnvSources(item.project)
+	m.taskOutput = loadRunOutput()
+
+	itemCopy := item
+	m.taskProject = &itemCopy
+	m.taskChoices = tasks
+	m.taskCursor = 0
+	m.taskCustom = false
+	m.showTaskPicker = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, nil
+}
+
+// updateTaskPicker handles key presses while the run-task picker is open
+func (m model) updateTaskPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Custom command input
+	if m.taskCustom {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.taskCustom = false
+			return m, nil
+		case "enter":
+			command := strings.TrimSpace(m.executeCommandInput.Value())
+			if command == "" {
+				m.errorMessage = "Please enter a valid command"
+				return m, nil
+			}
+			project := m.taskProject.project
+			if err := m.alreadyRunning(project, command); err != "" {
+				m.errorMessage = err
+				return m, nil
+			}
+			env, output := m.taskEnv, m.taskOutput
+			m.closeTaskPicker()
+			switch output {
+			case runOutputLog:
+				return m, capturedRunCmd(project, command, env)
+			case runOutputPane:
+				return m.startStreamedRun(project, command, env)
+			}
+			m.statusMessage = "Executing command" + envSuffix(env) + "..."
+			return m, executeCommandCmd(project.Path, command, env)
+		}
+
+		var cmd tea.Cmd
+		m.executeCommandInput, cmd = m.executeCommandInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.closeTaskPicker()
+		return m, nil
+
+	case "up", "k":
+		if m.taskCursor > 0 {
+			m.taskCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.taskCursor < len(m.taskChoices)-1 {
+			m.taskCursor++
+		}
+		return m, nil
+
+	case "enter":
+		task := m.taskChoices[m.taskCursor]
+		project := m.taskProject.project
+		path := project.Path
+
+		if task.Source == taskSourceCustom {
+			cmdInput := textinput.New()
+			cmdInput.Placeholder = "e.g., npm test, go build, python script.py"
+			cmdInput.Focus()
+			cmdInput.CharLimit = 500
+			cmdInput.Width = 60
+			m.executeCommandInput = cmdInput
+			m.taskCustom = true
+			m.errorMessage = ""
+			return m, textinput.Blink
+		}
+
+		if err := m.alreadyRunning(project, task.Command); err != "" {
+			m.errorMessage = err
+			return m, nil
+		}
+
+		env := m.taskEnv
+		// Captured and streamed runs start every task the same way, the dev command included
+		switch m.taskOutput {
+		case runOutputLog:
+			m.closeTaskPicker()
+			return m, capturedRunCmd(project, task.Command, env)
+		case runOutputPane:
+			m.closeTaskPicker()
+			return m.startStreamedRun(project, task.Command, env)
+		}
+
+		if task.Source == taskSourceDefault {
+			m.closeTaskPicker()
+			m.statusMessage = "Opening new terminal window to run project in development mode" + envSuffix(env) + "..."
+			return m, runProjectCmd(path, env)
+		}
+
+		m.closeTaskPicker()
+		m.statusMessage = fmt.Sprintf("Running %s%s...", task.Command, envSuffix(env))
+		return m, executeCommandCmd(path, task.Command, env)
+
+	case "e":
+		// Toggle loading the project's environment for this run
+		if m.taskEnv != "" {
+			m.taskEnv = ""
+		} else {
+			m.taskEnv = m.taskEnvFound
+		}
+		return m, nil
+
+	case "c":
+		// Switch this run's output between a terminal window, a run log and the output pane
+		m.taskOutput = nextRunOutput(m.taskOutput)
+		return m, nil
+
+	case "s":
+		return m.stopDevRuns(false)
+
+	case "r":
+		return m.stopDevRuns(true)
+	}
+
+	return m, nil
+}
+
+// alreadyRunning explains why a command can't be started when the project runs it already,
+// which would have two servers fighting over the same port. It returns "" otherwise.
+func (m model) alreadyRunning(project models.Project, command string) string {
+	run, ok := m.runningTask(project.ID, command)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s is already running (pid %d); press s to stop it or r to restart it", run.Task, run.PID)
+}
+
+// closeTaskPicker hides the run-task picker and clears its state
+func (m *model) closeTaskPicker() {
+	m.showTaskPicker = false
+	m.taskCustom = false
+	m.taskProject = nil
+	m.taskChoices = nil
+	m.taskEnvFound = ""
+	m.taskEnv = ""
+	m.taskOutput = ""
+	m.errorMessage = ""
+}
+
+// viewTaskPicker renders the run-task picker
+func (m model) viewTaskPicker() string {
+	s := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Render("▶ RUN TASK") + "\n\n"
+
+	if m.taskProject != nil {
+		s += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(m.taskProject.project.Name) + "\n"
+		s += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(envStatus(m.taskEnvFound, m.taskEnv)) + "\n"
+		s += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(outputStatus(m.taskOutput)) + "\n"
+		for _, run := range m.devRuns[m.taskProject.project.ID] {
+			s += lipgloss.NewStyle().
+				Foreground(colorSuccess).
+				Render("● Running: "+describeDevRun(run)) + "\n"
+		}
+		s += "\n"
+	}
+
+	if m.taskCustom {
+		s += lipgloss.NewStyle().
+			Foreground(colorText).
+			Render("Enter command to run in the project directory:") + "\n" +
+			m.executeCommandInput.View() + "\n" +
+			lipgloss.NewStyle().
+				Foreground(colorDim).
+				Render("\nPress Enter to run | ESC to go back")
+		return s
+	}
+
+	// Keep the cursor visible within a fixed-height window
+	const maxVisible = 12
+	start := 0
+	if m.taskCursor >= maxVisible {
+		start = m.taskCursor - maxVisible + 1
+	}
+	for i := start; i < len(m.taskChoices) && i < start+maxVisible; i++ {
+		task := m.taskChoices[i]
+		label := task.Name
+		var hint string
+		switch task.Source {
+		case taskSourceDefault:
+			label += " [default]"
+			hint = " " + task.Command
+		case taskSourceCustom:
+			// Nothing to show until the command is entered
+		default:
+			hint = fmt.Sprintf(" %s (%s)", task.Command, task.Source)
+		}
+		if m.taskProject != nil && task.Source != taskSourceCustom {
+			if _, running := m.runningTask(m.taskProject.project.ID, task.Command); running {
+				label += " [running]"
+			}
+		}
+		hint = lipgloss.NewStyle().Foreground(colorDim).Render(hint)
+
+		if i == m.taskCursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+label) + hint + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+label) + hint + "\n"
+		}
+	}
+
+	help := "\n↑↓=navigate  enter=run  e=toggle env  c=switch output"
+	if m.taskProject != nil && len(m.devRuns[m.taskProject.project.ID]) > 0 {
+		help += "  s=stop  r=restart"
+	}
+	s += lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render(help + "  esc=cancel")
+	return s
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+)
+
+// Theme names accepted by the "theme" config key and the DEVBASE_THEME env var
+const (
+	themeDefault      = "default"
+	themeHighContrast = "high-contrast"
+)
+
+// Colors used across the UI. They are set by applyTheme; every state shown with one of
+// them also has a text marker (►, ✓, [Archived], …) so nothing relies on color alone.
+var (
+	colorAccent        lipgloss.TerminalColor
+	colorText          lipgloss.TerminalColor
+	colorDim           lipgloss.TerminalColor
+	colorNoteText      lipgloss.TerminalColor
+	colorBorder        lipgloss.TerminalColor
+	colorSelection     lipgloss.TerminalColor
+	colorSelectionText lipgloss.TerminalColor
+	colorInverse       lipgloss.TerminalColor
+	colorSuccess       lipgloss.TerminalColor
+	colorSuccessDim    lipgloss.TerminalColor
+	colorWarning       lipgloss.TerminalColor
+	colorCaution       lipgloss.TerminalColor
+	colorDanger        lipgloss.TerminalColor
+)
+
+// noColor is set when colors are disabled with NO_COLOR (https://no-color.org)
+var noColor bool
+
+// highContrast is set when the high-contrast theme is active
+var highContrast bool
+
+func init() {
+	applyTheme(themeDefault)
+}
+
+// loadTheme applies the theme from the "theme" config key, which the DEVBASE_THEME env
+// var overrides. NO_COLOR disables colors regardless of the theme.
+func loadTheme() {
+	name, _ := db.GetConfig("theme")
+	if env := os.Getenv("DEVBASE_THEME"); env != "" {
+		name = env
+	}
+	applyTheme(name)
+}
+
+// applyTheme sets the UI colors for the named theme. The default theme adapts to light and
+// dark terminal backgrounds; unknown names fall back to it.
+func applyTheme(name string) {
+	noColor = os.Getenv("NO_COLOR") != ""
+	highContrast = name == themeHighContrast && !noColor
+
+	switch {
+	case noColor:
+		none := lipgloss.NoColor{}
+		colorAccent, colorText, colorDim, colorNoteText = none, none, none, none
+		colorBorder, colorSelection, colorSelectionText, colorInverse = none, none, none, none
+		colorSuccess, colorSuccessDim, colorWarning, colorCaution, colorDanger = none, none, none, none, none
+
+	case highContrast:
+		colorAccent = lipgloss.AdaptiveColor{Light: "#0000AA", Dark: "#00FFFF"}
+		colorText = lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"}
+		colorDim = colorText
+		colorNoteText = colorText
+		colorBorder = colorText
+		colorSelection = colorText
+		colorSelectionText = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"}
+		colorInverse = colorSelectionText
+		colorSuccess = lipgloss.AdaptiveColor{Light: "#005F00", Dark: "#00FF00"}
+		colorSuccessDim = colorSuccess
+		colorWarning = lipgloss.AdaptiveColor{Light: "#870000", Dark: "#FFFF00"}
+		colorCaution = colorWarning
+		colorDanger = lipgloss.AdaptiveColor{Light: "#AF0000", Dark: "#FF5F5F"}
+
+	default:
+		colorAccent = lipgloss.AdaptiveColor{Light: "#007A87", Dark: "#00FFFF"}
+		colorText = lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#FFFFFF"}
+		colorDim = lipgloss.AdaptiveColor{Light: "#666666", Dark: "#888888"}
+		colorNoteText = lipgloss.AdaptiveColor{Light: "#333333", Dark: "#DDDDDD"}
+		colorBorder = lipgloss.AdaptiveColor{Light: "#BBBBBB", Dark: "#444444"}
+		colorSelection = lipgloss.AdaptiveColor{Light: "#DDDDDD", Dark: "#444444"}
+		colorSelectionText = colorText
+		colorInverse = lipgloss.Color("#000000")
+		colorSuccess = lipgloss.AdaptiveColor{Light: "#008700", Dark: "#00FF00"}
+		colorSuccessDim = lipgloss.AdaptiveColor{Light: "#006400", Dark: "#00AA00"}
+		colorWarning = lipgloss.AdaptiveColor{Light: "#AF5F00", Dark: "#FFAA00"}
+		colorCaution = lipgloss.AdaptiveColor{Light: "#878700", Dark: "#FFFF00"}
+		colorDanger = lipgloss.AdaptiveColor{Light: "#D70000", Dark: "#FF0000"}
+	}
+
+	// Package-level styles are built once, so refresh them with the new colors
+	errorStyle = errorStyle.Foreground(colorDanger)
+	titleStyle = titleStyle.Foreground(colorAccent)
+	subtitleStyle = subtitleStyle.Foreground(colorDim)
+}
+
+// paletteColor returns a decorative color (language badges, activity kinds) for the active
+// theme. High contrast replaces it with the text color and NO_COLOR drops it.
+func paletteColor(hex string) lipgloss.TerminalColor {
+	switch {
+	case noColor:
+		return lipgloss.NoColor{}
+	case highContrast:
+		return colorText
+	}
+	return lipgloss.Color(hex)
+}
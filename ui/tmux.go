@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// TmuxSessionMsg is sent when the tmux session for a project has been created or found
+type TmuxSessionMsg struct {
+	project models.Project
+	session string
+	created bool
+	err     error
+}
+
+// openTmux opens or switches to the tmux session of the selected project
+func (m model) openTmux(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "opened in a local tmux session")
+		return m, nil
+	}
+	if item.missing || item.project.Status == "archived" {
+		m.errorMessage = "Project directory is not available"
+		return m, nil
+	}
+	if !engine.TmuxAvailable() {
+		m.errorMessage = "tmux is not installed or not on PATH"
+		return m, nil
+	}
+
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, ensureTmuxSessionCmd(item.project)
+}
+
+// ensureTmuxSessionCmd creates a command that creates the project's tmux session if needed,
+// using the window layout from the "tmux_layout" config key
+func ensureTmuxSessionCmd(project models.Project) tea.Cmd {
+	return func() tea.Msg {
+		layout, _ := db.GetConfig("tmux_layout")
+		session := engine.TmuxSessionName(project.Name)
+		created, err := engine.EnsureTmuxSession(session, hostPath(project.Path), engine.ParseTmuxLayout(layout))
+		return TmuxSessionMsg{project: project, session: session, created: created, err: err}
+	}
+}
+
+// tmuxSessionReady moves the user to the session once it exists. Outside tmux the attach
+// takes over the terminal until the user detaches.
+func (m model) tmuxSessionReady(msg TmuxSessionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.errorMessage = msg.err.Error()
+		return m, nil
+	}
+
+	if msg.created {
+		m.statusMessage = fmt.Sprintf("Created tmux session %s", msg.session)
+	} else {
+		m.statusMessage = fmt.Sprintf("Switched to tmux session %s", msg.session)
+	}
+
+	return m, tea.Batch(recordOpenCmd(msg.project), tea.ExecProcess(engine.TmuxAttachCommand(msg.session), func(err error) tea.Msg {
+		if err == nil {
+			_ = db.LogActivity(models.ActivityOpen, msg.project.ID, "tmux")
+		}
+		return OpenProjectMsg{projectID: msg.project.ID, editor: "tmux", err: err}
+	}))
+}
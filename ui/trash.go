@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/engine"
+)
+
+// TrashMsg is sent when the trash and its purge schedule have been loaded
+type TrashMsg struct {
+	purges []engine.TrashPurge
+	err    error
+}
+
+// TrashPurgedMsg is sent when checkouts have been purged from the trash
+type TrashPurgedMsg struct {
+	count int
+	size  int64
+	err   error
+}
+
+// openTrash shows the archived checkouts in the trash with when each is purged
+func (m model) openTrash() (tea.Model, tea.Cmd) {
+	m.trashPurges = nil
+	m.trashCursor = 0
+	m.trashLoading = true
+	m.trashConfirm = false
+	m.trashRetention = engine.TrashRetentionConfig()
+	m.screen = screenTrash
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, trashCmd()
+}
+
+// trashCmd creates a command that loads the purge schedule of the trash
+func trashCmd() tea.Cmd {
+	return func() tea.Msg {
+		purges, err := engine.TrashReport()
+		return TrashMsg{purges: purges, err: err}
+	}
+}
+
+// purgeTrashEntryCmd creates a command that purges one checkout right away
+func purgeTrashEntryCmd(purge engine.TrashPurge) tea.Cmd {
+	return func() tea.Msg {
+		if err := engine.PurgeTrashEntry(purge.Entry.ID); err != nil {
+			return TrashPurgedMsg{err: err}
+		}
+		return TrashPurgedMsg{count: 1, size: purge.Entry.Size}
+	}
+}
+
+// purgeDueTrashCmd creates a command that runs the cleanup without waiting for the
+// background one
+func purgeDueTrashCmd() tea.Cmd {
+	return func() tea.Msg {
+		purged, err := engine.PurgeTrash()
+		msg := TrashPurgedMsg{count: len(purged), err: err}
+		for _, purge := range purged {
+			msg.size += purge.Entry.Size
+		}
+		return msg
+	}
+}
+
+// updateTrash handles updates for the trash screen
+func (m model) updateTrash(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TrashMsg:
+		m.trashLoading = false
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			return m, nil
+		}
+		m.trashPurges = msg.purges
+		m.trashCursor = min(m.trashCursor, max(0, len(msg.purges)-1))
+		return m, nil
+
+	case TrashPurgedMsg:
+		if msg.err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to purge: %v", msg.err)
+		}
+		m.statusMessage = fmt.Sprintf("Purged %d archived projects, freed %s", msg.count, engine.FormatSize(msg.size))
+		m.trashLoading = true
+		return m, trashCmd()
+
+	case tea.KeyMsg:
+		if m.trashConfirm {
+			switch msg.String() {
+			case "y", "enter":
+				m.trashConfirm = false
+				m.trashLoading = true
+				return m, purgeTrashEntryCmd(m.trashPurges[m.trashCursor])
+			case "ctrl+c":
+				return m, tea.Quit
+			default:
+				m.trashConfirm = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q":
+			m.screen = screenList
+			m.trashPurges = nil
+			return m, nil
+
+		case "up", "k":
+			if m.trashCursor > 0 {
+				m.trashCursor--
+			}
+
+		case "down", "j":
+			if m.trashCursor < len(m.trashPurges)-1 {
+				m.trashCursor++
+			}
+
+		case "x":
+			if m.trashLoading || len(m.trashPurges) == 0 {
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.trashConfirm = true
+
+		case "P":
+			if m.trashLoading {
+				return m, nil
+			}
+			m.errorMessage = ""
+			m.trashLoading = true
+			return m, purgeDueTrashCmd()
+		}
+	}
+	return m, nil
+}
+
+// trashPageSize returns how many trash rows fit on screen
+func (m model) trashPageSize() int {
+	return max(5, m.height-14)
+}
+
+// purgeTime formats when a checkout is purged, e.g. "in 27d"
+func purgeTime(purge engine.TrashPurge, now time.Time) string {
+	d := purge.At.Sub(now)
+	switch {
+	case purge.Due(now):
+		return "on the next cleanup"
+	case d < time.Hour:
+		return fmt.Sprintf("in %dm", int(d.Minutes())+1)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("in %dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("in %dd", int(d.Hours()/24))
+	}
+}
+
+// viewTrash renders the checkouts in the trash, next purge first
+func (m model) viewTrash() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Archive Trash")
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	var total int64
+	for _, purge := range m.trashPurges {
+		total += purge.Entry.Size
+	}
+	retention := fmt.Sprintf("keeping archived checkouts for %d days", m.trashRetention.Days)
+	if m.trashRetention.Days == 0 {
+		retention = "archiving deletes checkouts (archive_trash_days is 0)"
+	}
+	s += lipgloss.NewStyle().Foreground(colorText).Render(fmt.Sprintf("%d archived projects, %s", len(m.trashPurges), engine.FormatSize(total))) +
+		dimStyle.Render("  "+retention) + "\n\n"
+
+	switch {
+	case m.trashLoading && len(m.trashPurges) == 0:
+		s += dimStyle.Render("Loading trash...") + "\n"
+	case len(m.trashPurges) == 0:
+		s += dimStyle.Render("The trash is empty") + "\n"
+	}
+
+	// Keep the cursor on screen
+	now := time.Now()
+	pageSize := m.trashPageSize()
+	start := max(0, m.trashCursor-pageSize+1)
+	end := min(len(m.trashPurges), start+pageSize)
+	for i, purge := range m.trashPurges[start:end] {
+		index := start + i
+		cursor := "  "
+		if index == m.trashCursor {
+			cursor = "► "
+		}
+		style := lipgloss.NewStyle().Foreground(colorText)
+		if purge.Due(now) {
+			style = style.Foreground(colorWarning)
+		}
+		if index == m.trashCursor {
+			style = style.Background(colorSelection).Foreground(colorSelectionText).Bold(true)
+		}
+		s += style.Render(fmt.Sprintf("%s%-24s %9s  archived %-10s purged %s", cursor, purge.Entry.Name,
+			engine.FormatSize(purge.Entry.Size), relativeTime(purge.Entry.CreatedAt, now), purgeTime(purge, now))) + " " +
+			dimStyle.Render(purge.Reason) + "\n"
+	}
+	if end < len(m.trashPurges) {
+		s += dimStyle.Render(fmt.Sprintf("… %d more", len(m.trashPurges)-end)) + "\n"
+	}
+
+	if m.trashConfirm {
+		purge := m.trashPurges[m.trashCursor]
+		s += "\n" + lipgloss.NewStyle().
+			Foreground(colorWarning).
+			Bold(true).
+			Render(fmt.Sprintf("Delete the archived checkout of %s (%s) now? It can only be cloned again afterwards. y/n", purge.Entry.Name, engine.FormatSize(purge.Entry.Size))) + "\n"
+	} else {
+		s += dimStyle.Render("\n↑↓=move  x=purge now  P=run cleanup  esc=back  (r on an archived project restores it from here)")
+	}
+
+	if m.statusMessage != "" {
+		s += lipgloss.NewStyle().Foreground(colorSuccessDim).Render("\n✓ " + m.statusMessage)
+	}
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+
+	return docStyle.Render(s)
+}
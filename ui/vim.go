@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+)
+
+// keymapVim is the "keymap" config value that enables vim-style keybindings
+const keymapVim = "vim"
+
+// vimCommands maps ":" command-line commands to the list keybinding they run
+var vimCommands = map[string]tea.KeyMsg{
+	"q":         keyRune('q'),
+	"quit":      keyRune('q'),
+	"open":      {Type: tea.KeyEnter},
+	"e":         keyRune('e'),
+	"edit":      keyRune('e'),
+	"browser":   keyRune('o'),
+	"gitclient": keyRune('G'),
+	"run":       keyRune('x'),
+	"tmux":      keyRune('a'),
+	"container": keyRune('C'),
+	"scan":      keyRune('s'),
+	"clone":     keyRune('g'),
+	"init":      keyRune('i'),
+	"starred":   keyRune('S'),
+	"org":       keyRune('O'),
+	"archive":   keyRune('d'),
+	"restore":   keyRune('r'),
+	"restoreto": keyRune('A'),
+	"retry":     keyRune('E'),
+	"folders":   keyRune('f'),
+	"sync":      keyRune('u'),
+	"load":      keyRune('l'),
+	"github":    keyRune('t'),
+	"view":      keyRune('v'),
+	"mark":      keyRune('m'),
+	"pin":       keyRune('P'),
+	"lock":      keyRune('U'),
+	"sessions":  keyRune('w'),
+	"tags":      keyRune('T'),
+	"notes":     keyRune('N'),
+	"history":   keyRune('H'),
+	"logs":      keyRune('L'),
+	"stale":     keyRune('Z'),
+	"reclaim":   keyRune('R'),
+	"runs":      keyRune('X'),
+	"output":    keyRune('F'),
+	"jobs":      keyRune('J'),
+	"report":    keyRune('Y'),
+	"worktrees": keyRune('K'),
+	"ref":       keyRune('B'),
+	"icon":      keyRune('I'),
+	"details":   keyRune('D'),
+	"palette":   {Type: tea.KeyCtrlP},
+}
+
+// loadVimMode reports whether vim-style keybindings are enabled in config
+func loadVimMode() bool {
+	keymap, _ := db.GetConfig("keymap")
+	return keymap == keymapVim
+}
+
+// toggleVimMode switches between the default and vim-style keybindings and saves the choice
+func (m model) toggleVimMode() (tea.Model, tea.Cmd) {
+	m.vimMode = !m.vimMode
+	m.vimPending = ""
+	keymap, status := "default", "Default keybindings enabled"
+	if m.vimMode {
+		keymap, status = keymapVim, "Vim keybindings enabled (hjkl, gg/G, dd, :, V to switch back)"
+	}
+	if err := db.SetConfig("keymap", keymap); err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to save keymap: %v", err)
+		return m, nil
+	}
+	m.errorMessage = ""
+	m.statusMessage = status
+	return m, nil
+}
+
+// runListKey runs a list keybinding directly, without vim key sequence handling
+func (m model) runListKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	vimMode := m.vimMode
+	m.vimMode = false
+	updated, cmd := m.update(key)
+	if um, ok := updated.(model); ok {
+		um.vimMode = vimMode
+		return um, cmd
+	}
+	return updated, cmd
+}
+
+// handleVimKey applies vim key sequences on the project list. It returns the key the
+// regular keybindings should handle, or handled=true when the key was consumed.
+func (m *model) handleVimKey(msg tea.KeyMsg) (key tea.KeyMsg, cmd tea.Cmd, handled bool) {
+	pending := m.vimPending
+	m.vimPending = ""
+
+	switch pending {
+	case "g":
+		// gg jumps to the first project; anything else cancels the sequence
+		if msg.String() == "g" {
+			return m.updateListWith(tea.KeyMsg{Type: tea.KeyHome})
+		}
+		return msg, nil, true
+	case "d":
+		// dd archives the selected project (with the usual DELETE confirmation)
+		if msg.String() == "d" {
+			return keyRune('d'), nil, false
+		}
+		return msg, nil, true
+	}
+
+	switch msg.String() {
+	case "g", "d":
+		m.vimPending = msg.String()
+		return msg, nil, true
+
+	// The list handles home/end and left/right as first/last project and previous/next page
+	case "G":
+		return m.updateListWith(tea.KeyMsg{Type: tea.KeyEnd})
+	case "h":
+		return m.updateListWith(tea.KeyMsg{Type: tea.KeyLeft})
+	case "l":
+		return m.updateListWith(tea.KeyMsg{Type: tea.KeyRight})
+
+	case ":":
+		input := textinput.New()
+		input.Prompt = ":"
+		input.Focus()
+		input.CharLimit = 100
+		input.Width = 40
+		m.vimInput = input
+		m.vimCommandLine = true
+		m.errorMessage = ""
+		m.statusMessage = ""
+		return msg, textinput.Blink, true
+	}
+
+	return msg, nil, false
+}
+
+// updateListWith passes a translated key to the project list and marks the vim key as handled
+func (m *model) updateListWith(key tea.KeyMsg) (tea.KeyMsg, tea.Cmd, bool) {
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(key)
+	return key, cmd, true
+}
+
+// updateVimCommandLine handles key presses while the ":" command line is open
+func (m model) updateVimCommandLine(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.vimCommandLine = false
+		return m, nil
+
+	case "backspace":
+		// Backspace on an empty command line closes it, as in vim
+		if m.vimInput.Value() == "" {
+			m.vimCommandLine = false
+			return m, nil
+		}
+
+	case "enter":
+		m.vimCommandLine = false
+		return m.runVimCommand(strings.TrimSpace(m.vimInput.Value()))
+	}
+
+	var cmd tea.Cmd
+	m.vimInput, cmd = m.vimInput.Update(msg)
+	return m, cmd
+}
+
+// runVimCommand runs a ":" command: a line number, "set novim" or one of vimCommands
+func (m model) runVimCommand(command string) (tea.Model, tea.Cmd) {
+	if command == "" {
+		return m, nil
+	}
+
+	// :N jumps to the Nth project
+	if n, err := strconv.Atoi(command); err == nil {
+		if visible := len(m.list.VisibleItems()); visible > 0 {
+			m.list.Select(max(0, min(n, visible)-1))
+		}
+		return m, nil
+	}
+
+	if command == "set novim" {
+		return m.toggleVimMode()
+	}
+
+	key, ok := vimCommands[command]
+	if !ok {
+		m.errorMessage = fmt.Sprintf("Not a DevBase command: %s", command)
+		return m, nil
+	}
+	return m.runListKey(key)
+}
+
+// viewVimCommandLine renders the ":" command line
+func (m model) viewVimCommandLine() string {
+	return m.vimInput.View() + "\n" +
+		lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render("open, edit, browser, gitclient, run, tmux, container, scan, clone, starred, org, archive, restore, restoreto, retry, folders, sync, load, pin, lock, icon, tags, notes, history, logs, q, N (line), set novim")
+}
@@ -0,0 +1,537 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// Steps of the first-run wizard, in order
+const (
+	wizardStepDatabase = iota
+	wizardStepFolders
+	wizardStepEditor
+	wizardStepTerminal
+	wizardStepGitHub
+	wizardStepScan
+	wizardStepCount
+)
+
+// wizardStepTitles names the wizard steps in the progress indicator
+var wizardStepTitles = [wizardStepCount]string{"Database", "Root folders", "Editor", "Terminal", "GitHub", "First scan"}
+
+// wizardState tracks the first-run wizard while it is running
+type wizardState struct {
+	active     bool // Wizard is running; the GitHub setup screens return to it
+	step       int
+	input      textinput.Model   // Path input of the database and root folder steps
+	folders    []string          // Root folders to scan at the end
+	editors    []engine.Editor   // Editors detected on PATH
+	terminals  []engine.Terminal // Terminals detected on PATH
+	cursor     int               // Highlighted editor or terminal
+	scanning   bool
+	scanned    int      // Root folders scanned so far
+	scanIDs    []uint   // Root folder IDs, parallel to folders
+	scanErrors []string // Root folders whose scan failed
+	found      int
+	added      int
+}
+
+// startWizard switches to the first-run wizard at the given step
+func (m model) startWizard(step int) (model, tea.Cmd) {
+	m.wizard = wizardState{
+		active:    true,
+		editors:   engine.DetectEditors(),
+		terminals: engine.DetectTerminals(),
+	}
+	m.screen = screenWizard
+	return m.enterWizardStep(step)
+}
+
+// enterWizardStep moves the wizard to a step and prepares its input
+func (m model) enterWizardStep(step int) (model, tea.Cmd) {
+	m.wizard.step = step
+	m.wizard.cursor = 0
+	m.errorMessage = ""
+
+	switch step {
+	case wizardStepDatabase:
+		path, err := db.ResolveDBPath()
+		if err != nil {
+			m.errorMessage = err.Error()
+		}
+		m.wizard.input = newWizardInput("Database file (e.g., D:\\\\DevBase\\\\devbase.db)", path)
+		return m, textinput.Blink
+
+	case wizardStepFolders:
+		value := ""
+		if len(m.wizard.folders) == 0 {
+			// Suggest the home directory for the first root folder
+			value, _ = os.UserHomeDir()
+		}
+		m.wizard.input = newWizardInput("Enter path (e.g., D:\\\\Projects)", value)
+		return m, textinput.Blink
+
+	case wizardStepEditor:
+		current, _ := db.GetConfig("editor")
+		m.wizard.cursor = max(0, slices.IndexFunc(m.wizard.editors, func(e engine.Editor) bool { return e.Command == current }))
+
+	case wizardStepTerminal:
+		current, _ := db.GetConfig("terminal")
+		m.wizard.cursor = max(0, slices.IndexFunc(m.wizard.terminals, func(t engine.Terminal) bool { return t.Command == current }))
+	}
+	return m, nil
+}
+
+// newWizardInput creates a focused path input for the wizard
+func newWizardInput(placeholder, value string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.Focus()
+	ti.CharLimit = 256
+	ti.Width = 60
+	ti.SetValue(value)
+	return ti
+}
+
+// updateWizard handles updates for the first-run wizard
+func (m model) updateWizard(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ScanCompleteMsg:
+		return m.wizardScanComplete(msg)
+
+	case reloadMsg:
+		// Keep the list up to date for when the wizard finishes
+		m.list.SetItems(assignHotkeys(msg.items, m.recentHotkeys))
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "shift+tab":
+			// Back to the previous step (not while scanning or once the scan has run)
+			if m.wizard.step > wizardStepDatabase && !m.wizard.scanning && m.wizard.scanned == 0 {
+				return m.enterWizardStep(m.wizard.step - 1)
+			}
+			return m, nil
+		}
+
+		switch m.wizard.step {
+		case wizardStepDatabase:
+			return m.updateWizardDatabase(msg)
+		case wizardStepFolders:
+			return m.updateWizardFolders(msg)
+		case wizardStepEditor, wizardStepTerminal:
+			return m.updateWizardChoice(msg)
+		case wizardStepGitHub:
+			return m.updateWizardGitHub(msg)
+		case wizardStepScan:
+			return m.updateWizardScan(msg)
+		}
+	}
+
+	return m, nil
+}
+
+// updateWizardDatabase handles the database location step
+func (m model) updateWizardDatabase(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "tab":
+		path, err := wizardDBPath(m.wizard.input.Value())
+		if err != nil {
+			m.errorMessage = err.Error()
+			return m, nil
+		}
+
+		current, _ := db.ResolveDBPath()
+		if path != current {
+			if err := db.SwitchDB(path); err != nil {
+				m.errorMessage = fmt.Sprintf("Failed to open database: %v", err)
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("Using database %s", path)
+		}
+		return m.enterWizardStep(wizardStepFolders)
+	}
+
+	var cmd tea.Cmd
+	m.wizard.input, cmd = m.wizard.input.Update(msg)
+	return m, cmd
+}
+
+// wizardDBPath turns the entered database location into an absolute file path.
+// Existing directories get the default database file name appended.
+func wizardDBPath(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("please enter a database location")
+	}
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid database location: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, db.DefaultDBFileName)
+	}
+	return path, nil
+}
+
+// updateWizardFolders handles the root folder step
+func (m model) updateWizardFolders(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(m.wizard.input.Value())
+
+	switch msg.String() {
+	case "enter", "tab":
+		// Enter on an empty input (or tab) moves on once a folder has been added
+		if value == "" || msg.String() == "tab" {
+			if value != "" {
+				if !m.addWizardFolder(value) {
+					return m, nil
+				}
+			}
+			if len(m.wizard.folders) == 0 {
+				m.errorMessage = "Please add at least one root folder"
+				return m, nil
+			}
+			return m.enterWizardStep(wizardStepEditor)
+		}
+
+		m.addWizardFolder(value)
+		return m, nil
+
+	case "backspace":
+		// Backspace on an empty input removes the last folder
+		if value == "" && len(m.wizard.folders) > 0 {
+			m.wizard.folders = m.wizard.folders[:len(m.wizard.folders)-1]
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.wizard.input, cmd = m.wizard.input.Update(msg)
+	return m, cmd
+}
+
+// addWizardFolder validates and adds a root folder, reporting whether it was added
+func (m *model) addWizardFolder(value string) bool {
+	path, err := filepath.Abs(value)
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("Invalid path: %v", err)
+		return false
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		m.errorMessage = fmt.Sprintf("Directory not found: %s", path)
+		return false
+	}
+	if slices.Contains(m.wizard.folders, path) {
+		m.errorMessage = fmt.Sprintf("%s was already added", path)
+		return false
+	}
+
+	m.wizard.folders = append(m.wizard.folders, path)
+	m.wizard.input.SetValue("")
+	m.errorMessage = ""
+	return true
+}
+
+// updateWizardChoice handles the editor and terminal steps
+func (m model) updateWizardChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	count := len(m.wizard.editors)
+	if m.wizard.step == wizardStepTerminal {
+		count = len(m.wizard.terminals)
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.wizard.cursor > 0 {
+			m.wizard.cursor--
+		}
+
+	case "down", "j":
+		if m.wizard.cursor < count-1 {
+			m.wizard.cursor++
+		}
+
+	case "enter", "tab":
+		if count > 0 {
+			key, value := "editor", ""
+			if m.wizard.step == wizardStepTerminal {
+				key, value = "terminal", m.wizard.terminals[m.wizard.cursor].Command
+			} else {
+				value = m.wizard.editors[m.wizard.cursor].Command
+			}
+			if err := db.SetConfig(key, value); err != nil {
+				m.errorMessage = fmt.Sprintf("Failed to save %s: %v", key, err)
+				return m, nil
+			}
+		}
+		return m.enterWizardStep(m.wizard.step + 1)
+	}
+
+	return m, nil
+}
+
+// updateWizardGitHub handles the optional GitHub step
+func (m model) updateWizardGitHub(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		// Reuse the GitHub setup screens, which return to the wizard when done
+		return m.openGitHubSetup()
+
+	case "tab", "s":
+		return m.enterWizardStep(wizardStepScan)
+	}
+	return m, nil
+}
+
+// leaveGitHubSetup returns from the GitHub setup screens to the wizard or the project list
+func (m model) leaveGitHubSetup(authenticated bool) (tea.Model, tea.Cmd) {
+	if !m.wizard.active {
+		m.screen = screenList
+		return m, reloadProjectsCmd(m.statusFilter)
+	}
+	m.screen = screenWizard
+	if authenticated {
+		return m.enterWizardStep(wizardStepScan)
+	}
+	return m.enterWizardStep(wizardStepGitHub)
+}
+
+// updateWizardScan handles the first scan step
+func (m model) updateWizardScan(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() != "enter" || m.wizard.scanning {
+		return m, nil
+	}
+
+	// Scan finished: open the project list
+	if m.wizard.scanned > 0 {
+		m.wizard = wizardState{}
+		m.screen = screenList
+		m.errorMessage = ""
+		return m, reloadProjectsCmd(m.statusFilter)
+	}
+
+	// Create a root folder for each path, the first one becomes active
+	m.wizard.scanIDs = nil
+	for i, path := range m.wizard.folders {
+		rootFolder, err := db.GetRootFolderByPath(path)
+		if err != nil {
+			rootFolder = &models.RootFolder{
+				Name:     filepath.Base(path),
+				Path:     path,
+				IsActive: i == 0,
+			}
+			if err := db.AddRootFolder(rootFolder); err != nil {
+				m.errorMessage = fmt.Sprintf("Failed to create root folder: %v", err)
+				return m, nil
+			}
+		}
+		m.wizard.scanIDs = append(m.wizard.scanIDs, rootFolder.ID)
+	}
+
+	if err := db.SetActiveRootFolder(m.wizard.scanIDs[0]); err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to activate root folder: %v", err)
+		return m, nil
+	}
+	m.activeRootFolderID = m.wizard.scanIDs[0]
+	m.rootScanPath = m.wizard.folders[0]
+
+	// Save root path to config for backward compatibility
+	_ = db.SetConfig("root_scan_path", m.rootScanPath)
+
+	m.wizard.scanning = true
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, m.scanRootFolderCmd(m.wizard.scanIDs[0], m.wizard.folders[0])
+}
+
+// wizardScanComplete records a finished root folder scan and starts the next one
+func (m model) wizardScanComplete(msg ScanCompleteMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.wizard.scanErrors = append(m.wizard.scanErrors, fmt.Sprintf("%s: %v", m.wizard.folders[m.wizard.scanned], msg.err))
+	}
+	m.wizard.found += msg.projectsFound
+	m.wizard.added += msg.projectsAdded
+	m.wizard.scanned++
+
+	if m.wizard.scanned < len(m.wizard.folders) {
+		next := m.wizard.scanned
+		return m, m.scanRootFolderCmd(m.wizard.scanIDs[next], m.wizard.folders[next])
+	}
+
+	m.wizard.scanning = false
+	m.statusMessage = fmt.Sprintf("Found %d projects, added %d to database", m.wizard.found, m.wizard.added)
+	return m, reloadProjectsCmd(m.statusFilter)
+}
+
+// viewWizard renders the first-run wizard
+func (m model) viewWizard() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Welcome to DevBase")
+
+	s := "\n" + titleBox + "\n\n" + m.viewWizardProgress() + "\n\n"
+
+	textStyle := lipgloss.NewStyle().Foreground(colorText)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+	help := ""
+
+	switch m.wizard.step {
+	case wizardStepDatabase:
+		s += textStyle.Render("Where should DevBase keep its database?") + "\n"
+		s += dimStyle.Italic(true).Render("(a file path, or a directory to create devbase.db in)") + "\n\n"
+		s += m.wizard.input.View() + "\n"
+		help = "enter/tab=next"
+
+	case wizardStepFolders:
+		s += textStyle.Render("Add the root folders that contain your projects:") + "\n\n"
+		for _, folder := range m.wizard.folders {
+			s += lipgloss.NewStyle().Foreground(colorSuccess).Render("  ✓ "+folder) + "\n"
+		}
+		if len(m.wizard.folders) > 0 {
+			s += "\n"
+		}
+		s += m.wizard.input.View() + "\n"
+		help = "enter=add folder  enter on empty/tab=next  backspace on empty=remove last  shift+tab=back"
+
+	case wizardStepEditor:
+		s += textStyle.Render("Which editor should open your projects?") + "\n\n"
+		names := make([]string, len(m.wizard.editors))
+		for i, editor := range m.wizard.editors {
+			names[i] = fmt.Sprintf("%s (%s)", editor.Name, editor.Command)
+		}
+		s += m.viewWizardChoices(names, fmt.Sprintf("No known editors found on PATH, '%s' will be used", engine.DefaultEditorCommand))
+		help = "↑↓=choose  enter/tab=next  shift+tab=back"
+
+	case wizardStepTerminal:
+		s += textStyle.Render("Which terminal should run dev servers and commands?") + "\n\n"
+		names := make([]string, len(m.wizard.terminals))
+		for i, terminal := range m.wizard.terminals {
+			names[i] = fmt.Sprintf("%s (%s)", terminal.Name, terminal.Command)
+		}
+		s += m.viewWizardChoices(names, "No known terminals found on PATH, the defaults will be used")
+		help = "↑↓=choose  enter/tab=next  shift+tab=back"
+
+	case wizardStepGitHub:
+		s += textStyle.Render("Connect GitHub to back up projects to a private gist and clone your repositories (optional).") + "\n\n"
+		if token, _ := db.GetConfig("github_token"); token != "" {
+			s += lipgloss.NewStyle().Foreground(colorSuccess).Render("✓ GitHub is connected") + "\n"
+		} else {
+			s += dimStyle.Render("Not connected") + "\n"
+		}
+		help = "enter=connect GitHub  tab/s=skip  shift+tab=back"
+
+	case wizardStepScan:
+		s += m.viewWizardScan()
+		switch {
+		case m.wizard.scanning:
+			help = "Scanning..."
+		case m.wizard.scanned > 0:
+			help = "enter=open DevBase"
+		default:
+			help = "enter=start scan  shift+tab=back"
+		}
+	}
+
+	s += "\n" + dimStyle.Render(help+"  •  Ctrl+C to quit")
+
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+
+	return docStyle.Render(s)
+}
+
+// viewWizardProgress renders the step indicator, e.g. "Step 2 of 6" and the list of steps
+func (m model) viewWizardProgress() string {
+	steps := make([]string, wizardStepCount)
+	for i, title := range wizardStepTitles {
+		switch {
+		case i < m.wizard.step:
+			steps[i] = lipgloss.NewStyle().Foreground(colorSuccessDim).Render("✓ " + title)
+		case i == m.wizard.step:
+			steps[i] = lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("● " + title)
+		default:
+			steps[i] = lipgloss.NewStyle().Foreground(colorDim).Render("○ " + title)
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(colorDim).
+		Render(fmt.Sprintf("Step %d of %d", m.wizard.step+1, wizardStepCount)) + "\n" +
+		strings.Join(steps, "  ")
+}
+
+// viewWizardChoices renders the editor or terminal choices with the cursor
+func (m model) viewWizardChoices(names []string, empty string) string {
+	if len(names) == 0 {
+		return lipgloss.NewStyle().Foreground(colorWarning).Render(empty) + "\n"
+	}
+
+	s := ""
+	for i, name := range names {
+		if i == m.wizard.cursor {
+			s += lipgloss.NewStyle().
+				Background(colorSelection).
+				Foreground(colorSelectionText).
+				Bold(true).
+				Render("► "+name) + "\n"
+		} else {
+			s += lipgloss.NewStyle().
+				Foreground(colorText).
+				Render("  "+name) + "\n"
+		}
+	}
+	return s
+}
+
+// viewWizardScan renders the root folders with their scan progress
+func (m model) viewWizardScan() string {
+	textStyle := lipgloss.NewStyle().Foreground(colorText)
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	s := textStyle.Render(fmt.Sprintf("Ready to scan %d root folder(s):", len(m.wizard.folders))) + "\n\n"
+	for i, folder := range m.wizard.folders {
+		switch {
+		case i < m.wizard.scanned:
+			s += lipgloss.NewStyle().Foreground(colorSuccess).Render("  ✓ "+folder) + "\n"
+		case i == m.wizard.scanned && m.wizard.scanning:
+			s += lipgloss.NewStyle().Foreground(colorWarning).Render("  ⟳ "+folder) + "\n"
+		default:
+			s += dimStyle.Render("  ○ "+folder) + "\n"
+		}
+	}
+
+	if m.wizard.scanning || m.wizard.scanned > 0 {
+		const barWidth = 30
+		filled := barWidth * m.wizard.scanned / len(m.wizard.folders)
+		s += "\n" + lipgloss.NewStyle().Foreground(colorAccent).Render(strings.Repeat("█", filled)) +
+			dimStyle.Render(strings.Repeat("░", barWidth-filled)) +
+			textStyle.Render(fmt.Sprintf(" %d/%d", m.wizard.scanned, len(m.wizard.folders))) + "\n"
+	}
+
+	if !m.wizard.scanning && m.wizard.scanned > 0 {
+		s += "\n" + lipgloss.NewStyle().Foreground(colorSuccess).Bold(true).
+			Render(fmt.Sprintf("Found %d projects, added %d to database", m.wizard.found, m.wizard.added)) + "\n"
+	}
+	for _, scanErr := range m.wizard.scanErrors {
+		s += lipgloss.NewStyle().Foreground(colorWarning).Render("⚠ Scan failed for "+scanErr) + "\n"
+	}
+	return s
+}
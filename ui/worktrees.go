@@ -0,0 +1,405 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devbase/db"
+	"devbase/engine"
+	"devbase/models"
+)
+
+// worktreeRow is a worktree of the project on the worktrees screen, with its entry when
+// it is registered
+type worktreeRow struct {
+	worktree engine.Worktree
+	project  *models.Project
+}
+
+// WorktreesMsg is sent when the worktrees of a project have been listed
+type WorktreesMsg struct {
+	rows []worktreeRow
+	err  error
+}
+
+// WorktreeChangedMsg is sent when a worktree was added, linked or removed
+type WorktreeChangedMsg struct {
+	status string
+	err    error
+}
+
+// openWorktrees shows the git worktrees of a project and lists them in the background
+func (m model) openWorktrees(item projectItem) (tea.Model, tea.Cmd) {
+	if item.remoteHost != "" {
+		m.errorMessage = remoteUnsupported(item, "given worktrees")
+		return m, nil
+	}
+	if item.project.Status != "active" || item.missing {
+		m.errorMessage = fmt.Sprintf("%s isn't on disk; restore it first", item.project.Name)
+		return m, nil
+	}
+
+	// A worktree's screen shows the worktrees of its parent
+	parent := item.project
+	if parent.ParentID != 0 {
+		if p, err := db.GetProjectByID(parent.ParentID); err == nil {
+			parent = *p
+		}
+	}
+
+	m.worktreeParent = parent
+	m.worktreeRows = nil
+	m.worktreeCursor = 0
+	m.worktreeAdding = false
+	m.worktreeConfirm = false
+	m.worktreeChanged = false
+	m.worktreeLoading = true
+	m.screen = screenWorktrees
+	m.errorMessage = ""
+	m.statusMessage = ""
+	return m, worktreesCmd(parent)
+}
+
+// worktreesCmd creates a command that lists the worktrees of a project and matches them
+// with the registered projects
+func worktreesCmd(parent models.Project) tea.Cmd {
+	return func() tea.Msg {
+		worktrees, err := engine.ListWorktrees(parent.Path)
+		if err != nil {
+			return WorktreesMsg{err: err}
+		}
+		rows := make([]worktreeRow, len(worktrees))
+		for i, wt := range worktrees {
+			rows[i].worktree = wt
+			if wt.Main {
+				rows[i].project = &parent
+			} else if project, err := db.GetProjectByPath(wt.Path); err == nil {
+				rows[i].project = project
+			}
+		}
+		return WorktreesMsg{rows: rows}
+	}
+}
+
+// worktreeCmd creates a command that changes the worktrees of a project
+func worktreeCmd(change func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		status, err := change()
+		return WorktreeChangedMsg{status: status, err: err}
+	}
+}
+
+// updateWorktrees handles updates for the worktrees screen
+func (m model) updateWorktrees(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case WorktreesMsg:
+		m.worktreeLoading = false
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			return m, nil
+		}
+		m.worktreeRows = msg.rows
+		m.worktreeCursor = min(m.worktreeCursor, max(0, len(msg.rows)-1))
+		return m, nil
+
+	case WorktreeChangedMsg:
+		if msg.err != nil {
+			m.errorMessage = msg.err.Error()
+			m.statusMessage = ""
+		} else {
+			m.worktreeChanged = true
+			m.errorMessage = ""
+			m.statusMessage = msg.status
+		}
+		m.worktreeLoading = true
+		return m, worktreesCmd(m.worktreeParent)
+
+	case tea.KeyMsg:
+		if m.worktreeAdding {
+			return m.updateWorktreeInput(msg)
+		}
+		if m.worktreeConfirm {
+			m.worktreeConfirm = false
+			switch msg.String() {
+			case "y":
+				parent, project := m.worktreeParent, *m.worktreeRows[m.worktreeCursor].project
+				m.statusMessage = fmt.Sprintf("Removing %s...", project.Name)
+				return m, worktreeCmd(func() (string, error) {
+					if err := engine.RemoveWorktree(parent, project); err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("Removed worktree %s", project.Path), nil
+				})
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			m.statusMessage = ""
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "K":
+			return m.closeWorktrees(), m.worktreesReloadCmd()
+
+		case "up", "k":
+			if m.worktreeCursor > 0 {
+				m.worktreeCursor--
+			}
+
+		case "down", "j":
+			if m.worktreeCursor < len(m.worktreeRows)-1 {
+				m.worktreeCursor++
+			}
+
+		case "r":
+			m.worktreeLoading = true
+			return m, worktreesCmd(m.worktreeParent)
+
+		case "a":
+			input := textinput.New()
+			input.Placeholder = "feature/my-branch"
+			input.Focus()
+			input.CharLimit = 200
+			input.Width = 40
+			m.worktreeInput = input
+			m.worktreeAdding = true
+			m.errorMessage = ""
+			m.statusMessage = ""
+			return m, textinput.Blink
+
+		case "enter":
+			if len(m.worktreeRows) == 0 {
+				return m, nil
+			}
+			row := m.worktreeRows[m.worktreeCursor]
+			if row.project == nil {
+				// Register the worktree, linked to the project
+				if row.worktree.Prunable {
+					m.errorMessage = "The worktree's directory is gone"
+					return m, nil
+				}
+				parent, path := m.worktreeParent, row.worktree.Path
+				return m, worktreeCmd(func() (string, error) {
+					project, err := engine.LinkWorktree(parent, path)
+					if err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("Registered %s as a worktree of %s", project.Name, parent.Name), nil
+				})
+			}
+			// Open it like from the list
+			project := *row.project
+			reload := m.worktreesReloadCmd()
+			m = m.closeWorktrees()
+			return m, tea.Batch(reload, openProjectCmd(project, projectEditor(project)), recordOpenCmd(project))
+
+		case "d":
+			if len(m.worktreeRows) == 0 {
+				return m, nil
+			}
+			row := m.worktreeRows[m.worktreeCursor]
+			switch {
+			case row.worktree.Main:
+				m.errorMessage = "The main worktree can't be removed; archive the project instead"
+			case row.project == nil || row.project.ParentID != m.worktreeParent.ID:
+				m.errorMessage = "Only registered worktrees are removed here; press enter to register it first"
+			default:
+				m.errorMessage = ""
+				m.worktreeConfirm = true
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// updateWorktreeInput handles key presses while the branch for a new worktree is entered
+func (m model) updateWorktreeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.worktreeAdding = false
+		return m, nil
+
+	case "enter":
+		branch := m.worktreeInput.Value()
+		if err := engine.ValidateRef(branch); err != nil || branch == "" {
+			m.errorMessage = "Please enter a valid branch name"
+			return m, nil
+		}
+		m.worktreeAdding = false
+		m.errorMessage = ""
+		m.statusMessage = fmt.Sprintf("Checking out %s in %s...", branch, engine.WorktreePath(m.worktreeParent, branch))
+		parent := m.worktreeParent
+		return m, worktreeCmd(func() (string, error) {
+			project, err := engine.AddWorktree(parent, branch)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Added worktree %s for %s", project.Name, branch), nil
+		})
+	}
+
+	var cmd tea.Cmd
+	m.worktreeInput, cmd = m.worktreeInput.Update(msg)
+	return m, cmd
+}
+
+// closeWorktrees returns to the project list
+func (m model) closeWorktrees() model {
+	m.screen = screenList
+	m.worktreeRows = nil
+	m.worktreeAdding = false
+	m.worktreeConfirm = false
+	return m
+}
+
+// worktreesReloadCmd reloads the project list when worktrees were added or removed
+func (m model) worktreesReloadCmd() tea.Cmd {
+	if !m.worktreeChanged {
+		return nil
+	}
+	return reloadProjectsCmd(m.statusFilter)
+}
+
+// viewWorktrees renders the worktrees of the project
+func (m model) viewWorktrees() string {
+	titleBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(0, 2).
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Worktrees of " + m.worktreeParent.Name)
+
+	s := "\n" + titleBox + "\n\n"
+	dimStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	if m.worktreeLoading && len(m.worktreeRows) == 0 {
+		s += dimStyle.Render("Listing worktrees...") + "\n"
+	}
+	for i, row := range m.worktreeRows {
+		cursor := "  "
+		if i == m.worktreeCursor {
+			cursor = "► "
+		}
+		branch := row.worktree.Branch
+		if branch == "" {
+			branch = "(detached " + shortHash(row.worktree.Head) + ")"
+		}
+
+		var notes []string
+		switch {
+		case row.worktree.Main:
+			notes = append(notes, "main")
+		case row.project == nil:
+			notes = append(notes, "not registered")
+		case row.project.ParentID != m.worktreeParent.ID:
+			notes = append(notes, "registered as "+row.project.Name)
+		}
+		if row.worktree.Locked {
+			notes = append(notes, "locked")
+		}
+		if row.worktree.Prunable {
+			notes = append(notes, "directory gone")
+		}
+
+		style := lipgloss.NewStyle().Foreground(colorText)
+		if i == m.worktreeCursor {
+			style = style.Background(colorSelection).Foreground(colorSelectionText).Bold(true)
+		}
+		s += style.Render(fmt.Sprintf("%s%-28s %s", cursor, branch, row.worktree.Path))
+		for _, note := range notes {
+			s += dimStyle.Render("  [" + note + "]")
+		}
+		s += "\n"
+	}
+
+	switch {
+	case m.worktreeAdding:
+		s += "\n" + lipgloss.NewStyle().Foreground(colorText).Render("Branch to check out (created from HEAD when it doesn't exist):") + "\n" +
+			m.worktreeInput.View() + "\n" +
+			dimStyle.Render("enter=add  esc=cancel")
+	case m.worktreeConfirm:
+		s += "\n" + lipgloss.NewStyle().Foreground(colorWarning).Bold(true).
+			Render(fmt.Sprintf("Delete the worktree at %s? (y/n)", m.worktreeRows[m.worktreeCursor].worktree.Path))
+	default:
+		s += dimStyle.Render("\n↑↓=move  enter=open (registers unregistered ones)  a=add  d=remove  r=refresh  esc=back")
+	}
+
+	if m.statusMessage != "" {
+		s += lipgloss.NewStyle().Foreground(colorSuccessDim).Render("\n✓ " + m.statusMessage)
+	}
+	if m.errorMessage != "" {
+		s += errorStyle.Render(fmt.Sprintf("\n⚠ %s", m.errorMessage))
+	}
+	return docStyle.Render(s)
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	return hash[:min(7, len(hash))]
+}
+
+// groupWorktrees moves the projects registered as worktrees right after their parent, in
+// the order they came in. Worktrees whose parent isn't listed stay where they are.
+func groupWorktrees(items []list.Item) []list.Item {
+	listed := make(map[uint]bool, len(items))
+	for _, item := range items {
+		if pi, ok := item.(projectItem); ok {
+			listed[pi.project.ID] = true
+		}
+	}
+	isChild := func(item list.Item) bool {
+		pi, ok := item.(projectItem)
+		return ok && pi.project.ParentID != 0 && pi.project.ParentID != pi.project.ID && listed[pi.project.ParentID]
+	}
+	children := make(map[uint][]list.Item)
+	for _, item := range items {
+		if isChild(item) {
+			parentID := item.(projectItem).project.ParentID
+			children[parentID] = append(children[parentID], item)
+		}
+	}
+	if len(children) == 0 {
+		return items
+	}
+
+	grouped := make([]list.Item, 0, len(items))
+	placed := make(map[uint]bool, len(items))
+	var place func(item list.Item)
+	place = func(item list.Item) {
+		grouped = append(grouped, item)
+		pi, ok := item.(projectItem)
+		if !ok {
+			return
+		}
+		placed[pi.project.ID] = true
+		for _, child := range children[pi.project.ID] {
+			if !placed[child.(projectItem).project.ID] {
+				place(child)
+			}
+		}
+	}
+	for _, item := range items {
+		if !isChild(item) {
+			place(item)
+		}
+	}
+	// Links that go around in a circle have no top-level parent
+	for _, item := range items {
+		if pi, ok := item.(projectItem); ok && !placed[pi.project.ID] {
+			place(item)
+		}
+	}
+	return grouped
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"os"
+	"runtime"
+
+	"devbase/db"
+	"devbase/engine"
+)
+
+// hostPathFunc returns a function that translates project paths for this machine (see
+// engine.HostPath). On Windows, Linux paths registered from inside WSL belong to the
+// distribution in the "wsl_distro" config key, which is read once per call.
+func hostPathFunc() func(string) string {
+	var distro string
+	if runtime.GOOS == "windows" {
+		distro, _ = db.GetConfig("wsl_distro")
+	}
+	return func(path string) string {
+		return engine.HostPath(path, distro)
+	}
+}
+
+// hostPath translates a single project path for this machine
+func hostPath(path string) string {
+	return hostPathFunc()(path)
+}
+
+// wslProject returns the WSL location of a project when DevBase runs on Windows
+func wslProject(path string) (engine.WSLPath, bool) {
+	if runtime.GOOS != "windows" {
+		return engine.WSLPath{}, false
+	}
+	return engine.ParseWSLPath(hostPath(path))
+}
+
+// wslTerminalDir is the Windows directory terminals start in before entering WSL, since
+// cmd.exe can't use a UNC path as its working directory
+func wslTerminalDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return home
+	}
+	return `C:\`
+}